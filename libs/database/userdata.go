@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// UserTable names one table holding rows owned by a user and the column
+// carrying the user's ID. Services list their user-owned tables once and hand
+// the slice to ExportUserRows / EraseUserRows, which back the
+// /internal/account/export and /internal/account/erase endpoints the auth
+// service fans out to on a data export or account deletion.
+type UserTable struct {
+	Table  string
+	Column string
+}
+
+// ExportUserRows reads every row of every table owned by userID, keyed by
+// table name. Rows are returned as column → value maps so the archive keeps
+// the full stored shape without a per-table DTO. Two entries for the same
+// table (e.g. user_follows by follower_id and by followee_id) are merged.
+func ExportUserRows(ctx context.Context, db *gorm.DB, userID string, tables []UserTable) (map[string][]map[string]any, error) {
+	out := make(map[string][]map[string]any, len(tables))
+	for _, t := range tables {
+		var rows []map[string]any
+		err := db.WithContext(ctx).
+			Table(t.Table).
+			Where(fmt.Sprintf("%s = ?", t.Column), userID).
+			Find(&rows).Error
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", t.Table, err)
+		}
+		out[t.Table] = append(out[t.Table], rows...)
+		if out[t.Table] == nil {
+			out[t.Table] = []map[string]any{}
+		}
+	}
+	return out, nil
+}
+
+// EraseUserRows deletes every row owned by userID from the given tables in
+// one transaction, in slice order (list child tables before parents), and
+// returns the per-table deleted counts. Idempotent: a repeated erase deletes
+// nothing and succeeds.
+func EraseUserRows(ctx context.Context, db *gorm.DB, userID string, tables []UserTable) (map[string]int64, error) {
+	deleted := make(map[string]int64, len(tables))
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, t := range tables {
+			res := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s = ?", t.Table, t.Column), userID)
+			if res.Error != nil {
+				return fmt.Errorf("erase %s: %w", t.Table, res.Error)
+			}
+			deleted[t.Table] += res.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
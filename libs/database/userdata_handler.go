@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+)
+
+// UserDataStore is the export + erasure port of the account fan-out. A
+// service implements it over its UserTable list (usually a thin
+// repo.AccountRepository around ExportUserRows / EraseUserRows).
+type UserDataStore interface {
+	Export(ctx context.Context, userID string) (map[string][]map[string]any, error)
+	Erase(ctx context.Context, userID string) (map[string]int64, error)
+}
+
+// UserDataHandler serves the auth service's account fan-out for one service:
+//
+//	POST /internal/account/export  {"user_id"} → the service's rows of the user
+//	POST /internal/account/erase   {"user_id"} → hard-delete them (idempotent)
+//
+// Docker-network only — the gateway never proxies /internal/*.
+type UserDataHandler struct {
+	store UserDataStore
+	log   *logger.Logger
+}
+
+func NewUserDataHandler(store UserDataStore, log *logger.Logger) *UserDataHandler {
+	return &UserDataHandler{store: store, log: log}
+}
+
+type userDataRequest struct {
+	UserID string `json:"user_id"`
+}
+
+// Export handles POST /internal/account/export.
+func (h *UserDataHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID, ok := bindUserDataRequest(w, r)
+	if !ok {
+		return
+	}
+	tables, err := h.store.Export(r.Context(), userID)
+	if err != nil {
+		h.log.Errorw("account export failed", "user_id", userID, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, map[string]any{"tables": tables})
+}
+
+// Erase handles POST /internal/account/erase.
+func (h *UserDataHandler) Erase(w http.ResponseWriter, r *http.Request) {
+	userID, ok := bindUserDataRequest(w, r)
+	if !ok {
+		return
+	}
+	deleted, err := h.store.Erase(r.Context(), userID)
+	if err != nil {
+		h.log.Errorw("account erase failed", "user_id", userID, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	h.log.Infow("account erased", "user_id", userID, "deleted", deleted)
+	httputil.OK(w, map[string]any{"status": "erased", "deleted": deleted})
+}
+
+// bindUserDataRequest decodes the {"user_id"} body, answering 400 itself
+// when it is malformed or the id is missing.
+func bindUserDataRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var req userDataRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return "", false
+	}
+	if req.UserID == "" {
+		httputil.BadRequest(w, "user_id is required")
+		return "", false
+	}
+	return req.UserID, true
+}
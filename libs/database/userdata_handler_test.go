@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+)
+
+type fakeUserDataStore struct {
+	erased []string
+}
+
+func (f *fakeUserDataStore) Export(_ context.Context, userID string) (map[string][]map[string]any, error) {
+	return map[string][]map[string]any{"things": {{"user_id": userID}}}, nil
+}
+
+func (f *fakeUserDataStore) Erase(_ context.Context, userID string) (map[string]int64, error) {
+	f.erased = append(f.erased, userID)
+	return map[string]int64{"things": 1}, nil
+}
+
+func TestUserDataHandler(t *testing.T) {
+	store := &fakeUserDataStore{}
+	h := NewUserDataHandler(store, logger.Default())
+
+	rec := httptest.NewRecorder()
+	h.Export(rec, httptest.NewRequest(http.MethodPost, "/internal/account/export", strings.NewReader(`{"user_id":"u1"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("export status = %d, want 200", rec.Code)
+	}
+	var body struct {
+		Data struct {
+			Tables map[string][]map[string]any `json:"tables"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode export: %v", err)
+	}
+	if got := body.Data.Tables["things"]; len(got) != 1 || got[0]["user_id"] != "u1" {
+		t.Errorf("export tables = %v", body.Data.Tables)
+	}
+
+	rec = httptest.NewRecorder()
+	h.Erase(rec, httptest.NewRequest(http.MethodPost, "/internal/account/erase", strings.NewReader(`{"user_id":"u1"}`)))
+	if rec.Code != http.StatusOK || len(store.erased) != 1 || store.erased[0] != "u1" {
+		t.Errorf("erase status = %d, erased = %v", rec.Code, store.erased)
+	}
+
+	rec = httptest.NewRecorder()
+	h.Erase(rec, httptest.NewRequest(http.MethodPost, "/internal/account/erase", strings.NewReader(`{}`)))
+	if rec.Code != http.StatusBadRequest || len(store.erased) != 1 {
+		t.Errorf("missing user_id: status = %d, erased = %v", rec.Code, store.erased)
+	}
+}
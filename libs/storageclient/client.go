@@ -276,6 +276,16 @@ func contentTypeFor(name string) string {
 		return "image/jpeg"
 	case ".vtt":
 		return "text/vtt"
+	case ".epub":
+		return "application/epub+zip"
+	case ".pdf":
+		return "application/pdf"
+	case ".opus":
+		return "audio/ogg"
+	case ".mp3":
+		return "audio/mpeg"
+	case ".wav":
+		return "audio/wav"
 	default:
 		return "application/octet-stream"
 	}
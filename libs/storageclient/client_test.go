@@ -450,6 +450,11 @@ func TestContentTypeFor(t *testing.T) {
 		"playlist.m3u8": "application/vnd.apple.mpegurl",
 		"thumb.jpg":     "image/jpeg",
 		"subs.vtt":      "text/vtt",
+		"book.epub":     "application/epub+zip",
+		"book.pdf":      "application/pdf",
+		"talk.opus":     "audio/ogg",
+		"talk.mp3":      "audio/mpeg",
+		"chunk.wav":     "audio/wav",
 		"whatever.bin":  "application/octet-stream",
 		"noextension":   "application/octet-stream",
 		"UPPER.TS":      "video/mp2t",
@@ -61,6 +61,10 @@ func main() {
 		&domain.DailyPuzzle{},
 		&domain.UserGameResult{},
 		&domain.UserStats{},
+		&domain.ModePuzzle{},
+		&domain.UserModeStats{},
+		&domain.Challenge{},
+		&domain.ChallengeResult{},
 	); err != nil {
 		log.Fatalw("failed to migrate database", "error", err)
 	}
@@ -79,13 +83,28 @@ func main() {
 	statsSvc := service.NewStatsService(gameRepo)
 	lbSvc := service.NewLeaderboardService(service.NewRedisZSet(redisCache.Client(), 48*time.Hour))
 	dailySvc := service.NewDailyService(gameRepo, poolStore, nil /*realClock*/, gameRepo, statsSvc)
+	characterPool := service.NewModePool(redisCache, "anidle:pool:characters", poolClient.FetchCharacters,
+		func(c domain.PoolCharacter) string { return c.ID },
+		func(c domain.PoolCharacter) []string { return []string{c.NameRU, c.Name, c.NameJP} },
+		cfg.PoolTTL, log)
+	themePool := service.NewModePool(redisCache, "anidle:pool:themes", service.NewThemePoolClient(cfg.ThemesURL, 60*time.Second).Fetch,
+		func(t domain.PoolTheme) string { return t.ID },
+		func(t domain.PoolTheme) []string { return []string{t.AnimeNameRU, t.AnimeName} },
+		cfg.PoolTTL, log)
+	characterSvc := service.NewCharacterService(gameRepo, characterPool, nil /*realClock*/, gameRepo, statsSvc, log)
+	themeSvc := service.NewThemeService(gameRepo, themePool, nil /*realClock*/, gameRepo, statsSvc, log)
+	challengeSvc := service.NewChallengeService(repo.NewChallengeRepo(db.DB), poolStore, log)
 	endlessSvc := service.NewEndlessService(poolStore, service.NewRedisTokenStore(redisCache.Client(), time.Hour), nil)
 
 	healthHandler := handler.NewHealthHandler()
 	anidleHandler := handler.NewAnidleHandler(dailySvc, endlessSvc, statsSvc, lbSvc, poolStore)
+	anidleHandler.SetFriends(repo.NewFollowRepo(db.DB))
+	modesHandler := handler.NewModesHandler(characterSvc, themeSvc, lbSvc, cfg.ThemesURL, log)
+	challengeHandler := handler.NewChallengeHandler(challengeSvc)
+	accountHandler := handler.NewAccountInternalHandler(repo.NewAccountRepo(db.DB), lbSvc, log)
 
 	mc := metrics.NewCollector("anidle")
-	router := transport.NewRouter(healthHandler, anidleHandler, cfg.JWT, log, mc)
+	router := transport.NewRouter(healthHandler, anidleHandler, modesHandler, challengeHandler, accountHandler, cfg.JWT, log, mc)
 
 	srv := &http.Server{
 		Addr:         cfg.Server.Address(),
@@ -104,7 +123,33 @@ func main() {
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	// shutdown stops the challenge purge loop.
+	shutdown := make(chan struct{})
+
+	// Periodic purge of challenges expired more than 30 days ago.
+	go func() {
+		t := time.NewTicker(time.Hour)
+		defer t.Stop()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-t.C:
+				n, err := challengeSvc.Purge(context.Background())
+				if err != nil {
+					log.Warnw("challenge purge failed", "error", err)
+					continue
+				}
+				if n > 0 {
+					log.Infow("challenge purge", "deleted", n)
+				}
+			}
+		}
+	}()
+
 	<-quit
+	close(shutdown)
 
 	log.Info("shutting down anidle service...")
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -17,6 +17,7 @@ require (
 	github.com/ILITA-hub/animeenigma/libs/authz v0.0.0-20260605053210-7d61fcc7b6d6
 	github.com/ILITA-hub/animeenigma/libs/cache v0.0.0-00010101000000-000000000000
 	github.com/ILITA-hub/animeenigma/libs/database v0.0.0-00010101000000-000000000000
+	github.com/ILITA-hub/animeenigma/libs/errors v0.0.0
 	github.com/ILITA-hub/animeenigma/libs/httputil v0.0.0-00010101000000-000000000000
 	github.com/ILITA-hub/animeenigma/libs/logger v0.0.0
 	github.com/ILITA-hub/animeenigma/libs/metrics v0.0.0
@@ -26,7 +27,6 @@ require (
 )
 
 require (
-	github.com/ILITA-hub/animeenigma/libs/errors v0.0.0 // indirect
 	github.com/ajg/form v1.5.1 // indirect
 	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/cenkalti/backoff/v5 v5.0.3 // indirect
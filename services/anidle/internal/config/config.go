@@ -17,6 +17,7 @@ type Config struct {
 	Redis      cache.Config
 	JWT        authz.JWTConfig
 	CatalogURL string
+	ThemesURL  string
 	PoolTTL    time.Duration
 }
 
@@ -57,6 +58,7 @@ func Load() (*Config, error) {
 			RefreshTokenTTL: getEnvDuration("JWT_REFRESH_TTL", 7*24*time.Hour),
 		},
 		CatalogURL: getEnv("CATALOG_URL", "http://catalog:8081"),
+		ThemesURL:  getEnv("THEMES_SERVICE_URL", "http://themes:8086"),
 		PoolTTL:    getEnvDuration("ANIDLE_POOL_TTL", 12*time.Hour),
 	}, nil
 }
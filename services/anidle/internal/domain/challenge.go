@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// Challenge is a user-made puzzle: the creator picks the secret anime, may cap
+// the attempts and attach hints, and shares the link. It is playable until
+// ExpiresAt. The secret is frozen in AnswerSnapshot like a daily puzzle.
+type Challenge struct {
+	ID             string          `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CreatorID      string          `gorm:"size:64;index" json:"creator_id"`
+	CreatorName    string          `gorm:"size:64" json:"creator_name"`
+	Title          string          `gorm:"size:120" json:"title"`
+	AnimeID        string          `gorm:"size:64" json:"-"`
+	AnswerSnapshot Snapshot        `gorm:"serializer:json" json:"-"`
+	MaxAttempts    int             `json:"max_attempts"` // 0 = unlimited
+	Hints          []ChallengeHint `gorm:"serializer:json" json:"-"`
+	ExpiresAt      time.Time       `gorm:"index" json:"expires_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+func (Challenge) TableName() string { return "anidle_challenge" }
+
+// ChallengeHint is a creator-written hint unlocked once the player has made
+// AfterAttempts guesses (0 = shown from the start).
+type ChallengeHint struct {
+	AfterAttempts int    `json:"after_attempts"`
+	Text          string `json:"text"`
+}
+
+// Expired reports whether the challenge can no longer be played at t.
+func (c *Challenge) Expired(t time.Time) bool { return !t.Before(c.ExpiresAt) }
+
+// ChallengeResult is one user's game of a challenge. Solved rows make up the
+// per-challenge leaderboard.
+type ChallengeResult struct {
+	ChallengeID string     `gorm:"type:uuid;primaryKey" json:"challenge_id"`
+	UserID      string     `gorm:"size:64;primaryKey" json:"user_id"`
+	Username    string     `gorm:"size:64" json:"username"`
+	Solved      bool       `json:"solved"`
+	GaveUp      bool       `json:"gave_up"` // gave up or ran out of attempts
+	Attempts    int        `json:"attempts"`
+	Guesses     []string   `gorm:"serializer:json" json:"guesses"` // ordered anime_ids
+	SolvedAt    *time.Time `json:"solved_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (ChallengeResult) TableName() string { return "anidle_challenge_result" }
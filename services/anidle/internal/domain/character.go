@@ -0,0 +1,29 @@
+package domain
+
+// PoolCharacter is one guessable character of the character mode, decoded
+// from catalog's GET /internal/guessgame/characters. Field shape MUST match
+// the catalog DTO (services/catalog/internal/service/guesscharacters.go
+// GuessCharacterEntry). Franchise is the franchise slug of the first
+// appearance (the anime ID for a standalone); Year is that anime's year.
+type PoolCharacter struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	NameRU        string  `json:"name_ru"`
+	NameJP        string  `json:"name_jp"`
+	PosterURL     string  `json:"poster_url"`
+	Gender        string  `json:"gender"` // "male" / "female" / "" (unknown)
+	Role          string  `json:"role"`   // "main" / "supporting"
+	Franchise     string  `json:"franchise"`
+	FranchiseName string  `json:"franchise_name"`
+	Year          int     `json:"year"`
+	VoiceActors   []Taxon `json:"voice_actors"`
+}
+
+// CharacterComparison is the per-column result of one character guess.
+type CharacterComparison struct {
+	Gender     ColumnResult `json:"gender"`
+	Role       ColumnResult `json:"role"`
+	Franchise  ColumnResult `json:"franchise"`
+	Year       ColumnResult `json:"year"`
+	VoiceActor ColumnResult `json:"voice_actor"`
+}
@@ -2,6 +2,17 @@ package domain
 
 import "time"
 
+// Game modes. Each mode has its own daily secret, result rows, stats and
+// leaderboard key.
+const (
+	ModeDaily     = "daily"     // classic: guess the anime by its attributes
+	ModeCharacter = "character" // guess the character
+	ModeTheme     = "theme"     // guess the anime from an OP/ED audio snippet
+)
+
+// Modes lists every game mode.
+var Modes = []string{ModeDaily, ModeCharacter, ModeTheme}
+
 // Snapshot is the frozen answer (the 8 attributes + display fields) stored as
 // JSONB on a daily puzzle. It is a PoolAnime — reuse the same shape so Compare
 // works directly.
@@ -22,11 +33,13 @@ type UserGameResult struct {
 	ID         string     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	UserID     string     `gorm:"size:64;index:idx_anidle_result_user_date_mode,unique,priority:1" json:"user_id"`
 	PuzzleDate string     `gorm:"size:10;index:idx_anidle_result_user_date_mode,unique,priority:2" json:"puzzle_date"`
-	Mode       string     `gorm:"size:16;index:idx_anidle_result_user_date_mode,unique,priority:3" json:"mode"` // "daily"
+	Mode       string     `gorm:"size:16;index:idx_anidle_result_user_date_mode,unique,priority:3" json:"mode"` // ModeDaily / ModeCharacter / ModeTheme
 	Solved     bool       `json:"solved"`
 	GaveUp     bool       `json:"gave_up"` // finished-but-lost sentinel (distinct from "still playing")
 	Attempts   int        `json:"attempts"`
 	Guesses    []string   `gorm:"serializer:json" json:"guesses"` // ordered anime_ids
+	Hints      []string   `gorm:"serializer:json" json:"hints"`   // HintKinds used, in order (classic mode)
+	Hard       bool       `json:"hard"`                           // hard mode, fixed by the first guess (classic mode)
 	SolvedAt   *time.Time `json:"solved_at,omitempty"`
 	CreatedAt  time.Time  `json:"created_at"`
 	UpdatedAt  time.Time  `json:"updated_at"`
@@ -34,7 +47,21 @@ type UserGameResult struct {
 
 func (UserGameResult) TableName() string { return "anidle_user_game_result" }
 
-// UserStats is the per-user aggregate.
+// ModePuzzle is the secret of one calendar day (UTC) for the character and
+// theme modes. Snapshot freezes the secret (a PoolCharacter / PoolTheme as
+// JSON) so a pool refresh cannot change the day's answer. Immutable once
+// created.
+type ModePuzzle struct {
+	Date      string    `gorm:"primaryKey;size:10" json:"date"`
+	Mode      string    `gorm:"primaryKey;size:16" json:"mode"`
+	SecretID  string    `gorm:"size:64;index" json:"secret_id"`
+	Snapshot  string    `gorm:"type:text" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (ModePuzzle) TableName() string { return "anidle_mode_puzzle" }
+
+// UserStats is the per-user aggregate of the classic daily mode.
 type UserStats struct {
 	UserID            string         `gorm:"size:64;primaryKey" json:"user_id"`
 	GamesPlayed       int            `json:"games_played"`
@@ -42,8 +69,17 @@ type UserStats struct {
 	CurrentStreak     int            `json:"current_streak"`
 	MaxStreak         int            `json:"max_streak"`
 	GuessDistribution map[string]int `gorm:"serializer:json" json:"guess_distribution"` // attempts -> count
+	HintsUsed         int            `json:"hints_used"`                                // classic mode only
 	LastPlayedDate    string         `gorm:"size:10" json:"last_played_date"`
 	UpdatedAt         time.Time      `json:"updated_at"`
 }
 
 func (UserStats) TableName() string { return "anidle_user_stats" }
+
+// UserModeStats is UserStats for the other modes, keyed by (user, mode).
+type UserModeStats struct {
+	Mode string `gorm:"size:16;primaryKey" json:"mode"`
+	UserStats
+}
+
+func (UserModeStats) TableName() string { return "anidle_user_mode_stats" }
@@ -0,0 +1,24 @@
+package domain
+
+// HintKind is a hint of the classic daily mode. Hints unlock as the player
+// makes guesses; every use is recorded on the result row and counts against
+// the player in stats and leaderboard tiebreaks.
+type HintKind string
+
+const (
+	HintTag    HintKind = "tag"    // one of the secret's tags
+	HintStudio HintKind = "studio" // the secret's (first) studio
+	HintLetter HintKind = "letter" // the first letter of the title
+	HintPoster HintKind = "poster" // a pixelated poster
+)
+
+// HintKinds lists every hint in unlock order.
+var HintKinds = []HintKind{HintTag, HintStudio, HintLetter, HintPoster}
+
+// HintUnlockAfter is the number of guesses after which a hint unlocks.
+var HintUnlockAfter = map[HintKind]int{
+	HintTag:    2,
+	HintStudio: 4,
+	HintLetter: 6,
+	HintPoster: 8,
+}
@@ -0,0 +1,37 @@
+package domain
+
+// PoolTheme is one OP/ED with audio, decoded from themes' GET
+// /internal/guessgame/themes. Field shape MUST match the themes DTO
+// (services/themes/internal/domain/theme.go GuessTheme). It is the secret of
+// the theme mode; the player guesses its anime.
+type PoolTheme struct {
+	ID            string `json:"id"`
+	Slug          string `json:"slug"`       // "OP1", "ED2"
+	ThemeType     string `json:"theme_type"` // "OP" / "ED"
+	SongTitle     string `json:"song_title"`
+	ArtistName    string `json:"artist_name"`
+	AudioBasename string `json:"audio_basename"`
+	AnimeID       string `json:"anime_id"`
+	AnimeName     string `json:"anime_name"`
+	AnimeNameRU   string `json:"anime_name_ru"`
+	PosterURL     string `json:"poster_url"`
+	Franchise     string `json:"franchise"`
+	Year          int    `json:"year"`
+}
+
+// ThemeAnime is a guessable answer of the theme mode: an anime that has at
+// least one theme in the pool.
+type ThemeAnime struct {
+	ID        string `json:"id"`
+	NameRU    string `json:"name_ru"`
+	NameEN    string `json:"name_en"`
+	PosterURL string `json:"poster_url"`
+	Franchise string `json:"franchise,omitempty"`
+	Year      int    `json:"year"`
+}
+
+// ThemeComparison is the per-column result of one theme-mode guess.
+type ThemeComparison struct {
+	Franchise ColumnResult `json:"franchise"`
+	Year      ColumnResult `json:"year"`
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+)
+
+// accountStore is the export + erasure port of the account fan-out
+// (repo.AccountRepo in production).
+type accountStore interface {
+	Export(ctx context.Context, userID string) (map[string][]map[string]any, error)
+	Erase(ctx context.Context, userID string) (map[string]int64, error)
+}
+
+// leaderboardForgetter drops a username from the daily boards.
+type leaderboardForgetter interface {
+	Forget(ctx context.Context, username string, dates ...string) error
+}
+
+// AccountInternalHandler serves the auth service's account fan-out:
+//
+//	POST /internal/account/export  {"user_id"} → the user's anidle rows
+//	POST /internal/account/erase   {"user_id","username"} → hard-delete them
+//	                               and drop the username from the live
+//	                               leaderboards (idempotent)
+//
+// Docker-network only — the gateway never proxies /internal/*.
+type AccountInternalHandler struct {
+	store accountStore
+	lb    leaderboardForgetter
+	log   *logger.Logger
+}
+
+func NewAccountInternalHandler(store accountStore, lb leaderboardForgetter, log *logger.Logger) *AccountInternalHandler {
+	return &AccountInternalHandler{store: store, lb: lb, log: log}
+}
+
+type accountRequest struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// Export handles POST /internal/account/export.
+func (h *AccountInternalHandler) Export(w http.ResponseWriter, r *http.Request) {
+	var req accountRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	if req.UserID == "" {
+		httputil.BadRequest(w, "user_id is required")
+		return
+	}
+	tables, err := h.store.Export(r.Context(), req.UserID)
+	if err != nil {
+		h.log.Errorw("account export failed", "user_id", req.UserID, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, map[string]any{"tables": tables})
+}
+
+// Erase handles POST /internal/account/erase. Leaderboard keys live 48h, so
+// today's and yesterday's boards are the only ones that can still hold the
+// username.
+func (h *AccountInternalHandler) Erase(w http.ResponseWriter, r *http.Request) {
+	var req accountRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	if req.UserID == "" {
+		httputil.BadRequest(w, "user_id is required")
+		return
+	}
+	deleted, err := h.store.Erase(r.Context(), req.UserID)
+	if err != nil {
+		h.log.Errorw("account erase failed", "user_id", req.UserID, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	if req.Username != "" && h.lb != nil {
+		now := time.Now().UTC()
+		today := now.Format("2006-01-02")
+		yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+		if err := h.lb.Forget(r.Context(), req.Username, today, yesterday); err != nil {
+			h.log.Errorw("account leaderboard erase failed", "user_id", req.UserID, "error", err)
+			httputil.Error(w, err)
+			return
+		}
+	}
+	h.log.Infow("account erased", "user_id", req.UserID, "deleted", deleted)
+	httputil.OK(w, map[string]any{"status": "erased", "deleted": deleted})
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"time"
 
@@ -14,9 +15,11 @@ import (
 
 type dailyService interface {
 	GetOrCreateToday(ctx context.Context) (*domain.DailyPuzzle, error)
-	Guess(ctx context.Context, userID, animeID string) (*service.GuessOutcome, error)
+	Guess(ctx context.Context, userID, animeID string, hard bool) (*service.GuessOutcome, error)
 	GiveUp(ctx context.Context, userID string) (*service.VisibleAnime, error)
 	Resume(ctx context.Context, userID string) (*service.DailyState, error)
+	UseHint(ctx context.Context, userID string, kind domain.HintKind) (*service.HintReveal, error)
+	PosterHint(ctx context.Context, userID string) (string, error)
 }
 type endlessService interface {
 	NewRound(ctx context.Context) (*service.EndlessRound, error)
@@ -24,10 +27,17 @@ type endlessService interface {
 }
 type statsService interface {
 	Get(ctx context.Context, userID string) (*domain.UserStats, error)
+	GetMode(ctx context.Context, userID, mode string) (*domain.UserStats, error)
 }
 type leaderboardService interface {
 	Top(ctx context.Context, date string, n int) ([]service.LeaderEntry, error)
-	RecordSolve(ctx context.Context, date, username string, attempts int, solveUnix int64) error
+	TopMode(ctx context.Context, mode, date string, n int) ([]service.LeaderEntry, error)
+	TopAmong(ctx context.Context, mode, date string, usernames []string) ([]service.LeaderEntry, error)
+	RecordSolve(ctx context.Context, date, username string, attempts, hints int, solveUnix int64) error
+	RecordModeSolve(ctx context.Context, mode, date, username string, attempts int, solveUnix int64) error
+}
+type friendsLister interface {
+	FollowedUsernames(ctx context.Context, followerID string) ([]string, error)
 }
 type searchService interface {
 	Search(ctx context.Context, q string, limit int) []domain.PoolAnime
@@ -39,13 +49,19 @@ type AnidleHandler struct {
 	stats   statsService
 	lb      leaderboardService
 	search  searchService
+	friends friendsLister // nil: the friends view is unavailable
+	client  *http.Client  // fetches posters for the poster hint
 	log     *logger.Logger
 }
 
 func NewAnidleHandler(d dailyService, e endlessService, st statsService, lb leaderboardService, s searchService) *AnidleHandler {
-	return &AnidleHandler{daily: d, endless: e, stats: st, lb: lb, search: s}
+	return &AnidleHandler{daily: d, endless: e, stats: st, lb: lb, search: s,
+		client: &http.Client{Timeout: 15 * time.Second}}
 }
 
+// SetFriends enables the friends-only leaderboard view (?scope=friends).
+func (h *AnidleHandler) SetFriends(f friendsLister) { h.friends = f }
+
 func userID(r *http.Request) (id, username string) {
 	if claims, ok := authz.ClaimsFromContext(r.Context()); ok && claims != nil {
 		return claims.UserID, claims.Username
@@ -56,6 +72,7 @@ func userID(r *http.Request) (id, username string) {
 type guessReq struct {
 	AnimeID    string `json:"anime_id"`
 	RoundToken string `json:"round_token"`
+	Hard       bool   `json:"hard"` // daily only; fixed by the first guess
 }
 
 func (h *AnidleHandler) DailyMeta(w http.ResponseWriter, r *http.Request) {
@@ -75,7 +92,7 @@ func (h *AnidleHandler) DailyGuess(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	uid, username := userID(r)
-	out, err := h.daily.Guess(r.Context(), uid, req.AnimeID)
+	out, err := h.daily.Guess(r.Context(), uid, req.AnimeID, req.Hard)
 	if err != nil {
 		httputil.Error(w, err)
 		return
@@ -84,7 +101,7 @@ func (h *AnidleHandler) DailyGuess(w http.ResponseWriter, r *http.Request) {
 	// re-submitting the correct answer must not rewrite (worsen) the rank.
 	if out.FreshSolve && uid != "" && username != "" && h.lb != nil {
 		date := time.Now().UTC().Format("2006-01-02")
-		_ = h.lb.RecordSolve(r.Context(), date, username, out.Attempt, time.Now().UTC().Unix())
+		_ = h.lb.RecordSolve(r.Context(), date, username, out.Attempt, out.HintsUsed, time.Now().UTC().Unix())
 	}
 	httputil.OK(w, out)
 }
@@ -100,6 +117,58 @@ func (h *AnidleHandler) DailyGiveUp(w http.ResponseWriter, r *http.Request) {
 	httputil.OK(w, ans)
 }
 
+// DailyHint handles POST /api/anidle/daily/hint {kind} — logged-in only.
+func (h *AnidleHandler) DailyHint(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Kind domain.HintKind `json:"kind"`
+	}
+	if err := httputil.Bind(r, &req); err != nil || req.Kind == "" {
+		httputil.BadRequest(w, "kind is required")
+		return
+	}
+	uid, _ := userID(r)
+	reveal, err := h.daily.UseHint(r.Context(), uid, req.Kind)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, reveal)
+}
+
+// DailyHintPoster handles GET /api/anidle/daily/hint/poster — the secret's
+// poster, pixelated server-side so the original URL never reaches the client.
+func (h *AnidleHandler) DailyHintPoster(w http.ResponseWriter, r *http.Request) {
+	uid, _ := userID(r)
+	posterURL, err := h.daily.PosterHint(r.Context(), uid)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, posterURL, nil)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		httputil.JSON(w, http.StatusBadGateway, map[string]string{"error": "poster unavailable"})
+		return
+	}
+	img, err := service.PixelatePoster(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		httputil.JSON(w, http.StatusBadGateway, map[string]string{"error": "poster unavailable"})
+		return
+	}
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "private, no-store")
+	_, _ = w.Write(img)
+}
+
 func (h *AnidleHandler) Search(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query().Get("q")
 	res := h.search.Search(r.Context(), q, 10)
@@ -132,13 +201,20 @@ func (h *AnidleHandler) EndlessGuess(w http.ResponseWriter, r *http.Request) {
 	httputil.OK(w, out)
 }
 
+// Stats handles GET /api/anidle/stats[?mode=character|theme] (default: the
+// classic daily mode).
 func (h *AnidleHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	mode, ok := queryMode(r)
+	if !ok {
+		httputil.BadRequest(w, "unknown mode")
+		return
+	}
 	uid, _ := userID(r)
 	if uid == "" {
 		httputil.NoContent(w)
 		return
 	}
-	st, err := h.stats.Get(r.Context(), uid)
+	st, err := h.stats.GetMode(r.Context(), uid, mode)
 	if err != nil {
 		httputil.Error(w, err)
 		return
@@ -146,15 +222,67 @@ func (h *AnidleHandler) Stats(w http.ResponseWriter, r *http.Request) {
 	httputil.OK(w, st)
 }
 
+// Leaderboard handles GET /api/anidle/leaderboard[?date=&mode=&scope=friends]
+// — each mode has its own board. scope=friends narrows it to the caller and
+// the users they follow.
 func (h *AnidleHandler) Leaderboard(w http.ResponseWriter, r *http.Request) {
+	mode, ok := queryMode(r)
+	if !ok {
+		httputil.BadRequest(w, "unknown mode")
+		return
+	}
 	date := r.URL.Query().Get("date")
 	if date == "" {
 		date = time.Now().UTC().Format("2006-01-02")
 	}
-	top, err := h.lb.Top(r.Context(), date, 50)
+	if r.URL.Query().Get("scope") == "friends" {
+		h.friendsLeaderboard(w, r, mode, date)
+		return
+	}
+	top, err := h.lb.TopMode(r.Context(), mode, date, 50)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, top)
+}
+
+func (h *AnidleHandler) friendsLeaderboard(w http.ResponseWriter, r *http.Request, mode, date string) {
+	uid, username := userID(r)
+	if uid == "" {
+		httputil.Unauthorized(w)
+		return
+	}
+	if h.friends == nil {
+		httputil.BadRequest(w, "friends leaderboard is unavailable")
+		return
+	}
+	names, err := h.friends.FollowedUsernames(r.Context(), uid)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	if username != "" {
+		names = append(names, username)
+	}
+	top, err := h.lb.TopAmong(r.Context(), mode, date, names)
 	if err != nil {
 		httputil.Error(w, err)
 		return
 	}
 	httputil.OK(w, top)
 }
+
+// queryMode reads ?mode=, defaulting to the classic daily mode.
+func queryMode(r *http.Request) (string, bool) {
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		return domain.ModeDaily, true
+	}
+	for _, m := range domain.Modes {
+		if m == mode {
+			return mode, true
+		}
+	}
+	return "", false
+}
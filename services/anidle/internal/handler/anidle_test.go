@@ -22,12 +22,16 @@ type fakeDaily struct {
 func (f *fakeDaily) GetOrCreateToday(_ context.Context) (*domain.DailyPuzzle, error) {
 	return &domain.DailyPuzzle{Date: "2026-06-15"}, nil
 }
-func (f *fakeDaily) Guess(_ context.Context, _, _ string) (*service.GuessOutcome, error) {
+func (f *fakeDaily) Guess(_ context.Context, _, _ string, _ bool) (*service.GuessOutcome, error) {
 	return f.guessOut, nil
 }
 func (f *fakeDaily) GiveUp(_ context.Context, _ string) (*service.VisibleAnime, error) {
 	return &service.VisibleAnime{ID: "frieren"}, nil
 }
+func (f *fakeDaily) UseHint(_ context.Context, _ string, kind domain.HintKind) (*service.HintReveal, error) {
+	return &service.HintReveal{Kind: kind, Text: "Madhouse"}, nil
+}
+func (f *fakeDaily) PosterHint(_ context.Context, _ string) (string, error) { return "", nil }
 func (f *fakeDaily) Resume(_ context.Context, _ string) (*service.DailyState, error) {
 	return &service.DailyState{Date: "2026-06-15", Guesses: []service.GuessOutcome{}}, nil
 }
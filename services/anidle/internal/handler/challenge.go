@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/service"
+)
+
+type challengeService interface {
+	Create(ctx context.Context, creatorID, creatorName string, in service.ChallengeInput) (*domain.Challenge, error)
+	ListMine(ctx context.Context, creatorID string) ([]domain.Challenge, error)
+	Resume(ctx context.Context, id, userID string) (*service.ChallengeState, error)
+	Guess(ctx context.Context, id, userID, username, animeID string) (*service.ChallengeGuess, error)
+	GiveUp(ctx context.Context, id, userID, username string) (*service.VisibleAnime, error)
+	Leaderboard(ctx context.Context, id string) ([]service.LeaderEntry, error)
+}
+
+// ChallengeHandler serves user-made challenges:
+//
+//	POST /challenges               create (logged-in); returns the share id
+//	GET  /challenges               the caller's challenges
+//	GET  /challenges/{id}          public view + the caller's progress
+//	POST /challenges/{id}/guess    (logged-in)
+//	POST /challenges/{id}/giveup   (logged-in)
+//	GET  /challenges/{id}/leaderboard
+type ChallengeHandler struct {
+	challenges challengeService
+}
+
+func NewChallengeHandler(c challengeService) *ChallengeHandler {
+	return &ChallengeHandler{challenges: c}
+}
+
+func (h *ChallengeHandler) Create(w http.ResponseWriter, r *http.Request) {
+	uid, username := userID(r)
+	if uid == "" {
+		httputil.Unauthorized(w)
+		return
+	}
+	var req service.ChallengeInput
+	if err := httputil.Bind(r, &req); err != nil || req.AnimeID == "" {
+		httputil.BadRequest(w, "anime_id is required")
+		return
+	}
+	c, err := h.challenges.Create(r.Context(), uid, username, req)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.Created(w, c)
+}
+
+func (h *ChallengeHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	uid, _ := userID(r)
+	if uid == "" {
+		httputil.Unauthorized(w)
+		return
+	}
+	list, err := h.challenges.ListMine(r.Context(), uid)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	if list == nil {
+		list = []domain.Challenge{}
+	}
+	httputil.OK(w, list)
+}
+
+func (h *ChallengeHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := challengeID(w, r)
+	if !ok {
+		return
+	}
+	uid, _ := userID(r)
+	state, err := h.challenges.Resume(r.Context(), id, uid)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, state)
+}
+
+func (h *ChallengeHandler) Guess(w http.ResponseWriter, r *http.Request) {
+	id, ok := challengeID(w, r)
+	if !ok {
+		return
+	}
+	var req guessReq
+	if err := httputil.Bind(r, &req); err != nil || req.AnimeID == "" {
+		httputil.BadRequest(w, "anime_id is required")
+		return
+	}
+	uid, username := userID(r)
+	out, err := h.challenges.Guess(r.Context(), id, uid, username, req.AnimeID)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, out)
+}
+
+func (h *ChallengeHandler) GiveUp(w http.ResponseWriter, r *http.Request) {
+	id, ok := challengeID(w, r)
+	if !ok {
+		return
+	}
+	uid, username := userID(r)
+	ans, err := h.challenges.GiveUp(r.Context(), id, uid, username)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, ans)
+}
+
+func (h *ChallengeHandler) Leaderboard(w http.ResponseWriter, r *http.Request) {
+	id, ok := challengeID(w, r)
+	if !ok {
+		return
+	}
+	top, err := h.challenges.Leaderboard(r.Context(), id)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, top)
+}
+
+// challengeID reads {id}; challenge ids are UUIDs, anything else is a 404.
+func challengeID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(id); err != nil {
+		httputil.NotFound(w, "challenge")
+		return "", false
+	}
+	return id, true
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/service"
+)
+
+type characterService interface {
+	Guess(ctx context.Context, userID, characterID string) (*service.CharacterGuess, error)
+	GiveUp(ctx context.Context, userID string) (*domain.PoolCharacter, error)
+	Resume(ctx context.Context, userID string) (*service.CharacterState, error)
+	Search(ctx context.Context, q string, limit int) []domain.PoolCharacter
+}
+
+type themeService interface {
+	Guess(ctx context.Context, userID, animeID string) (*service.ThemeGuess, error)
+	GiveUp(ctx context.Context, userID string) (*domain.PoolTheme, error)
+	Resume(ctx context.Context, userID string) (*service.ThemeState, error)
+	Audio(ctx context.Context, userID string, step int) (*service.ThemeAudio, error)
+	Search(ctx context.Context, q string, limit int) []domain.ThemeAnime
+}
+
+// ModesHandler serves the character and OP/ED modes:
+//
+//	GET  /character, POST /character/guess, POST /character/giveup, GET /character/search
+//	GET  /theme,     POST /theme/guess,     POST /theme/giveup,     GET /theme/search
+//	GET  /theme/audio?step=N — the day's track, cut to the unlocked snippet
+//
+// Stats and leaderboards of every mode are served by AnidleHandler (?mode=).
+type ModesHandler struct {
+	character characterService
+	theme     themeService
+	lb        leaderboardService
+	themesURL string
+	client    *http.Client
+	log       *logger.Logger
+}
+
+func NewModesHandler(c characterService, t themeService, lb leaderboardService, themesURL string, log *logger.Logger) *ModesHandler {
+	return &ModesHandler{
+		character: c, theme: t, lb: lb, themesURL: themesURL, log: log,
+		client: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+type modeGuessReq struct {
+	CharacterID string `json:"character_id"`
+	AnimeID     string `json:"anime_id"`
+}
+
+// recordSolve writes the one-time leaderboard entry of a fresh solve.
+func (h *ModesHandler) recordSolve(r *http.Request, mode string, fresh bool, attempts int) {
+	uid, username := userID(r)
+	if !fresh || uid == "" || username == "" || h.lb == nil {
+		return
+	}
+	now := time.Now().UTC()
+	_ = h.lb.RecordModeSolve(r.Context(), mode, now.Format("2006-01-02"), username, attempts, now.Unix())
+}
+
+func (h *ModesHandler) CharacterMeta(w http.ResponseWriter, r *http.Request) {
+	uid, _ := userID(r)
+	state, err := h.character.Resume(r.Context(), uid)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, state)
+}
+
+func (h *ModesHandler) CharacterGuess(w http.ResponseWriter, r *http.Request) {
+	var req modeGuessReq
+	if err := httputil.Bind(r, &req); err != nil || req.CharacterID == "" {
+		httputil.BadRequest(w, "character_id is required")
+		return
+	}
+	uid, _ := userID(r)
+	out, err := h.character.Guess(r.Context(), uid, req.CharacterID)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	h.recordSolve(r, domain.ModeCharacter, out.FreshSolve, out.Attempt)
+	httputil.OK(w, out)
+}
+
+func (h *ModesHandler) CharacterGiveUp(w http.ResponseWriter, r *http.Request) {
+	uid, _ := userID(r)
+	ans, err := h.character.GiveUp(r.Context(), uid)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, ans)
+}
+
+func (h *ModesHandler) CharacterSearch(w http.ResponseWriter, r *http.Request) {
+	res := h.character.Search(r.Context(), r.URL.Query().Get("q"), 10)
+	if res == nil {
+		res = []domain.PoolCharacter{}
+	}
+	httputil.OK(w, res)
+}
+
+func (h *ModesHandler) ThemeMeta(w http.ResponseWriter, r *http.Request) {
+	uid, _ := userID(r)
+	state, err := h.theme.Resume(r.Context(), uid)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, state)
+}
+
+func (h *ModesHandler) ThemeGuess(w http.ResponseWriter, r *http.Request) {
+	var req modeGuessReq
+	if err := httputil.Bind(r, &req); err != nil || req.AnimeID == "" {
+		httputil.BadRequest(w, "anime_id is required")
+		return
+	}
+	uid, _ := userID(r)
+	out, err := h.theme.Guess(r.Context(), uid, req.AnimeID)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	h.recordSolve(r, domain.ModeTheme, out.FreshSolve, out.Attempt)
+	httputil.OK(w, out)
+}
+
+func (h *ModesHandler) ThemeGiveUp(w http.ResponseWriter, r *http.Request) {
+	uid, _ := userID(r)
+	ans, err := h.theme.GiveUp(r.Context(), uid)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, ans)
+}
+
+func (h *ModesHandler) ThemeSearch(w http.ResponseWriter, r *http.Request) {
+	res := h.theme.Search(r.Context(), r.URL.Query().Get("q"), 10)
+	if res == nil {
+		res = []domain.ThemeAnime{}
+	}
+	httputil.OK(w, res)
+}
+
+// ThemeAudio handles GET /api/anidle/theme/audio?step=N. It streams the day's
+// track through the themes audio proxy, cut to the snippet the player has
+// unlocked, so the basename (which names the anime) never reaches the client
+// before the game is over.
+func (h *ModesHandler) ThemeAudio(w http.ResponseWriter, r *http.Request) {
+	step, _ := strconv.Atoi(r.URL.Query().Get("step"))
+	uid, _ := userID(r)
+	audio, err := h.theme.Audio(r.Context(), uid, step)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, h.themesURL+"/api/themes/audio/"+audio.Basename, nil)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	if audio.MaxBytes > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", audio.MaxBytes-1))
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		h.log.Errorw("theme audio fetch failed", "error", err)
+		httputil.BadRequest(w, "failed to fetch audio")
+		return
+	}
+	defer resp.Body.Close()
+
+	w.Header().Set("Content-Type", "audio/ogg")
+	w.Header().Set("Cache-Control", "no-store")
+	// The snippet is capped here too: an upstream that ignores Range would
+	// otherwise hand over the whole track. Its Content-Length then no longer
+	// describes the body, so it is only forwarded for the full track.
+	body := io.Reader(resp.Body)
+	if audio.MaxBytes > 0 {
+		body = io.LimitReader(resp.Body, audio.MaxBytes)
+	} else if cl := resp.Header.Get("Content-Length"); cl != "" {
+		w.Header().Set("Content-Length", cl)
+	}
+	// A snippet is served as a complete (short) file, not a partial range of
+	// the track, so the player cannot seek past it.
+	status := resp.StatusCode
+	if status == http.StatusPartialContent {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	if _, err := io.Copy(w, body); err != nil {
+		h.log.Debugw("theme audio copy interrupted", "error", err)
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/service"
+)
+
+type fakeTheme struct {
+	audio *service.ThemeAudio
+}
+
+func (f *fakeTheme) Guess(context.Context, string, string) (*service.ThemeGuess, error) {
+	return nil, nil
+}
+func (f *fakeTheme) GiveUp(context.Context, string) (*domain.PoolTheme, error) { return nil, nil }
+func (f *fakeTheme) Resume(context.Context, string) (*service.ThemeState, error) {
+	return nil, nil
+}
+func (f *fakeTheme) Audio(context.Context, string, int) (*service.ThemeAudio, error) {
+	return f.audio, nil
+}
+func (f *fakeTheme) Search(context.Context, string, int) []domain.ThemeAnime { return nil }
+
+// TestModes_ThemeAudio_CapsSnippet covers an upstream that ignores Range and
+// sends the whole track: the snippet is still cut to MaxBytes and the
+// upstream Content-Length is not forwarded.
+func TestModes_ThemeAudio_CapsSnippet(t *testing.T) {
+	track := strings.Repeat("x", 4096)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/themes/audio/secret.ogg", r.URL.Path)
+		w.Header().Set("Content-Length", "4096")
+		_, _ = w.Write([]byte(track))
+	}))
+	defer upstream.Close()
+
+	theme := &fakeTheme{audio: &service.ThemeAudio{Basename: "secret.ogg", MaxBytes: 1000}}
+	h := NewModesHandler(nil, theme, nil, upstream.URL, logger.Default())
+
+	rec := httptest.NewRecorder()
+	h.ThemeAudio(rec, httptest.NewRequest(http.MethodGet, "/api/anidle/theme/audio?step=0", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1000, rec.Body.Len())
+	assert.Empty(t, rec.Header().Get("Content-Length"))
+
+	// A finished game streams the whole track with its length.
+	theme.audio = &service.ThemeAudio{Basename: "secret.ogg"}
+	rec = httptest.NewRecorder()
+	h.ThemeAudio(rec, httptest.NewRequest(http.MethodGet, "/api/anidle/theme/audio", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 4096, rec.Body.Len())
+	assert.Equal(t, "4096", rec.Header().Get("Content-Length"))
+}
@@ -0,0 +1,44 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ILITA-hub/animeenigma/libs/database"
+	"gorm.io/gorm"
+)
+
+// accountUserTables lists the anidle tables holding a user's rows — game
+// results, the aggregated stats of every mode and custom challenges — in
+// erase order.
+var accountUserTables = []database.UserTable{
+	{Table: "anidle_user_game_result", Column: "user_id"},
+	{Table: "anidle_user_stats", Column: "user_id"},
+	{Table: "anidle_user_mode_stats", Column: "user_id"},
+	{Table: "anidle_challenge_result", Column: "user_id"},
+	{Table: "anidle_challenge", Column: "creator_id"},
+}
+
+// AccountRepo backs the auth-driven account export + erasure fan-out
+// (/internal/account/*).
+type AccountRepo struct{ db *gorm.DB }
+
+func NewAccountRepo(db *gorm.DB) *AccountRepo { return &AccountRepo{db: db} }
+
+// Export returns every anidle row of the user, keyed by table.
+func (r *AccountRepo) Export(ctx context.Context, userID string) (map[string][]map[string]any, error) {
+	rows, err := database.ExportUserRows(ctx, r.db, userID, accountUserTables)
+	if err != nil {
+		return nil, fmt.Errorf("export account: %w", err)
+	}
+	return rows, nil
+}
+
+// Erase hard-deletes every anidle row of the user.
+func (r *AccountRepo) Erase(ctx context.Context, userID string) (map[string]int64, error) {
+	deleted, err := database.EraseUserRows(ctx, r.db, userID, accountUserTables)
+	if err != nil {
+		return nil, fmt.Errorf("erase account: %w", err)
+	}
+	return deleted, nil
+}
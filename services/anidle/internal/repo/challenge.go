@@ -0,0 +1,109 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/domain"
+)
+
+// ChallengeRepo persists user-made challenges and their results.
+type ChallengeRepo struct{ db *gorm.DB }
+
+func NewChallengeRepo(db *gorm.DB) *ChallengeRepo { return &ChallengeRepo{db: db} }
+
+func (r *ChallengeRepo) CreateChallenge(ctx context.Context, c *domain.Challenge) error {
+	// Ensure ID is set for databases that don't auto-generate UUIDs (e.g. sqlite in tests).
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return r.db.WithContext(ctx).Create(c).Error
+}
+
+func (r *ChallengeRepo) GetChallenge(ctx context.Context, id string) (*domain.Challenge, error) {
+	var c domain.Challenge
+	err := r.db.WithContext(ctx).First(&c, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get challenge: %w", err)
+	}
+	return &c, nil
+}
+
+// ListByCreator returns a creator's challenges, newest first.
+func (r *ChallengeRepo) ListByCreator(ctx context.Context, creatorID string, limit int) ([]domain.Challenge, error) {
+	var out []domain.Challenge
+	err := r.db.WithContext(ctx).Where("creator_id = ?", creatorID).
+		Order("created_at DESC").Limit(limit).Find(&out).Error
+	if err != nil {
+		return nil, fmt.Errorf("list challenges: %w", err)
+	}
+	return out, nil
+}
+
+// CountActive counts a creator's challenges that have not expired at now.
+func (r *ChallengeRepo) CountActive(ctx context.Context, creatorID string, now time.Time) (int, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&domain.Challenge{}).
+		Where("creator_id = ? AND expires_at > ?", creatorID, now).Count(&n).Error
+	if err != nil {
+		return 0, fmt.Errorf("count active challenges: %w", err)
+	}
+	return int(n), nil
+}
+
+// DeleteExpired removes challenges that expired before `before`, together
+// with their results.
+func (r *ChallengeRepo) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		expired := tx.Model(&domain.Challenge{}).Select("id").Where("expires_at < ?", before)
+		if err := tx.Where("challenge_id IN (?)", expired).Delete(&domain.ChallengeResult{}).Error; err != nil {
+			return err
+		}
+		res := tx.Where("expires_at < ?", before).Delete(&domain.Challenge{})
+		deleted = res.RowsAffected
+		return res.Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("delete expired challenges: %w", err)
+	}
+	return deleted, nil
+}
+
+func (r *ChallengeRepo) GetChallengeResult(ctx context.Context, challengeID, userID string) (*domain.ChallengeResult, error) {
+	var res domain.ChallengeResult
+	err := r.db.WithContext(ctx).First(&res, "challenge_id = ? AND user_id = ?", challengeID, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil // absence is not an error for resume
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get challenge result: %w", err)
+	}
+	return &res, nil
+}
+
+// SaveChallengeResult upserts on (challenge_id, user_id).
+func (r *ChallengeRepo) SaveChallengeResult(ctx context.Context, res *domain.ChallengeResult) error {
+	return r.db.WithContext(ctx).Save(res).Error
+}
+
+// ChallengeSolvers returns the solved results of a challenge ranked by fewest
+// attempts, then earliest solve.
+func (r *ChallengeRepo) ChallengeSolvers(ctx context.Context, challengeID string, limit int) ([]domain.ChallengeResult, error) {
+	var out []domain.ChallengeResult
+	err := r.db.WithContext(ctx).
+		Where("challenge_id = ? AND solved = ?", challengeID, true).
+		Order("attempts ASC, solved_at ASC").Limit(limit).Find(&out).Error
+	if err != nil {
+		return nil, fmt.Errorf("challenge solvers: %w", err)
+	}
+	return out, nil
+}
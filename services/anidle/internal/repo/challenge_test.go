@@ -0,0 +1,66 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/domain"
+)
+
+func newChallengeTestRepo(t *testing.T) *ChallengeRepo {
+	db := newTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE anidle_challenge (
+		id TEXT PRIMARY KEY, creator_id TEXT, creator_name TEXT, title TEXT, anime_id TEXT,
+		answer_snapshot TEXT, max_attempts INTEGER, hints TEXT, expires_at DATETIME, created_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE anidle_challenge_result (
+		challenge_id TEXT, user_id TEXT, username TEXT, solved INTEGER, gave_up INTEGER, attempts INTEGER,
+		guesses TEXT, solved_at DATETIME, created_at DATETIME, updated_at DATETIME,
+		PRIMARY KEY (challenge_id, user_id))`).Error)
+	return NewChallengeRepo(db)
+}
+
+func TestChallengeRepo_SolversAndPurge(t *testing.T) {
+	r := newChallengeTestRepo(t)
+	ctx := context.Background()
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	live := &domain.Challenge{CreatorID: "u1", AnimeID: "frieren",
+		Hints: []domain.ChallengeHint{{AfterAttempts: 1, Text: "elves"}}, ExpiresAt: now.Add(time.Hour)}
+	old := &domain.Challenge{CreatorID: "u1", AnimeID: "mob", ExpiresAt: now.Add(-time.Hour)}
+	require.NoError(t, r.CreateChallenge(ctx, live))
+	require.NoError(t, r.CreateChallenge(ctx, old))
+
+	got, err := r.GetChallenge(ctx, live.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Hints, 1)
+	assert.Equal(t, "elves", got.Hints[0].Text)
+
+	active, err := r.CountActive(ctx, "u1", now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, active)
+
+	t1, t2 := now.Add(-2*time.Minute), now.Add(-time.Minute)
+	require.NoError(t, r.SaveChallengeResult(ctx, &domain.ChallengeResult{ChallengeID: live.ID, UserID: "u2", Username: "bob", Solved: true, Attempts: 3, SolvedAt: &t1}))
+	require.NoError(t, r.SaveChallengeResult(ctx, &domain.ChallengeResult{ChallengeID: live.ID, UserID: "u3", Username: "carol", Solved: true, Attempts: 2, SolvedAt: &t2}))
+	require.NoError(t, r.SaveChallengeResult(ctx, &domain.ChallengeResult{ChallengeID: live.ID, UserID: "u4", Username: "dave", GaveUp: true, Attempts: 5}))
+	require.NoError(t, r.SaveChallengeResult(ctx, &domain.ChallengeResult{ChallengeID: old.ID, UserID: "u2", Username: "bob", Solved: true, Attempts: 1, SolvedAt: &t1}))
+
+	solvers, err := r.ChallengeSolvers(ctx, live.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, solvers, 2)
+	assert.Equal(t, "carol", solvers[0].Username)
+	assert.Equal(t, "bob", solvers[1].Username)
+
+	n, err := r.DeleteExpired(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	_, err = r.GetChallenge(ctx, old.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+	res, err := r.GetChallengeResult(ctx, old.ID, "u2")
+	require.NoError(t, err)
+	assert.Nil(t, res)
+}
@@ -0,0 +1,29 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// FollowRepo reads the player service's follow graph (user_follows, joined to
+// users) from the shared database. Read-only: follows are owned by player.
+type FollowRepo struct{ db *gorm.DB }
+
+func NewFollowRepo(db *gorm.DB) *FollowRepo { return &FollowRepo{db: db} }
+
+// FollowedUsernames returns the usernames of the users followerID follows.
+// Leaderboards are keyed by username, so that is what the friends view
+// filters on.
+func (r *FollowRepo) FollowedUsernames(ctx context.Context, followerID string) ([]string, error) {
+	var names []string
+	err := r.db.WithContext(ctx).Table("user_follows").
+		Joins("JOIN users ON users.id = user_follows.followed_id").
+		Where("user_follows.follower_id = ? AND users.deleted_at IS NULL", followerID).
+		Pluck("users.username", &names).Error
+	if err != nil {
+		return nil, fmt.Errorf("followed usernames: %w", err)
+	}
+	return names, nil
+}
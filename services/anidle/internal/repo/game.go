@@ -45,6 +45,35 @@ func (r *GameRepo) RecentAnswerIDs(ctx context.Context, days int) ([]string, err
 	return ids, nil
 }
 
+func (r *GameRepo) GetModePuzzle(ctx context.Context, mode, date string) (*domain.ModePuzzle, error) {
+	var p domain.ModePuzzle
+	err := r.db.WithContext(ctx).First(&p, "mode = ? AND date = ?", mode, date).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get mode puzzle: %w", err)
+	}
+	return &p, nil
+}
+
+func (r *GameRepo) CreateModePuzzle(ctx context.Context, p *domain.ModePuzzle) error {
+	return r.db.WithContext(ctx).Create(p).Error
+}
+
+// RecentModeSecretIDs returns the secret ids of the most recent `days`
+// puzzles of a mode.
+func (r *GameRepo) RecentModeSecretIDs(ctx context.Context, mode string, days int) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).Model(&domain.ModePuzzle{}).
+		Where("mode = ?", mode).
+		Order("date DESC").Limit(days).Pluck("secret_id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("recent mode secret ids: %w", err)
+	}
+	return ids, nil
+}
+
 func (r *GameRepo) GetUserResult(ctx context.Context, userID, date, mode string) (*domain.UserGameResult, error) {
 	var res domain.UserGameResult
 	err := r.db.WithContext(ctx).First(&res, "user_id = ? AND puzzle_date = ? AND mode = ?", userID, date, mode).Error
@@ -90,3 +119,19 @@ func (r *GameRepo) GetUserStats(ctx context.Context, userID string) (*domain.Use
 func (r *GameRepo) SaveUserStats(ctx context.Context, st *domain.UserStats) error {
 	return r.db.WithContext(ctx).Save(st).Error
 }
+
+func (r *GameRepo) GetUserModeStats(ctx context.Context, userID, mode string) (*domain.UserModeStats, error) {
+	var st domain.UserModeStats
+	err := r.db.WithContext(ctx).First(&st, "user_id = ? AND mode = ?", userID, mode).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get user mode stats: %w", err)
+	}
+	return &st, nil
+}
+
+func (r *GameRepo) SaveUserModeStats(ctx context.Context, st *domain.UserModeStats) error {
+	return r.db.WithContext(ctx).Save(st).Error
+}
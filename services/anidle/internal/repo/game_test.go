@@ -19,10 +19,10 @@ func newTestDB(t *testing.T) *gorm.DB {
 		date TEXT PRIMARY KEY, anime_id TEXT, answer_snapshot TEXT, created_at DATETIME)`).Error)
 	require.NoError(t, db.Exec(`CREATE TABLE anidle_user_game_result (
 		id TEXT PRIMARY KEY, user_id TEXT, puzzle_date TEXT, mode TEXT, solved INTEGER, gave_up INTEGER,
-		attempts INTEGER, guesses TEXT, solved_at DATETIME, created_at DATETIME, updated_at DATETIME)`).Error)
+		attempts INTEGER, guesses TEXT, hints TEXT, hard INTEGER, solved_at DATETIME, created_at DATETIME, updated_at DATETIME)`).Error)
 	require.NoError(t, db.Exec(`CREATE TABLE anidle_user_stats (
 		user_id TEXT PRIMARY KEY, games_played INTEGER, games_won INTEGER, current_streak INTEGER,
-		max_streak INTEGER, guess_distribution TEXT, last_played_date TEXT, updated_at DATETIME)`).Error)
+		max_streak INTEGER, guess_distribution TEXT, hints_used INTEGER, last_played_date TEXT, updated_at DATETIME)`).Error)
 	return db
 }
 
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/repo"
+)
+
+// Challenge limits.
+const (
+	challengeDefaultTTL  = 7 * 24 * time.Hour
+	challengeMaxTTL      = 30 * 24 * time.Hour
+	challengeMaxAttempts = 30
+	challengeMaxHints    = 5
+	challengeMaxHintLen  = 200
+	challengeMaxTitleLen = 120
+	challengeMaxActive   = 20 // per creator
+	challengeBoardSize   = 50
+	challengeListLimit   = 50
+	challengePurgeGrace  = 30 * 24 * time.Hour // expired challenges stay viewable this long
+)
+
+type challengeRepo interface {
+	CreateChallenge(ctx context.Context, c *domain.Challenge) error
+	GetChallenge(ctx context.Context, id string) (*domain.Challenge, error)
+	ListByCreator(ctx context.Context, creatorID string, limit int) ([]domain.Challenge, error)
+	CountActive(ctx context.Context, creatorID string, now time.Time) (int, error)
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+	GetChallengeResult(ctx context.Context, challengeID, userID string) (*domain.ChallengeResult, error)
+	SaveChallengeResult(ctx context.Context, res *domain.ChallengeResult) error
+	ChallengeSolvers(ctx context.Context, challengeID string, limit int) ([]domain.ChallengeResult, error)
+}
+
+// ChallengeService runs user-made challenges: a creator picks the secret from
+// the daily pool, optionally caps the attempts and adds hints; logged-in
+// players solve it until it expires. Solves are ranked per challenge.
+type ChallengeService struct {
+	repo challengeRepo
+	pool poolReader
+	log  *logger.Logger
+}
+
+func NewChallengeService(r challengeRepo, p poolReader, log *logger.Logger) *ChallengeService {
+	return &ChallengeService{repo: r, pool: p, log: log}
+}
+
+// ChallengeInput is the create request.
+type ChallengeInput struct {
+	AnimeID     string                 `json:"anime_id"`
+	Title       string                 `json:"title"`
+	MaxAttempts int                    `json:"max_attempts"` // 0 = unlimited
+	Hints       []domain.ChallengeHint `json:"hints"`
+	TTLHours    int                    `json:"ttl_hours"` // 0 = default (7 days)
+}
+
+// ChallengeState is the public view of a challenge plus the caller's progress:
+// only the hints unlocked so far, and the answer once the game is finished
+// (or to the creator).
+type ChallengeState struct {
+	ID          string                 `json:"id"`
+	Title       string                 `json:"title"`
+	CreatorName string                 `json:"creator_name"`
+	MaxAttempts int                    `json:"max_attempts"`
+	ExpiresAt   time.Time              `json:"expires_at"`
+	Expired     bool                   `json:"expired"`
+	IsCreator   bool                   `json:"is_creator"`
+	Solved      bool                   `json:"solved"`
+	GaveUp      bool                   `json:"gave_up"`
+	Guesses     []GuessOutcome         `json:"guesses"`
+	Hints       []domain.ChallengeHint `json:"hints"`
+	HintsTotal  int                    `json:"hints_total"`
+	Answer      *VisibleAnime          `json:"answer,omitempty"`
+}
+
+// ChallengeGuess is the per-guess response: the scored guess, whether the
+// game is over, and the hints unlocked so far.
+type ChallengeGuess struct {
+	GuessOutcome
+	Finished bool                   `json:"finished"`
+	Hints    []domain.ChallengeHint `json:"hints"`
+}
+
+// Create validates the input and stores a new challenge.
+func (s *ChallengeService) Create(ctx context.Context, creatorID, creatorName string, in ChallengeInput) (*domain.Challenge, error) {
+	secret, ok := s.pool.Lookup(in.AnimeID)
+	if !ok {
+		return nil, apperrors.InvalidInput("unknown anime")
+	}
+	title := strings.TrimSpace(in.Title)
+	if utf8.RuneCountInString(title) > challengeMaxTitleLen {
+		return nil, apperrors.InvalidInput("title is too long")
+	}
+	if in.MaxAttempts < 0 || in.MaxAttempts > challengeMaxAttempts {
+		return nil, apperrors.InvalidInput("max_attempts must be between 0 and 30")
+	}
+	if len(in.Hints) > challengeMaxHints {
+		return nil, apperrors.InvalidInput("at most 5 hints")
+	}
+	hints := make([]domain.ChallengeHint, 0, len(in.Hints))
+	for _, h := range in.Hints {
+		text := strings.TrimSpace(h.Text)
+		switch {
+		case text == "":
+			return nil, apperrors.InvalidInput("hint text is required")
+		case utf8.RuneCountInString(text) > challengeMaxHintLen:
+			return nil, apperrors.InvalidInput("hint is too long")
+		case h.AfterAttempts < 0 || (in.MaxAttempts > 0 && h.AfterAttempts >= in.MaxAttempts):
+			return nil, apperrors.InvalidInput("hint after_attempts is out of range")
+		}
+		hints = append(hints, domain.ChallengeHint{AfterAttempts: h.AfterAttempts, Text: text})
+	}
+	ttl := challengeDefaultTTL
+	if in.TTLHours != 0 {
+		ttl = time.Duration(in.TTLHours) * time.Hour
+		if ttl < time.Hour || ttl > challengeMaxTTL {
+			return nil, apperrors.InvalidInput("ttl_hours must be between 1 and 720")
+		}
+	}
+
+	now := timeNow()
+	active, err := s.repo.CountActive(ctx, creatorID, now)
+	if err != nil {
+		return nil, err
+	}
+	if active >= challengeMaxActive {
+		return nil, apperrors.InvalidInput("too many active challenges")
+	}
+
+	c := &domain.Challenge{
+		CreatorID:      creatorID,
+		CreatorName:    creatorName,
+		Title:          title,
+		AnimeID:        secret.ID,
+		AnswerSnapshot: secret,
+		MaxAttempts:    in.MaxAttempts,
+		Hints:          hints,
+		ExpiresAt:      now.Add(ttl),
+		CreatedAt:      now,
+	}
+	if err := s.repo.CreateChallenge(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ListMine returns the caller's challenges, newest first.
+func (s *ChallengeService) ListMine(ctx context.Context, creatorID string) ([]domain.Challenge, error) {
+	return s.repo.ListByCreator(ctx, creatorID, challengeListLimit)
+}
+
+func (s *ChallengeService) get(ctx context.Context, id string) (*domain.Challenge, error) {
+	c, err := s.repo.GetChallenge(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, apperrors.NotFound("challenge")
+	}
+	return c, err
+}
+
+// playable loads a challenge a logged-in player may still guess on.
+func (s *ChallengeService) playable(ctx context.Context, id, userID string) (*domain.Challenge, error) {
+	if userID == "" {
+		return nil, apperrors.Unauthorized("log in to play challenges")
+	}
+	c, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.Expired(timeNow()) {
+		return nil, apperrors.InvalidInput("challenge has expired")
+	}
+	if c.CreatorID == userID {
+		return nil, apperrors.Forbidden("cannot play your own challenge")
+	}
+	return c, nil
+}
+
+// Resume returns the challenge and the caller's progress. userID == "" is a
+// guest: they see the challenge but must log in to play.
+func (s *ChallengeService) Resume(ctx context.Context, id, userID string) (*ChallengeState, error) {
+	c, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	state := &ChallengeState{
+		ID: c.ID, Title: c.Title, CreatorName: c.CreatorName, MaxAttempts: c.MaxAttempts,
+		ExpiresAt: c.ExpiresAt, Expired: c.Expired(timeNow()),
+		IsCreator: userID != "" && userID == c.CreatorID,
+		Guesses:   []GuessOutcome{}, HintsTotal: len(c.Hints),
+	}
+	if state.IsCreator {
+		state.Hints = c.Hints
+		a := visible(c.AnswerSnapshot)
+		state.Answer = &a
+		return state, nil
+	}
+	var res *domain.ChallengeResult
+	if userID != "" {
+		if res, err = s.repo.GetChallengeResult(ctx, c.ID, userID); err != nil {
+			return nil, err
+		}
+	}
+	if res == nil {
+		state.Hints = unlockedHints(c, 0, false)
+		return state, nil
+	}
+	state.Solved, state.GaveUp = res.Solved, res.GaveUp
+	for _, gid := range res.Guesses {
+		g, ok := s.pool.Lookup(gid)
+		if !ok {
+			continue
+		}
+		state.Guesses = append(state.Guesses, GuessOutcome{
+			Anime:  visible(g),
+			Result: Compare(c.AnswerSnapshot, g),
+			Solved: gid == c.AnimeID,
+		})
+	}
+	finished := res.Solved || res.GaveUp
+	state.Hints = unlockedHints(c, len(res.Guesses), finished)
+	if finished {
+		a := visible(c.AnswerSnapshot)
+		state.Answer = &a
+	}
+	return state, nil
+}
+
+// Guess scores one guess of a logged-in player. Running out of attempts
+// finishes the game as lost.
+func (s *ChallengeService) Guess(ctx context.Context, id, userID, username, animeID string) (*ChallengeGuess, error) {
+	c, err := s.playable(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	guess, ok := s.pool.Lookup(animeID)
+	if !ok {
+		return nil, apperrors.InvalidInput("unknown anime")
+	}
+	res, err := s.result(ctx, c.ID, userID, username)
+	if err != nil {
+		return nil, err
+	}
+	solved := animeID == c.AnimeID
+	out := &ChallengeGuess{GuessOutcome: GuessOutcome{
+		Anime:  visible(guess),
+		Result: Compare(c.AnswerSnapshot, guess),
+		Solved: solved,
+	}}
+	if !res.Solved && !res.GaveUp { // ignore guesses once the game is finished
+		res.Guesses = append(res.Guesses, animeID)
+		res.Attempts = len(res.Guesses)
+		if solved {
+			res.Solved = true
+			now := timeNow()
+			res.SolvedAt = &now
+			out.FreshSolve = true
+		} else if c.MaxAttempts > 0 && res.Attempts >= c.MaxAttempts {
+			res.GaveUp = true
+		}
+		if err := s.repo.SaveChallengeResult(ctx, res); err != nil {
+			return nil, err
+		}
+	}
+	out.Attempt = res.Attempts
+	out.Finished = res.Solved || res.GaveUp
+	out.Hints = unlockedHints(c, res.Attempts, out.Finished)
+	if out.Finished {
+		a := visible(c.AnswerSnapshot)
+		out.Answer = &a
+	}
+	return out, nil
+}
+
+// GiveUp finishes the caller's game as lost and reveals the answer.
+func (s *ChallengeService) GiveUp(ctx context.Context, id, userID, username string) (*VisibleAnime, error) {
+	c, err := s.playable(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.result(ctx, c.ID, userID, username)
+	if err != nil {
+		return nil, err
+	}
+	if !res.Solved && !res.GaveUp {
+		res.GaveUp = true
+		res.Attempts = len(res.Guesses)
+		if err := s.repo.SaveChallengeResult(ctx, res); err != nil {
+			return nil, err
+		}
+	}
+	a := visible(c.AnswerSnapshot)
+	return &a, nil
+}
+
+// Leaderboard ranks the solvers of a challenge: fewest attempts, then
+// earliest solve. Expired challenges keep their board until purged.
+func (s *ChallengeService) Leaderboard(ctx context.Context, id string) ([]LeaderEntry, error) {
+	c, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.ChallengeSolvers(ctx, c.ID, challengeBoardSize)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]LeaderEntry, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, LeaderEntry{Username: r.Username, Attempts: r.Attempts})
+	}
+	return out, nil
+}
+
+// Purge deletes challenges (and their results) that expired more than the
+// grace period ago.
+func (s *ChallengeService) Purge(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx, timeNow().Add(-challengePurgeGrace))
+}
+
+func (s *ChallengeService) result(ctx context.Context, challengeID, userID, username string) (*domain.ChallengeResult, error) {
+	res, err := s.repo.GetChallengeResult(ctx, challengeID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		res = &domain.ChallengeResult{ChallengeID: challengeID, UserID: userID}
+	}
+	res.Username = username
+	return res, nil
+}
+
+// unlockedHints returns the hints unlocked after `attempts` guesses; a
+// finished game shows them all.
+func unlockedHints(c *domain.Challenge, attempts int, finished bool) []domain.ChallengeHint {
+	out := []domain.ChallengeHint{}
+	for _, h := range c.Hints {
+		if finished || h.AfterAttempts <= attempts {
+			out = append(out, h)
+		}
+	}
+	return out
+}
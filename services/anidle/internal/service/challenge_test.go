@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/repo"
+)
+
+type fakeChallengeRepo struct {
+	challenges map[string]*domain.Challenge
+	results    map[string]*domain.ChallengeResult
+	nextID     int
+}
+
+func newFakeChallengeRepo() *fakeChallengeRepo {
+	return &fakeChallengeRepo{challenges: map[string]*domain.Challenge{}, results: map[string]*domain.ChallengeResult{}}
+}
+
+func (f *fakeChallengeRepo) CreateChallenge(_ context.Context, c *domain.Challenge) error {
+	f.nextID++
+	c.ID = fmt.Sprintf("00000000-0000-0000-0000-%012d", f.nextID)
+	cp := *c
+	f.challenges[c.ID] = &cp
+	return nil
+}
+func (f *fakeChallengeRepo) GetChallenge(_ context.Context, id string) (*domain.Challenge, error) {
+	c, ok := f.challenges[id]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+	cp := *c
+	return &cp, nil
+}
+func (f *fakeChallengeRepo) ListByCreator(_ context.Context, creatorID string, _ int) ([]domain.Challenge, error) {
+	var out []domain.Challenge
+	for _, c := range f.challenges {
+		if c.CreatorID == creatorID {
+			out = append(out, *c)
+		}
+	}
+	return out, nil
+}
+func (f *fakeChallengeRepo) CountActive(_ context.Context, creatorID string, now time.Time) (int, error) {
+	n := 0
+	for _, c := range f.challenges {
+		if c.CreatorID == creatorID && c.ExpiresAt.After(now) {
+			n++
+		}
+	}
+	return n, nil
+}
+func (f *fakeChallengeRepo) DeleteExpired(_ context.Context, before time.Time) (int64, error) {
+	var n int64
+	for id, c := range f.challenges {
+		if c.ExpiresAt.Before(before) {
+			delete(f.challenges, id)
+			n++
+		}
+	}
+	return n, nil
+}
+func (f *fakeChallengeRepo) GetChallengeResult(_ context.Context, challengeID, userID string) (*domain.ChallengeResult, error) {
+	r, ok := f.results[challengeID+"|"+userID]
+	if !ok {
+		return nil, nil
+	}
+	cp := *r
+	return &cp, nil
+}
+func (f *fakeChallengeRepo) SaveChallengeResult(_ context.Context, res *domain.ChallengeResult) error {
+	cp := *res
+	f.results[res.ChallengeID+"|"+res.UserID] = &cp
+	return nil
+}
+func (f *fakeChallengeRepo) ChallengeSolvers(_ context.Context, challengeID string, limit int) ([]domain.ChallengeResult, error) {
+	var out []domain.ChallengeResult
+	for _, r := range f.results {
+		if r.ChallengeID == challengeID && r.Solved {
+			out = append(out, *r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Attempts != out[j].Attempts {
+			return out[i].Attempts < out[j].Attempts
+		}
+		return out[i].SolvedAt.Before(*out[j].SolvedAt)
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func newChallengeFixture(t *testing.T) (*ChallengeService, *fakeChallengeRepo, *time.Time) {
+	t.Helper()
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	prev := timeNow
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = prev })
+	pool := &fakePool{pool: []domain.PoolAnime{
+		{ID: "frieren", NameRU: "Фрирен", Year: 2023},
+		{ID: "bocchi", NameRU: "Бочча", Year: 2022},
+		{ID: "mob", NameRU: "Моб", Year: 2016},
+	}}
+	r := newFakeChallengeRepo()
+	return NewChallengeService(r, pool, nil), r, &now
+}
+
+func TestChallenge_CreateValidates(t *testing.T) {
+	svc, _, _ := newChallengeFixture(t)
+	ctx := context.Background()
+
+	_, err := svc.Create(ctx, "u1", "alice", ChallengeInput{AnimeID: "nope"})
+	require.Error(t, err)
+
+	_, err = svc.Create(ctx, "u1", "alice", ChallengeInput{AnimeID: "frieren", MaxAttempts: 3,
+		Hints: []domain.ChallengeHint{{AfterAttempts: 3, Text: "elves"}}})
+	appErr, ok := apperrors.IsAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, apperrors.CodeInvalidInput, appErr.Code) // hint never reachable
+
+	c, err := svc.Create(ctx, "u1", "alice", ChallengeInput{AnimeID: "frieren", Title: "  easy one  "})
+	require.NoError(t, err)
+	assert.Equal(t, "easy one", c.Title)
+	assert.Equal(t, time.Date(2026, 6, 22, 12, 0, 0, 0, time.UTC), c.ExpiresAt) // default 7 days
+}
+
+func TestChallenge_HintsUnlockAndAttemptsCap(t *testing.T) {
+	svc, _, _ := newChallengeFixture(t)
+	ctx := context.Background()
+	c, err := svc.Create(ctx, "u1", "alice", ChallengeInput{AnimeID: "frieren", MaxAttempts: 2,
+		Hints: []domain.ChallengeHint{{AfterAttempts: 0, Text: "fantasy"}, {AfterAttempts: 1, Text: "elves"}}})
+	require.NoError(t, err)
+
+	st, err := svc.Resume(ctx, c.ID, "u2")
+	require.NoError(t, err)
+	require.Len(t, st.Hints, 1)
+	assert.Nil(t, st.Answer)
+
+	out, err := svc.Guess(ctx, c.ID, "u2", "bob", "bocchi")
+	require.NoError(t, err)
+	assert.False(t, out.Finished)
+	assert.Len(t, out.Hints, 2)
+
+	out, err = svc.Guess(ctx, c.ID, "u2", "bob", "mob")
+	require.NoError(t, err)
+	assert.True(t, out.Finished) // out of attempts
+	require.NotNil(t, out.Answer)
+	assert.Equal(t, "frieren", out.Answer.ID)
+
+	// the creator cannot play their own challenge
+	_, err = svc.Guess(ctx, c.ID, "u1", "alice", "frieren")
+	require.Error(t, err)
+}
+
+func TestChallenge_LeaderboardAndExpiry(t *testing.T) {
+	svc, _, now := newChallengeFixture(t)
+	ctx := context.Background()
+	c, err := svc.Create(ctx, "u1", "alice", ChallengeInput{AnimeID: "frieren", TTLHours: 24})
+	require.NoError(t, err)
+
+	_, err = svc.Guess(ctx, c.ID, "u2", "bob", "mob")
+	require.NoError(t, err)
+	out, err := svc.Guess(ctx, c.ID, "u2", "bob", "frieren")
+	require.NoError(t, err)
+	assert.True(t, out.FreshSolve)
+	_, err = svc.Guess(ctx, c.ID, "u3", "carol", "frieren")
+	require.NoError(t, err)
+	_, err = svc.GiveUp(ctx, c.ID, "u4", "dave")
+	require.NoError(t, err)
+
+	top, err := svc.Leaderboard(ctx, c.ID)
+	require.NoError(t, err)
+	require.Len(t, top, 2)
+	assert.Equal(t, "carol", top[0].Username)
+	assert.Equal(t, "bob", top[1].Username)
+	assert.Equal(t, 2, top[1].Attempts)
+
+	*now = now.Add(25 * time.Hour)
+	_, err = svc.Guess(ctx, c.ID, "u5", "erin", "frieren")
+	require.Error(t, err)
+	st, err := svc.Resume(ctx, c.ID, "")
+	require.NoError(t, err)
+	assert.True(t, st.Expired)
+
+	*now = now.Add(31 * 24 * time.Hour)
+	n, err := svc.Purge(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/domain"
+)
+
+// CharacterGuess / CharacterState are the character mode's guess and resume
+// payloads.
+type (
+	CharacterGuess = ModeGuess[domain.PoolCharacter, domain.PoolCharacter, domain.CharacterComparison]
+	CharacterState = ModeState[domain.PoolCharacter, domain.PoolCharacter, domain.CharacterComparison]
+)
+
+type characterPool interface {
+	All(ctx context.Context) ([]domain.PoolCharacter, error)
+	Lookup(id string) (domain.PoolCharacter, bool)
+	Search(ctx context.Context, q string, limit int) []domain.PoolCharacter
+}
+
+// CharacterService is the character mode: guess the day's character by
+// gender, role, franchise, first-appearance year and voice actor.
+type CharacterService struct {
+	game *modeGame[domain.PoolCharacter, domain.PoolCharacter, domain.CharacterComparison]
+	pool characterPool
+}
+
+func NewCharacterService(r modePuzzleRepo, p characterPool, clock Clock, rs resultStore, stats modeStatsUpdater, log *logger.Logger) *CharacterService {
+	if clock == nil {
+		clock = realClock{}
+	}
+	return &CharacterService{
+		pool: p,
+		game: &modeGame[domain.PoolCharacter, domain.PoolCharacter, domain.CharacterComparison]{
+			mode: domain.ModeCharacter, puzzles: r, clock: clock, rs: rs, stats: stats, log: log,
+			secrets:  p.All,
+			secretID: func(c domain.PoolCharacter) string { return c.ID },
+			lookup: func(ctx context.Context, id string) (domain.PoolCharacter, bool) {
+				if _, err := p.All(ctx); err != nil {
+					return domain.PoolCharacter{}, false
+				}
+				return p.Lookup(id)
+			},
+			score: func(secret, guess domain.PoolCharacter) (domain.CharacterComparison, bool) {
+				return CompareCharacter(secret, guess), secret.ID == guess.ID
+			},
+		},
+	}
+}
+
+func (s *CharacterService) Guess(ctx context.Context, userID, characterID string) (*CharacterGuess, error) {
+	return s.game.Guess(ctx, userID, characterID)
+}
+
+func (s *CharacterService) GiveUp(ctx context.Context, userID string) (*domain.PoolCharacter, error) {
+	return s.game.GiveUp(ctx, userID)
+}
+
+func (s *CharacterService) Resume(ctx context.Context, userID string) (*CharacterState, error) {
+	return s.game.Resume(ctx, userID)
+}
+
+func (s *CharacterService) Search(ctx context.Context, q string, limit int) []domain.PoolCharacter {
+	return s.pool.Search(ctx, q, limit)
+}
+
+// CompareCharacter scores a character guess against the secret. Pure
+// function. An unknown gender compares as its own value.
+func CompareCharacter(secret, guess domain.PoolCharacter) domain.CharacterComparison {
+	return domain.CharacterComparison{
+		Gender:     compareEnum(secret.Gender, guess.Gender),
+		Role:       compareEnum(secret.Role, guess.Role),
+		Franchise:  compareEnum(secret.Franchise, guess.Franchise),
+		Year:       compareInt(secret.Year, guess.Year),
+		VoiceActor: compareSet(taxonIDs(secret.VoiceActors), taxonIDs(guess.VoiceActors)),
+	}
+}
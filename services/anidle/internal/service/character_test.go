@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/domain"
+)
+
+// fakeModeRepo implements modePuzzleRepo.
+type fakeModeRepo struct {
+	puzzles map[string]*domain.ModePuzzle
+	recent  []string
+}
+
+func newFakeModeRepo() *fakeModeRepo { return &fakeModeRepo{puzzles: map[string]*domain.ModePuzzle{}} }
+
+func (f *fakeModeRepo) GetModePuzzle(_ context.Context, mode, date string) (*domain.ModePuzzle, error) {
+	p, ok := f.puzzles[mode+"|"+date]
+	if !ok {
+		return nil, errRepoNotFound
+	}
+	return p, nil
+}
+func (f *fakeModeRepo) CreateModePuzzle(_ context.Context, p *domain.ModePuzzle) error {
+	f.puzzles[p.Mode+"|"+p.Date] = p
+	return nil
+}
+func (f *fakeModeRepo) RecentModeSecretIDs(_ context.Context, _ string, _ int) ([]string, error) {
+	return f.recent, nil
+}
+
+// fakeModeStats implements modeStatsUpdater.
+type fakeModeStats struct{ solves, losses []string }
+
+func (f *fakeModeStats) RecordModeResult(_ context.Context, mode, userID, _ string, won bool, _ int) error {
+	if won {
+		f.solves = append(f.solves, mode+"|"+userID)
+	} else {
+		f.losses = append(f.losses, mode+"|"+userID)
+	}
+	return nil
+}
+
+// fakeCharacterPool implements characterPool.
+type fakeCharacterPool struct{ pool []domain.PoolCharacter }
+
+func (f *fakeCharacterPool) All(_ context.Context) ([]domain.PoolCharacter, error) {
+	return f.pool, nil
+}
+func (f *fakeCharacterPool) Lookup(id string) (domain.PoolCharacter, bool) {
+	for _, c := range f.pool {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return domain.PoolCharacter{}, false
+}
+func (f *fakeCharacterPool) Search(_ context.Context, _ string, _ int) []domain.PoolCharacter {
+	return nil
+}
+
+func sampleCharacters() []domain.PoolCharacter {
+	return []domain.PoolCharacter{
+		{ID: "frieren", Gender: "female", Role: "main", Franchise: "frieren", Year: 2023, VoiceActors: tx("tanezaki")},
+		{ID: "fern", Gender: "female", Role: "main", Franchise: "frieren", Year: 2023, VoiceActors: tx("ichinose")},
+		{ID: "gojo", Gender: "male", Role: "supporting", Franchise: "jujutsu_kaisen", Year: 2020, VoiceActors: tx("nakamura")},
+	}
+}
+
+func TestCompareCharacter(t *testing.T) {
+	c := sampleCharacters()
+	got := CompareCharacter(c[0], c[2])
+	assert.Equal(t, domain.MatchWrong, got.Gender.Status)
+	assert.Equal(t, domain.MatchWrong, got.Role.Status)
+	assert.Equal(t, domain.MatchWrong, got.Franchise.Status)
+	assert.Equal(t, domain.HintHigher, got.Year.Hint)
+	assert.Equal(t, domain.MatchWrong, got.VoiceActor.Status)
+
+	got = CompareCharacter(c[0], c[1])
+	assert.Equal(t, domain.MatchCorrect, got.Gender.Status)
+	assert.Equal(t, domain.MatchCorrect, got.Franchise.Status)
+	assert.Equal(t, domain.MatchCorrect, got.Year.Status)
+	assert.Equal(t, domain.MatchWrong, got.VoiceActor.Status)
+}
+
+func TestCharacterMode_OwnSecretResultsAndStats(t *testing.T) {
+	repo := newFakeModeRepo()
+	rs := newFakeResultStore()
+	st := &fakeModeStats{}
+	svc := NewCharacterService(repo, &fakeCharacterPool{pool: sampleCharacters()}, fixedClock{"2026-06-15"}, rs, st, nil)
+	ctx := context.Background()
+
+	state, err := svc.Resume(ctx, "u1")
+	require.NoError(t, err)
+	assert.Nil(t, state.Answer)
+	p := repo.puzzles[domain.ModeCharacter+"|2026-06-15"]
+	require.NotNil(t, p, "the mode stores its own daily secret")
+	secretID := p.SecretID
+	wrongID := "gojo"
+	if secretID == "gojo" {
+		wrongID = "fern"
+	}
+
+	out, err := svc.Guess(ctx, "u1", wrongID)
+	require.NoError(t, err)
+	assert.False(t, out.Solved)
+	assert.Nil(t, out.Answer)
+
+	out, err = svc.Guess(ctx, "u1", secretID)
+	require.NoError(t, err)
+	assert.True(t, out.Solved)
+	assert.True(t, out.FreshSolve)
+	assert.Equal(t, 2, out.Attempt)
+	require.NotNil(t, out.Answer)
+	assert.Equal(t, secretID, out.Answer.ID)
+
+	res := rs.results[key("u1", "2026-06-15", domain.ModeCharacter)]
+	require.NotNil(t, res)
+	assert.Equal(t, []string{wrongID, secretID}, res.Guesses)
+	assert.Nil(t, rs.results[key("u1", "2026-06-15", domain.ModeDaily)], "classic result untouched")
+	assert.Equal(t, []string{"character|u1"}, st.solves)
+
+	// re-submitting after the solve is not a fresh solve
+	out, err = svc.Guess(ctx, "u1", secretID)
+	require.NoError(t, err)
+	assert.False(t, out.FreshSolve)
+	assert.Len(t, st.solves, 1)
+}
+
+func TestCharacterMode_SnapshotSurvivesPoolChange(t *testing.T) {
+	repo := newFakeModeRepo()
+	pool := &fakeCharacterPool{pool: sampleCharacters()}
+	svc := NewCharacterService(repo, pool, fixedClock{"2026-06-15"}, nil, nil, nil)
+	ctx := context.Background()
+
+	before, err := svc.GiveUp(ctx, "")
+	require.NoError(t, err)
+	pool.pool = pool.pool[:0] // the pool refreshes empty
+	after, err := svc.GiveUp(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, before.ID, after.ID)
+}
@@ -6,6 +6,7 @@ import (
 	"hash/fnv"
 	"time"
 
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
 	"github.com/ILITA-hub/animeenigma/libs/logger"
 	"github.com/ILITA-hub/animeenigma/services/anidle/internal/domain"
 	"github.com/ILITA-hub/animeenigma/services/anidle/internal/repo"
@@ -31,6 +32,7 @@ type resultStore interface {
 
 type statsUpdater interface {
 	RecordDailyResult(ctx context.Context, userID, date string, won bool, attempts int) error
+	RecordHintUse(ctx context.Context, userID string) error
 }
 
 // DailyService is used by Guess/Resume (Task 3); declared here, used there.
@@ -135,6 +137,8 @@ type GuessOutcome struct {
 	Solved  bool                   `json:"solved"`
 	Attempt int                    `json:"attempt"`
 	Answer  *VisibleAnime          `json:"answer,omitempty"`
+	// HintsUsed is the number of hints the player used today (classic mode).
+	HintsUsed int `json:"hints_used,omitempty"`
 	// FreshSolve is true only on the guess that first solves the day — server
 	// internal (not serialized). The handler gates the one-time leaderboard
 	// write on it so re-submitting the correct answer can't worsen the rank.
@@ -146,7 +150,9 @@ type DailyState struct {
 	Date    string         `json:"date"`
 	Solved  bool           `json:"solved"`
 	GaveUp  bool           `json:"gave_up"`
+	Hard    bool           `json:"hard"`
 	Guesses []GuessOutcome `json:"guesses"`
+	Hints   []HintOffer    `json:"hints"`
 	Answer  *VisibleAnime  `json:"answer,omitempty"`
 }
 
@@ -159,7 +165,10 @@ func visible(a domain.PoolAnime) VisibleAnime {
 }
 
 // Guess scores one guess. userID == "" means an anonymous guest (no persistence).
-func (s *DailyService) Guess(ctx context.Context, userID, animeID string) (*GuessOutcome, error) {
+// hard asks for hard mode: no numeric arrows and, for a logged-in player, every
+// guess must be consistent with the feedback so far. It is fixed by the first
+// guess of the day and ignored afterwards.
+func (s *DailyService) Guess(ctx context.Context, userID, animeID string, hard bool) (*GuessOutcome, error) {
 	puzzle, err := s.GetOrCreateToday(ctx)
 	if err != nil {
 		return nil, err
@@ -179,6 +188,9 @@ func (s *DailyService) Guess(ctx context.Context, userID, animeID string) (*Gues
 
 	if userID == "" || s.rs == nil { // guest path: compare only
 		out.Attempt = 0
+		if hard {
+			out.Result = HideArrows(out.Result)
+		}
 		if solved {
 			a := visible(secret)
 			out.Answer = &a
@@ -193,7 +205,18 @@ func (s *DailyService) Guess(ctx context.Context, userID, animeID string) (*Gues
 	if res == nil {
 		res = &domain.UserGameResult{UserID: userID, PuzzleDate: puzzle.Date, Mode: modeDaily}
 	}
+	if len(res.Guesses) == 0 && !res.Solved && !res.GaveUp {
+		res.Hard = hard
+	}
+	if res.Hard {
+		out.Result = HideArrows(out.Result)
+	}
 	if !res.Solved && !res.GaveUp { // ignore guesses once the game is finished
+		if res.Hard {
+			if err := s.checkConsistent(secret, res.Guesses, guess); err != nil {
+				return nil, err
+			}
+		}
 		res.Guesses = append(res.Guesses, animeID)
 		res.Attempts = len(res.Guesses)
 		if solved {
@@ -212,6 +235,7 @@ func (s *DailyService) Guess(ctx context.Context, userID, animeID string) (*Gues
 		}
 	}
 	out.Attempt = res.Attempts
+	out.HintsUsed = len(res.Hints)
 	if res.Solved {
 		a := visible(secret)
 		out.Answer = &a
@@ -219,6 +243,22 @@ func (s *DailyService) Guess(ctx context.Context, userID, animeID string) (*Gues
 	return out, nil
 }
 
+// checkConsistent rejects a hard-mode guess that contradicts the feedback of
+// an earlier guess.
+func (s *DailyService) checkConsistent(secret domain.PoolAnime, earlier []string, guess domain.PoolAnime) error {
+	for _, gid := range earlier {
+		prev, ok := s.pool.Lookup(gid)
+		if !ok {
+			continue
+		}
+		if !Consistent(secret, prev, guess) {
+			return apperrors.InvalidInput("hard mode: guess contradicts the feedback of an earlier guess").
+				WithDetail("earlier_anime_id", gid)
+		}
+	}
+	return nil
+}
+
 // GiveUp marks the day lost for a logged-in user and reveals the answer.
 func (s *DailyService) GiveUp(ctx context.Context, userID string) (*VisibleAnime, error) {
 	puzzle, err := s.GetOrCreateToday(ctx)
@@ -254,7 +294,7 @@ func (s *DailyService) Resume(ctx context.Context, userID string) (*DailyState,
 	if err != nil {
 		return nil, err
 	}
-	state := &DailyState{Date: puzzle.Date, Guesses: []GuessOutcome{}}
+	state := &DailyState{Date: puzzle.Date, Guesses: []GuessOutcome{}, Hints: []HintOffer{}}
 	if userID == "" || s.rs == nil {
 		return state, nil
 	}
@@ -263,21 +303,28 @@ func (s *DailyService) Resume(ctx context.Context, userID string) (*DailyState,
 		return nil, err
 	}
 	if res == nil {
+		state.Hints = hintOffers(puzzle, nil)
 		return state, nil
 	}
 	state.Solved = res.Solved
 	state.GaveUp = res.GaveUp
+	state.Hard = res.Hard
 	for _, gid := range res.Guesses {
 		g, ok := s.pool.Lookup(gid)
 		if !ok {
 			continue
 		}
+		result := Compare(puzzle.AnswerSnapshot, g)
+		if res.Hard {
+			result = HideArrows(result)
+		}
 		state.Guesses = append(state.Guesses, GuessOutcome{
 			Anime:  visible(g),
-			Result: Compare(puzzle.AnswerSnapshot, g),
+			Result: result,
 			Solved: gid == puzzle.AnimeID,
 		})
 	}
+	state.Hints = hintOffers(puzzle, res)
 	if res.Solved || res.GaveUp { // reveal the answer once the game is finished
 		a := visible(puzzle.AnswerSnapshot)
 		state.Answer = &a
@@ -107,6 +107,7 @@ func (f *fakeResultStore) SaveUserResult(_ context.Context, r *domain.UserGameRe
 type fakeStats struct {
 	solves []string
 	losses int
+	hints  int
 }
 
 func (f *fakeStats) RecordDailyResult(_ context.Context, userID, date string, won bool, attempts int) error {
@@ -118,6 +119,11 @@ func (f *fakeStats) RecordDailyResult(_ context.Context, userID, date string, wo
 	return nil
 }
 
+func (f *fakeStats) RecordHintUse(_ context.Context, _ string) error {
+	f.hints++
+	return nil
+}
+
 func dailySvcWithStores(date string) (*DailyService, *fakeResultStore, *fakeStats) {
 	rs := newFakeResultStore()
 	st := &fakeStats{}
@@ -137,14 +143,14 @@ func TestDaily_Guess_WrongThenRight_PersistsForLoggedIn(t *testing.T) {
 	}
 
 	// wrong guess
-	out, err := svc.Guess(ctx, "u1", wrongID)
+	out, err := svc.Guess(ctx, "u1", wrongID, false)
 	require.NoError(t, err)
 	assert.False(t, out.Solved)
 	assert.Nil(t, out.Answer)
 	assert.Equal(t, 1, out.Attempt)
 
 	// correct guess
-	out, err = svc.Guess(ctx, "u1", secretID)
+	out, err = svc.Guess(ctx, "u1", secretID, false)
 	require.NoError(t, err)
 	assert.True(t, out.Solved)
 	require.NotNil(t, out.Answer)
@@ -162,7 +168,7 @@ func TestDaily_Guess_Guest_NoPersistButCompares(t *testing.T) {
 	svc, rs, _ := dailySvcWithStores("2026-06-15")
 	ctx := context.Background()
 	p, _ := svc.GetOrCreateToday(ctx)
-	out, err := svc.Guess(ctx, "", p.AnimeID) // empty userID = guest
+	out, err := svc.Guess(ctx, "", p.AnimeID, false) // empty userID = guest
 	require.NoError(t, err)
 	assert.True(t, out.Solved)
 	assert.Empty(t, rs.results) // nothing persisted for guests
@@ -171,7 +177,7 @@ func TestDaily_Guess_Guest_NoPersistButCompares(t *testing.T) {
 func TestDaily_Guess_UnknownAnime_Errors(t *testing.T) {
 	svc, _, _ := dailySvcWithStores("2026-06-15")
 	_, _ = svc.GetOrCreateToday(context.Background())
-	_, err := svc.Guess(context.Background(), "u1", "does-not-exist")
+	_, err := svc.Guess(context.Background(), "u1", "does-not-exist", false)
 	require.Error(t, err)
 }
 
@@ -179,8 +185,8 @@ func TestDaily_Resume_ReplaysStoredGuesses(t *testing.T) {
 	svc, rs, _ := dailySvcWithStores("2026-06-15")
 	ctx := context.Background()
 	p, _ := svc.GetOrCreateToday(ctx)
-	_, _ = svc.Guess(ctx, "u1", "a")
-	_, _ = svc.Guess(ctx, "u1", "b")
+	_, _ = svc.Guess(ctx, "u1", "a", false)
+	_, _ = svc.Guess(ctx, "u1", "b", false)
 
 	state, err := svc.Resume(ctx, "u1")
 	require.NoError(t, err)
@@ -199,12 +205,12 @@ func TestDaily_ResubmitAfterSolve_NoFreshSolve(t *testing.T) {
 	ctx := context.Background()
 	p, _ := svc.GetOrCreateToday(ctx)
 
-	first, err := svc.Guess(ctx, "u1", p.AnimeID)
+	first, err := svc.Guess(ctx, "u1", p.AnimeID, false)
 	require.NoError(t, err)
 	assert.True(t, first.Solved)
 	assert.True(t, first.FreshSolve, "first solving guess must be FreshSolve")
 
-	again, err := svc.Guess(ctx, "u1", p.AnimeID)
+	again, err := svc.Guess(ctx, "u1", p.AnimeID, false)
 	require.NoError(t, err)
 	assert.True(t, again.Solved)
 	assert.False(t, again.FreshSolve, "re-submitting the correct answer must NOT be a fresh solve")
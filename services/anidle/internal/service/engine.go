@@ -73,3 +73,19 @@ func compareEnum(secret, guess string) domain.ColumnResult {
 	}
 	return domain.ColumnResult{Status: domain.MatchWrong}
 }
+
+// HideArrows strips the numeric higher/lower arrows of compareInt and
+// compareFloat — hard mode shows only whether year/episodes/score match.
+func HideArrows(c domain.GuessComparison) domain.GuessComparison {
+	c.Year.Hint = domain.HintNone
+	c.Episodes.Hint = domain.HintNone
+	c.Score.Hint = domain.HintNone
+	return c
+}
+
+// Consistent reports whether candidate could still be the secret given the
+// hard-mode feedback an earlier guess received: scoring that guess against
+// candidate must reproduce the same verdict in every column.
+func Consistent(secret, earlier, candidate domain.PoolAnime) bool {
+	return HideArrows(Compare(candidate, earlier)) == HideArrows(Compare(secret, earlier))
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png" // posters are JPEG or PNG
+	"io"
+	"unicode"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/domain"
+)
+
+// posterHintURL is where the pixelated poster of a used poster hint is served.
+const posterHintURL = "/api/anidle/daily/hint/poster"
+
+// posterBlocks is the pixelation grid of the poster hint (width in blocks).
+const posterBlocks = 12
+
+// HintOffer is one hint of the day as the player sees it: when it unlocks,
+// whether it is available for today's secret, and its value once used.
+type HintOffer struct {
+	Kind        domain.HintKind `json:"kind"`
+	UnlockAfter int             `json:"unlock_after"`
+	Unlocked    bool            `json:"unlocked"`
+	Available   bool            `json:"available"`
+	Reveal      *HintReveal     `json:"reveal,omitempty"`
+}
+
+// HintReveal is the value of a used hint. Text is the studio/tag name or the
+// first letter of the Russian title (TextEN: of the English one); URL points
+// to the pixelated poster.
+type HintReveal struct {
+	Kind   domain.HintKind `json:"kind"`
+	Text   string          `json:"text,omitempty"`
+	TextEN string          `json:"text_en,omitempty"`
+	URL    string          `json:"url,omitempty"`
+}
+
+// UseHint records a hint on the caller's game and reveals it. Using a hint
+// again returns the same reveal without counting it twice.
+func (s *DailyService) UseHint(ctx context.Context, userID string, kind domain.HintKind) (*HintReveal, error) {
+	unlockAfter, known := domain.HintUnlockAfter[kind]
+	if !known {
+		return nil, apperrors.InvalidInput("unknown hint")
+	}
+	if userID == "" || s.rs == nil {
+		return nil, apperrors.Unauthorized("log in to use hints")
+	}
+	puzzle, err := s.GetOrCreateToday(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reveal, ok := revealHint(puzzle, kind)
+	if !ok {
+		return nil, apperrors.InvalidInput("hint is not available today")
+	}
+	res, err := s.rs.GetUserResult(ctx, userID, puzzle.Date, modeDaily)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		res = &domain.UserGameResult{UserID: userID, PuzzleDate: puzzle.Date, Mode: modeDaily}
+	}
+	if usedHint(res, kind) {
+		return reveal, nil
+	}
+	if res.Solved || res.GaveUp {
+		return nil, apperrors.InvalidInput("game is finished")
+	}
+	if len(res.Guesses) < unlockAfter {
+		return nil, apperrors.InvalidInput(fmt.Sprintf("hint unlocks after %d guesses", unlockAfter))
+	}
+	res.Hints = append(res.Hints, string(kind))
+	if err := s.rs.SaveUserResult(ctx, res); err != nil {
+		return nil, err
+	}
+	if s.stats != nil {
+		if serr := s.stats.RecordHintUse(ctx, userID); serr != nil && s.log != nil {
+			s.log.Warnw("record hint use failed", "user", userID, "error", serr)
+		}
+	}
+	return reveal, nil
+}
+
+// PosterHint returns the original poster URL of today's secret if the caller
+// used the poster hint (or finished the game); the handler pixelates it.
+func (s *DailyService) PosterHint(ctx context.Context, userID string) (string, error) {
+	if userID == "" || s.rs == nil {
+		return "", apperrors.Unauthorized("log in to use hints")
+	}
+	puzzle, err := s.GetOrCreateToday(ctx)
+	if err != nil {
+		return "", err
+	}
+	res, err := s.rs.GetUserResult(ctx, userID, puzzle.Date, modeDaily)
+	if err != nil {
+		return "", err
+	}
+	if res == nil || !(usedHint(res, domain.HintPoster) || res.Solved || res.GaveUp) {
+		return "", apperrors.Forbidden("poster hint not used")
+	}
+	if puzzle.AnswerSnapshot.PosterURL == "" {
+		return "", apperrors.NotFound("poster")
+	}
+	return puzzle.AnswerSnapshot.PosterURL, nil
+}
+
+// hintOffers lists the day's hints for a player's game (nil: not started).
+func hintOffers(puzzle *domain.DailyPuzzle, res *domain.UserGameResult) []HintOffer {
+	guesses := 0
+	if res != nil {
+		guesses = len(res.Guesses)
+	}
+	out := make([]HintOffer, 0, len(domain.HintKinds))
+	for _, kind := range domain.HintKinds {
+		reveal, available := revealHint(puzzle, kind)
+		offer := HintOffer{
+			Kind:        kind,
+			UnlockAfter: domain.HintUnlockAfter[kind],
+			Unlocked:    guesses >= domain.HintUnlockAfter[kind],
+			Available:   available,
+		}
+		if res != nil && usedHint(res, kind) {
+			offer.Reveal = reveal
+		}
+		out = append(out, offer)
+	}
+	return out
+}
+
+// revealHint computes a hint of the day's secret; false when the secret has
+// nothing to reveal (no studio, no tags, no poster). The tag is picked by
+// the date so every player sees the same one.
+func revealHint(puzzle *domain.DailyPuzzle, kind domain.HintKind) (*HintReveal, bool) {
+	a := puzzle.AnswerSnapshot
+	switch kind {
+	case domain.HintTag:
+		if len(a.Tags) == 0 {
+			return nil, false
+		}
+		t := a.Tags[hashDate(puzzle.Date+":tag")%uint32(len(a.Tags))]
+		return &HintReveal{Kind: kind, Text: t.Name}, true
+	case domain.HintStudio:
+		if len(a.Studios) == 0 {
+			return nil, false
+		}
+		return &HintReveal{Kind: kind, Text: a.Studios[0].Name}, true
+	case domain.HintLetter:
+		ru, en := firstLetter(a.NameRU), firstLetter(a.NameEN)
+		if ru == "" && en == "" {
+			return nil, false
+		}
+		return &HintReveal{Kind: kind, Text: ru, TextEN: en}, true
+	case domain.HintPoster:
+		if a.PosterURL == "" {
+			return nil, false
+		}
+		return &HintReveal{Kind: kind, URL: posterHintURL}, true
+	}
+	return nil, false
+}
+
+func usedHint(res *domain.UserGameResult, kind domain.HintKind) bool {
+	for _, h := range res.Hints {
+		if h == string(kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstLetter returns the first letter or digit of a title, upper-cased.
+func firstLetter(name string) string {
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return string(unicode.ToUpper(r))
+		}
+	}
+	return ""
+}
+
+// PixelatePoster decodes a poster and re-encodes it as a JPEG averaged over a
+// coarse grid, so the hint shows colours and layout but not the artwork.
+func PixelatePoster(r io.Reader) ([]byte, error) {
+	src, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("decode poster: %w", err)
+	}
+	b := src.Bounds()
+	block := b.Dx() / posterBlocks
+	if block < 1 {
+		block = 1
+	}
+	dst := image.NewRGBA(b)
+	for y0 := b.Min.Y; y0 < b.Max.Y; y0 += block {
+		for x0 := b.Min.X; x0 < b.Max.X; x0 += block {
+			cell := image.Rect(x0, y0, x0+block, y0+block).Intersect(b)
+			var rs, gs, bs, n uint64
+			for y := cell.Min.Y; y < cell.Max.Y; y++ {
+				for x := cell.Min.X; x < cell.Max.X; x++ {
+					cr, cg, cb, _ := src.At(x, y).RGBA()
+					rs, gs, bs, n = rs+uint64(cr), gs+uint64(cg), bs+uint64(cb), n+1
+				}
+			}
+			avg := color.RGBA64{R: uint16(rs / n), G: uint16(gs / n), B: uint16(bs / n), A: 0xffff}
+			for y := cell.Min.Y; y < cell.Max.Y; y++ {
+				for x := cell.Min.X; x < cell.Max.X; x++ {
+					dst.Set(x, y, avg)
+				}
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, fmt.Errorf("encode poster: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/domain"
+)
+
+func hintSamplePool() []domain.PoolAnime {
+	return []domain.PoolAnime{
+		{ID: "frieren", NameRU: "Фрирен", NameEN: "Frieren", PosterURL: "https://img/frieren.jpg",
+			Year: 2023, Episodes: 28, Genres: tx("fantasy"), Studios: tx("Madhouse"), Tags: tx("elves")},
+		{ID: "bocchi", NameRU: "Бочча", Year: 2022, Episodes: 12, Genres: tx("comedy"), Studios: tx("CloverWorks")},
+		{ID: "mob", NameRU: "Моб", Year: 2016, Episodes: 12, Genres: tx("action"), Studios: tx("Bones")},
+		{ID: "mushoku", NameRU: "Реинкарнация", Year: 2021, Episodes: 23, Genres: tx("fantasy"), Studios: tx("Bind"), Tags: tx("isekai")},
+	}
+}
+
+// hintSvc returns a DailyService whose secret of 2026-06-15 is frieren.
+func hintSvc() (*DailyService, *fakeResultStore, *fakeStats) {
+	pool := hintSamplePool()
+	games := newFakeGameRepo()
+	games.puzzles["2026-06-15"] = &domain.DailyPuzzle{Date: "2026-06-15", AnimeID: "frieren", AnswerSnapshot: pool[0]}
+	rs := newFakeResultStore()
+	st := &fakeStats{}
+	return NewDailyService(games, &fakePool{pool: pool}, fixedClock{"2026-06-15"}, rs, st), rs, st
+}
+
+func TestHints_UnlockRecordAndIdempotent(t *testing.T) {
+	svc, rs, st := hintSvc()
+	ctx := context.Background()
+
+	_, err := svc.UseHint(ctx, "", domain.HintStudio)
+	require.Error(t, err) // guests cannot use hints
+
+	_, err = svc.Guess(ctx, "u1", "bocchi", false)
+	require.NoError(t, err)
+	_, err = svc.UseHint(ctx, "u1", domain.HintTag)
+	require.Error(t, err) // unlocks after 2 guesses
+
+	_, err = svc.Guess(ctx, "u1", "mob", false)
+	require.NoError(t, err)
+	reveal, err := svc.UseHint(ctx, "u1", domain.HintTag)
+	require.NoError(t, err)
+	assert.Equal(t, "elves", reveal.Text)
+	_, err = svc.UseHint(ctx, "u1", domain.HintTag)
+	require.NoError(t, err)
+
+	res := rs.results[key("u1", "2026-06-15", modeDaily)]
+	assert.Equal(t, []string{"tag"}, res.Hints)
+	assert.Equal(t, 1, st.hints) // the repeat is not counted
+
+	out, err := svc.Guess(ctx, "u1", "frieren", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, out.HintsUsed)
+
+	state, err := svc.Resume(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, state.Hints, len(domain.HintKinds))
+	require.NotNil(t, state.Hints[0].Reveal)
+	assert.Nil(t, state.Hints[1].Reveal)
+}
+
+func TestHardMode_HidesArrowsAndRequiresConsistency(t *testing.T) {
+	svc, rs, _ := hintSvc()
+	ctx := context.Background()
+
+	out, err := svc.Guess(ctx, "u1", "bocchi", true)
+	require.NoError(t, err)
+	assert.Equal(t, domain.HintNone, out.Result.Year.Hint)
+	assert.Equal(t, domain.HintNone, out.Result.Episodes.Hint)
+
+	// bocchi had 12 episodes and got "wrong", so mob (also 12) is ruled out
+	_, err = svc.Guess(ctx, "u1", "mob", false)
+	require.Error(t, err)
+	assert.Len(t, rs.results[key("u1", "2026-06-15", modeDaily)].Guesses, 1) // rejected guess is not counted
+
+	// mushoku is still a candidate for every column bocchi was scored on
+	_, err = svc.Guess(ctx, "u1", "mushoku", false)
+	require.NoError(t, err)
+	state, err := svc.Resume(ctx, "u1")
+	require.NoError(t, err)
+	assert.True(t, state.Hard)
+	assert.Equal(t, domain.HintNone, state.Guesses[1].Result.Year.Hint)
+}
+
+func TestConsistent(t *testing.T) {
+	secret := domain.PoolAnime{Year: 2023, Genres: tx("a", "b")}
+	earlier := domain.PoolAnime{Year: 2020, Genres: tx("a")}
+	assert.True(t, Consistent(secret, earlier, secret))
+	assert.True(t, Consistent(secret, earlier, domain.PoolAnime{Year: 2001, Genres: tx("a", "c")}))
+	assert.False(t, Consistent(secret, earlier, domain.PoolAnime{Year: 2020, Genres: tx("a", "c")})) // year matched earlier
+	assert.False(t, Consistent(secret, earlier, domain.PoolAnime{Year: 2001, Genres: tx("c")}))      // no genre overlap
+}
+
+func TestPixelatePoster(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 24, 24))
+	for y := 0; y < 24; y++ {
+		for x := 0; x < 24; x++ {
+			if (x+y)%2 == 0 {
+				src.Set(x, y, color.White)
+			} else {
+				src.Set(x, y, color.Black)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, src))
+
+	out, err := PixelatePoster(&buf)
+	require.NoError(t, err)
+	img, err := jpeg.Decode(bytes.NewReader(out))
+	require.NoError(t, err)
+	assert.Equal(t, src.Bounds(), img.Bounds())
+	// the checkerboard averages to grey within each 2px block
+	r, _, _, _ := img.At(0, 0).RGBA()
+	assert.InDelta(t, 0x7fff, int(r), 0x1500)
+}
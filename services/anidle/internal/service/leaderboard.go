@@ -3,6 +3,10 @@ package service
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/domain"
 )
 
 // ZEntry is one sorted-set member+score.
@@ -14,6 +18,9 @@ type ZEntry struct {
 type zsetStore interface {
 	ZAdd(ctx context.Context, key, member string, score float64) error
 	ZRangeAsc(ctx context.Context, key string, n int) ([]ZEntry, error)
+	ZRem(ctx context.Context, key, member string) error
+	// ZScores returns the entries of the given members that are in the set.
+	ZScores(ctx context.Context, key string, members []string) ([]ZEntry, error)
 }
 
 type LeaderboardService struct{ z zsetStore }
@@ -24,30 +31,97 @@ func NewLeaderboardService(z zsetStore) *LeaderboardService { return &Leaderboar
 type LeaderEntry struct {
 	Username string `json:"username"`
 	Attempts int    `json:"attempts"`
+	Hints    int    `json:"hints"`
 }
 
-const attemptsWeight = 1e10 // attempts dominate; solve-time breaks ties
+// Score packing: attempts dominate, then hints used, then the solve time.
+const (
+	attemptsWeight = 1e10
+	hintsWeight    = 1e5 // > seconds in a day
+	secondsPerDay  = 86400
+)
 
 func lbKey(date string) string { return "anidle:leaderboard:" + date }
 
-// RecordSolve adds a solver. Score packs (attempts, solveUnix) so ascending
-// ZRange = fewest attempts first, earliest solve first within a tie.
-func (s *LeaderboardService) RecordSolve(ctx context.Context, date, username string, attempts int, solveUnix int64) error {
-	score := float64(attempts)*attemptsWeight + float64(solveUnix)
-	return s.z.ZAdd(ctx, lbKey(date), username, score)
+// modeKey is the board of one mode and day; the classic mode keeps its
+// original key.
+func modeKey(mode, date string) string {
+	if mode == domain.ModeDaily {
+		return lbKey(date)
+	}
+	return "anidle:leaderboard:" + mode + ":" + date
+}
+
+// RecordSolve adds a solver of the classic mode. Score packs (attempts,
+// hints, solve time of day) so ascending ZRange = fewest attempts first, then
+// fewest hints, then earliest solve. Boards are per day, so the time of day
+// orders solves as well as the full timestamp would.
+func (s *LeaderboardService) RecordSolve(ctx context.Context, date, username string, attempts, hints int, solveUnix int64) error {
+	return s.z.ZAdd(ctx, modeKey(domain.ModeDaily, date), username, packScore(attempts, hints, solveUnix))
+}
+
+// RecordModeSolve is RecordSolve on the board of the given mode (no hints).
+func (s *LeaderboardService) RecordModeSolve(ctx context.Context, mode, date, username string, attempts int, solveUnix int64) error {
+	return s.z.ZAdd(ctx, modeKey(mode, date), username, packScore(attempts, 0, solveUnix))
+}
+
+func packScore(attempts, hints int, solveUnix int64) float64 {
+	return float64(attempts)*attemptsWeight + float64(hints)*hintsWeight + float64(solveUnix%secondsPerDay)
+}
+
+func unpackScore(member string, score float64) LeaderEntry {
+	return LeaderEntry{
+		Username: member,
+		Attempts: int(score / attemptsWeight),
+		Hints:    int(math.Mod(score, attemptsWeight) / hintsWeight),
+	}
 }
 
 func (s *LeaderboardService) Top(ctx context.Context, date string, n int) ([]LeaderEntry, error) {
-	entries, err := s.z.ZRangeAsc(ctx, lbKey(date), n)
+	return s.TopMode(ctx, domain.ModeDaily, date, n)
+}
+
+// TopMode is Top on the board of the given mode.
+func (s *LeaderboardService) TopMode(ctx context.Context, mode, date string, n int) ([]LeaderEntry, error) {
+	entries, err := s.z.ZRangeAsc(ctx, modeKey(mode, date), n)
 	if err != nil {
 		return nil, fmt.Errorf("leaderboard top: %w", err)
 	}
 	out := make([]LeaderEntry, 0, len(entries))
 	for _, e := range entries {
-		out = append(out, LeaderEntry{
-			Username: e.Member,
-			Attempts: int(e.Score / attemptsWeight),
-		})
+		out = append(out, unpackScore(e.Member, e.Score))
+	}
+	return out, nil
+}
+
+// TopAmong is TopMode restricted to the given usernames (the friends view:
+// the caller and the users they follow).
+func (s *LeaderboardService) TopAmong(ctx context.Context, mode, date string, usernames []string) ([]LeaderEntry, error) {
+	out := []LeaderEntry{}
+	if len(usernames) == 0 {
+		return out, nil
+	}
+	entries, err := s.z.ZScores(ctx, modeKey(mode, date), usernames)
+	if err != nil {
+		return nil, fmt.Errorf("leaderboard among: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Score < entries[j].Score })
+	for _, e := range entries {
+		out = append(out, unpackScore(e.Member, e.Score))
 	}
 	return out, nil
 }
+
+// Forget drops a user from every mode's boards of the given dates (account
+// deletion). Boards are keyed by username and expire after 48h, so callers
+// pass today and yesterday.
+func (s *LeaderboardService) Forget(ctx context.Context, username string, dates ...string) error {
+	for _, d := range dates {
+		for _, mode := range domain.Modes {
+			if err := s.z.ZRem(ctx, modeKey(mode, d), username); err != nil {
+				return fmt.Errorf("leaderboard forget: %w", err)
+			}
+		}
+	}
+	return nil
+}
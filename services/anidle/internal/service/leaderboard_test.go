@@ -18,6 +18,19 @@ func (f *fakeZSet) ZAdd(_ context.Context, key, member string, score float64) er
 	f.members[key][member] = score
 	return nil
 }
+func (f *fakeZSet) ZRem(_ context.Context, key, member string) error {
+	delete(f.members[key], member)
+	return nil
+}
+func (f *fakeZSet) ZScores(_ context.Context, key string, members []string) ([]ZEntry, error) {
+	out := []ZEntry{}
+	for _, m := range members {
+		if s, ok := f.members[key][m]; ok {
+			out = append(out, ZEntry{Member: m, Score: s})
+		}
+	}
+	return out, nil
+}
 func (f *fakeZSet) ZRangeAsc(_ context.Context, key string, n int) ([]ZEntry, error) {
 	type kv struct {
 		m string
@@ -45,9 +58,9 @@ func TestLeaderboard_RanksByFewerAttemptsThenEarlier(t *testing.T) {
 	lb := NewLeaderboardService(z)
 	ctx := context.Background()
 
-	require.NoError(t, lb.RecordSolve(ctx, "2026-06-15", "alice", 4, 1000))
-	require.NoError(t, lb.RecordSolve(ctx, "2026-06-15", "bob", 2, 2000))
-	require.NoError(t, lb.RecordSolve(ctx, "2026-06-15", "carol", 2, 1500))
+	require.NoError(t, lb.RecordSolve(ctx, "2026-06-15", "alice", 4, 0, 1000))
+	require.NoError(t, lb.RecordSolve(ctx, "2026-06-15", "bob", 2, 0, 2000))
+	require.NoError(t, lb.RecordSolve(ctx, "2026-06-15", "carol", 2, 0, 1500))
 
 	top, err := lb.Top(ctx, "2026-06-15", 10)
 	require.NoError(t, err)
@@ -58,3 +71,60 @@ func TestLeaderboard_RanksByFewerAttemptsThenEarlier(t *testing.T) {
 	assert.Equal(t, "alice", top[2].Username)
 	assert.Equal(t, 2, top[0].Attempts)
 }
+
+func TestLeaderboard_ForgetRemovesUserFromGivenDays(t *testing.T) {
+	z := newFakeZSet()
+	lb := NewLeaderboardService(z)
+	ctx := context.Background()
+
+	require.NoError(t, lb.RecordSolve(ctx, "2026-06-14", "alice", 3, 0, 900))
+	require.NoError(t, lb.RecordSolve(ctx, "2026-06-15", "alice", 4, 0, 1000))
+	require.NoError(t, lb.RecordSolve(ctx, "2026-06-15", "bob", 2, 0, 2000))
+
+	require.NoError(t, lb.Forget(ctx, "alice", "2026-06-14", "2026-06-15"))
+
+	top, err := lb.Top(ctx, "2026-06-15", 10)
+	require.NoError(t, err)
+	require.Len(t, top, 1)
+	assert.Equal(t, "bob", top[0].Username)
+	top, err = lb.Top(ctx, "2026-06-14", 10)
+	require.NoError(t, err)
+	assert.Empty(t, top)
+}
+
+func TestLeaderboard_TopAmongKeepsOnlyGivenUsers(t *testing.T) {
+	z := newFakeZSet()
+	lb := NewLeaderboardService(z)
+	ctx := context.Background()
+
+	require.NoError(t, lb.RecordSolve(ctx, "2026-06-15", "alice", 4, 0, 1000))
+	require.NoError(t, lb.RecordSolve(ctx, "2026-06-15", "bob", 2, 0, 2000))
+	require.NoError(t, lb.RecordSolve(ctx, "2026-06-15", "carol", 1, 0, 1500))
+
+	top, err := lb.TopAmong(ctx, "daily", "2026-06-15", []string{"alice", "bob", "dave"})
+	require.NoError(t, err)
+	require.Len(t, top, 2) // carol is not a friend, dave did not solve
+	assert.Equal(t, "bob", top[0].Username)
+	assert.Equal(t, "alice", top[1].Username)
+	assert.Equal(t, 4, top[1].Attempts)
+}
+
+func TestLeaderboard_HintsBreakAttemptTies(t *testing.T) {
+	z := newFakeZSet()
+	lb := NewLeaderboardService(z)
+	ctx := context.Background()
+
+	require.NoError(t, lb.RecordSolve(ctx, "2026-06-15", "alice", 3, 2, 1000))
+	require.NoError(t, lb.RecordSolve(ctx, "2026-06-15", "bob", 3, 0, 2000))
+	require.NoError(t, lb.RecordSolve(ctx, "2026-06-15", "carol", 2, 4, 3000))
+
+	top, err := lb.Top(ctx, "2026-06-15", 10)
+	require.NoError(t, err)
+	require.Len(t, top, 3)
+	assert.Equal(t, "carol", top[0].Username) // fewer attempts beat fewer hints
+	assert.Equal(t, 4, top[0].Hints)
+	assert.Equal(t, "bob", top[1].Username) // same attempts: fewer hints first
+	assert.Equal(t, "alice", top[2].Username)
+	assert.Equal(t, 3, top[2].Attempts)
+	assert.Equal(t, 2, top[2].Hints)
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/repo"
+)
+
+type modePuzzleRepo interface {
+	GetModePuzzle(ctx context.Context, mode, date string) (*domain.ModePuzzle, error)
+	CreateModePuzzle(ctx context.Context, p *domain.ModePuzzle) error
+	RecentModeSecretIDs(ctx context.Context, mode string, days int) ([]string, error)
+}
+
+type modeStatsUpdater interface {
+	RecordModeResult(ctx context.Context, mode, userID, date string, won bool, attempts int) error
+}
+
+// ModeGuess is one scored guess of a mode: the guessed entry (public — the
+// player chose it) and the per-column result. Answer is the secret, set only
+// once the game is solved.
+type ModeGuess[S, G, V any] struct {
+	Guess   G    `json:"guess"`
+	Result  V    `json:"result"`
+	Solved  bool `json:"solved"`
+	Attempt int  `json:"attempt"`
+	Answer  *S   `json:"answer,omitempty"`
+	// FreshSolve marks the guess that first solves the day (see GuessOutcome).
+	FreshSolve bool `json:"-"`
+}
+
+// ModeState is a mode's resume payload (no secret unless finished).
+type ModeState[S, G, V any] struct {
+	Date    string               `json:"date"`
+	Solved  bool                 `json:"solved"`
+	GaveUp  bool                 `json:"gave_up"`
+	Guesses []ModeGuess[S, G, V] `json:"guesses"`
+	Answer  *S                   `json:"answer,omitempty"`
+}
+
+// modeGame is the daily-game core shared by the character and theme modes —
+// the day's secret, the logged-in result row, stats and the fresh-solve
+// flag — mirroring DailyService. S is the secret, G a guessable entry and V
+// the per-guess comparison; the mode supplies the pool and the scoring.
+type modeGame[S, G, V any] struct {
+	mode    string
+	puzzles modePuzzleRepo
+	clock   Clock
+	rs      resultStore // nil-safe: guests only
+	stats   modeStatsUpdater
+	log     *logger.Logger
+
+	secrets  func(ctx context.Context) ([]S, error)
+	secretID func(S) string
+	lookup   func(ctx context.Context, id string) (G, bool)
+	score    func(secret S, guess G) (V, bool)
+}
+
+// today returns the day's puzzle and its frozen secret, creating it
+// deterministically on first call.
+func (g *modeGame[S, G, V]) today(ctx context.Context) (*domain.ModePuzzle, S, error) {
+	var secret S
+	date := g.clock.Today()
+	p, err := g.puzzles.GetModePuzzle(ctx, g.mode, date)
+	if errors.Is(err, repo.ErrNotFound) {
+		p, err = g.create(ctx, date)
+	}
+	if err != nil {
+		return nil, secret, err
+	}
+	if err := json.Unmarshal([]byte(p.Snapshot), &secret); err != nil {
+		return nil, secret, fmt.Errorf("decode %s snapshot: %w", g.mode, err)
+	}
+	return p, secret, nil
+}
+
+func (g *modeGame[S, G, V]) create(ctx context.Context, date string) (*domain.ModePuzzle, error) {
+	pool, err := g.secrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(pool) == 0 {
+		return nil, fmt.Errorf("anidle: empty %s pool", g.mode)
+	}
+	recent, err := g.puzzles.RecentModeSecretIDs(ctx, g.mode, recentExclusionDays)
+	if err != nil {
+		return nil, err
+	}
+	recentSet := make(map[string]struct{}, len(recent))
+	for _, id := range recent {
+		recentSet[id] = struct{}{}
+	}
+	eligible := make([]S, 0, len(pool))
+	for _, s := range pool {
+		if _, bad := recentSet[g.secretID(s)]; !bad {
+			eligible = append(eligible, s)
+		}
+	}
+	if len(eligible) == 0 {
+		eligible = pool
+	}
+	// The mode salts the hash so the modes' secrets are not correlated.
+	secret := eligible[int(hashDate(g.mode+":"+date)%uint32(len(eligible)))]
+	snap, err := json.Marshal(secret)
+	if err != nil {
+		return nil, err
+	}
+	p := &domain.ModePuzzle{Date: date, Mode: g.mode, SecretID: g.secretID(secret), Snapshot: string(snap)}
+	if err := g.puzzles.CreateModePuzzle(ctx, p); err != nil {
+		// lost a create race — re-read the winner
+		if existing, gerr := g.puzzles.GetModePuzzle(ctx, g.mode, date); gerr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+// result returns the user's row for the day, or nil for guests / no game yet.
+func (g *modeGame[S, G, V]) result(ctx context.Context, userID, date string) (*domain.UserGameResult, error) {
+	if userID == "" || g.rs == nil {
+		return nil, nil
+	}
+	return g.rs.GetUserResult(ctx, userID, date, g.mode)
+}
+
+// Guess scores one guess. userID == "" means an anonymous guest (no persistence).
+func (g *modeGame[S, G, V]) Guess(ctx context.Context, userID, guessID string) (*ModeGuess[S, G, V], error) {
+	p, secret, err := g.today(ctx)
+	if err != nil {
+		return nil, err
+	}
+	guess, ok := g.lookup(ctx, guessID)
+	if !ok {
+		return nil, fmt.Errorf("anidle: unknown %s guess", g.mode)
+	}
+	result, solved := g.score(secret, guess)
+	out := &ModeGuess[S, G, V]{Guess: guess, Result: result, Solved: solved}
+
+	if userID == "" || g.rs == nil {
+		if solved {
+			out.Answer = &secret
+		}
+		return out, nil
+	}
+
+	res, err := g.rs.GetUserResult(ctx, userID, p.Date, g.mode)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		res = &domain.UserGameResult{UserID: userID, PuzzleDate: p.Date, Mode: g.mode}
+	}
+	if !res.Solved && !res.GaveUp { // ignore guesses once the game is finished
+		res.Guesses = append(res.Guesses, guessID)
+		res.Attempts = len(res.Guesses)
+		if solved {
+			res.Solved = true
+			now := timeNow()
+			res.SolvedAt = &now
+			out.FreshSolve = true
+		}
+		if err := g.rs.SaveUserResult(ctx, res); err != nil {
+			return nil, err
+		}
+		if solved && g.stats != nil {
+			if serr := g.stats.RecordModeResult(ctx, g.mode, userID, p.Date, true, res.Attempts); serr != nil && g.log != nil {
+				g.log.Warnw("record mode stats failed", "mode", g.mode, "user", userID, "error", serr)
+			}
+		}
+	}
+	out.Attempt = res.Attempts
+	if res.Solved {
+		out.Answer = &secret
+	}
+	return out, nil
+}
+
+// GiveUp marks the day lost for a logged-in user and reveals the secret.
+func (g *modeGame[S, G, V]) GiveUp(ctx context.Context, userID string) (*S, error) {
+	p, secret, err := g.today(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if userID != "" && g.rs != nil {
+		res, err := g.rs.GetUserResult(ctx, userID, p.Date, g.mode)
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			res = &domain.UserGameResult{UserID: userID, PuzzleDate: p.Date, Mode: g.mode}
+		}
+		if !res.Solved && !res.GaveUp { // finalize the loss exactly once
+			res.GaveUp = true
+			res.Attempts = len(res.Guesses)
+			if err := g.rs.SaveUserResult(ctx, res); err != nil {
+				return nil, err
+			}
+			if g.stats != nil {
+				_ = g.stats.RecordModeResult(ctx, g.mode, userID, p.Date, false, res.Attempts)
+			}
+		}
+	}
+	return &secret, nil
+}
+
+// Resume rebuilds a logged-in user's progress for today.
+func (g *modeGame[S, G, V]) Resume(ctx context.Context, userID string) (*ModeState[S, G, V], error) {
+	p, secret, err := g.today(ctx)
+	if err != nil {
+		return nil, err
+	}
+	state := &ModeState[S, G, V]{Date: p.Date, Guesses: []ModeGuess[S, G, V]{}}
+	res, err := g.result(ctx, userID, p.Date)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return state, nil
+	}
+	state.Solved = res.Solved
+	state.GaveUp = res.GaveUp
+	for _, id := range res.Guesses {
+		guess, ok := g.lookup(ctx, id)
+		if !ok {
+			continue
+		}
+		result, solved := g.score(secret, guess)
+		state.Guesses = append(state.Guesses, ModeGuess[S, G, V]{Guess: guess, Result: result, Solved: solved})
+	}
+	if res.Solved || res.GaveUp { // reveal the secret once the game is finished
+		state.Answer = &secret
+	}
+	return state, nil
+}
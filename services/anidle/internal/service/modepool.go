@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/cache"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+)
+
+// ModePool caches the pool of a non-classic mode (Redis + in-memory index)
+// exactly like PoolStore does for the anime pool: loaded on first use and
+// reloaded once older than ttl.
+type ModePool[T any] struct {
+	cache poolCache
+	fetch func(ctx context.Context) ([]T, error)
+	key   string
+	id    func(T) string
+	names func(T) []string
+	ttl   time.Duration
+	log   *logger.Logger
+
+	mu       sync.RWMutex
+	byID     map[string]T
+	all      []T
+	loaded   bool
+	loadedAt time.Time
+	now      func() time.Time
+}
+
+// NewModePool builds a pool cached under key. id indexes entries for
+// Lookup; names are what Search matches.
+func NewModePool[T any](c poolCache, key string, fetch func(ctx context.Context) ([]T, error), id func(T) string, names func(T) []string, ttl time.Duration, log *logger.Logger) *ModePool[T] {
+	return &ModePool[T]{cache: c, fetch: fetch, key: key, id: id, names: names, ttl: ttl, log: log, now: time.Now}
+}
+
+func (p *ModePool[T]) freshLocked() bool {
+	return p.loaded && (p.ttl <= 0 || p.now().Before(p.loadedAt.Add(p.ttl)))
+}
+
+// All returns the full pool, loading it (Redis → source) when stale.
+func (p *ModePool[T]) All(ctx context.Context) ([]T, error) {
+	p.mu.RLock()
+	if p.freshLocked() {
+		all := p.all
+		p.mu.RUnlock()
+		return all, nil
+	}
+	p.mu.RUnlock()
+	return p.load(ctx)
+}
+
+func (p *ModePool[T]) load(ctx context.Context) ([]T, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.freshLocked() {
+		return p.all, nil
+	}
+
+	var pool []T
+	if err := p.cache.Get(ctx, p.key, &pool); err != nil {
+		if !errors.Is(err, cache.ErrNotFound) && !errors.Is(err, errCacheMiss) && p.log != nil {
+			p.log.Warnw("mode pool cache get failed; refetching", "key", p.key, "error", err)
+		}
+		fetched, ferr := p.fetch(ctx)
+		if ferr != nil {
+			return nil, ferr
+		}
+		pool = fetched
+		if serr := p.cache.Set(ctx, p.key, pool, p.ttl); serr != nil && p.log != nil {
+			p.log.Warnw("mode pool cache set failed", "key", p.key, "error", serr)
+		}
+	}
+
+	p.byID = make(map[string]T, len(pool))
+	for _, e := range pool {
+		p.byID[p.id(e)] = e
+	}
+	p.all = pool
+	p.loaded = true
+	p.loadedAt = p.now()
+	return pool, nil
+}
+
+// Lookup returns the entry by id (after All has loaded the pool).
+func (p *ModePool[T]) Lookup(id string) (T, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	e, ok := p.byID[id]
+	return e, ok
+}
+
+// Search returns up to limit entries with a name containing q
+// (case-insensitive).
+func (p *ModePool[T]) Search(ctx context.Context, q string, limit int) []T {
+	q = strings.ToLower(strings.TrimSpace(q))
+	if q == "" {
+		return nil
+	}
+	all, err := p.All(ctx)
+	if err != nil {
+		return nil
+	}
+	out := make([]T, 0, limit)
+	for _, e := range all {
+		for _, n := range p.names(e) {
+			if n != "" && strings.Contains(strings.ToLower(n), q) {
+				out = append(out, e)
+				break
+			}
+		}
+		if len(out) >= limit {
+			break
+		}
+	}
+	return out
+}
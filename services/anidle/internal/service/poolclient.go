@@ -30,21 +30,50 @@ func NewPoolClient(catalogURL string, timeout time.Duration, log *logger.Logger)
 	}
 }
 
-type poolEnvelope struct {
-	Success bool               `json:"success"`
-	Data    []domain.PoolAnime `json:"data"`
+type poolEnvelope[T any] struct {
+	Success bool `json:"success"`
+	Data    []T  `json:"data"`
 }
 
 // Fetch GETs /internal/guessgame/pool and decodes the {success,data} envelope.
 func (c *PoolClient) Fetch(ctx context.Context) ([]domain.PoolAnime, error) {
-	endpoint := c.baseURL + "/internal/guessgame/pool"
+	return fetchPool[domain.PoolAnime](ctx, c.client, c.baseURL+"/internal/guessgame/pool")
+}
+
+// FetchCharacters GETs catalog's /internal/guessgame/characters — the
+// character-mode pool.
+func (c *PoolClient) FetchCharacters(ctx context.Context) ([]domain.PoolCharacter, error) {
+	return fetchPool[domain.PoolCharacter](ctx, c.client, c.baseURL+"/internal/guessgame/characters")
+}
+
+// ThemePoolClient fetches the theme-mode pool from the themes service.
+type ThemePoolClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewThemePoolClient(themesURL string, timeout time.Duration) *ThemePoolClient {
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	return &ThemePoolClient{baseURL: themesURL, client: &http.Client{Timeout: timeout}}
+}
+
+// Fetch GETs /internal/guessgame/themes.
+func (c *ThemePoolClient) Fetch(ctx context.Context) ([]domain.PoolTheme, error) {
+	return fetchPool[domain.PoolTheme](ctx, c.client, c.baseURL+"/internal/guessgame/themes")
+}
+
+// fetchPool GETs an internal pool endpoint and decodes the {success,data}
+// envelope.
+func fetchPool[T any](ctx context.Context, client *http.Client, endpoint string) ([]T, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return nil, fmt.Errorf("build pool request: %w", err)
 	}
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := c.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("pool request: %w", err)
 	}
@@ -55,12 +84,12 @@ func (c *PoolClient) Fetch(ctx context.Context) ([]domain.PoolAnime, error) {
 		return nil, fmt.Errorf("pool endpoint returned %d: %s", resp.StatusCode, string(body))
 	}
 
-	var env poolEnvelope
+	var env poolEnvelope[T]
 	if err := json.Unmarshal(body, &env); err != nil {
 		return nil, fmt.Errorf("decode pool envelope: %w", err)
 	}
 	if !env.Success {
-		// Catalog signalled failure with HTTP 200 — do NOT cache an empty pool.
+		// The service signalled failure with HTTP 200 — do NOT cache an empty pool.
 		return nil, fmt.Errorf("pool endpoint reported success=false")
 	}
 	return env.Data, nil
@@ -67,3 +67,31 @@ func (s *RedisZSet) ZRangeAsc(ctx context.Context, key string, n int) ([]ZEntry,
 	}
 	return out, nil
 }
+
+func (s *RedisZSet) ZRem(ctx context.Context, key, member string) error {
+	return s.client.ZRem(ctx, key, member).Err()
+}
+
+// ZScores pipelines one ZSCORE per member; members not in the set are skipped.
+func (s *RedisZSet) ZScores(ctx context.Context, key string, members []string) ([]ZEntry, error) {
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.FloatCmd, len(members))
+	for i, m := range members {
+		cmds[i] = pipe.ZScore(ctx, key, m)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+	out := make([]ZEntry, 0, len(members))
+	for i, cmd := range cmds {
+		score, err := cmd.Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, ZEntry{Member: members[i], Score: score})
+	}
+	return out, nil
+}
@@ -11,6 +11,8 @@ import (
 type statsStore interface {
 	GetUserStats(ctx context.Context, userID string) (*domain.UserStats, error)
 	SaveUserStats(ctx context.Context, st *domain.UserStats) error
+	GetUserModeStats(ctx context.Context, userID, mode string) (*domain.UserModeStats, error)
+	SaveUserModeStats(ctx context.Context, st *domain.UserModeStats) error
 }
 
 type StatsService struct{ store statsStore }
@@ -28,8 +30,37 @@ func (s *StatsService) Get(ctx context.Context, userID string) (*domain.UserStat
 	return st, nil
 }
 
+// GetMode returns the user's stats for one mode (the classic daily stats for
+// domain.ModeDaily).
+func (s *StatsService) GetMode(ctx context.Context, userID, mode string) (*domain.UserStats, error) {
+	if mode == domain.ModeDaily {
+		return s.Get(ctx, userID)
+	}
+	st, err := s.store.GetUserModeStats(ctx, userID, mode)
+	if err != nil {
+		return nil, err
+	}
+	if st == nil {
+		return &domain.UserStats{UserID: userID, GuessDistribution: map[string]int{}}, nil
+	}
+	return &st.UserStats, nil
+}
+
 // RecordDailyResult updates aggregates + streak for one finished daily game.
 func (s *StatsService) RecordDailyResult(ctx context.Context, userID, date string, won bool, attempts int) error {
+	st, err := s.store.GetUserStats(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if st == nil {
+		st = &domain.UserStats{UserID: userID}
+	}
+	applyResult(st, date, won, attempts)
+	return s.store.SaveUserStats(ctx, st)
+}
+
+// RecordHintUse counts one hint of the classic daily mode.
+func (s *StatsService) RecordHintUse(ctx context.Context, userID string) error {
 	st, err := s.store.GetUserStats(ctx, userID)
 	if err != nil {
 		return err
@@ -37,6 +68,30 @@ func (s *StatsService) RecordDailyResult(ctx context.Context, userID, date strin
 	if st == nil {
 		st = &domain.UserStats{UserID: userID, GuessDistribution: map[string]int{}}
 	}
+	st.HintsUsed++
+	st.UpdatedAt = time.Now().UTC()
+	return s.store.SaveUserStats(ctx, st)
+}
+
+// RecordModeResult is RecordDailyResult for any mode; each mode keeps its
+// own streak.
+func (s *StatsService) RecordModeResult(ctx context.Context, mode, userID, date string, won bool, attempts int) error {
+	if mode == domain.ModeDaily {
+		return s.RecordDailyResult(ctx, userID, date, won, attempts)
+	}
+	st, err := s.store.GetUserModeStats(ctx, userID, mode)
+	if err != nil {
+		return err
+	}
+	if st == nil {
+		st = &domain.UserModeStats{Mode: mode, UserStats: domain.UserStats{UserID: userID}}
+	}
+	applyResult(&st.UserStats, date, won, attempts)
+	return s.store.SaveUserModeStats(ctx, st)
+}
+
+// applyResult folds one finished game into the aggregates + streak.
+func applyResult(st *domain.UserStats, date string, won bool, attempts int) {
 	if st.GuessDistribution == nil {
 		st.GuessDistribution = map[string]int{}
 	}
@@ -58,7 +113,6 @@ func (s *StatsService) RecordDailyResult(ctx context.Context, userID, date strin
 	}
 	st.LastPlayedDate = date
 	st.UpdatedAt = time.Now().UTC()
-	return s.store.SaveUserStats(ctx, st)
 }
 
 // isYesterday reports whether `prev` is exactly one day before `cur` (both "2006-01-02").
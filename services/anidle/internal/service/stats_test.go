@@ -10,9 +10,14 @@ import (
 	"github.com/ILITA-hub/animeenigma/services/anidle/internal/domain"
 )
 
-type fakeStatsStore struct{ stats map[string]*domain.UserStats }
+type fakeStatsStore struct {
+	stats map[string]*domain.UserStats
+	modes map[string]*domain.UserModeStats
+}
 
-func newFakeStatsStore() *fakeStatsStore { return &fakeStatsStore{stats: map[string]*domain.UserStats{}} }
+func newFakeStatsStore() *fakeStatsStore {
+	return &fakeStatsStore{stats: map[string]*domain.UserStats{}, modes: map[string]*domain.UserModeStats{}}
+}
 func (f *fakeStatsStore) GetUserStats(_ context.Context, u string) (*domain.UserStats, error) {
 	return f.stats[u], nil
 }
@@ -20,6 +25,13 @@ func (f *fakeStatsStore) SaveUserStats(_ context.Context, st *domain.UserStats)
 	f.stats[st.UserID] = st
 	return nil
 }
+func (f *fakeStatsStore) GetUserModeStats(_ context.Context, u, mode string) (*domain.UserModeStats, error) {
+	return f.modes[u+"|"+mode], nil
+}
+func (f *fakeStatsStore) SaveUserModeStats(_ context.Context, st *domain.UserModeStats) error {
+	f.modes[st.UserID+"|"+st.Mode] = st
+	return nil
+}
 
 func TestStats_StreakIncrementsOnConsecutiveDays(t *testing.T) {
 	store := newFakeStatsStore()
@@ -59,3 +71,24 @@ func TestStats_LossBreaksStreak(t *testing.T) {
 	assert.Equal(t, 1, st.GamesWon)
 	assert.Equal(t, 2, st.GamesPlayed)
 }
+
+func TestStats_ModesKeepSeparateStreaks(t *testing.T) {
+	store := newFakeStatsStore()
+	svc := NewStatsService(store)
+	ctx := context.Background()
+
+	require.NoError(t, svc.RecordModeResult(ctx, domain.ModeDaily, "u1", "2026-06-15", true, 3))
+	require.NoError(t, svc.RecordModeResult(ctx, domain.ModeCharacter, "u1", "2026-06-14", true, 5))
+	require.NoError(t, svc.RecordModeResult(ctx, domain.ModeCharacter, "u1", "2026-06-15", true, 2))
+
+	daily, err := svc.GetMode(ctx, "u1", domain.ModeDaily)
+	require.NoError(t, err)
+	assert.Equal(t, 1, daily.CurrentStreak)
+	chars, err := svc.GetMode(ctx, "u1", domain.ModeCharacter)
+	require.NoError(t, err)
+	assert.Equal(t, 2, chars.CurrentStreak)
+	assert.Equal(t, 1, chars.GuessDistribution["5"])
+	theme, err := svc.GetMode(ctx, "u1", domain.ModeTheme)
+	require.NoError(t, err)
+	assert.Zero(t, theme.GamesPlayed)
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/domain"
+)
+
+// snippetSeconds is how much of the track each attempt unlocks: the first
+// listen is 1s, every wrong guess plays a longer one.
+var snippetSeconds = []int{1, 2, 4, 7, 11, 16}
+
+// snippetBytesPerSecond converts a snippet length into a byte prefix of the
+// audio track. animethemes audio is ~192 kbps Ogg Vorbis, and a prefix of an
+// Ogg stream decodes up to where it is cut.
+const snippetBytesPerSecond = 24 * 1024
+
+// ThemeGuess is the theme mode's guess payload.
+type ThemeGuess = ModeGuess[domain.PoolTheme, domain.ThemeAnime, domain.ThemeComparison]
+
+// ThemeState is the theme mode's resume payload. ThemeType ("OP" / "ED") is
+// a free hint; SnippetSeconds is the length the player may listen to now (0
+// once the game is finished — the whole track).
+type ThemeState struct {
+	ModeState[domain.PoolTheme, domain.ThemeAnime, domain.ThemeComparison]
+	ThemeType      string `json:"theme_type"`
+	Step           int    `json:"step"`
+	MaxStep        int    `json:"max_step"`
+	SnippetSeconds int    `json:"snippet_seconds"`
+}
+
+// ThemeAudio is what the audio proxy streams: the secret track's basename and
+// the byte prefix allowed (0 = the whole track).
+type ThemeAudio struct {
+	Basename string
+	MaxBytes int64
+}
+
+type themePool interface {
+	All(ctx context.Context) ([]domain.PoolTheme, error)
+}
+
+// ThemeService is the OP/ED audio mode: guess the anime of the day's theme
+// from progressively longer snippets.
+type ThemeService struct {
+	game *modeGame[domain.PoolTheme, domain.ThemeAnime, domain.ThemeComparison]
+	pool themePool
+}
+
+func NewThemeService(r modePuzzleRepo, p themePool, clock Clock, rs resultStore, stats modeStatsUpdater, log *logger.Logger) *ThemeService {
+	if clock == nil {
+		clock = realClock{}
+	}
+	s := &ThemeService{pool: p}
+	s.game = &modeGame[domain.PoolTheme, domain.ThemeAnime, domain.ThemeComparison]{
+		mode: domain.ModeTheme, puzzles: r, clock: clock, rs: rs, stats: stats, log: log,
+		secrets:  p.All,
+		secretID: func(t domain.PoolTheme) string { return t.ID },
+		lookup:   s.lookupAnime,
+		score: func(secret domain.PoolTheme, guess domain.ThemeAnime) (domain.ThemeComparison, bool) {
+			return CompareTheme(secret, guess), secret.AnimeID == guess.ID
+		},
+	}
+	return s
+}
+
+func (s *ThemeService) Guess(ctx context.Context, userID, animeID string) (*ThemeGuess, error) {
+	return s.game.Guess(ctx, userID, animeID)
+}
+
+func (s *ThemeService) GiveUp(ctx context.Context, userID string) (*domain.PoolTheme, error) {
+	return s.game.GiveUp(ctx, userID)
+}
+
+func (s *ThemeService) Resume(ctx context.Context, userID string) (*ThemeState, error) {
+	st, err := s.game.Resume(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	p, secret, err := s.game.today(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := &ThemeState{ModeState: *st, ThemeType: secret.ThemeType, MaxStep: len(snippetSeconds) - 1}
+	if st.Solved || st.GaveUp {
+		out.Step = out.MaxStep
+		return out, nil
+	}
+	res, err := s.game.result(ctx, userID, p.Date)
+	if err != nil {
+		return nil, err
+	}
+	if res != nil {
+		out.Step = snippetStep(len(res.Guesses))
+	}
+	out.SnippetSeconds = snippetSeconds[out.Step]
+	return out, nil
+}
+
+// Audio resolves what the player may hear of today's track at step. A
+// logged-in player is capped at the step their wrong guesses unlocked and
+// hears the whole track once finished; a guest's step is taken as asked.
+func (s *ThemeService) Audio(ctx context.Context, userID string, step int) (*ThemeAudio, error) {
+	p, secret, err := s.game.today(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if userID != "" && s.game.rs != nil {
+		res, err := s.game.result(ctx, userID, p.Date)
+		if err != nil {
+			return nil, err
+		}
+		unlocked := 0
+		if res != nil {
+			if res.Solved || res.GaveUp {
+				return &ThemeAudio{Basename: secret.AudioBasename}, nil
+			}
+			unlocked = len(res.Guesses)
+		}
+		step = min(step, unlocked)
+	}
+	step = snippetStep(step)
+	return &ThemeAudio{Basename: secret.AudioBasename, MaxBytes: int64(snippetSeconds[step]) * snippetBytesPerSecond}, nil
+}
+
+// Search returns up to limit guessable anime whose name contains q.
+func (s *ThemeService) Search(ctx context.Context, q string, limit int) []domain.ThemeAnime {
+	q = strings.ToLower(strings.TrimSpace(q))
+	if q == "" {
+		return nil
+	}
+	pool, err := s.pool.All(ctx)
+	if err != nil {
+		return nil
+	}
+	out := make([]domain.ThemeAnime, 0, limit)
+	seen := make(map[string]bool)
+	for _, t := range pool {
+		if seen[t.AnimeID] {
+			continue
+		}
+		if strings.Contains(strings.ToLower(t.AnimeNameRU), q) || strings.Contains(strings.ToLower(t.AnimeName), q) {
+			seen[t.AnimeID] = true
+			out = append(out, themeAnime(t))
+			if len(out) >= limit {
+				break
+			}
+		}
+	}
+	return out
+}
+
+// lookupAnime finds a guessable anime by id among the pool's themes.
+func (s *ThemeService) lookupAnime(ctx context.Context, id string) (domain.ThemeAnime, bool) {
+	pool, err := s.pool.All(ctx)
+	if err != nil {
+		return domain.ThemeAnime{}, false
+	}
+	for _, t := range pool {
+		if t.AnimeID == id {
+			return themeAnime(t), true
+		}
+	}
+	return domain.ThemeAnime{}, false
+}
+
+func themeAnime(t domain.PoolTheme) domain.ThemeAnime {
+	return domain.ThemeAnime{
+		ID: t.AnimeID, NameRU: t.AnimeNameRU, NameEN: t.AnimeName,
+		PosterURL: t.PosterURL, Franchise: t.Franchise, Year: t.Year,
+	}
+}
+
+// snippetStep clamps n into the snippet schedule.
+func snippetStep(n int) int {
+	return max(0, min(n, len(snippetSeconds)-1))
+}
+
+// CompareTheme scores a theme-mode guess against the secret. Pure function.
+// Anime of the same franchise count as a franchise match.
+func CompareTheme(secret domain.PoolTheme, guess domain.ThemeAnime) domain.ThemeComparison {
+	franchise := domain.ColumnResult{Status: domain.MatchWrong}
+	if secret.AnimeID == guess.ID || (secret.Franchise != "" && secret.Franchise == guess.Franchise) {
+		franchise.Status = domain.MatchCorrect
+	}
+	return domain.ThemeComparison{
+		Franchise: franchise,
+		Year:      compareInt(secret.Year, guess.Year),
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ILITA-hub/animeenigma/services/anidle/internal/domain"
+)
+
+type fakeThemePool struct{ pool []domain.PoolTheme }
+
+func (f *fakeThemePool) All(_ context.Context) ([]domain.PoolTheme, error) { return f.pool, nil }
+
+func newThemeSvc(rs resultStore) *ThemeService {
+	// A single theme keeps the day's secret deterministic.
+	pool := &fakeThemePool{pool: []domain.PoolTheme{
+		{ID: "t1", Slug: "OP1", ThemeType: "OP", AudioBasename: "SousouNoFrieren-OP1.ogg",
+			AnimeID: "frieren", AnimeNameRU: "Фрирен", Franchise: "frieren", Year: 2023},
+	}}
+	return NewThemeService(newFakeModeRepo(), pool, fixedClock{"2026-06-15"}, rs, &fakeModeStats{}, nil)
+}
+
+func TestCompareTheme_FranchiseAndYear(t *testing.T) {
+	secret := domain.PoolTheme{AnimeID: "jjk2", Franchise: "jujutsu_kaisen", Year: 2023}
+	got := CompareTheme(secret, domain.ThemeAnime{ID: "jjk1", Franchise: "jujutsu_kaisen", Year: 2020})
+	assert.Equal(t, domain.MatchCorrect, got.Franchise.Status)
+	assert.Equal(t, domain.HintHigher, got.Year.Hint)
+
+	got = CompareTheme(secret, domain.ThemeAnime{ID: "frieren", Franchise: "frieren", Year: 2023})
+	assert.Equal(t, domain.MatchWrong, got.Franchise.Status)
+	assert.Equal(t, domain.MatchCorrect, got.Year.Status)
+}
+
+func TestThemeMode_SnippetGrowsWithWrongGuesses(t *testing.T) {
+	rs := newFakeResultStore()
+	svc := newThemeSvc(rs)
+	ctx := context.Background()
+
+	st, err := svc.Resume(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "OP", st.ThemeType)
+	assert.Equal(t, 0, st.Step)
+	assert.Equal(t, snippetSeconds[0], st.SnippetSeconds)
+
+	// asking for a longer snippet than unlocked is capped
+	audio, err := svc.Audio(ctx, "u1", 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(snippetSeconds[0])*snippetBytesPerSecond, audio.MaxBytes)
+
+	rs.results[key("u1", "2026-06-15", domain.ModeTheme)] = &domain.UserGameResult{
+		UserID: "u1", PuzzleDate: "2026-06-15", Mode: domain.ModeTheme, Guesses: []string{"x", "y"}}
+	st, err = svc.Resume(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, st.Step)
+	audio, err = svc.Audio(ctx, "u1", 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(snippetSeconds[2])*snippetBytesPerSecond, audio.MaxBytes)
+}
+
+func TestThemeMode_SolveRevealsWholeTrack(t *testing.T) {
+	rs := newFakeResultStore()
+	svc := newThemeSvc(rs)
+	ctx := context.Background()
+
+	out, err := svc.Guess(ctx, "u1", "frieren")
+	require.NoError(t, err)
+	assert.True(t, out.Solved)
+	require.NotNil(t, out.Answer)
+	assert.Equal(t, "OP1", out.Answer.Slug)
+
+	audio, err := svc.Audio(ctx, "u1", 0)
+	require.NoError(t, err)
+	assert.Zero(t, audio.MaxBytes, "a finished game streams the whole track")
+	assert.Equal(t, "SousouNoFrieren-OP1.ogg", audio.Basename)
+
+	st, err := svc.Resume(ctx, "u1")
+	require.NoError(t, err)
+	assert.Zero(t, st.SnippetSeconds)
+	require.NotNil(t, st.Answer)
+}
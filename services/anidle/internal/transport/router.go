@@ -13,7 +13,7 @@ import (
 	"github.com/ILITA-hub/animeenigma/services/anidle/internal/handler"
 )
 
-func NewRouter(healthHandler *handler.HealthHandler, anidleHandler *handler.AnidleHandler, jwtCfg authz.JWTConfig, log *logger.Logger, mc *metrics.Collector) http.Handler {
+func NewRouter(healthHandler *handler.HealthHandler, anidleHandler *handler.AnidleHandler, modesHandler *handler.ModesHandler, challengeHandler *handler.ChallengeHandler, accountHandler *handler.AccountInternalHandler, jwtCfg authz.JWTConfig, log *logger.Logger, mc *metrics.Collector) http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
@@ -27,16 +27,43 @@ func NewRouter(healthHandler *handler.HealthHandler, anidleHandler *handler.Anid
 		metrics.Handler().ServeHTTP(w, r)
 	})
 
+	// Account export / erasure fan-out from auth. Docker-network only.
+	if accountHandler != nil {
+		r.Post("/internal/account/export", accountHandler.Export)
+		r.Post("/internal/account/erase", accountHandler.Erase)
+	}
+
 	r.Route("/api/anidle", func(r chi.Router) {
 		r.Use(OptionalAuthMiddleware(jwtCfg))
 		r.Get("/daily", anidleHandler.DailyMeta)
 		r.Post("/daily/guess", anidleHandler.DailyGuess)
 		r.Post("/daily/giveup", anidleHandler.DailyGiveUp)
+		r.Post("/daily/hint", anidleHandler.DailyHint)
+		r.Get("/daily/hint/poster", anidleHandler.DailyHintPoster)
 		r.Get("/search", anidleHandler.Search)
 		r.Post("/endless/new", anidleHandler.EndlessNew)
 		r.Post("/endless/guess", anidleHandler.EndlessGuess)
 		r.Get("/stats", anidleHandler.Stats)
 		r.Get("/leaderboard", anidleHandler.Leaderboard)
+
+		// Character and OP/ED modes — own daily secret, stats and board.
+		r.Get("/character", modesHandler.CharacterMeta)
+		r.Post("/character/guess", modesHandler.CharacterGuess)
+		r.Post("/character/giveup", modesHandler.CharacterGiveUp)
+		r.Get("/character/search", modesHandler.CharacterSearch)
+		r.Get("/theme", modesHandler.ThemeMeta)
+		r.Post("/theme/guess", modesHandler.ThemeGuess)
+		r.Post("/theme/giveup", modesHandler.ThemeGiveUp)
+		r.Get("/theme/search", modesHandler.ThemeSearch)
+		r.Get("/theme/audio", modesHandler.ThemeAudio)
+
+		// User-made challenges, shared by id; playing requires login.
+		r.Post("/challenges", challengeHandler.Create)
+		r.Get("/challenges", challengeHandler.ListMine)
+		r.Get("/challenges/{id}", challengeHandler.Get)
+		r.Post("/challenges/{id}/guess", challengeHandler.Guess)
+		r.Post("/challenges/{id}/giveup", challengeHandler.GiveUp)
+		r.Get("/challenges/{id}/leaderboard", challengeHandler.Leaderboard)
 	})
 
 	return r
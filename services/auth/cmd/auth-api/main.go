@@ -66,7 +66,7 @@ func main() {
 	}
 
 	// Auto-migrate schema
-	if err := db.AutoMigrate(&domain.User{}, &domain.UserSession{}, &domain.AuthCA{}, &domain.UserCertificate{}, &domain.WebAuthnCredential{}); err != nil {
+	if err := db.AutoMigrate(&domain.User{}, &domain.UserSession{}, &domain.AuthCA{}, &domain.UserCertificate{}, &domain.WebAuthnCredential{}, &domain.AccountDeletion{}, &domain.AccountDeletionStep{}); err != nil {
 		log.Fatalw("failed to migrate database", "error", err)
 	}
 
@@ -105,6 +105,7 @@ func main() {
 	sessionRepo := repo.NewSessionRepository(db.DB)
 	certRepo := repo.NewCertRepository(db.DB)
 	passkeyRepo := repo.NewPasskeyRepository(db.DB)
+	accountRepo := repo.NewAccountRepository(db.DB)
 
 	// Initialize services
 	authService := service.NewAuthService(userRepo, sessionRepo, redisCache, cfg.JWT, cfg.Telegram.BotToken, cfg.GuestTokenTTL, log)
@@ -120,6 +121,10 @@ func main() {
 		log.Fatalw("init passkey service", "error", err)
 	}
 
+	// Account export + deletion fan-out to every service holding user data.
+	accountService := service.NewAccountService(accountRepo, userRepo, service.AccountPeersFromConfig(cfg.AccountPeers),
+		&http.Client{Transport: tracing.WrapTransport(nil)}, log)
+
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService, cfg.Cookie, cfg.Telegram, log)
 	telegramBotHandler := handler.NewTelegramBotHandler(authService, cfg.Telegram.BotToken, cfg.Telegram.WebhookSecret, log)
@@ -130,12 +135,13 @@ func main() {
 	adminUsersHandler := handler.NewAdminUsersHandler(userRepo, log)
 	passkeyHandler := handler.NewPasskeyHandler(passkeyService, authService, authHandler, log)
 	certHandler := handler.NewCertHandler(certService, authService, userService, authHandler, log)
+	accountHandler := handler.NewAccountHandler(accountService, log)
 
 	// Initialize metrics collector
 	metricsCollector := metrics.NewCollector("auth")
 
 	// Initialize router
-	router := transport.NewRouter(authHandler, telegramBotHandler, userHandler, sessionsHandler, magicLinkHandler, userResolveHandler, adminUsersHandler, passkeyHandler, certHandler, cfg.JWT, log, metricsCollector)
+	router := transport.NewRouter(authHandler, telegramBotHandler, userHandler, sessionsHandler, magicLinkHandler, userResolveHandler, adminUsersHandler, passkeyHandler, certHandler, accountHandler, cfg.JWT, log, metricsCollector)
 
 	// Register Telegram webhook (warn on failure, don't block startup)
 	if cfg.Telegram.BotToken != "" && cfg.Telegram.WebhookURL != "" {
@@ -161,7 +167,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	// shutdown is closed when SIGINT is received — long-lived goroutines
-	// (session cleanup) listen for it.
+	// (session cleanup, account deletion) listen for it.
 	shutdown := make(chan struct{})
 
 	// Periodic session cleanup — drops rows revoked or expired >7 days ago.
@@ -185,6 +191,28 @@ func main() {
 		}
 	}()
 
+	// Account deletion worker — runs due erase steps (with backoff) and
+	// hard-deletes users whose every service has confirmed the erase.
+	go func() {
+		t := time.NewTicker(time.Minute)
+		defer t.Stop()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-t.C:
+				n, err := accountService.ProcessDue(context.Background())
+				if err != nil {
+					log.Warnw("account deletion run failed", "error", err)
+					continue
+				}
+				if n > 0 {
+					log.Infow("account deletions completed", "completed", n)
+				}
+			}
+		}
+	}()
+
 	// Start server
 	go func() {
 		log.Infow("starting auth service", "address", cfg.Server.Address())
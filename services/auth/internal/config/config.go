@@ -34,6 +34,24 @@ type Config struct {
 
 	// WebAuthn configures the passkey (FIDO2/WebAuthn) relying party.
 	WebAuthn WebAuthnConfig
+
+	// AccountPeers are the internal base URLs of the services holding user
+	// data, called for a data export and fanned out to on account deletion.
+	AccountPeers AccountPeersConfig
+}
+
+// AccountPeersConfig holds the Docker-network base URLs of every service the
+// account export / deletion fan-out reaches (/internal/account/* — analytics
+// exposes only /internal/erase).
+type AccountPeersConfig struct {
+	Player        string
+	Notifications string
+	Gacha         string
+	Anidle        string
+	Fanfic        string
+	WatchTogether string
+	Themes        string
+	Analytics     string
 }
 
 // WebAuthnConfig configures the passkey relying party identity. RPID is the
@@ -113,6 +131,16 @@ func Load() (*Config, error) {
 			RPID:      getEnv("WEBAUTHN_RP_ID", "animeenigma.org"),
 			RPOrigins: parseRPOrigins(getEnv("WEBAUTHN_RP_ORIGINS", "https://animeenigma.org")),
 		},
+		AccountPeers: AccountPeersConfig{
+			Player:        getEnv("PLAYER_INTERNAL_URL", "http://player:8083"),
+			Notifications: getEnv("NOTIFICATIONS_INTERNAL_URL", "http://notifications:8090"),
+			Gacha:         getEnv("GACHA_INTERNAL_URL", "http://gacha:8093"),
+			Anidle:        getEnv("ANIDLE_INTERNAL_URL", "http://anidle:8095"),
+			Fanfic:        getEnv("FANFIC_INTERNAL_URL", "http://fanfic:8097"),
+			WatchTogether: getEnv("WATCH_TOGETHER_INTERNAL_URL", "http://watch-together:8091"),
+			Themes:        getEnv("THEMES_INTERNAL_URL", "http://themes:8086"),
+			Analytics:     getEnv("ANALYTICS_INTERNAL_URL", "http://analytics:8092"),
+		},
 	}, nil
 }
 
@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+// Account deletion lifecycle. A deletion request soft-deletes the User and
+// revokes every session at once; the per-service erase fan-out then runs in
+// the background (one AccountDeletionStep per peer, retried with backoff)
+// and the User row plus its auth-owned rows are hard-deleted only once every
+// step has succeeded.
+const (
+	AccountDeletionPending   = "pending"
+	AccountDeletionCompleted = "completed"
+
+	AccountDeletionStepPending = "pending"
+	AccountDeletionStepDone    = "done"
+)
+
+// AccountDeletion is one user-initiated account deletion. Username is kept
+// only while the fan-out runs (anidle's leaderboards are keyed by it) and is
+// blanked on completion.
+type AccountDeletion struct {
+	ID          string                `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID      string                `gorm:"type:uuid;not null;uniqueIndex" json:"user_id"`
+	Username    string                `gorm:"size:32;not null;default:''" json:"-"`
+	Status      string                `gorm:"size:20;not null;index" json:"status"`
+	RequestedAt time.Time             `gorm:"not null" json:"requested_at"`
+	CompletedAt *time.Time            `json:"completed_at,omitempty"`
+	Steps       []AccountDeletionStep `gorm:"foreignKey:DeletionID" json:"steps"`
+}
+
+func (AccountDeletion) TableName() string { return "account_deletions" }
+
+// AccountDeletionStep tracks the erase call to one peer service.
+type AccountDeletionStep struct {
+	DeletionID    string     `gorm:"type:uuid;primaryKey" json:"-"`
+	Service       string     `gorm:"size:32;primaryKey" json:"service"`
+	Status        string     `gorm:"size:20;not null" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	LastError     string     `gorm:"type:text;not null;default:''" json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `gorm:"not null;index" json:"next_attempt_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+func (AccountDeletionStep) TableName() string { return "account_deletion_steps" }
+
+// DeleteAccountRequest confirms POST /api/auth/account/delete by retyping the
+// username.
+type DeleteAccountRequest struct {
+	ConfirmUsername string `json:"confirm_username"`
+}
+
+// AccountExportManifest is manifest.json at the root of the export archive.
+// One entry per source; a failed peer is listed with its error instead of
+// failing the whole export.
+type AccountExportManifest struct {
+	FormatVersion int                    `json:"format_version"`
+	UserID        string                 `json:"user_id"`
+	GeneratedAt   time.Time              `json:"generated_at"`
+	Services      []AccountExportService `json:"services"`
+}
+
+// AccountExport* source statuses.
+const (
+	AccountExportOK            = "ok"
+	AccountExportFailed        = "failed"
+	AccountExportNotExportable = "not_exportable"
+)
+
+// AccountExportService is one manifest entry.
+type AccountExportService struct {
+	Service string `json:"service"`
+	Status  string `json:"status"`
+	File    string `json:"file,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Note    string `json:"note,omitempty"`
+}
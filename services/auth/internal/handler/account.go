@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/auth/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/auth/internal/service"
+)
+
+// AccountHandler serves the user's data export and account deletion:
+//
+//	GET  /api/auth/account/export    — zip archive of everything we hold
+//	POST /api/auth/account/delete    — start deletion ({"confirm_username"})
+//	GET  /api/auth/account/deletion  — per-service deletion progress
+//	GET  /api/admin/users/{id}/deletion — same, for operators
+type AccountHandler struct {
+	accounts *service.AccountService
+	log      *logger.Logger
+}
+
+func NewAccountHandler(accounts *service.AccountService, log *logger.Logger) *AccountHandler {
+	return &AccountHandler{accounts: accounts, log: log}
+}
+
+// Export streams the export archive as a download.
+func (h *AccountHandler) Export(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok {
+		httputil.Unauthorized(w)
+		return
+	}
+	archive, err := h.accounts.Export(r.Context(), claims.UserID)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	name := fmt.Sprintf("animeenigma-export-%s.zip", time.Now().UTC().Format("2006-01-02"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	w.Header().Set("Content-Length", strconv.Itoa(len(archive)))
+	_, _ = w.Write(archive)
+}
+
+// RequestDeletion starts the account deletion. 202 — the erase fan-out
+// completes in the background.
+func (h *AccountHandler) RequestDeletion(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok {
+		httputil.Unauthorized(w)
+		return
+	}
+	var req domain.DeleteAccountRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	d, err := h.accounts.RequestDeletion(r.Context(), claims.UserID, req.ConfirmUsername)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.JSON(w, http.StatusAccepted, d)
+}
+
+// GetDeletion returns the caller's deletion progress.
+func (h *AccountHandler) GetDeletion(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok {
+		httputil.Unauthorized(w)
+		return
+	}
+	d, err := h.accounts.GetDeletion(r.Context(), claims.UserID)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, d)
+}
+
+// AdminGetDeletion returns a user's deletion progress (admin only).
+func (h *AccountHandler) AdminGetDeletion(w http.ResponseWriter, r *http.Request) {
+	d, err := h.accounts.GetDeletion(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, d)
+}
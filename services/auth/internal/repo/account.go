@@ -0,0 +1,174 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	liberrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/auth/internal/domain"
+	"gorm.io/gorm"
+)
+
+// AccountRepository persists account deletions and reads the auth-owned
+// slice of a data export.
+type AccountRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountRepository(db *gorm.DB) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+// AuthExport is the auth service's own slice of a data export. Secrets
+// (password / API-key hashes, refresh-token hashes, key material) are
+// excluded by the domain types' json:"-" tags.
+type AuthExport struct {
+	User         *domain.User                `json:"user"`
+	Sessions     []domain.UserSession        `json:"sessions"`
+	Passkeys     []domain.WebAuthnCredential `json:"passkeys"`
+	Certificates []domain.UserCertificate    `json:"certificates"`
+}
+
+// ExportAuthData reads the user's auth rows.
+func (r *AccountRepository) ExportAuthData(ctx context.Context, userID string) (*AuthExport, error) {
+	out := &AuthExport{
+		Sessions:     []domain.UserSession{},
+		Passkeys:     []domain.WebAuthnCredential{},
+		Certificates: []domain.UserCertificate{},
+	}
+	var user domain.User
+	if err := r.db.WithContext(ctx).First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, liberrors.NotFound("user")
+		}
+		return nil, fmt.Errorf("export user: %w", err)
+	}
+	out.User = &user
+	db := r.db.WithContext(ctx)
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&out.Sessions).Error; err != nil {
+		return nil, fmt.Errorf("export sessions: %w", err)
+	}
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&out.Passkeys).Error; err != nil {
+		return nil, fmt.Errorf("export passkeys: %w", err)
+	}
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&out.Certificates).Error; err != nil {
+		return nil, fmt.Errorf("export certificates: %w", err)
+	}
+	return out, nil
+}
+
+// CreateDeletion records the deletion + its steps, soft-deletes the user,
+// scrubs the login credentials (so a Telegram re-signup or a leaked API key
+// can't reach the tombstoned row) and revokes every session — all in one
+// transaction. Returns the existing deletion instead if one is already
+// recorded for the user.
+func (r *AccountRepository) CreateDeletion(ctx context.Context, d *domain.AccountDeletion) (*domain.AccountDeletion, error) {
+	var out *domain.AccountDeletion
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing domain.AccountDeletion
+		err := tx.Preload("Steps").First(&existing, "user_id = ?", d.UserID).Error
+		if err == nil {
+			out = &existing
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("get account deletion: %w", err)
+		}
+		if err := tx.Create(d).Error; err != nil {
+			return fmt.Errorf("create account deletion: %w", err)
+		}
+		if err := tx.Model(&domain.User{}).Where("id = ?", d.UserID).Updates(map[string]interface{}{
+			"password_hash": "",
+			"api_key_hash":  nil,
+			"telegram_id":   nil,
+		}).Error; err != nil {
+			return fmt.Errorf("scrub user credentials: %w", err)
+		}
+		if err := tx.Delete(&domain.User{}, "id = ?", d.UserID).Error; err != nil {
+			return fmt.Errorf("soft-delete user: %w", err)
+		}
+		if err := tx.Model(&domain.UserSession{}).
+			Where("user_id = ? AND revoked_at IS NULL", d.UserID).
+			Update("revoked_at", d.RequestedAt).Error; err != nil {
+			return fmt.Errorf("revoke sessions: %w", err)
+		}
+		out = d
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDeletionByUser returns the user's deletion with its steps.
+func (r *AccountRepository) GetDeletionByUser(ctx context.Context, userID string) (*domain.AccountDeletion, error) {
+	var d domain.AccountDeletion
+	err := r.db.WithContext(ctx).Preload("Steps").First(&d, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, liberrors.NotFound("account deletion")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get account deletion: %w", err)
+	}
+	return &d, nil
+}
+
+// ListPendingDeletions returns pending deletions (with steps), oldest first.
+func (r *AccountRepository) ListPendingDeletions(ctx context.Context, limit int) ([]domain.AccountDeletion, error) {
+	var out []domain.AccountDeletion
+	err := r.db.WithContext(ctx).
+		Preload("Steps").
+		Where("status = ?", domain.AccountDeletionPending).
+		Order("requested_at").
+		Limit(limit).
+		Find(&out).Error
+	if err != nil {
+		return nil, fmt.Errorf("list pending account deletions: %w", err)
+	}
+	return out, nil
+}
+
+// SaveStep persists a step's attempt bookkeeping.
+func (r *AccountRepository) SaveStep(ctx context.Context, step *domain.AccountDeletionStep) error {
+	err := r.db.WithContext(ctx).
+		Model(&domain.AccountDeletionStep{}).
+		Where("deletion_id = ? AND service = ?", step.DeletionID, step.Service).
+		Updates(map[string]interface{}{
+			"status":          step.Status,
+			"attempts":        step.Attempts,
+			"last_error":      step.LastError,
+			"next_attempt_at": step.NextAttemptAt,
+			"completed_at":    step.CompletedAt,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("save account deletion step: %w", err)
+	}
+	return nil
+}
+
+// CompleteDeletion hard-deletes the user and every auth-owned row, then
+// marks the deletion completed and forgets the username.
+func (r *AccountRepository) CompleteDeletion(ctx context.Context, d *domain.AccountDeletion, now time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&domain.UserSession{}, &domain.WebAuthnCredential{}, &domain.UserCertificate{}} {
+			if err := tx.Where("user_id = ?", d.UserID).Delete(model).Error; err != nil {
+				return fmt.Errorf("delete auth rows: %w", err)
+			}
+		}
+		if err := tx.Unscoped().Delete(&domain.User{}, "id = ?", d.UserID).Error; err != nil {
+			return fmt.Errorf("hard-delete user: %w", err)
+		}
+		err := tx.Model(&domain.AccountDeletion{}).Where("id = ?", d.ID).Updates(map[string]interface{}{
+			"status":       domain.AccountDeletionCompleted,
+			"completed_at": now,
+			"username":     "",
+		}).Error
+		if err != nil {
+			return fmt.Errorf("complete account deletion: %w", err)
+		}
+		return nil
+	})
+}
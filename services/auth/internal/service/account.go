@@ -0,0 +1,361 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/auth/internal/config"
+	"github.com/ILITA-hub/animeenigma/services/auth/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/auth/internal/repo"
+)
+
+const (
+	// accountPeerTimeout bounds one export / erase call to a peer. Export
+	// calls run concurrently, so the whole archive stays well inside the
+	// server's 15s WriteTimeout.
+	accountPeerTimeout = 10 * time.Second
+	// accountStepBackoffBase / Max shape the erase retry schedule:
+	// 30s, 1m, 2m, … capped at 6h. Steps retry until they succeed.
+	accountStepBackoffBase = 30 * time.Second
+	accountStepBackoffMax  = 6 * time.Hour
+	// accountDeletionBatch caps the pending deletions one worker tick walks.
+	accountDeletionBatch = 50
+)
+
+// analyticsNote explains the analytics manifest entry: its data is erased on
+// deletion but never part of an export.
+const analyticsNote = "pseudonymous product telemetry, kept 90 days; erased on account deletion, not exported"
+
+// AccountPeer is one service holding user data. ExportPath is empty for
+// erase-only peers.
+type AccountPeer struct {
+	Name       string
+	BaseURL    string
+	ExportPath string
+	ErasePath  string
+	Note       string
+}
+
+// AccountPeersFromConfig lists every peer of the export / deletion fan-out.
+func AccountPeersFromConfig(c config.AccountPeersConfig) []AccountPeer {
+	peer := func(name, base string) AccountPeer {
+		return AccountPeer{Name: name, BaseURL: base, ExportPath: "/internal/account/export", ErasePath: "/internal/account/erase"}
+	}
+	return []AccountPeer{
+		peer("player", c.Player),
+		peer("notifications", c.Notifications),
+		peer("gacha", c.Gacha),
+		peer("anidle", c.Anidle),
+		peer("fanfic", c.Fanfic),
+		peer("watch-together", c.WatchTogether),
+		peer("themes", c.Themes),
+		{Name: "analytics", BaseURL: c.Analytics, ErasePath: "/internal/erase", Note: analyticsNote},
+	}
+}
+
+// accountStore is satisfied by *repo.AccountRepository.
+type accountStore interface {
+	ExportAuthData(ctx context.Context, userID string) (*repo.AuthExport, error)
+	CreateDeletion(ctx context.Context, d *domain.AccountDeletion) (*domain.AccountDeletion, error)
+	GetDeletionByUser(ctx context.Context, userID string) (*domain.AccountDeletion, error)
+	ListPendingDeletions(ctx context.Context, limit int) ([]domain.AccountDeletion, error)
+	SaveStep(ctx context.Context, step *domain.AccountDeletionStep) error
+	CompleteDeletion(ctx context.Context, d *domain.AccountDeletion, now time.Time) error
+}
+
+// AccountService builds data-export archives and drives account deletion.
+type AccountService struct {
+	store  accountStore
+	users  userByIDGetter
+	peers  []AccountPeer
+	client *http.Client
+	log    *logger.Logger
+	now    func() time.Time
+}
+
+func NewAccountService(store accountStore, users userByIDGetter, peers []AccountPeer, client *http.Client, log *logger.Logger) *AccountService {
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &AccountService{store: store, users: users, peers: peers, client: client, log: log, now: time.Now}
+}
+
+// peerResult is the outcome of one export call.
+type peerResult struct {
+	data json.RawMessage
+	err  error
+}
+
+// Export builds the zip archive of everything the platform holds on the
+// user: manifest.json, auth.json and one <service>.json per peer. A failing
+// peer is recorded in the manifest instead of failing the export.
+func (s *AccountService) Export(ctx context.Context, userID string) ([]byte, error) {
+	authData, err := s.store.ExportAuthData(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]peerResult, len(s.peers))
+	var wg sync.WaitGroup
+	for i, p := range s.peers {
+		if p.ExportPath == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, p AccountPeer) {
+			defer wg.Done()
+			data, err := s.callPeer(ctx, p.BaseURL+p.ExportPath, map[string]string{"user_id": userID})
+			results[i] = peerResult{data: data, err: err}
+		}(i, p)
+	}
+	wg.Wait()
+
+	manifest := domain.AccountExportManifest{
+		FormatVersion: 1,
+		UserID:        userID,
+		GeneratedAt:   s.now().UTC(),
+		Services:      []domain.AccountExportService{{Service: "auth", Status: domain.AccountExportOK, File: "auth.json"}},
+	}
+	files := map[string][]byte{}
+	if files["auth.json"], err = json.MarshalIndent(authData, "", "  "); err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to encode auth export")
+	}
+	for i, p := range s.peers {
+		entry := domain.AccountExportService{Service: p.Name, Note: p.Note}
+		switch {
+		case p.ExportPath == "":
+			entry.Status = domain.AccountExportNotExportable
+		case results[i].err != nil:
+			entry.Status = domain.AccountExportFailed
+			entry.Error = results[i].err.Error()
+			s.log.Warnw("account export peer failed", "user_id", userID, "service", p.Name, "error", results[i].err)
+		default:
+			entry.Status = domain.AccountExportOK
+			entry.File = p.Name + ".json"
+			var pretty bytes.Buffer
+			if json.Indent(&pretty, results[i].data, "", "  ") != nil {
+				pretty.Reset()
+				pretty.Write(results[i].data)
+			}
+			files[entry.File] = pretty.Bytes()
+		}
+		manifest.Services = append(manifest.Services, entry)
+	}
+	if files["manifest.json"], err = json.MarshalIndent(manifest, "", "  "); err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to encode export manifest")
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range exportFileOrder(manifest) {
+		f, err := zw.Create(name)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.CodeInternal, "failed to write export archive")
+		}
+		if _, err := f.Write(files[name]); err != nil {
+			return nil, errors.Wrap(err, errors.CodeInternal, "failed to write export archive")
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to write export archive")
+	}
+	return buf.Bytes(), nil
+}
+
+// exportFileOrder lists the archive entries: manifest first, then the
+// per-service files in manifest order.
+func exportFileOrder(m domain.AccountExportManifest) []string {
+	names := []string{"manifest.json"}
+	for _, e := range m.Services {
+		if e.File != "" {
+			names = append(names, e.File)
+		}
+	}
+	return names
+}
+
+// RequestDeletion starts the deletion of the user's account. The caller must
+// retype their username. The user is soft-deleted and logged out everywhere
+// immediately; the erase fan-out runs on the background worker. Repeating
+// the request returns the deletion already in progress.
+func (s *AccountService) RequestDeletion(ctx context.Context, userID, confirmUsername string) (*domain.AccountDeletion, error) {
+	if existing, err := s.store.GetDeletionByUser(ctx, userID); err == nil {
+		return existing, nil
+	} else if appErr, ok := errors.IsAppError(err); !ok || appErr.Code != errors.CodeNotFound {
+		return nil, err
+	}
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(confirmUsername) != user.Username {
+		return nil, errors.InvalidInput("confirm_username does not match your username")
+	}
+
+	now := s.now()
+	d := &domain.AccountDeletion{
+		ID:          uuid.NewString(),
+		UserID:      userID,
+		Username:    user.Username,
+		Status:      domain.AccountDeletionPending,
+		RequestedAt: now,
+	}
+	for _, p := range s.peers {
+		d.Steps = append(d.Steps, domain.AccountDeletionStep{
+			DeletionID:    d.ID,
+			Service:       p.Name,
+			Status:        domain.AccountDeletionStepPending,
+			NextAttemptAt: now,
+		})
+	}
+	created, err := s.store.CreateDeletion(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	s.log.Infow("account deletion requested", "user_id", userID, "deletion_id", created.ID)
+	return created, nil
+}
+
+// GetDeletion returns the user's deletion with per-service progress.
+func (s *AccountService) GetDeletion(ctx context.Context, userID string) (*domain.AccountDeletion, error) {
+	return s.store.GetDeletionByUser(ctx, userID)
+}
+
+// ProcessDue runs every due erase step of the pending deletions and
+// hard-deletes the users whose steps have all succeeded. Returns how many
+// deletions completed. Called by the background worker.
+func (s *AccountService) ProcessDue(ctx context.Context) (int, error) {
+	pending, err := s.store.ListPendingDeletions(ctx, accountDeletionBatch)
+	if err != nil {
+		return 0, err
+	}
+	completed := 0
+	for i := range pending {
+		d := &pending[i]
+		if s.runSteps(ctx, d) {
+			if err := s.store.CompleteDeletion(ctx, d, s.now()); err != nil {
+				s.log.Errorw("account deletion finalize failed", "deletion_id", d.ID, "error", err)
+				continue
+			}
+			s.log.Infow("account deletion completed", "user_id", d.UserID, "deletion_id", d.ID)
+			completed++
+		}
+	}
+	return completed, nil
+}
+
+// runSteps attempts every due step of d and reports whether all its steps
+// are done.
+func (s *AccountService) runSteps(ctx context.Context, d *domain.AccountDeletion) bool {
+	peers := make(map[string]AccountPeer, len(s.peers))
+	for _, p := range s.peers {
+		peers[p.Name] = p
+	}
+	allDone := true
+	for i := range d.Steps {
+		step := &d.Steps[i]
+		if step.Status == domain.AccountDeletionStepDone {
+			continue
+		}
+		now := s.now()
+		if step.NextAttemptAt.After(now) {
+			allDone = false
+			continue
+		}
+		p, ok := peers[step.Service]
+		var err error
+		if !ok {
+			err = fmt.Errorf("unknown service %q", step.Service)
+		} else {
+			_, err = s.callPeer(ctx, p.BaseURL+p.ErasePath, map[string]string{"user_id": d.UserID, "username": d.Username})
+		}
+		step.Attempts++
+		if err == nil {
+			step.Status = domain.AccountDeletionStepDone
+			step.LastError = ""
+			step.CompletedAt = &now
+		} else {
+			allDone = false
+			step.LastError = err.Error()
+			step.NextAttemptAt = now.Add(accountStepBackoff(step.Attempts))
+			s.log.Warnw("account erase step failed", "deletion_id", d.ID, "service", step.Service,
+				"attempts", step.Attempts, "next_attempt_at", step.NextAttemptAt, "error", err)
+		}
+		if err := s.store.SaveStep(ctx, step); err != nil {
+			s.log.Errorw("account erase step save failed", "deletion_id", d.ID, "service", step.Service, "error", err)
+			allDone = false
+		}
+	}
+	return allDone
+}
+
+// accountStepBackoff is the delay before retry n+1 after n failed attempts.
+func accountStepBackoff(attempts int) time.Duration {
+	d := accountStepBackoffBase
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= accountStepBackoffMax {
+			return accountStepBackoffMax
+		}
+	}
+	return d
+}
+
+// peerEnvelope is the libs/httputil response envelope peers reply with.
+type peerEnvelope struct {
+	Data  json.RawMessage `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// callPeer POSTs body to url and returns the envelope's data field.
+func (s *AccountService) callPeer(ctx context.Context, url string, body interface{}) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, accountPeerTimeout)
+	defer cancel()
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var env peerEnvelope
+	_ = json.Unmarshal(raw, &env)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if env.Error != nil && env.Error.Message != "" {
+			return nil, fmt.Errorf("status %d: %s", resp.StatusCode, env.Error.Message)
+		}
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	if len(env.Data) == 0 {
+		return json.RawMessage("null"), nil
+	}
+	return env.Data, nil
+}
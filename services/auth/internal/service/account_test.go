@@ -0,0 +1,230 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/auth/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/auth/internal/repo"
+)
+
+// fakePeer is an httptest peer service recording the bodies it was called
+// with; fail makes every call answer 503.
+type fakePeer struct {
+	mu    sync.Mutex
+	srv   *httptest.Server
+	calls []map[string]string
+	fail  bool
+}
+
+func newFakePeer(t *testing.T, exportData interface{}) *fakePeer {
+	p := &fakePeer{}
+	p.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		p.mu.Lock()
+		p.calls = append(p.calls, body)
+		fail := p.fail
+		p.mu.Unlock()
+		if fail {
+			httputil.Error(w, context.DeadlineExceeded)
+			return
+		}
+		if r.URL.Path == "/internal/account/export" {
+			httputil.OK(w, exportData)
+			return
+		}
+		httputil.OK(w, map[string]string{"status": "erased"})
+	}))
+	t.Cleanup(p.srv.Close)
+	return p
+}
+
+func (p *fakePeer) setFail(v bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fail = v
+}
+
+// newAccountService builds an AccountService over the SQLite users +
+// user_sessions tables of newUserServiceWithSessions plus the remaining
+// auth-owned tables, with player / gacha / analytics peers.
+func newAccountService(t *testing.T) (*AccountService, *gorm.DB, map[string]*fakePeer) {
+	t.Helper()
+	_, db := newUserServiceWithSessions(t)
+	require.NoError(t, db.Exec(
+		`CREATE TABLE webauthn_credentials (
+			id text primary key, user_id text, credential_id text, public_key blob, sign_count integer,
+			transports text, aaguid blob, backup_eligible integer, backup_state integer,
+			name text, created_at datetime, last_used_at datetime
+		)`,
+	).Error)
+	require.NoError(t, db.Exec(
+		`CREATE TABLE user_certificates (
+			id text primary key, user_id text, name text, fingerprint_sha256 text, serial text,
+			not_after datetime, created_at datetime, last_used_at datetime, revoked_at datetime
+		)`,
+	).Error)
+	require.NoError(t, db.AutoMigrate(&domain.AccountDeletion{}, &domain.AccountDeletionStep{}))
+
+	peers := map[string]*fakePeer{
+		"player":    newFakePeer(t, map[string]interface{}{"tables": map[string]interface{}{"anime_list": []interface{}{map[string]string{"anime_id": "a1"}}}}),
+		"gacha":     newFakePeer(t, nil),
+		"analytics": newFakePeer(t, nil),
+	}
+	list := []AccountPeer{
+		{Name: "player", BaseURL: peers["player"].srv.URL, ExportPath: "/internal/account/export", ErasePath: "/internal/account/erase"},
+		{Name: "gacha", BaseURL: peers["gacha"].srv.URL, ExportPath: "/internal/account/export", ErasePath: "/internal/account/erase"},
+		{Name: "analytics", BaseURL: peers["analytics"].srv.URL, ErasePath: "/internal/erase", Note: analyticsNote},
+	}
+	svc := NewAccountService(repo.NewAccountRepository(db), repo.NewUserRepository(db), list, nil, logger.Default())
+	return svc, db, peers
+}
+
+func TestAccountExport_ArchivesEveryServiceAndRecordsFailures(t *testing.T) {
+	svc, db, peers := newAccountService(t)
+	ctx := context.Background()
+	userID := seedUserWithPassword(t, db, "oldpass123")
+	seedSession(t, db, "sess-1", userID)
+	peers["gacha"].setFail(true)
+
+	archive, err := svc.Export(ctx, userID)
+	require.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+	files := map[string][]byte{}
+	var names []string
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		b, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		_ = rc.Close()
+		files[f.Name] = b
+		names = append(names, f.Name)
+	}
+	require.Equal(t, []string{"manifest.json", "auth.json", "player.json"}, names)
+
+	var manifest domain.AccountExportManifest
+	require.NoError(t, json.Unmarshal(files["manifest.json"], &manifest))
+	require.Equal(t, userID, manifest.UserID)
+	statuses := map[string]string{}
+	for _, s := range manifest.Services {
+		statuses[s.Service] = s.Status
+		if s.Service == "gacha" {
+			require.NotEmpty(t, s.Error)
+		}
+		if s.Service == "analytics" {
+			require.Equal(t, analyticsNote, s.Note)
+		}
+	}
+	require.Equal(t, map[string]string{
+		"auth":      domain.AccountExportOK,
+		"player":    domain.AccountExportOK,
+		"gacha":     domain.AccountExportFailed,
+		"analytics": domain.AccountExportNotExportable,
+	}, statuses)
+
+	require.Contains(t, string(files["player.json"]), `"anime_id": "a1"`)
+	require.Contains(t, string(files["auth.json"]), `"sessions"`)
+	require.NotContains(t, string(files["auth.json"]), "password")
+	require.NotContains(t, string(files["auth.json"]), "hash_sess-1")
+	require.Empty(t, peers["analytics"].calls, "erase-only peer must not be called on export")
+}
+
+func TestAccountDeletion_RetriesFailedStepsThenHardDeletes(t *testing.T) {
+	svc, db, peers := newAccountService(t)
+	ctx := context.Background()
+	userID := seedUserWithPassword(t, db, "oldpass123")
+	username := "user_" + userID[:8]
+	seedSession(t, db, "sess-1", userID)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	_, err := svc.RequestDeletion(ctx, userID, "someone_else")
+	require.Error(t, err)
+
+	d, err := svc.RequestDeletion(ctx, userID, username)
+	require.NoError(t, err)
+	require.Equal(t, domain.AccountDeletionPending, d.Status)
+	require.Len(t, d.Steps, 3)
+
+	// Logged out and tombstoned immediately.
+	require.Empty(t, aliveSessionIDs(t, db, userID))
+	_, err = repo.NewUserRepository(db).GetByID(ctx, userID)
+	require.Error(t, err)
+
+	// A repeated request returns the deletion in progress.
+	again, err := svc.RequestDeletion(ctx, userID, username)
+	require.NoError(t, err)
+	require.Equal(t, d.ID, again.ID)
+
+	// First run: gacha is down — nothing is hard-deleted yet.
+	peers["gacha"].setFail(true)
+	n, err := svc.ProcessDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+	got, err := svc.GetDeletion(ctx, userID)
+	require.NoError(t, err)
+	for _, s := range got.Steps {
+		if s.Service == "gacha" {
+			require.Equal(t, domain.AccountDeletionStepPending, s.Status)
+			require.Equal(t, 1, s.Attempts)
+			require.NotEmpty(t, s.LastError)
+			require.True(t, s.NextAttemptAt.Equal(now.Add(accountStepBackoffBase)))
+		} else {
+			require.Equal(t, domain.AccountDeletionStepDone, s.Status)
+		}
+	}
+	require.Equal(t, []map[string]string{{"user_id": userID, "username": username}}, peers["player"].calls)
+
+	// Recovered, but the retry isn't due yet: no call.
+	peers["gacha"].setFail(false)
+	_, err = svc.ProcessDue(ctx)
+	require.NoError(t, err)
+	require.Len(t, peers["gacha"].calls, 1)
+
+	// Past the backoff: gacha succeeds, the user is hard-deleted and the
+	// done steps are not re-run.
+	now = now.Add(accountStepBackoffBase)
+	n, err = svc.ProcessDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Len(t, peers["gacha"].calls, 2)
+	require.Len(t, peers["player"].calls, 1)
+
+	var users, sessions int64
+	require.NoError(t, db.Raw(`SELECT COUNT(*) FROM users WHERE id = ?`, userID).Scan(&users).Error)
+	require.NoError(t, db.Raw(`SELECT COUNT(*) FROM user_sessions WHERE user_id = ?`, userID).Scan(&sessions).Error)
+	require.Zero(t, users)
+	require.Zero(t, sessions)
+
+	done, err := svc.GetDeletion(ctx, userID)
+	require.NoError(t, err)
+	require.Equal(t, domain.AccountDeletionCompleted, done.Status)
+	require.NotNil(t, done.CompletedAt)
+	var storedName string
+	require.NoError(t, db.Raw(`SELECT username FROM account_deletions WHERE id = ?`, d.ID).Scan(&storedName).Error)
+	require.Empty(t, storedName)
+}
+
+func TestAccountStepBackoff(t *testing.T) {
+	require.Equal(t, 30*time.Second, accountStepBackoff(1))
+	require.Equal(t, time.Minute, accountStepBackoff(2))
+	require.Equal(t, 4*time.Minute, accountStepBackoff(4))
+	require.Equal(t, accountStepBackoffMax, accountStepBackoff(30))
+}
@@ -22,6 +22,7 @@ func NewRouter(
 	adminUsersHandler *handler.AdminUsersHandler,
 	passkeyHandler *handler.PasskeyHandler,
 	certHandler *handler.CertHandler,
+	accountHandler *handler.AccountHandler,
 	jwtConfig authz.JWTConfig,
 	log *logger.Logger,
 	metricsCollector *metrics.Collector,
@@ -105,6 +106,9 @@ func NewRouter(
 			r.Get("/auth/certs/ca", certHandler.CAInfo)
 			r.Delete("/auth/certs/{id}", certHandler.Revoke)
 			r.Put("/auth/profile/cert-auto-login", certHandler.UpdateAutoLogin)
+			r.Get("/auth/account/export", accountHandler.Export)
+			r.Post("/auth/account/delete", accountHandler.RequestDeletion)
+			r.Get("/auth/account/deletion", accountHandler.GetDeletion)
 		})
 
 		// Public profile by public_id
@@ -131,6 +135,7 @@ func NewRouter(
 			r.Use(AdminMiddleware)
 			r.Get("/", adminUsersHandler.List)
 			r.Patch("/{id}/role", adminUsersHandler.UpdateRole)
+			r.Get("/{id}/deletion", accountHandler.AdminGetDeletion)
 			r.Get("/resolve", userResolveHandler.Resolve)
 		})
 	})
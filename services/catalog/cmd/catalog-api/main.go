@@ -622,6 +622,7 @@ func main() {
 	// Serves GET /internal/guessgame/pool for the anidle guessing-game service.
 	guessPoolService := service.NewGuessPoolService(animeRepo, shikimoriClient, log)
 	internalGuessPoolHandler := handler.NewInternalGuessPoolHandler(guessPoolService, log)
+	internalGuessPoolHandler.SetCharacterPool(service.NewGuessCharacterService(characterRepo))
 
 	// Workstream raw-jp, Phase 02 — multi-provider subtitle aggregator.
 	// Fans out to Jimaku (JP) + OpenSubtitles (everything else, keyed by
@@ -21,6 +21,10 @@ type Character struct {
 	PosterURL   string `gorm:"size:1000" json:"poster_url,omitempty"`
 	Description string `gorm:"type:text" json:"description,omitempty"`
 	URL         string `gorm:"size:1000" json:"url,omitempty"`
+	// Gender is "male" / "female", or empty when unknown. Shikimori does not
+	// expose it, so the fetchers never set it and upserts never overwrite it —
+	// it is filled out-of-band and read by the anidle character mode.
+	Gender string `gorm:"size:10" json:"gender,omitempty"`
 	// Seyu — the character's voice cast, stored inline as JSON (serializer:json,
 	// portable across Postgres + the SQLite test DB). Populated by the
 	// per-character REST fetch in GetCharacterByID.
@@ -15,11 +15,19 @@ type poolBuilder interface {
 	BuildPool(ctx context.Context) ([]service.GuessPoolEntry, error)
 }
 
-// InternalGuessPoolHandler serves GET /internal/guessgame/pool (Docker-network
-// only; NOT proxied by the gateway).
+// characterPoolBuilder is the subset of GuessCharacterService the handler
+// needs.
+type characterPoolBuilder interface {
+	BuildCharacterPool(ctx context.Context) ([]service.GuessCharacterEntry, error)
+}
+
+// InternalGuessPoolHandler serves GET /internal/guessgame/pool and
+// /internal/guessgame/characters (Docker-network only; NOT proxied by the
+// gateway).
 type InternalGuessPoolHandler struct {
-	svc poolBuilder
-	log *logger.Logger
+	svc        poolBuilder
+	characters characterPoolBuilder
+	log        *logger.Logger
 }
 
 func NewInternalGuessPoolHandler(svc poolBuilder, log *logger.Logger) *InternalGuessPoolHandler {
@@ -34,3 +42,21 @@ func (h *InternalGuessPoolHandler) GetPool(w http.ResponseWriter, r *http.Reques
 	}
 	httputil.OK(w, entries)
 }
+
+// SetCharacterPool wires the character pool served by GetCharacterPool.
+func (h *InternalGuessPoolHandler) SetCharacterPool(c characterPoolBuilder) {
+	h.characters = c
+}
+
+func (h *InternalGuessPoolHandler) GetCharacterPool(w http.ResponseWriter, r *http.Request) {
+	if h.characters == nil {
+		httputil.OK(w, []service.GuessCharacterEntry{})
+		return
+	}
+	entries, err := h.characters.BuildCharacterPool(r.Context())
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, entries)
+}
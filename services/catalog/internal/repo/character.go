@@ -126,3 +126,40 @@ func (r *CharacterRepository) GetByAnimeID(ctx context.Context, animeID string)
 	}
 	return views, nil
 }
+
+// GuessCharacterRow is one (character, anime) appearance of the guess-game
+// character pool, scanned from ListGuessCharacterRows.
+type GuessCharacterRow struct {
+	domain.Character
+	Role        string `gorm:"column:role"`
+	AnimeID     string `gorm:"column:anime_id"`
+	AnimeName   string `gorm:"column:anime_name"`
+	AnimeNameRU string `gorm:"column:anime_name_ru"`
+	Franchise   string `gorm:"column:franchise"`
+	AnimeYear   int    `gorm:"column:anime_year"`
+}
+
+// ListGuessCharacterRows returns every appearance of a character that is a
+// main character of at least one non-hidden anime scoring above minScore.
+// Rows are ordered by character, then earliest-aired anime first (NULLs
+// last), so the first row of each character is its first appearance.
+func (r *CharacterRepository) ListGuessCharacterRows(ctx context.Context, minScore float64) ([]GuessCharacterRow, error) {
+	var rows []GuessCharacterRow
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT c.*, ac.role, a.id AS anime_id, a.name AS anime_name, a.name_ru AS anime_name_ru,
+			a.franchise, a.year AS anime_year
+		FROM characters c
+		JOIN anime_characters ac ON ac.character_id = c.id
+		JOIN animes a ON a.id = ac.anime_id AND a.deleted_at IS NULL AND (a.hidden = ? OR a.hidden IS NULL)
+		WHERE c.deleted_at IS NULL AND c.id IN (
+			SELECT ac2.character_id FROM anime_characters ac2
+			JOIN animes a2 ON a2.id = ac2.anime_id
+			WHERE ac2.role = 'main' AND a2.score > ? AND a2.deleted_at IS NULL AND (a2.hidden = ? OR a2.hidden IS NULL)
+		)
+		ORDER BY c.id, a.aired_on ASC NULLS LAST
+	`, false, minScore, false).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ILITA-hub/animeenigma/services/catalog/internal/repo"
+)
+
+// GuessCharacterEntry is one guessable character with the 5 comparison
+// attributes of the anidle character mode. Field shape MUST match the anidle
+// DTO (services/anidle/internal/domain/character.go PoolCharacter).
+type GuessCharacterEntry struct {
+	ID            string      `json:"id"`
+	Name          string      `json:"name"`
+	NameRU        string      `json:"name_ru"`
+	NameJP        string      `json:"name_jp"`
+	PosterURL     string      `json:"poster_url"`
+	Gender        string      `json:"gender"`
+	Role          string      `json:"role"`
+	Franchise     string      `json:"franchise"`
+	FranchiseName string      `json:"franchise_name"`
+	Year          int         `json:"year"`
+	VoiceActors   []PoolTaxon `json:"voice_actors"`
+}
+
+type guessCharacterRepo interface {
+	ListGuessCharacterRows(ctx context.Context, minScore float64) ([]repo.GuessCharacterRow, error)
+}
+
+// GuessCharacterService builds the character pool: main characters of the
+// anime the anime pool is drawn from.
+type GuessCharacterService struct {
+	repo     guessCharacterRepo
+	minScore float64
+}
+
+func NewGuessCharacterService(repo guessCharacterRepo) *GuessCharacterService {
+	return &GuessCharacterService{repo: repo, minScore: 8.0}
+}
+
+// BuildCharacterPool folds each character's appearances into one entry.
+// Franchise and year come from the first appearance (a standalone anime is
+// its own franchise); role is "main" if the character leads any anime.
+func (s *GuessCharacterService) BuildCharacterPool(ctx context.Context) ([]GuessCharacterEntry, error) {
+	rows, err := s.repo.ListGuessCharacterRows(ctx, s.minScore)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]GuessCharacterEntry, 0)
+	index := make(map[string]int)
+	for _, r := range rows {
+		if i, ok := index[r.ID]; ok {
+			if r.Role == "main" {
+				out[i].Role = "main"
+			}
+			continue
+		}
+		index[r.ID] = len(out)
+		out = append(out, toCharacterEntry(r))
+	}
+	return out, nil
+}
+
+func toCharacterEntry(r repo.GuessCharacterRow) GuessCharacterEntry {
+	franchise := r.Franchise
+	if franchise == "" {
+		franchise = r.AnimeID
+	}
+	name := r.AnimeNameRU
+	if name == "" {
+		name = r.AnimeName
+	}
+	e := GuessCharacterEntry{
+		ID:            r.ID,
+		Name:          r.Name,
+		NameRU:        r.NameRU,
+		NameJP:        r.NameJP,
+		PosterURL:     r.PosterURL,
+		Gender:        r.Gender,
+		Role:          r.Role,
+		Franchise:     franchise,
+		FranchiseName: name,
+		Year:          r.AnimeYear,
+		VoiceActors:   make([]PoolTaxon, 0, len(r.Seyu)),
+	}
+	for _, v := range r.Seyu {
+		vname := v.NameRU
+		if vname == "" {
+			vname = v.Name
+		}
+		e.VoiceActors = append(e.VoiceActors, PoolTaxon{ID: v.ShikimoriID, Name: vname})
+	}
+	return e
+}
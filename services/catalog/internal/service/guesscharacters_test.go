@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ILITA-hub/animeenigma/services/catalog/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/catalog/internal/repo"
+)
+
+type fakeGuessCharacterRepo struct{ rows []repo.GuessCharacterRow }
+
+func (f *fakeGuessCharacterRepo) ListGuessCharacterRows(_ context.Context, _ float64) ([]repo.GuessCharacterRow, error) {
+	return f.rows, nil
+}
+
+func TestBuildCharacterPool_FoldsAppearances(t *testing.T) {
+	gojo := domain.Character{ID: "gojo", Name: "Satoru Gojo", Gender: "male",
+		Seyu: []domain.CharacterSeyu{{ShikimoriID: "1", Name: "Yuichi Nakamura", NameRU: "Юити Накамура"}}}
+	solo := domain.Character{ID: "solo", Name: "Solo"}
+	svc := NewGuessCharacterService(&fakeGuessCharacterRepo{rows: []repo.GuessCharacterRow{
+		// first appearance (earliest aired) comes first per character
+		{Character: gojo, Role: "supporting", AnimeID: "jjk0", AnimeNameRU: "Магическая битва", Franchise: "jujutsu_kaisen", AnimeYear: 2020},
+		{Character: gojo, Role: "main", AnimeID: "jjk1", Franchise: "jujutsu_kaisen", AnimeYear: 2021},
+		{Character: solo, Role: "main", AnimeID: "standalone", AnimeName: "Standalone", AnimeYear: 2019},
+	}})
+
+	pool, err := svc.BuildCharacterPool(context.Background())
+	if err != nil {
+		t.Fatalf("BuildCharacterPool: %v", err)
+	}
+	if len(pool) != 2 {
+		t.Fatalf("want 2 characters, got %d", len(pool))
+	}
+	g := pool[0]
+	if g.Role != "main" || g.Year != 2020 || g.Franchise != "jujutsu_kaisen" || g.FranchiseName != "Магическая битва" {
+		t.Errorf("gojo = %+v; want main, first appearance 2020 in jujutsu_kaisen", g)
+	}
+	if g.Gender != "male" || len(g.VoiceActors) != 1 || g.VoiceActors[0].Name != "Юити Накамура" {
+		t.Errorf("gojo gender/voice = %q %+v", g.Gender, g.VoiceActors)
+	}
+	if s := pool[1]; s.Franchise != "standalone" || s.FranchiseName != "Standalone" || s.VoiceActors == nil {
+		t.Errorf("standalone = %+v; want its anime as franchise and a non-nil voice list", s)
+	}
+}
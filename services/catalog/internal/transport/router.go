@@ -137,6 +137,7 @@ func NewRouter(
 	// Anidle guess-game pool (spec 2026-06-15) — Docker-network only.
 	if internalGuessPoolHandler != nil {
 		r.Get("/internal/guessgame/pool", internalGuessPoolHandler.GetPool)
+		r.Get("/internal/guessgame/characters", internalGuessPoolHandler.GetCharacterPool)
 	}
 
 	// API routes
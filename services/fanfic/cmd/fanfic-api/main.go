@@ -1,9 +1,12 @@
 // Package main is the fanfic service entrypoint (port 8097) — an admin-only
-// AI fanfiction generator backed by Groq. Mirrors the gacha boot sequence.
+// AI fanfiction generator streaming from Groq or any OpenAI-compatible
+// backend with fallback, plus publishing of human-authored works, their
+// EPUB/PDF export and VOICEVOX narration. Mirrors the gacha boot sequence.
 package main
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"os"
 	"os/signal"
@@ -14,6 +17,7 @@ import (
 	"github.com/ILITA-hub/animeenigma/libs/database"
 	"github.com/ILITA-hub/animeenigma/libs/logger"
 	"github.com/ILITA-hub/animeenigma/libs/metrics"
+	"github.com/ILITA-hub/animeenigma/libs/storageclient"
 	"github.com/ILITA-hub/animeenigma/libs/tracing"
 	gormtrace "github.com/ILITA-hub/animeenigma/libs/tracing/gormtrace"
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/alert"
@@ -22,9 +26,14 @@ import (
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/groq"
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/handler"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/llm"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/narration"
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/repo"
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/service"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/storagegw"
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/transport"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/voicevox"
+	goredis "github.com/redis/go-redis/v9"
 )
 
 func main() {
@@ -58,7 +67,15 @@ func main() {
 	if sqlDB, err := db.DB.DB(); err == nil {
 		metrics.StartDBPoolCollector(sqlDB, 15*time.Second)
 	}
-	if err := db.AutoMigrate(&domain.Fanfic{}); err != nil {
+	if err := db.AutoMigrate(
+		&domain.Fanfic{},
+		&domain.Chapter{},
+		&domain.Kudos{},
+		&domain.Bookmark{},
+		&domain.Subscription{},
+		&domain.Export{},
+		&domain.Narration{},
+	); err != nil {
 		log.Fatalw("failed to migrate database", "error", err)
 	}
 
@@ -68,12 +85,22 @@ func main() {
 	}
 	defer redis.Close()
 
-	groqClient := groq.New(cfg.Groq.APIKey, cfg.Groq.BaseURL, cfg.Groq.Model, cfg.Groq.Timeout)
+	backends := make([]llm.Generator, 0, len(cfg.Backends))
+	budgets := make(map[string]int, len(cfg.Backends))
+	for _, b := range cfg.Backends {
+		if b.Name == groq.Name {
+			backends = append(backends, groq.New(b.APIKey, b.BaseURL, b.Models, b.Timeout))
+		} else {
+			backends = append(backends, llm.NewOpenAI(b.Name, b.APIKey, b.BaseURL, b.Models, b.Timeout))
+		}
+		budgets[b.Name] = b.DailyTokens
+	}
 	fanficRepo := repo.NewRepository(db.DB)
-	quota := service.NewQuota(newRedisQuotaStore(redis), cfg.DailyCap, time.Now)
+	quota := service.NewQuota(newRedisQuotaStore(redis), cfg.DailyCap, budgets, time.Now)
+	llmRouter := llm.NewRouter(backends, quota, log)
 	catalogClient := catalog.NewClient(cfg.CatalogURL, cfg.CatalogTimeout, log)
-	generator := service.NewGenerator(groqClient, fanficRepo, quota, catalogClient, cfg.Groq.Model, cfg.ContinueContextRunes, log)
-	h := handler.NewHandler(generator, fanficRepo, log)
+	generator := service.NewGenerator(llmRouter, fanficRepo, quota, catalogClient, cfg.ContinueContextRunes, log)
+	h := handler.NewHandler(generator, fanficRepo, llmRouter, log)
 
 	// Alerter fails open to Noop unless BOTH the ALERTS bot token and admin
 	// chat ID are configured (docker-compose wiring lands in Task 19).
@@ -81,11 +108,40 @@ func main() {
 	if cfg.AlertsBotToken != "" && cfg.AlertsChatID != "" {
 		alerter = alert.NewTelegram(cfg.AlertsBotToken, cfg.AlertsChatID, "", nil)
 	}
-	dailyService := service.NewDailyService(groqClient, fanficRepo, catalogClient, alerter, cfg.Groq.Model, cfg.DailyAnimePool, cfg.BotLanguage, time.Now, log)
+	dailyService := service.NewDailyService(llmRouter, fanficRepo, catalogClient, alerter, cfg.DailyAnimePool, cfg.BotLanguage, time.Now, log)
 	dh := handler.NewDailyHandler(dailyService)
 
 	mc := metrics.NewCollector("fanfic")
-	router := transport.NewRouter(h, dh, cfg.JWT, log, mc)
+	ah := database.NewUserDataHandler(repo.NewAccountRepository(db.DB), log)
+
+	chapterNotifier := service.NewChapterNotifier(cfg.NotificationsURL, cfg.ChapterNotifyEnabled, log)
+	workRepo := repo.NewWorkRepository(db.DB)
+	workService := service.NewWorkService(workRepo, chapterNotifier, time.Now, log)
+	wh := handler.NewWorkHandler(workService)
+
+	exportStorage := storagegw.New(storageclient.New(cfg.StorageURL))
+	posters := service.NewPosterFetcher(cfg.PosterTimeout, cfg.PosterMaxBytes)
+	exportService := service.NewExportService(repo.NewExportRepository(db.DB), workRepo, exportStorage, posters, time.Now, log)
+	eh := handler.NewExportHandler(exportService)
+
+	// Narration is low-priority synthesis on the shared VOICEVOX engine: it
+	// pauses while the governor-published level (Redis ae:degradation:level)
+	// is Elevated+, the same threshold the gateway's Zundamon facade sheds
+	// at. Fail-open: a missing key reads as Normal.
+	shedWatcher := cache.NewDegradationWatcher(redis, 5*time.Second)
+	narrationService := service.NewNarrationService(
+		repo.NewNarrationRepository(db.DB),
+		workRepo,
+		voicevox.New(cfg.VoicevoxURL, cfg.VoicevoxTimeout),
+		exportStorage,
+		narration.FFmpeg{Path: cfg.FFmpegPath},
+		shedWatcher,
+		time.Now,
+		log,
+	)
+	nh := handler.NewNarrationHandler(narrationService)
+
+	router := transport.NewRouter(h, wh, eh, nh, dh, ah, cfg.JWT, log, mc)
 
 	srv := &http.Server{
 		Addr:        cfg.Server.Address(),
@@ -98,15 +154,28 @@ func main() {
 	}
 
 	go func() {
-		log.Infow("starting fanfic service", "address", cfg.Server.Address(), "model", cfg.Groq.Model)
+		log.Infow("starting fanfic service", "address", cfg.Server.Address(), "backends", llmRouter.Backends())
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalw("failed to start server", "error", err)
 		}
 	}()
 
+	// Publishes scheduled chapters as their time comes.
+	publishCtx, stopPublishing := context.WithCancel(context.Background())
+	defer stopPublishing()
+	go workService.Run(publishCtx, cfg.ChapterPublishInterval)
+
+	// Renders queued EPUB/PDF exports into object storage.
+	go exportService.Run(publishCtx, cfg.ExportInterval)
+
+	// Synthesizes queued narrations chunk by chunk, pausing under load.
+	shedWatcher.Start(publishCtx)
+	go narrationService.Run(publishCtx, cfg.NarrationInterval)
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
+	stopPublishing()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	_ = srv.Shutdown(ctx)
@@ -130,3 +199,13 @@ func (s *redisQuotaStore) SetNX(ctx context.Context, key string, ttl time.Durati
 func (s *redisQuotaStore) Del(ctx context.Context, key string) error {
 	return s.rc.Client().Del(ctx, key).Err()
 }
+func (s *redisQuotaStore) IncrBy(ctx context.Context, key string, n int64) (int64, error) {
+	return s.rc.Client().IncrBy(ctx, key, n).Result()
+}
+func (s *redisQuotaStore) Get(ctx context.Context, key string) (int64, error) {
+	n, err := s.rc.Client().Get(ctx, key).Int64()
+	if errors.Is(err, goredis.Nil) {
+		return 0, nil
+	}
+	return n, err
+}
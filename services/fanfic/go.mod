@@ -10,6 +10,7 @@ replace (
 	github.com/ILITA-hub/animeenigma/libs/httputil => ../../libs/httputil
 	github.com/ILITA-hub/animeenigma/libs/logger => ../../libs/logger
 	github.com/ILITA-hub/animeenigma/libs/metrics => ../../libs/metrics
+	github.com/ILITA-hub/animeenigma/libs/storageclient => ../../libs/storageclient
 	github.com/ILITA-hub/animeenigma/libs/tracing => ../../libs/tracing
 )
 
@@ -19,7 +20,9 @@ require (
 	github.com/ILITA-hub/animeenigma/libs/database v0.0.0-00010101000000-000000000000
 	github.com/ILITA-hub/animeenigma/libs/errors v0.0.0
 	github.com/ILITA-hub/animeenigma/libs/httputil v0.0.0-00010101000000-000000000000
+	github.com/ILITA-hub/animeenigma/libs/storageclient v0.0.0
 	github.com/google/uuid v1.6.0
+	golang.org/x/image v0.44.0
 	gorm.io/datatypes v1.2.7
 	gorm.io/driver/sqlite v1.6.0
 	gorm.io/gorm v1.31.2
@@ -70,9 +73,9 @@ require (
 	go.yaml.in/yaml/v2 v2.4.2 // indirect
 	golang.org/x/crypto v0.51.0 // indirect
 	golang.org/x/net v0.55.0 // indirect
-	golang.org/x/sync v0.20.0 // indirect
+	golang.org/x/sync v0.22.0 // indirect
 	golang.org/x/sys v0.45.0 // indirect
-	golang.org/x/text v0.37.0 // indirect
+	golang.org/x/text v0.40.0 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20251022142026-3a174f9686a8 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20251022142026-3a174f9686a8 // indirect
 	google.golang.org/grpc v1.77.0 // indirect
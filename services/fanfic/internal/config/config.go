@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -17,8 +18,8 @@ type Config struct {
 	Database database.Config
 	Redis    cache.Config
 	JWT      authz.JWTConfig
-	Groq     GroqConfig
-	DailyCap int // FANFIC_DAILY_CAP — max generations per user per day (default 100)
+	Backends []BackendConfig // FANFIC_BACKENDS — generation backends in fallback order (default "groq")
+	DailyCap int             // FANFIC_DAILY_CAP — max generations per user per day (default 100)
 
 	CatalogURL           string
 	CatalogTimeout       time.Duration
@@ -29,6 +30,24 @@ type Config struct {
 	AlertsChatID   string   // TELEGRAM_ADMIN_CHAT_ID — empty ⇒ alerter falls back to Noop (fail-open)
 	DailyAnimePool []string // FANFIC_DAILY_ANIME_POOL — CSV of shikimori IDs the daily picker draws from
 	BotLanguage    string   // FANFIC_BOT_LANGUAGE — language the bot generates the daily fanfic in
+
+	// Human-authored works — see internal/service/works.go.
+	NotificationsURL       string        // NOTIFICATIONS_INTERNAL_URL — new-chapter notifications go to its internal upsert
+	ChapterNotifyEnabled   bool          // FANFIC_CHAPTER_NOTIFY_ENABLED — toggles new-chapter notifications
+	ChapterPublishInterval time.Duration // FANFIC_CHAPTER_PUBLISH_INTERVAL — scheduled-chapter publisher tick (0 disables)
+
+	// EPUB/PDF exports — see internal/service/export.go.
+	StorageURL     string        // FANFIC_STORAGE_URL — storage service the rendered files are cached in
+	ExportInterval time.Duration // FANFIC_EXPORT_INTERVAL — export render queue tick (0 disables rendering)
+	PosterTimeout  time.Duration // FANFIC_POSTER_TIMEOUT — cover poster download timeout
+	PosterMaxBytes int64         // FANFIC_POSTER_MAX_BYTES — posters above this size export without a cover
+
+	// VOICEVOX narration — see internal/service/narration.go. Audio lands in
+	// the same storage service as exports.
+	VoicevoxURL       string        // FANFIC_VOICEVOX_URL — internal VOICEVOX engine
+	VoicevoxTimeout   time.Duration // FANFIC_VOICEVOX_TIMEOUT — per engine call (one chunk of up to 500 characters)
+	NarrationInterval time.Duration // FANFIC_NARRATION_INTERVAL — narration queue tick (0 disables synthesis)
+	FFmpegPath        string        // FANFIC_FFMPEG_PATH — encoder of the finished Opus/MP3 files
 }
 
 type ServerConfig struct {
@@ -38,19 +57,27 @@ type ServerConfig struct {
 
 func (s ServerConfig) Address() string { return fmt.Sprintf("%s:%d", s.Host, s.Port) }
 
-type GroqConfig struct {
-	APIKey  string
-	BaseURL string
-	Model   string
-	Timeout time.Duration
+// BackendConfig is one OpenAI-compatible generation backend — see
+// internal/llm. Groq reads the legacy FANFIC_GROQ_* vars; any other name
+// reads FANFIC_BACKEND_<NAME>_{BASE_URL,API_KEY,MODELS,TIMEOUT,DAILY_TOKENS}.
+type BackendConfig struct {
+	Name        string
+	APIKey      string // empty ⇒ no Authorization header (LAN llama.cpp / vLLM)
+	BaseURL     string
+	Models      []string // default first
+	Timeout     time.Duration
+	DailyTokens int // daily token budget across all users; 0 ⇒ unlimited
 }
 
+var backendNameRe = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
 func Load() (*Config, error) {
 	if getEnv("JWT_SECRET", "") == "" {
 		return nil, fmt.Errorf("JWT_SECRET environment variable is required")
 	}
-	if getEnv("FANFIC_GROQ_API_KEY", "") == "" {
-		return nil, fmt.Errorf("FANFIC_GROQ_API_KEY environment variable is required")
+	backends, err := loadBackends()
+	if err != nil {
+		return nil, err
 	}
 	return &Config{
 		Server: ServerConfig{
@@ -77,12 +104,7 @@ func Load() (*Config, error) {
 			AccessTokenTTL:  getEnvDuration("JWT_ACCESS_TTL", 15*time.Minute),
 			RefreshTokenTTL: getEnvDuration("JWT_REFRESH_TTL", 7*24*time.Hour),
 		},
-		Groq: GroqConfig{
-			APIKey:  getEnv("FANFIC_GROQ_API_KEY", ""),
-			BaseURL: getEnv("FANFIC_GROQ_BASE_URL", "https://api.groq.com/openai/v1"),
-			Model:   getEnv("FANFIC_GROQ_MODEL", "llama-3.1-8b-instant"),
-			Timeout: getEnvDuration("FANFIC_GROQ_TIMEOUT", 120*time.Second),
-		},
+		Backends: backends,
 		DailyCap: getEnvInt("FANFIC_DAILY_CAP", 100),
 
 		CatalogURL:           getEnv("CATALOG_URL", "http://catalog:8081"),
@@ -93,9 +115,76 @@ func Load() (*Config, error) {
 		AlertsChatID:   getEnv("TELEGRAM_ADMIN_CHAT_ID", ""),
 		DailyAnimePool: getEnvCSV("FANFIC_DAILY_ANIME_POOL", "20,21,1735,52991,16498,5114"),
 		BotLanguage:    getEnv("FANFIC_BOT_LANGUAGE", "ru"),
+
+		NotificationsURL:       strings.TrimRight(getEnv("NOTIFICATIONS_INTERNAL_URL", "http://notifications:8090"), "/"),
+		ChapterNotifyEnabled:   getEnvBool("FANFIC_CHAPTER_NOTIFY_ENABLED", true),
+		ChapterPublishInterval: getEnvDuration("FANFIC_CHAPTER_PUBLISH_INTERVAL", time.Minute),
+
+		StorageURL:     strings.TrimRight(getEnv("FANFIC_STORAGE_URL", "http://storage:8099"), "/"),
+		ExportInterval: getEnvDuration("FANFIC_EXPORT_INTERVAL", 5*time.Second),
+		PosterTimeout:  getEnvDuration("FANFIC_POSTER_TIMEOUT", 10*time.Second),
+		PosterMaxBytes: int64(getEnvInt("FANFIC_POSTER_MAX_BYTES", 5<<20)),
+
+		VoicevoxURL:       strings.TrimRight(getEnv("FANFIC_VOICEVOX_URL", "http://voicevox:50021"), "/"),
+		VoicevoxTimeout:   getEnvDuration("FANFIC_VOICEVOX_TIMEOUT", 60*time.Second),
+		NarrationInterval: getEnvDuration("FANFIC_NARRATION_INTERVAL", 10*time.Second),
+		FFmpegPath:        getEnv("FANFIC_FFMPEG_PATH", "ffmpeg"),
 	}, nil
 }
 
+// loadBackends reads FANFIC_BACKENDS and each listed backend's settings.
+func loadBackends() ([]BackendConfig, error) {
+	names := getEnvCSV("FANFIC_BACKENDS", "groq")
+	if len(names) == 0 {
+		return nil, fmt.Errorf("FANFIC_BACKENDS must list at least one backend")
+	}
+	seen := make(map[string]bool, len(names))
+	out := make([]BackendConfig, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if !backendNameRe.MatchString(name) {
+			return nil, fmt.Errorf("invalid backend name %q in FANFIC_BACKENDS", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("backend %q listed twice in FANFIC_BACKENDS", name)
+		}
+		seen[name] = true
+
+		if name == "groq" {
+			if getEnv("FANFIC_GROQ_API_KEY", "") == "" {
+				return nil, fmt.Errorf("FANFIC_GROQ_API_KEY environment variable is required")
+			}
+			out = append(out, BackendConfig{
+				Name:        name,
+				APIKey:      getEnv("FANFIC_GROQ_API_KEY", ""),
+				BaseURL:     getEnv("FANFIC_GROQ_BASE_URL", "https://api.groq.com/openai/v1"),
+				Models:      getEnvCSV("FANFIC_GROQ_MODELS", getEnv("FANFIC_GROQ_MODEL", "llama-3.1-8b-instant")),
+				Timeout:     getEnvDuration("FANFIC_GROQ_TIMEOUT", 120*time.Second),
+				DailyTokens: getEnvInt("FANFIC_GROQ_DAILY_TOKENS", 0),
+			})
+			continue
+		}
+
+		prefix := "FANFIC_BACKEND_" + strings.ToUpper(name) + "_"
+		b := BackendConfig{
+			Name:        name,
+			APIKey:      getEnv(prefix+"API_KEY", ""),
+			BaseURL:     getEnv(prefix+"BASE_URL", ""),
+			Models:      getEnvCSV(prefix+"MODELS", ""),
+			Timeout:     getEnvDuration(prefix+"TIMEOUT", 300*time.Second),
+			DailyTokens: getEnvInt(prefix+"DAILY_TOKENS", 0),
+		}
+		if b.BaseURL == "" {
+			return nil, fmt.Errorf("%sBASE_URL environment variable is required", prefix)
+		}
+		if len(b.Models) == 0 {
+			return nil, fmt.Errorf("%sMODELS environment variable is required", prefix)
+		}
+		out = append(out, b)
+	}
+	return out, nil
+}
+
 func getEnv(key, def string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -121,6 +210,15 @@ func getEnvDuration(key string, def time.Duration) time.Duration {
 	return def
 }
 
+func getEnvBool(key string, def bool) bool {
+	if v := os.Getenv(key); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return def
+}
+
 // getEnvCSV splits a comma-separated env var into a trimmed, non-empty-entry
 // slice, falling back to a (also CSV) default when the env var is unset.
 func getEnvCSV(key, def string) []string {
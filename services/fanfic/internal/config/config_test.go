@@ -4,6 +4,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // setRequired sets the two env vars Load() hard-requires and returns a
@@ -100,3 +101,76 @@ func TestLoad_AlertsAndLangOverride(t *testing.T) {
 		t.Errorf("BotLanguage = %q; want en", cfg.BotLanguage)
 	}
 }
+
+func TestLoad_BackendsDefaultToGroq(t *testing.T) {
+	setRequired(t)
+	os.Unsetenv("FANFIC_BACKENDS")
+	os.Setenv("FANFIC_GROQ_MODELS", "llama-3.3-70b-versatile, llama-3.1-8b-instant")
+	defer os.Unsetenv("FANFIC_GROQ_MODELS")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.Backends) != 1 || cfg.Backends[0].Name != "groq" || cfg.Backends[0].APIKey != "x" {
+		t.Fatalf("Backends = %+v; want the single groq backend", cfg.Backends)
+	}
+	want := []string{"llama-3.3-70b-versatile", "llama-3.1-8b-instant"}
+	if !reflect.DeepEqual(cfg.Backends[0].Models, want) {
+		t.Errorf("groq Models = %v; want %v", cfg.Backends[0].Models, want)
+	}
+}
+
+func TestLoad_OpenAICompatibleBackend(t *testing.T) {
+	os.Setenv("JWT_SECRET", "x")
+	os.Unsetenv("FANFIC_GROQ_API_KEY")
+	os.Setenv("FANFIC_BACKENDS", "lan")
+	defer func() {
+		os.Unsetenv("JWT_SECRET")
+		os.Unsetenv("FANFIC_BACKENDS")
+	}()
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error when the lan backend has no BASE_URL")
+	}
+
+	os.Setenv("FANFIC_BACKEND_LAN_BASE_URL", "http://10.0.0.5:8080/v1")
+	os.Setenv("FANFIC_BACKEND_LAN_MODELS", "qwen2.5-7b-instruct")
+	os.Setenv("FANFIC_BACKEND_LAN_DAILY_TOKENS", "500000")
+	defer func() {
+		os.Unsetenv("FANFIC_BACKEND_LAN_BASE_URL")
+		os.Unsetenv("FANFIC_BACKEND_LAN_MODELS")
+		os.Unsetenv("FANFIC_BACKEND_LAN_DAILY_TOKENS")
+	}()
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load without groq must not require FANFIC_GROQ_API_KEY: %v", err)
+	}
+	b := cfg.Backends[0]
+	if len(cfg.Backends) != 1 || b.Name != "lan" || b.APIKey != "" || b.DailyTokens != 500000 {
+		t.Errorf("Backends = %+v; want keyless lan with a 500000 budget", cfg.Backends)
+	}
+}
+
+func TestLoad_ExportDefaults(t *testing.T) {
+	setRequired(t)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.StorageURL != "http://storage:8099" || cfg.ExportInterval != 5*time.Second || cfg.PosterMaxBytes != 5<<20 {
+		t.Errorf("export config = %q %v %d", cfg.StorageURL, cfg.ExportInterval, cfg.PosterMaxBytes)
+	}
+}
+
+func TestLoad_NarrationDefaults(t *testing.T) {
+	setRequired(t)
+	t.Setenv("FANFIC_VOICEVOX_URL", "http://tts:50021/")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.VoicevoxURL != "http://tts:50021" || cfg.VoicevoxTimeout != time.Minute || cfg.NarrationInterval != 10*time.Second || cfg.FFmpegPath != "ffmpeg" {
+		t.Errorf("narration config = %q %v %v %q", cfg.VoicevoxURL, cfg.VoicevoxTimeout, cfg.NarrationInterval, cfg.FFmpegPath)
+	}
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Export is one rendered EPUB/PDF of a fanfic or of a reader's bookmarks,
+// cached in object storage. Rows are keyed by Fingerprint — a hash of the
+// rendered content — so an unchanged fanfic is rendered once and served to
+// every later request.
+type Export struct {
+	ID          string     `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID      string     `gorm:"type:uuid;index;not null" json:"-"` // who requested it
+	Kind        string     `gorm:"size:16" json:"kind"`               // fanfic | bookmarks
+	FanficID    string     `gorm:"type:uuid;index" json:"fanfic_id,omitempty"`
+	Format      string     `gorm:"size:8" json:"format"`
+	Fingerprint string     `gorm:"size:64;uniqueIndex" json:"-"`
+	Title       string     `gorm:"size:512" json:"title"`
+	Status      string     `gorm:"size:16;index" json:"status"` // pending | running | ready | failed
+	Storage     string     `gorm:"size:16" json:"-"`
+	ObjectKey   string     `gorm:"size:512" json:"-"`
+	Size        int64      `json:"size,omitempty"`
+	ErrorMsg    string     `gorm:"type:text" json:"error,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// DownloadURL is filled in the API response once the export is ready.
+	DownloadURL string `gorm:"-" json:"download_url,omitempty"`
+}
+
+func (Export) TableName() string { return "fanfic_exports" }
+
+// BeforeCreate fills ID in Go, same as Fanfic.BeforeCreate.
+func (e *Export) BeforeCreate(*gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// Export kinds.
+const (
+	ExportKindFanfic    = "fanfic"
+	ExportKindBookmarks = "bookmarks"
+)
+
+// Export formats; each is also the stored file's extension.
+const (
+	ExportEPUB = "epub"
+	ExportPDF  = "pdf"
+)
+
+// Export statuses.
+const (
+	ExportPending = "pending"
+	ExportRunning = "running"
+	ExportReady   = "ready"
+	ExportFailed  = "failed"
+)
+
+// ExportRequest is the POST .../export body.
+type ExportRequest struct {
+	Format string `json:"format"`
+}
+
+// Validate implements httputil.Validator.
+func (r ExportRequest) Validate() error {
+	if r.Format != ExportEPUB && r.Format != ExportPDF {
+		return fmt.Errorf("invalid format %q (epub or pdf)", r.Format)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "testing"
+
+func TestExportRequestValidate(t *testing.T) {
+	for _, format := range []string{ExportEPUB, ExportPDF} {
+		if err := (ExportRequest{Format: format}).Validate(); err != nil {
+			t.Errorf("%s: %v", format, err)
+		}
+	}
+	for _, format := range []string{"", "mobi", "EPUB"} {
+		if err := (ExportRequest{Format: format}).Validate(); err == nil {
+			t.Errorf("%q: expected error", format)
+		}
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"gorm.io/gorm"
 )
 
-// Fanfic is one generated fanfiction, owned by the user who generated it.
+// Fanfic is one fanfiction, owned by the user who generated or wrote it.
+// Origin tells the two apart: generated rows carry their whole text in
+// Content, human-authored works keep it in Chapter rows.
 type Fanfic struct {
 	ID               string         `gorm:"type:uuid;primaryKey" json:"id"`
 	UserID           string         `gorm:"type:uuid;index;not null" json:"-"`
@@ -26,29 +28,57 @@ type Fanfic struct {
 	AuthorUsername   string         `gorm:"size:64" json:"author_username,omitempty"`
 	SpotlightCredit  bool           `gorm:"default:false" json:"spotlight_credit"`
 	AIGenerated      bool           `gorm:"default:false;index" json:"ai_generated"`
+	Origin           string         `gorm:"size:16;default:generated;index" json:"origin"`
 	Prompt           string         `gorm:"type:text" json:"prompt"`
 	Canon            bool           `gorm:"default:false" json:"canon"`
 	PartCount        int            `gorm:"default:1" json:"part_count"`
 	Title            string         `gorm:"size:512" json:"title"`
+	Summary          string         `gorm:"type:text" json:"summary,omitempty"`
 	Content          string         `gorm:"type:text" json:"content"`
+	Backend          string         `gorm:"size:32" json:"backend,omitempty"`
 	Model            string         `gorm:"size:64" json:"model"`
 	TokenUsage       int            `json:"token_usage"`
 	Status           string         `gorm:"size:16;index" json:"status"`
 	ErrorMsg         string         `gorm:"type:text" json:"error,omitempty"`
+	WordCount        int            `gorm:"default:0" json:"word_count"`
+	KudosCount       int            `gorm:"default:0" json:"kudos_count"`
+	BookmarkCount    int            `gorm:"default:0" json:"bookmark_count"`
+	PublishedAt      *time.Time     `gorm:"index" json:"published_at,omitempty"`
 	CreatedAt        time.Time      `json:"created_at"`
 	UpdatedAt        time.Time      `json:"updated_at"`
 	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
-// Status values.
+// Status values. Generated fanfics go generating → complete | failed;
+// human-authored works are drafts until their first chapter is published.
+// StatusScheduled is used by chapters only.
 const (
 	StatusGenerating = "generating"
 	StatusComplete   = "complete"
 	StatusFailed     = "failed"
+	StatusDraft      = "draft"
+	StatusScheduled  = "scheduled"
+	StatusPublished  = "published"
+)
+
+// Origin values.
+const (
+	OriginGenerated = "generated"
+	OriginHuman     = "human"
 )
 
 func (Fanfic) TableName() string { return "fanfics" }
 
+// IsPublic reports whether anyone may read f: a published human work, or a
+// complete generated fanfic already public through the daily spotlight (a
+// bot row, or one whose author opted into spotlight credit).
+func (f *Fanfic) IsPublic() bool {
+	if f.Origin == OriginHuman {
+		return f.Status == StatusPublished
+	}
+	return f.Status == StatusComplete && (f.AIGenerated || f.SpotlightCredit)
+}
+
 // BeforeCreate populates ID in Go so the row gets an id on every dialect
 // (Postgres's gen_random_uuid() default is not available on SQLite, which
 // hosts the in-memory repo tests). Every insert goes through GORM Create, so
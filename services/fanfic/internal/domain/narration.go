@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Narration is one VOICEVOX-narrated audio version of a Japanese fanfic.
+// Like Export, rows are keyed by Fingerprint — the narrated text plus voice
+// settings — so an unchanged fanfic read in the same voice is synthesized
+// once. The chunk plan is frozen in Plan at request time: the background job
+// synthesizes chunk ChunksDone next, so a job paused by platform load, a
+// restart or an engine outage resumes where it stopped.
+type Narration struct {
+	ID           string         `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID       string         `gorm:"type:uuid;index;not null" json:"-"` // who requested it
+	FanficID     string         `gorm:"type:uuid;index;not null" json:"fanfic_id"`
+	Fingerprint  string         `gorm:"size:64;uniqueIndex" json:"-"`
+	Title        string         `gorm:"size:512" json:"title"`
+	Speaker      string         `gorm:"size:128" json:"speaker"`
+	StyleID      int            `json:"style_id"`
+	StyleName    string         `gorm:"size:128" json:"style"`
+	Speed        float64        `json:"speed"`
+	Format       string         `gorm:"size:8" json:"format"`
+	Status       string         `gorm:"size:16;index" json:"status"` // pending | running | paused | ready | failed
+	Plan         datatypes.JSON `gorm:"type:jsonb" json:"-"`         // []NarrationPart
+	ChunksTotal  int            `json:"chunks_total"`
+	ChunksDone   int            `json:"chunks_done"`
+	Attempts     int            `json:"-"`                // consecutive failed tries of the current chunk
+	ChunkStorage string         `gorm:"size:16" json:"-"` // backend holding the synthesized chunks
+	Storage      string         `gorm:"size:16" json:"-"` // backend holding the encoded file
+	ObjectKey    string         `gorm:"size:512" json:"-"`
+	Size         int64          `json:"size,omitempty"`
+	DurationMs   int64          `json:"duration_ms,omitempty"`
+	ErrorMsg     string         `gorm:"type:text" json:"error,omitempty"`
+	CompletedAt  *time.Time     `json:"completed_at,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+
+	// DownloadURL is filled in the API response once the narration is ready.
+	DownloadURL string `gorm:"-" json:"download_url,omitempty"`
+}
+
+func (Narration) TableName() string { return "fanfic_narrations" }
+
+// BeforeCreate fills ID in Go, same as Fanfic.BeforeCreate.
+func (n *Narration) BeforeCreate(*gorm.DB) error {
+	if n.ID == "" {
+		n.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// NarrationPart is one chapter of the narration plan: its title (the
+// chapter marker) and the text chunks read for it, title first.
+type NarrationPart struct {
+	Title  string   `json:"title"`
+	Chunks []string `json:"chunks"`
+}
+
+// Narration formats; each is also the stored file's extension.
+const (
+	NarrationOpus = "opus"
+	NarrationMP3  = "mp3"
+)
+
+// Narration statuses. Paused jobs wait out platform load or an engine
+// outage and resume on their own.
+const (
+	NarrationPending = "pending"
+	NarrationRunning = "running"
+	NarrationPaused  = "paused"
+	NarrationReady   = "ready"
+	NarrationFailed  = "failed"
+)
+
+// NarrationRequest is the POST /api/fanfic/{id}/narration body. StyleID is a
+// VOICEVOX style id (GET /api/fanfic/narration/speakers); 0 is a real style,
+// hence the pointer. Speed defaults to 1.
+type NarrationRequest struct {
+	StyleID *int    `json:"style_id"`
+	Speed   float64 `json:"speed"`
+	Format  string  `json:"format"`
+}
+
+// Validate implements httputil.Validator.
+func (r NarrationRequest) Validate() error {
+	if r.StyleID == nil || *r.StyleID < 0 {
+		return fmt.Errorf("style_id is required")
+	}
+	if r.Speed != 0 && (r.Speed < 0.5 || r.Speed > 2) {
+		return fmt.Errorf("speed must be between 0.5 and 2")
+	}
+	if r.Format != NarrationOpus && r.Format != NarrationMP3 {
+		return fmt.Errorf("invalid format %q (opus or mp3)", r.Format)
+	}
+	return nil
+}
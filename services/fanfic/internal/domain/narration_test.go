@@ -0,0 +1,26 @@
+package domain
+
+import "testing"
+
+func TestNarrationRequestValidate(t *testing.T) {
+	zero, three, negative := 0, 3, -1
+	for _, r := range []NarrationRequest{
+		{StyleID: &zero, Format: NarrationOpus},
+		{StyleID: &three, Speed: 1.5, Format: NarrationMP3},
+	} {
+		if err := r.Validate(); err != nil {
+			t.Errorf("%+v: %v", r, err)
+		}
+	}
+	for name, r := range map[string]NarrationRequest{
+		"no style":       {Format: NarrationOpus},
+		"negative style": {StyleID: &negative, Format: NarrationOpus},
+		"slow":           {StyleID: &three, Speed: 0.2, Format: NarrationOpus},
+		"fast":           {StyleID: &three, Speed: 3, Format: NarrationOpus},
+		"format":         {StyleID: &three, Format: "wav"},
+	} {
+		if err := r.Validate(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -36,6 +36,11 @@ type GenerateRequest struct {
 	// the "Фанфик дня" (daily spotlight) rotation, credited under their
 	// username. Defaults to false (opt-in, not opt-out).
 	SpotlightCredit bool `json:"spotlight_credit"`
+	// Backend and Model optionally pick the generation backend/model (see
+	// GET /api/fanfic/models); empty means the configured default. The other
+	// backends remain fallbacks either way.
+	Backend string `json:"backend,omitempty"`
+	Model   string `json:"model,omitempty"`
 }
 
 var (
@@ -43,6 +48,9 @@ var (
 	validPOV      = map[string]bool{"first": true, "third": true}
 	validRating   = map[string]bool{"teen": true, "mature": true, "explicit": true}
 	validLanguage = map[string]bool{"ru": true, "en": true}
+	// Human-authored works may also be written in Japanese — the language
+	// they can be narrated in (VOICEVOX reads Japanese only).
+	validWorkLanguage = map[string]bool{"ru": true, "en": true, "ja": true}
 )
 
 // Validate implements httputil.Validator.
@@ -76,6 +84,9 @@ func (r GenerateRequest) Validate() error {
 	if utf8.RuneCountInString(r.Prompt) > 2000 {
 		return fmt.Errorf("prompt too long (max 2000)")
 	}
+	if len(r.Backend) > 32 || len(r.Model) > 64 {
+		return fmt.Errorf("backend or model name too long")
+	}
 	if r.Canon && strings.TrimSpace(r.Anime.ID) == "" && strings.TrimSpace(r.Anime.ShikimoriID) == "" {
 		return fmt.Errorf("canon mode requires an anime id or shikimori_id")
 	}
@@ -1,5 +1,7 @@
 package domain
 
+import "strings"
+
 // Tag is a curated fanfic tag with localized labels.
 type Tag struct {
 	Slug string `json:"slug"`
@@ -20,3 +22,31 @@ var CuratedTags = []Tag{
 	{"adventure", "приключения", "adventure"},
 	{"friendship", "дружба", "friendship"},
 }
+
+// NormalizeTag trims t and maps any curated tag (by slug or either label,
+// case-insensitively) to its slug, so "Slow Burn" and "медленное развитие"
+// both search as "slow-burn". Free-text tags are lowercased.
+func NormalizeTag(t string) string {
+	t = strings.ToLower(strings.TrimSpace(t))
+	for _, c := range CuratedTags {
+		if t == c.Slug || t == strings.ToLower(c.RU) || t == strings.ToLower(c.EN) {
+			return c.Slug
+		}
+	}
+	return t
+}
+
+// NormalizeTags normalizes and dedupes tags, dropping empty ones.
+func NormalizeTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		n := NormalizeTag(t)
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		out = append(out, n)
+	}
+	return out
+}
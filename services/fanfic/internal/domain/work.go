@@ -0,0 +1,214 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Chapter is one chapter of a human-authored work (a Fanfic with
+// Origin == OriginHuman). Readers only ever see published chapters.
+type Chapter struct {
+	ID          string     `gorm:"type:uuid;primaryKey" json:"id"`
+	FanficID    string     `gorm:"type:uuid;index;not null" json:"fanfic_id"`
+	Position    int        `gorm:"not null" json:"position"`
+	Title       string     `gorm:"size:512" json:"title"`
+	Content     string     `gorm:"type:text" json:"content"`
+	WordCount   int        `gorm:"default:0" json:"word_count"`
+	Status      string     `gorm:"size:16;index" json:"status"` // draft | scheduled | published
+	PublishAt   *time.Time `gorm:"index" json:"publish_at,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (Chapter) TableName() string { return "fanfic_chapters" }
+
+// BeforeCreate fills ID in Go, same as Fanfic.BeforeCreate.
+func (c *Chapter) BeforeCreate(*gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// Kudos is a reader's one-time appreciation of a work.
+type Kudos struct {
+	FanficID  string    `gorm:"type:uuid;primaryKey" json:"fanfic_id"`
+	UserID    string    `gorm:"type:uuid;primaryKey;index" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Kudos) TableName() string { return "fanfic_kudos" }
+
+// Bookmark is a work saved to a reader's bookmarks, with an optional note.
+type Bookmark struct {
+	UserID    string    `gorm:"type:uuid;primaryKey" json:"-"`
+	FanficID  string    `gorm:"type:uuid;primaryKey;index" json:"fanfic_id"`
+	Note      string    `gorm:"size:1000" json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Bookmark) TableName() string { return "fanfic_bookmarks" }
+
+// Subscription asks for a notification whenever the work gets a new chapter.
+type Subscription struct {
+	UserID    string    `gorm:"type:uuid;primaryKey" json:"-"`
+	FanficID  string    `gorm:"type:uuid;primaryKey;index" json:"fanfic_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Subscription) TableName() string { return "fanfic_subscriptions" }
+
+// WorkDetail is a work with the chapters the viewer may read and the
+// viewer's own kudos/bookmark/subscription state.
+type WorkDetail struct {
+	Fanfic
+	Chapters   []Chapter `json:"chapters"`
+	Kudosed    bool      `json:"kudosed"`
+	Bookmarked bool      `json:"bookmarked"`
+	Subscribed bool      `json:"subscribed"`
+}
+
+// BookmarkedWork is one entry of a reader's bookmarks list.
+type BookmarkedWork struct {
+	Fanfic
+	Note         string    `json:"note,omitempty"`
+	BookmarkedAt time.Time `json:"bookmarked_at"`
+}
+
+// WorkRequest is the POST/PUT /api/fanfic/works body. Tags are free text
+// like GenerateRequest's; curated ones are normalized to their slug.
+type WorkRequest struct {
+	Anime      AnimeRef       `json:"anime"`
+	Title      string         `json:"title"`
+	Summary    string         `json:"summary"`
+	Characters []CharacterRef `json:"characters"`
+	Tags       []string       `json:"tags"`
+	Rating     string         `json:"rating"`
+	Language   string         `json:"language"`
+}
+
+// Validate implements httputil.Validator.
+func (r WorkRequest) Validate() error {
+	if strings.TrimSpace(r.Anime.Title) == "" {
+		return fmt.Errorf("anime title is required")
+	}
+	if strings.TrimSpace(r.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	if utf8.RuneCountInString(r.Title) > 200 {
+		return fmt.Errorf("title too long (max 200)")
+	}
+	if utf8.RuneCountInString(r.Summary) > 2000 {
+		return fmt.Errorf("summary too long (max 2000)")
+	}
+	if !validRating[r.Rating] {
+		return fmt.Errorf("invalid rating %q", r.Rating)
+	}
+	if !validWorkLanguage[r.Language] {
+		return fmt.Errorf("invalid language %q", r.Language)
+	}
+	if len(r.Characters) > 12 {
+		return fmt.Errorf("too many characters (max 12)")
+	}
+	if len(r.Tags) > 16 {
+		return fmt.Errorf("too many tags (max 16)")
+	}
+	for _, t := range r.Tags {
+		if utf8.RuneCountInString(t) > 32 {
+			return fmt.Errorf("tag too long (max 32): %q", t)
+		}
+	}
+	return nil
+}
+
+// ChapterRequest is the chapter create/edit body. Drafts may be empty.
+type ChapterRequest struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// Validate implements httputil.Validator.
+func (r ChapterRequest) Validate() error {
+	if utf8.RuneCountInString(r.Title) > 200 {
+		return fmt.Errorf("title too long (max 200)")
+	}
+	if utf8.RuneCountInString(r.Content) > 300000 {
+		return fmt.Errorf("chapter too long (max 300000)")
+	}
+	return nil
+}
+
+// PublishRequest publishes a chapter now (PublishAt nil or past) or
+// schedules it for PublishAt.
+type PublishRequest struct {
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+// BookmarkRequest is the PUT /works/{id}/bookmark body.
+type BookmarkRequest struct {
+	Note string `json:"note"`
+}
+
+// Validate implements httputil.Validator.
+func (r BookmarkRequest) Validate() error {
+	if utf8.RuneCountInString(r.Note) > 1000 {
+		return fmt.Errorf("note too long (max 1000)")
+	}
+	return nil
+}
+
+// Search sort orders.
+const (
+	SortRecent = "recent"
+	SortKudos  = "kudos"
+)
+
+// SearchParams filters the public works listing. Empty fields do not filter.
+type SearchParams struct {
+	Query       string
+	AnimeID     string
+	ShikimoriID string
+	Tags        []string // every tag must match
+	Rating      string
+	Length      string
+	Origin      string
+	Sort        string
+	Limit       int
+	Offset      int
+}
+
+// LengthLong is the length bucket beyond "short"; only human works reach it.
+const LengthLong = "long"
+
+// ValidSearchLength reports whether length is a searchable length bucket.
+func ValidSearchLength(length string) bool {
+	return validLength[length] || length == LengthLong
+}
+
+// ValidSearchRating reports whether rating is a known rating.
+func ValidSearchRating(rating string) bool { return validRating[rating] }
+
+// LengthForWords buckets a human work's word count into the same length
+// presets generated fanfics use, so one length filter covers both origins.
+func LengthForWords(words int) string {
+	switch {
+	case words < 1000:
+		return "drabble"
+	case words < 7500:
+		return "oneshot"
+	case words < 40000:
+		return "short"
+	default:
+		return LengthLong
+	}
+}
+
+// CountWords counts whitespace-separated words.
+func CountWords(s string) int { return len(strings.Fields(s)) }
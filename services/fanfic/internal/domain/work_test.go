@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func validWorkReq() WorkRequest {
+	return WorkRequest{
+		Anime:    AnimeRef{Title: "Frieren"},
+		Title:    "Тихий вечер",
+		Rating:   "teen",
+		Language: "ru",
+	}
+}
+
+func TestWorkRequestValidate(t *testing.T) {
+	if err := validWorkReq().Validate(); err != nil {
+		t.Fatalf("expected valid, got %v", err)
+	}
+	ja := validWorkReq()
+	ja.Language = "ja"
+	if err := ja.Validate(); err != nil {
+		t.Fatalf("japanese work: %v", err)
+	}
+	cases := map[string]func(*WorkRequest){
+		"anime":    func(r *WorkRequest) { r.Anime.Title = "" },
+		"title":    func(r *WorkRequest) { r.Title = "  " },
+		"long":     func(r *WorkRequest) { r.Title = strings.Repeat("я", 201) },
+		"rating":   func(r *WorkRequest) { r.Rating = "nsfw" },
+		"language": func(r *WorkRequest) { r.Language = "de" },
+		"tag":      func(r *WorkRequest) { r.Tags = []string{strings.Repeat("a", 33)} },
+	}
+	for name, mut := range cases {
+		r := validWorkReq()
+		mut(&r)
+		if err := r.Validate(); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
+
+func TestLengthForWords(t *testing.T) {
+	cases := map[int]string{0: "drabble", 999: "drabble", 1000: "oneshot", 7500: "short", 40000: LengthLong}
+	for words, want := range cases {
+		if got := LengthForWords(words); got != want {
+			t.Errorf("LengthForWords(%d) = %q; want %q", words, got, want)
+		}
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got := NormalizeTags([]string{"Slow Burn", "медленное развитие", " AU ", "", "Своё"})
+	want := []string{"slow-burn", "au", "своё"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("NormalizeTags = %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,198 @@
+// Package export renders fanfics into downloadable books — EPUB 3 and a
+// simple PDF. It is a pure renderer: the service layer assembles a Book from
+// stored fanfics and chapters and handles caching and storage.
+package export
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	// Poster decoders for NormalizeCover.
+	_ "image/gif"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+)
+
+// Book is one export: a single work, or a set of works (a reader's bookmarks).
+type Book struct {
+	ID       string // stable identifier, used for the EPUB dc:identifier
+	Title    string
+	Language string // "ru" | "en" — picks the labels and the EPUB xml:lang
+	Modified time.Time
+	Cover    *Image // nil ⇒ no cover
+	Works    []Work
+}
+
+// Work is one fanfic with its metadata and parts, in reading order.
+type Work struct {
+	Title      string
+	Authors    []string
+	Anime      string
+	Characters []string
+	Tags       []string
+	Rating     string
+	Summary    string
+	Parts      []Part
+}
+
+// Part is one chapter. Text is the stored markdown-lite: blank-line separated
+// paragraphs, "#"/"##"/"###" headings and "---" scene breaks.
+type Part struct {
+	Title string
+	Text  string
+}
+
+// Image is an embedded JPEG.
+type Image struct {
+	Data          []byte
+	Width, Height int
+}
+
+// NormalizeCover decodes a poster in any supported format (JPEG, PNG, GIF,
+// WebP) and re-encodes it as JPEG, the one format both renderers embed.
+func NormalizeCover(data []byte) (*Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	// Flatten onto RGB: grayscale sources would otherwise encode as a
+	// one-component JPEG, which the PDF declares as DeviceRGB.
+	b := img.Bounds()
+	rgb := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgb, rgb.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(rgb, rgb.Bounds(), img, b.Min, draw.Over)
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, rgb, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return &Image{Data: buf.Bytes(), Width: b.Dx(), Height: b.Dy()}, nil
+}
+
+// labels are the localized strings both renderers print.
+type labels struct {
+	Author, Anime, Characters, Tags, Rating, Contents string
+}
+
+func labelsFor(language string) labels {
+	if language == "ru" {
+		return labels{Author: "Автор", Anime: "Аниме", Characters: "Персонажи", Tags: "Метки", Rating: "Рейтинг", Contents: "Содержание"}
+	}
+	return labels{Author: "Author", Anime: "Anime", Characters: "Characters", Tags: "Tags", Rating: "Rating", Contents: "Contents"}
+}
+
+// metaLines returns the work's "Label: value" metadata lines, skipping empty ones.
+func (w Work) metaLines(l labels) [][2]string {
+	var out [][2]string
+	add := func(label string, values ...string) {
+		var vs []string
+		for _, v := range values {
+			if v = strings.TrimSpace(v); v != "" {
+				vs = append(vs, v)
+			}
+		}
+		if len(vs) > 0 {
+			out = append(out, [2]string{label, strings.Join(vs, ", ")})
+		}
+	}
+	add(l.Author, w.Authors...)
+	add(l.Anime, w.Anime)
+	add(l.Characters, w.Characters...)
+	add(l.Tags, w.Tags...)
+	add(l.Rating, w.Rating)
+	return out
+}
+
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockRule
+)
+
+// block is one parsed unit of a Part's text.
+type block struct {
+	kind  blockKind
+	level int      // heading level, 1-3
+	lines []string // paragraph lines (kept line breaks) or the heading text
+}
+
+var (
+	headingRe = regexp.MustCompile(`^(#{1,3})\s+(.+)$`)
+	ruleRe    = regexp.MustCompile(`^(-{3,}|\*{3,}|(\* ){2,}\*)$`)
+)
+
+// parseBlocks splits markdown-lite text into paragraphs, headings and rules.
+func parseBlocks(text string) []block {
+	var out []block
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			out = append(out, block{kind: blockParagraph, lines: para})
+			para = nil
+		}
+	}
+	for _, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := strings.TrimRightFunc(raw, unicode.IsSpace)
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+		case ruleRe.MatchString(trimmed):
+			flush()
+			out = append(out, block{kind: blockRule})
+		case headingRe.MatchString(trimmed):
+			flush()
+			m := headingRe.FindStringSubmatch(trimmed)
+			out = append(out, block{kind: blockHeading, level: len(m[1]), lines: []string{m[2]}})
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+	return out
+}
+
+var emphasisRe = regexp.MustCompile(`\*\*([^*]+)\*\*|\*([^*\s][^*]*)\*`)
+
+// plainText drops **bold** / *italic* markers.
+func plainText(s string) string {
+	return emphasisRe.ReplaceAllString(s, "$1$2")
+}
+
+// cleanText drops runes XML 1.0 forbids (stray control characters in
+// generated text would make the EPUB unparseable).
+func cleanText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return r
+		}
+		if r < 0x20 || r == 0xFFFE || r == 0xFFFF || (r >= 0xD800 && r <= 0xDFFF) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// Paragraphs returns text's headings and paragraphs as plain strings in
+// reading order — rules and emphasis markers dropped, line breaks within a
+// paragraph kept. Narration reads the same blocks the renderers lay out.
+func Paragraphs(text string) []string {
+	var out []string
+	for _, b := range parseBlocks(cleanText(text)) {
+		if b.kind == blockRule {
+			continue
+		}
+		if p := strings.TrimSpace(plainText(strings.Join(b.lines, "\n"))); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,276 @@
+package export
+
+import (
+	"archive/zip"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+const epubCSS = `body { font-family: serif; line-height: 1.5; margin: 0 5%; }
+h1 { font-size: 1.6em; text-align: center; margin: 2em 0 1em; }
+h2 { font-size: 1.3em; margin: 1.5em 0 0.8em; }
+h3 { font-size: 1.1em; }
+p { margin: 0 0 0.8em; text-indent: 1.2em; }
+hr { border: none; text-align: center; margin: 1.5em 0; }
+hr::after { content: "* * *"; }
+dl.meta dt { font-weight: bold; margin-top: 0.6em; }
+dl.meta dd { margin: 0 0 0 1em; }
+p.summary { font-style: italic; text-indent: 0; margin-top: 1.5em; }
+div.cover { text-align: center; }
+div.cover img { max-width: 100%; max-height: 100%; }
+`
+
+// epubFile is one manifest entry.
+type epubFile struct {
+	id, href, mediaType, properties, body string
+	spine                                 bool
+}
+
+// WriteEPUB writes b to w as an EPUB 3 package: a cover page (when b has a
+// cover), one metadata page per work, and one XHTML document per part.
+func WriteEPUB(w io.Writer, b *Book) error {
+	l := labelsFor(b.Language)
+	var files []epubFile
+	var cover []byte
+
+	if b.Cover != nil {
+		cover = b.Cover.Data
+		files = append(files, epubFile{
+			id: "cover", href: "cover.xhtml", mediaType: "application/xhtml+xml", spine: true,
+			body: xhtml(b.Language, b.Title, `<div class="cover"><img src="cover.jpg" alt="`+esc(b.Title)+`"/></div>`),
+		})
+	}
+	multi := len(b.Works) > 1
+	if multi {
+		var sb strings.Builder
+		sb.WriteString("<h1>" + esc(b.Title) + "</h1>\n<h2>" + esc(l.Contents) + "</h2>\n<ol>\n")
+		for i, wk := range b.Works {
+			fmt.Fprintf(&sb, "<li><a href=\"w%d.xhtml\">%s</a></li>\n", i+1, esc(wk.Title))
+		}
+		sb.WriteString("</ol>\n")
+		files = append(files, epubFile{id: "title", href: "title.xhtml", mediaType: "application/xhtml+xml", spine: true,
+			body: xhtml(b.Language, b.Title, sb.String())})
+	}
+	for i, wk := range b.Works {
+		files = append(files, epubFile{
+			id: fmt.Sprintf("w%d", i+1), href: fmt.Sprintf("w%d.xhtml", i+1), mediaType: "application/xhtml+xml", spine: true,
+			body: xhtml(b.Language, wk.Title, workPage(wk, l)),
+		})
+		for j, p := range wk.Parts {
+			files = append(files, epubFile{
+				id: fmt.Sprintf("w%d-p%d", i+1, j+1), href: fmt.Sprintf("w%d-p%d.xhtml", i+1, j+1), mediaType: "application/xhtml+xml", spine: true,
+				body: xhtml(b.Language, p.Title, partPage(p)),
+			})
+		}
+	}
+	files = append(files, epubFile{id: "nav", href: "nav.xhtml", mediaType: "application/xhtml+xml", properties: "nav", spine: false,
+		body: navPage(b, l)})
+
+	zw := zip.NewWriter(w)
+	// The mimetype entry must come first and be stored uncompressed.
+	mw, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mw, "application/epub+zip"); err != nil {
+		return err
+	}
+	entries := []struct {
+		name string
+		data []byte
+	}{
+		{"META-INF/container.xml", []byte(containerXML)},
+		{"OEBPS/content.opf", []byte(packageOPF(b, files))},
+		{"OEBPS/style.css", []byte(epubCSS)},
+	}
+	if cover != nil {
+		entries = append(entries, struct {
+			name string
+			data []byte
+		}{"OEBPS/cover.jpg", cover})
+	}
+	for _, f := range files {
+		entries = append(entries, struct {
+			name string
+			data []byte
+		}{"OEBPS/" + f.href, []byte(f.body)})
+	}
+	for _, e := range entries {
+		fw, err := zw.Create(e.name)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(e.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+const containerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+// packageOPF renders the package document: Dublin Core metadata (authors,
+// tags and characters as subjects, anime as a collection), manifest, spine.
+func packageOPF(b *Book, files []epubFile) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&sb, `<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="bookid" xml:lang="%s">`+"\n", esc(b.Language))
+	sb.WriteString(`<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">` + "\n")
+	fmt.Fprintf(&sb, "<dc:identifier id=\"bookid\">urn:animeenigma:fanfic:%s</dc:identifier>\n", esc(b.ID))
+	fmt.Fprintf(&sb, "<dc:title>%s</dc:title>\n", esc(b.Title))
+	fmt.Fprintf(&sb, "<dc:language>%s</dc:language>\n", esc(b.Language))
+	sb.WriteString("<dc:publisher>AnimeEnigma</dc:publisher>\n")
+	var authors, subjects, anime []string
+	for _, wk := range b.Works {
+		authors = appendUnique(authors, wk.Authors...)
+		subjects = appendUnique(subjects, wk.Tags...)
+		subjects = appendUnique(subjects, wk.Characters...)
+		anime = appendUnique(anime, wk.Anime)
+	}
+	for _, a := range authors {
+		fmt.Fprintf(&sb, "<dc:creator>%s</dc:creator>\n", esc(a))
+	}
+	for _, s := range subjects {
+		fmt.Fprintf(&sb, "<dc:subject>%s</dc:subject>\n", esc(s))
+	}
+	if len(b.Works) == 1 && b.Works[0].Summary != "" {
+		fmt.Fprintf(&sb, "<dc:description>%s</dc:description>\n", esc(b.Works[0].Summary))
+	}
+	for i, a := range anime {
+		fmt.Fprintf(&sb, "<meta property=\"belongs-to-collection\" id=\"anime%d\">%s</meta>\n", i+1, esc(a))
+	}
+	fmt.Fprintf(&sb, "<meta property=\"dcterms:modified\">%s</meta>\n", b.Modified.UTC().Truncate(time.Second).Format("2006-01-02T15:04:05Z"))
+	if b.Cover != nil {
+		sb.WriteString(`<meta name="cover" content="cover-image"/>` + "\n")
+	}
+	sb.WriteString("</metadata>\n<manifest>\n")
+	sb.WriteString(`<item id="css" href="style.css" media-type="text/css"/>` + "\n")
+	if b.Cover != nil {
+		sb.WriteString(`<item id="cover-image" href="cover.jpg" media-type="image/jpeg" properties="cover-image"/>` + "\n")
+	}
+	for _, f := range files {
+		props := ""
+		if f.properties != "" {
+			props = ` properties="` + f.properties + `"`
+		}
+		fmt.Fprintf(&sb, "<item id=\"%s\" href=\"%s\" media-type=\"%s\"%s/>\n", f.id, f.href, f.mediaType, props)
+	}
+	sb.WriteString("</manifest>\n<spine>\n")
+	for _, f := range files {
+		if f.spine {
+			fmt.Fprintf(&sb, "<itemref idref=\"%s\"/>\n", f.id)
+		}
+	}
+	sb.WriteString("</spine>\n</package>\n")
+	return sb.String()
+}
+
+func navPage(b *Book, l labels) string {
+	var sb strings.Builder
+	sb.WriteString(`<nav epub:type="toc" id="toc"><h1>` + esc(l.Contents) + "</h1>\n<ol>\n")
+	for i, wk := range b.Works {
+		fmt.Fprintf(&sb, "<li><a href=\"w%d.xhtml\">%s</a>", i+1, esc(wk.Title))
+		if len(wk.Parts) > 0 {
+			sb.WriteString("\n<ol>\n")
+			for j, p := range wk.Parts {
+				fmt.Fprintf(&sb, "<li><a href=\"w%d-p%d.xhtml\">%s</a></li>\n", i+1, j+1, esc(p.Title))
+			}
+			sb.WriteString("</ol>\n")
+		}
+		sb.WriteString("</li>\n")
+	}
+	sb.WriteString("</ol>\n</nav>\n")
+	return xhtml(b.Language, l.Contents, sb.String())
+}
+
+func workPage(wk Work, l labels) string {
+	var sb strings.Builder
+	sb.WriteString("<h1>" + esc(wk.Title) + "</h1>\n")
+	if lines := wk.metaLines(l); len(lines) > 0 {
+		sb.WriteString("<dl class=\"meta\">\n")
+		for _, m := range lines {
+			fmt.Fprintf(&sb, "<dt>%s</dt><dd>%s</dd>\n", esc(m[0]), esc(m[1]))
+		}
+		sb.WriteString("</dl>\n")
+	}
+	if wk.Summary != "" {
+		sb.WriteString("<p class=\"summary\">" + esc(wk.Summary) + "</p>\n")
+	}
+	return sb.String()
+}
+
+func partPage(p Part) string {
+	var sb strings.Builder
+	sb.WriteString("<h2>" + esc(p.Title) + "</h2>\n")
+	for _, blk := range parseBlocks(p.Text) {
+		switch blk.kind {
+		case blockRule:
+			sb.WriteString("<hr/>\n")
+		case blockHeading:
+			lvl := blk.level + 1 // the part title is the h2
+			if lvl > 4 {
+				lvl = 4
+			}
+			fmt.Fprintf(&sb, "<h%d>%s</h%d>\n", lvl, inline(blk.lines[0]), lvl)
+		default:
+			parts := make([]string, len(blk.lines))
+			for i, line := range blk.lines {
+				parts[i] = inline(line)
+			}
+			sb.WriteString("<p>" + strings.Join(parts, "<br/>") + "</p>\n")
+		}
+	}
+	return sb.String()
+}
+
+// inline escapes s and turns **bold** / *italic* into strong / em.
+func inline(s string) string {
+	return emphasisRe.ReplaceAllStringFunc(esc(s), func(m string) string {
+		if strings.HasPrefix(m, "**") {
+			return "<strong>" + m[2:len(m)-2] + "</strong>"
+		}
+		return "<em>" + m[1:len(m)-1] + "</em>"
+	})
+}
+
+func xhtml(lang, title, body string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="` + esc(lang) + `" lang="` + esc(lang) + `">
+<head><meta charset="UTF-8"/><title>` + esc(title) + `</title><link rel="stylesheet" type="text/css" href="style.css"/></head>
+<body>
+` + body + `</body>
+</html>
+`
+}
+
+func esc(s string) string { return html.EscapeString(cleanText(s)) }
+
+func appendUnique(dst []string, values ...string) []string {
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		dup := false
+		for _, d := range dst {
+			if d == v {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			dst = append(dst, v)
+		}
+	}
+	return dst
+}
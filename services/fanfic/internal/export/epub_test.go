@@ -0,0 +1,151 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testBook() *Book {
+	return &Book{
+		ID:       "f1",
+		Title:    "Летний фестиваль",
+		Language: "ru",
+		Modified: time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC),
+		Works: []Work{{
+			Title:      "Летний фестиваль",
+			Authors:    []string{"Сгенерировано ИИ"},
+			Anime:      "Frieren",
+			Characters: []string{"Frieren", "Fern"},
+			Tags:       []string{"fluff"},
+			Summary:    "Short & sweet <summary>",
+			Parts: []Part{
+				{Title: "Часть 1", Text: "Первый абзац с *курсивом*.\nВторая строка.\n\n---\n\n## Сцена\n\nТекст."},
+				{Title: "Часть 2", Text: "Финал **жирным**."},
+			},
+		}},
+	}
+}
+
+func readZip(t *testing.T, data []byte) (*zip.Reader, map[string]string) {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(b)
+	}
+	return zr, files
+}
+
+func TestWriteEPUB_PackageLayoutAndMetadata(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteEPUB(&buf, testBook()); err != nil {
+		t.Fatalf("WriteEPUB: %v", err)
+	}
+	zr, files := readZip(t, buf.Bytes())
+
+	if first := zr.File[0]; first.Name != "mimetype" || first.Method != zip.Store || files["mimetype"] != "application/epub+zip" {
+		t.Fatalf("first entry = %s (method %d); want stored mimetype", first.Name, first.Method)
+	}
+	opf := files["OEBPS/content.opf"]
+	for _, want := range []string{
+		"<dc:identifier id=\"bookid\">urn:animeenigma:fanfic:f1</dc:identifier>",
+		"<dc:title>Летний фестиваль</dc:title>",
+		"<dc:language>ru</dc:language>",
+		"<dc:creator>Сгенерировано ИИ</dc:creator>",
+		"<dc:subject>fluff</dc:subject>",
+		"<dc:subject>Fern</dc:subject>",
+		"<dc:description>Short &amp; sweet &lt;summary&gt;</dc:description>",
+		"belongs-to-collection\" id=\"anime1\">Frieren</meta>",
+		"<meta property=\"dcterms:modified\">2026-07-01T12:00:00Z</meta>",
+		"properties=\"nav\"",
+	} {
+		if !strings.Contains(opf, want) {
+			t.Errorf("content.opf missing %q", want)
+		}
+	}
+	if strings.Contains(opf, "cover-image") {
+		t.Error("no cover given, but the OPF declares one")
+	}
+	if _, ok := files["OEBPS/w1-p2.xhtml"]; !ok {
+		t.Error("missing the second part document")
+	}
+	part := files["OEBPS/w1-p1.xhtml"]
+	for _, want := range []string{"<h2>Часть 1</h2>", "<em>курсивом</em>", "<br/>Вторая строка", "<hr/>", "<h3>Сцена</h3>"} {
+		if !strings.Contains(part, want) {
+			t.Errorf("part 1 missing %q:\n%s", want, part)
+		}
+	}
+	if nav := files["OEBPS/nav.xhtml"]; !strings.Contains(nav, `href="w1-p2.xhtml">Часть 2</a>`) {
+		t.Errorf("nav missing part 2:\n%s", nav)
+	}
+}
+
+func TestWriteEPUB_Cover(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 4, 6))
+	img.Set(1, 1, color.White)
+	var src bytes.Buffer
+	_ = png.Encode(&src, img)
+	cover, err := NormalizeCover(src.Bytes())
+	if err != nil {
+		t.Fatalf("NormalizeCover: %v", err)
+	}
+	if cover.Width != 4 || cover.Height != 6 {
+		t.Errorf("cover = %dx%d", cover.Width, cover.Height)
+	}
+
+	b := testBook()
+	b.Cover = cover
+	var buf bytes.Buffer
+	if err := WriteEPUB(&buf, b); err != nil {
+		t.Fatalf("WriteEPUB: %v", err)
+	}
+	_, files := readZip(t, buf.Bytes())
+	if !bytes.Equal([]byte(files["OEBPS/cover.jpg"]), cover.Data) {
+		t.Error("cover.jpg not embedded")
+	}
+	if !strings.Contains(files["OEBPS/content.opf"], `properties="cover-image"`) {
+		t.Error("OPF does not mark the cover image")
+	}
+}
+
+func TestParseBlocks(t *testing.T) {
+	blocks := parseBlocks("# Title\n\nline one\nline two\n\n* * *\n\nend")
+	if len(blocks) != 4 {
+		t.Fatalf("blocks = %+v", blocks)
+	}
+	if blocks[0].kind != blockHeading || blocks[0].level != 1 || blocks[0].lines[0] != "Title" {
+		t.Errorf("heading = %+v", blocks[0])
+	}
+	if blocks[1].kind != blockParagraph || len(blocks[1].lines) != 2 {
+		t.Errorf("paragraph = %+v", blocks[1])
+	}
+	if blocks[2].kind != blockRule {
+		t.Errorf("rule = %+v", blocks[2])
+	}
+}
+
+func TestParagraphs(t *testing.T) {
+	got := Paragraphs("## 第一章\n\n**彼女**は言った。\n「行こう」\n\n---\n\n*終わり*")
+	want := []string{"第一章", "彼女は言った。\n「行こう」", "終わり"}
+	if len(got) != len(want) {
+		t.Fatalf("paragraphs = %q", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("paragraph %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
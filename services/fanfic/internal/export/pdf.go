@@ -0,0 +1,437 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// A5 portrait, in PDF points — a comfortable e-reader / phone page.
+const (
+	pageWidth    = 420.0
+	pageHeight   = 595.0
+	pageMargin   = 48.0
+	footerHeight = 20.0
+	bodySize     = 11.0
+	leading      = 1.45 // line height as a multiple of the font size
+)
+
+// WritePDF writes b to w as a simple paginated PDF: a cover page (when b has
+// a cover), then per work a metadata page followed by its parts. Text is set
+// in the embedded Go fonts, which cover Latin and Cyrillic.
+func WritePDF(w io.Writer, b *Book) error {
+	regular, err := newPDFFont("F1", "GoRegular", goregular.TTF)
+	if err != nil {
+		return err
+	}
+	bold, err := newPDFFont("F2", "GoBold", gobold.TTF)
+	if err != nil {
+		return err
+	}
+	lay := &pdfLayout{regular: regular, bold: bold}
+	l := labelsFor(b.Language)
+
+	if b.Cover != nil {
+		lay.coverPage()
+	}
+	if len(b.Works) > 1 {
+		lay.newPage()
+		lay.centered(bold, 20, b.Title)
+		lay.gap(14)
+		lay.block(bold, 13, l.Contents, 0)
+		for i, wk := range b.Works {
+			lay.block(regular, bodySize, fmt.Sprintf("%d. %s", i+1, wk.Title), 0)
+		}
+	}
+	for _, wk := range b.Works {
+		lay.newPage()
+		lay.gap(pageHeight / 6)
+		lay.centered(bold, 20, wk.Title)
+		lay.gap(16)
+		for _, m := range wk.metaLines(l) {
+			lay.block(regular, bodySize, m[0]+": "+m[1], 0)
+		}
+		if wk.Summary != "" {
+			lay.gap(10)
+			lay.block(regular, bodySize, wk.Summary, 0)
+		}
+		for _, p := range wk.Parts {
+			lay.newPage()
+			lay.block(bold, 15, p.Title, 0)
+			lay.gap(8)
+			for _, blk := range parseBlocks(p.Text) {
+				switch blk.kind {
+				case blockRule:
+					lay.gap(4)
+					lay.centered(regular, bodySize, "* * *")
+					lay.gap(4)
+				case blockHeading:
+					lay.gap(6)
+					lay.block(bold, 14-float64(blk.level), plainText(blk.lines[0]), 0)
+				default:
+					for i, line := range blk.lines {
+						indent := 0.0
+						if i == 0 {
+							indent = 14
+						}
+						lay.block(regular, bodySize, plainText(line), indent)
+					}
+					lay.gap(bodySize * 0.4)
+				}
+			}
+		}
+	}
+	lay.pageNumbers()
+	return lay.write(w, b)
+}
+
+// pdfFont is an embedded TrueType font addressed by glyph id (Identity-H).
+type pdfFont struct {
+	res  string // resource name, e.g. F1
+	base string
+	ttf  []byte
+	f    *sfnt.Font
+	buf  sfnt.Buffer
+	upem fixed.Int26_6
+	used map[sfnt.GlyphIndex]rune
+	adv  map[sfnt.GlyphIndex]float64 // advance in 1/1000 em
+}
+
+func newPDFFont(res, base string, ttf []byte) (*pdfFont, error) {
+	f, err := sfnt.Parse(ttf)
+	if err != nil {
+		return nil, err
+	}
+	return &pdfFont{res: res, base: base, ttf: ttf, f: f, upem: fixed.I(int(f.UnitsPerEm())),
+		used: map[sfnt.GlyphIndex]rune{}, adv: map[sfnt.GlyphIndex]float64{}}, nil
+}
+
+func (f *pdfFont) glyph(r rune) sfnt.GlyphIndex {
+	g, err := f.f.GlyphIndex(&f.buf, r)
+	if err != nil || g == 0 {
+		g, _ = f.f.GlyphIndex(&f.buf, '?')
+	}
+	return g
+}
+
+func (f *pdfFont) advance(g sfnt.GlyphIndex) float64 {
+	if a, ok := f.adv[g]; ok {
+		return a
+	}
+	a, err := f.f.GlyphAdvance(&f.buf, g, f.upem, font.HintingNone)
+	w := 0.0
+	if err == nil {
+		w = float64(a) / float64(f.upem) * 1000
+	}
+	f.adv[g] = w
+	return w
+}
+
+// width is s's advance in points at size.
+func (f *pdfFont) width(s string, size float64) float64 {
+	total := 0.0
+	for _, r := range s {
+		total += f.advance(f.glyph(r))
+	}
+	return total * size / 1000
+}
+
+// encode returns s as a hex string of glyph ids, recording each for the
+// font's width and ToUnicode tables.
+func (f *pdfFont) encode(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('<')
+	for _, r := range s {
+		g := f.glyph(r)
+		if _, ok := f.used[g]; !ok {
+			f.used[g] = r
+			f.advance(g)
+		}
+		fmt.Fprintf(&sb, "%04X", uint16(g))
+	}
+	sb.WriteByte('>')
+	return sb.String()
+}
+
+// pdfLayout flows text top-down onto pages.
+type pdfLayout struct {
+	regular, bold *pdfFont
+	pages         []*bytes.Buffer
+	y             float64
+	hasCover      bool // the first page is the cover
+}
+
+func (l *pdfLayout) cur() *bytes.Buffer {
+	if len(l.pages) == 0 {
+		l.newPage()
+	}
+	return l.pages[len(l.pages)-1]
+}
+
+func (l *pdfLayout) newPage() {
+	l.pages = append(l.pages, &bytes.Buffer{})
+	l.y = pageHeight - pageMargin
+}
+
+func (l *pdfLayout) coverPage() {
+	l.newPage()
+	l.hasCover = true
+}
+
+func (l *pdfLayout) gap(h float64) { l.y -= h }
+
+// line places one line of text; it starts a new page when the line would
+// run into the footer.
+func (l *pdfLayout) line(f *pdfFont, size, x float64, s string) {
+	h := size * leading
+	if len(l.pages) == 0 || l.y-h < pageMargin+footerHeight {
+		l.newPage()
+	}
+	l.y -= h
+	fmt.Fprintf(l.cur(), "BT /%s %s Tf 1 0 0 1 %s %s Tm %s Tj ET\n", f.res, num(size), num(x), num(l.y+size*(leading-1)), f.encode(s))
+}
+
+func (l *pdfLayout) centered(f *pdfFont, size float64, s string) {
+	for _, ln := range wrap(f, size, s, pageWidth-2*pageMargin, 0) {
+		l.line(f, size, (pageWidth-f.width(ln, size))/2, ln)
+	}
+}
+
+// block wraps s to the text column, indenting its first line.
+func (l *pdfLayout) block(f *pdfFont, size float64, s string, indent float64) {
+	for i, ln := range wrap(f, size, s, pageWidth-2*pageMargin, indent) {
+		x := pageMargin
+		if i == 0 {
+			x += indent
+		}
+		l.line(f, size, x, ln)
+	}
+}
+
+// pageNumbers stamps a centered number on every page but the cover.
+func (l *pdfLayout) pageNumbers() {
+	for i, p := range l.pages {
+		if l.hasCover && i == 0 {
+			continue
+		}
+		s := strconv.Itoa(i + 1)
+		x := (pageWidth - l.regular.width(s, 9)) / 2
+		fmt.Fprintf(p, "BT /%s 9 Tf 1 0 0 1 %s %s Tm %s Tj ET\n", l.regular.res, num(x), num(pageMargin/2), l.regular.encode(s))
+	}
+}
+
+// wrap breaks s into lines no wider than width (the first line is indent
+// narrower), splitting over-long words by rune.
+func wrap(f *pdfFont, size float64, s string, width, indent float64) []string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return nil
+	}
+	space := f.width(" ", size)
+	var lines []string
+	cur, curW, avail := "", 0.0, width-indent
+	for _, w := range words {
+		ww := f.width(w, size)
+		for ww > avail && cur == "" {
+			// A single word wider than the column: hard-break it.
+			head, rest := splitToWidth(f, size, w, avail)
+			lines = append(lines, head)
+			w, ww, avail = rest, f.width(rest, size), width
+		}
+		switch {
+		case cur == "":
+			cur, curW = w, ww
+		case curW+space+ww <= avail:
+			cur += " " + w
+			curW += space + ww
+		default:
+			lines = append(lines, cur)
+			avail = width
+			cur, curW = w, ww
+			for curW > avail {
+				head, rest := splitToWidth(f, size, cur, avail)
+				lines = append(lines, head)
+				cur, curW = rest, f.width(rest, size)
+			}
+		}
+	}
+	if cur != "" {
+		lines = append(lines, cur)
+	}
+	return lines
+}
+
+func splitToWidth(f *pdfFont, size float64, w string, width float64) (string, string) {
+	runes := []rune(w)
+	total := 0.0
+	for i, r := range runes {
+		total += f.width(string(r), size)
+		if total > width && i > 0 {
+			return string(runes[:i]), string(runes[i:])
+		}
+	}
+	return w, ""
+}
+
+// pdfWriter serializes numbered objects and the cross-reference table.
+type pdfWriter struct {
+	out     bytes.Buffer
+	offsets map[int]int
+	next    int
+}
+
+func (w *pdfWriter) alloc() int {
+	w.next++
+	return w.next
+}
+
+func (w *pdfWriter) obj(n int, body string) {
+	w.offsets[n] = w.out.Len()
+	fmt.Fprintf(&w.out, "%d 0 obj\n%s\nendobj\n", n, body)
+}
+
+// stream writes a Flate-compressed stream object; extra goes into its dict.
+func (w *pdfWriter) stream(n int, extra string, data []byte) {
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	_, _ = zw.Write(data)
+	_ = zw.Close()
+	w.rawStream(n, "/Filter /FlateDecode "+extra, z.Bytes())
+}
+
+func (w *pdfWriter) rawStream(n int, dict string, data []byte) {
+	w.offsets[n] = w.out.Len()
+	fmt.Fprintf(&w.out, "%d 0 obj\n<< %s /Length %d >>\nstream\n", n, dict, len(data))
+	w.out.Write(data)
+	w.out.WriteString("\nendstream\nendobj\n")
+}
+
+func (l *pdfLayout) write(out io.Writer, b *Book) error {
+	w := &pdfWriter{offsets: map[int]int{}}
+	w.out.WriteString("%PDF-1.7\n%\xE2\xE3\xCF\xD3\n")
+	catalog, pages, info := w.alloc(), w.alloc(), w.alloc()
+
+	fonts := ""
+	for _, f := range []*pdfFont{l.regular, l.bold} {
+		fonts += fmt.Sprintf("/%s %d 0 R ", f.res, w.writeFont(f))
+	}
+	image := 0
+	if l.hasCover {
+		image = w.alloc()
+		w.rawStream(image, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode",
+			b.Cover.Width, b.Cover.Height), b.Cover.Data)
+		// Fit the cover inside the margins, centered.
+		scale := min((pageWidth-2*pageMargin)/float64(b.Cover.Width), (pageHeight-2*pageMargin)/float64(b.Cover.Height))
+		iw, ih := float64(b.Cover.Width)*scale, float64(b.Cover.Height)*scale
+		fmt.Fprintf(l.pages[0], "q %s 0 0 %s %s %s cm /Im1 Do Q\n", num(iw), num(ih), num((pageWidth-iw)/2), num((pageHeight-ih)/2))
+	}
+	resources := "<< /Font << " + fonts + ">>"
+	if image != 0 {
+		resources += fmt.Sprintf(" /XObject << /Im1 %d 0 R >>", image)
+	}
+	resources += " >>"
+
+	kids := make([]string, 0, len(l.pages))
+	for _, p := range l.pages {
+		page, content := w.alloc(), w.alloc()
+		w.stream(content, "", p.Bytes())
+		w.obj(page, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources %s /Contents %d 0 R >>",
+			pages, num(pageWidth), num(pageHeight), resources, content))
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+	}
+	w.obj(pages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+	w.obj(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages))
+
+	var authors []string
+	for _, wk := range b.Works {
+		authors = appendUnique(authors, wk.Authors...)
+	}
+	w.obj(info, fmt.Sprintf("<< /Title %s /Author %s /Producer %s /CreationDate (D:%s) >>",
+		pdfText(b.Title), pdfText(strings.Join(authors, ", ")), pdfText("AnimeEnigma"), b.Modified.UTC().Format("20060102150405Z")))
+
+	xref := w.out.Len()
+	fmt.Fprintf(&w.out, "xref\n0 %d\n0000000000 65535 f \n", w.next+1)
+	for n := 1; n <= w.next; n++ {
+		fmt.Fprintf(&w.out, "%010d 00000 n \n", w.offsets[n])
+	}
+	fmt.Fprintf(&w.out, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", w.next+1, catalog, info, xref)
+	_, err := out.Write(w.out.Bytes())
+	return err
+}
+
+// writeFont embeds f as a Type0 / CIDFontType2 font and returns the Type0
+// object number.
+func (w *pdfWriter) writeFont(f *pdfFont) int {
+	type0, cid, desc, file, toUni := w.alloc(), w.alloc(), w.alloc(), w.alloc(), w.alloc()
+
+	w.stream(file, fmt.Sprintf("/Length1 %d", len(f.ttf)), f.ttf)
+
+	upem := fixed.I(int(f.f.UnitsPerEm()))
+	m, _ := f.f.Metrics(&f.buf, upem, font.HintingNone)
+	bounds, _ := f.f.Bounds(&f.buf, upem, font.HintingNone)
+	scale := func(v fixed.Int26_6) int { return int(float64(v) / float64(upem) * 1000) }
+	w.obj(desc, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		f.base, scale(bounds.Min.X), -scale(bounds.Max.Y), scale(bounds.Max.X), -scale(bounds.Min.Y),
+		scale(m.Ascent), -scale(m.Descent), scale(m.CapHeight), file))
+
+	glyphs := make([]int, 0, len(f.used))
+	for g := range f.used {
+		glyphs = append(glyphs, int(g))
+	}
+	sort.Ints(glyphs)
+	var widths strings.Builder
+	for _, g := range glyphs {
+		fmt.Fprintf(&widths, "%d [%d] ", g, int(f.adv[sfnt.GlyphIndex(g)]))
+	}
+	w.obj(cid, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /CIDToGIDMap /Identity /DW 1000 /W [%s] >>",
+		f.base, desc, widths.String()))
+
+	var cmap strings.Builder
+	cmap.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	for i := 0; i < len(glyphs); i += 100 {
+		chunk := glyphs[i:min(i+100, len(glyphs))]
+		fmt.Fprintf(&cmap, "%d beginbfchar\n", len(chunk))
+		for _, g := range chunk {
+			fmt.Fprintf(&cmap, "<%04X> <%s>\n", g, utf16Hex(string(f.used[sfnt.GlyphIndex(g)])))
+		}
+		cmap.WriteString("endbfchar\n")
+	}
+	cmap.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	w.stream(toUni, "", []byte(cmap.String()))
+
+	w.obj(type0, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		f.base, cid, toUni))
+	return type0
+}
+
+// pdfText encodes s as a UTF-16BE text string with a byte-order mark.
+func pdfText(s string) string { return "<FEFF" + utf16Hex(s) + ">" }
+
+func utf16Hex(s string) string {
+	var sb strings.Builder
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&sb, "%04X", u)
+	}
+	return sb.String()
+}
+
+// num formats a coordinate with at most two decimals.
+func num(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
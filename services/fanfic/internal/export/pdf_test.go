@@ -0,0 +1,70 @@
+package export
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestWritePDF_Structure(t *testing.T) {
+	b := testBook()
+	b.Works[0].Parts[1].Text = strings.Repeat("Очень длинный абзац, который не помещается на одну страницу. ", 400)
+	var buf bytes.Buffer
+	if err := WritePDF(&buf, b); err != nil {
+		t.Fatalf("WritePDF: %v", err)
+	}
+	pdf := buf.String()
+
+	if !strings.HasPrefix(pdf, "%PDF-1.7\n") || !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Fatal("missing PDF header or trailer")
+	}
+	for _, want := range []string{"/BaseFont /GoRegular", "/BaseFont /GoBold", "/Encoding /Identity-H", "/FontFile2", "/ToUnicode"} {
+		if !strings.Contains(pdf, want) {
+			t.Errorf("PDF missing %q", want)
+		}
+	}
+	// Metadata page + part 1 + a multi-page part 2.
+	m := regexp.MustCompile(`/Type /Pages /Kids \[[^]]*\] /Count (\d+)`).FindStringSubmatch(pdf)
+	if m == nil || m[1] == "1" || m[1] == "2" || m[1] == "3" {
+		t.Errorf("page count = %v; want the long part to overflow onto several pages", m)
+	}
+	if strings.Contains(pdf, "/XObject") {
+		t.Error("no cover given, but the PDF embeds an image")
+	}
+
+	// startxref must point at the xref table.
+	i := strings.LastIndex(pdf, "startxref\n")
+	var off int
+	for _, c := range pdf[i+len("startxref\n"):] {
+		if c < '0' || c > '9' {
+			break
+		}
+		off = off*10 + int(c-'0')
+	}
+	if !strings.HasPrefix(pdf[off:], "xref\n") {
+		t.Errorf("startxref %d does not point at the xref table", off)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	f, err := newPDFFont("F1", "GoRegular", goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := wrap(f, 11, strings.Repeat("слово ", 60)+strings.Repeat("x", 200), 300, 14)
+	if len(lines) < 3 {
+		t.Fatalf("lines = %d", len(lines))
+	}
+	for i, ln := range lines {
+		limit := 300.0
+		if i == 0 {
+			limit -= 14
+		}
+		if w := f.width(ln, 11); w > limit {
+			t.Errorf("line %d is %.1fpt wide; limit %.0f", i, w, limit)
+		}
+	}
+}
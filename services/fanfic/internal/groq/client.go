@@ -1,141 +1,17 @@
-// Package groq is a minimal OpenAI-compatible streaming client for the Groq API.
+// Package groq configures the Groq API as an llm backend — Groq speaks the
+// OpenAI chat/completions dialect, so it is an llm.OpenAIClient under a name.
 package groq
 
 import (
-	"bufio"
-	"bytes"
-	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 	"time"
-)
-
-type Client struct {
-	apiKey  string
-	baseURL string
-	model   string
-	http    *http.Client
-}
-
-// StatusError is a non-200 response from Groq. It preserves the HTTP status so
-// callers (ensure-daily) can distinguish auth failures (401/403) from transient
-// errors without string-matching.
-type StatusError struct {
-	Code int
-	Body string
-}
-
-func (e *StatusError) Error() string {
-	return fmt.Sprintf("groq status %d: %s", e.Code, e.Body)
-}
-
-func New(apiKey, baseURL, model string, timeout time.Duration) *Client {
-	return &Client{
-		apiKey:  apiKey,
-		baseURL: strings.TrimRight(baseURL, "/"),
-		model:   model,
-		http:    &http.Client{Timeout: timeout},
-	}
-}
-
-type message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-type chatRequest struct {
-	Model         string        `json:"model"`
-	Messages      []message     `json:"messages"`
-	MaxTokens     int           `json:"max_tokens"`
-	Temperature   float64       `json:"temperature"`
-	Stream        bool          `json:"stream"`
-	StreamOptions streamOptions `json:"stream_options"`
-}
-
-type streamOptions struct {
-	IncludeUsage bool `json:"include_usage"`
-}
 
-type sseChunk struct {
-	Choices []struct {
-		Delta struct {
-			Content string `json:"content"`
-		} `json:"delta"`
-	} `json:"choices"`
-	Usage *struct {
-		TotalTokens int `json:"total_tokens"`
-	} `json:"usage"`
-}
-
-// Stream calls chat/completions with stream:true, invoking onDelta for each token
-// chunk. It returns the full accumulated text and total token usage (0 if absent).
-func (c *Client) Stream(ctx context.Context, system, user string, maxTokens int, temperature float64, onDelta func(string)) (string, int, error) {
-	body, err := json.Marshal(chatRequest{
-		Model: c.model,
-		Messages: []message{
-			{Role: "system", Content: system},
-			{Role: "user", Content: user},
-		},
-		MaxTokens:     maxTokens,
-		Temperature:   temperature,
-		Stream:        true,
-		StreamOptions: streamOptions{IncludeUsage: true},
-	})
-	if err != nil {
-		return "", 0, err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
-	if err != nil {
-		return "", 0, err
-	}
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "text/event-stream")
-
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return "", 0, err
-	}
-	defer resp.Body.Close()
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/llm"
+)
 
-	if resp.StatusCode != http.StatusOK {
-		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
-		return "", 0, &StatusError{Code: resp.StatusCode, Body: strings.TrimSpace(string(snippet))}
-	}
+// Name is the backend name Groq registers under (FANFIC_BACKENDS, stored rows).
+const Name = "groq"
 
-	var sb strings.Builder
-	usage := 0
-	scanner := bufio.NewScanner(resp.Body)
-	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if !strings.HasPrefix(line, "data: ") {
-			continue
-		}
-		payload := strings.TrimPrefix(line, "data: ")
-		if payload == "[DONE]" {
-			break
-		}
-		var chunk sseChunk
-		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
-			continue // tolerate keep-alive / malformed lines
-		}
-		if chunk.Usage != nil {
-			usage = chunk.Usage.TotalTokens
-		}
-		for _, ch := range chunk.Choices {
-			if ch.Delta.Content != "" {
-				sb.WriteString(ch.Delta.Content)
-				onDelta(ch.Delta.Content)
-			}
-		}
-	}
-	if err := scanner.Err(); err != nil {
-		return sb.String(), usage, fmt.Errorf("reading groq stream: %w", err)
-	}
-	return sb.String(), usage, nil
+// New builds the Groq backend serving models, default first.
+func New(apiKey, baseURL string, models []string, timeout time.Duration) *llm.OpenAIClient {
+	return llm.NewOpenAI(Name, apiKey, baseURL, models, timeout)
 }
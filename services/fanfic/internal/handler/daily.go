@@ -8,7 +8,7 @@ import (
 	"github.com/ILITA-hub/animeenigma/libs/authz"
 	"github.com/ILITA-hub/animeenigma/libs/httputil"
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
-	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/groq"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/llm"
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/service"
 )
 
@@ -97,19 +97,19 @@ type ensureResponse struct {
 }
 
 // Ensure serves POST /internal/fanfic/ensure-daily — the scheduler's cron
-// hitting the idempotent generate-if-missing flow. A Groq auth failure
-// (401/403, surfaced as a *groq.StatusError) has ALREADY fired a Telegram
+// hitting the idempotent generate-if-missing flow. A backend auth failure
+// (401/403, surfaced as an *llm.StatusError) has ALREADY fired a Telegram
 // alert inside EnsureDaily itself — the alert IS the operator-facing signal
-// — so this responds 200 with error:"groq_auth" instead of 500, letting the
+// — so this responds 200 with error:"backend_auth" instead of 500, letting the
 // scheduler record a normal "ran successfully" tick rather than flag a
 // retry-worthy scheduler fault. Any other error (DB write failure, etc.) is a
 // genuine handler-level fault and gets a real 500.
 func (h *DailyHandler) Ensure(w http.ResponseWriter, r *http.Request) {
 	res, err := h.daily.EnsureDaily(r.Context())
 	if err != nil {
-		var se *groq.StatusError
-		if errors.As(err, &se) && (se.Code == http.StatusUnauthorized || se.Code == http.StatusForbidden) {
-			httputil.OK(w, ensureResponse{Generated: false, Error: "groq_auth"})
+		var se *llm.StatusError
+		if errors.As(err, &se) && service.IsAuthStatus(se.Code) {
+			httputil.OK(w, ensureResponse{Generated: false, Error: "backend_auth"})
 			return
 		}
 		httputil.Error(w, err)
@@ -11,7 +11,7 @@ import (
 
 	"github.com/ILITA-hub/animeenigma/libs/authz"
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
-	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/groq"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/llm"
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/service"
 )
 
@@ -166,19 +166,19 @@ func TestEnsure_NoOp(t *testing.T) {
 	}
 }
 
-// TestEnsure_GroqAuthFailure_Returns200 verifies the groq-auth classification:
+// TestEnsure_BackendAuthFailure_Returns200 verifies the backend-auth classification:
 // EnsureDaily already fired the Telegram alert (that's the operator signal),
-// so the handler must respond 200 with error:"groq_auth" — NOT 500 — so the
+// so the handler must respond 200 with error:"backend_auth" — NOT 500 — so the
 // scheduler records a normal "ran successfully" tick instead of a
 // retry-worthy fault.
-func TestEnsure_GroqAuthFailure_Returns200(t *testing.T) {
-	wrapped := fmt.Errorf("ensure-daily: groq: %w", &groq.StatusError{Code: http.StatusUnauthorized, Body: "bad key"})
+func TestEnsure_BackendAuthFailure_Returns200(t *testing.T) {
+	wrapped := fmt.Errorf("ensure-daily: generate: %w", &llm.StatusError{Backend: "groq", Code: http.StatusUnauthorized, Body: "bad key"})
 	h := NewDailyHandler(&fakeDaily{ensureErr: wrapped})
 	rec := httptest.NewRecorder()
 	h.Ensure(rec, httptest.NewRequest(http.MethodPost, "/internal/fanfic/ensure-daily", nil))
 
 	if rec.Code != http.StatusOK {
-		t.Fatalf("status = %d, want 200 (groq auth failure already alerted upstream)", rec.Code)
+		t.Fatalf("status = %d, want 200 (backend auth failure already alerted upstream)", rec.Code)
 	}
 	var resp struct {
 		Data ensureResponse `json:"data"`
@@ -186,13 +186,13 @@ func TestEnsure_GroqAuthFailure_Returns200(t *testing.T) {
 	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
 		t.Fatal(err)
 	}
-	if resp.Data.Generated || resp.Data.Error != "groq_auth" {
-		t.Errorf("resp = %+v, want generated=false error=groq_auth", resp.Data)
+	if resp.Data.Generated || resp.Data.Error != "backend_auth" {
+		t.Errorf("resp = %+v, want generated=false error=backend_auth", resp.Data)
 	}
 }
 
 func TestEnsure_Forbidden_Returns200(t *testing.T) {
-	wrapped := fmt.Errorf("ensure-daily: groq: %w", &groq.StatusError{Code: http.StatusForbidden, Body: "forbidden"})
+	wrapped := fmt.Errorf("ensure-daily: generate: %w", &llm.StatusError{Backend: "groq", Code: http.StatusForbidden, Body: "forbidden"})
 	h := NewDailyHandler(&fakeDaily{ensureErr: wrapped})
 	rec := httptest.NewRecorder()
 	h.Ensure(rec, httptest.NewRequest(http.MethodPost, "/internal/fanfic/ensure-daily", nil))
@@ -206,6 +206,6 @@ func TestEnsure_TransientError_Returns500(t *testing.T) {
 	rec := httptest.NewRecorder()
 	h.Ensure(rec, httptest.NewRequest(http.MethodPost, "/internal/fanfic/ensure-daily", nil))
 	if rec.Code != http.StatusInternalServerError {
-		t.Fatalf("status = %d, want 500 for a non-groq-auth error", rec.Code)
+		t.Fatalf("status = %d, want 500 for a non-backend-auth error", rec.Code)
 	}
 }
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// exportService is the subset of *service.ExportService this handler depends on.
+type exportService interface {
+	RequestFanfic(ctx context.Context, userID, fanficID, format string) (*domain.Export, error)
+	RequestBookmarks(ctx context.Context, userID, format string) (*domain.Export, error)
+	Get(ctx context.Context, userID, id string) (*domain.Export, error)
+	DownloadURL(ctx context.Context, userID, id string) (*domain.Export, string, error)
+}
+
+var exportContentTypes = map[string]string{
+	domain.ExportEPUB: "application/epub+zip",
+	domain.ExportPDF:  "application/pdf",
+}
+
+// ExportHandler serves EPUB/PDF exports of fanfics and bookmark sets.
+type ExportHandler struct {
+	exports exportService
+	httpGet func(ctx context.Context, url string) (*http.Response, error)
+}
+
+func NewExportHandler(exports exportService) *ExportHandler {
+	return &ExportHandler{exports: exports, httpGet: func(ctx context.Context, url string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		return http.DefaultClient.Do(req)
+	}}
+}
+
+// Fanfic serves POST /api/fanfic/{id}/export {"format":"epub"|"pdf"}.
+// Answers 202 while the export renders in the background, 200 once a cached
+// file is ready.
+func (h *ExportHandler) Fanfic(w http.ResponseWriter, r *http.Request) {
+	var req domain.ExportRequest
+	if err := httputil.BindAndValidate(r, &req); err != nil {
+		httputil.BadRequest(w, err.Error())
+		return
+	}
+	e, err := h.exports.RequestFanfic(r.Context(), authz.UserIDFromContext(r.Context()), chi.URLParam(r, "id"), req.Format)
+	h.respond(w, e, err)
+}
+
+// Bookmarks serves POST /api/fanfic/bookmarks/export — the caller's
+// bookmarked works as one book.
+func (h *ExportHandler) Bookmarks(w http.ResponseWriter, r *http.Request) {
+	var req domain.ExportRequest
+	if err := httputil.BindAndValidate(r, &req); err != nil {
+		httputil.BadRequest(w, err.Error())
+		return
+	}
+	e, err := h.exports.RequestBookmarks(r.Context(), authz.UserIDFromContext(r.Context()), req.Format)
+	h.respond(w, e, err)
+}
+
+// Get serves GET /api/fanfic/exports/{id} — the client polls it until the
+// status is ready (or failed).
+func (h *ExportHandler) Get(w http.ResponseWriter, r *http.Request) {
+	e, err := h.exports.Get(r.Context(), authz.UserIDFromContext(r.Context()), chi.URLParam(r, "id"))
+	h.respond(w, e, err)
+}
+
+// Download serves GET /api/fanfic/exports/{id}/download, streaming the
+// stored file (the presigned storage URL is internal-only).
+func (h *ExportHandler) Download(w http.ResponseWriter, r *http.Request) {
+	e, url, err := h.exports.DownloadURL(r.Context(), authz.UserIDFromContext(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	resp, err := h.httpGet(r.Context(), url)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", exportContentTypes[e.Format])
+	w.Header().Set("Content-Disposition", contentDisposition(e.Title, e.Format))
+	if cl := resp.Header.Get("Content-Length"); cl != "" {
+		w.Header().Set("Content-Length", cl)
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+func (h *ExportHandler) respond(w http.ResponseWriter, e *domain.Export, err error) {
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	status := http.StatusAccepted
+	if e.Status == domain.ExportReady {
+		status = http.StatusOK
+		e.DownloadURL = "/api/fanfic/exports/" + e.ID + "/download"
+	} else if e.Status == domain.ExportFailed {
+		status = http.StatusOK
+	}
+	httputil.JSON(w, status, e)
+}
+
+// contentDisposition names the download after the book title: an ASCII
+// fallback plus the RFC 5987 UTF-8 form browsers prefer.
+func contentDisposition(title, format string) string {
+	name := strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == '"' || unicode.IsControl(r) {
+			return '_'
+		}
+		return r
+	}, title))
+	if name == "" {
+		name = "fanfic"
+	}
+	ascii := strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII {
+			return -1
+		}
+		return r
+	}, name)
+	if ascii = strings.TrimSpace(ascii); ascii == "" {
+		ascii = "fanfic"
+	}
+	ext := "." + format
+	return `attachment; filename="` + ascii + ext + `"; filename*=UTF-8''` + url.PathEscape(name+ext)
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+)
+
+type fakeExports struct {
+	export *domain.Export
+	format string
+}
+
+func (f *fakeExports) RequestFanfic(_ context.Context, _, _, format string) (*domain.Export, error) {
+	f.format = format
+	return f.export, nil
+}
+func (f *fakeExports) RequestBookmarks(_ context.Context, _, format string) (*domain.Export, error) {
+	f.format = format
+	return f.export, nil
+}
+func (f *fakeExports) Get(context.Context, string, string) (*domain.Export, error) {
+	return f.export, nil
+}
+func (f *fakeExports) DownloadURL(context.Context, string, string) (*domain.Export, string, error) {
+	return f.export, "http://minio/fanfic-exports/x/book.epub", nil
+}
+
+func TestExportFanfic_AcceptedUntilReady(t *testing.T) {
+	svc := &fakeExports{export: &domain.Export{ID: "e1", Status: domain.ExportPending}}
+	h := NewExportHandler(svc)
+
+	req := withURLParam(withUser(httptest.NewRequest(http.MethodPost, "/api/fanfic/f1/export", strings.NewReader(`{"format":"pdf"}`))), "id", "f1")
+	rec := httptest.NewRecorder()
+	h.Fanfic(rec, req)
+	if rec.Code != http.StatusAccepted || svc.format != domain.ExportPDF {
+		t.Fatalf("pending: status %d, format %q; want 202 pdf", rec.Code, svc.format)
+	}
+
+	svc.export.Status = domain.ExportReady
+	rec = httptest.NewRecorder()
+	h.Get(rec, withURLParam(withUser(httptest.NewRequest(http.MethodGet, "/api/fanfic/exports/e1", nil)), "id", "e1"))
+	var body struct {
+		Data domain.Export `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != http.StatusOK || body.Data.DownloadURL != "/api/fanfic/exports/e1/download" {
+		t.Errorf("ready: status %d, body %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.Bookmarks(rec, withUser(httptest.NewRequest(http.MethodPost, "/api/fanfic/bookmarks/export", strings.NewReader(`{"format":"mobi"}`))))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown format: status %d; want 400", rec.Code)
+	}
+}
+
+func TestExportDownload_StreamsWithFilename(t *testing.T) {
+	svc := &fakeExports{export: &domain.Export{ID: "e1", Status: domain.ExportReady, Format: domain.ExportEPUB, Title: "Летний фестиваль"}}
+	h := NewExportHandler(svc)
+	h.httpGet = func(_ context.Context, url string) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("EPUB"))}, nil
+	}
+
+	rec := httptest.NewRecorder()
+	h.Download(rec, withURLParam(withUser(httptest.NewRequest(http.MethodGet, "/api/fanfic/exports/e1/download", nil)), "id", "e1"))
+	if rec.Code != http.StatusOK || rec.Body.String() != "EPUB" {
+		t.Fatalf("status %d body %q", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/epub+zip" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	cd := rec.Header().Get("Content-Disposition")
+	if !strings.Contains(cd, `filename="fanfic.epub"`) || !strings.Contains(cd, "filename*=UTF-8''%D0%9B") {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+}
@@ -10,8 +10,8 @@ import (
 	liberrors "github.com/ILITA-hub/animeenigma/libs/errors"
 	"github.com/ILITA-hub/animeenigma/libs/httputil"
 	"github.com/ILITA-hub/animeenigma/libs/logger"
-	"github.com/ILITA-hub/animeenigma/libs/pagination"
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/llm"
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/service"
 	"github.com/go-chi/chi/v5"
 )
@@ -34,14 +34,20 @@ type libraryStore interface {
 	SoftDelete(ctx context.Context, userID, id string) error
 }
 
+// backendLister lists the configured generation backends (*llm.Router).
+type backendLister interface {
+	Backends() []llm.BackendInfo
+}
+
 type Handler struct {
-	gen  generator
-	repo libraryStore
-	log  *logger.Logger
+	gen      generator
+	repo     libraryStore
+	backends backendLister
+	log      *logger.Logger
 }
 
-func NewHandler(gen generator, repo libraryStore, log *logger.Logger) *Handler {
-	return &Handler{gen: gen, repo: repo, log: log}
+func NewHandler(gen generator, repo libraryStore, backends backendLister, log *logger.Logger) *Handler {
+	return &Handler{gen: gen, repo: repo, backends: backends, log: log}
 }
 
 // Generate streams a fanfic as SSE and persists it on completion.
@@ -137,17 +143,7 @@ func (h *Handler) Continue(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	userID := authz.UserIDFromContext(r.Context())
-	page := pagination.ParseIntParam(r.URL.Query().Get("page"), 1)
-	limit := pagination.ParseIntParam(r.URL.Query().Get("limit"), 20)
-	if limit < 1 {
-		limit = 20
-	}
-	if limit > 100 {
-		limit = 100
-	}
-	if page < 1 {
-		page = 1
-	}
+	page, limit := pageParams(r)
 	items, total, err := h.repo.List(r.Context(), userID, limit, (page-1)*limit)
 	if err != nil {
 		httputil.Error(w, err)
@@ -178,3 +174,10 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) Tags(w http.ResponseWriter, _ *http.Request) {
 	httputil.OK(w, domain.CuratedTags)
 }
+
+// Models serves GET /api/fanfic/models — the generation backends in fallback
+// order with the models each serves (default first), for the generate form's
+// backend/model picker.
+func (h *Handler) Models(w http.ResponseWriter, _ *http.Request) {
+	httputil.OK(w, h.backends.Backends())
+}
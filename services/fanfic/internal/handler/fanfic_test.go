@@ -87,7 +87,7 @@ func withURLParam(r *http.Request, key, value string) *http.Request {
 }
 
 func TestGenerate_SSE(t *testing.T) {
-	h := NewHandler(&fakeGen{}, &fakeLib{}, nil)
+	h := NewHandler(&fakeGen{}, &fakeLib{}, nil, nil)
 	req := withUser(httptest.NewRequest(http.MethodPost, "/api/fanfic/generate",
 		strings.NewReader(`{"anime":{"title":"Frieren"},"length":"oneshot","pov":"third","rating":"teen","language":"ru"}`)))
 	rec := httptest.NewRecorder()
@@ -110,7 +110,7 @@ func TestGenerate_SSE(t *testing.T) {
 }
 
 func TestGenerate_ValidationError(t *testing.T) {
-	h := NewHandler(&fakeGen{}, &fakeLib{}, nil)
+	h := NewHandler(&fakeGen{}, &fakeLib{}, nil, nil)
 	req := withUser(httptest.NewRequest(http.MethodPost, "/api/fanfic/generate",
 		strings.NewReader(`{"anime":{"title":""},"length":"oneshot","pov":"third","rating":"teen","language":"ru"}`)))
 	rec := httptest.NewRecorder()
@@ -133,7 +133,7 @@ func TestList_ClampsLimit(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.query, func(t *testing.T) {
 			lib := &fakeLib{}
-			h := NewHandler(&fakeGen{}, lib, nil)
+			h := NewHandler(&fakeGen{}, lib, nil, nil)
 			req := withUser(httptest.NewRequest(http.MethodGet, "/api/fanfic?"+tc.query, nil))
 			rec := httptest.NewRecorder()
 			h.List(rec, req)
@@ -145,7 +145,7 @@ func TestList_ClampsLimit(t *testing.T) {
 }
 
 func TestTags(t *testing.T) {
-	h := NewHandler(&fakeGen{}, &fakeLib{}, nil)
+	h := NewHandler(&fakeGen{}, &fakeLib{}, nil, nil)
 	rec := httptest.NewRecorder()
 	h.Tags(rec, httptest.NewRequest(http.MethodGet, "/api/fanfic/tags", nil))
 	var resp struct {
@@ -159,7 +159,7 @@ func TestTags(t *testing.T) {
 
 func TestContinue_409OnNonComplete(t *testing.T) {
 	repo := &fakeLib{get: &domain.Fanfic{ID: "f1", UserID: "u-1", Status: domain.StatusGenerating}}
-	h := NewHandler(&fakeGen{}, repo, nil)
+	h := NewHandler(&fakeGen{}, repo, nil, nil)
 
 	req := withUser(httptest.NewRequest(http.MethodPost, "/api/fanfic/f1/continue", nil))
 	req = withURLParam(req, "id", "f1")
@@ -174,7 +174,7 @@ func TestContinue_409OnNonComplete(t *testing.T) {
 func TestContinue_StreamsWhenComplete(t *testing.T) {
 	repo := &fakeLib{get: &domain.Fanfic{ID: "f1", UserID: "u-1", Status: domain.StatusComplete}}
 	gen := &fakeGen{continueEvents: []string{"meta", "delta", "done"}}
-	h := NewHandler(gen, repo, nil)
+	h := NewHandler(gen, repo, nil, nil)
 
 	req := withUser(httptest.NewRequest(http.MethodPost, "/api/fanfic/f1/continue", nil))
 	req = withURLParam(req, "id", "f1")
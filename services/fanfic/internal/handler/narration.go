@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/voicevox"
+	"github.com/go-chi/chi/v5"
+)
+
+// narrationService is the subset of *service.NarrationService this handler depends on.
+type narrationService interface {
+	Speakers(ctx context.Context) ([]voicevox.Speaker, error)
+	Request(ctx context.Context, userID, fanficID string, req domain.NarrationRequest) (*domain.Narration, error)
+	Get(ctx context.Context, userID, id string) (*domain.Narration, error)
+	Resume(ctx context.Context, userID, id string) (*domain.Narration, error)
+	DownloadURL(ctx context.Context, userID, id string) (*domain.Narration, string, error)
+}
+
+var narrationContentTypes = map[string]string{
+	domain.NarrationOpus: "audio/ogg",
+	domain.NarrationMP3:  "audio/mpeg",
+}
+
+// NarrationHandler serves VOICEVOX narrations of Japanese fanfics.
+type NarrationHandler struct {
+	narrations narrationService
+	httpGet    func(ctx context.Context, url string) (*http.Response, error)
+}
+
+func NewNarrationHandler(narrations narrationService) *NarrationHandler {
+	return &NarrationHandler{narrations: narrations, httpGet: func(ctx context.Context, url string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		return http.DefaultClient.Do(req)
+	}}
+}
+
+// Speakers serves GET /api/fanfic/narration/speakers — the voices a
+// narration may be read in; 503 while the platform sheds.
+func (h *NarrationHandler) Speakers(w http.ResponseWriter, r *http.Request) {
+	speakers, err := h.narrations.Speakers(r.Context())
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, speakers)
+}
+
+// Request serves POST /api/fanfic/{id}/narration
+// {"style_id":3,"speed":1,"format":"opus"|"mp3"}. Answers 202 while the job
+// runs in the background, 200 once a cached file is ready.
+func (h *NarrationHandler) Request(w http.ResponseWriter, r *http.Request) {
+	var req domain.NarrationRequest
+	if err := httputil.BindAndValidate(r, &req); err != nil {
+		httputil.BadRequest(w, err.Error())
+		return
+	}
+	n, err := h.narrations.Request(r.Context(), authz.UserIDFromContext(r.Context()), chi.URLParam(r, "id"), req)
+	h.respond(w, n, err)
+}
+
+// Get serves GET /api/fanfic/narrations/{id} — the job status with its
+// chunks_done/chunks_total progress; clients poll it until ready (or failed).
+func (h *NarrationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	n, err := h.narrations.Get(r.Context(), authz.UserIDFromContext(r.Context()), chi.URLParam(r, "id"))
+	h.respond(w, n, err)
+}
+
+// Resume serves POST /api/fanfic/narrations/{id}/resume — requeues a failed
+// or paused job from its last synthesized chunk.
+func (h *NarrationHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	n, err := h.narrations.Resume(r.Context(), authz.UserIDFromContext(r.Context()), chi.URLParam(r, "id"))
+	h.respond(w, n, err)
+}
+
+// Download serves GET /api/fanfic/narrations/{id}/download, streaming the
+// stored audio (the presigned storage URL is internal-only).
+func (h *NarrationHandler) Download(w http.ResponseWriter, r *http.Request) {
+	n, url, err := h.narrations.DownloadURL(r.Context(), authz.UserIDFromContext(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	resp, err := h.httpGet(r.Context(), url)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", narrationContentTypes[n.Format])
+	w.Header().Set("Content-Disposition", contentDisposition(n.Title, n.Format))
+	if cl := resp.Header.Get("Content-Length"); cl != "" {
+		w.Header().Set("Content-Length", cl)
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+func (h *NarrationHandler) respond(w http.ResponseWriter, n *domain.Narration, err error) {
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	status := http.StatusAccepted
+	if n.Status == domain.NarrationReady {
+		status = http.StatusOK
+		n.DownloadURL = "/api/fanfic/narrations/" + n.ID + "/download"
+	} else if n.Status == domain.NarrationFailed {
+		status = http.StatusOK
+	}
+	httputil.JSON(w, status, n)
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	liberrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/voicevox"
+)
+
+type fakeNarrations struct {
+	narration  *domain.Narration
+	req        domain.NarrationRequest
+	resumed    bool
+	speakerErr error
+}
+
+func (f *fakeNarrations) Speakers(context.Context) ([]voicevox.Speaker, error) {
+	if f.speakerErr != nil {
+		return nil, f.speakerErr
+	}
+	return []voicevox.Speaker{{Name: "ずんだもん", Styles: []voicevox.Style{{ID: 3, Name: "ノーマル"}}}}, nil
+}
+func (f *fakeNarrations) Request(_ context.Context, _, _ string, req domain.NarrationRequest) (*domain.Narration, error) {
+	f.req = req
+	return f.narration, nil
+}
+func (f *fakeNarrations) Get(context.Context, string, string) (*domain.Narration, error) {
+	return f.narration, nil
+}
+func (f *fakeNarrations) Resume(context.Context, string, string) (*domain.Narration, error) {
+	f.resumed = true
+	f.narration.Status = domain.NarrationPending
+	return f.narration, nil
+}
+func (f *fakeNarrations) DownloadURL(context.Context, string, string) (*domain.Narration, string, error) {
+	return f.narration, "http://minio/fanfic-narrations/x/narration.opus", nil
+}
+
+func TestNarrationRequest_AcceptedWithProgress(t *testing.T) {
+	svc := &fakeNarrations{narration: &domain.Narration{ID: "n1", Status: domain.NarrationRunning, ChunksTotal: 10, ChunksDone: 4}}
+	h := NewNarrationHandler(svc)
+
+	req := withURLParam(withUser(httptest.NewRequest(http.MethodPost, "/api/fanfic/f1/narration", strings.NewReader(`{"style_id":0,"format":"mp3"}`))), "id", "f1")
+	rec := httptest.NewRecorder()
+	h.Request(rec, req)
+	if rec.Code != http.StatusAccepted || svc.req.StyleID == nil || *svc.req.StyleID != 0 || svc.req.Format != domain.NarrationMP3 {
+		t.Fatalf("status %d, req %+v; want 202 with style 0", rec.Code, svc.req)
+	}
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &body)
+	if body.Data["chunks_done"] != 4.0 || body.Data["chunks_total"] != 10.0 || body.Data["status"] != "running" {
+		t.Errorf("body = %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.Request(rec, withURLParam(withUser(httptest.NewRequest(http.MethodPost, "/api/fanfic/f1/narration", strings.NewReader(`{"format":"opus"}`))), "id", "f1"))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("missing style: status %d; want 400", rec.Code)
+	}
+}
+
+func TestNarrationResumeAndReady(t *testing.T) {
+	svc := &fakeNarrations{narration: &domain.Narration{ID: "n1", Status: domain.NarrationFailed}}
+	h := NewNarrationHandler(svc)
+
+	rec := httptest.NewRecorder()
+	h.Resume(rec, withURLParam(withUser(httptest.NewRequest(http.MethodPost, "/api/fanfic/narrations/n1/resume", nil)), "id", "n1"))
+	if rec.Code != http.StatusAccepted || !svc.resumed {
+		t.Fatalf("resume: status %d, resumed %v", rec.Code, svc.resumed)
+	}
+
+	svc.narration.Status = domain.NarrationReady
+	rec = httptest.NewRecorder()
+	h.Get(rec, withURLParam(withUser(httptest.NewRequest(http.MethodGet, "/api/fanfic/narrations/n1", nil)), "id", "n1"))
+	var body struct {
+		Data domain.Narration `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != http.StatusOK || body.Data.DownloadURL != "/api/fanfic/narrations/n1/download" {
+		t.Errorf("ready: status %d, body %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestNarrationDownload_StreamsAudio(t *testing.T) {
+	svc := &fakeNarrations{narration: &domain.Narration{ID: "n1", Status: domain.NarrationReady, Format: domain.NarrationOpus, Title: "夏祭り"}}
+	h := NewNarrationHandler(svc)
+	h.httpGet = func(_ context.Context, url string) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("OggS"))}, nil
+	}
+
+	rec := httptest.NewRecorder()
+	h.Download(rec, withURLParam(withUser(httptest.NewRequest(http.MethodGet, "/api/fanfic/narrations/n1/download", nil)), "id", "n1"))
+	if rec.Code != http.StatusOK || rec.Body.String() != "OggS" {
+		t.Fatalf("status %d body %q", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "audio/ogg" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.Contains(cd, "filename*=UTF-8''%E5%A4%8F") || !strings.HasSuffix(cd, ".opus") {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+}
+
+func TestNarrationSpeakers_ShedIs503(t *testing.T) {
+	svc := &fakeNarrations{speakerErr: liberrors.ServiceUnavailable("narration is paused")}
+	rec := httptest.NewRecorder()
+	NewNarrationHandler(svc).Speakers(rec, withUser(httptest.NewRequest(http.MethodGet, "/api/fanfic/narration/speakers", nil)))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status %d; want 503", rec.Code)
+	}
+
+	svc.speakerErr = nil
+	rec = httptest.NewRecorder()
+	NewNarrationHandler(svc).Speakers(rec, withUser(httptest.NewRequest(http.MethodGet, "/api/fanfic/narration/speakers", nil)))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "ずんだもん") {
+		t.Errorf("status %d body %s", rec.Code, rec.Body.String())
+	}
+}
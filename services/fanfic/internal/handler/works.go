@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/pagination"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// workService is the subset of *service.WorkService this handler depends on.
+type workService interface {
+	Create(ctx context.Context, userID, username string, req domain.WorkRequest) (*domain.Fanfic, error)
+	Update(ctx context.Context, userID, id string, req domain.WorkRequest) (*domain.Fanfic, error)
+	Get(ctx context.Context, viewerID, id string) (*domain.WorkDetail, error)
+	AddChapter(ctx context.Context, userID, workID string, req domain.ChapterRequest) (*domain.Chapter, error)
+	UpdateChapter(ctx context.Context, userID, workID, chapterID string, req domain.ChapterRequest) (*domain.Chapter, error)
+	DeleteChapter(ctx context.Context, userID, workID, chapterID string) error
+	PublishChapter(ctx context.Context, userID, workID, chapterID string, req domain.PublishRequest) (*domain.Chapter, error)
+	Unschedule(ctx context.Context, userID, workID, chapterID string) (*domain.Chapter, error)
+	Search(ctx context.Context, p domain.SearchParams) ([]domain.Fanfic, int64, error)
+	Kudos(ctx context.Context, userID, id string) error
+	Bookmark(ctx context.Context, userID, id, note string) error
+	Unbookmark(ctx context.Context, userID, id string) error
+	Bookmarks(ctx context.Context, userID string, limit, offset int) ([]domain.BookmarkedWork, int64, error)
+	Subscribe(ctx context.Context, userID, id string) error
+	Unsubscribe(ctx context.Context, userID, id string) error
+}
+
+// WorkHandler serves human-authored works and the reader-side kudos,
+// bookmarks, subscriptions and search.
+type WorkHandler struct {
+	works workService
+}
+
+func NewWorkHandler(works workService) *WorkHandler {
+	return &WorkHandler{works: works}
+}
+
+// Search serves GET /api/fanfic/works — public works of both origins.
+//
+//	?q=&anime_id=&shikimori_id=&tags=a,b&rating=&length=&origin=human|generated&sort=recent|kudos&page=&limit=
+func (h *WorkHandler) Search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	page, limit := pageParams(r)
+	p := domain.SearchParams{
+		Query:       q.Get("q"),
+		AnimeID:     q.Get("anime_id"),
+		ShikimoriID: q.Get("shikimori_id"),
+		Rating:      q.Get("rating"),
+		Length:      q.Get("length"),
+		Origin:      q.Get("origin"),
+		Sort:        q.Get("sort"),
+		Limit:       limit,
+		Offset:      (page - 1) * limit,
+	}
+	if tags := q.Get("tags"); tags != "" {
+		p.Tags = httputil.ParseCommaList(tags)
+	}
+	items, total, err := h.works.Search(r.Context(), p)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	if items == nil {
+		items = []domain.Fanfic{}
+	}
+	httputil.JSON(w, http.StatusOK, map[string]any{"items": items, "total": total, "page": page, "limit": limit})
+}
+
+// Create serves POST /api/fanfic/works — starts a draft work.
+func (h *WorkHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := authz.UserIDFromContext(r.Context())
+	if userID == "" {
+		httputil.Unauthorized(w)
+		return
+	}
+	username := ""
+	if claims, _ := authz.ClaimsFromContext(r.Context()); claims != nil {
+		username = claims.Username
+	}
+	var req domain.WorkRequest
+	if err := httputil.BindAndValidate(r, &req); err != nil {
+		httputil.BadRequest(w, err.Error())
+		return
+	}
+	f, err := h.works.Create(r.Context(), userID, username, req)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.Created(w, f)
+}
+
+// Get serves GET /api/fanfic/works/{id}.
+func (h *WorkHandler) Get(w http.ResponseWriter, r *http.Request) {
+	detail, err := h.works.Get(r.Context(), authz.UserIDFromContext(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, detail)
+}
+
+// Update serves PUT /api/fanfic/works/{id} — replaces the work's metadata.
+func (h *WorkHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req domain.WorkRequest
+	if err := httputil.BindAndValidate(r, &req); err != nil {
+		httputil.BadRequest(w, err.Error())
+		return
+	}
+	f, err := h.works.Update(r.Context(), authz.UserIDFromContext(r.Context()), chi.URLParam(r, "id"), req)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, f)
+}
+
+// AddChapter serves POST /api/fanfic/works/{id}/chapters — adds a draft.
+func (h *WorkHandler) AddChapter(w http.ResponseWriter, r *http.Request) {
+	var req domain.ChapterRequest
+	if err := httputil.BindAndValidate(r, &req); err != nil {
+		httputil.BadRequest(w, err.Error())
+		return
+	}
+	c, err := h.works.AddChapter(r.Context(), authz.UserIDFromContext(r.Context()), chi.URLParam(r, "id"), req)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.Created(w, c)
+}
+
+// UpdateChapter serves PUT /api/fanfic/works/{id}/chapters/{chapterID}.
+func (h *WorkHandler) UpdateChapter(w http.ResponseWriter, r *http.Request) {
+	var req domain.ChapterRequest
+	if err := httputil.BindAndValidate(r, &req); err != nil {
+		httputil.BadRequest(w, err.Error())
+		return
+	}
+	c, err := h.works.UpdateChapter(r.Context(), authz.UserIDFromContext(r.Context()),
+		chi.URLParam(r, "id"), chi.URLParam(r, "chapterID"), req)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, c)
+}
+
+// DeleteChapter serves DELETE /api/fanfic/works/{id}/chapters/{chapterID}.
+func (h *WorkHandler) DeleteChapter(w http.ResponseWriter, r *http.Request) {
+	if err := h.works.DeleteChapter(r.Context(), authz.UserIDFromContext(r.Context()),
+		chi.URLParam(r, "id"), chi.URLParam(r, "chapterID")); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.NoContent(w)
+}
+
+// PublishChapter serves POST /api/fanfic/works/{id}/chapters/{chapterID}/publish.
+// An empty body or a past publish_at publishes now; a future one schedules.
+func (h *WorkHandler) PublishChapter(w http.ResponseWriter, r *http.Request) {
+	var req domain.PublishRequest
+	if r.ContentLength != 0 {
+		if err := httputil.Bind(r, &req); err != nil {
+			httputil.BadRequest(w, err.Error())
+			return
+		}
+	}
+	c, err := h.works.PublishChapter(r.Context(), authz.UserIDFromContext(r.Context()),
+		chi.URLParam(r, "id"), chi.URLParam(r, "chapterID"), req)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, c)
+}
+
+// Unschedule serves DELETE /api/fanfic/works/{id}/chapters/{chapterID}/publish
+// — turns a scheduled chapter back into a draft.
+func (h *WorkHandler) Unschedule(w http.ResponseWriter, r *http.Request) {
+	c, err := h.works.Unschedule(r.Context(), authz.UserIDFromContext(r.Context()),
+		chi.URLParam(r, "id"), chi.URLParam(r, "chapterID"))
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, c)
+}
+
+// Kudos serves POST /api/fanfic/works/{id}/kudos.
+func (h *WorkHandler) Kudos(w http.ResponseWriter, r *http.Request) {
+	if err := h.works.Kudos(r.Context(), authz.UserIDFromContext(r.Context()), chi.URLParam(r, "id")); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.NoContent(w)
+}
+
+// Bookmark serves PUT /api/fanfic/works/{id}/bookmark.
+func (h *WorkHandler) Bookmark(w http.ResponseWriter, r *http.Request) {
+	var req domain.BookmarkRequest
+	if r.ContentLength != 0 {
+		if err := httputil.BindAndValidate(r, &req); err != nil {
+			httputil.BadRequest(w, err.Error())
+			return
+		}
+	}
+	if err := h.works.Bookmark(r.Context(), authz.UserIDFromContext(r.Context()), chi.URLParam(r, "id"), req.Note); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.NoContent(w)
+}
+
+// Unbookmark serves DELETE /api/fanfic/works/{id}/bookmark.
+func (h *WorkHandler) Unbookmark(w http.ResponseWriter, r *http.Request) {
+	if err := h.works.Unbookmark(r.Context(), authz.UserIDFromContext(r.Context()), chi.URLParam(r, "id")); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.NoContent(w)
+}
+
+// Bookmarks serves GET /api/fanfic/bookmarks — the caller's bookmarks.
+func (h *WorkHandler) Bookmarks(w http.ResponseWriter, r *http.Request) {
+	page, limit := pageParams(r)
+	items, total, err := h.works.Bookmarks(r.Context(), authz.UserIDFromContext(r.Context()), limit, (page-1)*limit)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	if items == nil {
+		items = []domain.BookmarkedWork{}
+	}
+	httputil.JSON(w, http.StatusOK, map[string]any{"items": items, "total": total, "page": page, "limit": limit})
+}
+
+// Subscribe serves PUT /api/fanfic/works/{id}/subscription.
+func (h *WorkHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	if err := h.works.Subscribe(r.Context(), authz.UserIDFromContext(r.Context()), chi.URLParam(r, "id")); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.NoContent(w)
+}
+
+// Unsubscribe serves DELETE /api/fanfic/works/{id}/subscription.
+func (h *WorkHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	if err := h.works.Unsubscribe(r.Context(), authz.UserIDFromContext(r.Context()), chi.URLParam(r, "id")); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.NoContent(w)
+}
+
+// pageParams parses ?page=&limit= with the library list's defaults and caps.
+func pageParams(r *http.Request) (page, limit int) {
+	page = pagination.ParseIntParam(r.URL.Query().Get("page"), 1)
+	limit = pagination.ParseIntParam(r.URL.Query().Get("limit"), 20)
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if page < 1 {
+		page = 1
+	}
+	return page, limit
+}
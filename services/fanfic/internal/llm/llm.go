@@ -0,0 +1,258 @@
+// Package llm abstracts the text-generation backends the fanfic service can
+// stream from (Groq, or any OpenAI-compatible endpoint such as a self-hosted
+// llama.cpp / vLLM server) and routes a generation across them with fallback.
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	liberrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+)
+
+// ErrBudgetExhausted marks a backend skipped because its daily token budget
+// is spent.
+var ErrBudgetExhausted = errors.New("daily token budget exhausted")
+
+// Prompt is one chat completion's input.
+type Prompt struct {
+	System      string
+	User        string
+	MaxTokens   int
+	Temperature float64
+}
+
+// Generator is one streaming text-generation backend.
+type Generator interface {
+	// Name identifies the backend in config, API and stored rows.
+	Name() string
+	// Models lists the models the backend serves; the first is its default.
+	Models() []string
+	// Stream generates with model, invoking onDelta per token chunk, and
+	// returns the full text and total token usage (0 if unreported).
+	Stream(ctx context.Context, model string, p Prompt, onDelta func(string)) (string, int, error)
+}
+
+// StatusError is a non-200 response from a backend. It preserves the HTTP
+// status so callers (ensure-daily) can distinguish auth failures (401/403)
+// from transient errors without string-matching.
+type StatusError struct {
+	Backend string
+	Code    int
+	Body    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s status %d: %s", e.Backend, e.Code, e.Body)
+}
+
+// Target is one backend+model pair a generation may run on.
+type Target struct {
+	Backend string `json:"backend"`
+	Model   string `json:"model"`
+}
+
+// Failure is one target that did not produce a result. Partial reports that
+// it had already streamed deltas before failing, so the client must discard
+// what it rendered. Usage is the tokens the attempt was metered at.
+type Failure struct {
+	Target
+	Err     error
+	Partial bool
+	Usage   int
+}
+
+// Result is a successful routed generation.
+type Result struct {
+	Target
+	Text     string
+	Usage    int
+	Failures []Failure // targets tried (and failed) before this one
+}
+
+// ExhaustedError is returned when every target in a plan failed. It unwraps
+// to each failure's error, so errors.As still finds a *StatusError.
+type ExhaustedError struct {
+	Failures []Failure
+}
+
+func (e *ExhaustedError) Error() string {
+	parts := make([]string, len(e.Failures))
+	for i, f := range e.Failures {
+		parts[i] = fmt.Sprintf("%s/%s: %v", f.Backend, f.Model, f.Err)
+	}
+	return "all generation backends failed: " + strings.Join(parts, "; ")
+}
+
+func (e *ExhaustedError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = f.Err
+	}
+	return errs
+}
+
+// Hooks receives a routed stream's progress. Nil fields are skipped.
+type Hooks struct {
+	Delta func(text string)
+	// Fallback fires when failed is abandoned and next is about to be tried.
+	Fallback func(failed Failure, next Target)
+}
+
+// Budget gates and meters per-backend token spend (service.Quota).
+type Budget interface {
+	BackendAvailable(ctx context.Context, backend string) bool
+	AddTokens(ctx context.Context, backend string, tokens int)
+}
+
+// BackendInfo is the GET /api/fanfic/models wire shape.
+type BackendInfo struct {
+	Name   string   `json:"name"`
+	Models []string `json:"models"`
+}
+
+// Router streams a generation from the configured backends in order,
+// falling back to the next one when a backend errors or is over budget.
+type Router struct {
+	backends []Generator
+	budget   Budget
+	log      *logger.Logger
+}
+
+// NewRouter builds a router over backends in fallback order. budget may be nil.
+func NewRouter(backends []Generator, budget Budget, log *logger.Logger) *Router {
+	return &Router{backends: backends, budget: budget, log: log}
+}
+
+// Backends lists the configured backends in fallback order.
+func (r *Router) Backends() []BackendInfo {
+	out := make([]BackendInfo, 0, len(r.backends))
+	for _, b := range r.backends {
+		out = append(out, BackendInfo{Name: b.Name(), Models: b.Models()})
+	}
+	return out
+}
+
+// Plan resolves the caller's preference into an ordered target list: the
+// preferred backend+model first, then every other backend on its default
+// model. An empty backend picks the first one serving model (or the first
+// backend outright when model is empty too); an unknown backend or a model
+// the backend does not serve is InvalidInput.
+func (r *Router) Plan(backend, model string) ([]Target, error) {
+	if len(r.backends) == 0 {
+		return nil, liberrors.ServiceUnavailable("no generation backends configured")
+	}
+	first := -1
+	switch {
+	case backend != "":
+		first = slices.IndexFunc(r.backends, func(g Generator) bool { return g.Name() == backend })
+		if first < 0 {
+			return nil, liberrors.InvalidInput(fmt.Sprintf("unknown backend %q", backend))
+		}
+		if model != "" && !slices.Contains(r.backends[first].Models(), model) {
+			return nil, liberrors.InvalidInput(fmt.Sprintf("backend %q does not serve model %q", backend, model))
+		}
+	case model != "":
+		first = slices.IndexFunc(r.backends, func(g Generator) bool { return slices.Contains(g.Models(), model) })
+		if first < 0 {
+			return nil, liberrors.InvalidInput(fmt.Sprintf("unknown model %q", model))
+		}
+	default:
+		first = 0
+	}
+
+	plan := make([]Target, 0, len(r.backends))
+	pick := r.backends[first]
+	if model == "" {
+		model = defaultModel(pick)
+	}
+	plan = append(plan, Target{Backend: pick.Name(), Model: model})
+	for i, b := range r.backends {
+		if i != first {
+			plan = append(plan, Target{Backend: b.Name(), Model: defaultModel(b)})
+		}
+	}
+	return plan, nil
+}
+
+// Stream runs p on plan's targets in order until one succeeds, skipping any
+// over its token budget. Every attempt — failed and partial ones included —
+// is metered against its backend's budget (see attemptUsage), even when ctx
+// is cancelled mid-stream. A cancelled ctx (client gone, shutdown) stops the
+// walk instead of falling back. When every target fails it returns an
+// *ExhaustedError.
+func (r *Router) Stream(ctx context.Context, plan []Target, p Prompt, hooks Hooks) (Result, error) {
+	var failures []Failure
+	for _, t := range plan {
+		if len(failures) > 0 && hooks.Fallback != nil {
+			hooks.Fallback(failures[len(failures)-1], t)
+		}
+		gen := r.backend(t.Backend)
+		if gen == nil {
+			failures = append(failures, Failure{Target: t, Err: fmt.Errorf("backend %q is not configured", t.Backend)})
+			continue
+		}
+		if r.budget != nil && !r.budget.BackendAvailable(ctx, t.Backend) {
+			failures = append(failures, Failure{Target: t, Err: ErrBudgetExhausted})
+			continue
+		}
+
+		var streamed strings.Builder
+		text, usage, err := gen.Stream(ctx, t.Model, p, func(d string) {
+			streamed.WriteString(d)
+			if hooks.Delta != nil {
+				hooks.Delta(d)
+			}
+		})
+		spent := attemptUsage(p, usage, streamed.String())
+		if r.budget != nil && spent > 0 {
+			r.budget.AddTokens(context.WithoutCancel(ctx), t.Backend, spent)
+		}
+		if err == nil {
+			return Result{Target: t, Text: text, Usage: usage, Failures: failures}, nil
+		}
+		failures = append(failures, Failure{Target: t, Err: err, Partial: streamed.Len() > 0, Usage: spent})
+		if ctx.Err() != nil {
+			break
+		}
+		if r.log != nil {
+			r.log.Warnw("generation backend failed", "backend", t.Backend, "model", t.Model, "partial", streamed.Len() > 0, "tokens", spent, "error", err)
+		}
+	}
+	return Result{Failures: failures}, &ExhaustedError{Failures: failures}
+}
+
+// attemptUsage is the tokens one attempt is metered at: the backend's
+// reported usage, else an estimate of about four bytes a token over the
+// prompt and the output streamed (a stream cut before its usage chunk, or a
+// server that never reports it). An attempt that failed before any output —
+// refused or unreachable — was not served and costs nothing.
+func attemptUsage(p Prompt, usage int, streamed string) int {
+	if usage > 0 {
+		return usage
+	}
+	if streamed == "" {
+		return 0
+	}
+	return (len(p.System) + len(p.User) + len(streamed) + 3) / 4
+}
+
+func (r *Router) backend(name string) Generator {
+	for _, b := range r.backends {
+		if b.Name() == name {
+			return b
+		}
+	}
+	return nil
+}
+
+func defaultModel(g Generator) string {
+	if models := g.Models(); len(models) > 0 {
+		return models[0]
+	}
+	return ""
+}
@@ -0,0 +1,225 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	liberrors "github.com/ILITA-hub/animeenigma/libs/errors"
+)
+
+// fakeGen streams out (emitting partial first when set) or fails with err,
+// reporting usage for the failed attempt.
+type fakeGen struct {
+	name    string
+	models  []string
+	out     string
+	partial string
+	err     error
+	usage   int
+	calls   int
+	model   string
+}
+
+func (f *fakeGen) Name() string     { return f.name }
+func (f *fakeGen) Models() []string { return f.models }
+func (f *fakeGen) Stream(_ context.Context, model string, _ Prompt, onDelta func(string)) (string, int, error) {
+	f.calls++
+	f.model = model
+	if f.partial != "" {
+		onDelta(f.partial)
+	}
+	if f.err != nil {
+		return f.partial, f.usage, f.err
+	}
+	onDelta(f.out)
+	return f.out, 10, nil
+}
+
+// fakeBudget records metered tokens; exhausted backends report unavailable.
+// A sample metered on a cancelled ctx would be dropped by Redis, so those
+// are counted apart.
+type fakeBudget struct {
+	exhausted map[string]bool
+	added     map[string]int
+	dropped   int
+}
+
+func (b *fakeBudget) BackendAvailable(_ context.Context, backend string) bool {
+	return !b.exhausted[backend]
+}
+func (b *fakeBudget) AddTokens(ctx context.Context, backend string, tokens int) {
+	if ctx.Err() != nil {
+		b.dropped++
+		return
+	}
+	b.added[backend] += tokens
+}
+
+func TestPlan_PreferenceThenDefaults(t *testing.T) {
+	r := NewRouter([]Generator{
+		&fakeGen{name: "groq", models: []string{"llama-8b", "llama-70b"}},
+		&fakeGen{name: "lan", models: []string{"qwen"}},
+	}, nil, nil)
+
+	plan, err := r.Plan("", "")
+	if err != nil || len(plan) != 2 || plan[0] != (Target{"groq", "llama-8b"}) || plan[1] != (Target{"lan", "qwen"}) {
+		t.Fatalf("default plan = %v, %v", plan, err)
+	}
+	plan, _ = r.Plan("lan", "")
+	if plan[0] != (Target{"lan", "qwen"}) || plan[1] != (Target{"groq", "llama-8b"}) {
+		t.Errorf("lan-first plan = %v", plan)
+	}
+	plan, _ = r.Plan("", "llama-70b")
+	if plan[0] != (Target{"groq", "llama-70b"}) {
+		t.Errorf("model-only plan = %v; want the backend serving it", plan)
+	}
+
+	for _, tc := range []struct{ backend, model string }{{"openai", ""}, {"lan", "llama-8b"}, {"", "gpt-4"}} {
+		_, err := r.Plan(tc.backend, tc.model)
+		if appErr, ok := liberrors.IsAppError(err); !ok || appErr.Code != liberrors.CodeInvalidInput {
+			t.Errorf("Plan(%q, %q) = %v; want InvalidInput", tc.backend, tc.model, err)
+		}
+	}
+}
+
+func TestStream_FallsBackAfterPartialFailure(t *testing.T) {
+	groq := &fakeGen{name: "groq", models: []string{"llama"}, partial: "Once", err: errors.New("stream reset")}
+	lan := &fakeGen{name: "lan", models: []string{"qwen"}, out: "Story"}
+	budget := &fakeBudget{added: map[string]int{}}
+	r := NewRouter([]Generator{groq, lan}, budget, nil)
+
+	plan, _ := r.Plan("", "")
+	var deltas []string
+	var fallbacks []Failure
+	res, err := r.Stream(context.Background(), plan, Prompt{}, Hooks{
+		Delta:    func(d string) { deltas = append(deltas, d) },
+		Fallback: func(f Failure, next Target) { fallbacks = append(fallbacks, f) },
+	})
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	if res.Backend != "lan" || res.Model != "qwen" || res.Text != "Story" {
+		t.Errorf("result = %+v; want lan's story", res)
+	}
+	if len(fallbacks) != 1 || fallbacks[0].Backend != "groq" || !fallbacks[0].Partial {
+		t.Errorf("fallbacks = %+v; want one partial groq failure", fallbacks)
+	}
+	if len(deltas) != 2 {
+		t.Errorf("deltas = %v; want the partial then lan's text", deltas)
+	}
+	if budget.added["lan"] != 10 || budget.added["groq"] != 1 || fallbacks[0].Usage != 1 {
+		t.Errorf("metered = %v; want lan's usage and an estimate for groq's partial stream", budget.added)
+	}
+}
+
+func TestStream_SkipsExhaustedBudget(t *testing.T) {
+	groq := &fakeGen{name: "groq", models: []string{"llama"}, out: "g"}
+	lan := &fakeGen{name: "lan", models: []string{"qwen"}, out: "l"}
+	budget := &fakeBudget{exhausted: map[string]bool{"groq": true}, added: map[string]int{}}
+	r := NewRouter([]Generator{groq, lan}, budget, nil)
+
+	plan, _ := r.Plan("", "")
+	res, err := r.Stream(context.Background(), plan, Prompt{}, Hooks{})
+	if err != nil || res.Backend != "lan" {
+		t.Fatalf("res = %+v, %v; want lan", res, err)
+	}
+	if groq.calls != 0 {
+		t.Error("an over-budget backend must not be called")
+	}
+	if len(res.Failures) != 1 || !errors.Is(res.Failures[0].Err, ErrBudgetExhausted) {
+		t.Errorf("failures = %+v", res.Failures)
+	}
+}
+
+func TestStream_AllFailUnwrapsStatusError(t *testing.T) {
+	r := NewRouter([]Generator{
+		&fakeGen{name: "groq", models: []string{"llama"}, err: &StatusError{Backend: "groq", Code: http.StatusUnauthorized}},
+		&fakeGen{name: "lan", models: []string{"qwen"}, err: errors.New("connection refused")},
+	}, nil, nil)
+
+	plan, _ := r.Plan("", "")
+	_, err := r.Stream(context.Background(), plan, Prompt{}, Hooks{})
+	var exhausted *ExhaustedError
+	if !errors.As(err, &exhausted) || len(exhausted.Failures) != 2 {
+		t.Fatalf("err = %v; want ExhaustedError over both", err)
+	}
+	var se *StatusError
+	if !errors.As(err, &se) || se.Code != http.StatusUnauthorized {
+		t.Errorf("errors.As StatusError = %v", se)
+	}
+}
+
+func TestStream_CancelledContextStopsFallback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	lan := &fakeGen{name: "lan", models: []string{"qwen"}, out: "l"}
+	r := NewRouter([]Generator{
+		&fakeGen{name: "groq", models: []string{"llama"}, err: context.Canceled},
+		lan,
+	}, nil, nil)
+
+	plan, _ := r.Plan("", "")
+	if _, err := r.Stream(ctx, plan, Prompt{}, Hooks{}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if lan.calls != 0 {
+		t.Error("a cancelled request must not fall back")
+	}
+}
+
+func TestStream_MetersEveryAttempt(t *testing.T) {
+	p := Prompt{System: strings.Repeat("s", 40), User: strings.Repeat("u", 36)} // 19 tokens at 4 bytes each
+	refused := &fakeGen{name: "refused", models: []string{"m"}, err: &StatusError{Backend: "refused", Code: http.StatusTooManyRequests}}
+	cut := &fakeGen{name: "cut", models: []string{"m"}, partial: strings.Repeat("x", 20), err: errors.New("stream reset")}
+	reported := &fakeGen{name: "reported", models: []string{"m"}, partial: "x", usage: 250, err: errors.New("stream reset")}
+	budget := &fakeBudget{added: map[string]int{}}
+	r := NewRouter([]Generator{refused, cut, reported}, budget, nil)
+
+	plan, _ := r.Plan("", "")
+	res, err := r.Stream(context.Background(), plan, p, Hooks{})
+	var exhausted *ExhaustedError
+	if !errors.As(err, &exhausted) {
+		t.Fatalf("err = %v; want ExhaustedError", err)
+	}
+	want := map[string]int{"cut": 24, "reported": 250}
+	if !reflect.DeepEqual(budget.added, want) {
+		t.Errorf("metered = %v; want %v (nothing for the refused request)", budget.added, want)
+	}
+	for i, usage := range []int{0, 24, 250} {
+		if res.Failures[i].Usage != usage {
+			t.Errorf("failure %s usage = %d; want %d", res.Failures[i].Backend, res.Failures[i].Usage, usage)
+		}
+	}
+}
+
+// cancelGen cancels the request after streaming, as a client hanging up
+// mid-generation does.
+type cancelGen struct {
+	fakeGen
+	cancel context.CancelFunc
+}
+
+func (g *cancelGen) Stream(ctx context.Context, _ string, _ Prompt, onDelta func(string)) (string, int, error) {
+	onDelta("Once upon a time")
+	g.cancel()
+	return "Once upon a time", 0, ctx.Err()
+}
+
+func TestStream_MetersAttemptCutByCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	budget := &fakeBudget{added: map[string]int{}}
+	r := NewRouter([]Generator{&cancelGen{fakeGen: fakeGen{name: "groq", models: []string{"llama"}}, cancel: cancel}}, budget, nil)
+
+	plan, _ := r.Plan("", "")
+	if _, err := r.Stream(ctx, plan, Prompt{}, Hooks{}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if budget.dropped != 0 || budget.added["groq"] != 4 {
+		t.Errorf("metered = %v (%d dropped); want the cut stream's 4 tokens", budget.added, budget.dropped)
+	}
+}
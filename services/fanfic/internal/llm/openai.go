@@ -0,0 +1,140 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAIClient is a minimal streaming client for any OpenAI-compatible
+// chat/completions endpoint — Groq, or a self-hosted llama.cpp / vLLM server.
+type OpenAIClient struct {
+	name    string
+	apiKey  string
+	baseURL string
+	models  []string
+	http    *http.Client
+}
+
+// NewOpenAI builds a backend named name. apiKey may be empty for servers
+// that do not authenticate (a LAN llama.cpp); models lists what the endpoint
+// serves, default first.
+func NewOpenAI(name, apiKey, baseURL string, models []string, timeout time.Duration) *OpenAIClient {
+	return &OpenAIClient{
+		name:    name,
+		apiKey:  apiKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		models:  models,
+		http:    &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *OpenAIClient) Name() string     { return c.name }
+func (c *OpenAIClient) Models() []string { return c.models }
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model         string        `json:"model"`
+	Messages      []message     `json:"messages"`
+	MaxTokens     int           `json:"max_tokens"`
+	Temperature   float64       `json:"temperature"`
+	Stream        bool          `json:"stream"`
+	StreamOptions streamOptions `json:"stream_options"`
+}
+
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type sseChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// Stream calls chat/completions with stream:true, invoking onDelta for each token
+// chunk. It returns the full accumulated text and total token usage (0 if absent).
+func (c *OpenAIClient) Stream(ctx context.Context, model string, p Prompt, onDelta func(string)) (string, int, error) {
+	body, err := json.Marshal(chatRequest{
+		Model: model,
+		Messages: []message{
+			{Role: "system", Content: p.System},
+			{Role: "user", Content: p.User},
+		},
+		MaxTokens:     p.MaxTokens,
+		Temperature:   p.Temperature,
+		Stream:        true,
+		StreamOptions: streamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", 0, err
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", 0, &StatusError{Backend: c.name, Code: resp.StatusCode, Body: strings.TrimSpace(string(snippet))}
+	}
+
+	var sb strings.Builder
+	usage := 0
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		payload := strings.TrimPrefix(line, "data: ")
+		if payload == "[DONE]" {
+			break
+		}
+		var chunk sseChunk
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			continue // tolerate keep-alive / malformed lines
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage.TotalTokens
+		}
+		for _, ch := range chunk.Choices {
+			if ch.Delta.Content != "" {
+				sb.WriteString(ch.Delta.Content)
+				onDelta(ch.Delta.Content)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return sb.String(), usage, fmt.Errorf("reading %s stream: %w", c.name, err)
+	}
+	return sb.String(), usage, nil
+}
@@ -1,4 +1,4 @@
-package groq
+package llm
 
 import (
 	"context"
@@ -12,7 +12,7 @@ import (
 )
 
 func TestStream_AccumulatesDeltasAndUsage(t *testing.T) {
-	// Fake Groq SSE: two content deltas, then a usage-only chunk, then [DONE].
+	// Fake OpenAI-compatible SSE: two content deltas, then a usage-only chunk, then [DONE].
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
 			t.Errorf("missing auth header, got %q", got)
@@ -25,9 +25,9 @@ func TestStream_AccumulatesDeltasAndUsage(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	c := New("test-key", srv.URL, "llama-3.1-8b-instant", 5*time.Second)
+	c := NewOpenAI("groq", "test-key", srv.URL, []string{"llama-3.1-8b-instant"}, 5*time.Second)
 	var deltas []string
-	text, usage, err := c.Stream(context.Background(), "sys", "usr", 100, 0.9, func(d string) {
+	text, usage, err := c.Stream(context.Background(), "llama-3.1-8b-instant", Prompt{System: "sys", User: "usr", MaxTokens: 100, Temperature: 0.9}, func(d string) {
 		deltas = append(deltas, d)
 	})
 	if err != nil {
@@ -49,8 +49,8 @@ func TestStream_UpstreamError(t *testing.T) {
 		http.Error(w, "rate limited", http.StatusTooManyRequests)
 	}))
 	defer srv.Close()
-	c := New("k", srv.URL, "m", time.Second)
-	if _, _, err := c.Stream(context.Background(), "s", "u", 10, 0.5, func(string) {}); err == nil {
+	c := NewOpenAI("groq", "k", srv.URL, []string{"m"}, time.Second)
+	if _, _, err := c.Stream(context.Background(), "m", Prompt{System: "s", User: "u", MaxTokens: 10}, func(string) {}); err == nil {
 		t.Fatal("expected error on 429, got nil")
 	}
 }
@@ -61,10 +61,25 @@ func TestStream_401_ReturnsStatusError(t *testing.T) {
 		_, _ = w.Write([]byte(`{"error":{"code":"invalid_api_key"}}`))
 	}))
 	defer srv.Close()
-	c := New("bad", srv.URL, "m", 5*time.Second)
-	_, _, err := c.Stream(context.Background(), "s", "u", 10, 0.9, func(string) {})
+	c := NewOpenAI("groq", "bad", srv.URL, []string{"m"}, 5*time.Second)
+	_, _, err := c.Stream(context.Background(), "m", Prompt{System: "s", User: "u", MaxTokens: 10}, func(string) {})
 	var se *StatusError
-	if !errors.As(err, &se) || se.Code != http.StatusUnauthorized {
+	if !errors.As(err, &se) || se.Code != http.StatusUnauthorized || se.Backend != "groq" {
 		t.Fatalf("want *StatusError{401}, got %v", err)
 	}
 }
+
+func TestStream_NoAPIKey_OmitsAuthorization(t *testing.T) {
+	// A LAN llama.cpp server runs without auth; no key means no header.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("Authorization = %q, want none", got)
+		}
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\ndata: [DONE]\n\n")
+	}))
+	defer srv.Close()
+	c := NewOpenAI("lan", "", srv.URL, []string{"qwen2.5-7b"}, time.Second)
+	if text, _, err := c.Stream(context.Background(), "qwen2.5-7b", Prompt{User: "u"}, func(string) {}); err != nil || text != "hi" {
+		t.Fatalf("Stream = %q, %v", text, err)
+	}
+}
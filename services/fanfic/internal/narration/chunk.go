@@ -0,0 +1,112 @@
+// Package narration turns fanfic text into an audiobook: it chunks text at
+// sentence boundaries for the VOICEVOX engine, joins the engine's WAV
+// chunks, and encodes the result to Opus/MP3 with chapter markers via
+// ffmpeg. It does no I/O beyond local files; the service drives the engine
+// and object storage.
+package narration
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// sentenceEnds close a sentence; closers (brackets, quotes) that directly
+// follow one stay with it.
+const (
+	sentenceEnds = "。！？!?…‥"
+	closers      = "」』）)】〕〉》\"'”’"
+	// softBreaks are where an over-long sentence is cut when it must be.
+	softBreaks = "、，,；;：:　 "
+)
+
+// Split packs paragraphs into chunks of at most max runes each. Sentences
+// are never split unless one alone exceeds max; a chunk may span paragraphs,
+// since every paragraph ends a sentence.
+func Split(paragraphs []string, max int) []string {
+	var out []string
+	var cur strings.Builder
+	curRunes := 0
+	flush := func() {
+		if s := strings.TrimSpace(cur.String()); s != "" {
+			out = append(out, s)
+		}
+		cur.Reset()
+		curRunes = 0
+	}
+	for _, p := range paragraphs {
+		for _, s := range sentences(p) {
+			for _, piece := range cut(s, max) {
+				n := utf8.RuneCountInString(piece)
+				if curRunes+n > max {
+					flush()
+				}
+				cur.WriteString(piece)
+				curRunes += n
+			}
+		}
+	}
+	flush()
+	return out
+}
+
+// sentences splits a paragraph after each sentence end (and its closers) and
+// at line breaks. A line that does not end a sentence (a heading, a bare
+// line of dialogue) gets a 。 so the engine pauses after it instead of
+// running it into the next one.
+func sentences(p string) []string {
+	var out []string
+	var cur strings.Builder
+	emit := func(terminate bool) {
+		s := strings.TrimSpace(cur.String())
+		cur.Reset()
+		if s == "" {
+			return
+		}
+		if last, _ := utf8.DecodeLastRuneInString(s); terminate && !strings.ContainsRune(sentenceEnds+closers, last) {
+			s += "。"
+		}
+		out = append(out, s)
+	}
+	runes := []rune(p)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\n' {
+			emit(true)
+			continue
+		}
+		cur.WriteRune(r)
+		if !strings.ContainsRune(sentenceEnds, r) {
+			continue
+		}
+		for i+1 < len(runes) && strings.ContainsRune(sentenceEnds+closers, runes[i+1]) {
+			i++
+			cur.WriteRune(runes[i])
+		}
+		emit(false)
+	}
+	emit(true)
+	return out
+}
+
+// cut breaks a sentence longer than max runes, preferring the last soft
+// break (comma, space) in each window and falling back to a hard cut.
+func cut(s string, max int) []string {
+	runes := []rune(s)
+	var out []string
+	for len(runes) > max {
+		at := max
+		for i := max - 1; i > max/2; i-- {
+			if strings.ContainsRune(softBreaks, runes[i]) || unicode.IsSpace(runes[i]) {
+				at = i + 1
+				break
+			}
+		}
+		out = append(out, string(runes[:at]))
+		runes = runes[at:]
+	}
+	if len(runes) > 0 {
+		out = append(out, string(runes))
+	}
+	return out
+}
@@ -0,0 +1,103 @@
+package narration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Output formats; each is also the encoded file's extension.
+const (
+	FormatOpus = "opus"
+	FormatMP3  = "mp3"
+)
+
+// Chapter is one chapter marker of the encoded file.
+type Chapter struct {
+	Title string
+	Start time.Duration
+	End   time.Duration
+}
+
+// Meta is the encoded file's tags.
+type Meta struct {
+	Title    string
+	Artist   string
+	Album    string
+	Language string
+}
+
+// FFmpeg encodes joined WAVs with the ffmpeg binary at Path.
+type FFmpeg struct {
+	Path string
+}
+
+// Encode encodes the WAV at src into format at dst, tagging it with meta and
+// chapters. Opus stores chapters as Vorbis comments, MP3 as ID3v2 CHAP
+// frames — both what podcast and audiobook players read.
+func (f FFmpeg) Encode(ctx context.Context, src, dst, format string, meta Meta, chapters []Chapter) error {
+	var codec []string
+	switch format {
+	case FormatOpus:
+		codec = []string{"-c:a", "libopus", "-b:a", "48k", "-application", "voip"}
+	case FormatMP3:
+		codec = []string{"-c:a", "libmp3lame", "-b:a", "96k", "-id3v2_version", "3"}
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+	metaPath := filepath.Join(filepath.Dir(dst), "ffmetadata.txt")
+	if err := os.WriteFile(metaPath, []byte(FFMetadata(meta, chapters)), 0o644); err != nil {
+		return err
+	}
+	defer os.Remove(metaPath)
+
+	path := f.Path
+	if path == "" {
+		path = "ffmpeg"
+	}
+	args := []string{"-hide_banner", "-loglevel", "error", "-nostdin", "-y",
+		"-i", src, "-f", "ffmetadata", "-i", metaPath,
+		"-map", "0:a", "-map_metadata", "1", "-map_chapters", "1"}
+	args = append(append(args, codec...), dst)
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// FFMetadata renders meta and chapters in ffmpeg's FFMETADATA1 format.
+func FFMetadata(meta Meta, chapters []Chapter) string {
+	var sb strings.Builder
+	sb.WriteString(";FFMETADATA1\n")
+	for _, kv := range [][2]string{{"title", meta.Title}, {"artist", meta.Artist}, {"album", meta.Album}, {"language", meta.Language}} {
+		if kv[1] != "" {
+			fmt.Fprintf(&sb, "%s=%s\n", kv[0], escapeMetadata(kv[1]))
+		}
+	}
+	for _, c := range chapters {
+		fmt.Fprintf(&sb, "\n[CHAPTER]\nTIMEBASE=1/1000\nSTART=%d\nEND=%d\ntitle=%s\n",
+			c.Start.Milliseconds(), c.End.Milliseconds(), escapeMetadata(c.Title))
+	}
+	return sb.String()
+}
+
+// escapeMetadata backslash-escapes the characters FFMETADATA1 reserves.
+func escapeMetadata(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch r {
+		case '=', ';', '#', '\\', '\n':
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
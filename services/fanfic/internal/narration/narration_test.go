@@ -0,0 +1,130 @@
+package narration
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestSplit_SentenceBoundaries(t *testing.T) {
+	got := Split([]string{"第一章", "彼女は言った。「行こう！」\n彼は頷いた", "おわり。"}, 13)
+	want := []string{"第一章。彼女は言った。", "「行こう！」彼は頷いた。", "おわり。"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("chunks = %q, want %q", got, want)
+	}
+}
+
+func TestSplit_PacksSentencesUpToMax(t *testing.T) {
+	got := Split([]string{"あいう。えお。かきくけこ。"}, 8)
+	want := []string{"あいう。えお。", "かきくけこ。"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("chunks = %q, want %q", got, want)
+	}
+}
+
+func TestSplit_CutsLongSentenceAtComma(t *testing.T) {
+	long := strings.Repeat("あ", 300) + "、" + strings.Repeat("い", 300) + "。"
+	got := Split([]string{long}, 500)
+	if len(got) != 2 || !strings.HasSuffix(got[0], "、") {
+		t.Fatalf("chunks = %d, first ends %q", len(got), got[0][len(got[0])-3:])
+	}
+	for _, c := range got {
+		if utf8.RuneCountInString(c) > 500 {
+			t.Fatalf("chunk of %d runes", utf8.RuneCountInString(c))
+		}
+	}
+
+	hard := Split([]string{strings.Repeat("う", 1200)}, 500)
+	if len(hard) != 3 || utf8.RuneCountInString(hard[0]) != 500 {
+		t.Fatalf("hard cut = %d chunks", len(hard))
+	}
+}
+
+// pcmWAV builds a mono 16-bit WAV of n samples at rate.
+func pcmWAV(rate uint32, n int) []byte {
+	f := Format{Channels: 1, SampleRate: rate, BitsPerSample: 16}
+	return append(wavHeader(f, uint32(n*2)), make([]byte, n*2)...)
+}
+
+func TestParseWAV(t *testing.T) {
+	f, pcm, err := ParseWAV(pcmWAV(24000, 100))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if f.SampleRate != 24000 || f.Channels != 1 || f.BitsPerSample != 16 || len(pcm) != 200 {
+		t.Fatalf("format %+v, %d bytes", f, len(pcm))
+	}
+	if _, _, err := ParseWAV([]byte("not a wav")); err == nil {
+		t.Fatal("expected an error for non-WAV data")
+	}
+}
+
+func TestConcatWAV(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.wav"), filepath.Join(dir, "b.wav")
+	_ = os.WriteFile(a, pcmWAV(24000, 24000), 0o644)
+	_ = os.WriteFile(b, pcmWAV(24000, 12000), 0o644)
+
+	dst := filepath.Join(dir, "out.wav")
+	durations, err := ConcatWAV(dst, []string{a, b})
+	if err != nil {
+		t.Fatalf("concat: %v", err)
+	}
+	if durations[0] != time.Second || durations[1] != 500*time.Millisecond {
+		t.Fatalf("durations = %v", durations)
+	}
+	data, _ := os.ReadFile(dst)
+	f, pcm, err := ParseWAV(data)
+	if err != nil || f.SampleRate != 24000 || len(pcm) != 72000 {
+		t.Fatalf("joined: %+v %d bytes %v", f, len(pcm), err)
+	}
+	if size := binary.LittleEndian.Uint32(data[4:8]); size != uint32(len(data)-8) {
+		t.Fatalf("RIFF size %d, file %d", size, len(data))
+	}
+
+	_ = os.WriteFile(b, pcmWAV(48000, 10), 0o644)
+	if _, err := ConcatWAV(dst, []string{a, b}); err == nil {
+		t.Fatal("expected an error for mismatched formats")
+	}
+}
+
+func TestFFMetadata(t *testing.T) {
+	got := FFMetadata(Meta{Title: "夜=朝", Artist: "ずんだもん"}, []Chapter{
+		{Title: "第1章", Start: 0, End: 1500 * time.Millisecond},
+		{Title: "第2章; 終", Start: 1500 * time.Millisecond, End: 3 * time.Second},
+	})
+	for _, want := range []string{
+		";FFMETADATA1\n", "title=夜\\=朝\n", "artist=ずんだもん\n",
+		"[CHAPTER]\nTIMEBASE=1/1000\nSTART=0\nEND=1500\ntitle=第1章\n",
+		"START=1500\nEND=3000\ntitle=第2章\\; 終\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("metadata missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestFFmpegEncode(t *testing.T) {
+	path, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		t.Skip("ffmpeg not installed")
+	}
+	dir := t.TempDir()
+	src := filepath.Join(dir, "in.wav")
+	_ = os.WriteFile(src, pcmWAV(24000, 48000), 0o644)
+	dst := filepath.Join(dir, "out.mp3")
+	err = FFmpeg{Path: path}.Encode(context.Background(), src, dst, FormatMP3, Meta{Title: "t"},
+		[]Chapter{{Title: "a", End: time.Second}, {Title: "b", Start: time.Second, End: 2 * time.Second}})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if info, err := os.Stat(dst); err != nil || info.Size() == 0 {
+		t.Fatalf("no output: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package narration
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Format is the PCM layout of a WAV file.
+type Format struct {
+	Channels      uint16
+	SampleRate    uint32
+	BitsPerSample uint16
+}
+
+// bytesPerSecond is the PCM data rate of f.
+func (f Format) bytesPerSecond() int64 {
+	return int64(f.SampleRate) * int64(f.Channels) * int64(f.BitsPerSample/8)
+}
+
+// ParseWAV returns the format and PCM data of a RIFF/WAVE file. Only
+// uncompressed PCM is accepted — what the VOICEVOX engine produces.
+func ParseWAV(data []byte) (Format, []byte, error) {
+	var f Format
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return f, nil, errors.New("not a RIFF/WAVE file")
+	}
+	haveFmt := false
+	for off := 12; off+8 <= len(data); {
+		id := string(data[off : off+4])
+		size := int(binary.LittleEndian.Uint32(data[off+4 : off+8]))
+		body := off + 8
+		if size < 0 || body+size > len(data) {
+			// Streams written without a final size end at the file's end.
+			size = len(data) - body
+		}
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return f, nil, errors.New("short fmt chunk")
+			}
+			if tag := binary.LittleEndian.Uint16(data[body : body+2]); tag != 1 {
+				return f, nil, fmt.Errorf("unsupported WAV encoding %d (PCM only)", tag)
+			}
+			f.Channels = binary.LittleEndian.Uint16(data[body+2 : body+4])
+			f.SampleRate = binary.LittleEndian.Uint32(data[body+4 : body+8])
+			f.BitsPerSample = binary.LittleEndian.Uint16(data[body+14 : body+16])
+			if f.Channels == 0 || f.SampleRate == 0 || f.BitsPerSample == 0 || f.BitsPerSample%8 != 0 {
+				return f, nil, errors.New("invalid fmt chunk")
+			}
+			haveFmt = true
+		case "data":
+			if !haveFmt {
+				return f, nil, errors.New("data chunk before fmt chunk")
+			}
+			return f, data[body : body+size], nil
+		}
+		off = body + size + size%2 // chunks are word-aligned
+	}
+	return f, nil, errors.New("no data chunk")
+}
+
+// ConcatWAV joins the WAV files at srcs, in order, into one WAV at dst and
+// returns each source's duration. Every source must share the first one's
+// format.
+func ConcatWAV(dst string, srcs []string) ([]time.Duration, error) {
+	if len(srcs) == 0 {
+		return nil, errors.New("nothing to join")
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return nil, err
+	}
+	defer out.Close()
+	// Header placeholder; the sizes are patched once all data is written.
+	if _, err := out.Write(make([]byte, 44)); err != nil {
+		return nil, err
+	}
+
+	var format Format
+	var total int64
+	durations := make([]time.Duration, 0, len(srcs))
+	for i, src := range srcs {
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return nil, err
+		}
+		f, pcm, err := ParseWAV(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", src, err)
+		}
+		if i == 0 {
+			format = f
+		} else if f != format {
+			return nil, fmt.Errorf("%s: format %+v differs from %+v", src, f, format)
+		}
+		if _, err := out.Write(pcm); err != nil {
+			return nil, err
+		}
+		total += int64(len(pcm))
+		durations = append(durations, time.Duration(int64(len(pcm))*int64(time.Second)/format.bytesPerSecond()))
+	}
+	if total > 0xFFFFFFFF-36 {
+		return nil, errors.New("joined audio exceeds the WAV size limit")
+	}
+
+	if _, err := out.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if _, err := out.Write(wavHeader(format, uint32(total))); err != nil {
+		return nil, err
+	}
+	return durations, out.Close()
+}
+
+// wavHeader is the canonical 44-byte PCM header for dataSize bytes of data.
+func wavHeader(f Format, dataSize uint32) []byte {
+	h := make([]byte, 44)
+	blockAlign := f.Channels * (f.BitsPerSample / 8)
+	copy(h[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(h[4:8], 36+dataSize)
+	copy(h[8:12], "WAVE")
+	copy(h[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(h[16:20], 16)
+	binary.LittleEndian.PutUint16(h[20:22], 1)
+	binary.LittleEndian.PutUint16(h[22:24], f.Channels)
+	binary.LittleEndian.PutUint32(h[24:28], f.SampleRate)
+	binary.LittleEndian.PutUint32(h[28:32], f.SampleRate*uint32(blockAlign))
+	binary.LittleEndian.PutUint16(h[32:34], blockAlign)
+	binary.LittleEndian.PutUint16(h[34:36], f.BitsPerSample)
+	copy(h[36:40], "data")
+	binary.LittleEndian.PutUint32(h[40:44], dataSize)
+	return h
+}
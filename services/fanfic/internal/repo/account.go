@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/ILITA-hub/animeenigma/libs/database"
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"gorm.io/gorm"
+)
+
+// accountUserTables lists the fanfic tables holding a user's rows — fanfics (soft-deleted rows included)
+// the reader-side kudos, bookmarks and subscriptions, and requested exports and narrations — in erase order.
+// Chapters hang off fanfics rather than users and are handled separately.
+var accountUserTables = []database.UserTable{
+	{Table: "fanfic_exports", Column: "user_id"},
+	{Table: "fanfic_narrations", Column: "user_id"},
+	{Table: "fanfic_kudos", Column: "user_id"},
+	{Table: "fanfic_bookmarks", Column: "user_id"},
+	{Table: "fanfic_subscriptions", Column: "user_id"},
+	{Table: "fanfics", Column: "user_id"},
+}
+
+// userChapters selects the chapters of every work of the user.
+const userChapters = "fanfic_id IN (SELECT id FROM fanfics WHERE user_id = ?)"
+
+// AccountRepository backs the auth-driven account export + erasure fan-out
+// (/internal/account/*).
+type AccountRepository struct{ db *gorm.DB }
+
+func NewAccountRepository(db *gorm.DB) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+// Export returns every fanfic row of the user, keyed by table.
+func (r *AccountRepository) Export(ctx context.Context, userID string) (map[string][]map[string]any, error) {
+	rows, err := database.ExportUserRows(ctx, r.db, userID, accountUserTables)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to export account data")
+	}
+	var chapters []map[string]any
+	if err := r.db.WithContext(ctx).Table("fanfic_chapters").Where(userChapters, userID).Find(&chapters).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to export account data")
+	}
+	if chapters == nil {
+		chapters = []map[string]any{}
+	}
+	rows["fanfic_chapters"] = chapters
+	return rows, nil
+}
+
+// Erase hard-deletes every fanfic row of the user, chapters first.
+func (r *AccountRepository) Erase(ctx context.Context, userID string) (map[string]int64, error) {
+	res := r.db.WithContext(ctx).Exec("DELETE FROM fanfic_chapters WHERE "+userChapters, userID)
+	if res.Error != nil {
+		return nil, apperrors.Wrap(res.Error, apperrors.CodeInternal, "failed to erase account data")
+	}
+	deleted, err := database.EraseUserRows(ctx, r.db, userID, accountUserTables)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to erase account data")
+	}
+	deleted["fanfic_chapters"] = res.RowsAffected
+	return deleted, nil
+}
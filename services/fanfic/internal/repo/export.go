@@ -0,0 +1,121 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	liberrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExportRepository stores EPUB/PDF export rows — the render queue and the
+// cache index over object storage.
+type ExportRepository struct {
+	db *gorm.DB
+}
+
+func NewExportRepository(db *gorm.DB) *ExportRepository { return &ExportRepository{db: db} }
+
+// CreateOrGet inserts e unless a row with its fingerprint exists already, and
+// returns whichever row holds the fingerprint. Concurrent requests for the
+// same content therefore share one row.
+func (r *ExportRepository) CreateOrGet(ctx context.Context, e *domain.Export) (*domain.Export, error) {
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "fingerprint"}},
+		DoNothing: true,
+	}).Create(e).Error; err != nil {
+		return nil, liberrors.Wrap(err, liberrors.CodeInternal, "create export")
+	}
+	var got domain.Export
+	if err := r.db.WithContext(ctx).Where("fingerprint = ?", e.Fingerprint).First(&got).Error; err != nil {
+		return nil, liberrors.Wrap(err, liberrors.CodeInternal, "get export")
+	}
+	return &got, nil
+}
+
+// Get fetches an export by id; a missing row returns NotFound.
+func (r *ExportRepository) Get(ctx context.Context, id string) (*domain.Export, error) {
+	var e domain.Export
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&e).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, liberrors.NotFound("export")
+		}
+		return nil, liberrors.Wrap(err, liberrors.CodeInternal, "get export")
+	}
+	return &e, nil
+}
+
+// Requeue puts a failed export back in the queue for userID.
+func (r *ExportRepository) Requeue(ctx context.Context, id, userID string) error {
+	if err := r.db.WithContext(ctx).Model(&domain.Export{}).
+		Where("id = ? AND status = ?", id, domain.ExportFailed).
+		Updates(map[string]interface{}{"status": domain.ExportPending, "error_msg": "", "user_id": userID}).Error; err != nil {
+		return liberrors.Wrap(err, liberrors.CodeInternal, "requeue export")
+	}
+	return nil
+}
+
+// ClaimNext marks the oldest pending export running and returns it, or nil
+// when the queue is empty. The status-guarded update makes the claim safe
+// across replicas: a row another worker took first is skipped.
+func (r *ExportRepository) ClaimNext(ctx context.Context) (*domain.Export, error) {
+	for {
+		var e domain.Export
+		err := r.db.WithContext(ctx).Where("status = ?", domain.ExportPending).Order("created_at").First(&e).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, liberrors.Wrap(err, liberrors.CodeInternal, "claim export")
+		}
+		res := r.db.WithContext(ctx).Model(&domain.Export{}).
+			Where("id = ? AND status = ?", e.ID, domain.ExportPending).
+			Update("status", domain.ExportRunning)
+		if res.Error != nil {
+			return nil, liberrors.Wrap(res.Error, liberrors.CodeInternal, "claim export")
+		}
+		if res.RowsAffected == 1 {
+			e.Status = domain.ExportRunning
+			return &e, nil
+		}
+	}
+}
+
+// Complete records the stored file of a rendered export.
+func (r *ExportRepository) Complete(ctx context.Context, id, storage, key string, size int64, at time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&domain.Export{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       domain.ExportReady,
+		"storage":      storage,
+		"object_key":   key,
+		"size":         size,
+		"error_msg":    "",
+		"completed_at": at,
+	}).Error; err != nil {
+		return liberrors.Wrap(err, liberrors.CodeInternal, "complete export")
+	}
+	return nil
+}
+
+// Fail marks an export failed with msg.
+func (r *ExportRepository) Fail(ctx context.Context, id, msg string) error {
+	if err := r.db.WithContext(ctx).Model(&domain.Export{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status": domain.ExportFailed, "error_msg": msg}).Error; err != nil {
+		return liberrors.Wrap(err, liberrors.CodeInternal, "fail export")
+	}
+	return nil
+}
+
+// ResetStale requeues exports left running since before cutoff — a worker
+// that died mid-render.
+func (r *ExportRepository) ResetStale(ctx context.Context, cutoff time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Model(&domain.Export{}).
+		Where("status = ? AND updated_at < ?", domain.ExportRunning, cutoff).
+		Update("status", domain.ExportPending)
+	if res.Error != nil {
+		return 0, liberrors.Wrap(res.Error, liberrors.CodeInternal, "reset stale exports")
+	}
+	return res.RowsAffected, nil
+}
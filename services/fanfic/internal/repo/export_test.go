@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+)
+
+func newTestExportRepo(t *testing.T) *ExportRepository {
+	t.Helper()
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&domain.Export{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+	return NewExportRepository(db)
+}
+
+func TestExportRepository_CreateOrGetSharesFingerprint(t *testing.T) {
+	r := newTestExportRepo(t)
+	ctx := context.Background()
+
+	first, err := r.CreateOrGet(ctx, &domain.Export{UserID: "u1", Kind: domain.ExportKindFanfic, Format: domain.ExportEPUB, Fingerprint: "fp", Status: domain.ExportPending})
+	if err != nil {
+		t.Fatalf("CreateOrGet: %v", err)
+	}
+	second, err := r.CreateOrGet(ctx, &domain.Export{UserID: "u2", Kind: domain.ExportKindFanfic, Format: domain.ExportEPUB, Fingerprint: "fp", Status: domain.ExportPending})
+	if err != nil {
+		t.Fatalf("CreateOrGet again: %v", err)
+	}
+	if second.ID != first.ID || second.UserID != "u1" {
+		t.Errorf("second = %+v; want the first row back", second)
+	}
+}
+
+func TestExportRepository_ClaimCompleteFailRequeue(t *testing.T) {
+	r := newTestExportRepo(t)
+	ctx := context.Background()
+
+	if e, err := r.ClaimNext(ctx); err != nil || e != nil {
+		t.Fatalf("ClaimNext on empty queue = %v, %v", e, err)
+	}
+	a, _ := r.CreateOrGet(ctx, &domain.Export{UserID: "u1", Fingerprint: "a", Status: domain.ExportPending, CreatedAt: time.Now().Add(-time.Minute)})
+	b, _ := r.CreateOrGet(ctx, &domain.Export{UserID: "u1", Fingerprint: "b", Status: domain.ExportPending})
+
+	claimed, err := r.ClaimNext(ctx)
+	if err != nil || claimed == nil || claimed.ID != a.ID || claimed.Status != domain.ExportRunning {
+		t.Fatalf("ClaimNext = %+v, %v; want the oldest pending row", claimed, err)
+	}
+	if err := r.Complete(ctx, a.ID, "minio", "fanfic-exports/a/book.epub", 42, time.Now()); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	got, _ := r.Get(ctx, a.ID)
+	if got.Status != domain.ExportReady || got.Storage != "minio" || got.Size != 42 || got.CompletedAt == nil {
+		t.Errorf("completed row = %+v", got)
+	}
+
+	claimed, _ = r.ClaimNext(ctx)
+	if claimed == nil || claimed.ID != b.ID {
+		t.Fatalf("second claim = %+v", claimed)
+	}
+	if err := r.Fail(ctx, b.ID, "boom"); err != nil {
+		t.Fatalf("Fail: %v", err)
+	}
+	if err := r.Requeue(ctx, b.ID, "u2"); err != nil {
+		t.Fatalf("Requeue: %v", err)
+	}
+	got, _ = r.Get(ctx, b.ID)
+	if got.Status != domain.ExportPending || got.ErrorMsg != "" || got.UserID != "u2" {
+		t.Errorf("requeued row = %+v", got)
+	}
+}
+
+func TestExportRepository_ResetStale(t *testing.T) {
+	r := newTestExportRepo(t)
+	ctx := context.Background()
+
+	e, _ := r.CreateOrGet(ctx, &domain.Export{UserID: "u1", Fingerprint: "a", Status: domain.ExportPending})
+	if _, err := r.ClaimNext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := r.ResetStale(ctx, time.Now().Add(-time.Hour)); n != 0 {
+		t.Errorf("reset %d fresh rows; want 0", n)
+	}
+	if n, _ := r.ResetStale(ctx, time.Now().Add(time.Hour)); n != 1 {
+		t.Errorf("reset %d stale rows; want 1", n)
+	}
+	if got, _ := r.Get(ctx, e.ID); got.Status != domain.ExportPending {
+		t.Errorf("status = %q; want pending", got.Status)
+	}
+}
@@ -35,14 +35,17 @@ func (r *Repository) Create(ctx context.Context, f *domain.Fanfic) error {
 	return nil
 }
 
-// UpdateResult stores the generated title/content/usage and flips the row to
-// StatusComplete.
-func (r *Repository) UpdateResult(ctx context.Context, id, title, content string, usage int) error {
+// UpdateResult stores the generated title/content/usage and the backend/model
+// that produced it (a fallback may differ from the planned one), and flips
+// the row to StatusComplete.
+func (r *Repository) UpdateResult(ctx context.Context, id, title, content, backend, model string, usage int) error {
 	if err := r.db.WithContext(ctx).Model(&domain.Fanfic{}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
 			"title":       title,
 			"content":     content,
+			"backend":     backend,
+			"model":       model,
 			"token_usage": usage,
 			"status":      domain.StatusComplete,
 		}).Error; err != nil {
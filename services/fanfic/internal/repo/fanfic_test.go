@@ -116,7 +116,7 @@ func TestUpdateResultAndList(t *testing.T) {
 	user := "22222222-2222-2222-2222-222222222222"
 	f := &domain.Fanfic{UserID: user, AnimeTitle: "A", Status: domain.StatusGenerating}
 	_ = r.Create(ctx, f)
-	if err := r.UpdateResult(ctx, f.ID, "My Title", "the story", 123); err != nil {
+	if err := r.UpdateResult(ctx, f.ID, "My Title", "the story", "lan", "qwen2.5-7b", 123); err != nil {
 		t.Fatalf("UpdateResult: %v", err)
 	}
 	items, total, err := r.List(ctx, user, 10, 0)
@@ -132,6 +132,9 @@ func TestUpdateResultAndList(t *testing.T) {
 	if items[0].TokenUsage != 123 {
 		t.Errorf("TokenUsage = %d; want 123", items[0].TokenUsage)
 	}
+	if items[0].Backend != "lan" || items[0].Model != "qwen2.5-7b" {
+		t.Errorf("backend/model = %q/%q; want the producing lan/qwen2.5-7b", items[0].Backend, items[0].Model)
+	}
 }
 
 func TestMarkFailed(t *testing.T) {
@@ -0,0 +1,150 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	liberrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NarrationRepository stores narration jobs — the synthesis queue, each
+// job's chunk progress, and the cache index over the encoded files.
+type NarrationRepository struct {
+	db *gorm.DB
+}
+
+func NewNarrationRepository(db *gorm.DB) *NarrationRepository { return &NarrationRepository{db: db} }
+
+// CreateOrGet inserts n unless a row with its fingerprint exists already, and
+// returns whichever row holds the fingerprint (see ExportRepository.CreateOrGet).
+func (r *NarrationRepository) CreateOrGet(ctx context.Context, n *domain.Narration) (*domain.Narration, error) {
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "fingerprint"}},
+		DoNothing: true,
+	}).Create(n).Error; err != nil {
+		return nil, liberrors.Wrap(err, liberrors.CodeInternal, "create narration")
+	}
+	var got domain.Narration
+	if err := r.db.WithContext(ctx).Where("fingerprint = ?", n.Fingerprint).First(&got).Error; err != nil {
+		return nil, liberrors.Wrap(err, liberrors.CodeInternal, "get narration")
+	}
+	return &got, nil
+}
+
+// Get fetches a narration by id; a missing row returns NotFound.
+func (r *NarrationRepository) Get(ctx context.Context, id string) (*domain.Narration, error) {
+	var n domain.Narration
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&n).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, liberrors.NotFound("narration")
+		}
+		return nil, liberrors.Wrap(err, liberrors.CodeInternal, "get narration")
+	}
+	return &n, nil
+}
+
+// Requeue puts a failed or paused narration back in the queue for userID,
+// claimable at once. Its chunk progress is kept, so synthesis resumes at the
+// chunk it stopped on.
+func (r *NarrationRepository) Requeue(ctx context.Context, id, userID string) error {
+	if err := r.db.WithContext(ctx).Model(&domain.Narration{}).
+		Where("id = ? AND status IN ?", id, []string{domain.NarrationFailed, domain.NarrationPaused}).
+		Updates(map[string]interface{}{"status": domain.NarrationPending, "error_msg": "", "attempts": 0, "user_id": userID}).Error; err != nil {
+		return liberrors.Wrap(err, liberrors.CodeInternal, "requeue narration")
+	}
+	return nil
+}
+
+// ClaimNext marks the oldest claimable narration running and returns it, or
+// nil when there is none. Pending jobs are claimable at once, paused ones
+// only once they have waited since before retryBefore. The status-guarded
+// update makes the claim safe across replicas.
+func (r *NarrationRepository) ClaimNext(ctx context.Context, retryBefore time.Time) (*domain.Narration, error) {
+	for {
+		var n domain.Narration
+		err := r.db.WithContext(ctx).
+			Where("status = ? OR (status = ? AND updated_at < ?)", domain.NarrationPending, domain.NarrationPaused, retryBefore).
+			Order("created_at").First(&n).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, liberrors.Wrap(err, liberrors.CodeInternal, "claim narration")
+		}
+		res := r.db.WithContext(ctx).Model(&domain.Narration{}).
+			Where("id = ? AND status = ?", n.ID, n.Status).
+			Update("status", domain.NarrationRunning)
+		if res.Error != nil {
+			return nil, liberrors.Wrap(res.Error, liberrors.CodeInternal, "claim narration")
+		}
+		if res.RowsAffected == 1 {
+			n.Status = domain.NarrationRunning
+			return &n, nil
+		}
+	}
+}
+
+// Advance records that the first done chunks are synthesized and stored on
+// chunkStorage, clearing the failure count.
+func (r *NarrationRepository) Advance(ctx context.Context, id string, done int, chunkStorage string) error {
+	if err := r.db.WithContext(ctx).Model(&domain.Narration{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"chunks_done":   done,
+		"chunk_storage": chunkStorage,
+		"attempts":      0,
+		"error_msg":     "",
+	}).Error; err != nil {
+		return liberrors.Wrap(err, liberrors.CodeInternal, "advance narration")
+	}
+	return nil
+}
+
+// Pause parks a running narration until it is claimed again, recording the
+// failure count and reason (empty when paused for platform load).
+func (r *NarrationRepository) Pause(ctx context.Context, id string, attempts int, msg string) error {
+	if err := r.db.WithContext(ctx).Model(&domain.Narration{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status": domain.NarrationPaused, "attempts": attempts, "error_msg": msg}).Error; err != nil {
+		return liberrors.Wrap(err, liberrors.CodeInternal, "pause narration")
+	}
+	return nil
+}
+
+// Complete records the encoded file of a finished narration.
+func (r *NarrationRepository) Complete(ctx context.Context, id, storage, key string, size, durationMs int64, at time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&domain.Narration{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       domain.NarrationReady,
+		"storage":      storage,
+		"object_key":   key,
+		"size":         size,
+		"duration_ms":  durationMs,
+		"error_msg":    "",
+		"completed_at": at,
+	}).Error; err != nil {
+		return liberrors.Wrap(err, liberrors.CodeInternal, "complete narration")
+	}
+	return nil
+}
+
+// Fail marks a narration failed with msg.
+func (r *NarrationRepository) Fail(ctx context.Context, id, msg string) error {
+	if err := r.db.WithContext(ctx).Model(&domain.Narration{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status": domain.NarrationFailed, "error_msg": msg}).Error; err != nil {
+		return liberrors.Wrap(err, liberrors.CodeInternal, "fail narration")
+	}
+	return nil
+}
+
+// ResetStale pauses narrations left running since before cutoff — a worker
+// that died mid-job. They resume from their last stored chunk.
+func (r *NarrationRepository) ResetStale(ctx context.Context, cutoff time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Model(&domain.Narration{}).
+		Where("status = ? AND updated_at < ?", domain.NarrationRunning, cutoff).
+		Update("status", domain.NarrationPaused)
+	if res.Error != nil {
+		return 0, liberrors.Wrap(res.Error, liberrors.CodeInternal, "reset stale narrations")
+	}
+	return res.RowsAffected, nil
+}
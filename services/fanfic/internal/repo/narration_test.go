@@ -0,0 +1,107 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+)
+
+func newTestNarrationRepo(t *testing.T) *NarrationRepository {
+	t.Helper()
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&domain.Narration{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+	return NewNarrationRepository(db)
+}
+
+func TestNarrationRepository_CreateOrGetSharesFingerprint(t *testing.T) {
+	r := newTestNarrationRepo(t)
+	ctx := context.Background()
+
+	first, err := r.CreateOrGet(ctx, &domain.Narration{UserID: "u1", FanficID: "f1", Fingerprint: "fp", Status: domain.NarrationPending, ChunksTotal: 3})
+	if err != nil {
+		t.Fatalf("CreateOrGet: %v", err)
+	}
+	second, err := r.CreateOrGet(ctx, &domain.Narration{UserID: "u2", FanficID: "f1", Fingerprint: "fp", Status: domain.NarrationPending})
+	if err != nil {
+		t.Fatalf("CreateOrGet again: %v", err)
+	}
+	if second.ID != first.ID || second.UserID != "u1" || second.ChunksTotal != 3 {
+		t.Errorf("second = %+v; want the first row back", second)
+	}
+}
+
+func TestNarrationRepository_PauseAndResume(t *testing.T) {
+	r := newTestNarrationRepo(t)
+	ctx := context.Background()
+
+	n, _ := r.CreateOrGet(ctx, &domain.Narration{UserID: "u1", FanficID: "f1", Fingerprint: "a", Status: domain.NarrationPending, ChunksTotal: 4})
+	claimed, err := r.ClaimNext(ctx, time.Now())
+	if err != nil || claimed == nil || claimed.ID != n.ID || claimed.Status != domain.NarrationRunning {
+		t.Fatalf("ClaimNext = %+v, %v", claimed, err)
+	}
+	if err := r.Advance(ctx, n.ID, 2, "minio"); err != nil {
+		t.Fatalf("Advance: %v", err)
+	}
+	if err := r.Pause(ctx, n.ID, 1, "engine down"); err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+
+	// A freshly paused job waits out the retry delay.
+	if got, _ := r.ClaimNext(ctx, time.Now().Add(-time.Minute)); got != nil {
+		t.Fatalf("claimed a paused job before its retry time: %+v", got)
+	}
+	claimed, _ = r.ClaimNext(ctx, time.Now().Add(time.Minute))
+	if claimed == nil || claimed.ChunksDone != 2 || claimed.ChunkStorage != "minio" || claimed.Attempts != 1 {
+		t.Fatalf("resumed job = %+v", claimed)
+	}
+
+	if err := r.Complete(ctx, n.ID, "minio", "fanfic-narrations/a/narration.opus", 42, 61000, time.Now()); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	got, _ := r.Get(ctx, n.ID)
+	if got.Status != domain.NarrationReady || got.Size != 42 || got.DurationMs != 61000 || got.ErrorMsg != "" || got.CompletedAt == nil {
+		t.Errorf("completed row = %+v", got)
+	}
+}
+
+func TestNarrationRepository_FailRequeueKeepsProgress(t *testing.T) {
+	r := newTestNarrationRepo(t)
+	ctx := context.Background()
+
+	n, _ := r.CreateOrGet(ctx, &domain.Narration{UserID: "u1", FanficID: "f1", Fingerprint: "a", Status: domain.NarrationPending})
+	_, _ = r.ClaimNext(ctx, time.Now())
+	_ = r.Advance(ctx, n.ID, 5, "minio")
+	if err := r.Fail(ctx, n.ID, "boom"); err != nil {
+		t.Fatalf("Fail: %v", err)
+	}
+	if err := r.Requeue(ctx, n.ID, "u2"); err != nil {
+		t.Fatalf("Requeue: %v", err)
+	}
+	got, _ := r.Get(ctx, n.ID)
+	if got.Status != domain.NarrationPending || got.ErrorMsg != "" || got.UserID != "u2" || got.ChunksDone != 5 {
+		t.Errorf("requeued row = %+v", got)
+	}
+}
+
+func TestNarrationRepository_ResetStale(t *testing.T) {
+	r := newTestNarrationRepo(t)
+	ctx := context.Background()
+
+	n, _ := r.CreateOrGet(ctx, &domain.Narration{UserID: "u1", FanficID: "f1", Fingerprint: "a", Status: domain.NarrationPending})
+	if _, err := r.ClaimNext(ctx, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if c, _ := r.ResetStale(ctx, time.Now().Add(-time.Hour)); c != 0 {
+		t.Errorf("reset %d fresh rows; want 0", c)
+	}
+	if c, _ := r.ResetStale(ctx, time.Now().Add(time.Hour)); c != 1 {
+		t.Errorf("reset %d stale rows; want 1", c)
+	}
+	if got, _ := r.Get(ctx, n.ID); got.Status != domain.NarrationPaused {
+		t.Errorf("status = %q; want paused", got.Status)
+	}
+}
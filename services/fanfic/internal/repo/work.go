@@ -0,0 +1,428 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	liberrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WorkRepository stores human-authored works (fanfics rows with
+// origin=human), their chapters, and the reader-side kudos, bookmarks and
+// subscriptions. Unlike Repository it is not owner-scoped: the service
+// decides who may see or change what.
+type WorkRepository struct {
+	db *gorm.DB
+}
+
+func NewWorkRepository(db *gorm.DB) *WorkRepository { return &WorkRepository{db: db} }
+
+// publicWorks restricts q to works anyone may read: published human works,
+// plus generated fanfics that are public already through the daily
+// spotlight (bot rows and rows whose author opted into spotlight credit).
+// Keep in sync with domain.Fanfic.IsPublic.
+func publicWorks(q *gorm.DB) *gorm.DB {
+	return q.Where(
+		"(origin = ? AND status = ?) OR (origin = ? AND status = ? AND (ai_generated = ? OR spotlight_credit = ?))",
+		domain.OriginHuman, domain.StatusPublished,
+		domain.OriginGenerated, domain.StatusComplete, true, true,
+	)
+}
+
+// CreateWork inserts a new work. An empty AnimeID is omitted like in
+// Repository.Create.
+func (r *WorkRepository) CreateWork(ctx context.Context, f *domain.Fanfic) error {
+	db := r.db.WithContext(ctx)
+	if f.AnimeID == "" {
+		db = db.Omit("anime_id")
+	}
+	if err := db.Create(f).Error; err != nil {
+		return liberrors.Wrap(err, liberrors.CodeInternal, "create work")
+	}
+	return nil
+}
+
+// GetWork fetches any fanfic by id; a missing row returns NotFound.
+func (r *WorkRepository) GetWork(ctx context.Context, id string) (*domain.Fanfic, error) {
+	var f domain.Fanfic
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&f).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, liberrors.NotFound("work")
+		}
+		return nil, liberrors.Wrap(err, liberrors.CodeInternal, "get work")
+	}
+	return &f, nil
+}
+
+// UpdateWorkMeta stores the author-editable metadata of a work.
+func (r *WorkRepository) UpdateWorkMeta(ctx context.Context, f *domain.Fanfic) error {
+	updates := map[string]interface{}{
+		"anime_shikimori_id": f.AnimeShikimoriID,
+		"anime_title":        f.AnimeTitle,
+		"anime_japanese":     f.AnimeJapanese,
+		"anime_poster":       f.AnimePoster,
+		"title":              f.Title,
+		"summary":            f.Summary,
+		"characters":         f.Characters,
+		"tags":               f.Tags,
+		"rating":             f.Rating,
+		"language":           f.Language,
+	}
+	if f.AnimeID != "" {
+		updates["anime_id"] = f.AnimeID
+	}
+	if err := r.db.WithContext(ctx).Model(&domain.Fanfic{}).Where("id = ?", f.ID).Updates(updates).Error; err != nil {
+		return liberrors.Wrap(err, liberrors.CodeInternal, "update work")
+	}
+	return nil
+}
+
+// CreateChapter appends c to its work, numbering it after the last chapter.
+func (r *WorkRepository) CreateChapter(ctx context.Context, c *domain.Chapter) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var last int
+		if err := tx.Model(&domain.Chapter{}).Where("fanfic_id = ?", c.FanficID).
+			Select("COALESCE(MAX(position), 0)").Scan(&last).Error; err != nil {
+			return err
+		}
+		c.Position = last + 1
+		return tx.Create(c).Error
+	})
+	if err != nil {
+		return liberrors.Wrap(err, liberrors.CodeInternal, "create chapter")
+	}
+	return nil
+}
+
+// GetChapter fetches one chapter of a work; a missing row returns NotFound.
+func (r *WorkRepository) GetChapter(ctx context.Context, workID, chapterID string) (*domain.Chapter, error) {
+	var c domain.Chapter
+	if err := r.db.WithContext(ctx).Where("id = ? AND fanfic_id = ?", chapterID, workID).First(&c).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, liberrors.NotFound("chapter")
+		}
+		return nil, liberrors.Wrap(err, liberrors.CodeInternal, "get chapter")
+	}
+	return &c, nil
+}
+
+// UpdateChapter stores a chapter's text and publication state, then
+// refreshes the work's totals.
+func (r *WorkRepository) UpdateChapter(ctx context.Context, c *domain.Chapter) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.Chapter{}).Where("id = ?", c.ID).Updates(map[string]interface{}{
+			"title":        c.Title,
+			"content":      c.Content,
+			"word_count":   c.WordCount,
+			"status":       c.Status,
+			"publish_at":   c.PublishAt,
+			"published_at": c.PublishedAt,
+		}).Error; err != nil {
+			return err
+		}
+		return refreshWork(tx, c.FanficID, c.PublishedAt)
+	})
+	if err != nil {
+		return liberrors.Wrap(err, liberrors.CodeInternal, "update chapter")
+	}
+	return nil
+}
+
+// PublishChapter moves a chapter from status from to published with
+// c.PublishedAt, then refreshes the work's totals. The update is conditional
+// on the row still being in from, so two publishers racing over one chapter
+// (the scheduler on two replicas, or the author publishing by hand while it
+// comes due) publish it once; the loser gets false and writes nothing.
+func (r *WorkRepository) PublishChapter(ctx context.Context, c *domain.Chapter, from string) (bool, error) {
+	published := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.Chapter{}).Where("id = ? AND status = ?", c.ID, from).Updates(map[string]interface{}{
+			"status":       domain.StatusPublished,
+			"publish_at":   nil,
+			"published_at": c.PublishedAt,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		published = true
+		return refreshWork(tx, c.FanficID, c.PublishedAt)
+	})
+	if err != nil {
+		return false, liberrors.Wrap(err, liberrors.CodeInternal, "publish chapter")
+	}
+	return published, nil
+}
+
+// DeleteChapter removes a chapter, closes the gap in the numbering and
+// refreshes the work's totals.
+func (r *WorkRepository) DeleteChapter(ctx context.Context, c *domain.Chapter) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", c.ID).Delete(&domain.Chapter{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.Chapter{}).
+			Where("fanfic_id = ? AND position > ?", c.FanficID, c.Position).
+			Update("position", gorm.Expr("position - 1")).Error; err != nil {
+			return err
+		}
+		return refreshWork(tx, c.FanficID, nil)
+	})
+	if err != nil {
+		return liberrors.Wrap(err, liberrors.CodeInternal, "delete chapter")
+	}
+	return nil
+}
+
+// refreshWork recomputes a work's part_count, word_count and length from its
+// published chapters. A work with a published chapter is published (first
+// published at publishedAt); one without falls back to draft.
+func refreshWork(tx *gorm.DB, workID string, publishedAt *time.Time) error {
+	var totals struct {
+		Parts int
+		Words int
+	}
+	if err := tx.Model(&domain.Chapter{}).
+		Where("fanfic_id = ? AND status = ?", workID, domain.StatusPublished).
+		Select("COUNT(*) AS parts, COALESCE(SUM(word_count), 0) AS words").
+		Scan(&totals).Error; err != nil {
+		return err
+	}
+	updates := map[string]interface{}{
+		"part_count": totals.Parts,
+		"word_count": totals.Words,
+		"length":     domain.LengthForWords(totals.Words),
+		"status":     domain.StatusDraft,
+	}
+	if totals.Parts > 0 {
+		updates["status"] = domain.StatusPublished
+	}
+	q := tx.Model(&domain.Fanfic{}).Where("id = ?", workID)
+	if err := q.Updates(updates).Error; err != nil {
+		return err
+	}
+	if totals.Parts > 0 && publishedAt != nil {
+		return tx.Model(&domain.Fanfic{}).
+			Where("id = ? AND published_at IS NULL", workID).
+			Update("published_at", *publishedAt).Error
+	}
+	return nil
+}
+
+// Chapters lists a work's chapters in reading order; publishedOnly hides
+// drafts and scheduled chapters.
+func (r *WorkRepository) Chapters(ctx context.Context, workID string, publishedOnly bool) ([]domain.Chapter, error) {
+	var out []domain.Chapter
+	q := r.db.WithContext(ctx).Where("fanfic_id = ?", workID)
+	if publishedOnly {
+		q = q.Where("status = ?", domain.StatusPublished)
+	}
+	if err := q.Order("position ASC").Find(&out).Error; err != nil {
+		return nil, liberrors.Wrap(err, liberrors.CodeInternal, "list chapters")
+	}
+	return out, nil
+}
+
+// DueChapters returns scheduled chapters whose publish time has come,
+// oldest first.
+func (r *WorkRepository) DueChapters(ctx context.Context, now time.Time, limit int) ([]domain.Chapter, error) {
+	var out []domain.Chapter
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND publish_at <= ?", domain.StatusScheduled, now).
+		Order("publish_at ASC").Limit(limit).Find(&out).Error
+	if err != nil {
+		return nil, liberrors.Wrap(err, liberrors.CodeInternal, "due chapters")
+	}
+	return out, nil
+}
+
+// Search lists public works matching p, plus the total match count.
+func (r *WorkRepository) Search(ctx context.Context, p domain.SearchParams) ([]domain.Fanfic, int64, error) {
+	q := publicWorks(r.db.WithContext(ctx).Model(&domain.Fanfic{}))
+	if p.Query != "" {
+		like := "%" + escapeLike(strings.ToLower(p.Query)) + "%"
+		q = q.Where(`(LOWER(title) LIKE ? ESCAPE '\' OR LOWER(summary) LIKE ? ESCAPE '\' OR LOWER(anime_title) LIKE ? ESCAPE '\')`, like, like, like)
+	}
+	if p.AnimeID != "" {
+		q = q.Where("anime_id = ?", p.AnimeID)
+	}
+	if p.ShikimoriID != "" {
+		q = q.Where("anime_shikimori_id = ?", p.ShikimoriID)
+	}
+	// Tags are stored as a JSON string array; matching the quoted value in
+	// its text form works on both Postgres jsonb and SQLite.
+	for _, t := range p.Tags {
+		q = q.Where(`CAST(tags AS TEXT) LIKE ? ESCAPE '\'`, `%"`+escapeLike(t)+`"%`)
+	}
+	if p.Rating != "" {
+		q = q.Where("rating = ?", p.Rating)
+	}
+	if p.Length != "" {
+		q = q.Where("length = ?", p.Length)
+	}
+	if p.Origin != "" {
+		q = q.Where("origin = ?", p.Origin)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, liberrors.Wrap(err, liberrors.CodeInternal, "count works")
+	}
+	order := "COALESCE(published_at, created_at) DESC, id DESC"
+	if p.Sort == domain.SortKudos {
+		order = "kudos_count DESC, " + order
+	}
+	var items []domain.Fanfic
+	if err := q.Order(order).Limit(p.Limit).Offset(p.Offset).Find(&items).Error; err != nil {
+		return nil, 0, liberrors.Wrap(err, liberrors.CodeInternal, "search works")
+	}
+	return items, total, nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// AddKudos records the user's kudos on a work once; a repeat is a no-op and
+// reports added=false.
+func (r *WorkRepository) AddKudos(ctx context.Context, workID, userID string) (bool, error) {
+	added := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&domain.Kudos{FanficID: workID, UserID: userID})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		added = true
+		return tx.Model(&domain.Fanfic{}).Where("id = ?", workID).
+			Update("kudos_count", gorm.Expr("kudos_count + 1")).Error
+	})
+	if err != nil {
+		return false, liberrors.Wrap(err, liberrors.CodeInternal, "add kudos")
+	}
+	return added, nil
+}
+
+// SetBookmark bookmarks a work for the user, or updates the note of an
+// existing bookmark.
+func (r *WorkRepository) SetBookmark(ctx context.Context, b *domain.Bookmark) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.Bookmark{}).
+			Where("user_id = ? AND fanfic_id = ?", b.UserID, b.FanficID).
+			Update("note", b.Note)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			return nil
+		}
+		if err := tx.Create(b).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.Fanfic{}).Where("id = ?", b.FanficID).
+			Update("bookmark_count", gorm.Expr("bookmark_count + 1")).Error
+	})
+	if err != nil {
+		return liberrors.Wrap(err, liberrors.CodeInternal, "set bookmark")
+	}
+	return nil
+}
+
+// DeleteBookmark removes the user's bookmark; a missing one returns NotFound.
+func (r *WorkRepository) DeleteBookmark(ctx context.Context, userID, workID string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("user_id = ? AND fanfic_id = ?", userID, workID).Delete(&domain.Bookmark{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Model(&domain.Fanfic{}).Where("id = ? AND bookmark_count > 0", workID).
+			Update("bookmark_count", gorm.Expr("bookmark_count - 1")).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return liberrors.NotFound("bookmark")
+	}
+	if err != nil {
+		return liberrors.Wrap(err, liberrors.CodeInternal, "delete bookmark")
+	}
+	return nil
+}
+
+// Bookmarks lists the user's bookmarked works, newest bookmark first. Works
+// that were deleted or are no longer public (and not the user's own) drop
+// out.
+func (r *WorkRepository) Bookmarks(ctx context.Context, userID string, limit, offset int) ([]domain.BookmarkedWork, int64, error) {
+	q := r.db.WithContext(ctx).Model(&domain.Fanfic{}).
+		Joins("JOIN fanfic_bookmarks b ON b.fanfic_id = fanfics.id AND b.user_id = ?", userID).
+		Where(publicWorks(r.db.Session(&gorm.Session{NewDB: true})).Or("fanfics.user_id = ?", userID))
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, liberrors.Wrap(err, liberrors.CodeInternal, "count bookmarks")
+	}
+	var items []domain.BookmarkedWork
+	if err := q.Select("fanfics.*, b.note AS note, b.created_at AS bookmarked_at").
+		Order("b.created_at DESC").Limit(limit).Offset(offset).Scan(&items).Error; err != nil {
+		return nil, 0, liberrors.Wrap(err, liberrors.CodeInternal, "list bookmarks")
+	}
+	return items, total, nil
+}
+
+// Subscribe subscribes the user to a work's new chapters (idempotent).
+func (r *WorkRepository) Subscribe(ctx context.Context, userID, workID string) error {
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&domain.Subscription{UserID: userID, FanficID: workID}).Error; err != nil {
+		return liberrors.Wrap(err, liberrors.CodeInternal, "subscribe")
+	}
+	return nil
+}
+
+// Unsubscribe drops the user's subscription (idempotent).
+func (r *WorkRepository) Unsubscribe(ctx context.Context, userID, workID string) error {
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND fanfic_id = ?", userID, workID).
+		Delete(&domain.Subscription{}).Error; err != nil {
+		return liberrors.Wrap(err, liberrors.CodeInternal, "unsubscribe")
+	}
+	return nil
+}
+
+// Subscribers lists the users subscribed to a work.
+func (r *WorkRepository) Subscribers(ctx context.Context, workID string) ([]string, error) {
+	var ids []string
+	if err := r.db.WithContext(ctx).Model(&domain.Subscription{}).
+		Where("fanfic_id = ?", workID).Pluck("user_id", &ids).Error; err != nil {
+		return nil, liberrors.Wrap(err, liberrors.CodeInternal, "list subscribers")
+	}
+	return ids, nil
+}
+
+// ViewerState reports whether the user gave kudos to, bookmarked and
+// subscribed to a work.
+func (r *WorkRepository) ViewerState(ctx context.Context, userID, workID string) (kudosed, bookmarked, subscribed bool, err error) {
+	db := r.db.WithContext(ctx)
+	var n int64
+	if err = db.Model(&domain.Kudos{}).Where("fanfic_id = ? AND user_id = ?", workID, userID).Count(&n).Error; err != nil {
+		return false, false, false, liberrors.Wrap(err, liberrors.CodeInternal, "viewer kudos")
+	}
+	kudosed = n > 0
+	if err = db.Model(&domain.Bookmark{}).Where("fanfic_id = ? AND user_id = ?", workID, userID).Count(&n).Error; err != nil {
+		return false, false, false, liberrors.Wrap(err, liberrors.CodeInternal, "viewer bookmark")
+	}
+	bookmarked = n > 0
+	if err = db.Model(&domain.Subscription{}).Where("fanfic_id = ? AND user_id = ?", workID, userID).Count(&n).Error; err != nil {
+		return false, false, false, liberrors.Wrap(err, liberrors.CodeInternal, "viewer subscription")
+	}
+	subscribed = n > 0
+	return kudosed, bookmarked, subscribed, nil
+}
@@ -0,0 +1,288 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func newTestWorkRepo(t *testing.T) (*WorkRepository, *gorm.DB) {
+	t.Helper()
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&domain.Chapter{}, &domain.Kudos{}, &domain.Bookmark{}, &domain.Subscription{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+	return NewWorkRepository(db), db
+}
+
+func createWork(t *testing.T, r *WorkRepository, userID, title string, tags string) *domain.Fanfic {
+	t.Helper()
+	f := &domain.Fanfic{
+		UserID: userID, Origin: domain.OriginHuman, Status: domain.StatusDraft,
+		AnimeTitle: "Frieren", Title: title, Rating: "teen", Language: "ru",
+		Tags: datatypes.JSON([]byte(tags)),
+	}
+	if err := r.CreateWork(context.Background(), f); err != nil {
+		t.Fatalf("CreateWork: %v", err)
+	}
+	return f
+}
+
+func addChapter(t *testing.T, r *WorkRepository, workID, content string) *domain.Chapter {
+	t.Helper()
+	c := &domain.Chapter{FanficID: workID, Content: content, WordCount: domain.CountWords(content), Status: domain.StatusDraft}
+	if err := r.CreateChapter(context.Background(), c); err != nil {
+		t.Fatalf("CreateChapter: %v", err)
+	}
+	return c
+}
+
+func publishChapter(t *testing.T, r *WorkRepository, c *domain.Chapter, at time.Time) {
+	t.Helper()
+	c.Status = domain.StatusPublished
+	c.PublishedAt = &at
+	if err := r.UpdateChapter(context.Background(), c); err != nil {
+		t.Fatalf("UpdateChapter: %v", err)
+	}
+}
+
+func TestCreateChapter_NumbersSequentially(t *testing.T) {
+	r, _ := newTestWorkRepo(t)
+	w := createWork(t, r, "u1", "W", `[]`)
+	c1 := addChapter(t, r, w.ID, "one")
+	c2 := addChapter(t, r, w.ID, "two")
+	if c1.Position != 1 || c2.Position != 2 {
+		t.Fatalf("positions = %d, %d; want 1, 2", c1.Position, c2.Position)
+	}
+
+	if err := r.DeleteChapter(context.Background(), c1); err != nil {
+		t.Fatalf("DeleteChapter: %v", err)
+	}
+	chapters, err := r.Chapters(context.Background(), w.ID, false)
+	if err != nil {
+		t.Fatalf("Chapters: %v", err)
+	}
+	if len(chapters) != 1 || chapters[0].ID != c2.ID || chapters[0].Position != 1 {
+		t.Errorf("after delete: %+v; want c2 renumbered to 1", chapters)
+	}
+}
+
+func TestUpdateChapter_PublishingRefreshesWork(t *testing.T) {
+	r, _ := newTestWorkRepo(t)
+	ctx := context.Background()
+	w := createWork(t, r, "u1", "W", `[]`)
+	c1 := addChapter(t, r, w.ID, "раз два три")
+	addChapter(t, r, w.ID, "draft words that do not count")
+
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	publishChapter(t, r, c1, at)
+
+	got, err := r.GetWork(ctx, w.ID)
+	if err != nil {
+		t.Fatalf("GetWork: %v", err)
+	}
+	if got.Status != domain.StatusPublished || got.PartCount != 1 || got.WordCount != 3 {
+		t.Errorf("work = status %q parts %d words %d; want published/1/3", got.Status, got.PartCount, got.WordCount)
+	}
+	if got.Length != "drabble" {
+		t.Errorf("Length = %q; want drabble", got.Length)
+	}
+	if got.PublishedAt == nil || !got.PublishedAt.Equal(at) {
+		t.Errorf("PublishedAt = %v; want %v", got.PublishedAt, at)
+	}
+
+	published, err := r.Chapters(ctx, w.ID, true)
+	if err != nil {
+		t.Fatalf("Chapters: %v", err)
+	}
+	if len(published) != 1 || published[0].ID != c1.ID {
+		t.Errorf("published chapters = %+v; want only c1", published)
+	}
+
+	// Removing the only published chapter turns the work back into a draft.
+	if err := r.DeleteChapter(ctx, c1); err != nil {
+		t.Fatalf("DeleteChapter: %v", err)
+	}
+	got, _ = r.GetWork(ctx, w.ID)
+	if got.Status != domain.StatusDraft || got.PartCount != 0 {
+		t.Errorf("after delete: status %q parts %d; want draft/0", got.Status, got.PartCount)
+	}
+}
+
+func TestDueChapters(t *testing.T) {
+	r, _ := newTestWorkRepo(t)
+	ctx := context.Background()
+	w := createWork(t, r, "u1", "W", `[]`)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	due := addChapter(t, r, w.ID, "due")
+	past := now.Add(-time.Minute)
+	due.Status, due.PublishAt = domain.StatusScheduled, &past
+	_ = r.UpdateChapter(ctx, due)
+
+	later := addChapter(t, r, w.ID, "later")
+	future := now.Add(time.Hour)
+	later.Status, later.PublishAt = domain.StatusScheduled, &future
+	_ = r.UpdateChapter(ctx, later)
+
+	got, err := r.DueChapters(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("DueChapters: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != due.ID {
+		t.Errorf("due = %+v; want only the past-due chapter", got)
+	}
+}
+
+func TestSearch_PublicOnlyAndFilters(t *testing.T) {
+	r, db := newTestWorkRepo(t)
+	ctx := context.Background()
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	angst := createWork(t, r, "u1", "Тихий вечер", `["angst","slow-burn"]`)
+	publishChapter(t, r, addChapter(t, r, angst.ID, "text"), at)
+	fluff := createWork(t, r, "u2", "Morning", `["fluff"]`)
+	publishChapter(t, r, addChapter(t, r, fluff.ID, "text"), at.Add(time.Hour))
+	createWork(t, r, "u3", "Draft only", `["angst"]`) // never published
+
+	// Generated rows: a spotlight-credited one is public, a private one is not.
+	for _, f := range []*domain.Fanfic{
+		{UserID: "u4", Origin: domain.OriginGenerated, Status: domain.StatusComplete, SpotlightCredit: true, Title: "Gen public", CreatedAt: at.Add(-time.Hour), Tags: datatypes.JSON([]byte(`["angst"]`)), Rating: "teen", Length: "oneshot"},
+		{UserID: "u5", Origin: domain.OriginGenerated, Status: domain.StatusComplete, Title: "Gen private", Tags: datatypes.JSON([]byte(`["angst"]`)), Rating: "teen", Length: "oneshot"},
+	} {
+		if err := db.Create(f).Error; err != nil {
+			t.Fatalf("create generated: %v", err)
+		}
+	}
+
+	cases := []struct {
+		name string
+		p    domain.SearchParams
+		want []string
+	}{
+		{"all public", domain.SearchParams{}, []string{"Morning", "Тихий вечер", "Gen public"}},
+		{"tag", domain.SearchParams{Tags: []string{"angst"}}, []string{"Тихий вечер", "Gen public"}},
+		{"two tags", domain.SearchParams{Tags: []string{"angst", "slow-burn"}}, []string{"Тихий вечер"}},
+		{"origin human", domain.SearchParams{Origin: domain.OriginHuman}, []string{"Morning", "Тихий вечер"}},
+		{"origin generated", domain.SearchParams{Origin: domain.OriginGenerated}, []string{"Gen public"}},
+		{"query", domain.SearchParams{Query: "morn"}, []string{"Morning"}},
+		{"length", domain.SearchParams{Length: "oneshot"}, []string{"Gen public"}},
+	}
+	for _, tc := range cases {
+		tc.p.Limit = 10
+		items, total, err := r.Search(ctx, tc.p)
+		if err != nil {
+			t.Fatalf("%s: Search: %v", tc.name, err)
+		}
+		var titles []string
+		for _, it := range items {
+			titles = append(titles, it.Title)
+		}
+		if int(total) != len(tc.want) || len(titles) != len(tc.want) {
+			t.Errorf("%s: got %v (total %d); want %v", tc.name, titles, total, tc.want)
+			continue
+		}
+		for i := range titles {
+			if titles[i] != tc.want[i] {
+				t.Errorf("%s: got %v; want %v", tc.name, titles, tc.want)
+				break
+			}
+		}
+	}
+}
+
+func TestAddKudos_OncePerUser(t *testing.T) {
+	r, _ := newTestWorkRepo(t)
+	ctx := context.Background()
+	w := createWork(t, r, "u1", "W", `[]`)
+
+	added, err := r.AddKudos(ctx, w.ID, "u2")
+	if err != nil || !added {
+		t.Fatalf("first AddKudos = %v, %v; want true, nil", added, err)
+	}
+	added, err = r.AddKudos(ctx, w.ID, "u2")
+	if err != nil || added {
+		t.Fatalf("repeat AddKudos = %v, %v; want false, nil", added, err)
+	}
+	_, _ = r.AddKudos(ctx, w.ID, "u3")
+
+	got, _ := r.GetWork(ctx, w.ID)
+	if got.KudosCount != 2 {
+		t.Errorf("KudosCount = %d; want 2", got.KudosCount)
+	}
+	kudosed, _, _, err := r.ViewerState(ctx, "u2", w.ID)
+	if err != nil || !kudosed {
+		t.Errorf("ViewerState kudosed = %v, %v; want true", kudosed, err)
+	}
+}
+
+func TestBookmarks(t *testing.T) {
+	r, _ := newTestWorkRepo(t)
+	ctx := context.Background()
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	w := createWork(t, r, "u1", "Public", `[]`)
+	publishChapter(t, r, addChapter(t, r, w.ID, "text"), at)
+	draft := createWork(t, r, "u1", "Draft", `[]`)
+
+	if err := r.SetBookmark(ctx, &domain.Bookmark{UserID: "u2", FanficID: w.ID, Note: "first"}); err != nil {
+		t.Fatalf("SetBookmark: %v", err)
+	}
+	if err := r.SetBookmark(ctx, &domain.Bookmark{UserID: "u2", FanficID: w.ID, Note: "reread"}); err != nil {
+		t.Fatalf("SetBookmark update: %v", err)
+	}
+	// A bookmark on a work that is not public (anymore) drops out of the list.
+	if err := r.SetBookmark(ctx, &domain.Bookmark{UserID: "u2", FanficID: draft.ID}); err != nil {
+		t.Fatalf("SetBookmark draft: %v", err)
+	}
+
+	items, total, err := r.Bookmarks(ctx, "u2", 10, 0)
+	if err != nil {
+		t.Fatalf("Bookmarks: %v", err)
+	}
+	if total != 1 || len(items) != 1 || items[0].ID != w.ID || items[0].Note != "reread" {
+		t.Fatalf("Bookmarks = %+v (total %d); want the public work with the updated note", items, total)
+	}
+	got, _ := r.GetWork(ctx, w.ID)
+	if got.BookmarkCount != 1 {
+		t.Errorf("BookmarkCount = %d; want 1", got.BookmarkCount)
+	}
+
+	if err := r.DeleteBookmark(ctx, "u2", w.ID); err != nil {
+		t.Fatalf("DeleteBookmark: %v", err)
+	}
+	if err := r.DeleteBookmark(ctx, "u2", w.ID); err == nil {
+		t.Error("expected not-found on repeated DeleteBookmark")
+	}
+	got, _ = r.GetWork(ctx, w.ID)
+	if got.BookmarkCount != 0 {
+		t.Errorf("BookmarkCount after delete = %d; want 0", got.BookmarkCount)
+	}
+}
+
+func TestSubscriptions(t *testing.T) {
+	r, _ := newTestWorkRepo(t)
+	ctx := context.Background()
+	w := createWork(t, r, "u1", "W", `[]`)
+
+	for i := 0; i < 2; i++ {
+		if err := r.Subscribe(ctx, "u2", w.ID); err != nil {
+			t.Fatalf("Subscribe: %v", err)
+		}
+	}
+	_ = r.Subscribe(ctx, "u3", w.ID)
+	subs, err := r.Subscribers(ctx, w.ID)
+	if err != nil || len(subs) != 2 {
+		t.Fatalf("Subscribers = %v, %v; want 2", subs, err)
+	}
+	if err := r.Unsubscribe(ctx, "u2", w.ID); err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+	subs, _ = r.Subscribers(ctx, w.ID)
+	if len(subs) != 1 || subs[0] != "u3" {
+		t.Errorf("Subscribers after unsubscribe = %v; want [u3]", subs)
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+)
+
+// notifyTypeFanficChapter is emitted when a subscribed work gets a new
+// chapter. Mirrored by the notifications service's TypeFanficChapter.
+const notifyTypeFanficChapter = "fanfic_chapter"
+
+// uuidRe guards user ids: user_notifications.user_id is a Postgres uuid column.
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ChapterNotifier is the fire-and-forget producer of new-chapter
+// notifications. It POSTs to the notifications service's internal upsert
+// over the Docker network; a notifications outage never blocks publishing.
+type ChapterNotifier struct {
+	baseURL string
+	enabled bool
+	client  *http.Client
+	log     *logger.Logger
+}
+
+// NewChapterNotifier constructs the producer. enabled=false turns every call
+// into a no-op.
+func NewChapterNotifier(baseURL string, enabled bool, log *logger.Logger) *ChapterNotifier {
+	if log == nil {
+		log = logger.Default()
+	}
+	return &ChapterNotifier{
+		baseURL: baseURL,
+		enabled: enabled,
+		client:  &http.Client{Timeout: 5 * time.Second},
+		log:     log,
+	}
+}
+
+// fanficChapterNotification is the payload of fanfic_chapter.
+type fanficChapterNotification struct {
+	FanficID     string `json:"fanfic_id"`
+	ChapterID    string `json:"chapter_id"`
+	Title        string `json:"title"`
+	ChapterTitle string `json:"chapter_title,omitempty"`
+	Position     int    `json:"position"`
+	Author       string `json:"author,omitempty"`
+	URL          string `json:"url"`
+}
+
+// NotifyChapter tells each subscriber that c of work was published.
+func (n *ChapterNotifier) NotifyChapter(ctx context.Context, work *domain.Fanfic, c *domain.Chapter, userIDs []string) {
+	if n == nil || !n.enabled || n.baseURL == "" || work == nil || c == nil {
+		return
+	}
+	payload, err := json.Marshal(fanficChapterNotification{
+		FanficID:     work.ID,
+		ChapterID:    c.ID,
+		Title:        work.Title,
+		ChapterTitle: c.Title,
+		Position:     c.Position,
+		Author:       work.AuthorUsername,
+		URL:          fmt.Sprintf("/fanfics?work=%s&chapter=%d", work.ID, c.Position),
+	})
+	if err != nil {
+		n.log.Errorw("chapter notify: marshal payload", "fanfic_id", work.ID, "err", err)
+		return
+	}
+	for _, uid := range userIDs {
+		if !uuidRe.MatchString(uid) {
+			continue
+		}
+		body := map[string]interface{}{
+			"user_id":    uid,
+			"type":       notifyTypeFanficChapter,
+			"dedupe_key": fmt.Sprintf("fanfic_chapter:%s", c.ID),
+			"payload":    json.RawMessage(payload),
+		}
+		if err := n.post(ctx, body); err != nil {
+			n.log.Warnw("chapter notify failed (non-fatal)",
+				"fanfic_id", work.ID, "chapter_id", c.ID, "user_id", uid, "err", err)
+		}
+	}
+}
+
+func (n *ChapterNotifier) post(ctx context.Context, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.baseURL+"/internal/notifications", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, string(b))
+	}
+	return nil
+}
@@ -6,12 +6,14 @@ import (
 
 	liberrors "github.com/ILITA-hub/animeenigma/libs/errors"
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/llm"
 )
 
 // Continue generates the next part of an existing, complete fanfic and appends
 // it to that fanfic's content, sectioned by a divider + «Часть N» heading. It
 // reuses every stored parameter (length/POV/rating/language/canon); the prior
-// content is fed back as context, bounded to contextRunes.
+// content is fed back as context, bounded to contextRunes. It prefers the
+// backend/model the story was written with, falling back like Generate.
 func (g *Generator) Continue(ctx context.Context, userID, id string, emit Emit) error {
 	f, err := g.store.Get(ctx, userID, id)
 	if err != nil {
@@ -24,6 +26,15 @@ func (g *Generator) Continue(ctx context.Context, userID, id string, emit Emit)
 		return e
 	}
 
+	plan, err := g.backends.Plan(f.Backend, f.Model)
+	if err != nil {
+		// The story's backend or model has since been retired; use the defaults.
+		if plan, err = g.backends.Plan("", ""); err != nil {
+			g.safeEmit(emit, "error", map[string]any{"message": err.Error()})
+			return err
+		}
+	}
+
 	release, err := g.quota.Acquire(ctx, userID)
 	if err != nil {
 		g.safeEmit(emit, "error", map[string]any{"message": err.Error()})
@@ -35,25 +46,25 @@ func (g *Generator) Continue(ctx context.Context, userID, id string, emit Emit)
 	heading := headingWord(f.Language)
 	prefix := fmt.Sprintf("\n\n---\n\n## %s %d\n\n", heading, part)
 
-	g.safeEmit(emit, "meta", map[string]any{"id": f.ID, "model": g.model, "part": part})
+	g.safeEmit(emit, "meta", map[string]any{"id": f.ID, "backend": plan[0].Backend, "model": plan[0].Model, "part": part})
 	// Emit the divider+heading first so the live reader matches the stored form.
 	g.safeEmit(emit, "delta", map[string]any{"text": prefix})
 
 	prior := TailRunes(f.Content, g.contextRunes)
 	system, user := BuildContinueMessages(*f, prior)
 
-	text, usage, err := g.groq.Stream(ctx, system, user, MaxTokensFor(f.Length), 0.9, func(delta string) {
-		g.safeEmit(emit, "delta", map[string]any{"text": delta})
-	})
+	prompt := llm.Prompt{System: system, User: user, MaxTokens: MaxTokensFor(f.Length), Temperature: 0.9}
+	res, err := g.backends.Stream(ctx, plan, prompt, g.hooks(emit, prefix))
 	if err != nil {
 		g.safeEmit(emit, "error", map[string]any{"message": err.Error()})
 		return err
 	}
 
 	// Strip any stray leading title the model emitted; keep the body.
-	_, body := SplitTitle(text)
+	usage := res.Usage
+	_, body := SplitTitle(res.Text)
 	if body == "" {
-		body = text
+		body = res.Text
 	}
 	appended := prefix + body
 	if err := g.store.AppendPart(ctx, userID, id, appended, usage, part); err != nil {
@@ -63,7 +74,7 @@ func (g *Generator) Continue(ctx context.Context, userID, id string, emit Emit)
 	}
 	if g.log != nil {
 		g.log.Infow("fanfic continued", "user_id", userID, "fanfic_id", id, "action", "continue",
-			"canon", f.Canon, "part", part, "token_usage", usage, "status", "complete")
+			"canon", f.Canon, "part", part, "backend", res.Backend, "model", res.Model, "token_usage", usage, "status", "complete")
 	}
 	g.safeEmit(emit, "done", map[string]any{"id": f.ID, "part": part, "token_usage": usage})
 	return nil
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/llm"
 	"gorm.io/datatypes"
 )
 
@@ -19,7 +20,7 @@ type fakeContinueStore struct {
 }
 
 func (s *fakeContinueStore) Create(context.Context, *domain.Fanfic) error { return nil }
-func (s *fakeContinueStore) UpdateResult(context.Context, string, string, string, int) error {
+func (s *fakeContinueStore) UpdateResult(context.Context, string, string, string, string, string, int) error {
 	return nil
 }
 func (s *fakeContinueStore) MarkFailed(context.Context, string, string) error { return nil }
@@ -39,7 +40,7 @@ func TestContinue_AppendsSectionedPart(t *testing.T) {
 		Characters: datatypes.JSON([]byte(`[]`)), Tags: datatypes.JSON([]byte(`[]`)),
 	}}
 	g := NewGenerator(&fakeStreamer{out: "# Ignored\nследующая часть"}, store,
-		noopQuota{}, nil, "test-model", 24000, nil)
+		noopQuota{}, nil, 24000, nil)
 
 	var events []string
 	emit := func(event string, data any) error { events = append(events, event); return nil }
@@ -60,12 +61,28 @@ func TestContinue_AppendsSectionedPart(t *testing.T) {
 	}
 }
 
+func TestContinue_PrefersStoredBackend(t *testing.T) {
+	store := &fakeContinueStore{get: &domain.Fanfic{
+		ID: "f1", UserID: "u1", Status: domain.StatusComplete, Language: "en", PartCount: 1,
+		Backend: "lan", Model: "qwen2.5-7b",
+		Characters: datatypes.JSON([]byte(`[]`)), Tags: datatypes.JSON([]byte(`[]`)),
+	}}
+	streamed := &fakeStreamer{out: "more"}
+	g := NewGenerator(streamed, store, noopQuota{}, nil, 24000, nil)
+	if err := g.Continue(context.Background(), "u1", "f1", nil); err != nil {
+		t.Fatalf("continue: %v", err)
+	}
+	if streamed.planned != (llm.Target{Backend: "lan", Model: "qwen2.5-7b"}) {
+		t.Errorf("Plan got %+v; want the story's own backend/model", streamed.planned)
+	}
+}
+
 func TestContinue_RejectsNonComplete(t *testing.T) {
 	store := &fakeContinueStore{get: &domain.Fanfic{
 		ID: "f1", UserID: "u1", Status: domain.StatusGenerating,
 		Characters: datatypes.JSON([]byte(`[]`)), Tags: datatypes.JSON([]byte(`[]`)),
 	}}
-	g := NewGenerator(&fakeStreamer{}, store, noopQuota{}, nil, "m", 24000, nil)
+	g := NewGenerator(&fakeStreamer{}, store, noopQuota{}, nil, 24000, nil)
 	err := g.Continue(context.Background(), "u1", "f1", func(string, any) error { return nil })
 	if err == nil {
 		t.Fatal("expected error continuing a non-complete fanfic")
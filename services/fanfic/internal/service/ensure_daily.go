@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/ILITA-hub/animeenigma/libs/logger"
@@ -12,6 +13,7 @@ import (
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/catalog"
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/groq"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/llm"
 )
 
 const (
@@ -37,25 +39,24 @@ type EnsureResult struct {
 
 // DailyService owns the "Фанфик дня" pick + idempotent bot-generation flow.
 type DailyService struct {
-	groq      streamer
+	backends  streamer
 	repo      dailyRepo
 	meta      animeMetaFetcher
 	alerter   alert.Alerter
-	model     string
 	animePool []string
 	lang      string
 	now       func() time.Time
 	log       *logger.Logger
 }
 
-func NewDailyService(groq streamer, repo dailyRepo, meta animeMetaFetcher, alerter alert.Alerter, model string, animePool []string, lang string, now func() time.Time, log *logger.Logger) *DailyService {
+func NewDailyService(backends streamer, repo dailyRepo, meta animeMetaFetcher, alerter alert.Alerter, animePool []string, lang string, now func() time.Time, log *logger.Logger) *DailyService {
 	if now == nil {
 		now = time.Now
 	}
 	if lang == "" {
 		lang = "ru"
 	}
-	return &DailyService{groq: groq, repo: repo, meta: meta, alerter: alerter, model: model, animePool: animePool, lang: lang, now: now, log: log}
+	return &DailyService{backends: backends, repo: repo, meta: meta, alerter: alerter, animePool: animePool, lang: lang, now: now, log: log}
 }
 
 // DailyPick returns the day's fanfic (or nil). Shared by both daily handlers.
@@ -71,8 +72,9 @@ func (s *DailyService) DailyPick(ctx context.Context) (*domain.Fanfic, error) {
 // EnsureDaily generates today's bot fanfic. It runs unconditionally of user
 // fanfics: the bot is the guaranteed fallback for the day any user fanfic ages
 // out of the window (PickDaily still prefers user fanfics, so an unneeded bot
-// simply stays invisible), and the Groq call doubles as the daily key-health
-// probe — a 401/403 fires a Telegram alert. Idempotent — skips only when a bot
+// simply stays invisible), and the generation doubles as the daily key-health
+// probe — a 401/403 from any backend tried fires a Telegram alert, even when
+// a fallback backend then succeeded. Idempotent — skips only when a bot
 // fanfic was already created on the CURRENT UTC day; a bot from yesterday
 // still sitting in the eligibility window must NOT satisfy the check (it
 // expires at the next midnight rollover, which would leave the day empty).
@@ -89,29 +91,39 @@ func (s *DailyService) EnsureDaily(ctx context.Context) (EnsureResult, error) {
 		}
 	}
 
+	plan, err := s.backends.Plan("", "")
+	if err != nil {
+		return EnsureResult{}, fmt.Errorf("ensure-daily: %w", err)
+	}
 	req := s.randomRequest(ctx)
 	system, user := BuildMessages(req, "")
-	text, usage, err := s.groq.Stream(ctx, system, user, MaxTokensFor(req.Length), 0.9, func(string) {})
+	prompt := llm.Prompt{System: system, User: user, MaxTokens: MaxTokensFor(req.Length), Temperature: 0.9}
+	res, err := s.backends.Stream(ctx, plan, prompt, llm.Hooks{})
+	if se := authFailure(res, err); se != nil {
+		outcome := "FAILED"
+		if err == nil {
+			outcome = "fell back to " + res.Backend
+		}
+		msg := fmt.Sprintf("🚨 Fanfic daily generation %s: backend %s rejected the API key (status %d). Fix %s.", outcome, se.Backend, se.Code, apiKeyEnv(se.Backend))
+		_ = s.alerter.Send(ctx, msg)
+		if s.log != nil {
+			s.log.Errorw("fanfic.daily.backend_auth_failed", "backend", se.Backend, "status", se.Code)
+		}
+	}
 	if err != nil {
-		var se *groq.StatusError
-		if errors.As(err, &se) && (se.Code == http.StatusUnauthorized || se.Code == http.StatusForbidden) {
-			msg := fmt.Sprintf("🚨 Fanfic daily generation FAILED: Groq rejected the API key (status %d). Model=%s. Fix FANFIC_GROQ_API_KEY.", se.Code, s.model)
-			_ = s.alerter.Send(ctx, msg)
-			if s.log != nil {
-				s.log.Errorw("fanfic.daily.groq_auth_failed", "status", se.Code)
-			}
-		} else if s.log != nil {
-			s.log.Warnw("fanfic.daily.groq_failed", "error", err)
+		if s.log != nil {
+			s.log.Warnw("fanfic.daily.generation_failed", "error", err)
 		}
-		return EnsureResult{}, fmt.Errorf("ensure-daily: groq: %w", err)
+		return EnsureResult{}, fmt.Errorf("ensure-daily: generate: %w", err)
 	}
 
-	title, body := SplitTitle(text)
+	title, body := SplitTitle(res.Text)
 	f := &domain.Fanfic{
 		UserID:           FanficBotUserID,
 		AuthorUsername:   FanficBotUsername,
 		SpotlightCredit:  true,
 		AIGenerated:      true,
+		Origin:           domain.OriginGenerated,
 		AnimeID:          req.Anime.ID,
 		AnimeShikimoriID: req.Anime.ShikimoriID,
 		AnimeTitle:       req.Anime.Title,
@@ -124,8 +136,9 @@ func (s *DailyService) EnsureDaily(ctx context.Context) (EnsureResult, error) {
 		PartCount:        1,
 		Title:            title,
 		Content:          body,
-		Model:            s.model,
-		TokenUsage:       usage,
+		Backend:          res.Backend,
+		Model:            res.Model,
+		TokenUsage:       res.Usage,
 		Status:           domain.StatusComplete,
 	}
 	if err := s.repo.Create(ctx, f); err != nil {
@@ -134,6 +147,36 @@ func (s *DailyService) EnsureDaily(ctx context.Context) (EnsureResult, error) {
 	return EnsureResult{Generated: true, Reason: "generated", FanficID: f.ID}, nil
 }
 
+// authFailure returns the first 401/403 among a generation's failed attempts,
+// or nil. err covers a streamer that fails without reporting attempts.
+func authFailure(res llm.Result, err error) *llm.StatusError {
+	errs := make([]error, 0, len(res.Failures)+1)
+	for _, f := range res.Failures {
+		errs = append(errs, f.Err)
+	}
+	errs = append(errs, err)
+	for _, e := range errs {
+		var se *llm.StatusError
+		if errors.As(e, &se) && IsAuthStatus(se.Code) {
+			return se
+		}
+	}
+	return nil
+}
+
+// IsAuthStatus reports whether a backend status means its credentials were rejected.
+func IsAuthStatus(code int) bool {
+	return code == http.StatusUnauthorized || code == http.StatusForbidden
+}
+
+// apiKeyEnv names the env var holding backend's API key (see config.Load).
+func apiKeyEnv(backend string) string {
+	if backend == groq.Name {
+		return "FANFIC_GROQ_API_KEY"
+	}
+	return "FANFIC_BACKEND_" + strings.ToUpper(backend) + "_API_KEY"
+}
+
 // randomRequest builds deterministic-per-day random params (teen, RU) and fetches
 // anime metadata (fail-soft — generation proceeds with whatever title is available).
 func (s *DailyService) randomRequest(ctx context.Context) domain.GenerateRequest {
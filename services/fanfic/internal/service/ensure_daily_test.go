@@ -9,7 +9,7 @@ import (
 
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/catalog"
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
-	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/groq"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/llm"
 )
 
 type fakeRepo struct {
@@ -33,17 +33,27 @@ func (fakeMeta) FetchMeta(context.Context, string, string) (catalog.AnimeMeta, e
 }
 
 type fakeStream struct {
-	err   error
-	text  string
-	calls int
+	err      error
+	text     string
+	calls    int
+	failures []llm.Failure // attempts reported as failed before a success
+	winner   llm.Target    // overrides plan[0] as the succeeding target
 }
 
-func (f *fakeStream) Stream(_ context.Context, _, _ string, _ int, _ float64, on func(string)) (string, int, error) {
+func (f *fakeStream) Plan(string, string) ([]llm.Target, error) {
+	return []llm.Target{{Backend: "groq", Model: "m"}}, nil
+}
+
+func (f *fakeStream) Stream(_ context.Context, plan []llm.Target, _ llm.Prompt, _ llm.Hooks) (llm.Result, error) {
 	f.calls++
 	if f.err != nil {
-		return "", 0, f.err
+		return llm.Result{}, f.err
 	}
-	return f.text, 42, nil
+	target := plan[0]
+	if f.winner.Backend != "" {
+		target = f.winner
+	}
+	return llm.Result{Target: target, Text: f.text, Usage: 42, Failures: f.failures}, nil
 }
 
 type fakeAlerter struct{ sent []string }
@@ -55,7 +65,7 @@ func (a *fakeAlerter) Send(_ context.Context, s string) error { a.sent = append(
 var testNow = time.Unix(1700000000, 0).UTC()
 
 func newDaily(repo dailyRepo, stream streamer, al *fakeAlerter) *DailyService {
-	return NewDailyService(stream, repo, fakeMeta{}, al, "m", []string{"20"}, "ru", func() time.Time { return testNow }, nil)
+	return NewDailyService(stream, repo, fakeMeta{}, al, []string{"20"}, "ru", func() time.Time { return testNow }, nil)
 }
 
 func TestEnsureDaily_UserFanficExists_StillGeneratesDailyBot(t *testing.T) {
@@ -131,7 +141,7 @@ func TestEnsureDaily_GeneratesBotFanfic(t *testing.T) {
 func TestEnsureDaily_401_Alerts(t *testing.T) {
 	repo := &fakeRepo{}
 	al := &fakeAlerter{}
-	stream := &fakeStream{err: &groq.StatusError{Code: http.StatusUnauthorized, Body: "invalid_api_key"}}
+	stream := &fakeStream{err: &llm.StatusError{Backend: "groq", Code: http.StatusUnauthorized, Body: "invalid_api_key"}}
 	res, err := newDaily(repo, stream, al).EnsureDaily(context.Background())
 	if err == nil || res.Generated {
 		t.Fatalf("want error, res=%+v", res)
@@ -144,7 +154,7 @@ func TestEnsureDaily_401_Alerts(t *testing.T) {
 func TestEnsureDaily_403_Alerts(t *testing.T) {
 	repo := &fakeRepo{}
 	al := &fakeAlerter{}
-	stream := &fakeStream{err: &groq.StatusError{Code: http.StatusForbidden, Body: "forbidden"}}
+	stream := &fakeStream{err: &llm.StatusError{Backend: "groq", Code: http.StatusForbidden, Body: "forbidden"}}
 	res, err := newDaily(repo, stream, al).EnsureDaily(context.Background())
 	if err == nil || res.Generated {
 		t.Fatalf("want error, res=%+v", res)
@@ -154,6 +164,27 @@ func TestEnsureDaily_403_Alerts(t *testing.T) {
 	}
 }
 
+func TestEnsureDaily_AuthFailureThenFallback_AlertsAndGenerates(t *testing.T) {
+	// Groq's key is revoked but the LAN backend picked the story up: the day
+	// still gets its bot fanfic, and the dead key still pages the operator.
+	repo := &fakeRepo{}
+	al := &fakeAlerter{}
+	stream := &fakeStream{text: "# T\n\nBody", winner: llm.Target{Backend: "lan", Model: "qwen"}, failures: []llm.Failure{{
+		Target: llm.Target{Backend: "groq", Model: "m"},
+		Err:    &llm.StatusError{Backend: "groq", Code: http.StatusUnauthorized, Body: "invalid_api_key"},
+	}}}
+	res, err := newDaily(repo, stream, al).EnsureDaily(context.Background())
+	if err != nil || !res.Generated {
+		t.Fatalf("res=%+v err=%v", res, err)
+	}
+	if len(al.sent) != 1 {
+		t.Fatalf("want 1 alert for the rejected groq key, got %d", len(al.sent))
+	}
+	if repo.created.Backend != "lan" || repo.created.Model != "qwen" {
+		t.Errorf("backend/model = %q/%q; want the producing target", repo.created.Backend, repo.created.Model)
+	}
+}
+
 func TestEnsureDaily_TransientError_NoAlert(t *testing.T) {
 	repo := &fakeRepo{}
 	al := &fakeAlerter{}
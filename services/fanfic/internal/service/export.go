@@ -0,0 +1,540 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	liberrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/export"
+)
+
+const (
+	// exportRenderVersion is part of every fingerprint; bump it when the
+	// renderers' output changes so cached files are rebuilt.
+	exportRenderVersion = 1
+	// maxExportBookmarks caps how many bookmarked works one export bundles.
+	maxExportBookmarks = 200
+	// exportStaleAfter requeues renders left running this long (a worker
+	// that died mid-render).
+	exportStaleAfter = 15 * time.Minute
+	// exportPrefix is the object-storage prefix of rendered files.
+	exportPrefix = "fanfic-exports/"
+)
+
+// exportStore is the subset of *repo.ExportRepository ExportService depends on.
+type exportStore interface {
+	CreateOrGet(ctx context.Context, e *domain.Export) (*domain.Export, error)
+	Get(ctx context.Context, id string) (*domain.Export, error)
+	Requeue(ctx context.Context, id, userID string) error
+	ClaimNext(ctx context.Context) (*domain.Export, error)
+	Complete(ctx context.Context, id, storage, key string, size int64, at time.Time) error
+	Fail(ctx context.Context, id, msg string) error
+	ResetStale(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// exportSource reads the works an export renders (*repo.WorkRepository).
+type exportSource interface {
+	GetWork(ctx context.Context, id string) (*domain.Fanfic, error)
+	Chapters(ctx context.Context, workID string, publishedOnly bool) ([]domain.Chapter, error)
+	Bookmarks(ctx context.Context, userID string, limit, offset int) ([]domain.BookmarkedWork, int64, error)
+}
+
+// exportStorage stores rendered files (*storagegw.Gateway).
+type exportStorage interface {
+	Upload(ctx context.Context, prefix, path string) (string, error)
+	DownloadURL(ctx context.Context, storage, key string) (string, error)
+}
+
+// posterFetcher downloads an anime poster for the cover.
+type posterFetcher interface {
+	Fetch(ctx context.Context, url string) ([]byte, error)
+}
+
+// ExportService renders fanfics and bookmark sets into EPUB/PDF. Requests
+// only enqueue a row keyed by the content's fingerprint; Run renders queued
+// rows in the background and uploads them to object storage, where later
+// requests for unchanged content find them ready.
+type ExportService struct {
+	store   exportStore
+	source  exportSource
+	storage exportStorage
+	posters posterFetcher
+	now     func() time.Time
+	log     *logger.Logger
+}
+
+// NewExportService constructs an ExportService. posters may be nil (exports
+// go without a cover).
+func NewExportService(store exportStore, source exportSource, storage exportStorage, posters posterFetcher, now func() time.Time, log *logger.Logger) *ExportService {
+	if now == nil {
+		now = time.Now
+	}
+	return &ExportService{store: store, source: source, storage: storage, posters: posters, now: now, log: log}
+}
+
+// RequestFanfic enqueues (or returns the cached) export of one fanfic the
+// user may read.
+func (s *ExportService) RequestFanfic(ctx context.Context, userID, fanficID, format string) (*domain.Export, error) {
+	f, err := s.readable(ctx, userID, fanficID)
+	if err != nil {
+		return nil, err
+	}
+	book, poster, err := s.fanficBook(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return s.request(ctx, &domain.Export{UserID: userID, Kind: domain.ExportKindFanfic, FanficID: f.ID, Format: format}, book, poster)
+}
+
+// RequestBookmarks enqueues (or returns the cached) export of the user's
+// bookmarked works, newest bookmark first.
+func (s *ExportService) RequestBookmarks(ctx context.Context, userID, format string) (*domain.Export, error) {
+	book, err := s.bookmarksBook(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.request(ctx, &domain.Export{UserID: userID, Kind: domain.ExportKindBookmarks, Format: format}, book, "")
+}
+
+func (s *ExportService) request(ctx context.Context, e *domain.Export, book *export.Book, poster string) (*domain.Export, error) {
+	// Bookmark sets are private: the requester is part of the key so two
+	// readers with the same bookmarks never share a row.
+	owner := ""
+	if e.Kind == domain.ExportKindBookmarks {
+		owner = e.UserID
+	}
+	fp, err := fingerprint(e.Kind, e.Format, owner, poster, book)
+	if err != nil {
+		return nil, liberrors.Wrap(err, liberrors.CodeInternal, "fingerprint export")
+	}
+	e.Fingerprint = fp
+	e.Title = book.Title
+	e.Status = domain.ExportPending
+	got, err := s.store.CreateOrGet(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+	if got.Status == domain.ExportFailed {
+		if err := s.store.Requeue(ctx, got.ID, e.UserID); err != nil {
+			return nil, err
+		}
+		got.Status, got.ErrorMsg, got.UserID = domain.ExportPending, "", e.UserID
+	}
+	return got, nil
+}
+
+// Get returns an export the user may see: bookmark exports belong to their
+// requester, fanfic exports to anyone who may still read the fanfic.
+func (s *ExportService) Get(ctx context.Context, userID, id string) (*domain.Export, error) {
+	e, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch e.Kind {
+	case domain.ExportKindBookmarks:
+		if e.UserID != userID {
+			return nil, liberrors.NotFound("export")
+		}
+	default:
+		if _, err := s.readable(ctx, userID, e.FanficID); err != nil {
+			return nil, liberrors.NotFound("export")
+		}
+	}
+	return e, nil
+}
+
+// DownloadURL returns a ready export with a presigned GET URL of its file.
+// The URL is internal to the storage network; the handler streams it.
+func (s *ExportService) DownloadURL(ctx context.Context, userID, id string) (*domain.Export, string, error) {
+	e, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if e.Status != domain.ExportReady {
+		return nil, "", liberrors.New(liberrors.CodeConflict, "export is not ready")
+	}
+	url, err := s.storage.DownloadURL(ctx, e.Storage, e.ObjectKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return e, url, nil
+}
+
+// Run renders queued exports every interval until ctx is done.
+func (s *ExportService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.RenderPending(ctx); err != nil {
+				if s.log != nil {
+					s.log.Warnw("fanfic export rendering failed", "error", err)
+				}
+			} else if n > 0 && s.log != nil {
+				s.log.Infow("rendered fanfic exports", "count", n)
+			}
+		}
+	}
+}
+
+// RenderPending drains the export queue, returning how many exports were
+// rendered. A failing export is marked failed and does not stop the drain.
+func (s *ExportService) RenderPending(ctx context.Context) (int, error) {
+	if n, err := s.store.ResetStale(ctx, s.now().Add(-exportStaleAfter)); err != nil {
+		return 0, err
+	} else if n > 0 && s.log != nil {
+		s.log.Warnw("requeued stale fanfic exports", "count", n)
+	}
+	rendered := 0
+	for ctx.Err() == nil {
+		e, err := s.store.ClaimNext(ctx)
+		if err != nil {
+			return rendered, err
+		}
+		if e == nil {
+			break
+		}
+		if err := s.render(ctx, e); err != nil {
+			if s.log != nil {
+				s.log.Warnw("fanfic export failed", "export_id", e.ID, "kind", e.Kind, "format", e.Format, "error", err)
+			}
+			if ferr := s.store.Fail(ctx, e.ID, err.Error()); ferr != nil {
+				return rendered, ferr
+			}
+			continue
+		}
+		rendered++
+	}
+	return rendered, nil
+}
+
+// render rebuilds e's book from the current rows, renders it to a temp file
+// and uploads it under exportPrefix/{fingerprint}/.
+func (s *ExportService) render(ctx context.Context, e *domain.Export) error {
+	var book *export.Book
+	var poster string
+	var err error
+	switch e.Kind {
+	case domain.ExportKindBookmarks:
+		book, err = s.bookmarksBook(ctx, e.UserID)
+	default:
+		var f *domain.Fanfic
+		if f, err = s.readable(ctx, e.UserID, e.FanficID); err == nil {
+			book, poster, err = s.fanficBook(ctx, f)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if poster != "" && s.posters != nil {
+		book.Cover = s.cover(ctx, poster)
+	}
+	book.Modified = s.now()
+
+	dir, err := os.MkdirTemp("", "fanfic-export-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "book."+e.Format)
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if e.Format == domain.ExportPDF {
+		err = export.WritePDF(out, book)
+	} else {
+		err = export.WriteEPUB(out, book)
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("render %s: %w", e.Format, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	prefix := exportPrefix + e.Fingerprint + "/"
+	storage, err := s.storage.Upload(ctx, prefix, path)
+	if err != nil {
+		return fmt.Errorf("upload: %w", err)
+	}
+	return s.store.Complete(ctx, e.ID, storage, prefix+filepath.Base(path), info.Size(), s.now())
+}
+
+// cover fetches and normalizes a poster; any failure just drops the cover.
+func (s *ExportService) cover(ctx context.Context, url string) *export.Image {
+	data, err := s.posters.Fetch(ctx, url)
+	if err == nil {
+		var img *export.Image
+		if img, err = export.NormalizeCover(data); err == nil {
+			return img
+		}
+	}
+	if s.log != nil {
+		s.log.Warnw("fanfic export cover skipped", "poster", url, "error", err)
+	}
+	return nil
+}
+
+// readable returns a fanfic the user may export: their own, or a public one.
+// A generated fanfic must have finished generating.
+func (s *ExportService) readable(ctx context.Context, userID, id string) (*domain.Fanfic, error) {
+	return readableFanfic(ctx, s.source, userID, id)
+}
+
+// readableFanfic implements ExportService.readable over any work source;
+// narrations share it.
+func readableFanfic(ctx context.Context, source interface {
+	GetWork(ctx context.Context, id string) (*domain.Fanfic, error)
+}, userID, id string) (*domain.Fanfic, error) {
+	f, err := source.GetWork(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if f.UserID != userID && !f.IsPublic() {
+		return nil, liberrors.NotFound("fanfic")
+	}
+	if f.Origin != domain.OriginHuman && f.Status != domain.StatusComplete {
+		return nil, liberrors.New(liberrors.CodeConflict, "fanfic is not complete")
+	}
+	return f, nil
+}
+
+func (s *ExportService) fanficBook(ctx context.Context, f *domain.Fanfic) (*export.Book, string, error) {
+	w, err := s.work(ctx, f)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(w.Parts) == 0 {
+		return nil, "", liberrors.New(liberrors.CodeConflict, "fanfic has no published chapters")
+	}
+	return &export.Book{ID: f.ID, Title: f.Title, Language: exportLanguage(f.Language), Works: []export.Work{w}}, f.AnimePoster, nil
+}
+
+// bookmarksBook bundles the user's bookmarked works; the book's language is
+// the newest bookmarked work's.
+func (s *ExportService) bookmarksBook(ctx context.Context, userID string) (*export.Book, error) {
+	items, _, err := s.source.Bookmarks(ctx, userID, maxExportBookmarks, 0)
+	if err != nil {
+		return nil, err
+	}
+	var works []export.Work
+	language := ""
+	for i := range items {
+		f := &items[i].Fanfic
+		if f.Origin != domain.OriginHuman && f.Status != domain.StatusComplete {
+			continue
+		}
+		w, err := s.work(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+		if len(w.Parts) > 0 {
+			works = append(works, w)
+		}
+		if language == "" {
+			language = f.Language
+		}
+	}
+	if len(works) == 0 {
+		return nil, liberrors.New(liberrors.CodeConflict, "no bookmarked works to export")
+	}
+	language = exportLanguage(language)
+	title := "Bookmarks"
+	if language == "ru" {
+		title = "Закладки"
+	}
+	return &export.Book{ID: "bookmarks-" + userID, Title: title, Language: language, Works: works}, nil
+}
+
+// work converts a fanfic into an export.Work: human works by their published
+// chapters, generated ones by splitting Content on the continue divider.
+func (s *ExportService) work(ctx context.Context, f *domain.Fanfic) (export.Work, error) {
+	lang := exportLanguage(f.Language)
+	w := export.Work{
+		Title:   f.Title,
+		Anime:   f.AnimeTitle,
+		Rating:  f.Rating,
+		Summary: f.Summary,
+		Tags:    exportTags(f.Tags, lang),
+	}
+	var chars []domain.CharacterRef
+	_ = json.Unmarshal(f.Characters, &chars)
+	for _, c := range chars {
+		w.Characters = append(w.Characters, c.Name)
+	}
+
+	if f.Origin == domain.OriginHuman {
+		w.Authors = []string{f.AuthorUsername}
+		chapters, err := s.source.Chapters(ctx, f.ID, true)
+		if err != nil {
+			return w, err
+		}
+		for i, c := range chapters {
+			title := c.Title
+			if title == "" {
+				title = fmt.Sprintf("%s %d", chapterWord(lang), i+1)
+			}
+			w.Parts = append(w.Parts, export.Part{Title: title, Text: c.Content})
+		}
+		return w, nil
+	}
+
+	w.Authors = []string{generatedLabel(lang)}
+	w.Parts = SplitParts(f.Content, f.Title, lang)
+	return w, nil
+}
+
+// partDividerRe matches the divider + «Часть N» heading Continue inserts
+// between parts.
+var partDividerRe = regexp.MustCompile(`\n+---\n+## (?:Часть|Part) (\d+)\n+`)
+
+// SplitParts splits a generated fanfic's Content into its parts. A
+// single-part fanfic is one part titled after the fanfic.
+func SplitParts(content, title, language string) []export.Part {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil
+	}
+	locs := partDividerRe.FindAllStringSubmatchIndex(content, -1)
+	if len(locs) == 0 {
+		return []export.Part{{Title: title, Text: content}}
+	}
+	word := headingWord(language)
+	parts := []export.Part{{Title: word + " 1", Text: strings.TrimSpace(content[:locs[0][0]])}}
+	for i, loc := range locs {
+		end := len(content)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		parts = append(parts, export.Part{
+			Title: word + " " + content[loc[2]:loc[3]],
+			Text:  strings.TrimSpace(content[loc[1]:end]),
+		})
+	}
+	return parts
+}
+
+// exportTags decodes a fanfic's tags, labelling curated ones in language.
+func exportTags(raw []byte, language string) []string {
+	var tags []string
+	_ = json.Unmarshal(raw, &tags)
+	for i, t := range tags {
+		for _, c := range domain.CuratedTags {
+			if t == c.Slug {
+				tags[i] = c.EN
+				if language == "ru" {
+					tags[i] = c.RU
+				}
+			}
+		}
+	}
+	return tags
+}
+
+// exportLanguage is the book language; Japanese works keep "ja" (their
+// labels fall back to English).
+func exportLanguage(language string) string {
+	if language == "en" || language == "ja" {
+		return language
+	}
+	return "ru"
+}
+
+func chapterWord(language string) string {
+	if language == "ru" {
+		return "Глава"
+	}
+	return "Chapter"
+}
+
+func generatedLabel(language string) string {
+	if language == "ru" {
+		return "Сгенерировано ИИ"
+	}
+	return "AI-generated"
+}
+
+// fingerprint hashes everything that shapes the rendered file. The cover is
+// represented by its poster URL (it is fetched at render time) and Modified
+// is left out, so unchanged content always maps to the same cached row.
+func fingerprint(kind, format, owner, poster string, b *export.Book) (string, error) {
+	data, err := json.Marshal(struct {
+		Version int
+		Kind    string
+		Format  string
+		Owner   string
+		Poster  string
+		ID      string
+		Title   string
+		Lang    string
+		Works   []export.Work
+	}{exportRenderVersion, kind, format, owner, poster, b.ID, b.Title, b.Language, b.Works})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// PosterFetcher downloads cover images over HTTP, capped at maxBytes.
+type PosterFetcher struct {
+	http     *http.Client
+	maxBytes int64
+}
+
+// NewPosterFetcher constructs a PosterFetcher with a per-request timeout.
+func NewPosterFetcher(timeout time.Duration, maxBytes int64) *PosterFetcher {
+	return &PosterFetcher{http: &http.Client{Timeout: timeout}, maxBytes: maxBytes}
+}
+
+// Fetch GETs url. Only absolute http(s) URLs are fetched.
+func (p *PosterFetcher) Fetch(ctx context.Context, url string) ([]byte, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("poster url %q is not absolute", url)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("poster status %d", resp.StatusCode)
+	}
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.LimitReader(resp.Body, p.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if n > p.maxBytes {
+		return nil, fmt.Errorf("poster larger than %d bytes", p.maxBytes)
+	}
+	return buf.Bytes(), nil
+}
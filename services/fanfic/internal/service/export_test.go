@@ -0,0 +1,213 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	liberrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/repo"
+	"gorm.io/datatypes"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// fakeExportStorage keeps uploaded files in memory.
+type fakeExportStorage struct {
+	files   map[string][]byte
+	uploads int
+	err     error
+}
+
+func (s *fakeExportStorage) Upload(_ context.Context, prefix, path string) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	s.uploads++
+	s.files[prefix+filepath.Base(path)] = data
+	return "minio", nil
+}
+
+func (s *fakeExportStorage) DownloadURL(_ context.Context, storage, key string) (string, error) {
+	if _, ok := s.files[key]; !ok {
+		return "", liberrors.NotFound("export file")
+	}
+	return "http://" + storage + "/" + key, nil
+}
+
+// fakePosters serves a tiny PNG for every URL.
+type fakePosters struct{ calls int }
+
+func (p *fakePosters) Fetch(context.Context, string) ([]byte, error) {
+	p.calls++
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 3)))
+	return buf.Bytes(), nil
+}
+
+func newTestExportService(t *testing.T) (*ExportService, *gorm.DB, *fakeExportStorage, *fakePosters) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.Fanfic{}, &domain.Chapter{}, &domain.Bookmark{}, &domain.Export{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+	storage := &fakeExportStorage{files: map[string][]byte{}}
+	posters := &fakePosters{}
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewExportService(repo.NewExportRepository(db), repo.NewWorkRepository(db), storage, posters, func() time.Time { return now }, nil)
+	return svc, db, storage, posters
+}
+
+func createGenerated(t *testing.T, db *gorm.DB, userID, content string) *domain.Fanfic {
+	t.Helper()
+	f := &domain.Fanfic{
+		UserID: userID, Origin: domain.OriginGenerated, Status: domain.StatusComplete,
+		AnimeTitle: "Frieren", AnimePoster: "https://img/poster.webp", Title: "Летний фестиваль",
+		Language: "ru", Rating: "teen", Content: content, PartCount: 2,
+		Characters: datatypes.JSON(`[{"id":"1","name":"Fern"}]`), Tags: datatypes.JSON(`["slow-burn","свои"]`),
+	}
+	if err := db.Omit("anime_id").Create(f).Error; err != nil {
+		t.Fatalf("create fanfic: %v", err)
+	}
+	return f
+}
+
+func TestExportService_RendersOnceAndServesCache(t *testing.T) {
+	svc, db, storage, posters := newTestExportService(t)
+	ctx := context.Background()
+	f := createGenerated(t, db, "owner", "Начало.\n\n---\n\n## Часть 2\n\nПродолжение.")
+
+	if _, err := svc.RequestFanfic(ctx, "stranger", f.ID, domain.ExportEPUB); !isCode(err, liberrors.CodeNotFound) {
+		t.Fatalf("stranger request on a private fanfic = %v; want NotFound", err)
+	}
+	e, err := svc.RequestFanfic(ctx, "owner", f.ID, domain.ExportEPUB)
+	if err != nil || e.Status != domain.ExportPending {
+		t.Fatalf("RequestFanfic = %+v, %v; want pending", e, err)
+	}
+	if _, _, err := svc.DownloadURL(ctx, "owner", e.ID); !isCode(err, liberrors.CodeConflict) {
+		t.Errorf("download before render = %v; want Conflict", err)
+	}
+
+	if n, err := svc.RenderPending(ctx); err != nil || n != 1 {
+		t.Fatalf("RenderPending = %d, %v", n, err)
+	}
+	ready, err := svc.Get(ctx, "owner", e.ID)
+	if err != nil || ready.Status != domain.ExportReady || ready.Storage != "minio" || ready.Size == 0 {
+		t.Fatalf("after render = %+v, %v", ready, err)
+	}
+	if !strings.HasPrefix(ready.ObjectKey, "fanfic-exports/") || !strings.HasSuffix(ready.ObjectKey, "/book.epub") {
+		t.Errorf("object key = %q", ready.ObjectKey)
+	}
+	if posters.calls != 1 {
+		t.Errorf("poster fetched %d times; want once", posters.calls)
+	}
+	if _, err := svc.Get(ctx, "stranger", e.ID); !isCode(err, liberrors.CodeNotFound) {
+		t.Errorf("stranger Get = %v; want NotFound", err)
+	}
+
+	again, err := svc.RequestFanfic(ctx, "owner", f.ID, domain.ExportEPUB)
+	if err != nil || again.ID != e.ID || again.Status != domain.ExportReady {
+		t.Fatalf("repeat request = %+v, %v; want the cached ready row", again, err)
+	}
+	if n, _ := svc.RenderPending(ctx); n != 0 || storage.uploads != 1 {
+		t.Errorf("cached export re-rendered (%d renders, %d uploads)", n, storage.uploads)
+	}
+	if _, url, err := svc.DownloadURL(ctx, "owner", e.ID); err != nil || url != "http://minio/"+ready.ObjectKey {
+		t.Errorf("DownloadURL = %q, %v", url, err)
+	}
+
+	pdf, _ := svc.RequestFanfic(ctx, "owner", f.ID, domain.ExportPDF)
+	if pdf.ID == e.ID {
+		t.Error("PDF shares the EPUB row")
+	}
+	db.Model(f).Update("content", "Новый текст.")
+	changed, _ := svc.RequestFanfic(ctx, "owner", f.ID, domain.ExportEPUB)
+	if changed.ID == e.ID {
+		t.Error("edited content served from the stale cache")
+	}
+}
+
+func TestExportService_FailureRequeuesOnNextRequest(t *testing.T) {
+	svc, db, storage, _ := newTestExportService(t)
+	ctx := context.Background()
+	f := createGenerated(t, db, "owner", "Текст.")
+
+	e, _ := svc.RequestFanfic(ctx, "owner", f.ID, domain.ExportPDF)
+	storage.err = errors.New("storage down")
+	if n, err := svc.RenderPending(ctx); err != nil || n != 0 {
+		t.Fatalf("RenderPending = %d, %v", n, err)
+	}
+	failed, _ := svc.Get(ctx, "owner", e.ID)
+	if failed.Status != domain.ExportFailed || !strings.Contains(failed.ErrorMsg, "storage down") {
+		t.Fatalf("failed row = %+v", failed)
+	}
+
+	storage.err = nil
+	retry, err := svc.RequestFanfic(ctx, "owner", f.ID, domain.ExportPDF)
+	if err != nil || retry.ID != e.ID || retry.Status != domain.ExportPending {
+		t.Fatalf("retry = %+v, %v; want the row requeued", retry, err)
+	}
+	if n, _ := svc.RenderPending(ctx); n != 1 {
+		t.Errorf("requeued export not rendered")
+	}
+}
+
+func TestExportService_BookmarksArePrivateToRequester(t *testing.T) {
+	svc, db, _, _ := newTestExportService(t)
+	ctx := context.Background()
+
+	work := &domain.Fanfic{UserID: "author", Origin: domain.OriginHuman, Status: domain.StatusPublished,
+		AuthorUsername: "writer", AnimeTitle: "Frieren", Title: "Тихий вечер", Language: "ru", Tags: datatypes.JSON(`[]`)}
+	db.Omit("anime_id").Create(work)
+	db.Create(&domain.Chapter{FanficID: work.ID, Position: 1, Content: "Опубликовано.", Status: domain.StatusPublished})
+	db.Create(&domain.Chapter{FanficID: work.ID, Position: 2, Content: "Черновик.", Status: domain.StatusDraft})
+	db.Create(&domain.Bookmark{UserID: "reader", FanficID: work.ID})
+
+	if _, err := svc.RequestBookmarks(ctx, "nobody", domain.ExportEPUB); !isCode(err, liberrors.CodeConflict) {
+		t.Errorf("empty bookmarks = %v; want Conflict", err)
+	}
+	e, err := svc.RequestBookmarks(ctx, "reader", domain.ExportEPUB)
+	if err != nil || e.Kind != domain.ExportKindBookmarks || e.Title != "Закладки" {
+		t.Fatalf("RequestBookmarks = %+v, %v", e, err)
+	}
+	book, err := svc.bookmarksBook(ctx, "reader")
+	if err != nil || len(book.Works) != 1 || len(book.Works[0].Parts) != 1 || book.Works[0].Authors[0] != "writer" {
+		t.Fatalf("bookmarks book = %+v, %v; want one work with its published chapter", book, err)
+	}
+	if book.Works[0].Parts[0].Title != "Глава 1" {
+		t.Errorf("untitled chapter = %q", book.Works[0].Parts[0].Title)
+	}
+	if _, err := svc.Get(ctx, "author", e.ID); !isCode(err, liberrors.CodeNotFound) {
+		t.Errorf("another user's Get = %v; want NotFound", err)
+	}
+}
+
+func TestSplitParts(t *testing.T) {
+	parts := SplitParts("Начало.\n\n---\n\n## Часть 2\n\nСередина.\n\n---\n\n## Часть 3\n\nКонец.", "T", "ru")
+	if len(parts) != 3 {
+		t.Fatalf("parts = %+v", parts)
+	}
+	for i, want := range []struct{ title, text string }{{"Часть 1", "Начало."}, {"Часть 2", "Середина."}, {"Часть 3", "Конец."}} {
+		if parts[i].Title != want.title || parts[i].Text != want.text {
+			t.Errorf("part %d = %+v; want %+v", i, parts[i], want)
+		}
+	}
+	if single := SplitParts("Один.\n\n---\n\nсцена", "Title", "en"); len(single) != 1 || single[0].Title != "Title" {
+		t.Errorf("single part = %+v; a bare scene break is not a part divider", single)
+	}
+}
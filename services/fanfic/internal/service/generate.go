@@ -6,6 +6,7 @@ import (
 
 	"github.com/ILITA-hub/animeenigma/libs/logger"
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/llm"
 	"gorm.io/datatypes"
 )
 
@@ -13,13 +14,15 @@ import (
 // logged and ignored — server-side accumulation + persistence continue.
 type Emit func(event string, data any) error
 
+// streamer routes a generation across the configured backends (*llm.Router).
 type streamer interface {
-	Stream(ctx context.Context, system, user string, maxTokens int, temperature float64, onDelta func(string)) (string, int, error)
+	Plan(backend, model string) ([]llm.Target, error)
+	Stream(ctx context.Context, plan []llm.Target, p llm.Prompt, hooks llm.Hooks) (llm.Result, error)
 }
 
 type fanficStore interface {
 	Create(ctx context.Context, f *domain.Fanfic) error
-	UpdateResult(ctx context.Context, id, title, content string, usage int) error
+	UpdateResult(ctx context.Context, id, title, content, backend, model string, usage int) error
 	MarkFailed(ctx context.Context, id, msg string) error
 	Get(ctx context.Context, userID, id string) (*domain.Fanfic, error)
 	AppendPart(ctx context.Context, userID, id, appended string, addedUsage, newPartCount int) error
@@ -36,23 +39,32 @@ type synopsisFetcher interface {
 }
 
 type Generator struct {
-	groq         streamer
+	backends     streamer
 	store        fanficStore
 	quota        quota
 	catalog      synopsisFetcher
-	model        string
 	contextRunes int
 	log          *logger.Logger
 }
 
-func NewGenerator(groq streamer, store fanficStore, quota quota, catalog synopsisFetcher, model string, contextRunes int, log *logger.Logger) *Generator {
+func NewGenerator(backends streamer, store fanficStore, quota quota, catalog synopsisFetcher, contextRunes int, log *logger.Logger) *Generator {
 	if contextRunes <= 0 {
 		contextRunes = 24000
 	}
-	return &Generator{groq: groq, store: store, quota: quota, catalog: catalog, model: model, contextRunes: contextRunes, log: log}
+	return &Generator{backends: backends, store: store, quota: quota, catalog: catalog, contextRunes: contextRunes, log: log}
 }
 
+// Generate streams a new fanfic to emit while its row sits in
+// StatusGenerating. The "meta" event names the planned backend/model; a
+// "fallback" event announces a switch to the next backend, and when its
+// reset flag is set the client must discard the text streamed so far.
 func (g *Generator) Generate(ctx context.Context, userID, username string, req domain.GenerateRequest, emit Emit) error {
+	plan, err := g.backends.Plan(req.Backend, req.Model)
+	if err != nil {
+		g.safeEmit(emit, "error", map[string]any{"message": err.Error()})
+		return err
+	}
+
 	release, err := g.quota.Acquire(ctx, userID)
 	if err != nil {
 		g.safeEmit(emit, "error", map[string]any{"message": err.Error()})
@@ -78,16 +90,18 @@ func (g *Generator) Generate(ctx context.Context, userID, username string, req d
 		AuthorUsername:   username,
 		SpotlightCredit:  req.SpotlightCredit,
 		AIGenerated:      false, // explicit: this is the user path, mirrors bot rows setting it true
+		Origin:           domain.OriginGenerated,
 		Prompt:           req.Prompt,
 		Canon:            req.Canon,
 		PartCount:        1,
-		Model:            g.model,
+		Backend:          plan[0].Backend,
+		Model:            plan[0].Model,
 		Status:           domain.StatusGenerating,
 	}
 	if err := g.store.Create(ctx, f); err != nil {
 		return err
 	}
-	g.safeEmit(emit, "meta", map[string]any{"id": f.ID, "model": g.model})
+	g.safeEmit(emit, "meta", map[string]any{"id": f.ID, "backend": f.Backend, "model": f.Model})
 
 	synopsis := ""
 	if req.Canon && g.catalog != nil {
@@ -101,25 +115,47 @@ func (g *Generator) Generate(ctx context.Context, userID, username string, req d
 	}
 
 	system, user := BuildMessages(req, synopsis)
-	text, usage, err := g.groq.Stream(ctx, system, user, MaxTokensFor(req.Length), 0.9, func(delta string) {
-		g.safeEmit(emit, "delta", map[string]any{"text": delta})
-	})
+	prompt := llm.Prompt{System: system, User: user, MaxTokens: MaxTokensFor(req.Length), Temperature: 0.9}
+	res, err := g.backends.Stream(ctx, plan, prompt, g.hooks(emit, ""))
 	if err != nil {
 		_ = g.store.MarkFailed(ctx, f.ID, err.Error())
 		g.safeEmit(emit, "error", map[string]any{"message": err.Error()})
 		return err
 	}
 
-	title, body := SplitTitle(text)
-	if err := g.store.UpdateResult(ctx, f.ID, title, body, usage); err != nil {
+	title, body := SplitTitle(res.Text)
+	if err := g.store.UpdateResult(ctx, f.ID, title, body, res.Backend, res.Model, res.Usage); err != nil {
 		if g.log != nil {
 			g.log.Errorw("failed to persist fanfic result", "id", f.ID, "error", err)
 		}
 	}
-	g.safeEmit(emit, "done", map[string]any{"id": f.ID, "title": title, "token_usage": usage})
+	g.safeEmit(emit, "done", map[string]any{"id": f.ID, "title": title, "backend": res.Backend, "model": res.Model, "token_usage": res.Usage})
 	return nil
 }
 
+// hooks relays a routed stream to the client: each delta as a "delta" event,
+// each backend switch as a "fallback" event. After a reset the client has
+// dropped everything since "meta", so lead — any text the live reader must
+// start with, like Continue's part heading — is streamed again.
+func (g *Generator) hooks(emit Emit, lead string) llm.Hooks {
+	return llm.Hooks{
+		Delta: func(delta string) {
+			g.safeEmit(emit, "delta", map[string]any{"text": delta})
+		},
+		Fallback: func(failed llm.Failure, next llm.Target) {
+			g.safeEmit(emit, "fallback", map[string]any{
+				"failed":  failed.Backend,
+				"backend": next.Backend,
+				"model":   next.Model,
+				"reset":   failed.Partial,
+			})
+			if failed.Partial && lead != "" {
+				g.safeEmit(emit, "delta", map[string]any{"text": lead})
+			}
+		},
+	}
+}
+
 func (g *Generator) safeEmit(emit Emit, event string, data any) {
 	if emit == nil {
 		return
@@ -6,32 +6,53 @@ import (
 	"testing"
 
 	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/llm"
 )
 
 type fakeStreamer struct {
 	out   string
 	err   error
 	calls int
+
+	// planned records the last Plan preference; planErr fails Plan.
+	planned llm.Target
+	planErr error
+}
+
+func (f *fakeStreamer) Plan(backend, model string) ([]llm.Target, error) {
+	f.planned = llm.Target{Backend: backend, Model: model}
+	if f.planErr != nil {
+		return nil, f.planErr
+	}
+	if backend == "" {
+		backend = "groq"
+	}
+	if model == "" {
+		model = "llama-3.1-8b-instant"
+	}
+	return []llm.Target{{Backend: backend, Model: model}}, nil
 }
 
-func (f *fakeStreamer) Stream(_ context.Context, _, _ string, _ int, _ float64, onDelta func(string)) (string, int, error) {
+func (f *fakeStreamer) Stream(_ context.Context, plan []llm.Target, _ llm.Prompt, hooks llm.Hooks) (llm.Result, error) {
 	f.calls++
 	if f.err != nil {
 		// A real streamer may still have emitted partial deltas before failing;
 		// exercise that by emitting once before returning the error.
 		if f.out != "" {
-			onDelta(f.out)
+			hooks.Delta(f.out)
 		}
-		return f.out, 0, f.err
+		return llm.Result{}, f.err
 	}
-	onDelta(f.out)
-	return f.out, 55, nil
+	hooks.Delta(f.out)
+	return llm.Result{Target: plan[0], Text: f.out, Usage: 55}, nil
 }
 
 type fakeStore struct {
 	created   *domain.Fanfic
 	title     string
 	body      string
+	backend   string
+	model     string
 	usage     int
 	updateErr error
 	failedID  string
@@ -43,8 +64,8 @@ func (s *fakeStore) Create(_ context.Context, f *domain.Fanfic) error {
 	s.created = f
 	return nil
 }
-func (s *fakeStore) UpdateResult(_ context.Context, _, title, content string, usage int) error {
-	s.title, s.body, s.usage = title, content, usage
+func (s *fakeStore) UpdateResult(_ context.Context, _, title, content, backend, model string, usage int) error {
+	s.title, s.body, s.backend, s.model, s.usage = title, content, backend, model, usage
 	return s.updateErr
 }
 func (s *fakeStore) MarkFailed(_ context.Context, id, msg string) error {
@@ -76,7 +97,7 @@ func (q *stubQuota) Acquire(_ context.Context, _ string) (func(), error) {
 
 func TestGenerate_StreamsPersistsAndSplitsTitle(t *testing.T) {
 	store := &fakeStore{}
-	g := NewGenerator(&fakeStreamer{out: "# Тяжесть столетий\n\nКогда солнце..."}, store, noopQuota{}, nil, "llama-3.1-8b-instant", 24000, nil)
+	g := NewGenerator(&fakeStreamer{out: "# Тяжесть столетий\n\nКогда солнце..."}, store, noopQuota{}, nil, 24000, nil)
 
 	var events []string
 	emit := func(event string, _ any) error { events = append(events, event); return nil }
@@ -119,7 +140,7 @@ func TestGenerate_StreamsPersistsAndSplitsTitle(t *testing.T) {
 // scalar anime fields already covered above.
 func TestGenerate_SnapshotsCharactersAndTags(t *testing.T) {
 	store := &fakeStore{}
-	g := NewGenerator(&fakeStreamer{out: "# T\n\nbody"}, store, noopQuota{}, nil, "llama-3.1-8b-instant", 24000, nil)
+	g := NewGenerator(&fakeStreamer{out: "# T\n\nbody"}, store, noopQuota{}, nil, 24000, nil)
 
 	req := domain.GenerateRequest{
 		Anime:      domain.AnimeRef{Title: "Frieren", ShikimoriID: "52991"},
@@ -161,7 +182,7 @@ func contains(haystack, needle string) bool {
 func TestGenerate_StreamerErrorMarksFailedAndEmitsError(t *testing.T) {
 	store := &fakeStore{}
 	streamErr := errors.New("groq status 503: upstream overloaded")
-	g := NewGenerator(&fakeStreamer{err: streamErr}, store, noopQuota{}, nil, "llama-3.1-8b-instant", 24000, nil)
+	g := NewGenerator(&fakeStreamer{err: streamErr}, store, noopQuota{}, nil, 24000, nil)
 
 	var events []string
 	emit := func(event string, _ any) error { events = append(events, event); return nil }
@@ -193,7 +214,7 @@ func TestGenerate_StreamerErrorMarksFailedAndEmitsError(t *testing.T) {
 // returns nil.
 func TestGenerate_EmitErrorDoesNotAbortPersistence(t *testing.T) {
 	store := &fakeStore{}
-	g := NewGenerator(&fakeStreamer{out: "# Title\n\nBody text"}, store, noopQuota{}, nil, "llama-3.1-8b-instant", 24000, nil)
+	g := NewGenerator(&fakeStreamer{out: "# Title\n\nBody text"}, store, noopQuota{}, nil, 24000, nil)
 
 	emitCalls := 0
 	emit := func(event string, _ any) error {
@@ -223,7 +244,7 @@ func TestGenerate_EmitErrorDoesNotAbortPersistence(t *testing.T) {
 func TestGenerate_QuotaErrorAbortsBeforePersistence(t *testing.T) {
 	store := &fakeStore{}
 	q := &stubQuota{err: ErrBusy}
-	g := NewGenerator(&fakeStreamer{out: "# T\n\nbody"}, store, q, nil, "llama-3.1-8b-instant", 24000, nil)
+	g := NewGenerator(&fakeStreamer{out: "# T\n\nbody"}, store, q, nil, 24000, nil)
 
 	req := domain.GenerateRequest{Anime: domain.AnimeRef{Title: "Frieren"}, Length: "oneshot", POV: "third", Rating: "mature", Language: "ru"}
 	if err := g.Generate(context.Background(), "user-1", "arisu42", req, nil); !errors.Is(err, ErrBusy) {
@@ -246,7 +267,7 @@ func TestGenerate_QuotaErrorEmitsErrorEvent(t *testing.T) {
 	store := &fakeStore{}
 	streamed := &fakeStreamer{out: "# T\n\nbody"}
 	q := &stubQuota{err: ErrQuotaExceeded}
-	g := NewGenerator(streamed, store, q, nil, "llama-3.1-8b-instant", 24000, nil)
+	g := NewGenerator(streamed, store, q, nil, 24000, nil)
 
 	var events []string
 	var payloads []any
@@ -278,3 +299,55 @@ func TestGenerate_QuotaErrorEmitsErrorEvent(t *testing.T) {
 		t.Errorf("expected streamer not to be called on quota rejection, got %d calls", streamed.calls)
 	}
 }
+
+// TestGenerate_HonoursBackendChoice asserts the request's backend/model reach
+// the router's Plan and that the producing target lands on the row.
+func TestGenerate_HonoursBackendChoice(t *testing.T) {
+	store := &fakeStore{}
+	streamed := &fakeStreamer{out: "# T\n\nbody"}
+	g := NewGenerator(streamed, store, noopQuota{}, nil, 24000, nil)
+
+	var meta map[string]any
+	emit := func(event string, data any) error {
+		if event == "meta" {
+			meta = data.(map[string]any)
+		}
+		return nil
+	}
+	req := domain.GenerateRequest{Anime: domain.AnimeRef{Title: "Frieren"}, Length: "oneshot", POV: "third", Rating: "teen", Language: "ru",
+		Backend: "lan", Model: "qwen2.5-7b"}
+	if err := g.Generate(context.Background(), "user-1", "arisu42", req, emit); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if streamed.planned != (llm.Target{Backend: "lan", Model: "qwen2.5-7b"}) {
+		t.Errorf("Plan got %+v", streamed.planned)
+	}
+	if meta["backend"] != "lan" || meta["model"] != "qwen2.5-7b" {
+		t.Errorf("meta = %v", meta)
+	}
+	if store.created.Status != domain.StatusGenerating || store.backend != "lan" || store.model != "qwen2.5-7b" {
+		t.Errorf("row: status %q backend %q model %q", store.created.Status, store.backend, store.model)
+	}
+}
+
+// TestGenerate_UnknownBackendRejectedUpFront asserts a Plan rejection (unknown
+// backend/model) emits an error before any quota or row is spent.
+func TestGenerate_UnknownBackendRejectedUpFront(t *testing.T) {
+	store := &fakeStore{}
+	q := &stubQuota{}
+	streamed := &fakeStreamer{planErr: errors.New("unknown backend")}
+	g := NewGenerator(streamed, store, q, nil, 24000, nil)
+
+	var events []string
+	emit := func(event string, _ any) error { events = append(events, event); return nil }
+	req := domain.GenerateRequest{Anime: domain.AnimeRef{Title: "Frieren"}, Length: "oneshot", POV: "third", Rating: "teen", Language: "ru", Backend: "nope"}
+	if err := g.Generate(context.Background(), "user-1", "arisu42", req, emit); err == nil {
+		t.Fatal("expected the Plan error")
+	}
+	if q.acquired || store.created != nil || streamed.calls != 0 {
+		t.Errorf("acquired=%v created=%v calls=%d; want nothing spent", q.acquired, store.created, streamed.calls)
+	}
+	if len(events) != 1 || events[0] != "error" {
+		t.Errorf("events = %v", events)
+	}
+}
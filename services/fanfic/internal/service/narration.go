@@ -0,0 +1,522 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	liberrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/export"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/narration"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/voicevox"
+)
+
+const (
+	// narrationRenderVersion is part of every fingerprint; bump it when the
+	// chunking or encoding changes so cached files are rebuilt.
+	narrationRenderVersion = 1
+	// maxNarrationChunks caps one narration at roughly 100k characters —
+	// several hours of audio, whose joined WAV the worker holds on local disk
+	// while encoding.
+	maxNarrationChunks = 200
+	// maxNarrationAttempts consecutive failures of one chunk (engine down,
+	// storage errors) fail the job; it can still be resumed from there.
+	maxNarrationAttempts = 5
+	// narrationRetryAfter is how long a paused job waits before it is
+	// claimed again — long enough for the engine to restart after the
+	// governor releases it.
+	narrationRetryAfter = time.Minute
+	// narrationStaleAfter pauses jobs left running this long without
+	// progress (a worker that died mid-job).
+	narrationStaleAfter = 15 * time.Minute
+	// narrationShedLevel is the governor level from which synthesis pauses —
+	// the same threshold the gateway's Zundamon facade sheds at.
+	narrationShedLevel = 1
+	// narrationPrefix is the object-storage prefix of narrations; each job's
+	// chunks live under {fingerprint}/chunks/ until the file is encoded.
+	narrationPrefix = "fanfic-narrations/"
+	// speakersTTL is how long the engine's speaker list is cached.
+	speakersTTL = 5 * time.Minute
+)
+
+// errNarrationShed is returned while the platform is too loaded to narrate.
+var errNarrationShed = liberrors.ServiceUnavailable("narration is paused while the platform is under load")
+
+// narrationStore is the subset of *repo.NarrationRepository NarrationService depends on.
+type narrationStore interface {
+	CreateOrGet(ctx context.Context, n *domain.Narration) (*domain.Narration, error)
+	Get(ctx context.Context, id string) (*domain.Narration, error)
+	Requeue(ctx context.Context, id, userID string) error
+	ClaimNext(ctx context.Context, retryBefore time.Time) (*domain.Narration, error)
+	Advance(ctx context.Context, id string, done int, chunkStorage string) error
+	Pause(ctx context.Context, id string, attempts int, msg string) error
+	Complete(ctx context.Context, id, storage, key string, size, durationMs int64, at time.Time) error
+	Fail(ctx context.Context, id, msg string) error
+	ResetStale(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// narrationSource reads the works a narration reads (*repo.WorkRepository).
+type narrationSource interface {
+	GetWork(ctx context.Context, id string) (*domain.Fanfic, error)
+	Chapters(ctx context.Context, workID string, publishedOnly bool) ([]domain.Chapter, error)
+}
+
+// narrationEngine is the VOICEVOX engine (*voicevox.Client).
+type narrationEngine interface {
+	Speakers(ctx context.Context) ([]voicevox.Speaker, error)
+	Synthesize(ctx context.Context, text string, voice voicevox.Voice) ([]byte, error)
+}
+
+// narrationStorage stores chunks and encoded files (*storagegw.Gateway).
+type narrationStorage interface {
+	Upload(ctx context.Context, prefix, path string) (string, error)
+	DownloadURL(ctx context.Context, storage, key string) (string, error)
+	DownloadPrefix(ctx context.Context, storage, prefix, dir string) error
+	DeletePrefix(ctx context.Context, storage, prefix string) error
+}
+
+// audioEncoder encodes the joined WAV (narration.FFmpeg).
+type audioEncoder interface {
+	Encode(ctx context.Context, src, dst, format string, meta narration.Meta, chapters []narration.Chapter) error
+}
+
+// levelReader is the governor's degradation signal (*cache.DegradationWatcher).
+type levelReader interface {
+	Level() int
+}
+
+// NarrationService narrates Japanese fanfics through the VOICEVOX engine.
+// Requests freeze the fanfic's text into a chunk plan and enqueue a job keyed
+// by its fingerprint; Run synthesizes one chunk at a time in the background,
+// storing each chunk as it lands, and finally joins and encodes them into one
+// Opus/MP3 file with a chapter marker per fanfic chapter. Synthesis is
+// low-priority work: it pauses while the governor reports Elevated or worse,
+// and a paused, restarted or failed job resumes at its next unstored chunk.
+type NarrationService struct {
+	store   narrationStore
+	source  narrationSource
+	engine  narrationEngine
+	storage narrationStorage
+	encoder audioEncoder
+	level   levelReader
+	now     func() time.Time
+	log     *logger.Logger
+
+	speakersMu      sync.Mutex
+	speakers        []voicevox.Speaker
+	speakersExpires time.Time
+}
+
+// NewNarrationService constructs a NarrationService. level may be nil
+// (never sheds).
+func NewNarrationService(store narrationStore, source narrationSource, engine narrationEngine, storage narrationStorage, encoder audioEncoder, level levelReader, now func() time.Time, log *logger.Logger) *NarrationService {
+	if now == nil {
+		now = time.Now
+	}
+	return &NarrationService{store: store, source: source, engine: engine, storage: storage, encoder: encoder, level: level, now: now, log: log}
+}
+
+// Speakers lists the engine's talk voices for the picker. Like the gateway
+// facade it is shed while the platform is under load.
+func (s *NarrationService) Speakers(ctx context.Context) ([]voicevox.Speaker, error) {
+	if s.shed() {
+		return nil, errNarrationShed
+	}
+	return s.loadSpeakers(ctx)
+}
+
+func (s *NarrationService) loadSpeakers(ctx context.Context) ([]voicevox.Speaker, error) {
+	s.speakersMu.Lock()
+	defer s.speakersMu.Unlock()
+	if len(s.speakers) > 0 && s.now().Before(s.speakersExpires) {
+		return s.speakers, nil
+	}
+	speakers, err := s.engine.Speakers(ctx)
+	if err != nil {
+		if s.log != nil {
+			s.log.Warnw("VOICEVOX speaker discovery failed", "error", err)
+		}
+		return nil, liberrors.ServiceUnavailable("VOICEVOX is unavailable")
+	}
+	s.speakers, s.speakersExpires = speakers, s.now().Add(speakersTTL)
+	return speakers, nil
+}
+
+// Request enqueues (or returns the cached) narration of a Japanese fanfic the
+// user may read. A failed job for the same text and voice is resumed.
+func (s *NarrationService) Request(ctx context.Context, userID, fanficID string, req domain.NarrationRequest) (*domain.Narration, error) {
+	f, err := readableFanfic(ctx, s.source, userID, fanficID)
+	if err != nil {
+		return nil, err
+	}
+	if f.Language != "ja" {
+		return nil, liberrors.InvalidInput("only Japanese fanfics can be narrated")
+	}
+	speakers, err := s.loadSpeakers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	speaker, style, ok := findStyle(speakers, *req.StyleID)
+	if !ok {
+		return nil, liberrors.InvalidInput(fmt.Sprintf("unknown style %d", *req.StyleID))
+	}
+	speed := req.Speed
+	if speed == 0 {
+		speed = 1
+	}
+
+	parts, err := s.plan(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	total := 0
+	for _, p := range parts {
+		total += len(p.Chunks)
+	}
+	if total == 0 {
+		return nil, liberrors.New(liberrors.CodeConflict, "fanfic has nothing to narrate")
+	}
+	if total > maxNarrationChunks {
+		return nil, liberrors.InvalidInput("fanfic is too long to narrate")
+	}
+	plan, err := json.Marshal(parts)
+	if err != nil {
+		return nil, liberrors.Wrap(err, liberrors.CodeInternal, "encode narration plan")
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d\x00%d\x00%g\x00%s\x00%s\x00", narrationRenderVersion, style.ID, speed, req.Format, f.Title) + string(plan)))
+
+	got, err := s.store.CreateOrGet(ctx, &domain.Narration{
+		UserID:      userID,
+		FanficID:    f.ID,
+		Fingerprint: hex.EncodeToString(sum[:]),
+		Title:       f.Title,
+		Speaker:     speaker.Name,
+		StyleID:     style.ID,
+		StyleName:   style.Name,
+		Speed:       speed,
+		Format:      req.Format,
+		Status:      domain.NarrationPending,
+		Plan:        plan,
+		ChunksTotal: total,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if got.Status == domain.NarrationFailed {
+		if err := s.store.Requeue(ctx, got.ID, userID); err != nil {
+			return nil, err
+		}
+		got.Status, got.ErrorMsg, got.UserID = domain.NarrationPending, "", userID
+	}
+	return got, nil
+}
+
+// Get returns a narration of a fanfic the user may still read.
+func (s *NarrationService) Get(ctx context.Context, userID, id string) (*domain.Narration, error) {
+	n, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := readableFanfic(ctx, s.source, userID, n.FanficID); err != nil {
+		return nil, liberrors.NotFound("narration")
+	}
+	return n, nil
+}
+
+// Resume puts a failed or paused narration straight back in the queue; it
+// continues from its last stored chunk. Other jobs are returned unchanged.
+func (s *NarrationService) Resume(ctx context.Context, userID, id string) (*domain.Narration, error) {
+	n, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if n.Status == domain.NarrationFailed || n.Status == domain.NarrationPaused {
+		if err := s.store.Requeue(ctx, n.ID, userID); err != nil {
+			return nil, err
+		}
+		n.Status, n.ErrorMsg = domain.NarrationPending, ""
+	}
+	return n, nil
+}
+
+// DownloadURL returns a ready narration with a presigned GET URL of its
+// file. The URL is internal to the storage network; the handler streams it.
+func (s *NarrationService) DownloadURL(ctx context.Context, userID, id string) (*domain.Narration, string, error) {
+	n, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if n.Status != domain.NarrationReady {
+		return nil, "", liberrors.New(liberrors.CodeConflict, "narration is not ready")
+	}
+	url, err := s.storage.DownloadURL(ctx, n.Storage, n.ObjectKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return n, url, nil
+}
+
+// Run works the narration queue every interval until ctx is done.
+func (s *NarrationService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.ProcessPending(ctx); err != nil {
+				if s.log != nil {
+					s.log.Warnw("fanfic narration failed", "error", err)
+				}
+			} else if n > 0 && s.log != nil {
+				s.log.Infow("finished fanfic narrations", "count", n)
+			}
+		}
+	}
+}
+
+// ProcessPending works claimable jobs one at a time until the queue is empty
+// or the platform sheds, returning how many narrations finished. Nothing is
+// claimed while shedding. A failing job is marked failed and does not stop
+// the drain.
+func (s *NarrationService) ProcessPending(ctx context.Context) (int, error) {
+	if s.shed() {
+		return 0, nil
+	}
+	if n, err := s.store.ResetStale(ctx, s.now().Add(-narrationStaleAfter)); err != nil {
+		return 0, err
+	} else if n > 0 && s.log != nil {
+		s.log.Warnw("paused stale fanfic narrations", "count", n)
+	}
+	finished := 0
+	for ctx.Err() == nil && !s.shed() {
+		n, err := s.store.ClaimNext(ctx, s.now().Add(-narrationRetryAfter))
+		if err != nil {
+			return finished, err
+		}
+		if n == nil {
+			break
+		}
+		ready, err := s.process(ctx, n)
+		if err != nil {
+			if s.log != nil {
+				s.log.Warnw("fanfic narration failed", "narration_id", n.ID, "chunks_done", n.ChunksDone, "error", err)
+			}
+			if ferr := s.store.Fail(context.WithoutCancel(ctx), n.ID, err.Error()); ferr != nil {
+				return finished, ferr
+			}
+			continue
+		}
+		if ready {
+			finished++
+		}
+	}
+	return finished, nil
+}
+
+// process synthesizes n's remaining chunks and, once all are stored, encodes
+// the narration. It reports whether n finished; a nil error with false means
+// n was paused (shedding, shutdown or a retryable failure).
+func (s *NarrationService) process(ctx context.Context, n *domain.Narration) (bool, error) {
+	var parts []domain.NarrationPart
+	if err := json.Unmarshal(n.Plan, &parts); err != nil {
+		return false, fmt.Errorf("decode plan: %w", err)
+	}
+	var chunks []string
+	for _, p := range parts {
+		chunks = append(chunks, p.Chunks...)
+	}
+	if len(chunks) != n.ChunksTotal {
+		return false, fmt.Errorf("plan has %d chunks, expected %d", len(chunks), n.ChunksTotal)
+	}
+
+	dir, err := os.MkdirTemp("", "fanfic-narration-")
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(dir)
+	prefix := narrationPrefix + n.Fingerprint + "/"
+	chunkPrefix := prefix + "chunks/"
+	voice := voicevox.Voice{StyleID: n.StyleID, Speed: n.Speed}
+
+	for i := n.ChunksDone; i < len(chunks); i++ {
+		if s.shed() || ctx.Err() != nil {
+			return false, s.pause(ctx, n, n.Attempts, "")
+		}
+		storage, err := s.synthesizeChunk(ctx, dir, chunkPrefix, i, chunks[i], voice)
+		if err != nil {
+			return false, s.retry(ctx, n, fmt.Errorf("chunk %d: %w", i+1, err))
+		}
+		if err := s.store.Advance(ctx, n.ID, i+1, storage); err != nil {
+			return false, s.retry(ctx, n, err)
+		}
+		n.ChunksDone, n.ChunkStorage, n.Attempts = i+1, storage, 0
+	}
+
+	// Every chunk is stored: fetch them back (this worker may not be the one
+	// that synthesized them) and build the file.
+	chunkDir := filepath.Join(dir, "chunks")
+	if err := s.storage.DownloadPrefix(ctx, n.ChunkStorage, chunkPrefix, chunkDir); err != nil {
+		return false, s.retry(ctx, n, fmt.Errorf("download chunks: %w", err))
+	}
+	paths := make([]string, len(chunks))
+	for i := range chunks {
+		paths[i] = filepath.Join(chunkDir, chunkName(i))
+		if _, err := os.Stat(paths[i]); err != nil {
+			// A lost chunk is synthesized again rather than failing the job.
+			if err := s.store.Advance(ctx, n.ID, i, n.ChunkStorage); err != nil {
+				return false, err
+			}
+			return false, s.pause(ctx, n, 0, fmt.Sprintf("chunk %d is missing; resynthesizing", i+1))
+		}
+	}
+	joined := filepath.Join(dir, "narration.wav")
+	durations, err := narration.ConcatWAV(joined, paths)
+	if err != nil {
+		return false, fmt.Errorf("join chunks: %w", err)
+	}
+	_ = os.RemoveAll(chunkDir)
+
+	var chapters []narration.Chapter
+	var at time.Duration
+	i := 0
+	for _, p := range parts {
+		start := at
+		for range p.Chunks {
+			at += durations[i]
+			i++
+		}
+		if len(p.Chunks) > 0 {
+			chapters = append(chapters, narration.Chapter{Title: p.Title, Start: start, End: at})
+		}
+	}
+	out := filepath.Join(dir, "narration."+n.Format)
+	meta := narration.Meta{Title: n.Title, Album: n.Title, Artist: n.Speaker + "（" + n.StyleName + "）", Language: "jpn"}
+	if err := s.encoder.Encode(ctx, joined, out, n.Format, meta, chapters); err != nil {
+		return false, fmt.Errorf("encode %s: %w", n.Format, err)
+	}
+	info, err := os.Stat(out)
+	if err != nil {
+		return false, err
+	}
+	storage, err := s.storage.Upload(ctx, prefix, out)
+	if err != nil {
+		return false, s.retry(ctx, n, fmt.Errorf("upload: %w", err))
+	}
+	if err := s.store.Complete(ctx, n.ID, storage, prefix+filepath.Base(out), info.Size(), at.Milliseconds(), s.now()); err != nil {
+		return false, err
+	}
+	if err := s.storage.DeletePrefix(ctx, n.ChunkStorage, chunkPrefix); err != nil && s.log != nil {
+		s.log.Warnw("fanfic narration chunk cleanup failed", "narration_id", n.ID, "error", err)
+	}
+	return true, nil
+}
+
+// synthesizeChunk reads chunk i and stores it under chunkPrefix, returning
+// the backend it landed on.
+func (s *NarrationService) synthesizeChunk(ctx context.Context, dir, chunkPrefix string, i int, text string, voice voicevox.Voice) (string, error) {
+	audio, err := s.engine.Synthesize(ctx, text, voice)
+	if err != nil {
+		return "", err
+	}
+	if _, _, err := narration.ParseWAV(audio); err != nil {
+		return "", fmt.Errorf("engine returned bad audio: %w", err)
+	}
+	path := filepath.Join(dir, chunkName(i))
+	if err := os.WriteFile(path, audio, 0o644); err != nil {
+		return "", err
+	}
+	defer os.Remove(path)
+	return s.storage.Upload(ctx, chunkPrefix, path)
+}
+
+// retry pauses n after a retryable failure, or returns cause once n has
+// failed maxNarrationAttempts times in a row. Failures while shedding or
+// shutting down (the engine is being stopped, requests are cancelled) do not
+// count.
+func (s *NarrationService) retry(ctx context.Context, n *domain.Narration, cause error) error {
+	if s.shed() || ctx.Err() != nil {
+		return s.pause(ctx, n, n.Attempts, "")
+	}
+	attempts := n.Attempts + 1
+	if attempts >= maxNarrationAttempts {
+		return cause
+	}
+	if s.log != nil {
+		s.log.Warnw("fanfic narration paused after a failure", "narration_id", n.ID, "attempt", attempts, "error", cause)
+	}
+	return s.pause(ctx, n, attempts, cause.Error())
+}
+
+// pause parks n; it is written even when ctx is cancelled (shutdown) so the
+// job does not sit "running" until it goes stale.
+func (s *NarrationService) pause(ctx context.Context, n *domain.Narration, attempts int, msg string) error {
+	return s.store.Pause(context.WithoutCancel(ctx), n.ID, attempts, msg)
+}
+
+func (s *NarrationService) shed() bool {
+	return s.level != nil && s.level.Level() >= narrationShedLevel
+}
+
+// plan splits a fanfic into narration parts: human works by their published
+// chapters, generated ones by their continue parts. Each part opens with its
+// title, and the first with the fanfic's title.
+func (s *NarrationService) plan(ctx context.Context, f *domain.Fanfic) ([]domain.NarrationPart, error) {
+	var raw []export.Part
+	if f.Origin == domain.OriginHuman {
+		chapters, err := s.source.Chapters(ctx, f.ID, true)
+		if err != nil {
+			return nil, err
+		}
+		for i, c := range chapters {
+			title := c.Title
+			if title == "" {
+				title = fmt.Sprintf("第%d章", i+1)
+			}
+			raw = append(raw, export.Part{Title: title, Text: c.Content})
+		}
+	} else {
+		raw = SplitParts(f.Content, f.Title, f.Language)
+	}
+	if len(raw) == 0 {
+		return nil, liberrors.New(liberrors.CodeConflict, "fanfic has no published chapters")
+	}
+	parts := make([]domain.NarrationPart, 0, len(raw))
+	for i, p := range raw {
+		var paragraphs []string
+		if i == 0 && p.Title != f.Title {
+			paragraphs = append(paragraphs, f.Title)
+		}
+		paragraphs = append(append(paragraphs, p.Title), export.Paragraphs(p.Text)...)
+		parts = append(parts, domain.NarrationPart{Title: p.Title, Chunks: narration.Split(paragraphs, voicevox.MaxRunes)})
+	}
+	return parts, nil
+}
+
+func findStyle(speakers []voicevox.Speaker, id int) (voicevox.Speaker, voicevox.Style, bool) {
+	for _, sp := range speakers {
+		for _, st := range sp.Styles {
+			if st.ID == id {
+				return sp, st, true
+			}
+		}
+	}
+	return voicevox.Speaker{}, voicevox.Style{}, false
+}
+
+// chunkName is chunk i's file name; zero-padded so names sort in order.
+func chunkName(i int) string {
+	return fmt.Sprintf("chunk-%05d.wav", i+1)
+}
@@ -0,0 +1,381 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	liberrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/narration"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/repo"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/voicevox"
+	"gorm.io/datatypes"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// testWAV is one second of 24 kHz mono silence.
+func testWAV() []byte {
+	const n = 24000 * 2
+	h := make([]byte, 44, 44+n)
+	copy(h[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(h[4:8], 36+n)
+	copy(h[8:16], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(h[16:20], 16)
+	binary.LittleEndian.PutUint16(h[20:22], 1)
+	binary.LittleEndian.PutUint16(h[22:24], 1)
+	binary.LittleEndian.PutUint32(h[24:28], 24000)
+	binary.LittleEndian.PutUint32(h[28:32], 48000)
+	binary.LittleEndian.PutUint16(h[32:34], 2)
+	binary.LittleEndian.PutUint16(h[34:36], 16)
+	copy(h[36:40], "data")
+	binary.LittleEndian.PutUint32(h[40:44], n)
+	return append(h, make([]byte, n)...)
+}
+
+// fakeEngine synthesizes one second of audio per chunk. onCall runs before
+// each synthesis (to flip the governor level mid-job); err fails every call.
+type fakeEngine struct {
+	texts      []string
+	err        error
+	speakerErr error
+	onCall     func(n int)
+}
+
+func (e *fakeEngine) Speakers(context.Context) ([]voicevox.Speaker, error) {
+	if e.speakerErr != nil {
+		return nil, e.speakerErr
+	}
+	return []voicevox.Speaker{{Name: "ずんだもん", Styles: []voicevox.Style{{ID: 3, Name: "ノーマル", Type: "talk"}}}}, nil
+}
+
+func (e *fakeEngine) Synthesize(_ context.Context, text string, _ voicevox.Voice) ([]byte, error) {
+	if e.onCall != nil {
+		e.onCall(len(e.texts))
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+	e.texts = append(e.texts, text)
+	return testWAV(), nil
+}
+
+// fakeNarrationStorage keeps objects in memory.
+type fakeNarrationStorage struct{ files map[string][]byte }
+
+func (s *fakeNarrationStorage) Upload(_ context.Context, prefix, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	s.files[prefix+filepath.Base(path)] = data
+	return "minio", nil
+}
+
+func (s *fakeNarrationStorage) DownloadURL(_ context.Context, storage, key string) (string, error) {
+	if _, ok := s.files[key]; !ok {
+		return "", liberrors.NotFound("export file")
+	}
+	return "http://" + storage + "/" + key, nil
+}
+
+func (s *fakeNarrationStorage) DownloadPrefix(_ context.Context, _, prefix, dir string) error {
+	for key, data := range s.files {
+		if name, ok := strings.CutPrefix(key, prefix); ok {
+			_ = os.MkdirAll(dir, 0o755)
+			if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *fakeNarrationStorage) DeletePrefix(_ context.Context, _, prefix string) error {
+	for key := range s.files {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.files, key)
+		}
+	}
+	return nil
+}
+
+func (s *fakeNarrationStorage) count(prefix string) int {
+	n := 0
+	for key := range s.files {
+		if strings.HasPrefix(key, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+// fakeEncoder copies the joined WAV and records the chapters.
+type fakeEncoder struct {
+	chapters []narration.Chapter
+	meta     narration.Meta
+}
+
+func (e *fakeEncoder) Encode(_ context.Context, src, dst, _ string, meta narration.Meta, chapters []narration.Chapter) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	e.chapters, e.meta = chapters, meta
+	return os.WriteFile(dst, data, 0o644)
+}
+
+type fakeLevel struct{ level int }
+
+func (l *fakeLevel) Level() int { return l.level }
+
+type narrationFixture struct {
+	svc     *NarrationService
+	db      *gorm.DB
+	engine  *fakeEngine
+	storage *fakeNarrationStorage
+	encoder *fakeEncoder
+	level   *fakeLevel
+}
+
+func newTestNarrationService(t *testing.T) *narrationFixture {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.Fanfic{}, &domain.Chapter{}, &domain.Narration{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+	fx := &narrationFixture{
+		db:      db,
+		engine:  &fakeEngine{},
+		storage: &fakeNarrationStorage{files: map[string][]byte{}},
+		encoder: &fakeEncoder{},
+		level:   &fakeLevel{},
+	}
+	fx.svc = NewNarrationService(repo.NewNarrationRepository(db), repo.NewWorkRepository(db), fx.engine, fx.storage, fx.encoder, fx.level, nil, nil)
+	return fx
+}
+
+// waitOut backdates a paused job past its retry delay.
+func (fx *narrationFixture) waitOut(t *testing.T, id string) {
+	t.Helper()
+	if err := fx.db.Model(&domain.Narration{}).Where("id = ?", id).
+		UpdateColumn("updated_at", time.Now().Add(-2*narrationRetryAfter)).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+// createJapaneseWork adds a public human-authored work with two published
+// chapters and a draft.
+func createJapaneseWork(t *testing.T, db *gorm.DB) *domain.Fanfic {
+	t.Helper()
+	work := &domain.Fanfic{UserID: "author", Origin: domain.OriginHuman, Status: domain.StatusPublished,
+		AuthorUsername: "writer", AnimeTitle: "Frieren", Title: "夏祭り", Language: "ja", Tags: datatypes.JSON(`[]`)}
+	if err := db.Omit("anime_id").Create(work).Error; err != nil {
+		t.Fatalf("create work: %v", err)
+	}
+	db.Create(&domain.Chapter{FanficID: work.ID, Position: 1, Title: "出会い", Content: "夜空に花火が上がった。\n\n「きれいだね」と彼女は言った。", Status: domain.StatusPublished})
+	db.Create(&domain.Chapter{FanficID: work.ID, Position: 2, Content: "祭りは終わった。", Status: domain.StatusPublished})
+	db.Create(&domain.Chapter{FanficID: work.ID, Position: 3, Content: "下書き。", Status: domain.StatusDraft})
+	return work
+}
+
+func style(id int) *int { return &id }
+
+func TestNarrationService_RequestValidation(t *testing.T) {
+	fx := newTestNarrationService(t)
+	ctx := context.Background()
+	work := createJapaneseWork(t, fx.db)
+
+	fx.engine.speakerErr = errors.New("connection refused")
+	if _, err := fx.svc.Request(ctx, "reader", work.ID, domain.NarrationRequest{StyleID: style(3), Format: domain.NarrationOpus}); !isCode(err, liberrors.CodeUnavailable) {
+		t.Errorf("engine down = %v; want Unavailable", err)
+	}
+	fx.engine.speakerErr = nil
+	if _, err := fx.svc.Request(ctx, "reader", work.ID, domain.NarrationRequest{StyleID: style(99), Format: domain.NarrationOpus}); !isCode(err, liberrors.CodeInvalidInput) {
+		t.Errorf("unknown style = %v; want InvalidInput", err)
+	}
+	russian := createGenerated(t, fx.db, "reader", "Начало.")
+	if _, err := fx.svc.Request(ctx, "reader", russian.ID, domain.NarrationRequest{StyleID: style(3), Format: domain.NarrationOpus}); !isCode(err, liberrors.CodeInvalidInput) {
+		t.Errorf("russian fanfic = %v; want InvalidInput", err)
+	}
+
+	fx.level.level = 1
+	if _, err := fx.svc.Speakers(ctx); !isCode(err, liberrors.CodeUnavailable) {
+		t.Errorf("speakers while shedding = %v; want Unavailable", err)
+	}
+}
+
+func TestNarrationService_NarratesWithChapters(t *testing.T) {
+	fx := newTestNarrationService(t)
+	ctx := context.Background()
+	work := createJapaneseWork(t, fx.db)
+
+	n, err := fx.svc.Request(ctx, "reader", work.ID, domain.NarrationRequest{StyleID: style(3), Format: domain.NarrationOpus})
+	if err != nil || n.Status != domain.NarrationPending || n.Speaker != "ずんだもん" || n.Speed != 1 {
+		t.Fatalf("Request = %+v, %v", n, err)
+	}
+	// Chapter 1: work title + chapter title, then its text; chapter 2: its
+	// default title, then its text. The draft is not read.
+	if n.ChunksTotal != 2 {
+		t.Fatalf("chunks_total = %d; want 2", n.ChunksTotal)
+	}
+	if _, _, err := fx.svc.DownloadURL(ctx, "reader", n.ID); !isCode(err, liberrors.CodeConflict) {
+		t.Errorf("download before narration = %v; want Conflict", err)
+	}
+
+	if done, err := fx.svc.ProcessPending(ctx); err != nil || done != 1 {
+		t.Fatalf("ProcessPending = %d, %v", done, err)
+	}
+	if got := strings.Join(fx.engine.texts, "|"); got != "夏祭り。出会い。夜空に花火が上がった。「きれいだね」と彼女は言った。|第2章。祭りは終わった。" {
+		t.Errorf("synthesized %q", got)
+	}
+	ready, err := fx.svc.Get(ctx, "reader", n.ID)
+	if err != nil || ready.Status != domain.NarrationReady || ready.ChunksDone != 2 || ready.DurationMs != 2000 {
+		t.Fatalf("after run = %+v, %v", ready, err)
+	}
+	if !strings.HasSuffix(ready.ObjectKey, "/narration.opus") {
+		t.Errorf("object key = %q", ready.ObjectKey)
+	}
+	if len(fx.encoder.chapters) != 2 || fx.encoder.chapters[0].Title != "出会い" || fx.encoder.chapters[1].Start != time.Second || fx.encoder.chapters[1].End != 2*time.Second {
+		t.Errorf("chapters = %+v", fx.encoder.chapters)
+	}
+	if fx.encoder.meta.Artist != "ずんだもん（ノーマル）" {
+		t.Errorf("meta = %+v", fx.encoder.meta)
+	}
+	if c := fx.storage.count(strings.TrimSuffix(ready.ObjectKey, "narration.opus") + "chunks/"); c != 0 {
+		t.Errorf("%d chunks left after encoding", c)
+	}
+	if _, url, err := fx.svc.DownloadURL(ctx, "reader", n.ID); err != nil || !strings.HasSuffix(url, ready.ObjectKey) {
+		t.Errorf("DownloadURL = %q, %v", url, err)
+	}
+
+	again, err := fx.svc.Request(ctx, "other", work.ID, domain.NarrationRequest{StyleID: style(3), Format: domain.NarrationOpus})
+	if err != nil || again.ID != n.ID || again.Status != domain.NarrationReady {
+		t.Errorf("cached request = %+v, %v", again, err)
+	}
+	mp3, _ := fx.svc.Request(ctx, "other", work.ID, domain.NarrationRequest{StyleID: style(3), Format: domain.NarrationMP3})
+	if mp3 == nil || mp3.ID == n.ID {
+		t.Errorf("mp3 request shares the opus row")
+	}
+}
+
+func TestNarrationService_PausesWhileShedding(t *testing.T) {
+	fx := newTestNarrationService(t)
+	ctx := context.Background()
+	work := createJapaneseWork(t, fx.db)
+	n, _ := fx.svc.Request(ctx, "reader", work.ID, domain.NarrationRequest{StyleID: style(3), Format: domain.NarrationMP3})
+
+	fx.level.level = 1
+	if done, err := fx.svc.ProcessPending(ctx); err != nil || done != 0 || len(fx.engine.texts) != 0 {
+		t.Fatalf("while shedding: done %d, %v, %d syntheses", done, err, len(fx.engine.texts))
+	}
+
+	// The governor goes Elevated while the first chunk is synthesized.
+	fx.level.level = 0
+	fx.engine.onCall = func(calls int) {
+		if calls == 0 {
+			fx.level.level = 1
+		}
+	}
+	if done, err := fx.svc.ProcessPending(ctx); err != nil || done != 0 {
+		t.Fatalf("interrupted run = %d, %v", done, err)
+	}
+	paused, _ := fx.svc.Get(ctx, "reader", n.ID)
+	if paused.Status != domain.NarrationPaused || paused.ChunksDone != 1 || paused.ErrorMsg != "" {
+		t.Fatalf("after shedding = %+v", paused)
+	}
+
+	// Back to Normal: the job waits out the retry delay, then resumes at
+	// chunk 2 without re-reading chunk 1.
+	fx.level.level = 0
+	fx.engine.onCall = nil
+	if done, _ := fx.svc.ProcessPending(ctx); done != 0 {
+		t.Fatalf("claimed a paused job before its retry delay")
+	}
+	fx.waitOut(t, n.ID)
+	if done, err := fx.svc.ProcessPending(ctx); err != nil || done != 1 {
+		t.Fatalf("resumed run = %d, %v", done, err)
+	}
+	if len(fx.engine.texts) != 2 {
+		t.Errorf("synthesized %d chunks; want 2 in total", len(fx.engine.texts))
+	}
+}
+
+func TestNarrationService_RetriesThenFailsAndResumes(t *testing.T) {
+	fx := newTestNarrationService(t)
+	ctx := context.Background()
+	work := createJapaneseWork(t, fx.db)
+	n, _ := fx.svc.Request(ctx, "reader", work.ID, domain.NarrationRequest{StyleID: style(3), Format: domain.NarrationOpus})
+
+	fx.engine.onCall = func(calls int) {
+		if calls == 1 {
+			fx.engine.err = errors.New("engine restarting")
+		}
+	}
+	for i := 1; i < maxNarrationAttempts; i++ {
+		if _, err := fx.svc.ProcessPending(ctx); err != nil {
+			t.Fatal(err)
+		}
+		got, _ := fx.svc.Get(ctx, "reader", n.ID)
+		if got.Status != domain.NarrationPaused || got.ChunksDone != 1 || !strings.Contains(got.ErrorMsg, "engine restarting") {
+			t.Fatalf("attempt %d: %+v", i, got)
+		}
+		fx.waitOut(t, n.ID)
+	}
+	_, _ = fx.svc.ProcessPending(ctx)
+	failed, _ := fx.svc.Get(ctx, "reader", n.ID)
+	if failed.Status != domain.NarrationFailed || failed.ChunksDone != 1 {
+		t.Fatalf("after %d attempts: %+v", maxNarrationAttempts, failed)
+	}
+
+	fx.engine.err, fx.engine.onCall = nil, nil
+	resumed, err := fx.svc.Resume(ctx, "reader", n.ID)
+	if err != nil || resumed.Status != domain.NarrationPending {
+		t.Fatalf("Resume = %+v, %v", resumed, err)
+	}
+	if done, err := fx.svc.ProcessPending(ctx); err != nil || done != 1 {
+		t.Fatalf("resumed run = %d, %v", done, err)
+	}
+	if len(fx.engine.texts) != 2 {
+		t.Errorf("synthesized %d chunks; want 2 in total", len(fx.engine.texts))
+	}
+}
+
+func TestNarrationService_ResynthesizesLostChunks(t *testing.T) {
+	fx := newTestNarrationService(t)
+	ctx := context.Background()
+	work := createJapaneseWork(t, fx.db)
+	n, _ := fx.svc.Request(ctx, "reader", work.ID, domain.NarrationRequest{StyleID: style(3), Format: domain.NarrationOpus})
+
+	// Store chunk 1 then stop; drop it from storage before the job resumes.
+	fx.engine.onCall = func(calls int) {
+		if calls == 0 {
+			fx.level.level = 1
+		}
+	}
+	_, _ = fx.svc.ProcessPending(ctx)
+	_ = fx.storage.DeletePrefix(ctx, "minio", narrationPrefix)
+	fx.level.level, fx.engine.onCall = 0, nil
+	if _, err := fx.svc.Resume(ctx, "reader", n.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = fx.svc.ProcessPending(ctx)
+	got, _ := fx.svc.Get(ctx, "reader", n.ID)
+	if got.Status != domain.NarrationPaused || got.ChunksDone != 0 {
+		t.Fatalf("after a lost chunk = %+v", got)
+	}
+	fx.waitOut(t, n.ID)
+	if done, err := fx.svc.ProcessPending(ctx); err != nil || done != 1 {
+		t.Fatalf("rerun = %d, %v", done, err)
+	}
+}
@@ -18,16 +18,19 @@ type quotaStore interface {
 	Expire(ctx context.Context, key string, ttl time.Duration) error
 	SetNX(ctx context.Context, key string, ttl time.Duration) (bool, error)
 	Del(ctx context.Context, key string) error
+	IncrBy(ctx context.Context, key string, n int64) (int64, error)
+	Get(ctx context.Context, key string) (int64, error)
 }
 
 type Quota struct {
 	store    quotaStore
 	dailyCap int
+	budgets  map[string]int // backend name → daily token budget; absent or <= 0 is unlimited
 	now      func() time.Time
 }
 
-func NewQuota(store quotaStore, dailyCap int, now func() time.Time) *Quota {
-	return &Quota{store: store, dailyCap: dailyCap, now: now}
+func NewQuota(store quotaStore, dailyCap int, budgets map[string]int, now func() time.Time) *Quota {
+	return &Quota{store: store, dailyCap: dailyCap, budgets: budgets, now: now}
 }
 
 // Acquire enforces (a) one concurrent generation per user and (b) a daily cap.
@@ -59,3 +62,36 @@ func (q *Quota) Acquire(ctx context.Context, userID string) (func(), error) {
 	}
 	return release, nil
 }
+
+// AddTokens meters one generation attempt's usage — successful, failed or
+// cut short — against backend's daily counter (implements llm.Budget).
+// Best-effort: a Redis error drops the sample.
+func (q *Quota) AddTokens(ctx context.Context, backend string, tokens int) {
+	if tokens <= 0 {
+		return
+	}
+	key := q.tokensKey(backend)
+	n, err := q.store.IncrBy(ctx, key, int64(tokens))
+	if err == nil && n == int64(tokens) {
+		_ = q.store.Expire(ctx, key, 48*time.Hour)
+	}
+}
+
+// BackendAvailable reports whether backend is still under its daily token
+// budget (implements llm.Budget). Unbudgeted backends are always available,
+// and a Redis error FAILS OPEN like Acquire.
+func (q *Quota) BackendAvailable(ctx context.Context, backend string) bool {
+	budget := q.budgets[backend]
+	if budget <= 0 {
+		return true
+	}
+	used, err := q.store.Get(ctx, q.tokensKey(backend))
+	if err != nil {
+		return true
+	}
+	return used < int64(budget)
+}
+
+func (q *Quota) tokensKey(backend string) string {
+	return fmt.Sprintf("fanfic:tokens:%s:%s", backend, q.now().UTC().Format("20060102"))
+}
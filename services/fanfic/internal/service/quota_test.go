@@ -3,8 +3,11 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/llm"
 )
 
 type fakeQuotaStore struct {
@@ -36,10 +39,23 @@ func (f *fakeQuotaStore) SetNX(_ context.Context, key string, _ time.Duration) (
 	return true, nil
 }
 func (f *fakeQuotaStore) Del(_ context.Context, key string) error { delete(f.locks, key); return nil }
+func (f *fakeQuotaStore) IncrBy(_ context.Context, key string, n int64) (int64, error) {
+	if f.fail {
+		return 0, errors.New("redis down")
+	}
+	f.counts[key] += n
+	return f.counts[key], nil
+}
+func (f *fakeQuotaStore) Get(_ context.Context, key string) (int64, error) {
+	if f.fail {
+		return 0, errors.New("redis down")
+	}
+	return f.counts[key], nil
+}
 
 func TestQuota_AllowsUnderCapThenBlocks(t *testing.T) {
 	store := newFakeQuotaStore()
-	q := NewQuota(store, 2, func() time.Time { return time.Unix(0, 0) })
+	q := NewQuota(store, 2, nil, func() time.Time { return time.Unix(0, 0) })
 	ctx := context.Background()
 	for i := 0; i < 2; i++ {
 		rel, err := q.Acquire(ctx, "u")
@@ -55,7 +71,7 @@ func TestQuota_AllowsUnderCapThenBlocks(t *testing.T) {
 
 func TestQuota_ConcurrencyLock(t *testing.T) {
 	store := newFakeQuotaStore()
-	q := NewQuota(store, 100, func() time.Time { return time.Unix(0, 0) })
+	q := NewQuota(store, 100, nil, func() time.Time { return time.Unix(0, 0) })
 	rel, err := q.Acquire(context.Background(), "u")
 	if err != nil {
 		t.Fatalf("first acquire: %v", err)
@@ -69,10 +85,74 @@ func TestQuota_ConcurrencyLock(t *testing.T) {
 func TestQuota_FailsOpenOnRedisError(t *testing.T) {
 	store := newFakeQuotaStore()
 	store.fail = true
-	q := NewQuota(store, 1, func() time.Time { return time.Unix(0, 0) })
+	q := NewQuota(store, 1, nil, func() time.Time { return time.Unix(0, 0) })
 	rel, err := q.Acquire(context.Background(), "u")
 	if err != nil {
 		t.Fatalf("expected fail-open (nil err), got %v", err)
 	}
 	rel() // no-op release must be safe
 }
+
+func TestQuota_BackendTokenBudget(t *testing.T) {
+	store := newFakeQuotaStore()
+	q := NewQuota(store, 100, map[string]int{"groq": 1000}, func() time.Time { return time.Unix(0, 0) })
+	ctx := context.Background()
+
+	q.AddTokens(ctx, "groq", 600)
+	q.AddTokens(ctx, "lan", 5000)
+	if !q.BackendAvailable(ctx, "groq") {
+		t.Fatal("groq should be available at 600/1000")
+	}
+	q.AddTokens(ctx, "groq", 400)
+	if q.BackendAvailable(ctx, "groq") {
+		t.Error("groq should be exhausted at 1000/1000")
+	}
+	if !q.BackendAvailable(ctx, "lan") {
+		t.Error("an unbudgeted backend must stay available")
+	}
+	if used := store.counts["fanfic:tokens:lan:19700101"]; used != 5000 {
+		t.Errorf("lan used = %d, want 5000 (metered per backend)", used)
+	}
+
+	store.fail = true
+	if !q.BackendAvailable(ctx, "groq") {
+		t.Error("must fail open on redis error")
+	}
+}
+
+// partialGen streams n bytes and then fails without reporting usage.
+type partialGen struct {
+	name  string
+	n     int
+	calls int
+}
+
+func (g *partialGen) Name() string     { return g.name }
+func (g *partialGen) Models() []string { return []string{"m"} }
+func (g *partialGen) Stream(_ context.Context, _ string, _ llm.Prompt, onDelta func(string)) (string, int, error) {
+	g.calls++
+	out := strings.Repeat("x", g.n)
+	onDelta(out)
+	return out, 0, errors.New("stream reset")
+}
+
+func TestQuota_FailedAttemptsSpendBackendBudget(t *testing.T) {
+	store := newFakeQuotaStore()
+	q := NewQuota(store, 100, map[string]int{"groq": 100}, func() time.Time { return time.Unix(0, 0) })
+	groq := &partialGen{name: "groq", n: 400}
+	lan := &partialGen{name: "lan", n: 8}
+	r := llm.NewRouter([]llm.Generator{groq, lan}, q, nil)
+	ctx := context.Background()
+
+	plan, _ := r.Plan("", "")
+	_, _ = r.Stream(ctx, plan, llm.Prompt{}, llm.Hooks{})
+	if used := store.counts["fanfic:tokens:groq:19700101"]; used != 100 {
+		t.Fatalf("groq used = %d; want its failed partial stream metered (100)", used)
+	}
+
+	// The budget spent by failures alone keeps groq out of the next plan.
+	_, _ = r.Stream(ctx, plan, llm.Prompt{}, llm.Hooks{})
+	if groq.calls != 1 || lan.calls != 2 {
+		t.Errorf("calls groq=%d lan=%d; want groq skipped once over budget", groq.calls, lan.calls)
+	}
+}
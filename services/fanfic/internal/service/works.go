@@ -0,0 +1,435 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	liberrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"gorm.io/datatypes"
+)
+
+const (
+	// maxChaptersPerWork caps a work's chapter count, drafts included.
+	maxChaptersPerWork = 500
+	// publishBatch is how many due chapters one scheduler tick publishes.
+	publishBatch = 100
+)
+
+// workStore is the subset of *repo.WorkRepository WorkService depends on.
+type workStore interface {
+	CreateWork(ctx context.Context, f *domain.Fanfic) error
+	GetWork(ctx context.Context, id string) (*domain.Fanfic, error)
+	UpdateWorkMeta(ctx context.Context, f *domain.Fanfic) error
+	CreateChapter(ctx context.Context, c *domain.Chapter) error
+	GetChapter(ctx context.Context, workID, chapterID string) (*domain.Chapter, error)
+	UpdateChapter(ctx context.Context, c *domain.Chapter) error
+	PublishChapter(ctx context.Context, c *domain.Chapter, from string) (bool, error)
+	DeleteChapter(ctx context.Context, c *domain.Chapter) error
+	Chapters(ctx context.Context, workID string, publishedOnly bool) ([]domain.Chapter, error)
+	DueChapters(ctx context.Context, now time.Time, limit int) ([]domain.Chapter, error)
+	Search(ctx context.Context, p domain.SearchParams) ([]domain.Fanfic, int64, error)
+	AddKudos(ctx context.Context, workID, userID string) (bool, error)
+	SetBookmark(ctx context.Context, b *domain.Bookmark) error
+	DeleteBookmark(ctx context.Context, userID, workID string) error
+	Bookmarks(ctx context.Context, userID string, limit, offset int) ([]domain.BookmarkedWork, int64, error)
+	Subscribe(ctx context.Context, userID, workID string) error
+	Unsubscribe(ctx context.Context, userID, workID string) error
+	Subscribers(ctx context.Context, workID string) ([]string, error)
+	ViewerState(ctx context.Context, userID, workID string) (kudosed, bookmarked, subscribed bool, err error)
+}
+
+// chapterNotifier tells subscribers about a newly published chapter.
+// Nil-safe: a nil notifier skips notifications.
+type chapterNotifier interface {
+	NotifyChapter(ctx context.Context, work *domain.Fanfic, c *domain.Chapter, userIDs []string)
+}
+
+// WorkService manages human-authored works: drafting, chapter publishing
+// (immediate or scheduled), reader kudos/bookmarks/subscriptions and the
+// public search across human and generated works.
+type WorkService struct {
+	store  workStore
+	notify chapterNotifier
+	now    func() time.Time
+	log    *logger.Logger
+}
+
+func NewWorkService(store workStore, notify chapterNotifier, now func() time.Time, log *logger.Logger) *WorkService {
+	if now == nil {
+		now = time.Now
+	}
+	return &WorkService{store: store, notify: notify, now: now, log: log}
+}
+
+// Create starts a new draft work for the user.
+func (s *WorkService) Create(ctx context.Context, userID, username string, req domain.WorkRequest) (*domain.Fanfic, error) {
+	f := &domain.Fanfic{
+		UserID:         userID,
+		AuthorUsername: username,
+		Origin:         domain.OriginHuman,
+		Status:         domain.StatusDraft,
+		Length:         domain.LengthForWords(0),
+		PartCount:      0,
+	}
+	applyWorkRequest(f, req)
+	if err := s.store.CreateWork(ctx, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Update replaces the metadata of the user's work.
+func (s *WorkService) Update(ctx context.Context, userID, id string, req domain.WorkRequest) (*domain.Fanfic, error) {
+	f, err := s.owned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	applyWorkRequest(f, req)
+	if err := s.store.UpdateWorkMeta(ctx, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Get returns a work with the chapters the viewer may read. Authors see
+// their drafts and scheduled chapters; everyone else sees public works and
+// published chapters only, and a non-public work does not exist for them.
+// viewerID may be empty.
+func (s *WorkService) Get(ctx context.Context, viewerID, id string) (*domain.WorkDetail, error) {
+	f, err := s.visible(ctx, viewerID, id)
+	if err != nil {
+		return nil, err
+	}
+	detail := &domain.WorkDetail{Fanfic: *f, Chapters: []domain.Chapter{}}
+	if f.Origin == domain.OriginHuman {
+		chapters, err := s.store.Chapters(ctx, f.ID, f.UserID != viewerID)
+		if err != nil {
+			return nil, err
+		}
+		if chapters != nil {
+			detail.Chapters = chapters
+		}
+	}
+	if viewerID != "" {
+		detail.Kudosed, detail.Bookmarked, detail.Subscribed, err = s.store.ViewerState(ctx, viewerID, f.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return detail, nil
+}
+
+// AddChapter appends a draft chapter to the user's work.
+func (s *WorkService) AddChapter(ctx context.Context, userID, workID string, req domain.ChapterRequest) (*domain.Chapter, error) {
+	if _, err := s.owned(ctx, userID, workID); err != nil {
+		return nil, err
+	}
+	existing, err := s.store.Chapters(ctx, workID, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxChaptersPerWork {
+		return nil, liberrors.InvalidInput("chapter limit reached")
+	}
+	c := &domain.Chapter{
+		FanficID:  workID,
+		Title:     strings.TrimSpace(req.Title),
+		Content:   req.Content,
+		WordCount: domain.CountWords(req.Content),
+		Status:    domain.StatusDraft,
+	}
+	if err := s.store.CreateChapter(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UpdateChapter edits a chapter's title and text. Editing a published
+// chapter keeps it published and refreshes the work's word count.
+func (s *WorkService) UpdateChapter(ctx context.Context, userID, workID, chapterID string, req domain.ChapterRequest) (*domain.Chapter, error) {
+	c, err := s.ownedChapter(ctx, userID, workID, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	c.Title = strings.TrimSpace(req.Title)
+	c.Content = req.Content
+	c.WordCount = domain.CountWords(req.Content)
+	if err := s.store.UpdateChapter(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DeleteChapter removes a chapter of the user's work.
+func (s *WorkService) DeleteChapter(ctx context.Context, userID, workID, chapterID string) error {
+	c, err := s.ownedChapter(ctx, userID, workID, chapterID)
+	if err != nil {
+		return err
+	}
+	return s.store.DeleteChapter(ctx, c)
+}
+
+// PublishChapter publishes a chapter now, or schedules it when req.PublishAt
+// is in the future. Publishing the first chapter publishes the work; every
+// publication notifies the work's subscribers.
+func (s *WorkService) PublishChapter(ctx context.Context, userID, workID, chapterID string, req domain.PublishRequest) (*domain.Chapter, error) {
+	c, err := s.ownedChapter(ctx, userID, workID, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status == domain.StatusPublished {
+		return nil, liberrors.New(liberrors.CodeConflict, "chapter is already published")
+	}
+	if strings.TrimSpace(c.Content) == "" {
+		return nil, liberrors.InvalidInput("chapter is empty")
+	}
+	now := s.now()
+	if req.PublishAt != nil && req.PublishAt.After(now) {
+		at := req.PublishAt.UTC()
+		c.Status = domain.StatusScheduled
+		c.PublishAt = &at
+		if err := s.store.UpdateChapter(ctx, c); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	ok, err := s.publish(ctx, c, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, liberrors.New(liberrors.CodeConflict, "chapter is already published")
+	}
+	return c, nil
+}
+
+// Unschedule turns a scheduled chapter back into a draft.
+func (s *WorkService) Unschedule(ctx context.Context, userID, workID, chapterID string) (*domain.Chapter, error) {
+	c, err := s.ownedChapter(ctx, userID, workID, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != domain.StatusScheduled {
+		return nil, liberrors.New(liberrors.CodeConflict, "chapter is not scheduled")
+	}
+	c.Status = domain.StatusDraft
+	c.PublishAt = nil
+	if err := s.store.UpdateChapter(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// PublishDue publishes every scheduled chapter whose time has come and
+// returns how many it published. A chapter another publisher got to first
+// (or that was unscheduled meanwhile) is skipped without a notification.
+func (s *WorkService) PublishDue(ctx context.Context) (int, error) {
+	due, err := s.store.DueChapters(ctx, s.now(), publishBatch)
+	if err != nil {
+		return 0, err
+	}
+	published := 0
+	for i := range due {
+		c := &due[i]
+		at := s.now()
+		if c.PublishAt != nil {
+			at = *c.PublishAt
+		}
+		ok, err := s.publish(ctx, c, at)
+		if err != nil {
+			if s.log != nil {
+				s.log.Errorw("failed to publish scheduled chapter", "chapter_id", c.ID, "fanfic_id", c.FanficID, "error", err)
+			}
+			continue
+		}
+		if ok {
+			published++
+		}
+	}
+	return published, nil
+}
+
+// Run publishes due chapters every interval until ctx is done.
+func (s *WorkService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.PublishDue(ctx); err != nil {
+				if s.log != nil {
+					s.log.Warnw("scheduled chapter publishing failed", "error", err)
+				}
+			} else if n > 0 && s.log != nil {
+				s.log.Infow("published scheduled chapters", "count", n)
+			}
+		}
+	}
+}
+
+// publish moves c from its loaded status to published and notifies the
+// subscribers. It reports false, without notifying, when the chapter changed
+// status since it was loaded — someone else published it first.
+func (s *WorkService) publish(ctx context.Context, c *domain.Chapter, at time.Time) (bool, error) {
+	at = at.UTC()
+	from := c.Status
+	c.Status = domain.StatusPublished
+	c.PublishAt = nil
+	c.PublishedAt = &at
+	ok, err := s.store.PublishChapter(ctx, c, from)
+	if err != nil || !ok {
+		return false, err
+	}
+	if s.notify == nil {
+		return true, nil
+	}
+	work, err := s.store.GetWork(ctx, c.FanficID)
+	if err != nil {
+		return true, nil // the chapter is out; a lookup failure only costs the notification
+	}
+	subs, err := s.store.Subscribers(ctx, c.FanficID)
+	if err != nil {
+		if s.log != nil {
+			s.log.Warnw("failed to load chapter subscribers", "fanfic_id", c.FanficID, "error", err)
+		}
+		return true, nil
+	}
+	recipients := subs[:0]
+	for _, uid := range subs {
+		if uid != work.UserID {
+			recipients = append(recipients, uid)
+		}
+	}
+	if len(recipients) > 0 {
+		s.notify.NotifyChapter(ctx, work, c, recipients)
+	}
+	return true, nil
+}
+
+// Search lists public works, human and generated, matching p.
+func (s *WorkService) Search(ctx context.Context, p domain.SearchParams) ([]domain.Fanfic, int64, error) {
+	if p.Rating != "" && !domain.ValidSearchRating(p.Rating) {
+		return nil, 0, liberrors.InvalidInput("invalid rating")
+	}
+	if p.Length != "" && !domain.ValidSearchLength(p.Length) {
+		return nil, 0, liberrors.InvalidInput("invalid length")
+	}
+	if p.Origin != "" && p.Origin != domain.OriginHuman && p.Origin != domain.OriginGenerated {
+		return nil, 0, liberrors.InvalidInput("origin must be human or generated")
+	}
+	if p.Sort != "" && p.Sort != domain.SortRecent && p.Sort != domain.SortKudos {
+		return nil, 0, liberrors.InvalidInput("sort must be recent or kudos")
+	}
+	p.Query = strings.TrimSpace(p.Query)
+	p.Tags = domain.NormalizeTags(p.Tags)
+	return s.store.Search(ctx, p)
+}
+
+// Kudos leaves the user's kudos on a public work; repeats are no-ops.
+// Authors cannot kudos their own work.
+func (s *WorkService) Kudos(ctx context.Context, userID, id string) error {
+	f, err := s.visible(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if f.UserID == userID {
+		return liberrors.InvalidInput("cannot leave kudos on your own work")
+	}
+	_, err = s.store.AddKudos(ctx, f.ID, userID)
+	return err
+}
+
+// Bookmark saves a visible work to the user's bookmarks (or updates the note).
+func (s *WorkService) Bookmark(ctx context.Context, userID, id, note string) error {
+	f, err := s.visible(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	return s.store.SetBookmark(ctx, &domain.Bookmark{UserID: userID, FanficID: f.ID, Note: strings.TrimSpace(note)})
+}
+
+// Unbookmark removes a work from the user's bookmarks.
+func (s *WorkService) Unbookmark(ctx context.Context, userID, id string) error {
+	return s.store.DeleteBookmark(ctx, userID, id)
+}
+
+// Bookmarks lists the user's bookmarked works.
+func (s *WorkService) Bookmarks(ctx context.Context, userID string, limit, offset int) ([]domain.BookmarkedWork, int64, error) {
+	return s.store.Bookmarks(ctx, userID, limit, offset)
+}
+
+// Subscribe subscribes the user to new chapters of a public human work.
+func (s *WorkService) Subscribe(ctx context.Context, userID, id string) error {
+	f, err := s.visible(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if f.Origin != domain.OriginHuman {
+		return liberrors.InvalidInput("only human-authored works have chapter updates")
+	}
+	return s.store.Subscribe(ctx, userID, f.ID)
+}
+
+// Unsubscribe stops chapter notifications for a work.
+func (s *WorkService) Unsubscribe(ctx context.Context, userID, id string) error {
+	return s.store.Unsubscribe(ctx, userID, id)
+}
+
+func (s *WorkService) visible(ctx context.Context, viewerID, id string) (*domain.Fanfic, error) {
+	f, err := s.store.GetWork(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if f.UserID != viewerID && !f.IsPublic() {
+		return nil, liberrors.NotFound("work")
+	}
+	return f, nil
+}
+
+func (s *WorkService) owned(ctx context.Context, userID, id string) (*domain.Fanfic, error) {
+	f, err := s.store.GetWork(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if f.UserID != userID || f.Origin != domain.OriginHuman {
+		return nil, liberrors.NotFound("work")
+	}
+	return f, nil
+}
+
+func (s *WorkService) ownedChapter(ctx context.Context, userID, workID, chapterID string) (*domain.Chapter, error) {
+	if _, err := s.owned(ctx, userID, workID); err != nil {
+		return nil, err
+	}
+	return s.store.GetChapter(ctx, workID, chapterID)
+}
+
+// applyWorkRequest copies a validated WorkRequest onto a work.
+func applyWorkRequest(f *domain.Fanfic, req domain.WorkRequest) {
+	chars := req.Characters
+	if chars == nil {
+		chars = []domain.CharacterRef{}
+	}
+	charsJSON, _ := json.Marshal(chars)
+	tagsJSON, _ := json.Marshal(domain.NormalizeTags(req.Tags))
+	f.AnimeID = req.Anime.ID
+	f.AnimeShikimoriID = req.Anime.ShikimoriID
+	f.AnimeTitle = req.Anime.Title
+	f.AnimeJapanese = req.Anime.Japanese
+	f.AnimePoster = req.Anime.Poster
+	f.Title = strings.TrimSpace(req.Title)
+	f.Summary = strings.TrimSpace(req.Summary)
+	f.Characters = datatypes.JSON(charsJSON)
+	f.Tags = datatypes.JSON(tagsJSON)
+	f.Rating = req.Rating
+	f.Language = req.Language
+}
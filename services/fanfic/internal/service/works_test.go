@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	liberrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/fanfic/internal/repo"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// fakeChapterNotifier records NotifyChapter calls.
+type fakeChapterNotifier struct {
+	chapters   []string
+	recipients [][]string
+}
+
+func (n *fakeChapterNotifier) NotifyChapter(_ context.Context, _ *domain.Fanfic, c *domain.Chapter, userIDs []string) {
+	n.chapters = append(n.chapters, c.ID)
+	n.recipients = append(n.recipients, append([]string(nil), userIDs...))
+}
+
+// newTestWorkService wires WorkService to the real WorkRepository on
+// in-memory SQLite (the repo-test convention) with a settable clock.
+func newTestWorkService(t *testing.T) (*WorkService, *fakeChapterNotifier, *time.Time) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.Fanfic{}, &domain.Chapter{}, &domain.Kudos{}, &domain.Bookmark{}, &domain.Subscription{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	notifier := &fakeChapterNotifier{}
+	svc := NewWorkService(repo.NewWorkRepository(db), notifier, func() time.Time { return now }, nil)
+	return svc, notifier, &now
+}
+
+func workReq() domain.WorkRequest {
+	return domain.WorkRequest{
+		Anime:    domain.AnimeRef{Title: "Frieren"},
+		Title:    "Тихий вечер",
+		Tags:     []string{"Slow Burn", "свои теги"},
+		Rating:   "teen",
+		Language: "ru",
+	}
+}
+
+func isCode(err error, code liberrors.ErrorCode) bool {
+	appErr, ok := liberrors.IsAppError(err)
+	return ok && appErr.Code == code
+}
+
+func TestWorkService_DraftIsPrivateUntilPublished(t *testing.T) {
+	svc, notifier, _ := newTestWorkService(t)
+	ctx := context.Background()
+
+	w, err := svc.Create(ctx, "author", "writer", workReq())
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if w.Origin != domain.OriginHuman || w.Status != domain.StatusDraft {
+		t.Fatalf("new work = origin %q status %q; want human draft", w.Origin, w.Status)
+	}
+	if string(w.Tags) != `["slow-burn","свои теги"]` {
+		t.Errorf("Tags = %s; want curated tag normalized to its slug", w.Tags)
+	}
+	if _, err := svc.Get(ctx, "reader", w.ID); !isCode(err, liberrors.CodeNotFound) {
+		t.Fatalf("reader Get on draft = %v; want NotFound", err)
+	}
+
+	c, err := svc.AddChapter(ctx, "author", w.ID, domain.ChapterRequest{Title: "Глава 1", Content: "жили-были"})
+	if err != nil {
+		t.Fatalf("AddChapter: %v", err)
+	}
+	if _, err := svc.AddChapter(ctx, "reader", w.ID, domain.ChapterRequest{Content: "x"}); !isCode(err, liberrors.CodeNotFound) {
+		t.Errorf("non-author AddChapter = %v; want NotFound", err)
+	}
+	if _, err := svc.PublishChapter(ctx, "author", w.ID, c.ID, domain.PublishRequest{}); err != nil {
+		t.Fatalf("PublishChapter: %v", err)
+	}
+	if len(notifier.chapters) != 0 {
+		t.Errorf("notified %d times without subscribers; want 0", len(notifier.chapters))
+	}
+
+	detail, err := svc.Get(ctx, "reader", w.ID)
+	if err != nil {
+		t.Fatalf("reader Get after publish: %v", err)
+	}
+	if detail.Status != domain.StatusPublished || len(detail.Chapters) != 1 {
+		t.Errorf("detail = status %q chapters %d; want published/1", detail.Status, len(detail.Chapters))
+	}
+}
+
+func TestWorkService_ScheduledChapterPublishesAndNotifies(t *testing.T) {
+	svc, notifier, now := newTestWorkService(t)
+	ctx := context.Background()
+
+	w, _ := svc.Create(ctx, "author", "writer", workReq())
+	c1, _ := svc.AddChapter(ctx, "author", w.ID, domain.ChapterRequest{Content: "one"})
+	if _, err := svc.PublishChapter(ctx, "author", w.ID, c1.ID, domain.PublishRequest{}); err != nil {
+		t.Fatalf("publish c1: %v", err)
+	}
+	if err := svc.Subscribe(ctx, "reader", w.ID); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := svc.Subscribe(ctx, "author", w.ID); err != nil {
+		t.Fatalf("author Subscribe: %v", err)
+	}
+
+	c2, _ := svc.AddChapter(ctx, "author", w.ID, domain.ChapterRequest{Content: "two"})
+	at := now.Add(2 * time.Hour)
+	got, err := svc.PublishChapter(ctx, "author", w.ID, c2.ID, domain.PublishRequest{PublishAt: &at})
+	if err != nil {
+		t.Fatalf("schedule c2: %v", err)
+	}
+	if got.Status != domain.StatusScheduled {
+		t.Fatalf("c2 status = %q; want scheduled", got.Status)
+	}
+	detail, _ := svc.Get(ctx, "reader", w.ID)
+	if len(detail.Chapters) != 1 {
+		t.Errorf("reader sees %d chapters before the schedule; want 1", len(detail.Chapters))
+	}
+
+	if n, err := svc.PublishDue(ctx); err != nil || n != 0 {
+		t.Fatalf("early PublishDue = %d, %v; want 0", n, err)
+	}
+	*now = now.Add(3 * time.Hour)
+	if n, err := svc.PublishDue(ctx); err != nil || n != 1 {
+		t.Fatalf("PublishDue = %d, %v; want 1", n, err)
+	}
+
+	detail, _ = svc.Get(ctx, "reader", w.ID)
+	if len(detail.Chapters) != 2 || detail.PartCount != 2 {
+		t.Errorf("after schedule: chapters %d parts %d; want 2/2", len(detail.Chapters), detail.PartCount)
+	}
+	if !detail.Subscribed {
+		t.Error("expected reader's Subscribed flag")
+	}
+	if len(notifier.chapters) != 1 || notifier.chapters[0] != c2.ID {
+		t.Fatalf("notified chapters = %v; want [c2]", notifier.chapters)
+	}
+	if r := notifier.recipients[0]; len(r) != 1 || r[0] != "reader" {
+		t.Errorf("recipients = %v; want [reader] (the author is skipped)", r)
+	}
+}
+
+// staleDueStore replays a DueChapters snapshot, standing in for a second
+// replica that listed the same due chapter before the first published it.
+type staleDueStore struct {
+	*repo.WorkRepository
+	due []domain.Chapter
+}
+
+func (s *staleDueStore) DueChapters(context.Context, time.Time, int) ([]domain.Chapter, error) {
+	return append([]domain.Chapter(nil), s.due...), nil
+}
+
+func TestWorkService_ScheduledChapterPublishesOnce(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.Fanfic{}, &domain.Chapter{}, &domain.Kudos{}, &domain.Bookmark{}, &domain.Subscription{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	store := repo.NewWorkRepository(db)
+	notifier := &fakeChapterNotifier{}
+	svc := NewWorkService(store, notifier, clock, nil)
+	ctx := context.Background()
+
+	w, _ := svc.Create(ctx, "author", "writer", workReq())
+	c1, _ := svc.AddChapter(ctx, "author", w.ID, domain.ChapterRequest{Content: "one"})
+	if _, err := svc.PublishChapter(ctx, "author", w.ID, c1.ID, domain.PublishRequest{}); err != nil {
+		t.Fatalf("publish c1: %v", err)
+	}
+	if err := svc.Subscribe(ctx, "reader", w.ID); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	c, _ := svc.AddChapter(ctx, "author", w.ID, domain.ChapterRequest{Content: "two"})
+	at := now.Add(time.Hour)
+	if _, err := svc.PublishChapter(ctx, "author", w.ID, c.ID, domain.PublishRequest{PublishAt: &at}); err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	now = now.Add(2 * time.Hour)
+	due, err := store.DueChapters(ctx, now, 10)
+	if err != nil || len(due) != 1 {
+		t.Fatalf("DueChapters = %d, %v; want 1", len(due), err)
+	}
+
+	if n, err := svc.PublishDue(ctx); err != nil || n != 1 {
+		t.Fatalf("PublishDue = %d, %v; want 1", n, err)
+	}
+	replica := NewWorkService(&staleDueStore{WorkRepository: store, due: due}, notifier, clock, nil)
+	if n, err := replica.PublishDue(ctx); err != nil || n != 0 {
+		t.Fatalf("second PublishDue = %d, %v; want 0", n, err)
+	}
+	if len(notifier.chapters) != 1 {
+		t.Errorf("notified %d times; want once", len(notifier.chapters))
+	}
+	if _, err := svc.PublishChapter(ctx, "author", w.ID, c.ID, domain.PublishRequest{}); !isCode(err, liberrors.CodeConflict) {
+		t.Errorf("publishing again = %v; want conflict", err)
+	}
+}
+
+func TestWorkService_ReaderActions(t *testing.T) {
+	svc, _, _ := newTestWorkService(t)
+	ctx := context.Background()
+
+	w, _ := svc.Create(ctx, "author", "writer", workReq())
+	c, _ := svc.AddChapter(ctx, "author", w.ID, domain.ChapterRequest{Content: "text"})
+	if _, err := svc.PublishChapter(ctx, "author", w.ID, c.ID, domain.PublishRequest{}); err != nil {
+		t.Fatalf("PublishChapter: %v", err)
+	}
+
+	if err := svc.Kudos(ctx, "author", w.ID); !isCode(err, liberrors.CodeInvalidInput) {
+		t.Errorf("author Kudos = %v; want InvalidInput", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := svc.Kudos(ctx, "reader", w.ID); err != nil {
+			t.Fatalf("Kudos: %v", err)
+		}
+	}
+	if err := svc.Bookmark(ctx, "reader", w.ID, "  перечитать  "); err != nil {
+		t.Fatalf("Bookmark: %v", err)
+	}
+
+	detail, _ := svc.Get(ctx, "reader", w.ID)
+	if detail.KudosCount != 1 || !detail.Kudosed || !detail.Bookmarked {
+		t.Errorf("detail = kudos %d kudosed %v bookmarked %v; want 1/true/true", detail.KudosCount, detail.Kudosed, detail.Bookmarked)
+	}
+	items, total, err := svc.Bookmarks(ctx, "reader", 10, 0)
+	if err != nil || total != 1 || items[0].Note != "перечитать" {
+		t.Errorf("Bookmarks = %+v, %d, %v; want one trimmed note", items, total, err)
+	}
+}
+
+func TestWorkService_SearchValidation(t *testing.T) {
+	svc, _, _ := newTestWorkService(t)
+	ctx := context.Background()
+	for name, p := range map[string]domain.SearchParams{
+		"rating": {Rating: "nsfw"},
+		"length": {Length: "epic"},
+		"origin": {Origin: "robot"},
+		"sort":   {Sort: "random"},
+	} {
+		if _, _, err := svc.Search(ctx, p); !isCode(err, liberrors.CodeInvalidInput) {
+			t.Errorf("%s: Search = %v; want InvalidInput", name, err)
+		}
+	}
+	if _, _, err := svc.Search(ctx, domain.SearchParams{Length: domain.LengthLong, Limit: 10}); err != nil {
+		t.Errorf("long length should be searchable, got %v", err)
+	}
+}
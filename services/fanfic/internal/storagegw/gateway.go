@@ -0,0 +1,69 @@
+// Package storagegw is the fanfic service's single adapter over
+// libs/storageclient — the same thin-adapter pattern as
+// services/library/internal/storagegw and services/upscaler/internal/storagegw.
+// Rendered EPUB/PDF exports and narrated audio (plus the narration jobs'
+// in-progress chunks) are user content of class "fanfic-export", whose
+// destination backend the storage service resolves
+// (STORAGE_CLASS_FANFIC_EXPORT, minio by default). It holds no object-store
+// credentials.
+package storagegw
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/storageclient"
+)
+
+// ClassFanficExport is the storage-service placement class for rendered
+// exports and narrations. Mirrors services/storage/internal/domain.ClassFanficExport (the
+// storage service is a separate module; the string is the wire contract).
+const ClassFanficExport = "fanfic-export"
+
+// Gateway wraps a *storageclient.Client.
+type Gateway struct {
+	client *storageclient.Client
+}
+
+// New constructs a Gateway.
+func New(client *storageclient.Client) *Gateway {
+	return &Gateway{client: client}
+}
+
+// Upload PUTs the rendered file at path to {prefix}{basename} on the backend
+// the storage service resolves for ClassFanficExport, returning that backend
+// id so the export row records where the file landed.
+func (g *Gateway) Upload(ctx context.Context, prefix, path string) (string, error) {
+	return g.client.UploadFiles(ctx, ClassFanficExport, "", prefix, []string{path}, 1)
+}
+
+// DownloadURL returns a presigned GET URL for exactly key on backend storage.
+// The download handler fetches it server-side and streams the bytes (MinIO's
+// presigned host is internal-only).
+func (g *Gateway) DownloadURL(ctx context.Context, storage, key string) (string, error) {
+	urls, err := g.client.DownloadURLs(ctx, storage, key)
+	if err != nil {
+		return "", err
+	}
+	for _, u := range urls {
+		// Names come back relative to the requested prefix: "" for an exact
+		// match, or a basename-style tail.
+		if key == u.Name || strings.HasSuffix(key, u.Name) {
+			return u.URL, nil
+		}
+	}
+	return "", errors.NotFound("export file")
+}
+
+// DownloadPrefix fetches every object under prefix on backend storage into
+// dir — a narration job collecting its synthesized chunks.
+func (g *Gateway) DownloadPrefix(ctx context.Context, storage, prefix, dir string) error {
+	return g.client.DownloadPrefix(ctx, storage, prefix, dir)
+}
+
+// DeletePrefix removes every object under prefix on backend storage.
+func (g *Gateway) DeletePrefix(ctx context.Context, storage, prefix string) error {
+	_, err := g.client.DeletePrefix(ctx, storage, prefix)
+	return err
+}
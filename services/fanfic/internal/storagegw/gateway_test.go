@@ -0,0 +1,70 @@
+package storagegw
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ILITA-hub/animeenigma/libs/storageclient"
+)
+
+type fakeEnvelope struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// TestGateway_UploadThenDownloadURL routes an export through class
+// "fanfic-export" and resolves the stored key back to its presigned URL.
+func TestGateway_UploadThenDownloadURL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.epub")
+	if err := os.WriteFile(path, []byte("epub"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotClass, gotPrefix, gotType string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/internal/storage/ingest-urls", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Class  string   `json:"class"`
+			Prefix string   `json:"prefix"`
+			Files  []string `json:"files"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		gotClass, gotPrefix = req.Class, req.Prefix
+		_ = json.NewEncoder(w).Encode(fakeEnvelope{Success: true, Data: map[string]interface{}{
+			"storage": "minio",
+			"urls":    []map[string]string{{"name": req.Files[0], "put_url": "http://" + r.Host + "/put"}},
+		}})
+	})
+	mux.HandleFunc("/put", func(w http.ResponseWriter, r *http.Request) {
+		gotType = r.Header.Get("Content-Type")
+	})
+	mux.HandleFunc("/internal/storage/download-urls", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(fakeEnvelope{Success: true, Data: map[string]interface{}{
+			"urls": []map[string]string{{"name": "", "get_url": "http://get/book.epub"}},
+		}})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	g := New(storageclient.New(srv.URL))
+	storage, err := g.Upload(context.Background(), "fanfic-exports/abc/", path)
+	if err != nil || storage != "minio" {
+		t.Fatalf("Upload = %q, %v", storage, err)
+	}
+	if gotClass != ClassFanficExport || gotPrefix != "fanfic-exports/abc/" {
+		t.Errorf("ingest class/prefix = %q %q", gotClass, gotPrefix)
+	}
+	if gotType != "application/epub+zip" {
+		t.Errorf("PUT Content-Type = %q", gotType)
+	}
+
+	url, err := g.DownloadURL(context.Background(), storage, "fanfic-exports/abc/book.epub")
+	if err != nil || url != "http://get/book.epub" {
+		t.Errorf("DownloadURL = %q, %v", url, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/database"
 	"github.com/ILITA-hub/animeenigma/libs/httputil"
 	"github.com/ILITA-hub/animeenigma/libs/logger"
 	"github.com/ILITA-hub/animeenigma/libs/metrics"
@@ -21,15 +22,41 @@ import (
 //	POST   /api/fanfic/{id}/continue   (JWT) — SSE, appends next part
 //	GET    /api/fanfic                 (JWT) — list
 //	GET    /api/fanfic/tags            (JWT) — curated tags (registered before /{id})
+//	GET    /api/fanfic/works           (JWT) — search public works, human + generated
+//	POST   /api/fanfic/works           (JWT) — start a human-authored draft work
+//	GET    /api/fanfic/works/{id}      (JWT) — work + readable chapters + viewer state
+//	PUT    /api/fanfic/works/{id}      (JWT, author) — edit metadata
+//	POST   /api/fanfic/works/{id}/chapters                          (JWT, author)
+//	PUT    /api/fanfic/works/{id}/chapters/{chapterID}              (JWT, author)
+//	DELETE /api/fanfic/works/{id}/chapters/{chapterID}              (JWT, author)
+//	POST   /api/fanfic/works/{id}/chapters/{chapterID}/publish      (JWT, author) — now or scheduled
+//	DELETE /api/fanfic/works/{id}/chapters/{chapterID}/publish      (JWT, author) — unschedule
+//	POST   /api/fanfic/works/{id}/kudos                             (JWT)
+//	PUT    /api/fanfic/works/{id}/bookmark | DELETE                 (JWT)
+//	PUT    /api/fanfic/works/{id}/subscription | DELETE             (JWT) — chapter notifications
+//	GET    /api/fanfic/bookmarks       (JWT) — the caller's bookmarks
+//	POST   /api/fanfic/bookmarks/export        (JWT) — EPUB/PDF of the caller's bookmarks
+//	POST   /api/fanfic/{id}/export             (JWT) — EPUB/PDF of a readable fanfic; 202 until rendered
+//	GET    /api/fanfic/exports/{id}            (JWT) — export status
+//	GET    /api/fanfic/exports/{id}/download   (JWT) — the rendered file
+//	GET    /api/fanfic/narration/speakers      (JWT) — VOICEVOX voices; 503 while the platform sheds
+//	POST   /api/fanfic/{id}/narration          (JWT) — Opus/MP3 narration of a Japanese fanfic; 202 until encoded
+//	GET    /api/fanfic/narrations/{id}         (JWT) — job status with chunk progress
+//	POST   /api/fanfic/narrations/{id}/resume  (JWT) — requeue a failed/paused job from its last chunk
+//	GET    /api/fanfic/narrations/{id}/download (JWT) — the encoded audio
 //	GET    /api/fanfic/{id}            (JWT)
 //	DELETE /api/fanfic/{id}            (JWT)
 //	GET    /internal/fanfic/daily          (docker-network only, no JWT) — compact spotlight DTO
 //	POST   /internal/fanfic/ensure-daily   (docker-network only, no JWT) — scheduler cron hook
+//	POST   /internal/account/export        (docker-network only, no JWT) — auth account fan-out
+//	POST   /internal/account/erase         (docker-network only, no JWT) — auth account fan-out
 //
 // dh may be nil (e.g. a caller that hasn't wired DailyService yet); the three
 // daily/internal routes are only registered when it's non-nil, so a nil dh
-// degrades to those routes 404ing instead of panicking.
-func NewRouter(h *handler.Handler, dh *handler.DailyHandler, jwtConfig authz.JWTConfig, log *logger.Logger, mc *metrics.Collector) http.Handler {
+// degrades to those routes 404ing instead of panicking. ah is nil-guarded the
+// same way, and so are wh (the human-authored works routes), eh (exports) and
+// nh (narrations).
+func NewRouter(h *handler.Handler, wh *handler.WorkHandler, eh *handler.ExportHandler, nh *handler.NarrationHandler, dh *handler.DailyHandler, ah *database.UserDataHandler, jwtConfig authz.JWTConfig, log *logger.Logger, mc *metrics.Collector) http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
@@ -63,12 +90,54 @@ func NewRouter(h *handler.Handler, dh *handler.DailyHandler, jwtConfig authz.JWT
 		r.Post("/internal/fanfic/ensure-daily", dh.Ensure)
 	}
 
+	// Account export + erasure — the auth service's fan-out target when a
+	// user downloads their data or deletes their account. Internal like the
+	// daily routes above.
+	if ah != nil {
+		r.Post("/internal/account/export", ah.Export)
+		r.Post("/internal/account/erase", ah.Erase)
+	}
+
 	r.Route("/api/fanfic", func(r chi.Router) {
 		r.Use(AuthMiddleware(jwtConfig))
 		r.Post("/generate", h.Generate)
 		r.Post("/{id}/continue", h.Continue)
 		r.Get("/", h.List)
 		r.Get("/tags", h.Tags) // must be registered before /{id} or chi captures "tags" as the id param
+		r.Get("/models", h.Models)
+		if wh != nil {
+			// Static /works and /bookmarks segments win over /{id} in chi.
+			r.Get("/bookmarks", wh.Bookmarks)
+			r.Route("/works", func(r chi.Router) {
+				r.Get("/", wh.Search)
+				r.Post("/", wh.Create)
+				r.Get("/{id}", wh.Get)
+				r.Put("/{id}", wh.Update)
+				r.Post("/{id}/chapters", wh.AddChapter)
+				r.Put("/{id}/chapters/{chapterID}", wh.UpdateChapter)
+				r.Delete("/{id}/chapters/{chapterID}", wh.DeleteChapter)
+				r.Post("/{id}/chapters/{chapterID}/publish", wh.PublishChapter)
+				r.Delete("/{id}/chapters/{chapterID}/publish", wh.Unschedule)
+				r.Post("/{id}/kudos", wh.Kudos)
+				r.Put("/{id}/bookmark", wh.Bookmark)
+				r.Delete("/{id}/bookmark", wh.Unbookmark)
+				r.Put("/{id}/subscription", wh.Subscribe)
+				r.Delete("/{id}/subscription", wh.Unsubscribe)
+			})
+		}
+		if eh != nil {
+			r.Post("/bookmarks/export", eh.Bookmarks)
+			r.Post("/{id}/export", eh.Fanfic)
+			r.Get("/exports/{id}", eh.Get)
+			r.Get("/exports/{id}/download", eh.Download)
+		}
+		if nh != nil {
+			r.Get("/narration/speakers", nh.Speakers)
+			r.Post("/{id}/narration", nh.Request)
+			r.Get("/narrations/{id}", nh.Get)
+			r.Post("/narrations/{id}/resume", nh.Resume)
+			r.Get("/narrations/{id}/download", nh.Download)
+		}
 		r.Get("/{id}", h.Get)
 		r.Delete("/{id}", h.Delete)
 	})
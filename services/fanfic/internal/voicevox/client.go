@@ -0,0 +1,161 @@
+// Package voicevox is the fanfic service's client for the internal VOICEVOX
+// engine (services/voicevox). It mirrors the gateway's Zundamon facade
+// (services/gateway/internal/handler/zundamon.go) — the same bounded
+// audio_query → synthesis round trip and the same per-request rune cap — but
+// exposes every talk speaker, since narration lets the reader pick a voice.
+package voicevox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxRunes is the longest text one synthesis request may carry — the
+	// gateway facade's cap, kept so narration never queues heavier requests
+	// than interactive use does.
+	MaxRunes = 500
+
+	maxSpeakersBytes   = 2 << 20
+	maxAudioQueryBytes = 2 << 20
+	maxSynthesisBytes  = 16 << 20
+)
+
+// Style is one voice of a speaker; its ID is what the engine calls "speaker".
+type Style struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+// Speaker is a character with its styles.
+type Speaker struct {
+	Name   string  `json:"name"`
+	Styles []Style `json:"styles"`
+}
+
+// Voice is the synthesis settings of one request.
+type Voice struct {
+	StyleID int
+	Speed   float64 // speedScale, 1 = engine default
+	Pitch   float64 // pitchScale, 0 = engine default
+}
+
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+// New constructs a Client. timeout bounds each engine call; synthesis of a
+// full chunk on the engine's single CPU can take tens of seconds.
+func New(baseURL string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	return &Client{baseURL: strings.TrimRight(baseURL, "/"), client: &http.Client{Timeout: timeout}}
+}
+
+// Speakers lists the engine's talk speakers. Styles of other types (the
+// singing voices) cannot read text and are dropped, as are speakers left
+// without a style.
+func (c *Client) Speakers(ctx context.Context) ([]Speaker, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/speakers", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("speakers returned HTTP %d", resp.StatusCode)
+	}
+	var speakers []Speaker
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxSpeakersBytes)).Decode(&speakers); err != nil {
+		return nil, err
+	}
+	out := speakers[:0]
+	for _, s := range speakers {
+		styles := s.Styles[:0]
+		for _, st := range s.Styles {
+			if st.Type == "" || st.Type == "talk" {
+				styles = append(styles, st)
+			}
+		}
+		if len(styles) > 0 {
+			s.Styles = styles
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+// Synthesize reads text in voice and returns the engine's WAV.
+func (c *Client) Synthesize(ctx context.Context, text string, voice Voice) ([]byte, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || utf8.RuneCountInString(text) > MaxRunes {
+		return nil, fmt.Errorf("text must contain 1 to %d characters", MaxRunes)
+	}
+	speaker := strconv.Itoa(voice.StyleID)
+
+	queryResp, err := c.post(ctx, "/audio_query", url.Values{"text": {text}, "speaker": {speaker}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer queryResp.Body.Close()
+	if queryResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("audio_query returned HTTP %d", queryResp.StatusCode)
+	}
+	queryBytes, err := io.ReadAll(io.LimitReader(queryResp.Body, maxAudioQueryBytes+1))
+	if err != nil || len(queryBytes) > maxAudioQueryBytes {
+		return nil, errors.New("audio_query response is invalid or too large")
+	}
+	var audioQuery map[string]any
+	if err := json.Unmarshal(queryBytes, &audioQuery); err != nil {
+		return nil, err
+	}
+	if voice.Speed > 0 {
+		audioQuery["speedScale"] = voice.Speed
+	}
+	audioQuery["pitchScale"] = voice.Pitch
+	if queryBytes, err = json.Marshal(audioQuery); err != nil {
+		return nil, err
+	}
+
+	synthResp, err := c.post(ctx, "/synthesis", url.Values{"speaker": {speaker}}, queryBytes)
+	if err != nil {
+		return nil, err
+	}
+	defer synthResp.Body.Close()
+	if synthResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("synthesis returned HTTP %d", synthResp.StatusCode)
+	}
+	audio, err := io.ReadAll(io.LimitReader(synthResp.Body, maxSynthesisBytes+1))
+	if err != nil || len(audio) > maxSynthesisBytes {
+		return nil, errors.New("synthesis response is invalid or too large")
+	}
+	return audio, nil
+}
+
+func (c *Client) post(ctx context.Context, path string, params url.Values, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path+"?"+params.Encode(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "audio/wav")
+	}
+	return c.client.Do(req)
+}
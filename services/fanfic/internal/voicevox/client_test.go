@@ -0,0 +1,89 @@
+package voicevox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSpeakers_DropsSingingStyles(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/speakers" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`[
+			{"name":"ずんだもん","styles":[{"id":3,"name":"ノーマル","type":"talk"},{"id":3001,"name":"ハミング","type":"frame_decode"}]},
+			{"name":"波音リツ","styles":[{"id":6000,"name":"ノーマル","type":"singing_teacher"}]},
+			{"name":"四国めたん","styles":[{"id":2,"name":"ノーマル"}]}
+		]`))
+	}))
+	defer srv.Close()
+
+	got, err := New(srv.URL, time.Second).Speakers(context.Background())
+	if err != nil {
+		t.Fatalf("speakers: %v", err)
+	}
+	if len(got) != 2 || got[0].Name != "ずんだもん" || got[1].Name != "四国めたん" {
+		t.Fatalf("speakers = %+v", got)
+	}
+	if len(got[0].Styles) != 1 || got[0].Styles[0].ID != 3 {
+		t.Fatalf("zundamon styles = %+v", got[0].Styles)
+	}
+}
+
+func TestSynthesize_QueryThenSynthesis(t *testing.T) {
+	var synthesized map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/audio_query":
+			if r.URL.Query().Get("text") != "こんにちは。" || r.URL.Query().Get("speaker") != "3" {
+				t.Errorf("audio_query params = %v", r.URL.Query())
+			}
+			_, _ = w.Write([]byte(`{"accent_phrases":[],"speedScale":1.0,"pitchScale":0.0,"outputSamplingRate":24000}`))
+		case "/synthesis":
+			if r.URL.Query().Get("speaker") != "3" {
+				t.Errorf("synthesis speaker = %q", r.URL.Query().Get("speaker"))
+			}
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &synthesized)
+			_, _ = w.Write([]byte("RIFFwav"))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	audio, err := New(srv.URL, time.Second).Synthesize(context.Background(), " こんにちは。 ", Voice{StyleID: 3, Speed: 1.2, Pitch: 0.05})
+	if err != nil {
+		t.Fatalf("synthesize: %v", err)
+	}
+	if string(audio) != "RIFFwav" {
+		t.Fatalf("audio = %q", audio)
+	}
+	if synthesized["speedScale"] != 1.2 || synthesized["pitchScale"] != 0.05 || synthesized["outputSamplingRate"] != 24000.0 {
+		t.Fatalf("synthesis body = %v", synthesized)
+	}
+}
+
+func TestSynthesize_RejectsOversizedText(t *testing.T) {
+	c := New("http://127.0.0.1:1", time.Second)
+	if _, err := c.Synthesize(context.Background(), strings.Repeat("あ", MaxRunes+1), Voice{StyleID: 3}); err == nil {
+		t.Fatal("expected an error for text over MaxRunes")
+	}
+}
+
+func TestSynthesize_EngineError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}))
+	defer srv.Close()
+
+	if _, err := New(srv.URL, time.Second).Synthesize(context.Background(), "テスト", Voice{StyleID: 3}); err == nil {
+		t.Fatal("expected an error on HTTP 422")
+	}
+}
@@ -67,6 +67,9 @@ func main() {
 		&domain.Card{}, &domain.Group{}, &domain.CardGroup{},
 		&domain.Banner{}, &domain.BannerCard{},
 		&domain.CollectionEntry{}, &domain.PityCounter{},
+		&domain.TradeOffer{}, &domain.TradeItem{},
+		&domain.FairSeed{}, &domain.PullNonce{}, &domain.PullRecord{},
+		&domain.AchievementUnlock{}, &domain.AchievementReward{},
 	); err != nil {
 		log.Fatalw("failed to migrate database", "error", err)
 	}
@@ -107,15 +110,26 @@ func main() {
 	contentSvc := service.NewContentService(contentRepo, bannerRepo)
 	imageSvc := service.NewImageService(&storageAdapter{storage})
 	pullSvc := service.NewPullService(pullRepo, bannerRepo, contentRepo, cfg.Economy, service.NewSecureRand(), cfg.Enabled, log)
+	fairnessSvc := service.NewFairnessService(repo.NewFairnessRepository(db.DB), cfg.Economy.FairSeedPeriod, log)
+	pullSvc.SetFairness(fairnessSvc)
+	achievementSvc := service.NewAchievementService(repo.NewAchievementRepository(db.DB), pullRepo, contentRepo, cfg.Economy, cfg.Enabled, log)
+	walletSvc.SetAchievements(achievementSvc)
+	craftSvc := service.NewCraftService(repo.NewCraftRepository(db.DB), pullRepo, bannerRepo, contentRepo, cfg.Economy, cfg.Enabled, log)
+	tradeSvc := service.NewTradeService(repo.NewTradeRepository(db.DB), cfg.Economy, cfg.Enabled, log)
 
 	walletHandler := handler.NewWalletHandler(walletSvc, log)
 	internalHandler := handler.NewInternalHandler(walletSvc, log)
+	accountHandler := database.NewUserDataHandler(repo.NewAccountRepository(db.DB), log)
 	adminHandler := handler.NewAdminHandler(contentSvc, imageSvc, log)
 	imagesHandler := handler.NewImagesHandler(storage, log)
 	pullHandler := handler.NewPullHandler(pullSvc, log)
+	tradeHandler := handler.NewTradeHandler(tradeSvc, log)
+	craftHandler := handler.NewCraftHandler(craftSvc, log)
+	fairnessHandler := handler.NewFairnessHandler(fairnessSvc, log)
+	achievementHandler := handler.NewAchievementHandler(achievementSvc, log)
 
 	metricsCollector := metrics.NewCollector("gacha")
-	router := transport.NewRouter(walletHandler, internalHandler, adminHandler, imagesHandler, pullHandler, cfg.JWT, log, metricsCollector)
+	router := transport.NewRouter(walletHandler, internalHandler, accountHandler, adminHandler, imagesHandler, pullHandler, tradeHandler, craftHandler, fairnessHandler, achievementHandler, cfg.JWT, log, metricsCollector)
 
 	srv := &http.Server{
 		Addr:         cfg.Server.Address(),
@@ -54,6 +54,39 @@ type EconomyConfig struct {
 	WeightR       int   // GACHA_WEIGHT_R, default 22
 	WeightSR      int   // GACHA_WEIGHT_SR, default 8
 	WeightSSR     int   // GACHA_WEIGHT_SSR, default 1
+
+	// Soft pity: from pull SoftPityStart+1 on, every further pull without an
+	// SSR adds SoftPityStep to the SSR weight until hard pity. 0 disables.
+	SoftPityStart int // GACHA_SOFT_PITY_START, default 0 (off)
+	SoftPityStep  int // GACHA_SOFT_PITY_STEP, default 0 (off)
+
+	// Dismantle / craft knobs. Duplicates dismantle into shards at the
+	// per-rarity yield; a specific card crafts for the per-rarity cost.
+	DismantleYieldN   int64 // GACHA_DISMANTLE_YIELD_N, default 1
+	DismantleYieldR   int64 // GACHA_DISMANTLE_YIELD_R, default 5
+	DismantleYieldSR  int64 // GACHA_DISMANTLE_YIELD_SR, default 20
+	DismantleYieldSSR int64 // GACHA_DISMANTLE_YIELD_SSR, default 100
+	CraftCostN        int64 // GACHA_CRAFT_COST_N, default 5
+	CraftCostR        int64 // GACHA_CRAFT_COST_R, default 25
+	CraftCostSR       int64 // GACHA_CRAFT_COST_SR, default 100
+	CraftCostSSR      int64 // GACHA_CRAFT_COST_SSR, default 500
+
+	// Card trading knobs.
+	TradeSSRCooldown time.Duration // GACHA_TRADE_SSR_COOLDOWN, default 168h — an SSR can't be traded this long after it was obtained
+	TradeDailyCap    int           // GACHA_TRADE_DAILY_CAP, default 10 — offers made + offers accepted per UTC day
+
+	// Achievements: default «Энигмы» reward per kind (admins can override
+	// any single achievement) and the streak / pull-count targets.
+	AchievementRewardGroup    int64 // GACHA_ACH_REWARD_GROUP, default 100
+	AchievementRewardBanner   int64 // GACHA_ACH_REWARD_BANNER, default 300
+	AchievementRewardFirstSSR int64 // GACHA_ACH_REWARD_FIRST_SSR, default 100
+	AchievementRewardStreak   int64 // GACHA_ACH_REWARD_STREAK, default 500
+	AchievementRewardPulls    int64 // GACHA_ACH_REWARD_PULLS, default 200
+	AchievementStreakTarget   int   // GACHA_ACH_STREAK_TARGET, default 30 — consecutive daily claims
+	AchievementPullTarget     int   // GACHA_ACH_PULL_TARGET, default 100 — cards pulled
+
+	// Provably fair pulls: one committed seed per period, revealed after it.
+	FairSeedPeriod time.Duration // GACHA_FAIR_SEED_PERIOD, default 24h
 }
 
 func Load() (*Config, error) {
@@ -88,17 +121,38 @@ func Load() (*Config, error) {
 			RefreshTokenTTL: getEnvDuration("JWT_REFRESH_TTL", 7*24*time.Hour),
 		},
 		Economy: EconomyConfig{
-			StarterBonus:    int64(getEnvInt("GACHA_STARTER_BONUS", 300)),
-			DailyBase:       int64(getEnvInt("GACHA_DAILY_BASE", 50)),
-			DailyStreakStep: int64(getEnvInt("GACHA_DAILY_STREAK_STEP", 10)),
-			DailyStreakCap:  int64(getEnvInt("GACHA_DAILY_STREAK_CAP", 100)),
-			PullCostX1:      int64(getEnvInt("GACHA_PULL_COST_X1", 100)),
-			PullCostX10:   int64(getEnvInt("GACHA_PULL_COST_X10", 900)),
-			PityThreshold: getEnvInt("GACHA_PITY_THRESHOLD", 90),
-			WeightN:       getEnvInt("GACHA_WEIGHT_N", 69),
-			WeightR:       getEnvInt("GACHA_WEIGHT_R", 22),
-			WeightSR:      getEnvInt("GACHA_WEIGHT_SR", 8),
-			WeightSSR:     getEnvInt("GACHA_WEIGHT_SSR", 1),
+			StarterBonus:      int64(getEnvInt("GACHA_STARTER_BONUS", 300)),
+			DailyBase:         int64(getEnvInt("GACHA_DAILY_BASE", 50)),
+			DailyStreakStep:   int64(getEnvInt("GACHA_DAILY_STREAK_STEP", 10)),
+			DailyStreakCap:    int64(getEnvInt("GACHA_DAILY_STREAK_CAP", 100)),
+			PullCostX1:        int64(getEnvInt("GACHA_PULL_COST_X1", 100)),
+			PullCostX10:       int64(getEnvInt("GACHA_PULL_COST_X10", 900)),
+			PityThreshold:     getEnvInt("GACHA_PITY_THRESHOLD", 90),
+			WeightN:           getEnvInt("GACHA_WEIGHT_N", 69),
+			WeightR:           getEnvInt("GACHA_WEIGHT_R", 22),
+			WeightSR:          getEnvInt("GACHA_WEIGHT_SR", 8),
+			WeightSSR:         getEnvInt("GACHA_WEIGHT_SSR", 1),
+			SoftPityStart:     getEnvInt("GACHA_SOFT_PITY_START", 0),
+			SoftPityStep:      getEnvInt("GACHA_SOFT_PITY_STEP", 0),
+			DismantleYieldN:   int64(getEnvInt("GACHA_DISMANTLE_YIELD_N", 1)),
+			DismantleYieldR:   int64(getEnvInt("GACHA_DISMANTLE_YIELD_R", 5)),
+			DismantleYieldSR:  int64(getEnvInt("GACHA_DISMANTLE_YIELD_SR", 20)),
+			DismantleYieldSSR: int64(getEnvInt("GACHA_DISMANTLE_YIELD_SSR", 100)),
+			CraftCostN:        int64(getEnvInt("GACHA_CRAFT_COST_N", 5)),
+			CraftCostR:        int64(getEnvInt("GACHA_CRAFT_COST_R", 25)),
+			CraftCostSR:       int64(getEnvInt("GACHA_CRAFT_COST_SR", 100)),
+			CraftCostSSR:      int64(getEnvInt("GACHA_CRAFT_COST_SSR", 500)),
+			TradeSSRCooldown:  getEnvDuration("GACHA_TRADE_SSR_COOLDOWN", 7*24*time.Hour),
+			TradeDailyCap:     getEnvInt("GACHA_TRADE_DAILY_CAP", 10),
+			FairSeedPeriod:    getEnvDuration("GACHA_FAIR_SEED_PERIOD", 24*time.Hour),
+
+			AchievementRewardGroup:    int64(getEnvInt("GACHA_ACH_REWARD_GROUP", 100)),
+			AchievementRewardBanner:   int64(getEnvInt("GACHA_ACH_REWARD_BANNER", 300)),
+			AchievementRewardFirstSSR: int64(getEnvInt("GACHA_ACH_REWARD_FIRST_SSR", 100)),
+			AchievementRewardStreak:   int64(getEnvInt("GACHA_ACH_REWARD_STREAK", 500)),
+			AchievementRewardPulls:    int64(getEnvInt("GACHA_ACH_REWARD_PULLS", 200)),
+			AchievementStreakTarget:   getEnvInt("GACHA_ACH_STREAK_TARGET", 30),
+			AchievementPullTarget:     getEnvInt("GACHA_ACH_PULL_TARGET", 100),
 		},
 		Storage: videoutils.StorageConfig{
 			Endpoint:        getEnv("MINIO_ENDPOINT", "minio:9000"),
@@ -55,6 +55,8 @@ func TestLoad_PullDefaults(t *testing.T) {
 		{"WeightR", int64(e.WeightR), 22},
 		{"WeightSR", int64(e.WeightSR), 8},
 		{"WeightSSR", int64(e.WeightSSR), 1},
+		{"SoftPityStart", int64(e.SoftPityStart), 0},
+		{"SoftPityStep", int64(e.SoftPityStep), 0},
 	}
 	for _, c := range cases {
 		if c.got != c.want {
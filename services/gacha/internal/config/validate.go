@@ -1,6 +1,9 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Validate rejects nonsensical economy settings at boot (audit medium #19).
 // Without it, operator misconfig silently broke fairness/economy: PityThreshold<=0
@@ -12,6 +15,12 @@ func (c *Config) Validate() error {
 	if e.PityThreshold <= 0 {
 		return fmt.Errorf("GACHA_PITY_THRESHOLD must be > 0, got %d", e.PityThreshold)
 	}
+	if e.SoftPityStart < 0 || e.SoftPityStart >= e.PityThreshold {
+		return fmt.Errorf("GACHA_SOFT_PITY_START must be in [0, GACHA_PITY_THRESHOLD), got %d", e.SoftPityStart)
+	}
+	if e.SoftPityStep < 0 {
+		return fmt.Errorf("GACHA_SOFT_PITY_STEP must be >= 0, got %d", e.SoftPityStep)
+	}
 	if e.PullCostX1 < 0 || e.PullCostX10 < 0 {
 		return fmt.Errorf("GACHA_PULL_COST_X1/X10 must be >= 0, got %d/%d", e.PullCostX1, e.PullCostX10)
 	}
@@ -21,6 +30,34 @@ func (c *Config) Validate() error {
 	if e.StarterBonus < 0 || e.DailyBase < 0 {
 		return fmt.Errorf("GACHA_STARTER_BONUS and GACHA_DAILY_BASE must be >= 0, got %d/%d", e.StarterBonus, e.DailyBase)
 	}
+	yields := []int64{e.DismantleYieldN, e.DismantleYieldR, e.DismantleYieldSR, e.DismantleYieldSSR}
+	costs := []int64{e.CraftCostN, e.CraftCostR, e.CraftCostSR, e.CraftCostSSR}
+	for i, tier := range []string{"N", "R", "SR", "SSR"} {
+		if yields[i] < 0 {
+			return fmt.Errorf("GACHA_DISMANTLE_YIELD_%s must be >= 0, got %d", tier, yields[i])
+		}
+		// cost <= yield would let craft→dismantle loops mint shards.
+		if costs[i] <= yields[i] {
+			return fmt.Errorf("GACHA_CRAFT_COST_%s must be > GACHA_DISMANTLE_YIELD_%s, got %d/%d", tier, tier, costs[i], yields[i])
+		}
+	}
+	if e.TradeSSRCooldown < 0 {
+		return fmt.Errorf("GACHA_TRADE_SSR_COOLDOWN must be >= 0, got %s", e.TradeSSRCooldown)
+	}
+	if e.TradeDailyCap <= 0 {
+		return fmt.Errorf("GACHA_TRADE_DAILY_CAP must be > 0, got %d", e.TradeDailyCap)
+	}
+	for _, r := range []int64{e.AchievementRewardGroup, e.AchievementRewardBanner, e.AchievementRewardFirstSSR, e.AchievementRewardStreak, e.AchievementRewardPulls} {
+		if r < 0 {
+			return fmt.Errorf("GACHA_ACH_REWARD_* must be >= 0, got %d", r)
+		}
+	}
+	if e.AchievementStreakTarget <= 0 || e.AchievementPullTarget <= 0 {
+		return fmt.Errorf("GACHA_ACH_STREAK_TARGET and GACHA_ACH_PULL_TARGET must be > 0, got %d/%d", e.AchievementStreakTarget, e.AchievementPullTarget)
+	}
+	if e.FairSeedPeriod < time.Minute {
+		return fmt.Errorf("GACHA_FAIR_SEED_PERIOD must be >= 1m, got %s", e.FairSeedPeriod)
+	}
 	sum := 0
 	for _, w := range []int{e.WeightN, e.WeightR, e.WeightSR, e.WeightSSR} {
 		if w < 0 {
@@ -2,6 +2,7 @@ package config_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ILITA-hub/animeenigma/services/gacha/internal/config"
 )
@@ -9,8 +10,13 @@ import (
 func validEconomy() config.EconomyConfig {
 	return config.EconomyConfig{
 		StarterBonus: 300, DailyBase: 50, DailyStreakStep: 10, DailyStreakCap: 100,
-		PullCostX1: 100, PullCostX10: 900, PityThreshold: 90,
+		PullCostX1: 100, PullCostX10: 900, PityThreshold: 90, SoftPityStart: 74, SoftPityStep: 6,
 		WeightN: 69, WeightR: 22, WeightSR: 8, WeightSSR: 1,
+		DismantleYieldN: 1, DismantleYieldR: 5, DismantleYieldSR: 20, DismantleYieldSSR: 100,
+		CraftCostN: 5, CraftCostR: 25, CraftCostSR: 100, CraftCostSSR: 500,
+		TradeSSRCooldown: 7 * 24 * time.Hour, TradeDailyCap: 10,
+		AchievementStreakTarget: 30, AchievementPullTarget: 100,
+		FairSeedPeriod: 24 * time.Hour,
 	}
 }
 
@@ -28,6 +34,15 @@ func TestConfigValidate(t *testing.T) {
 		"negative daily base":                       func(e *config.EconomyConfig) { e.DailyBase = -1 },
 		"all weights zero (always top-of-pool)":     func(e *config.EconomyConfig) { e.WeightN, e.WeightR, e.WeightSR, e.WeightSSR = 0, 0, 0, 0 },
 		"negative weight":                           func(e *config.EconomyConfig) { e.WeightSSR = -1 },
+		"negative dismantle yield":                  func(e *config.EconomyConfig) { e.DismantleYieldR = -1 },
+		"craft cost <= yield (shard loop)":          func(e *config.EconomyConfig) { e.CraftCostSSR = 100 },
+		"negative trade SSR cooldown":               func(e *config.EconomyConfig) { e.TradeSSRCooldown = -time.Hour },
+		"soft pity at/after hard pity":              func(e *config.EconomyConfig) { e.SoftPityStart = 90 },
+		"negative soft pity step":                   func(e *config.EconomyConfig) { e.SoftPityStep = -1 },
+		"trade daily cap <= 0":                      func(e *config.EconomyConfig) { e.TradeDailyCap = 0 },
+		"negative achievement reward":               func(e *config.EconomyConfig) { e.AchievementRewardBanner = -1 },
+		"achievement streak target <= 0":            func(e *config.EconomyConfig) { e.AchievementStreakTarget = 0 },
+		"fair seed period under a minute":           func(e *config.EconomyConfig) { e.FairSeedPeriod = time.Second },
 	}
 	for name, mutate := range cases {
 		t.Run(name, func(t *testing.T) {
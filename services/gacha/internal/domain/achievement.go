@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Achievement kinds. Set achievements exist once per group / banner and carry
+// its ID: "group:<group_id>", "banner:<banner_id>". The rest are singletons
+// whose ID is the kind itself.
+const (
+	AchievementGroup    = "group"        // own every card of a group
+	AchievementBanner   = "banner"       // own every card of a banner's pool
+	AchievementFirstSSR = "first_ssr"    // obtain any SSR
+	AchievementStreak   = "daily_streak" // reach the daily-claim streak target
+	AchievementPulls    = "pulls"        // pull the target number of cards
+)
+
+// SplitAchievementID returns the kind and the set ID ("" for singletons) of
+// an achievement ID; ok is false for an unknown shape.
+func SplitAchievementID(id string) (kind, ref string, ok bool) {
+	kind, ref, _ = strings.Cut(id, ":")
+	switch kind {
+	case AchievementGroup, AchievementBanner:
+		return kind, ref, ref != ""
+	case AchievementFirstSSR, AchievementStreak, AchievementPulls:
+		return kind, "", ref == "" && !strings.Contains(id, ":")
+	}
+	return "", "", false
+}
+
+// AchievementUnlock is one row per (user, achievement) once it has been met.
+// UnlockedAt is stamped the first time the server sees the goal reached, so
+// goals that can regress (a broken streak, traded-away cards) stay unlocked.
+// ClaimedAt flips exactly once — the reward's idempotency guard.
+type AchievementUnlock struct {
+	UserID        string     `gorm:"type:uuid;primaryKey" json:"user_id"`
+	AchievementID string     `gorm:"size:80;primaryKey" json:"achievement_id"`
+	UnlockedAt    time.Time  `gorm:"not null" json:"unlocked_at"`
+	ClaimedAt     *time.Time `json:"claimed_at,omitempty"`
+}
+
+func (AchievementUnlock) TableName() string { return "gacha_achievements" }
+
+// AchievementReward is an admin override of what an achievement pays: an
+// amount of «Энигмы» and optionally a card (typically a disabled, off-banner
+// card — an exclusive). Achievements without a row pay the configured
+// per-kind default.
+type AchievementReward struct {
+	AchievementID string    `gorm:"size:80;primaryKey" json:"achievement_id"`
+	Currency      int64     `gorm:"not null;default:0" json:"currency"`
+	CardID        *string   `gorm:"type:uuid" json:"card_id,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (AchievementReward) TableName() string { return "gacha_achievement_rewards" }
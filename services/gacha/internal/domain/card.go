@@ -66,7 +66,9 @@ func (CardGroup) TableName() string { return "gacha_card_groups" }
 
 // Banner is a gameplay pull pool (spec §4.2): a scheduled, admin-curated
 // selection of cards. Exactly one banner should have IsStandard=true (the
-// always-on pool); the rest are timed events layered on top.
+// always-on pool); the rest are timed events layered on top. FeaturedShare is
+// the percentage of a tier's rolls that land on that tier's featured (rate-up)
+// cards, when the tier has any.
 type Banner struct {
 	ID          string         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	Name        string         `gorm:"size:128;not null" json:"name"`
@@ -82,6 +84,7 @@ type Banner struct {
 	ActiveFrom  *time.Time     `json:"active_from,omitempty"`
 	ActiveTo    *time.Time     `json:"active_to,omitempty"`
 	SortOrder   int            `gorm:"not null;default:0" json:"sort_order"`
+	FeaturedShare int          `gorm:"not null;default:50" json:"featured_share"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
@@ -90,9 +93,11 @@ type Banner struct {
 func (Banner) TableName() string { return "gacha_banners" }
 
 // BannerCard is the M:N banner↔card join row (the pull pool contents).
+// Featured marks a rate-up card of the banner.
 type BannerCard struct {
 	BannerID string `gorm:"type:uuid;not null;uniqueIndex:idx_banner_card,priority:1" json:"banner_id"`
 	CardID   string `gorm:"type:uuid;not null;uniqueIndex:idx_banner_card,priority:2" json:"card_id"`
+	Featured bool   `gorm:"not null;default:false" json:"featured"`
 }
 
 func (BannerCard) TableName() string { return "gacha_banner_cards" }
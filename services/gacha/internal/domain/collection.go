@@ -5,12 +5,20 @@ import "time"
 // CollectionEntry is one row per (user, card) — the player's owned cards.
 // Duplicate pulls bump Count rather than inserting a new row (spec §4.6,
 // decision #7 "вариант D"). FirstObtainedAt is set on the first ever obtain
-// and never moves on subsequent dupes.
+// and never moves on subsequent dupes. LastObtainedAt moves on every pull or
+// trade receipt and drives the fresh-SSR trade cooldown; rows written before
+// trading shipped have it NULL (treated as long obtained). A row whose copies
+// were all traded away stays at Count 0 so FirstObtainedAt survives.
+// TradeOnly marks a row whose copies all came from trades — the user never
+// pulled, crafted or was awarded the card — so it does not count toward set
+// or SSR achievements.
 type CollectionEntry struct {
-	UserID          string    `gorm:"type:uuid;not null;uniqueIndex:idx_user_card,priority:1" json:"user_id"`
-	CardID          string    `gorm:"type:uuid;not null;uniqueIndex:idx_user_card,priority:2" json:"card_id"`
-	Count           int       `gorm:"not null;default:1" json:"count"`
-	FirstObtainedAt time.Time `json:"first_obtained_at"`
+	UserID          string     `gorm:"type:uuid;not null;uniqueIndex:idx_user_card,priority:1" json:"user_id"`
+	CardID          string     `gorm:"type:uuid;not null;uniqueIndex:idx_user_card,priority:2" json:"card_id"`
+	Count           int        `gorm:"not null;default:1" json:"count"`
+	FirstObtainedAt time.Time  `json:"first_obtained_at"`
+	LastObtainedAt  *time.Time `json:"last_obtained_at,omitempty"`
+	TradeOnly       bool       `gorm:"not null;default:false" json:"-"`
 }
 
 func (CollectionEntry) TableName() string { return "gacha_collection" }
@@ -19,10 +27,13 @@ func (CollectionEntry) TableName() string { return "gacha_collection" }
 // decision #11). PullsSinceSSR increments on every roll for that banner and
 // resets to 0 on any SSR (natural or pity-forced). Counters are isolated per
 // banner — pulls on banner A never move banner B's counter.
+// GuaranteedFeatured is the 50/50 state: set when the last SSR on a rate-up
+// banner was off-banner, so the next SSR there is a featured card.
 type PityCounter struct {
-	UserID        string `gorm:"type:uuid;not null;uniqueIndex:idx_user_banner,priority:1" json:"user_id"`
-	BannerID      string `gorm:"type:uuid;not null;uniqueIndex:idx_user_banner,priority:2" json:"banner_id"`
-	PullsSinceSSR int    `gorm:"not null;default:0" json:"pulls_since_ssr"`
+	UserID             string `gorm:"type:uuid;not null;uniqueIndex:idx_user_banner,priority:1" json:"user_id"`
+	BannerID           string `gorm:"type:uuid;not null;uniqueIndex:idx_user_banner,priority:2" json:"banner_id"`
+	PullsSinceSSR      int    `gorm:"not null;default:0" json:"pulls_since_ssr"`
+	GuaranteedFeatured bool   `gorm:"not null;default:false" json:"guaranteed_featured"`
 }
 
 func (PityCounter) TableName() string { return "gacha_pity" }
@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// FairSeed is one commit-reveal period of the provably fair roller. Hash
+// (hex SHA-256 of Seed) is published as soon as the period opens; Seed
+// itself is only served once PeriodEnd has passed, so nobody — players or
+// operators editing odds mid-period — can steer a roll they can predict.
+type FairSeed struct {
+	ID          string    `gorm:"size:32;primaryKey" json:"id"` // PeriodStart as 20060102T150405Z
+	Hash        string    `gorm:"size:64;not null" json:"hash"`
+	Seed        string    `gorm:"size:64;not null" json:"-"`
+	PeriodStart time.Time `gorm:"not null;index" json:"period_start"`
+	PeriodEnd   time.Time `gorm:"not null" json:"period_end"`
+}
+
+func (FairSeed) TableName() string { return "gacha_fair_seeds" }
+
+// PullNonce is the per-user pull counter. Every pull takes the next value,
+// so no two pulls of a user ever share a random stream.
+type PullNonce struct {
+	UserID string `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Next   int64  `gorm:"not null;default:0" json:"next"`
+}
+
+func (PullNonce) TableName() string { return "gacha_pull_nonces" }
+
+// FairDraw is one random number the roller consumed: Value in [0, N).
+type FairDraw struct {
+	N     int `json:"n"`
+	Value int `json:"value"`
+}
+
+// PullRecord is the audit row of one x1/x10 pull: which seed period and
+// nonce fed it, every draw taken from the stream, and the resulting cards.
+// With the revealed seed anyone can recompute the draws.
+type PullRecord struct {
+	ID        string     `gorm:"size:64;primaryKey" json:"id"` // the pull's ledger ref
+	UserID    string     `gorm:"type:uuid;not null;index:idx_pull_log_user_created,priority:1" json:"user_id"`
+	BannerID  string     `gorm:"type:uuid;not null" json:"banner_id"`
+	Mode      string     `gorm:"size:8;not null" json:"mode"`
+	SeedID    string     `gorm:"size:32;not null;index" json:"seed_id"`
+	Nonce     int64      `gorm:"not null" json:"nonce"`
+	Draws     []FairDraw `gorm:"serializer:json;type:text" json:"draws"`
+	CardIDs   []string   `gorm:"serializer:json;type:text" json:"card_ids"`
+	CreatedAt time.Time  `gorm:"index:idx_pull_log_user_created,priority:2" json:"created_at"`
+}
+
+func (PullRecord) TableName() string { return "gacha_pull_log" }
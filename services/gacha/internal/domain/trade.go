@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// Trade offer statuses. Only a pending offer holds escrow; every other status
+// is terminal.
+const (
+	TradePending   = "pending"
+	TradeAccepted  = "accepted"
+	TradeCancelled = "cancelled" // withdrawn by the proposer
+	TradeDeclined  = "declined"  // rejected by the recipient
+	TradeCountered = "countered" // replaced by the recipient's counter-offer
+)
+
+// Trade item sides, from the proposer's point of view.
+const (
+	TradeSideOffered   = "offered"
+	TradeSideRequested = "requested"
+)
+
+// TradeOffer is one card-trade proposal: the proposer's offered cards plus
+// optional «Энигмы» for the recipient's requested cards. The offered side is
+// held in escrow while the offer is pending — the cards are taken out of the
+// proposer's collection and the currency is debited (reason trade_escrow) —
+// so it can never be spent, pulled against or offered twice. CounterOfID
+// links a counter-offer to the offer it replaced.
+type TradeOffer struct {
+	ID              string      `gorm:"type:uuid;primaryKey" json:"id"`
+	ProposerID      string      `gorm:"type:uuid;not null;index:idx_trade_proposer_created,priority:1" json:"proposer_id"`
+	RecipientID     string      `gorm:"type:uuid;not null;index:idx_trade_recipient_created,priority:1" json:"recipient_id"`
+	OfferedCurrency int64       `gorm:"not null;default:0" json:"offered_currency"`
+	Status          string      `gorm:"size:16;not null;index" json:"status"`
+	CounterOfID     *string     `gorm:"type:uuid" json:"counter_of_id,omitempty"`
+	CreatedAt       time.Time   `gorm:"index:idx_trade_proposer_created,priority:2;index:idx_trade_recipient_created,priority:2" json:"created_at"`
+	ResolvedAt      *time.Time  `json:"resolved_at,omitempty"`
+	Items           []TradeItem `gorm:"foreignKey:TradeID" json:"items"`
+}
+
+func (TradeOffer) TableName() string { return "gacha_trades" }
+
+// TradeItem is one card line of an offer: Count copies of CardID on Side.
+type TradeItem struct {
+	TradeID string `gorm:"type:uuid;not null;uniqueIndex:idx_trade_item,priority:1" json:"-"`
+	Side    string `gorm:"size:16;not null;uniqueIndex:idx_trade_item,priority:2" json:"side"`
+	CardID  string `gorm:"type:uuid;not null;uniqueIndex:idx_trade_item,priority:3" json:"card_id"`
+	Count   int    `gorm:"not null" json:"count"`
+}
+
+func (TradeItem) TableName() string { return "gacha_trade_items" }
+
+// ItemsOn returns the offer's items on one side.
+func (t *TradeOffer) ItemsOn(side string) []TradeItem {
+	var out []TradeItem
+	for _, it := range t.Items {
+		if it.Side == side {
+			out = append(out, it)
+		}
+	}
+	return out
+}
@@ -12,6 +12,20 @@ const (
 	ReasonTitleCompleted = "title_completed"
 	ReasonPullX1         = "pull_x1"
 	ReasonPullX10        = "pull_x10"
+	ReasonTradeEscrow    = "trade_escrow" // currency held when a trade offer is made
+	ReasonTradeRefund    = "trade_refund" // escrow returned on cancel / decline / counter
+	ReasonTradeIn        = "trade_in"     // escrowed currency paid to the accepting side
+	ReasonDismantle      = "dismantle"    // duplicate cards turned into shards
+	ReasonCraft          = "craft"        // shards spent on a specific card
+	ReasonAchievement    = "achievement"  // achievement reward; ref is the achievement ID
+)
+
+// Ledger currencies. «Энигмы» (Wallet.Balance) are earned and spent on pulls;
+// shards (Wallet.Shards) only come from dismantling duplicates and only buy
+// crafted cards.
+const (
+	CurrencyEnigma = "enigma"
+	CurrencyShards = "shards"
 )
 
 // Wallet is one row per user. Balance is denormalized from the ledger and
@@ -19,6 +33,7 @@ const (
 type Wallet struct {
 	UserID         string     `gorm:"type:uuid;primaryKey" json:"user_id"`
 	Balance        int64      `gorm:"not null;default:0" json:"balance"`
+	Shards         int64      `gorm:"not null;default:0" json:"shards"`
 	StarterGranted bool       `gorm:"not null;default:false" json:"starter_granted"`
 	DailyStreak    int        `gorm:"not null;default:0" json:"daily_streak"`
 	LastDailyAt    *time.Time `json:"last_daily_at,omitempty"`
@@ -29,13 +44,15 @@ type Wallet struct {
 func (Wallet) TableName() string { return "gacha_wallets" }
 
 // LedgerEntry is the append-only source of truth for every balance change.
-// Delta is positive for credits, negative for debits. Ref is an optional
-// idempotency discriminator (e.g. "<anime_id>:<episode>"); when non-empty,
-// the (UserID, Reason, Ref) unique index makes a duplicate insert a no-op.
+// Delta is positive for credits, negative for debits, in Currency (empty on
+// write = the column default, «Энигмы»). Ref is an optional idempotency
+// discriminator (e.g. "<anime_id>:<episode>"); when non-empty, the
+// (UserID, Reason, Ref) unique index makes a duplicate insert a no-op.
 type LedgerEntry struct {
 	ID        string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	UserID    string    `gorm:"type:uuid;not null;index:idx_ledger_user_created" json:"user_id"`
 	Delta     int64     `gorm:"not null" json:"delta"`
+	Currency  string    `gorm:"size:16;not null;default:enigma" json:"currency"`
 	Reason    string    `gorm:"size:32;not null" json:"reason"`
 	Ref       string    `gorm:"size:128;not null;default:''" json:"ref"`
 	CreatedAt time.Time `gorm:"index:idx_ledger_user_created" json:"created_at"`
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/ILITA-hub/animeenigma/libs/authz"
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// AchievementHandler serves the achievement endpoints:
+//
+//	GET    /achievements                   — the caller's progress on every achievement
+//	POST   /achievements/{id}/claim        — pay an unlocked achievement (idempotent)
+//	GET    /users/{id}/achievements        — another player's unlocked achievements (showcase)
+//	GET    /admin/achievements/rewards     — reward overrides
+//	PUT    /admin/achievements/{id}/reward — override one achievement's reward
+//	DELETE /admin/achievements/{id}/reward — back to the default reward
+type AchievementHandler struct {
+	svc *service.AchievementService
+	log *logger.Logger
+}
+
+func NewAchievementHandler(svc *service.AchievementService, log *logger.Logger) *AchievementHandler {
+	return &AchievementHandler{svc: svc, log: log}
+}
+
+// List handles GET /api/gacha/achievements.
+func (h *AchievementHandler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims.UserID == "" {
+		httputil.Unauthorized(w)
+		return
+	}
+	list, err := h.svc.Progress(r.Context(), claims.UserID)
+	if err != nil {
+		h.log.Errorw("achievement progress failed", "user_id", claims.UserID, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, list)
+}
+
+// Showcase handles GET /api/gacha/users/{id}/achievements.
+func (h *AchievementHandler) Showcase(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !isUUID(id) {
+		httputil.Error(w, apperrors.InvalidInput("invalid user id"))
+		return
+	}
+	list, err := h.svc.Showcase(r.Context(), id)
+	if err != nil {
+		h.log.Errorw("achievement showcase failed", "user_id", id, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, list)
+}
+
+// Claim handles POST /api/gacha/achievements/{id}/claim.
+func (h *AchievementHandler) Claim(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims.UserID == "" {
+		httputil.Unauthorized(w)
+		return
+	}
+	id := chi.URLParam(r, "id")
+	if !validAchievementID(id) {
+		httputil.Error(w, apperrors.InvalidInput("invalid achievement id"))
+		return
+	}
+	res, err := h.svc.Claim(r.Context(), claims.UserID, id)
+	if err != nil {
+		h.log.Infow("achievement claim failed", "user_id", claims.UserID, "achievement_id", id, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, res)
+}
+
+// ListRewards handles GET /api/gacha/admin/achievements/rewards.
+func (h *AchievementHandler) ListRewards(w http.ResponseWriter, r *http.Request) {
+	rewards, err := h.svc.ListRewards(r.Context())
+	if err != nil {
+		h.log.Errorw("list achievement rewards", "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, rewards)
+}
+
+// SetReward handles PUT /api/gacha/admin/achievements/{id}/reward.
+// Body: {"currency":500,"card_id":"<uuid>"} — card_id optional.
+func (h *AchievementHandler) SetReward(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !validAchievementID(id) {
+		httputil.Error(w, apperrors.InvalidInput("invalid achievement id"))
+		return
+	}
+	var req service.SetRewardRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	if req.CardID != "" && !isUUID(req.CardID) {
+		httputil.Error(w, apperrors.InvalidInput("invalid card_id"))
+		return
+	}
+	rw, err := h.svc.SetReward(r.Context(), id, req)
+	if err != nil {
+		h.log.Errorw("set achievement reward", "achievement_id", id, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, rw)
+}
+
+// DeleteReward handles DELETE /api/gacha/admin/achievements/{id}/reward.
+func (h *AchievementHandler) DeleteReward(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !validAchievementID(id) {
+		httputil.Error(w, apperrors.InvalidInput("invalid achievement id"))
+		return
+	}
+	if err := h.svc.DeleteReward(r.Context(), id); err != nil {
+		h.log.Errorw("delete achievement reward", "achievement_id", id, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, map[string]bool{"deleted": true})
+}
+
+// validAchievementID accepts the singleton IDs and "group:<uuid>" /
+// "banner:<uuid>".
+func validAchievementID(id string) bool {
+	_, ref, ok := domain.SplitAchievementID(id)
+	return ok && (ref == "" || isUUID(ref))
+}
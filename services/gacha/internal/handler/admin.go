@@ -341,9 +341,16 @@ func (h *AdminHandler) GetBanner(w http.ResponseWriter, r *http.Request) {
 		httputil.Error(w, err)
 		return
 	}
+	featuredIDs, err := h.content.BannerFeaturedIDs(r.Context(), id)
+	if err != nil {
+		h.log.Errorw("get banner featured ids", "banner_id", id, "error", err)
+		httputil.Error(w, err)
+		return
+	}
 	httputil.OK(w, map[string]interface{}{
-		"banner":   b,
-		"card_ids": cardIDs,
+		"banner":       b,
+		"card_ids":     cardIDs,
+		"featured_ids": featuredIDs,
 	})
 }
 
@@ -423,6 +430,27 @@ func (h *AdminHandler) AddBannerCards(w http.ResponseWriter, r *http.Request) {
 	httputil.OK(w, map[string]bool{"updated": true})
 }
 
+// SetBannerFeatured handles PUT /api/gacha/admin/banners/{id}/featured —
+// replaces the banner's rate-up cards (must be in the pool; [] clears).
+func (h *AdminHandler) SetBannerFeatured(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !isUUID(id) {
+		httputil.Error(w, apperrors.InvalidInput("invalid id"))
+		return
+	}
+	var req bannerCardsRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	if err := h.content.SetBannerFeatured(r.Context(), id, req.CardIDs); err != nil {
+		h.log.Errorw("set banner featured", "banner_id", id, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, map[string]bool{"updated": true})
+}
+
 // AddGroupCardsToBanner handles POST /api/gacha/admin/banners/{id}/groups/{groupId}
 func (h *AdminHandler) AddGroupCardsToBanner(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/ILITA-hub/animeenigma/libs/authz"
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// CraftHandler serves the authenticated shard-economy endpoints:
+// GET /crafting, POST /dismantle, POST /banners/{id}/craft.
+type CraftHandler struct {
+	svc *service.CraftService
+	log *logger.Logger
+}
+
+func NewCraftHandler(svc *service.CraftService, log *logger.Logger) *CraftHandler {
+	return &CraftHandler{svc: svc, log: log}
+}
+
+// Rates handles GET /api/gacha/crafting — per-rarity yields and costs.
+func (h *CraftHandler) Rates(w http.ResponseWriter, r *http.Request) {
+	httputil.OK(w, h.svc.Rates())
+}
+
+// dismantleRequest is the body of POST /dismantle. all_duplicates=true
+// dismantles every spare copy and ignores cards.
+type dismantleRequest struct {
+	Cards         []service.CardCount `json:"cards"`
+	AllDuplicates bool                `json:"all_duplicates"`
+}
+
+// Dismantle handles POST /api/gacha/dismantle.
+func (h *CraftHandler) Dismantle(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims.UserID == "" {
+		httputil.Unauthorized(w)
+		return
+	}
+	var req dismantleRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	for _, c := range req.Cards {
+		if !isUUID(c.CardID) {
+			httputil.Error(w, apperrors.InvalidInput("invalid card_id"))
+			return
+		}
+	}
+	res, err := h.svc.Dismantle(r.Context(), claims.UserID, req.Cards, req.AllDuplicates)
+	if err != nil {
+		h.log.Infow("dismantle failed", "user_id", claims.UserID, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, res)
+}
+
+// craftRequest is the body of POST /banners/{id}/craft.
+type craftRequest struct {
+	CardID string `json:"card_id"`
+}
+
+// Craft handles POST /api/gacha/banners/{id}/craft. Body: {"card_id":"..."}.
+func (h *CraftHandler) Craft(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims.UserID == "" {
+		httputil.Unauthorized(w)
+		return
+	}
+	id := chi.URLParam(r, "id")
+	if !isUUID(id) {
+		httputil.Error(w, apperrors.InvalidInput("invalid banner id"))
+		return
+	}
+	var req craftRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	if !isUUID(req.CardID) {
+		httputil.Error(w, apperrors.InvalidInput("invalid card_id"))
+		return
+	}
+	res, err := h.svc.Craft(r.Context(), claims.UserID, id, req.CardID)
+	if err != nil {
+		h.log.Infow("craft failed", "user_id", claims.UserID, "banner_id", id, "card_id", req.CardID, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, res)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/service"
+)
+
+// FairnessHandler serves the provably fair audit endpoints:
+//
+//	GET /fairness/seeds?limit=  — public seed commitments, revealed after each period
+//	GET /fairness/pulls?limit=  — the caller's verifiable pull history (JWT)
+type FairnessHandler struct {
+	svc *service.FairnessService
+	log *logger.Logger
+}
+
+func NewFairnessHandler(svc *service.FairnessService, log *logger.Logger) *FairnessHandler {
+	return &FairnessHandler{svc: svc, log: log}
+}
+
+// Seeds handles GET /api/gacha/fairness/seeds.
+func (h *FairnessHandler) Seeds(w http.ResponseWriter, r *http.Request) {
+	limit, ok := parseLimit(w, r)
+	if !ok {
+		return
+	}
+	seeds, err := h.svc.Seeds(r.Context(), limit)
+	if err != nil {
+		h.log.Errorw("fair seeds failed", "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, seeds)
+}
+
+// Pulls handles GET /api/gacha/fairness/pulls.
+func (h *FairnessHandler) Pulls(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims.UserID == "" {
+		httputil.Unauthorized(w)
+		return
+	}
+	limit, ok := parseLimit(w, r)
+	if !ok {
+		return
+	}
+	history, err := h.svc.History(r.Context(), claims.UserID, limit)
+	if err != nil {
+		h.log.Errorw("pull history failed", "user_id", claims.UserID, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, history)
+}
+
+// parseLimit reads the optional ?limit= page size; 0 means the default. On a
+// bad value it writes the 400 and returns false.
+func parseLimit(w http.ResponseWriter, r *http.Request) (int, bool) {
+	v := r.URL.Query().Get("limit")
+	if v == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		httputil.BadRequest(w, "limit must be a positive integer")
+		return 0, false
+	}
+	return n, true
+}
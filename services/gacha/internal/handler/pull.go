@@ -12,7 +12,8 @@ import (
 )
 
 // PullHandler serves the authenticated player-facing gacha endpoints:
-// POST /banners/{id}/pull, GET /banners, GET /collection.
+// POST /banners/{id}/pull, GET /banners, GET /collection, plus the public
+// GET /banners/{id}/odds.
 type PullHandler struct {
 	svc *service.PullService
 	log *logger.Logger
@@ -84,3 +85,18 @@ func (h *PullHandler) Collection(w http.ResponseWriter, r *http.Request) {
 	}
 	httputil.OK(w, view)
 }
+
+// Odds handles GET /api/gacha/banners/{id}/odds — public per-banner rates.
+func (h *PullHandler) Odds(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !isUUID(id) {
+		httputil.Error(w, apperrors.InvalidInput("invalid banner id"))
+		return
+	}
+	odds, err := h.svc.Odds(r.Context(), id)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, odds)
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/ILITA-hub/animeenigma/libs/authz"
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// TradeHandler serves the authenticated card-trading endpoints:
+//
+//	POST /trades                 — propose an offer (escrows the offered side)
+//	GET  /trades?status=&limit=  — the caller's trade history, newest first
+//	GET  /trades/{id}
+//	POST /trades/{id}/counter    — recipient answers with a new offer
+//	POST /trades/{id}/accept     — recipient completes the trade
+//	POST /trades/{id}/cancel     — proposer withdraws / recipient declines
+type TradeHandler struct {
+	svc *service.TradeService
+	log *logger.Logger
+}
+
+func NewTradeHandler(svc *service.TradeService, log *logger.Logger) *TradeHandler {
+	return &TradeHandler{svc: svc, log: log}
+}
+
+// tradeRequest is the body of POST /trades and POST /trades/{id}/counter.
+// recipient_id is ignored on a counter.
+type tradeRequest struct {
+	RecipientID     string              `json:"recipient_id"`
+	OfferedCards    []service.TradeCard `json:"offered_cards"`
+	OfferedCurrency int64               `json:"offered_currency"`
+	RequestedCards  []service.TradeCard `json:"requested_cards"`
+}
+
+// bindTrade decodes and shape-checks a trade body. Ownership, escrow and
+// anti-abuse rules are the service's job.
+func bindTrade(r *http.Request, counter bool) (service.TradeInput, error) {
+	var req tradeRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		return service.TradeInput{}, err
+	}
+	if !counter && !isUUID(req.RecipientID) {
+		return service.TradeInput{}, apperrors.InvalidInput("invalid recipient_id")
+	}
+	for _, side := range [][]service.TradeCard{req.OfferedCards, req.RequestedCards} {
+		for _, c := range side {
+			if !isUUID(c.CardID) {
+				return service.TradeInput{}, apperrors.InvalidInput("invalid card_id")
+			}
+		}
+	}
+	return service.TradeInput{
+		RecipientID:     req.RecipientID,
+		OfferedCards:    req.OfferedCards,
+		OfferedCurrency: req.OfferedCurrency,
+		RequestedCards:  req.RequestedCards,
+	}, nil
+}
+
+// Propose handles POST /api/gacha/trades.
+func (h *TradeHandler) Propose(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims.UserID == "" {
+		httputil.Unauthorized(w)
+		return
+	}
+	in, err := bindTrade(r, false)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	offer, err := h.svc.Propose(r.Context(), claims.UserID, in)
+	if err != nil {
+		h.log.Infow("trade propose failed", "user_id", claims.UserID, "recipient_id", in.RecipientID, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.Created(w, offer)
+}
+
+// List handles GET /api/gacha/trades.
+func (h *TradeHandler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims.UserID == "" {
+		httputil.Unauthorized(w)
+		return
+	}
+	q := r.URL.Query()
+	status := q.Get("status")
+	switch status {
+	case "", domain.TradePending, domain.TradeAccepted, domain.TradeCancelled, domain.TradeDeclined, domain.TradeCountered:
+	default:
+		httputil.BadRequest(w, "unknown status")
+		return
+	}
+	limit := 0
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			httputil.BadRequest(w, "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+	offers, err := h.svc.History(r.Context(), claims.UserID, status, limit)
+	if err != nil {
+		h.log.Errorw("trade history failed", "user_id", claims.UserID, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, offers)
+}
+
+// Get handles GET /api/gacha/trades/{id}.
+func (h *TradeHandler) Get(w http.ResponseWriter, r *http.Request) {
+	h.act(w, r, "get", h.svc.Get)
+}
+
+// Accept handles POST /api/gacha/trades/{id}/accept.
+func (h *TradeHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	h.act(w, r, "accept", h.svc.Accept)
+}
+
+// Cancel handles POST /api/gacha/trades/{id}/cancel.
+func (h *TradeHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.act(w, r, "cancel", h.svc.Cancel)
+}
+
+// Counter handles POST /api/gacha/trades/{id}/counter.
+func (h *TradeHandler) Counter(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims.UserID == "" {
+		httputil.Unauthorized(w)
+		return
+	}
+	id := chi.URLParam(r, "id")
+	if !isUUID(id) {
+		httputil.Error(w, apperrors.InvalidInput("invalid trade id"))
+		return
+	}
+	in, err := bindTrade(r, true)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	offer, err := h.svc.Counter(r.Context(), claims.UserID, id, in)
+	if err != nil {
+		h.log.Infow("trade counter failed", "user_id", claims.UserID, "trade_id", id, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.Created(w, offer)
+}
+
+// act runs a body-less per-offer operation for the caller.
+func (h *TradeHandler) act(
+	w http.ResponseWriter, r *http.Request, op string,
+	fn func(ctx context.Context, userID, tradeID string) (*domain.TradeOffer, error),
+) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims.UserID == "" {
+		httputil.Unauthorized(w)
+		return
+	}
+	id := chi.URLParam(r, "id")
+	if !isUUID(id) {
+		httputil.Error(w, apperrors.InvalidInput("invalid trade id"))
+		return
+	}
+	offer, err := fn(r.Context(), claims.UserID, id)
+	if err != nil {
+		h.log.Infow("trade "+op+" failed", "user_id", claims.UserID, "trade_id", id, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, offer)
+}
@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/database"
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/domain"
+	"gorm.io/gorm"
+)
+
+// accountUserTables lists the gacha tables holding a user's rows — trades, wallet, ledger, collection,
+// pity, achievements and the fair-pull log — in erase order. A trade belongs to both parties, so it is listed under each side.
+var accountUserTables = []database.UserTable{
+	{Table: "gacha_trades", Column: "proposer_id"},
+	{Table: "gacha_trades", Column: "recipient_id"},
+	{Table: "gacha_achievements", Column: "user_id"},
+	{Table: "gacha_pull_log", Column: "user_id"},
+	{Table: "gacha_pull_nonces", Column: "user_id"},
+	{Table: "gacha_pity", Column: "user_id"},
+	{Table: "gacha_collection", Column: "user_id"},
+	{Table: "gacha_ledger", Column: "user_id"},
+	{Table: "gacha_wallets", Column: "user_id"},
+}
+
+// AccountRepository backs the auth-driven account export + erasure fan-out
+// (/internal/account/*).
+type AccountRepository struct{ db *gorm.DB }
+
+func NewAccountRepository(db *gorm.DB) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+// userTrades selects the IDs of every trade the user is a party to.
+func (r *AccountRepository) userTrades(db *gorm.DB, userID string) *gorm.DB {
+	return db.Model(&domain.TradeOffer{}).Select("id").
+		Where("proposer_id = ? OR recipient_id = ?", userID, userID)
+}
+
+// Export returns every gacha row of the user, keyed by table. Trade items are
+// keyed by trade, not user, so they are collected through the user's trades.
+func (r *AccountRepository) Export(ctx context.Context, userID string) (map[string][]map[string]any, error) {
+	rows, err := database.ExportUserRows(ctx, r.db, userID, accountUserTables)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to export account data")
+	}
+	db := r.db.WithContext(ctx)
+	items := []map[string]any{}
+	if err := db.Table("gacha_trade_items").Where("trade_id IN (?)", r.userTrades(db, userID)).Find(&items).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to export account data")
+	}
+	rows["gacha_trade_items"] = items
+	return rows, nil
+}
+
+// Erase hard-deletes every gacha row of the user. Pending offers the user
+// received are declined first so their proposers get the escrow back; the
+// user's own escrow goes with their wallet and collection.
+func (r *AccountRepository) Erase(ctx context.Context, userID string) (map[string]int64, error) {
+	var items int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		trades := NewTradeRepository(tx)
+		var pending []domain.TradeOffer
+		if err := tx.Preload("Items").
+			Where("recipient_id = ? AND status = ?", userID, domain.TradePending).
+			Find(&pending).Error; err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		for i := range pending {
+			if err := trades.ResolveTx(tx, pending[i].ID, "recipient_id", userID, domain.TradeDeclined, now); err != nil {
+				return err
+			}
+			if err := trades.RefundEscrowTx(tx, &pending[i]); err != nil {
+				return err
+			}
+		}
+		res := tx.Where("trade_id IN (?)", r.userTrades(tx, userID)).Delete(&domain.TradeItem{})
+		items = res.RowsAffected
+		return res.Error
+	})
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to erase account data")
+	}
+	deleted, err := database.EraseUserRows(ctx, r.db, userID, accountUserTables)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to erase account data")
+	}
+	deleted["gacha_trade_items"] = items
+	return deleted, nil
+}
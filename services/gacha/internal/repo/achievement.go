@@ -0,0 +1,167 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SetProgress is how much of one card set (a group or a banner pool) a user
+// has collected. A card counts while the user holds it (a row traded down to
+// Count 0 does not) and only if the user pulled, crafted or was awarded it
+// themselves: TradeOnly rows never count. An unlock, once recorded, stays, so
+// the trade-only rule is what keeps one set passed between accounts from
+// unlocking set rewards on each.
+type SetProgress struct {
+	ID    string
+	Name  string
+	Owned int
+	Total int
+}
+
+// AchievementRepository reads the facts achievements are computed from and
+// owns the unlock / claim rows and the admin reward overrides.
+type AchievementRepository struct {
+	db *gorm.DB
+}
+
+func NewAchievementRepository(db *gorm.DB) *AchievementRepository {
+	return &AchievementRepository{db: db}
+}
+
+// DB exposes the connection for the claim transaction.
+func (r *AchievementRepository) DB() *gorm.DB { return r.db }
+
+// GroupProgress returns the user's progress on every group that has at least
+// one enabled card.
+func (r *AchievementRepository) GroupProgress(ctx context.Context, userID string) ([]SetProgress, error) {
+	var out []SetProgress
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT g.id, g.name, COUNT(c.id) AS total, COUNT(col.card_id) AS owned
+		FROM gacha_groups g
+		JOIN gacha_card_groups cg ON cg.group_id = g.id
+		JOIN gacha_cards c ON c.id = cg.card_id AND c.deleted_at IS NULL AND c.enabled = ?
+		LEFT JOIN gacha_collection col ON col.card_id = c.id AND col.user_id = ? AND col.count > 0 AND col.trade_only = ?
+		GROUP BY g.id, g.name
+		ORDER BY g.name`, true, userID, false).Scan(&out).Error
+	return out, err
+}
+
+// BannerProgress returns the user's progress on every enabled banner's pool.
+func (r *AchievementRepository) BannerProgress(ctx context.Context, userID string) ([]SetProgress, error) {
+	var out []SetProgress
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT b.id, b.name, COUNT(c.id) AS total, COUNT(col.card_id) AS owned
+		FROM gacha_banners b
+		JOIN gacha_banner_cards bc ON bc.banner_id = b.id
+		JOIN gacha_cards c ON c.id = bc.card_id AND c.deleted_at IS NULL AND c.enabled = ?
+		LEFT JOIN gacha_collection col ON col.card_id = c.id AND col.user_id = ? AND col.count > 0 AND col.trade_only = ?
+		WHERE b.enabled = ? AND b.deleted_at IS NULL
+		GROUP BY b.id, b.name
+		ORDER BY b.name`, true, userID, false, true).Scan(&out).Error
+	return out, err
+}
+
+// HasSSR reports whether the user holds an SSR card of their own (Count 0
+// rows left by trades and TradeOnly rows do not count).
+func (r *AchievementRepository) HasSSR(ctx context.Context, userID string) (bool, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Table("gacha_collection").
+		Joins("JOIN gacha_cards ON gacha_cards.id = gacha_collection.card_id").
+		Where("gacha_collection.user_id = ? AND gacha_collection.count > 0 AND gacha_collection.trade_only = ?", userID, false).
+		Where("gacha_cards.rarity = ?", domain.RaritySSR).
+		Count(&n).Error
+	return n > 0, err
+}
+
+// PullCount returns how many cards the user has pulled, from the ledger: one
+// per x1 debit, ten per x10.
+func (r *AchievementRepository) PullCount(ctx context.Context, userID string) (int, error) {
+	var n int
+	err := r.db.WithContext(ctx).Model(&domain.LedgerEntry{}).
+		Select("COALESCE(SUM(CASE reason WHEN ? THEN 1 WHEN ? THEN 10 ELSE 0 END), 0)",
+			domain.ReasonPullX1, domain.ReasonPullX10).
+		Where("user_id = ? AND reason IN ?", userID, []string{domain.ReasonPullX1, domain.ReasonPullX10}).
+		Scan(&n).Error
+	return n, err
+}
+
+// DailyStreak returns the user's current daily-claim streak (0 without a
+// wallet).
+func (r *AchievementRepository) DailyStreak(ctx context.Context, userID string) (int, error) {
+	var streak int
+	err := r.db.WithContext(ctx).Model(&domain.Wallet{}).
+		Select("daily_streak").
+		Where("user_id = ?", userID).
+		Scan(&streak).Error
+	return streak, err
+}
+
+// Unlocks returns the user's unlock rows keyed by achievement ID.
+func (r *AchievementRepository) Unlocks(ctx context.Context, userID string) (map[string]domain.AchievementUnlock, error) {
+	var rows []domain.AchievementUnlock
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[string]domain.AchievementUnlock, len(rows))
+	for _, u := range rows {
+		out[u.AchievementID] = u
+	}
+	return out, nil
+}
+
+// RecordUnlocks stamps the given achievements as unlocked at now. Rows that
+// already exist keep their original UnlockedAt.
+func (r *AchievementRepository) RecordUnlocks(ctx context.Context, userID string, ids []string, now time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	rows := make([]domain.AchievementUnlock, len(ids))
+	for i, id := range ids {
+		rows[i] = domain.AchievementUnlock{UserID: userID, AchievementID: id, UnlockedAt: now}
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+// ClaimTx marks the achievement claimed inside the caller's transaction. The
+// conditional UPDATE (`claimed_at IS NULL`) flips exactly once; a repeat or
+// racing claim returns claimed=false and must pay nothing.
+func (r *AchievementRepository) ClaimTx(tx *gorm.DB, userID, achievementID string, now time.Time) (bool, error) {
+	row := domain.AchievementUnlock{UserID: userID, AchievementID: achievementID, UnlockedAt: now}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+		return false, err
+	}
+	res := tx.Model(&domain.AchievementUnlock{}).
+		Where("user_id = ? AND achievement_id = ? AND claimed_at IS NULL", userID, achievementID).
+		UpdateColumn("claimed_at", now)
+	return res.RowsAffected == 1, res.Error
+}
+
+// Rewards returns every admin reward override keyed by achievement ID.
+func (r *AchievementRepository) Rewards(ctx context.Context) (map[string]domain.AchievementReward, error) {
+	var rows []domain.AchievementReward
+	if err := r.db.WithContext(ctx).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[string]domain.AchievementReward, len(rows))
+	for _, rw := range rows {
+		out[rw.AchievementID] = rw
+	}
+	return out, nil
+}
+
+// SetReward upserts an admin reward override.
+func (r *AchievementRepository) SetReward(ctx context.Context, rw *domain.AchievementReward) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "achievement_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"currency", "card_id", "updated_at"}),
+	}).Create(rw).Error
+}
+
+// DeleteReward drops an override so the achievement pays the default again.
+func (r *AchievementRepository) DeleteReward(ctx context.Context, achievementID string) error {
+	return r.db.WithContext(ctx).Delete(&domain.AchievementReward{}, "achievement_id = ?", achievementID).Error
+}
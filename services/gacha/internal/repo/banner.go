@@ -126,6 +126,47 @@ func (r *BannerRepository) BannerCardIDs(ctx context.Context, bannerID string) (
 	return cardIDs, nil
 }
 
+// SetFeatured replaces the banner's featured (rate-up) cards. Every ID must
+// already be in the pool; otherwise nothing changes and InvalidInput names
+// the problem.
+func (r *BannerRepository) SetFeatured(ctx context.Context, bannerID string, cardIDs []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.BannerCard{}).
+			Where("banner_id = ?", bannerID).
+			UpdateColumn("featured", false).Error; err != nil {
+			return err
+		}
+		if len(cardIDs) == 0 {
+			return nil
+		}
+		uniq := make(map[string]bool, len(cardIDs))
+		for _, id := range cardIDs {
+			uniq[id] = true
+		}
+		res := tx.Model(&domain.BannerCard{}).
+			Where("banner_id = ? AND card_id IN ?", bannerID, cardIDs).
+			UpdateColumn("featured", true)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != int64(len(uniq)) {
+			return apperrors.InvalidInput("featured cards must be in the banner pool")
+		}
+		return nil
+	})
+}
+
+// FeaturedCardIDs returns the IDs of the banner's featured cards.
+func (r *BannerRepository) FeaturedCardIDs(ctx context.Context, bannerID string) ([]string, error) {
+	ids := []string{}
+	err := r.db.WithContext(ctx).
+		Model(&domain.BannerCard{}).
+		Where("banner_id = ? AND featured = ?", bannerID, true).
+		Order("card_id").
+		Pluck("card_id", &ids).Error
+	return ids, err
+}
+
 // ActiveNow returns banners visible to players at the given time: enabled AND
 // (active_from IS NULL OR active_from <= now) AND (active_to IS NULL OR active_to >= now).
 // Ordered: is_standard DESC, sort_order ASC, created_at ASC.
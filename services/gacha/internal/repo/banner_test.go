@@ -53,6 +53,7 @@ func newBannerTestDB(t *testing.T) *gorm.DB {
 			active_from   DATETIME,
 			active_to     DATETIME,
 			sort_order    INTEGER NOT NULL DEFAULT 0,
+			featured_share INTEGER NOT NULL DEFAULT 50,
 			created_at    DATETIME,
 			updated_at    DATETIME,
 			deleted_at    DATETIME
@@ -62,6 +63,7 @@ func newBannerTestDB(t *testing.T) *gorm.DB {
 		`CREATE TABLE gacha_banner_cards (
 			banner_id TEXT NOT NULL,
 			card_id   TEXT NOT NULL,
+			featured  INTEGER NOT NULL DEFAULT 0,
 			UNIQUE(banner_id, card_id)
 		)`,
 	}
@@ -415,3 +417,43 @@ func TestBanner_BackdropPath_RoundTrip(t *testing.T) {
 		t.Errorf("BackdropPath after update: want %q, got %q", "banners/spring-backdrop-v2.webp", got2.BackdropPath)
 	}
 }
+
+// TestSetFeatured_PoolOnlyAndReplaces asserts featured cards must be in the
+// pool (a stray ID changes nothing) and that a new set replaces the old one.
+func TestSetFeatured_PoolOnlyAndReplaces(t *testing.T) {
+	db := newBannerTestDB(t)
+	cr := NewContentRepository(db)
+	br := NewBannerRepository(db)
+	ctx := context.Background()
+
+	c1 := seedCard(t, cr.db, "C1")
+	c2 := seedCard(t, cr.db, "C2")
+	outside := seedCard(t, cr.db, "Outside")
+
+	b := &domain.Banner{Name: "RateUp", Enabled: true}
+	if err := br.CreateBanner(ctx, b); err != nil {
+		t.Fatalf("CreateBanner: %v", err)
+	}
+	if err := br.SetCards(ctx, b.ID, []string{c1, c2}); err != nil {
+		t.Fatalf("SetCards: %v", err)
+	}
+	if err := br.SetFeatured(ctx, b.ID, []string{c1, c1}); err != nil {
+		t.Fatalf("SetFeatured: %v", err)
+	}
+	if err := br.SetFeatured(ctx, b.ID, []string{c2, outside}); err == nil {
+		t.Fatal("featuring a card outside the pool must fail")
+	}
+	ids, err := br.FeaturedCardIDs(ctx, b.ID)
+	if err != nil {
+		t.Fatalf("FeaturedCardIDs: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != c1 {
+		t.Errorf("featured after failed replace = %v; want [c1]", ids)
+	}
+	if err := br.SetFeatured(ctx, b.ID, []string{c2}); err != nil {
+		t.Fatalf("SetFeatured: %v", err)
+	}
+	if ids, _ = br.FeaturedCardIDs(ctx, b.ID); len(ids) != 1 || ids[0] != c2 {
+		t.Errorf("featured after replace = %v; want [c2]", ids)
+	}
+}
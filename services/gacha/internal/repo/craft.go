@@ -0,0 +1,113 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/domain"
+	"gorm.io/gorm"
+)
+
+// ErrInsufficientShards is returned by DebitShardsTx when the wallet holds
+// fewer shards than the craft costs. Like ErrInsufficientFunds, returning it
+// from the gorm.Transaction func rolls the whole craft back.
+var ErrInsufficientShards = apperrors.InvalidInput("insufficient shards")
+
+// CraftRepository owns the dismantle / craft writes: duplicate removal and
+// the shard side of the wallet. All *Tx methods operate on the caller's
+// transaction so the card move and its ledger entry land together.
+type CraftRepository struct {
+	db *gorm.DB
+}
+
+func NewCraftRepository(db *gorm.DB) *CraftRepository { return &CraftRepository{db: db} }
+
+// DuplicateCard is one dismantle candidate: Count copies owned, Count-1 spare.
+type DuplicateCard struct {
+	CardID string
+	Count  int
+	Rarity domain.Rarity
+}
+
+// DuplicatesTx returns the user's collection rows holding more than one copy,
+// with the card's rarity — the dismantle candidates. cardIDs nil means every
+// card.
+func (r *CraftRepository) DuplicatesTx(tx *gorm.DB, userID string, cardIDs []string) ([]DuplicateCard, error) {
+	q := tx.Table("gacha_collection").
+		Select("gacha_collection.card_id, gacha_collection.count, gacha_cards.rarity").
+		Joins("JOIN gacha_cards ON gacha_cards.id = gacha_collection.card_id").
+		Where("gacha_collection.user_id = ? AND gacha_collection.count > 1", userID)
+	if cardIDs != nil {
+		q = q.Where("gacha_collection.card_id IN ?", cardIDs)
+	}
+	var out []DuplicateCard
+	err := q.Order("gacha_collection.card_id").Scan(&out).Error
+	return out, err
+}
+
+// DismantleTx removes n spare copies of cardID. The conditional UPDATE keeps
+// at least one copy (`count > n`), so the last copy of a card can never be
+// dismantled; a short collection returns an InvalidInput error.
+func (r *CraftRepository) DismantleTx(tx *gorm.DB, userID, cardID string, n int) error {
+	res := tx.Model(&domain.CollectionEntry{}).
+		Where("user_id = ? AND card_id = ? AND count > ?", userID, cardID, n).
+		UpdateColumn("count", gorm.Expr("count - ?", n))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return apperrors.InvalidInput(fmt.Sprintf("not enough duplicates of card %s", cardID))
+	}
+	return nil
+}
+
+// CreditShardsTx adds amount shards and appends the shards ledger entry.
+func (r *CraftRepository) CreditShardsTx(tx *gorm.DB, userID string, amount int64, reason, ref string) error {
+	entry := domain.LedgerEntry{UserID: userID, Delta: amount, Currency: domain.CurrencyShards, Reason: reason, Ref: ref}
+	if err := tx.Create(&entry).Error; err != nil {
+		return err
+	}
+	return tx.Model(&domain.Wallet{}).
+		Where("user_id = ?", userID).
+		UpdateColumn("shards", gorm.Expr("shards + ?", amount)).Error
+}
+
+// DebitShardsTx removes amount shards with a conditional UPDATE
+// (`shards >= ?`) and appends the negative ledger entry. A short wallet
+// affects zero rows → ErrInsufficientShards with no side effects.
+func (r *CraftRepository) DebitShardsTx(tx *gorm.DB, userID string, amount int64, reason, ref string) error {
+	res := tx.Model(&domain.Wallet{}).
+		Where("user_id = ? AND shards >= ?", userID, amount).
+		UpdateColumn("shards", gorm.Expr("shards - ?", amount))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrInsufficientShards
+	}
+	entry := domain.LedgerEntry{UserID: userID, Delta: -amount, Currency: domain.CurrencyShards, Reason: reason, Ref: ref}
+	return tx.Create(&entry).Error
+}
+
+// InBanner reports whether cardID is in the banner's pool.
+func (r *CraftRepository) InBanner(ctx context.Context, bannerID, cardID string) (bool, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&domain.BannerCard{}).
+		Where("banner_id = ? AND card_id = ?", bannerID, cardID).
+		Count(&n).Error
+	return n > 0, err
+}
+
+// IsLimited reports whether cardID is a limited card: one that no enabled,
+// non-deleted standard banner carries, so it only ever appears on timed
+// event banners.
+func (r *CraftRepository) IsLimited(ctx context.Context, cardID string) (bool, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&domain.BannerCard{}).
+		Joins("JOIN gacha_banners ON gacha_banners.id = gacha_banner_cards.banner_id").
+		Where("gacha_banner_cards.card_id = ?", cardID).
+		Where("gacha_banners.is_standard = ? AND gacha_banners.enabled = ? AND gacha_banners.deleted_at IS NULL", true, true).
+		Count(&n).Error
+	return n == 0, err
+}
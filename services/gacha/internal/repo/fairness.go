@@ -0,0 +1,95 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FairnessRepository owns the commit-reveal seeds, the per-user pull nonces
+// and the pull audit log.
+type FairnessRepository struct {
+	db *gorm.DB
+}
+
+func NewFairnessRepository(db *gorm.DB) *FairnessRepository { return &FairnessRepository{db: db} }
+
+// EnsureSeed inserts s unless a seed for the same period already exists and
+// returns the stored row. Concurrent first pulls of a period race on the
+// primary key; DoNothing makes every loser read back the winner's seed, so a
+// period only ever has one committed hash.
+func (r *FairnessRepository) EnsureSeed(ctx context.Context, s *domain.FairSeed) (*domain.FairSeed, error) {
+	db := r.db.WithContext(ctx)
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(s).Error; err != nil {
+		return nil, err
+	}
+	var out domain.FairSeed
+	if err := db.First(&out, "id = ?", s.ID).Error; err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListSeeds returns the most recent seed periods, newest first.
+func (r *FairnessRepository) ListSeeds(ctx context.Context, limit int) ([]domain.FairSeed, error) {
+	out := []domain.FairSeed{}
+	err := r.db.WithContext(ctx).Order("period_start DESC").Limit(limit).Find(&out).Error
+	return out, err
+}
+
+// SeedsByID returns the seeds with the given IDs keyed by ID.
+func (r *FairnessRepository) SeedsByID(ctx context.Context, ids []string) (map[string]domain.FairSeed, error) {
+	out := make(map[string]domain.FairSeed, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	var seeds []domain.FairSeed
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&seeds).Error; err != nil {
+		return nil, err
+	}
+	for _, s := range seeds {
+		out[s.ID] = s
+	}
+	return out, nil
+}
+
+// NextNonceTx hands out the user's next pull nonce inside the caller's
+// transaction. The counter row is created on first use and locked
+// `FOR UPDATE` (like GetPityForUpdate) so concurrent pulls of one user get
+// distinct nonces; the increment only commits with the pull.
+func (r *FairnessRepository) NextNonceTx(tx *gorm.DB, userID string) (int64, error) {
+	seed := domain.PullNonce{UserID: userID}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&seed).Error; err != nil {
+		return 0, err
+	}
+	var n domain.PullNonce
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		First(&n).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Model(&domain.PullNonce{}).
+		Where("user_id = ?", userID).
+		UpdateColumn("next", n.Next+1).Error; err != nil {
+		return 0, err
+	}
+	return n.Next, nil
+}
+
+// CreateRecordTx appends one pull to the audit log.
+func (r *FairnessRepository) CreateRecordTx(tx *gorm.DB, rec *domain.PullRecord) error {
+	return tx.Create(rec).Error
+}
+
+// ListRecords returns the user's pull audit log, newest first.
+func (r *FairnessRepository) ListRecords(ctx context.Context, userID string, limit int) ([]domain.PullRecord, error) {
+	out := []domain.PullRecord{}
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, nonce DESC").
+		Limit(limit).
+		Find(&out).Error
+	return out, err
+}
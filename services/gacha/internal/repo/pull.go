@@ -64,17 +64,20 @@ func (r *PullRepository) GetPityForUpdate(tx *gorm.DB, userID, bannerID string)
 	return &p, nil
 }
 
-// SavePityTx persists the updated pity counter.
+// SavePityTx persists the updated pity counter and 50/50 state.
 func (r *PullRepository) SavePityTx(tx *gorm.DB, p *domain.PityCounter) error {
 	return tx.Model(&domain.PityCounter{}).
 		Where("user_id = ? AND banner_id = ?", p.UserID, p.BannerID).
-		UpdateColumn("pulls_since_ssr", p.PullsSinceSSR).Error
+		UpdateColumns(map[string]interface{}{
+			"pulls_since_ssr":     p.PullsSinceSSR,
+			"guaranteed_featured": p.GuaranteedFeatured,
+		}).Error
 }
 
 // AddToCollectionTx records each obtained card. Cards are processed one at a
 // time so a card appearing twice in the same slice increments twice. Each card
 // is an upsert: INSERT ... ON CONFLICT (user_id, card_id) DO UPDATE SET
-// count = count + 1. Returns which card IDs were newly obtained (first ever) and
+// count = count + 1, and the card is no longer trade-only. Returns which card IDs were newly obtained (first ever) and
 // the resulting count for each card ID after all increments.
 func (r *PullRepository) AddToCollectionTx(
 	tx *gorm.DB, userID string, cardIDs []string,
@@ -103,10 +106,15 @@ func (r *PullRepository) AddToCollectionTx(
 			CardID:          cid,
 			Count:           1,
 			FirstObtainedAt: now,
+			LastObtainedAt:  &now,
 		}
 		if err = tx.Clauses(clause.OnConflict{
-			Columns:   []clause.Column{{Name: "user_id"}, {Name: "card_id"}},
-			DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("gacha_collection.count + 1")}),
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "card_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"count":            gorm.Expr("gacha_collection.count + 1"),
+				"last_obtained_at": now,
+				"trade_only":       false,
+			}),
 		}).Create(&entry).Error; err != nil {
 			return nil, nil, err
 		}
@@ -129,33 +137,33 @@ func (r *PullRepository) AddToCollectionTx(
 	return newIDs, counts, nil
 }
 
-// ListCollection returns all of the user's owned card entries.
+// ListCollection returns all of the user's owned card entries. Rows left at
+// count 0 by trading are not owned and are skipped.
 func (r *PullRepository) ListCollection(ctx context.Context, userID string) ([]domain.CollectionEntry, error) {
 	var entries []domain.CollectionEntry
 	err := r.db.WithContext(ctx).
-		Where("user_id = ?", userID).
+		Where("user_id = ? AND count > 0", userID).
 		Find(&entries).Error
 	return entries, err
 }
 
 // GetPity returns the user's pity counter for a banner WITHOUT a lock (read
-// path for the banners view). Returns 0 when no row exists.
-func (r *PullRepository) GetPity(ctx context.Context, userID, bannerID string) (int, error) {
-	var p domain.PityCounter
+// path for the banners view). Returns a zero counter when no row exists.
+func (r *PullRepository) GetPity(ctx context.Context, userID, bannerID string) (domain.PityCounter, error) {
+	p := domain.PityCounter{UserID: userID, BannerID: bannerID}
 	err := r.db.WithContext(ctx).
 		Where("user_id = ? AND banner_id = ?", userID, bannerID).
 		First(&p).Error
 	if err == gorm.ErrRecordNotFound {
-		return 0, nil
-	}
-	if err != nil {
-		return 0, err
+		return p, nil
 	}
-	return p.PullsSinceSSR, nil
+	return p, err
 }
 
 // CardsByRarity returns the banner's enabled, non-soft-deleted cards grouped by
-// rarity tier. Used by the pull engine to build the roll pool.
+// rarity tier. Used by the pull engine to build the roll pool. Cards are
+// ordered by ID so a pull replayed from its fair-roll draws maps every draw
+// to the same card.
 func (r *PullRepository) CardsByRarity(ctx context.Context, bannerID string) (map[domain.Rarity][]domain.Card, error) {
 	var cards []domain.Card
 	err := r.db.WithContext(ctx).
@@ -163,6 +171,7 @@ func (r *PullRepository) CardsByRarity(ctx context.Context, bannerID string) (ma
 		Joins("JOIN gacha_banner_cards bc ON bc.card_id = gacha_cards.id").
 		Where("bc.banner_id = ?", bannerID).
 		Where("gacha_cards.enabled = ?", true).
+		Order("gacha_cards.id").
 		Find(&cards).Error
 	if err != nil {
 		return nil, err
@@ -24,6 +24,7 @@ func newPullTestDB(t *testing.T) *gorm.DB {
 		`CREATE TABLE gacha_wallets (
 			user_id TEXT PRIMARY KEY,
 			balance INTEGER NOT NULL DEFAULT 0,
+			shards INTEGER NOT NULL DEFAULT 0,
 			starter_granted INTEGER NOT NULL DEFAULT 0,
 			daily_streak INTEGER NOT NULL DEFAULT 0,
 			last_daily_at DATETIME,
@@ -36,6 +37,7 @@ func newPullTestDB(t *testing.T) *gorm.DB {
 			delta INTEGER NOT NULL,
 			reason TEXT NOT NULL,
 			ref TEXT NOT NULL DEFAULT '',
+			currency TEXT NOT NULL DEFAULT 'enigma',
 			created_at DATETIME
 		)`,
 		`CREATE UNIQUE INDEX idx_ledger_dedup ON gacha_ledger(user_id, reason, ref) WHERE ref <> ''`,
@@ -43,13 +45,16 @@ func newPullTestDB(t *testing.T) *gorm.DB {
 			user_id TEXT NOT NULL,
 			card_id TEXT NOT NULL,
 			count INTEGER NOT NULL DEFAULT 1,
-			first_obtained_at DATETIME
+			first_obtained_at DATETIME,
+			last_obtained_at DATETIME,
+			trade_only NUMERIC NOT NULL DEFAULT 0
 		)`,
 		`CREATE UNIQUE INDEX idx_user_card ON gacha_collection(user_id, card_id)`,
 		`CREATE TABLE gacha_pity (
 			user_id TEXT NOT NULL,
 			banner_id TEXT NOT NULL,
-			pulls_since_ssr INTEGER NOT NULL DEFAULT 0
+			pulls_since_ssr INTEGER NOT NULL DEFAULT 0,
+			guaranteed_featured INTEGER NOT NULL DEFAULT 0
 		)`,
 		`CREATE UNIQUE INDEX idx_user_banner ON gacha_pity(user_id, banner_id)`,
 		`CREATE TABLE gacha_cards (
@@ -65,7 +70,8 @@ func newPullTestDB(t *testing.T) *gorm.DB {
 		)`,
 		`CREATE TABLE gacha_banner_cards (
 			banner_id TEXT NOT NULL,
-			card_id TEXT NOT NULL
+			card_id TEXT NOT NULL,
+			featured INTEGER NOT NULL DEFAULT 0
 		)`,
 		`CREATE UNIQUE INDEX idx_banner_card ON gacha_banner_cards(banner_id, card_id)`,
 	}
@@ -0,0 +1,204 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrTradeNotPending is returned when a trade transition loses its
+// compare-and-set: the offer was already accepted, cancelled, declined or
+// countered (or the caller is not the side allowed to make the move).
+var ErrTradeNotPending = apperrors.New(apperrors.CodeConflict, "trade offer is no longer pending")
+
+// CreditTx credits amount «Энигмы» to the user's wallet inside the CALLER's
+// transaction and appends the matching ledger entry — the counterpart of
+// DebitTx for trade payouts and escrow refunds. ref is the trade ID; the
+// (user, reason, ref) dedup index turns a replay into a no-op and the
+// balance is only bumped when the ledger row actually landed.
+func CreditTx(tx *gorm.DB, userID string, amount int64, reason, ref string) error {
+	entry := domain.LedgerEntry{UserID: userID, Delta: amount, Reason: reason, Ref: ref}
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entry)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil
+	}
+	return tx.Model(&domain.Wallet{}).
+		Where("user_id = ?", userID).
+		UpdateColumn("balance", gorm.Expr("balance + ?", amount)).Error
+}
+
+// TradeRepository owns trade offers and the collection moves they make. All
+// *Tx methods operate on the caller-supplied transaction so a whole trade
+// step (status flip + escrow + card moves + ledger) is atomic.
+type TradeRepository struct {
+	db *gorm.DB
+}
+
+func NewTradeRepository(db *gorm.DB) *TradeRepository { return &TradeRepository{db: db} }
+
+// DB exposes the connection so the trade service can open its orchestration
+// transaction on the same handle.
+func (r *TradeRepository) DB() *gorm.DB { return r.db }
+
+// TakeCardsTx removes n copies of cardID from the user's collection. The
+// conditional UPDATE (`count >= n`) affects zero rows when the user owns
+// fewer copies, which returns an InvalidInput error naming the card. The row
+// stays at count 0 when the last copy leaves.
+func (r *TradeRepository) TakeCardsTx(tx *gorm.DB, userID, cardID string, n int) error {
+	res := tx.Model(&domain.CollectionEntry{}).
+		Where("user_id = ? AND card_id = ? AND count >= ?", userID, cardID, n).
+		UpdateColumn("count", gorm.Expr("count - ?", n))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return apperrors.InvalidInput(fmt.Sprintf("not enough copies of card %s", cardID))
+	}
+	return nil
+}
+
+// GiveCardsTx adds n copies of cardID to the user's collection (upsert, same
+// as a pull) and stamps LastObtainedAt so received SSRs start their own
+// trade cooldown. A card new to the user is TradeOnly; an existing row keeps
+// its flag.
+func (r *TradeRepository) GiveCardsTx(tx *gorm.DB, userID, cardID string, n int, now time.Time) error {
+	entry := domain.CollectionEntry{
+		UserID:          userID,
+		CardID:          cardID,
+		Count:           n,
+		FirstObtainedAt: now,
+		LastObtainedAt:  &now,
+		TradeOnly:       true,
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "card_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"count":            gorm.Expr("gacha_collection.count + ?", n),
+			"last_obtained_at": now,
+		}),
+	}).Create(&entry).Error
+}
+
+// ReturnCardsTx puts n escrowed copies of cardID back into the user's
+// collection. Unlike GiveCardsTx it leaves LastObtainedAt and TradeOnly
+// alone: a refund is not a new acquisition, so it must not restart the SSR
+// cooldown. TakeCardsTx never deletes the row, so it is always there.
+func (r *TradeRepository) ReturnCardsTx(tx *gorm.DB, userID, cardID string, n int) error {
+	res := tx.Model(&domain.CollectionEntry{}).
+		Where("user_id = ? AND card_id = ?", userID, cardID).
+		UpdateColumn("count", gorm.Expr("count + ?", n))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return apperrors.Internal(fmt.Sprintf("escrowed card %s missing from the collection", cardID))
+	}
+	return nil
+}
+
+// FreshSSRTx returns which of cardIDs are SSR cards the user last obtained
+// after since — the ones still inside the trade cooldown.
+func (r *TradeRepository) FreshSSRTx(tx *gorm.DB, userID string, cardIDs []string, since time.Time) ([]string, error) {
+	if len(cardIDs) == 0 {
+		return nil, nil
+	}
+	var fresh []string
+	err := tx.Table("gacha_collection").
+		Select("gacha_collection.card_id").
+		Joins("JOIN gacha_cards ON gacha_cards.id = gacha_collection.card_id").
+		Where("gacha_collection.user_id = ? AND gacha_collection.card_id IN ?", userID, cardIDs).
+		Where("gacha_cards.rarity = ? AND gacha_collection.last_obtained_at > ?", domain.RaritySSR, since).
+		Scan(&fresh).Error
+	return fresh, err
+}
+
+// CreateOfferTx inserts the offer and its items.
+func (r *TradeRepository) CreateOfferTx(tx *gorm.DB, t *domain.TradeOffer) error {
+	return tx.Create(t).Error
+}
+
+// ResolveTx flips a pending offer to status, but only when actorColumn
+// ("proposer_id" or "recipient_id") matches actorID — the CAS that makes a
+// double accept, or an accept racing a cancel, resolve exactly once.
+// Returns ErrTradeNotPending when it loses.
+func (r *TradeRepository) ResolveTx(tx *gorm.DB, id, actorColumn, actorID, status string, now time.Time) error {
+	res := tx.Model(&domain.TradeOffer{}).
+		Where("id = ? AND status = ?", id, domain.TradePending).
+		Where(actorColumn+" = ?", actorID).
+		Updates(map[string]interface{}{"status": status, "resolved_at": now})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrTradeNotPending
+	}
+	return nil
+}
+
+// RefundEscrowTx hands a resolved offer's escrow back to its proposer: the
+// offered cards return to the collection and the offered currency is
+// credited under trade_refund.
+func (r *TradeRepository) RefundEscrowTx(tx *gorm.DB, t *domain.TradeOffer) error {
+	for _, it := range t.ItemsOn(domain.TradeSideOffered) {
+		if err := r.ReturnCardsTx(tx, t.ProposerID, it.CardID, it.Count); err != nil {
+			return err
+		}
+	}
+	if t.OfferedCurrency > 0 {
+		return CreditTx(tx, t.ProposerID, t.OfferedCurrency, domain.ReasonTradeRefund, t.ID)
+	}
+	return nil
+}
+
+// GetOffer returns one offer with its items, or NotFound.
+func (r *TradeRepository) GetOffer(ctx context.Context, id string) (*domain.TradeOffer, error) {
+	var t domain.TradeOffer
+	err := r.db.WithContext(ctx).Preload("Items").First(&t, "id = ?", id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperrors.NotFound("trade offer")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ListForUser returns the offers the user made or received, newest first.
+// status "" means any.
+func (r *TradeRepository) ListForUser(ctx context.Context, userID, status string, limit int) ([]domain.TradeOffer, error) {
+	q := r.db.WithContext(ctx).Preload("Items").
+		Where("proposer_id = ? OR recipient_id = ?", userID, userID)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	out := []domain.TradeOffer{}
+	err := q.Order("created_at DESC").Limit(limit).Find(&out).Error
+	return out, err
+}
+
+// CountActionsSince counts the user's trade actions since `since`: offers and
+// counter-offers they made plus offers they accepted. Feeds the daily cap.
+func (r *TradeRepository) CountActionsSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&domain.TradeOffer{}).
+		Where("(proposer_id = ? AND created_at >= ?) OR (recipient_id = ? AND status = ? AND resolved_at >= ?)",
+			userID, since, userID, domain.TradeAccepted, since).
+		Count(&n).Error
+	return n, err
+}
+
+// WalletExists reports whether the user has a gacha wallet — the check that
+// a trade recipient is an actual player.
+func (r *TradeRepository) WalletExists(ctx context.Context, userID string) (bool, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&domain.Wallet{}).Where("user_id = ?", userID).Count(&n).Error
+	return n > 0, err
+}
@@ -20,6 +20,7 @@ func newTestDB(t *testing.T) *gorm.DB {
 		`CREATE TABLE gacha_wallets (
 			user_id TEXT PRIMARY KEY,
 			balance INTEGER NOT NULL DEFAULT 0,
+			shards INTEGER NOT NULL DEFAULT 0,
 			starter_granted INTEGER NOT NULL DEFAULT 0,
 			daily_streak INTEGER NOT NULL DEFAULT 0,
 			last_daily_at DATETIME,
@@ -32,6 +33,7 @@ func newTestDB(t *testing.T) *gorm.DB {
 			delta INTEGER NOT NULL,
 			reason TEXT NOT NULL,
 			ref TEXT NOT NULL DEFAULT '',
+			currency TEXT NOT NULL DEFAULT 'enigma',
 			created_at DATETIME
 		)`,
 		// Mirror the production partial unique index used for credit idempotency.
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/config"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/repo"
+	"gorm.io/gorm"
+)
+
+// AchievementRewardView is what claiming an achievement pays.
+type AchievementRewardView struct {
+	Currency int64  `json:"currency"`
+	CardID   string `json:"card_id,omitempty"`
+}
+
+// AchievementProgress is one achievement as shown on the profile showcase.
+// Name is the group / banner name for set achievements.
+type AchievementProgress struct {
+	ID         string                `json:"id"`
+	Kind       string                `json:"kind"`
+	Name       string                `json:"name,omitempty"`
+	Progress   int                   `json:"progress"`
+	Target     int                   `json:"target"`
+	Unlocked   bool                  `json:"unlocked"`
+	UnlockedAt *time.Time            `json:"unlocked_at,omitempty"`
+	Claimed    bool                  `json:"claimed"`
+	Reward     AchievementRewardView `json:"reward"`
+}
+
+// ClaimResult is the outcome of a claim. AlreadyClaimed marks a repeat claim:
+// it succeeds but pays nothing.
+type ClaimResult struct {
+	Achievement    AchievementProgress `json:"achievement"`
+	AlreadyClaimed bool                `json:"already_claimed"`
+	Card           *PulledCard         `json:"card,omitempty"`
+	Balance        int64               `json:"balance"`
+}
+
+// AchievementService computes achievement progress from what the player
+// already has — collection rows, the ledger, the wallet streak — so there is
+// no counter to drift. The first time a goal is seen met it is stamped
+// unlocked, which keeps goals that can regress (a broken streak) earned.
+// Claiming pays once: the claimed_at CAS and the (user, achievement, ref)
+// ledger dedup both guard it.
+type AchievementService struct {
+	db      *gorm.DB
+	ach     *repo.AchievementRepository
+	pull    *repo.PullRepository
+	content *repo.ContentRepository
+	econ    config.EconomyConfig
+	enabled bool
+	now     func() time.Time
+	log     *logger.Logger
+}
+
+// NewAchievementService wires the achievement engine. enabled is the
+// GACHA_ENABLED dark-ship toggle.
+func NewAchievementService(
+	achRepo *repo.AchievementRepository,
+	pullRepo *repo.PullRepository,
+	contentRepo *repo.ContentRepository,
+	econ config.EconomyConfig,
+	enabled bool,
+	log *logger.Logger,
+) *AchievementService {
+	return &AchievementService{
+		db:      achRepo.DB(),
+		ach:     achRepo,
+		pull:    pullRepo,
+		content: contentRepo,
+		econ:    econ,
+		enabled: enabled,
+		now:     time.Now,
+		log:     log,
+	}
+}
+
+// Progress returns every achievement with the user's progress, recording
+// any newly met goal as unlocked. Set achievements come first, then the
+// singletons; sets are ordered by name.
+func (s *AchievementService) Progress(ctx context.Context, userID string) ([]AchievementProgress, error) {
+	if !s.enabled {
+		return []AchievementProgress{}, nil
+	}
+	list, err := s.evaluate(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	unlocks, err := s.ach.Unlocks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	var fresh []string
+	for i := range list {
+		a := &list[i]
+		if u, ok := unlocks[a.ID]; ok {
+			at := u.UnlockedAt
+			a.Unlocked, a.UnlockedAt, a.Claimed = true, &at, u.ClaimedAt != nil
+			continue
+		}
+		if a.Progress >= a.Target {
+			fresh = append(fresh, a.ID)
+			a.Unlocked, a.UnlockedAt = true, &now
+		}
+	}
+	if err := s.ach.RecordUnlocks(ctx, userID, fresh, now); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Showcase returns only the user's unlocked achievements — the public view
+// of another player's profile.
+func (s *AchievementService) Showcase(ctx context.Context, userID string) ([]AchievementProgress, error) {
+	list, err := s.Progress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]AchievementProgress, 0, len(list))
+	for _, a := range list {
+		if a.Unlocked {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+// Sync records newly met goals without returning them. Called after events
+// that can be lost if nobody looks in time (the daily streak); errors are
+// logged, never surfaced to the event that triggered it.
+func (s *AchievementService) Sync(ctx context.Context, userID string) {
+	if _, err := s.Progress(ctx, userID); err != nil {
+		s.log.Warnw("achievement sync failed", "user_id", userID, "error", err)
+	}
+}
+
+// Claim pays an unlocked achievement's reward. A repeat claim returns
+// AlreadyClaimed with no payout rather than an error, so a retried request
+// is safe.
+func (s *AchievementService) Claim(ctx context.Context, userID, achievementID string) (*ClaimResult, error) {
+	if !s.enabled {
+		return nil, apperrors.InvalidInput("gacha is disabled")
+	}
+	if _, _, ok := domain.SplitAchievementID(achievementID); !ok {
+		return nil, apperrors.InvalidInput("invalid achievement id")
+	}
+	list, err := s.Progress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var ach *AchievementProgress
+	for i := range list {
+		if list[i].ID == achievementID {
+			ach = &list[i]
+			break
+		}
+	}
+	if ach == nil {
+		return nil, apperrors.NotFound("achievement")
+	}
+	if !ach.Unlocked {
+		return nil, apperrors.InvalidInput("achievement is not unlocked yet")
+	}
+
+	var card *domain.Card
+	if ach.Reward.CardID != "" {
+		if card, err = s.content.GetCard(ctx, ach.Reward.CardID); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ClaimResult{Achievement: *ach}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		claimed, err := s.ach.ClaimTx(tx, userID, achievementID, s.now().UTC())
+		if err != nil {
+			return err
+		}
+		result.AlreadyClaimed = !claimed
+		if claimed {
+			if r := ach.Reward; r.Currency > 0 {
+				if err := repo.CreditTx(tx, userID, r.Currency, domain.ReasonAchievement, achievementID); err != nil {
+					return err
+				}
+			}
+			if card != nil {
+				newIDs, counts, err := s.pull.AddToCollectionTx(tx, userID, []string{card.ID})
+				if err != nil {
+					return err
+				}
+				result.Card = &PulledCard{Card: *card, New: newIDs[card.ID], Count: counts[card.ID]}
+			}
+		}
+		var w domain.Wallet
+		if err := tx.Where("user_id = ?", userID).Limit(1).Find(&w).Error; err != nil {
+			return err
+		}
+		result.Balance = w.Balance
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Achievement.Claimed = true
+	if !result.AlreadyClaimed {
+		s.log.Infow("achievement claimed", "user_id", userID, "achievement_id", achievementID,
+			"currency", ach.Reward.Currency, "card_id", ach.Reward.CardID)
+	}
+	return result, nil
+}
+
+// SetRewardRequest is the admin override of one achievement's reward.
+type SetRewardRequest struct {
+	Currency int64  `json:"currency"`
+	CardID   string `json:"card_id"`
+}
+
+// SetReward overrides what achievementID pays. The card, if any, must exist
+// (it may be disabled — exclusive reward cards usually are).
+func (s *AchievementService) SetReward(ctx context.Context, achievementID string, req SetRewardRequest) (*domain.AchievementReward, error) {
+	if _, _, ok := domain.SplitAchievementID(achievementID); !ok {
+		return nil, apperrors.InvalidInput("invalid achievement id")
+	}
+	if req.Currency < 0 {
+		return nil, apperrors.InvalidInput("currency must be >= 0")
+	}
+	rw := &domain.AchievementReward{AchievementID: achievementID, Currency: req.Currency, UpdatedAt: s.now().UTC()}
+	if req.CardID != "" {
+		if _, err := s.content.GetCard(ctx, req.CardID); err != nil {
+			return nil, err
+		}
+		rw.CardID = &req.CardID
+	}
+	if err := s.ach.SetReward(ctx, rw); err != nil {
+		return nil, err
+	}
+	return rw, nil
+}
+
+// ListRewards returns every reward override, ordered by achievement ID.
+func (s *AchievementService) ListRewards(ctx context.Context) ([]domain.AchievementReward, error) {
+	m, err := s.ach.Rewards(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.AchievementReward, 0, len(m))
+	for _, rw := range m {
+		out = append(out, rw)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].AchievementID < out[j].AchievementID })
+	return out, nil
+}
+
+// DeleteReward drops an override; the achievement pays the default again.
+func (s *AchievementService) DeleteReward(ctx context.Context, achievementID string) error {
+	return s.ach.DeleteReward(ctx, achievementID)
+}
+
+// evaluate computes every achievement's progress and reward, without the
+// unlock state.
+func (s *AchievementService) evaluate(ctx context.Context, userID string) ([]AchievementProgress, error) {
+	rewards, err := s.ach.Rewards(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reward := func(id string, def int64) AchievementRewardView {
+		rw, ok := rewards[id]
+		if !ok {
+			return AchievementRewardView{Currency: def}
+		}
+		v := AchievementRewardView{Currency: rw.Currency}
+		if rw.CardID != nil {
+			v.CardID = *rw.CardID
+		}
+		return v
+	}
+
+	var out []AchievementProgress
+	groups, err := s.ach.GroupProgress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		id := domain.AchievementGroup + ":" + g.ID
+		out = append(out, AchievementProgress{
+			ID: id, Kind: domain.AchievementGroup, Name: g.Name, Progress: g.Owned, Target: g.Total,
+			Reward: reward(id, s.econ.AchievementRewardGroup),
+		})
+	}
+	banners, err := s.ach.BannerProgress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range banners {
+		id := domain.AchievementBanner + ":" + b.ID
+		out = append(out, AchievementProgress{
+			ID: id, Kind: domain.AchievementBanner, Name: b.Name, Progress: b.Owned, Target: b.Total,
+			Reward: reward(id, s.econ.AchievementRewardBanner),
+		})
+	}
+
+	hasSSR, err := s.ach.HasSSR(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ssr := 0
+	if hasSSR {
+		ssr = 1
+	}
+	streak, err := s.ach.DailyStreak(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	pulls, err := s.ach.PullCount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range []struct {
+		kind             string
+		progress, target int
+		def              int64
+	}{
+		{domain.AchievementFirstSSR, ssr, 1, s.econ.AchievementRewardFirstSSR},
+		{domain.AchievementStreak, streak, s.econ.AchievementStreakTarget, s.econ.AchievementRewardStreak},
+		{domain.AchievementPulls, pulls, s.econ.AchievementPullTarget, s.econ.AchievementRewardPulls},
+	} {
+		out = append(out, AchievementProgress{
+			ID: a.kind, Kind: a.kind, Progress: min(a.progress, a.target), Target: a.target,
+			Reward: reward(a.kind, a.def),
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/repo"
+	"gorm.io/gorm"
+)
+
+const groupA = "cccccccc-cccc-cccc-cccc-cccccccccccc"
+
+// newAchievementFixture extends the pull-service schema with groups and the
+// achievement tables, and seeds a wallet plus one banner whose two cards
+// also form groupA.
+func newAchievementFixture(t *testing.T) (*gorm.DB, *AchievementService) {
+	t.Helper()
+	db := newPullSvcDB(t)
+	for _, s := range []string{
+		`CREATE TABLE gacha_groups (id TEXT PRIMARY KEY, name TEXT NOT NULL, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE gacha_card_groups (group_id TEXT NOT NULL, card_id TEXT NOT NULL, UNIQUE(group_id, card_id))`,
+		`CREATE TABLE gacha_achievements (
+			user_id TEXT NOT NULL, achievement_id TEXT NOT NULL, unlocked_at DATETIME, claimed_at DATETIME,
+			PRIMARY KEY (user_id, achievement_id))`,
+		`CREATE TABLE gacha_achievement_rewards (
+			achievement_id TEXT PRIMARY KEY, currency INTEGER NOT NULL DEFAULT 0, card_id TEXT, updated_at DATETIME)`,
+	} {
+		if err := db.Exec(s).Error; err != nil {
+			t.Fatalf("DDL: %v", err)
+		}
+	}
+	seedBalance(t, db, 0)
+	seedBanner(t, db, bannerA, true)
+	seedCard(t, db, bannerA, "n1", domain.RarityN)
+	seedCard(t, db, bannerA, "ssr1", domain.RaritySSR)
+	db.Exec(`INSERT INTO gacha_groups (id, name) VALUES (?, 'Group A')`, groupA)
+	db.Exec(`INSERT INTO gacha_card_groups (group_id, card_id) VALUES (?, 'n1'), (?, 'ssr1')`, groupA, groupA)
+
+	econ := testEconomy()
+	econ.AchievementRewardGroup = 100
+	econ.AchievementRewardBanner = 300
+	econ.AchievementRewardFirstSSR = 50
+	econ.AchievementRewardStreak = 500
+	econ.AchievementRewardPulls = 200
+	econ.AchievementStreakTarget = 7
+	econ.AchievementPullTarget = 10
+	svc := NewAchievementService(repo.NewAchievementRepository(db), repo.NewPullRepository(db),
+		repo.NewContentRepository(db), econ, true, logger.Default())
+	return db, svc
+}
+
+func collect(t *testing.T, db *gorm.DB, cardIDs ...string) {
+	t.Helper()
+	for _, id := range cardIDs {
+		if err := db.Exec(`INSERT INTO gacha_collection (user_id, card_id, count) VALUES (?, ?, 1)`, svcUser, id).Error; err != nil {
+			t.Fatalf("collect %s: %v", id, err)
+		}
+	}
+}
+
+func findAchievement(list []AchievementProgress, id string) *AchievementProgress {
+	for i := range list {
+		if list[i].ID == id {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+func TestAchievements_SetProgressAndUnlock(t *testing.T) {
+	db, svc := newAchievementFixture(t)
+	ctx := context.Background()
+	collect(t, db, "n1")
+
+	list, err := svc.Progress(ctx, svcUser)
+	if err != nil {
+		t.Fatalf("Progress: %v", err)
+	}
+	g := findAchievement(list, domain.AchievementGroup+":"+groupA)
+	if g == nil || g.Progress != 1 || g.Target != 2 || g.Unlocked {
+		t.Fatalf("group = %+v; want 1/2 locked", g)
+	}
+	if _, err := svc.Claim(ctx, svcUser, g.ID); err == nil {
+		t.Fatal("claiming a locked achievement succeeded")
+	}
+
+	collect(t, db, "ssr1")
+	list, err = svc.Progress(ctx, svcUser)
+	if err != nil {
+		t.Fatalf("Progress: %v", err)
+	}
+	for _, id := range []string{domain.AchievementGroup + ":" + groupA, domain.AchievementBanner + ":" + bannerA, domain.AchievementFirstSSR} {
+		if a := findAchievement(list, id); a == nil || !a.Unlocked {
+			t.Errorf("%s = %+v; want unlocked", id, a)
+		}
+	}
+	show, err := svc.Showcase(ctx, svcUser)
+	if err != nil {
+		t.Fatalf("Showcase: %v", err)
+	}
+	if len(show) != 3 {
+		t.Errorf("showcase = %d achievements; want 3", len(show))
+	}
+}
+
+func TestAchievements_ClaimIsIdempotent(t *testing.T) {
+	db, svc := newAchievementFixture(t)
+	ctx := context.Background()
+	collect(t, db, "n1", "ssr1")
+	id := domain.AchievementGroup + ":" + groupA
+
+	res, err := svc.Claim(ctx, svcUser, id)
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if res.AlreadyClaimed || res.Balance != 100 {
+		t.Fatalf("first claim = %+v; want paid 100", res)
+	}
+	res, err = svc.Claim(ctx, svcUser, id)
+	if err != nil {
+		t.Fatalf("repeat Claim: %v", err)
+	}
+	if !res.AlreadyClaimed || res.Balance != 100 {
+		t.Fatalf("repeat claim = %+v; want already claimed, balance 100", res)
+	}
+	var n int64
+	db.Raw(`SELECT COUNT(*) FROM gacha_ledger WHERE user_id = ? AND reason = ?`, svcUser, domain.ReasonAchievement).Scan(&n)
+	if n != 1 {
+		t.Errorf("achievement ledger rows = %d; want 1", n)
+	}
+}
+
+func TestAchievements_RewardOverrideGrantsCard(t *testing.T) {
+	db, svc := newAchievementFixture(t)
+	ctx := context.Background()
+	// An exclusive card: disabled and in no banner pool.
+	db.Exec(`INSERT INTO gacha_cards (id, name, image_path, rarity, enabled) VALUES ('excl', 'Exclusive', 'cards/excl.webp', 'SSR', 0)`)
+	if _, err := svc.SetReward(ctx, domain.AchievementFirstSSR, SetRewardRequest{Currency: 0, CardID: "excl"}); err != nil {
+		t.Fatalf("SetReward: %v", err)
+	}
+	collect(t, db, "ssr1")
+
+	res, err := svc.Claim(ctx, svcUser, domain.AchievementFirstSSR)
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if res.Card == nil || res.Card.Card.ID != "excl" || !res.Card.New || res.Balance != 0 {
+		t.Fatalf("claim = %+v; want exclusive card and no currency", res)
+	}
+
+	if err := svc.DeleteReward(ctx, domain.AchievementFirstSSR); err != nil {
+		t.Fatalf("DeleteReward: %v", err)
+	}
+	list, _ := svc.Progress(ctx, svcUser)
+	if a := findAchievement(list, domain.AchievementFirstSSR); a == nil || a.Reward.Currency != 50 || a.Reward.CardID != "" {
+		t.Errorf("reward after delete = %+v; want default 50", a)
+	}
+}
+
+func TestAchievements_PullCountAndStreakStayUnlocked(t *testing.T) {
+	db, svc := newAchievementFixture(t)
+	ctx := context.Background()
+	db.Exec(`INSERT INTO gacha_ledger (user_id, delta, reason, ref) VALUES (?, -900, ?, 'p1'), (?, -100, ?, 'p2')`,
+		svcUser, domain.ReasonPullX10, svcUser, domain.ReasonPullX1)
+	db.Exec(`UPDATE gacha_wallets SET daily_streak = 7 WHERE user_id = ?`, svcUser)
+	svc.Sync(ctx, svcUser)
+
+	// The streak breaks; the achievement stays earned.
+	db.Exec(`UPDATE gacha_wallets SET daily_streak = 1 WHERE user_id = ?`, svcUser)
+	list, err := svc.Progress(ctx, svcUser)
+	if err != nil {
+		t.Fatalf("Progress: %v", err)
+	}
+	if a := findAchievement(list, domain.AchievementPulls); a == nil || a.Progress != 10 || !a.Unlocked {
+		t.Errorf("pulls = %+v; want 10/10 unlocked", a)
+	}
+	if a := findAchievement(list, domain.AchievementStreak); a == nil || a.Progress != 1 || !a.Unlocked {
+		t.Errorf("streak = %+v; want progress 1, unlocked", a)
+	}
+}
+
+func TestAchievements_TradedAwayCardsDoNotCount(t *testing.T) {
+	db, svc := newAchievementFixture(t)
+	ctx := context.Background()
+	collect(t, db, "n1", "ssr1")
+	// Every copy was traded away; the rows stay at count 0.
+	db.Exec(`UPDATE gacha_collection SET count = 0 WHERE user_id = ?`, svcUser)
+
+	list, err := svc.Progress(ctx, svcUser)
+	if err != nil {
+		t.Fatalf("Progress: %v", err)
+	}
+	if g := findAchievement(list, domain.AchievementGroup+":"+groupA); g == nil || g.Progress != 0 || g.Unlocked {
+		t.Errorf("group = %+v; want 0/2 locked", g)
+	}
+	for _, id := range []string{domain.AchievementBanner + ":" + bannerA, domain.AchievementFirstSSR} {
+		if a := findAchievement(list, id); a == nil || a.Unlocked {
+			t.Errorf("%s = %+v; want locked", id, a)
+		}
+	}
+	if _, err := svc.Claim(ctx, svcUser, domain.AchievementFirstSSR); err == nil {
+		t.Error("claimed first SSR with no SSR held")
+	}
+}
+
+// TestAchievements_TradedSetDoesNotUnlockTwice passes a completed set from
+// one account to another: the receiver holds every card but never pulled
+// them, so the set stays locked for them.
+func TestAchievements_TradedSetDoesNotUnlockTwice(t *testing.T) {
+	db, svc := newAchievementFixture(t)
+	ctx := context.Background()
+	const receiver = "22222222-2222-2222-2222-222222222222"
+	db.Exec(`INSERT INTO gacha_wallets (user_id, balance) VALUES (?, 0)`, receiver)
+	collect(t, db, "n1", "ssr1")
+	groupID := domain.AchievementGroup + ":" + groupA
+
+	if _, err := svc.Claim(ctx, svcUser, groupID); err != nil {
+		t.Fatalf("owner Claim: %v", err)
+	}
+
+	trades := repo.NewTradeRepository(db)
+	now := time.Now()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, card := range []string{"n1", "ssr1"} {
+			if err := trades.TakeCardsTx(tx, svcUser, card, 1); err != nil {
+				return err
+			}
+			if err := trades.GiveCardsTx(tx, receiver, card, 1, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("trade: %v", err)
+	}
+
+	list, err := svc.Progress(ctx, receiver)
+	if err != nil {
+		t.Fatalf("Progress: %v", err)
+	}
+	for _, id := range []string{groupID, domain.AchievementBanner + ":" + bannerA, domain.AchievementFirstSSR} {
+		if a := findAchievement(list, id); a == nil || a.Unlocked || a.Progress != 0 {
+			t.Errorf("receiver %s = %+v; want locked at 0", id, a)
+		}
+	}
+	if _, err := svc.Claim(ctx, receiver, groupID); err == nil {
+		t.Error("receiver claimed the traded set's reward")
+	}
+
+	// The original owner keeps the unlock they earned.
+	list, err = svc.Progress(ctx, svcUser)
+	if err != nil {
+		t.Fatalf("owner Progress: %v", err)
+	}
+	if a := findAchievement(list, groupID); a == nil || !a.Unlocked || !a.Claimed {
+		t.Errorf("owner group = %+v; want unlocked and claimed", a)
+	}
+
+	// Pulling a card of one's own makes it count again.
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		_, _, err := repo.NewPullRepository(db).AddToCollectionTx(tx, receiver, []string{"n1"})
+		return err
+	}); err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+	list, _ = svc.Progress(ctx, receiver)
+	if a := findAchievement(list, groupID); a == nil || a.Progress != 1 {
+		t.Errorf("receiver group after a pull = %+v; want 1/2", a)
+	}
+}
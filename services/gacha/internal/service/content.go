@@ -229,6 +229,8 @@ type CreateBannerRequest struct {
 	ActiveFrom   *time.Time `json:"active_from,omitempty"`
 	ActiveTo     *time.Time `json:"active_to,omitempty"`
 	SortOrder    int        `json:"sort_order"`
+	// FeaturedShare is the rate-up share in percent; 0 means the default 50.
+	FeaturedShare int `json:"featured_share"`
 }
 
 // UpdateBannerRequest carries the fields for updating a banner.
@@ -242,6 +244,18 @@ type UpdateBannerRequest struct {
 	ActiveFrom   *time.Time `json:"active_from,omitempty"`
 	ActiveTo     *time.Time `json:"active_to,omitempty"`
 	SortOrder    int        `json:"sort_order"`
+	// FeaturedShare is the rate-up share in percent; 0 keeps the current one.
+	FeaturedShare int `json:"featured_share"`
+}
+
+// defaultFeaturedShare is the classic 50/50 rate-up split.
+const defaultFeaturedShare = 50
+
+func validateFeaturedShare(share int) error {
+	if share < 0 || share > 100 {
+		return apperrors.InvalidInput("featured_share must be between 1 and 100")
+	}
+	return nil
 }
 
 func validateBannerWindow(activeFrom, activeTo *time.Time) error {
@@ -259,16 +273,23 @@ func (s *ContentService) CreateBanner(ctx context.Context, req CreateBannerReque
 	if err := validateBannerWindow(req.ActiveFrom, req.ActiveTo); err != nil {
 		return nil, err
 	}
+	if err := validateFeaturedShare(req.FeaturedShare); err != nil {
+		return nil, err
+	}
+	if req.FeaturedShare == 0 {
+		req.FeaturedShare = defaultFeaturedShare
+	}
 
 	b := &domain.Banner{
-		Name:         req.Name,
-		Description:  req.Description,
-		BackdropPath: req.BackdropPath,
-		IsStandard:   req.IsStandard,
-		Enabled:      req.Enabled,
-		ActiveFrom:   req.ActiveFrom,
-		ActiveTo:     req.ActiveTo,
-		SortOrder:    req.SortOrder,
+		Name:          req.Name,
+		Description:   req.Description,
+		BackdropPath:  req.BackdropPath,
+		IsStandard:    req.IsStandard,
+		Enabled:       req.Enabled,
+		ActiveFrom:    req.ActiveFrom,
+		ActiveTo:      req.ActiveTo,
+		SortOrder:     req.SortOrder,
+		FeaturedShare: req.FeaturedShare,
 	}
 	if err := s.banners.CreateBanner(ctx, b); err != nil {
 		return nil, err
@@ -284,6 +305,9 @@ func (s *ContentService) UpdateBanner(ctx context.Context, req UpdateBannerReque
 	if err := validateBannerWindow(req.ActiveFrom, req.ActiveTo); err != nil {
 		return nil, err
 	}
+	if err := validateFeaturedShare(req.FeaturedShare); err != nil {
+		return nil, err
+	}
 
 	b, err := s.banners.GetBanner(ctx, req.ID)
 	if err != nil {
@@ -297,6 +321,9 @@ func (s *ContentService) UpdateBanner(ctx context.Context, req UpdateBannerReque
 	b.ActiveFrom = req.ActiveFrom
 	b.ActiveTo = req.ActiveTo
 	b.SortOrder = req.SortOrder
+	if req.FeaturedShare != 0 {
+		b.FeaturedShare = req.FeaturedShare
+	}
 
 	if err := s.banners.UpdateBanner(ctx, b); err != nil {
 		return nil, err
@@ -334,6 +361,20 @@ func (s *ContentService) AddGroupCardsToBanner(ctx context.Context, bannerID, gr
 	return s.banners.AddGroupCards(ctx, bannerID, groupID)
 }
 
+// SetBannerFeatured replaces the banner's featured (rate-up) cards; every
+// card must already be in the pool.
+func (s *ContentService) SetBannerFeatured(ctx context.Context, bannerID string, cardIDs []string) error {
+	if _, err := s.banners.GetBanner(ctx, bannerID); err != nil {
+		return err
+	}
+	return s.banners.SetFeatured(ctx, bannerID, cardIDs)
+}
+
+// BannerFeaturedIDs returns the banner's featured card IDs.
+func (s *ContentService) BannerFeaturedIDs(ctx context.Context, bannerID string) ([]string, error) {
+	return s.banners.FeaturedCardIDs(ctx, bannerID)
+}
+
 // BannerCardIDs returns the card IDs in a banner's pool.
 func (s *ContentService) BannerCardIDs(ctx context.Context, bannerID string) ([]string, error) {
 	return s.banners.BannerCardIDs(ctx, bannerID)
@@ -54,6 +54,7 @@ func newServiceTestDB(t *testing.T) *gorm.DB {
 			active_from   DATETIME,
 			active_to     DATETIME,
 			sort_order    INTEGER NOT NULL DEFAULT 0,
+			featured_share INTEGER NOT NULL DEFAULT 50,
 			created_at    DATETIME,
 			updated_at    DATETIME,
 			deleted_at    DATETIME
@@ -61,6 +62,7 @@ func newServiceTestDB(t *testing.T) *gorm.DB {
 		`CREATE TABLE gacha_banner_cards (
 			banner_id TEXT NOT NULL,
 			card_id   TEXT NOT NULL,
+			featured  INTEGER NOT NULL DEFAULT 0,
 			UNIQUE(banner_id, card_id)
 		)`,
 	}
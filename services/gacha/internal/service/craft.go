@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/config"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/repo"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// dismantleMaxLines caps distinct cards in one dismantle request.
+const dismantleMaxLines = 50
+
+// CardCount is one card line of a dismantle request.
+type CardCount struct {
+	CardID string `json:"card_id"`
+	Count  int    `json:"count"`
+}
+
+// CraftRates is the per-rarity shard economy: what a spare copy dismantles
+// into and what a crafted card costs.
+type CraftRates struct {
+	DismantleYield map[domain.Rarity]int64 `json:"dismantle_yield"`
+	CraftCost      map[domain.Rarity]int64 `json:"craft_cost"`
+}
+
+// DismantleResult is the outcome of a dismantle request.
+type DismantleResult struct {
+	Dismantled []CardCount `json:"dismantled"`
+	Gained     int64       `json:"gained"`
+	Shards     int64       `json:"shards"`
+}
+
+// CraftResult is the outcome of a craft: the card (with its post-craft
+// collection status, like a pull) and the remaining shards.
+type CraftResult struct {
+	Card   PulledCard `json:"card"`
+	Shards int64      `json:"shards"`
+}
+
+// CraftService turns duplicates into shards and shards into specific cards.
+// Both directions are one transaction each and ledgered in the shards
+// currency (reasons dismantle / craft):
+//
+//   - Dismantle only ever takes spare copies — the last copy of a card stays.
+//   - Craft goes through a banner: the banner must be active and carry the
+//     card, and limited cards (on no standard banner) can't be crafted at
+//     all, so event exclusives stay pull-only.
+type CraftService struct {
+	db      *gorm.DB
+	craft   *repo.CraftRepository
+	pull    *repo.PullRepository
+	banners *repo.BannerRepository
+	content *repo.ContentRepository
+	econ    config.EconomyConfig
+	enabled bool
+	log     *logger.Logger
+}
+
+// NewCraftService wires the dismantle / craft engine. enabled is the
+// GACHA_ENABLED dark-ship toggle (mirrors PullService).
+func NewCraftService(
+	craftRepo *repo.CraftRepository,
+	pullRepo *repo.PullRepository,
+	bannerRepo *repo.BannerRepository,
+	contentRepo *repo.ContentRepository,
+	econ config.EconomyConfig,
+	enabled bool,
+	log *logger.Logger,
+) *CraftService {
+	return &CraftService{
+		db:      bannerRepo.DB(),
+		craft:   craftRepo,
+		pull:    pullRepo,
+		banners: bannerRepo,
+		content: contentRepo,
+		econ:    econ,
+		enabled: enabled,
+		log:     log,
+	}
+}
+
+// Rates returns the configured dismantle yields and craft costs.
+func (s *CraftService) Rates() CraftRates {
+	e := s.econ
+	return CraftRates{
+		DismantleYield: map[domain.Rarity]int64{
+			domain.RarityN: e.DismantleYieldN, domain.RarityR: e.DismantleYieldR,
+			domain.RaritySR: e.DismantleYieldSR, domain.RaritySSR: e.DismantleYieldSSR,
+		},
+		CraftCost: map[domain.Rarity]int64{
+			domain.RarityN: e.CraftCostN, domain.RarityR: e.CraftCostR,
+			domain.RaritySR: e.CraftCostSR, domain.RaritySSR: e.CraftCostSSR,
+		},
+	}
+}
+
+// Dismantle turns spare copies into shards. With allDuplicates every spare
+// copy the user owns is dismantled and cards is ignored; otherwise each line
+// must leave at least one copy behind.
+func (s *CraftService) Dismantle(ctx context.Context, userID string, cards []CardCount, allDuplicates bool) (*DismantleResult, error) {
+	if !s.enabled {
+		return nil, apperrors.InvalidInput("gacha is disabled")
+	}
+	var want []CardCount
+	if !allDuplicates {
+		var err error
+		if want, err = mergeCardCounts(cards); err != nil {
+			return nil, err
+		}
+		if len(want) == 0 {
+			return nil, apperrors.InvalidInput("nothing to dismantle")
+		}
+	}
+
+	rates := s.Rates().DismantleYield
+	ref := "dismantle-" + uuid.NewString()
+	result := &DismantleResult{Dismantled: []CardCount{}}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []string
+		for _, c := range want {
+			ids = append(ids, c.CardID)
+		}
+		dups, err := s.craft.DuplicatesTx(tx, userID, ids)
+		if err != nil {
+			return err
+		}
+		rarity := make(map[string]domain.Rarity, len(dups))
+		for _, d := range dups {
+			rarity[d.CardID] = d.Rarity
+			if allDuplicates {
+				want = append(want, CardCount{CardID: d.CardID, Count: d.Count - 1})
+			}
+		}
+		if len(want) == 0 {
+			return apperrors.InvalidInput("no duplicates to dismantle")
+		}
+		for _, c := range want {
+			r, ok := rarity[c.CardID]
+			if !ok {
+				return apperrors.InvalidInput(fmt.Sprintf("not enough duplicates of card %s", c.CardID))
+			}
+			if err := s.craft.DismantleTx(tx, userID, c.CardID, c.Count); err != nil {
+				return err
+			}
+			result.Gained += rates[r] * int64(c.Count)
+			result.Dismantled = append(result.Dismantled, c)
+		}
+		if result.Gained > 0 {
+			if err := s.craft.CreditShardsTx(tx, userID, result.Gained, domain.ReasonDismantle, ref); err != nil {
+				return err
+			}
+		}
+		var w domain.Wallet
+		if err := tx.First(&w, "user_id = ?", userID).Error; err != nil {
+			return err
+		}
+		result.Shards = w.Shards
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.log.Infow("cards dismantled", "user_id", userID, "lines", len(result.Dismantled), "shards", result.Gained)
+	return result, nil
+}
+
+// Craft buys one copy of cardID from bannerID's pool with shards.
+func (s *CraftService) Craft(ctx context.Context, userID, bannerID, cardID string) (*CraftResult, error) {
+	if !s.enabled {
+		return nil, apperrors.InvalidInput("gacha is disabled")
+	}
+	banner, err := s.banners.GetBanner(ctx, bannerID)
+	if err != nil {
+		return nil, err
+	}
+	if !bannerActiveNow(banner, time.Now()) {
+		return nil, apperrors.InvalidInput("banner is not active")
+	}
+	inBanner, err := s.craft.InBanner(ctx, bannerID, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if !inBanner {
+		return nil, apperrors.InvalidInput("card is not in this banner")
+	}
+	card, err := s.content.GetCard(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if !card.Enabled {
+		return nil, apperrors.InvalidInput("card is not available")
+	}
+	limited, err := s.craft.IsLimited(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if limited {
+		return nil, apperrors.InvalidInput("limited cards cannot be crafted")
+	}
+	cost := s.Rates().CraftCost[card.Rarity]
+
+	ref := "craft-" + uuid.NewString()
+	var result CraftResult
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.craft.DebitShardsTx(tx, userID, cost, domain.ReasonCraft, ref); err != nil {
+			return err
+		}
+		newIDs, counts, err := s.pull.AddToCollectionTx(tx, userID, []string{cardID})
+		if err != nil {
+			return err
+		}
+		result.Card = PulledCard{Card: *card, New: newIDs[cardID], Count: counts[cardID]}
+		var w domain.Wallet
+		if err := tx.First(&w, "user_id = ?", userID).Error; err != nil {
+			return err
+		}
+		result.Shards = w.Shards
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.log.Infow("card crafted", "user_id", userID, "banner_id", bannerID, "card_id", cardID, "cost", cost)
+	return &result, nil
+}
+
+// mergeCardCounts validates dismantle lines and merges repeats of a card,
+// keeping first-seen order.
+func mergeCardCounts(cards []CardCount) ([]CardCount, error) {
+	out := make([]CardCount, 0, len(cards))
+	idx := make(map[string]int, len(cards))
+	for _, c := range cards {
+		if c.CardID == "" || c.Count <= 0 {
+			return nil, apperrors.InvalidInput("each card needs a card_id and a positive count")
+		}
+		if i, ok := idx[c.CardID]; ok {
+			out[i].Count += c.Count
+			continue
+		}
+		idx[c.CardID] = len(out)
+		out = append(out, c)
+	}
+	if len(out) > dismantleMaxLines {
+		return nil, apperrors.InvalidInput(fmt.Sprintf("at most %d different cards per request", dismantleMaxLines))
+	}
+	return out, nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/repo"
+	"gorm.io/gorm"
+)
+
+const (
+	craftCardN     = "c0000000-0000-0000-0000-00000000000a"
+	craftCardSR    = "c0000000-0000-0000-0000-00000000000b"
+	craftCardEvent = "c0000000-0000-0000-0000-00000000000c"
+	eventBanner    = "eeeeeeee-eeee-eeee-eeee-eeeeeeeeeeee"
+)
+
+// newCraftFixture seeds a standard banner (bannerA) with an N and an SR card
+// and an event banner that also carries an event-only card.
+func newCraftFixture(t *testing.T) (*CraftService, *gorm.DB) {
+	t.Helper()
+	db := newPullSvcDB(t)
+	seedBalance(t, db, 0)
+	seedBanner(t, db, bannerA, true)
+	seedCard(t, db, bannerA, craftCardN, domain.RarityN)
+	seedCard(t, db, bannerA, craftCardSR, domain.RaritySR)
+	if err := db.Exec(`INSERT INTO gacha_banners (id, name, enabled, is_standard) VALUES (?, 'Event', 1, 0)`, eventBanner).Error; err != nil {
+		t.Fatalf("seed event banner: %v", err)
+	}
+	seedCard(t, db, eventBanner, craftCardEvent, domain.RaritySSR)
+	db.Exec(`INSERT INTO gacha_banner_cards (banner_id, card_id) VALUES (?, ?)`, eventBanner, craftCardSR)
+
+	econ := testEconomy()
+	econ.DismantleYieldN, econ.DismantleYieldR, econ.DismantleYieldSR, econ.DismantleYieldSSR = 1, 5, 20, 100
+	econ.CraftCostN, econ.CraftCostR, econ.CraftCostSR, econ.CraftCostSSR = 5, 25, 100, 500
+	pullRepo := repo.NewPullRepository(db)
+	svc := NewCraftService(repo.NewCraftRepository(db), pullRepo, repo.NewBannerRepository(db), repo.NewContentRepository(db), econ, true, logger.Default())
+	return svc, db
+}
+
+func setCount(t *testing.T, db *gorm.DB, cardID string, n int) {
+	t.Helper()
+	if err := db.Exec(`INSERT INTO gacha_collection (user_id, card_id, count, first_obtained_at) VALUES (?, ?, ?, CURRENT_TIMESTAMP)`,
+		svcUser, cardID, n).Error; err != nil {
+		t.Fatalf("seed collection: %v", err)
+	}
+}
+
+func TestDismantle_KeepsLastCopyAndLedgersShards(t *testing.T) {
+	svc, db := newCraftFixture(t)
+	ctx := context.Background()
+	setCount(t, db, craftCardN, 4)
+	setCount(t, db, craftCardSR, 2)
+
+	// The last copy is never dismantled.
+	if _, err := svc.Dismantle(ctx, svcUser, []CardCount{{CardID: craftCardN, Count: 4}}, false); err == nil {
+		t.Fatal("dismantling every copy should be rejected")
+	}
+	res, err := svc.Dismantle(ctx, svcUser, []CardCount{{CardID: craftCardN, Count: 2}}, false)
+	if err != nil {
+		t.Fatalf("Dismantle: %v", err)
+	}
+	if res.Gained != 2 || res.Shards != 2 {
+		t.Errorf("dismantle 2×N = +%d (now %d); want +2 (2)", res.Gained, res.Shards)
+	}
+
+	// all_duplicates sweeps the remaining spare N and the spare SR.
+	res, err = svc.Dismantle(ctx, svcUser, nil, true)
+	if err != nil {
+		t.Fatalf("Dismantle all: %v", err)
+	}
+	if res.Gained != 1+20 || res.Shards != 23 || len(res.Dismantled) != 2 {
+		t.Errorf("dismantle all = %+v; want +21, 23 shards, 2 lines", res)
+	}
+	for _, id := range []string{craftCardN, craftCardSR} {
+		var c int
+		db.Raw(`SELECT count FROM gacha_collection WHERE user_id = ? AND card_id = ?`, svcUser, id).Scan(&c)
+		if c != 1 {
+			t.Errorf("card %s count = %d; want 1", id, c)
+		}
+	}
+	if _, err := svc.Dismantle(ctx, svcUser, nil, true); err == nil {
+		t.Error("dismantling with no duplicates left should be rejected")
+	}
+
+	var sum int64
+	db.Raw(`SELECT COALESCE(SUM(delta), 0) FROM gacha_ledger WHERE user_id = ? AND currency = ? AND reason = ?`,
+		svcUser, domain.CurrencyShards, domain.ReasonDismantle).Scan(&sum)
+	if sum != 23 {
+		t.Errorf("shards ledger sum = %d; want 23", sum)
+	}
+}
+
+func TestCraft_ChargesShardsAndRespectsBannerRules(t *testing.T) {
+	svc, db := newCraftFixture(t)
+	ctx := context.Background()
+	db.Exec(`UPDATE gacha_wallets SET shards = 600 WHERE user_id = ?`, svcUser)
+
+	res, err := svc.Craft(ctx, svcUser, bannerA, craftCardSR)
+	if err != nil {
+		t.Fatalf("Craft: %v", err)
+	}
+	if !res.Card.New || res.Card.Count != 1 || res.Shards != 500 {
+		t.Errorf("craft SR = %+v; want new card, count 1, 500 shards left", res)
+	}
+	// The SR is on the event banner too, and it is not limited (the standard
+	// banner carries it), so crafting it there works.
+	if _, err := svc.Craft(ctx, svcUser, eventBanner, craftCardSR); err != nil {
+		t.Errorf("craft non-limited card via event banner: %v", err)
+	}
+
+	for name, tc := range map[string]struct{ banner, card string }{
+		"card not in banner": {bannerA, craftCardEvent},
+		"limited card":       {eventBanner, craftCardEvent},
+	} {
+		if _, err := svc.Craft(ctx, svcUser, tc.banner, tc.card); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	// 400 left; an SR costs 100 → 4 more, then the fifth fails with no side effects.
+	for i := 0; i < 4; i++ {
+		if _, err := svc.Craft(ctx, svcUser, bannerA, craftCardSR); err != nil {
+			t.Fatalf("craft %d: %v", i, err)
+		}
+	}
+	if _, err := svc.Craft(ctx, svcUser, bannerA, craftCardSR); err != repo.ErrInsufficientShards {
+		t.Errorf("craft with 0 shards err = %v; want ErrInsufficientShards", err)
+	}
+	var count int
+	db.Raw(`SELECT count FROM gacha_collection WHERE user_id = ? AND card_id = ?`, svcUser, craftCardSR).Scan(&count)
+	if count != 6 {
+		t.Errorf("SR count = %d; want 6", count)
+	}
+	var crafts int64
+	db.Raw(`SELECT COUNT(*) FROM gacha_ledger WHERE user_id = ? AND reason = ? AND currency = ?`,
+		svcUser, domain.ReasonCraft, domain.CurrencyShards).Scan(&crafts)
+	if crafts != 6 {
+		t.Errorf("craft ledger rows = %d; want 6", crafts)
+	}
+}
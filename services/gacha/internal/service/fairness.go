@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/repo"
+)
+
+const (
+	// seedIDLayout names a seed period by its UTC start instant.
+	seedIDLayout = "20060102T150405Z"
+
+	fairListDefault = 50
+	fairListMax     = 100
+)
+
+// fairStream is the deterministic random source of one pull. Bytes come from
+// HMAC-SHA256(key = seed, msg = "{user_id}:{nonce}:{block}") for block
+// 0, 1, 2, …; each block yields four big-endian uint64 words. IntN maps a word
+// to [0, n) by rejection sampling (words at or above the largest multiple of n
+// are skipped), so there is no modulo bias. Every value handed out is recorded
+// in draws for the audit log.
+type fairStream struct {
+	seed   []byte
+	userID string
+	nonce  int64
+	block  uint64
+	buf    []byte
+	draws  []domain.FairDraw
+}
+
+func newFairStream(seed, userID string, nonce int64) *fairStream {
+	return &fairStream{seed: []byte(seed), userID: userID, nonce: nonce}
+}
+
+func (f *fairStream) word() uint64 {
+	if len(f.buf) < 8 {
+		mac := hmac.New(sha256.New, f.seed)
+		fmt.Fprintf(mac, "%s:%d:%d", f.userID, f.nonce, f.block)
+		f.block++
+		f.buf = mac.Sum(nil)
+	}
+	w := binary.BigEndian.Uint64(f.buf[:8])
+	f.buf = f.buf[8:]
+	return w
+}
+
+// IntN has the func(int) int shape rollOne takes.
+func (f *fairStream) IntN(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	limit := math.MaxUint64 - math.MaxUint64%uint64(n)
+	w := f.word()
+	for w >= limit {
+		w = f.word()
+	}
+	v := int(w % uint64(n))
+	f.draws = append(f.draws, domain.FairDraw{N: n, Value: v})
+	return v
+}
+
+// VerifyDraws replays a pull's stream from the revealed seed and reports
+// whether it reproduces the recorded draws exactly.
+func VerifyDraws(seed, userID string, nonce int64, draws []domain.FairDraw) bool {
+	f := newFairStream(seed, userID, nonce)
+	for _, d := range draws {
+		if f.IntN(d.N) != d.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// hashSeed is the published commitment: hex SHA-256 of the hex seed string.
+func hashSeed(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
+
+// SeedView is one seed period as served publicly. Seed is empty until the
+// period has ended.
+type SeedView struct {
+	ID          string    `json:"id"`
+	Hash        string    `json:"hash"`
+	Seed        string    `json:"seed,omitempty"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Revealed    bool      `json:"revealed"`
+}
+
+// PullAudit is one entry of a user's verifiable pull history. Once the seed
+// is revealed, Verified reports whether replaying the stream reproduces
+// Draws; before that it is omitted.
+type PullAudit struct {
+	domain.PullRecord
+	SeedHash string `json:"seed_hash"`
+	Seed     string `json:"seed,omitempty"`
+	Verified *bool  `json:"verified,omitempty"`
+}
+
+// FairnessService runs the commit-reveal scheme behind every pull:
+//
+//   - Time is cut into fixed periods (GACHA_FAIR_SEED_PERIOD). The first pull
+//     of a period creates a secret random seed and publishes only its hash.
+//   - A pull is rolled from the stream HMAC(seed, user:nonce:block), where
+//     nonce is the user's pull counter; the draws are logged with the pull.
+//   - After the period ends the seed is served next to its hash, so anyone
+//     can check the hash and replay every pull of that period.
+type FairnessService struct {
+	repo   *repo.FairnessRepository
+	period time.Duration
+	now    func() time.Time
+	log    *logger.Logger
+}
+
+// NewFairnessService wires the seed/nonce/audit layer. period is the seed
+// rotation interval.
+func NewFairnessService(fairRepo *repo.FairnessRepository, period time.Duration, log *logger.Logger) *FairnessService {
+	return &FairnessService{repo: fairRepo, period: period, now: time.Now, log: log}
+}
+
+// CurrentSeed returns the seed of the period containing now, committing a new
+// one if this is the period's first use.
+func (s *FairnessService) CurrentSeed(ctx context.Context) (*domain.FairSeed, error) {
+	start := s.now().UTC().Truncate(s.period)
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	secret := hex.EncodeToString(raw)
+	seed, err := s.repo.EnsureSeed(ctx, &domain.FairSeed{
+		ID:          start.Format(seedIDLayout),
+		Hash:        hashSeed(secret),
+		Seed:        secret,
+		PeriodStart: start,
+		PeriodEnd:   start.Add(s.period),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return seed, nil
+}
+
+// Seeds returns recent seed periods, newest first, revealing the seeds of
+// periods that have ended. The current period is committed first so its hash
+// is always listed before anyone pulls on it.
+func (s *FairnessService) Seeds(ctx context.Context, limit int) ([]SeedView, error) {
+	if _, err := s.CurrentSeed(ctx); err != nil {
+		return nil, err
+	}
+	seeds, err := s.repo.ListSeeds(ctx, clampLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	out := make([]SeedView, 0, len(seeds))
+	for _, sd := range seeds {
+		v := SeedView{ID: sd.ID, Hash: sd.Hash, PeriodStart: sd.PeriodStart, PeriodEnd: sd.PeriodEnd}
+		if !now.Before(sd.PeriodEnd) {
+			v.Seed = sd.Seed
+			v.Revealed = true
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// History returns the user's pull audit log, newest first, with each pull's
+// seed commitment and — for ended periods — the seed and a verification.
+func (s *FairnessService) History(ctx context.Context, userID string, limit int) ([]PullAudit, error) {
+	recs, err := s.repo.ListRecords(ctx, userID, clampLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(recs))
+	for _, r := range recs {
+		ids = append(ids, r.SeedID)
+	}
+	seeds, err := s.repo.SeedsByID(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	out := make([]PullAudit, 0, len(recs))
+	for _, r := range recs {
+		a := PullAudit{PullRecord: r}
+		if sd, ok := seeds[r.SeedID]; ok {
+			a.SeedHash = sd.Hash
+			if !now.Before(sd.PeriodEnd) {
+				ok := hashSeed(sd.Seed) == sd.Hash && VerifyDraws(sd.Seed, r.UserID, r.Nonce, r.Draws)
+				a.Seed = sd.Seed
+				a.Verified = &ok
+			}
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+// clampLimit applies the seed / history page default and maximum.
+func clampLimit(limit int) int {
+	if limit <= 0 {
+		return fairListDefault
+	}
+	if limit > fairListMax {
+		return fairListMax
+	}
+	return limit
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/repo"
+	"gorm.io/gorm"
+)
+
+// newFairFixture is the pull fixture plus the fairness tables, with a pull
+// service rolling from the commit-reveal stream on a fixed clock.
+func newFairFixture(t *testing.T, now *time.Time) (*PullService, *FairnessService, *gorm.DB) {
+	t.Helper()
+	db := newPullSvcDB(t)
+	for _, s := range []string{
+		`CREATE TABLE gacha_fair_seeds (
+			id TEXT PRIMARY KEY, hash TEXT NOT NULL, seed TEXT NOT NULL,
+			period_start DATETIME NOT NULL, period_end DATETIME NOT NULL)`,
+		`CREATE TABLE gacha_pull_nonces (user_id TEXT PRIMARY KEY, next INTEGER NOT NULL DEFAULT 0)`,
+		`CREATE TABLE gacha_pull_log (
+			id TEXT PRIMARY KEY, user_id TEXT NOT NULL, banner_id TEXT NOT NULL, mode TEXT NOT NULL,
+			seed_id TEXT NOT NULL, nonce INTEGER NOT NULL, draws TEXT, card_ids TEXT, created_at DATETIME)`,
+	} {
+		if err := db.Exec(s).Error; err != nil {
+			t.Fatalf("DDL: %v", err)
+		}
+	}
+	fair := NewFairnessService(repo.NewFairnessRepository(db), 24*time.Hour, logger.Default())
+	fair.now = func() time.Time { return *now }
+	pull := newPullSvc(t, db, constRand(0))
+	pull.SetFairness(fair)
+	return pull, fair, db
+}
+
+func TestFairStream_DeterministicAndVerifiable(t *testing.T) {
+	a := newFairStream("seed", svcUser, 7)
+	b := newFairStream("seed", svcUser, 7)
+	for i := 0; i < 50; i++ {
+		n := 1 + i%13
+		if x, y := a.IntN(n), b.IntN(n); x != y || x < 0 || x >= n {
+			t.Fatalf("draw %d: %d vs %d (n=%d)", i, x, y, n)
+		}
+	}
+	if !VerifyDraws("seed", svcUser, 7, a.draws) {
+		t.Fatal("replay of own draws must verify")
+	}
+	if VerifyDraws("seed", svcUser, 8, a.draws) {
+		t.Error("another nonce must not reproduce the draws")
+	}
+	tampered := append([]domain.FairDraw(nil), a.draws...)
+	tampered[3].Value = (tampered[3].Value + 1) % tampered[3].N
+	if tampered[3].N > 1 && VerifyDraws("seed", svcUser, 7, tampered) {
+		t.Error("tampered draw must fail verification")
+	}
+}
+
+func TestPull_FairLogRevealedAfterPeriod(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	pull, fair, db := newFairFixture(t, &now)
+	seedBalance(t, db, 2000)
+	seedBanner(t, db, bannerA, true)
+	seedCard(t, db, bannerA, "c1", domain.RarityN)
+	seedCard(t, db, bannerA, "c2", domain.RarityR)
+	seedCard(t, db, bannerA, "c3", domain.RaritySR)
+	seedCard(t, db, bannerA, "c4", domain.RaritySSR)
+	ctx := context.Background()
+
+	first, err := pull.Pull(ctx, svcUser, bannerA, "x10")
+	if err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+	second, err := pull.Pull(ctx, svcUser, bannerA, "x1")
+	if err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+	if first.Proof == nil || second.Proof == nil {
+		t.Fatal("fair pulls must carry a proof")
+	}
+	if first.Proof.Nonce != 0 || second.Proof.Nonce != 1 || first.Proof.SeedID != "20261019T000000Z" {
+		t.Fatalf("proofs = %+v / %+v", first.Proof, second.Proof)
+	}
+
+	hist, err := fair.History(ctx, svcUser, 0)
+	if err != nil || len(hist) != 2 {
+		t.Fatalf("history = %d, %v", len(hist), err)
+	}
+	for _, h := range hist {
+		if h.Seed != "" || h.Verified != nil || h.SeedHash != first.Proof.SeedHash {
+			t.Fatalf("seed leaked before the period ended: %+v", h)
+		}
+	}
+	seeds, _ := fair.Seeds(ctx, 0)
+	if len(seeds) != 1 || seeds[0].Revealed || seeds[0].Seed != "" {
+		t.Fatalf("seeds before reveal = %+v", seeds)
+	}
+
+	now = now.Add(24 * time.Hour)
+	hist, err = fair.History(ctx, svcUser, 0)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	for _, h := range hist {
+		if h.Verified == nil || !*h.Verified || hashSeed(h.Seed) != h.SeedHash {
+			t.Fatalf("revealed pull must verify: %+v", h)
+		}
+		if len(h.Draws) == 0 || len(h.CardIDs) == 0 {
+			t.Fatalf("log entry missing draws/cards: %+v", h)
+		}
+	}
+	if hist[1].ID != first.Proof.PullID || len(hist[1].CardIDs) != 10 {
+		t.Fatalf("history order/cards = %+v", hist[1])
+	}
+}
+
+func TestOdds_MatchesTierTableWithPity(t *testing.T) {
+	pool := fullPool()
+	odds := oddsFor(bannerA, testEconomy(), pool, rateUp{})
+	if len(odds.Tiers) != 4 || odds.PityTier != domain.RaritySSR || !odds.SRFloor {
+		t.Fatalf("odds = %+v", odds)
+	}
+	ssr := odds.Tiers[3]
+	if math.Abs(ssr.Rate-0.01) > 1e-12 {
+		t.Errorf("SSR base rate = %v; want 0.01", ssr.Rate)
+	}
+	want := 0.01 / (1 - math.Pow(0.99, 90))
+	if math.Abs(odds.EffectiveRate-want) > 1e-12 {
+		t.Errorf("effective SSR rate = %v; want %v", odds.EffectiveRate, want)
+	}
+	sum := 0.0
+	for _, tr := range odds.Tiers {
+		sum += tr.Rate
+	}
+	if math.Abs(sum-1) > 1e-12 {
+		t.Errorf("tier rates sum to %v", sum)
+	}
+}
@@ -91,11 +91,26 @@ func buildTierTable(weights map[domain.Rarity]int, available map[domain.Rarity][
 	return table
 }
 
-// rollOne picks a tier by weight using randInt(total), then a uniform card from
-// that tier. When forceTier is a non-empty rarity present in the pool, it
+// rateUp is a banner's featured-card setup for a roll: the featured card IDs,
+// the percentage of a tier's rolls that go to the tier's featured cards
+// (Banner.FeaturedShare), and whether the next SSR is guaranteed featured
+// (the user lost the previous 50/50). The zero value is a flat pool.
+type rateUp struct {
+	featured   map[string]bool
+	share      int
+	guaranteed bool
+}
+
+// rollOne picks a tier by weight using randInt(total), then a card from that
+// tier. When forceTier is a non-empty rarity present in the pool, it
 // overrides the weighted pick (used for pity-forced SSR and the x10 SR-floor).
-// The table must be non-empty (guaranteed by the caller validating the pool).
-func rollOne(table []tierEntry, pool map[domain.Rarity][]domain.Card, randInt func(int) int, forceTier domain.Rarity) domain.Card {
+// If the tier has featured cards, randInt(100) < share picks among them and
+// anything else among the off-banner cards; a guaranteed SSR skips that draw
+// and goes straight to a featured card. A tier without featured cards is a
+// uniform pick, so flat banners consume exactly the draws they always did.
+// Reports whether the card is featured. The table must be non-empty
+// (guaranteed by the caller validating the pool).
+func rollOne(table []tierEntry, pool map[domain.Rarity][]domain.Card, randInt func(int) int, forceTier domain.Rarity, ru rateUp) (domain.Card, bool) {
 	var tier domain.Rarity
 	if forceTier != "" && len(pool[forceTier]) > 0 {
 		tier = forceTier
@@ -114,8 +129,46 @@ func rollOne(table []tierEntry, pool map[domain.Rarity][]domain.Card, randInt fu
 		}
 	}
 	cards := pool[tier]
-	idx := randInt(len(cards))
-	return cards[idx]
+	if feat, off := splitFeatured(cards, ru.featured); len(feat) > 0 {
+		switch {
+		case len(off) == 0, tier == domain.RaritySSR && ru.guaranteed:
+			cards = feat
+		case randInt(100) < ru.share:
+			cards = feat
+		default:
+			cards = off
+		}
+	}
+	c := cards[randInt(len(cards))]
+	return c, ru.featured[c.ID]
+}
+
+// splitFeatured partitions a tier's cards into featured and off-banner ones,
+// keeping pool order.
+func splitFeatured(cards []domain.Card, featured map[string]bool) (feat, off []domain.Card) {
+	if len(featured) == 0 {
+		return nil, cards
+	}
+	for _, c := range cards {
+		if featured[c.ID] {
+			feat = append(feat, c)
+		} else {
+			off = append(off, c)
+		}
+	}
+	return feat, off
+}
+
+// softPityWeights returns the tier weights for the pull that brings the
+// counter to pulls: past SoftPityStart every pull adds SoftPityStep to the
+// SSR weight. Only applied while the pool has SSRs, so the bonus is never
+// redistributed onto a lower tier.
+func softPityWeights(e config.EconomyConfig, pool map[domain.Rarity][]domain.Card, pulls int) map[domain.Rarity]int {
+	w := econWeights(e)
+	if e.SoftPityStart > 0 && pulls > e.SoftPityStart && len(pool[domain.RaritySSR]) > 0 {
+		w[domain.RaritySSR] += e.SoftPityStep * (pulls - e.SoftPityStart)
+	}
+	return w
 }
 
 // PulledCard is a single rolled card plus its post-pull collection status.
@@ -125,15 +178,27 @@ type PulledCard struct {
 	Count int         `json:"count"`
 }
 
-// PullResult is the outcome of a x1/x10 pull request.
+// PullResult is the outcome of a x1/x10 pull request. Proof is set when the
+// pull was rolled from the commit-reveal stream.
 type PullResult struct {
 	Cards   []PulledCard `json:"cards"`
 	Balance int64        `json:"balance"`
 	Pity    int          `json:"pity"`
+	Proof   *PullProof   `json:"proof,omitempty"`
+}
+
+// PullProof identifies the random stream a pull was rolled from: the seed
+// period, its published hash and the user's pull nonce.
+type PullProof struct {
+	PullID   string `json:"pull_id"`
+	SeedID   string `json:"seed_id"`
+	SeedHash string `json:"seed_hash"`
+	Nonce    int64  `json:"nonce"`
 }
 
 // PullService is the pull-engine use-case layer. randInt is injected so tests
-// can be deterministic; production uses math/rand/v2 (see NewSecureRand).
+// can be deterministic; production rolls from the commit-reveal stream once
+// SetFairness is wired, with randInt (NewSecureRand) as the fallback.
 type PullService struct {
 	db      *gorm.DB
 	pull    *repo.PullRepository
@@ -141,6 +206,7 @@ type PullService struct {
 	content *repo.ContentRepository
 	econ    config.EconomyConfig
 	randInt func(int) int
+	fair    *FairnessService
 	enabled bool
 	log     *logger.Logger
 }
@@ -173,6 +239,11 @@ func NewPullService(
 	}
 }
 
+// SetFairness switches Pull to the provably fair roller: every pull is drawn
+// from the current commit-reveal seed and the user's pull nonce instead of
+// randInt, and is written to the pull audit log.
+func (s *PullService) SetFairness(f *FairnessService) { s.fair = f }
+
 // NewSecureRand returns the production randInt backed by math/rand/v2
 // (auto-seeded, not cryptographic but server-side-only and statistically fine
 // for gacha odds — the spec forbids weak client-side rolls, not math/rand/v2).
@@ -225,10 +296,22 @@ func (s *PullService) Pull(ctx context.Context, userID, bannerID, mode string) (
 		return nil, apperrors.InvalidInput("banner has no cards")
 	}
 
-	table := buildTierTable(econWeights(s.econ), pool)
-	if len(table) == 0 {
+	if len(buildTierTable(econWeights(s.econ), pool)) == 0 {
 		return nil, apperrors.InvalidInput("banner has no rollable cards")
 	}
+	featured, err := s.featuredSet(ctx, bannerID)
+	if err != nil {
+		return nil, err
+	}
+	ru := rateUp{featured: featured, share: banner.FeaturedShare}
+	featuredSSR, _ := splitFeatured(pool[domain.RaritySSR], featured)
+
+	var seed *domain.FairSeed
+	if s.fair != nil {
+		if seed, err = s.fair.CurrentSeed(ctx); err != nil {
+			return nil, err
+		}
+	}
 
 	pullID := newPullID()
 	var result PullResult
@@ -239,16 +322,36 @@ func (s *PullService) Pull(ctx context.Context, userID, bannerID, mode string) (
 			return err
 		}
 
+		// Pick the random source: the user's next fair stream, or randInt.
+		randInt := s.randInt
+		var stream *fairStream
+		if seed != nil {
+			nonce, err := s.fair.repo.NextNonceTx(tx, userID)
+			if err != nil {
+				return err
+			}
+			stream = newFairStream(seed.Seed, userID, nonce)
+			randInt = stream.IntN
+		}
+
 		// b. Lock the pity row for this (user, banner).
 		pity, err := s.pull.GetPityForUpdate(tx, userID, bannerID)
 		if err != nil {
 			return err
 		}
 
-		// c. Roll each card.
+		// afterSSR resets pity and settles the 50/50: losing it (an
+		// off-banner SSR on a banner with featured SSRs) guarantees the next.
+		afterSSR := func(featured bool) {
+			pity.PullsSinceSSR = 0
+			pity.GuaranteedFeatured = len(featuredSSR) > 0 && !featured
+		}
+
+		// c. Roll each card; the tier table follows the soft-pity ramp.
 		rolled := make([]domain.Card, 0, count)
 		for i := 0; i < count; i++ {
 			pity.PullsSinceSSR++
+			table := buildTierTable(softPityWeights(s.econ, pool, pity.PullsSinceSSR), pool)
 			var force domain.Rarity
 			if pity.PullsSinceSSR >= s.econ.PityThreshold {
 				// Hard pity: force SSR if available, else the highest tier present.
@@ -258,9 +361,10 @@ func (s *PullService) Pull(ctx context.Context, userID, bannerID, mode string) (
 					force = highestAvailable(pool)
 				}
 			}
-			c := rollOne(table, pool, s.randInt, force)
+			ru.guaranteed = pity.GuaranteedFeatured
+			c, feat := rollOne(table, pool, randInt, force, ru)
 			if c.Rarity == domain.RaritySSR {
-				pity.PullsSinceSSR = 0
+				afterSSR(feat)
 			}
 			rolled = append(rolled, c)
 		}
@@ -275,11 +379,12 @@ func (s *PullService) Pull(ctx context.Context, userID, bannerID, mode string) (
 				floor = domain.RaritySSR
 			}
 			if floor != "" {
-				c := rollOne(table, pool, s.randInt, floor)
+				ru.guaranteed = pity.GuaranteedFeatured
+				c, feat := rollOne(buildTierTable(econWeights(s.econ), pool), pool, randInt, floor, ru)
 				rolled[len(rolled)-1] = c
 				if c.Rarity == domain.RaritySSR {
 					// Forced-SSR floor also resets pity.
-					pity.PullsSinceSSR = 0
+					afterSSR(feat)
 				}
 			} else {
 				// Pool has neither SR nor SSR — floor is unsatisfiable.
@@ -299,6 +404,16 @@ func (s *PullService) Pull(ctx context.Context, userID, bannerID, mode string) (
 		if err := s.pull.SavePityTx(tx, pity); err != nil {
 			return err
 		}
+		if stream != nil {
+			rec := &domain.PullRecord{
+				ID: pullID, UserID: userID, BannerID: bannerID, Mode: mode,
+				SeedID: seed.ID, Nonce: stream.nonce, Draws: stream.draws, CardIDs: ids,
+			}
+			if err := s.fair.repo.CreateRecordTx(tx, rec); err != nil {
+				return err
+			}
+			result.Proof = &PullProof{PullID: pullID, SeedID: seed.ID, SeedHash: seed.Hash, Nonce: stream.nonce}
+		}
 
 		// Build the result (inside the tx so it reflects committed state).
 		// Mark New only on the FIRST occurrence of a freshly-obtained card.
@@ -328,6 +443,19 @@ func (s *PullService) Pull(ctx context.Context, userID, bannerID, mode string) (
 	return &result, nil
 }
 
+// featuredSet returns the banner's featured card IDs as a set.
+func (s *PullService) featuredSet(ctx context.Context, bannerID string) (map[string]bool, error) {
+	ids, err := s.banners.FeaturedCardIDs(ctx, bannerID)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set, nil
+}
+
 // econWeights returns the tier-weight map from config.
 func econWeights(e config.EconomyConfig) map[domain.Rarity]int {
 	return map[domain.Rarity]int{
@@ -396,9 +524,11 @@ type BannerCardView struct {
 	// BackPath is the optional card-back image key; frontend falls back to branded default when empty.
 	BackPath string `json:"back_path"`
 	Owned    bool   `json:"owned"`
+	Featured bool   `json:"featured"`
 }
 
 // BannerView is one active banner with its pool + the caller's pity progress.
+// MyGuaranteed reports that the caller's next SSR here is a featured card.
 type BannerView struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
@@ -408,7 +538,9 @@ type BannerView struct {
 	IsStandard    bool             `json:"is_standard"`
 	Cards         []BannerCardView `json:"cards"`
 	MyPity        int              `json:"my_pity"`
+	MyGuaranteed  bool             `json:"my_guaranteed"`
 	PityThreshold int              `json:"pity_threshold"`
+	FeaturedShare int              `json:"featured_share"`
 }
 
 // ActiveBannersView returns the banners active right now, each with its card
@@ -434,12 +566,17 @@ func (s *PullService) ActiveBannersView(ctx context.Context, userID string) ([]B
 		if err != nil {
 			return nil, err
 		}
+		featured, err := s.featuredSet(ctx, b.ID)
+		if err != nil {
+			return nil, err
+		}
 		cards := make([]BannerCardView, 0)
 		for _, tier := range rarityOrder {
 			for _, c := range pool[tier] {
 				cards = append(cards, BannerCardView{
 					ID: c.ID, Name: c.Name, Rarity: c.Rarity,
 					ImagePath: c.ImagePath, BackPath: c.BackPath, Owned: owned[c.ID] > 0,
+					Featured: featured[c.ID],
 				})
 			}
 		}
@@ -450,7 +587,8 @@ func (s *PullService) ActiveBannersView(ctx context.Context, userID string) ([]B
 		views = append(views, BannerView{
 			ID: b.ID, Name: b.Name, Description: b.Description,
 			BackdropPath: b.BackdropPath, IsStandard: b.IsStandard, Cards: cards,
-			MyPity: pity, PityThreshold: s.econ.PityThreshold,
+			MyPity: pity.PullsSinceSSR, MyGuaranteed: pity.GuaranteedFeatured,
+			PityThreshold: s.econ.PityThreshold, FeaturedShare: b.FeaturedShare,
 		})
 	}
 	return views, nil
@@ -526,6 +664,145 @@ func (s *PullService) CollectionView(ctx context.Context, userID string) (*Colle
 	return out, nil
 }
 
+// TierOdds is one rarity tier's share of a banner's roll table.
+type TierOdds struct {
+	Rarity  domain.Rarity `json:"rarity"`
+	Rate    float64       `json:"rate"`     // chance per pull before pity
+	Cards   int           `json:"cards"`    // cards in the tier
+	PerCard float64       `json:"per_card"` // chance per off-banner card (every card on a flat tier)
+	// Featured counts the tier's rate-up cards; FeaturedPerCard is the
+	// chance per featured card, outside the 50/50 guarantee.
+	Featured        int     `json:"featured"`
+	FeaturedPerCard float64 `json:"featured_per_card,omitempty"`
+}
+
+// BannerOdds is the public odds sheet of a banner, computed from the same
+// tier tables Pull rolls against.
+type BannerOdds struct {
+	BannerID      string     `json:"banner_id"`
+	Tiers         []TierOdds `json:"tiers"`
+	PityThreshold int        `json:"pity_threshold"`
+	// SoftPityStart is the last pull before the SSR weight starts to climb
+	// by SoftPityStep per pull (0 = no soft pity).
+	SoftPityStart int `json:"soft_pity_start"`
+	SoftPityStep  int `json:"soft_pity_step"`
+	// PityTier is what hard pity forces: SSR, or the highest tier present
+	// when the pool has no SSR.
+	PityTier domain.Rarity `json:"pity_tier"`
+	// EffectiveRate is the long-run share of PityTier per pull once soft and
+	// hard pity are counted in (1 / expected pulls per PityTier card).
+	EffectiveRate float64 `json:"effective_rate"`
+	// FeaturedShare is the banner's rate-up split; FeaturedSSRShare is the
+	// long-run share of SSRs that are featured once the 50/50 guarantee is
+	// counted in (0 when the banner has no featured SSR).
+	FeaturedShare    int     `json:"featured_share"`
+	FeaturedSSRShare float64 `json:"featured_ssr_share"`
+	// SRFloor is the x10 guarantee of at least one SR or better.
+	SRFloor bool `json:"sr_floor"`
+}
+
+// Odds returns the public odds of an active banner (GET
+// /api/gacha/banners/{id}/odds). Inactive or unknown banners are NotFound.
+func (s *PullService) Odds(ctx context.Context, bannerID string) (*BannerOdds, error) {
+	if !s.enabled {
+		return nil, apperrors.NotFound("banner")
+	}
+	banner, err := s.banners.GetBanner(ctx, bannerID)
+	if err != nil {
+		return nil, err
+	}
+	if !bannerActiveNow(banner, time.Now()) {
+		return nil, apperrors.NotFound("banner")
+	}
+	pool, err := s.pull.CardsByRarity(ctx, bannerID)
+	if err != nil {
+		return nil, err
+	}
+	featured, err := s.featuredSet(ctx, bannerID)
+	if err != nil {
+		return nil, err
+	}
+	return oddsFor(bannerID, s.econ, pool, rateUp{featured: featured, share: banner.FeaturedShare}), nil
+}
+
+// oddsFor turns the tier tables into rates. With p_k the pity tier's rate on
+// the k-th pull since the last one (the soft-pity ramp) and hard pity at pull
+// T (p_T = 1), the expected pulls per pity-tier card are
+// Σ_{k=1}^{T} Π_{j<k} (1 - p_j), and the effective rate is its inverse. With
+// featured share s, a lost 50/50 makes the next SSR featured, so a
+// fraction 1/(2-s) of all SSRs end up featured.
+func oddsFor(bannerID string, e config.EconomyConfig, pool map[domain.Rarity][]domain.Card, ru rateUp) *BannerOdds {
+	out := &BannerOdds{
+		BannerID: bannerID, Tiers: []TierOdds{}, PityThreshold: e.PityThreshold,
+		SoftPityStart: e.SoftPityStart, SoftPityStep: e.SoftPityStep, FeaturedShare: ru.share,
+	}
+	table := buildTierTable(econWeights(e), pool)
+	if len(table) == 0 {
+		return out
+	}
+	out.PityTier = domain.RaritySSR
+	if len(pool[domain.RaritySSR]) == 0 {
+		out.PityTier = highestAvailable(pool)
+	}
+	share := float64(ru.share) / 100
+	for i, r := range tierRates(table) {
+		tier := table[i].rarity
+		feat, off := splitFeatured(pool[tier], ru.featured)
+		t := TierOdds{Rarity: tier, Rate: r, Cards: len(pool[tier]), Featured: len(feat)}
+		switch {
+		case len(feat) == 0:
+			t.PerCard = r / float64(len(off))
+		case len(off) == 0:
+			t.FeaturedPerCard = r / float64(len(feat))
+		default:
+			t.FeaturedPerCard = r * share / float64(len(feat))
+			t.PerCard = r * (1 - share) / float64(len(off))
+		}
+		out.Tiers = append(out.Tiers, t)
+		if tier == domain.RaritySR || tier == domain.RaritySSR {
+			out.SRFloor = true
+		}
+		if tier == domain.RaritySSR && len(feat) > 0 {
+			out.FeaturedSSRShare = 1
+			if len(off) > 0 {
+				out.FeaturedSSRShare = 1 / (2 - share)
+			}
+		}
+	}
+
+	expected, miss := 0.0, 1.0
+	for k := 1; k <= e.PityThreshold; k++ {
+		expected += miss
+		miss *= 1 - pityTierRate(buildTierTable(softPityWeights(e, pool, k), pool), out.PityTier)
+	}
+	if expected > 0 {
+		out.EffectiveRate = 1 / expected
+	}
+	return out
+}
+
+// tierRates returns each table entry's share of the total weight.
+func tierRates(table []tierEntry) []float64 {
+	total := float64(table[len(table)-1].cumulative)
+	out := make([]float64, len(table))
+	prev := 0
+	for i, e := range table {
+		out[i] = float64(e.cumulative-prev) / total
+		prev = e.cumulative
+	}
+	return out
+}
+
+// pityTierRate is tier's share of the table.
+func pityTierRate(table []tierEntry, tier domain.Rarity) float64 {
+	for i, r := range tierRates(table) {
+		if table[i].rarity == tier {
+			return r
+		}
+	}
+	return 0
+}
+
 // ownedSet returns a map of cardID → owned count for the user.
 func (s *PullService) ownedSet(ctx context.Context, userID string) (map[string]int, error) {
 	entries, err := s.pull.ListCollection(ctx, userID)
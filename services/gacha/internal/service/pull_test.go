@@ -117,17 +117,20 @@ func newPullSvcDB(t *testing.T) *gorm.DB {
 	}
 	stmts := []string{
 		`CREATE TABLE gacha_wallets (
-			user_id TEXT PRIMARY KEY, balance INTEGER NOT NULL DEFAULT 0,
+			user_id TEXT PRIMARY KEY, balance INTEGER NOT NULL DEFAULT 0, shards INTEGER NOT NULL DEFAULT 0,
 			starter_granted INTEGER NOT NULL DEFAULT 0, daily_streak INTEGER NOT NULL DEFAULT 0,
 			last_daily_at DATETIME, created_at DATETIME, updated_at DATETIME)`,
 		`CREATE TABLE gacha_ledger (
 			id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))), user_id TEXT NOT NULL,
-			delta INTEGER NOT NULL, reason TEXT NOT NULL, ref TEXT NOT NULL DEFAULT '', created_at DATETIME)`,
+			delta INTEGER NOT NULL, reason TEXT NOT NULL, ref TEXT NOT NULL DEFAULT '', currency TEXT NOT NULL DEFAULT 'enigma', created_at DATETIME)`,
 		`CREATE UNIQUE INDEX idx_ledger_dedup ON gacha_ledger(user_id, reason, ref) WHERE ref <> ''`,
 		`CREATE TABLE gacha_collection (
-			user_id TEXT NOT NULL, card_id TEXT NOT NULL, count INTEGER NOT NULL DEFAULT 1, first_obtained_at DATETIME)`,
+			user_id TEXT NOT NULL, card_id TEXT NOT NULL, count INTEGER NOT NULL DEFAULT 1, first_obtained_at DATETIME, last_obtained_at DATETIME,
+			trade_only NUMERIC NOT NULL DEFAULT 0)`,
 		`CREATE UNIQUE INDEX idx_user_card ON gacha_collection(user_id, card_id)`,
-		`CREATE TABLE gacha_pity (user_id TEXT NOT NULL, banner_id TEXT NOT NULL, pulls_since_ssr INTEGER NOT NULL DEFAULT 0)`,
+		`CREATE TABLE gacha_pity (
+			user_id TEXT NOT NULL, banner_id TEXT NOT NULL, pulls_since_ssr INTEGER NOT NULL DEFAULT 0,
+			guaranteed_featured INTEGER NOT NULL DEFAULT 0)`,
 		`CREATE UNIQUE INDEX idx_user_banner ON gacha_pity(user_id, banner_id)`,
 		`CREATE TABLE gacha_cards (
 			id TEXT PRIMARY KEY, name TEXT NOT NULL, source_title TEXT, image_path TEXT NOT NULL,
@@ -138,8 +141,9 @@ func newPullSvcDB(t *testing.T) *gorm.DB {
 			backdrop_path TEXT NOT NULL DEFAULT '',
 			is_standard INTEGER NOT NULL DEFAULT 0, enabled INTEGER NOT NULL DEFAULT 0,
 			active_from DATETIME, active_to DATETIME, sort_order INTEGER NOT NULL DEFAULT 0,
+			featured_share INTEGER NOT NULL DEFAULT 50,
 			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
-		`CREATE TABLE gacha_banner_cards (banner_id TEXT NOT NULL, card_id TEXT NOT NULL)`,
+		`CREATE TABLE gacha_banner_cards (banner_id TEXT NOT NULL, card_id TEXT NOT NULL, featured INTEGER NOT NULL DEFAULT 0)`,
 		`CREATE UNIQUE INDEX idx_banner_card ON gacha_banner_cards(banner_id, card_id)`,
 	}
 	for _, s := range stmts {
@@ -623,5 +627,132 @@ func TestPull_Distribution_Sanity(t *testing.T) {
 	}
 }
 
+// ─── rate-up / 50-50 / soft pity ────────────────────────────────────────────
+
+// ssrPool has one featured and one off-banner SSR.
+func ssrPool() (map[domain.Rarity][]domain.Card, map[string]bool) {
+	pool := map[domain.Rarity][]domain.Card{
+		domain.RarityN:   {card("n1", domain.RarityN)},
+		domain.RaritySSR: {card("feat", domain.RaritySSR), card("off", domain.RaritySSR)},
+	}
+	return pool, map[string]bool{"feat": true}
+}
+
+func TestRollOne_FeaturedShareCoin(t *testing.T) {
+	pool, featured := ssrPool()
+	table := buildTierTable(defaultWeights(), pool)
+	ru := rateUp{featured: featured, share: 50}
+
+	// Forced SSR: draws are coin (randInt(100)) then card index.
+	c, feat := rollOne(table, pool, seqRand([]int{49, 0}), domain.RaritySSR, ru)
+	if c.ID != "feat" || !feat {
+		t.Errorf("coin 49 < 50: got %s featured=%v; want feat", c.ID, feat)
+	}
+	c, feat = rollOne(table, pool, seqRand([]int{50, 0}), domain.RaritySSR, ru)
+	if c.ID != "off" || feat {
+		t.Errorf("coin 50 >= 50: got %s featured=%v; want off", c.ID, feat)
+	}
+}
+
+func TestRollOne_GuaranteeSkipsCoin(t *testing.T) {
+	pool, featured := ssrPool()
+	table := buildTierTable(defaultWeights(), pool)
+	calls := 0
+	rnd := func(n int) int { calls++; return n - 1 }
+
+	c, feat := rollOne(table, pool, rnd, domain.RaritySSR, rateUp{featured: featured, share: 50, guaranteed: true})
+	if c.ID != "feat" || !feat {
+		t.Errorf("guaranteed SSR = %s; want feat", c.ID)
+	}
+	if calls != 1 {
+		t.Errorf("draws = %d; want 1 (card index only, no coin)", calls)
+	}
+}
+
+func TestRollOne_FlatTierDrawsUnchanged(t *testing.T) {
+	pool, featured := ssrPool()
+	table := buildTierTable(defaultWeights(), pool)
+	calls := 0
+	rnd := func(n int) int { calls++; return 0 }
+
+	// Tier pick 0 lands in N, which has no featured card: tier + card only.
+	c, feat := rollOne(table, pool, rnd, "", rateUp{featured: featured, share: 50})
+	if c.ID != "n1" || feat || calls != 2 {
+		t.Errorf("flat tier: card %s featured=%v draws=%d; want n1/false/2", c.ID, feat, calls)
+	}
+}
+
+func TestSoftPityWeights_Ramp(t *testing.T) {
+	e := testEconomy()
+	e.SoftPityStart, e.SoftPityStep = 74, 6
+	pool := fullPool()
+	for pulls, want := range map[int]int{1: 1, 74: 1, 75: 7, 80: 37} {
+		if got := softPityWeights(e, pool, pulls)[domain.RaritySSR]; got != want {
+			t.Errorf("SSR weight at pull %d = %d; want %d", pulls, got, want)
+		}
+	}
+	noSSR := map[domain.Rarity][]domain.Card{domain.RarityN: {card("n1", domain.RarityN)}}
+	if got := softPityWeights(e, noSSR, 89)[domain.RaritySSR]; got != 1 {
+		t.Errorf("soft pity without SSRs must not add weight, got %d", got)
+	}
+}
+
+func TestPull_LostFiftyFiftyGuaranteesNextSSR(t *testing.T) {
+	db := newPullSvcDB(t)
+	seedBalance(t, db, 1000)
+	seedBanner(t, db, bannerA, true)
+	seedCard(t, db, bannerA, "feat", domain.RaritySSR)
+	seedCard(t, db, bannerA, "off", domain.RaritySSR)
+	db.Exec(`UPDATE gacha_banner_cards SET featured = 1 WHERE card_id = 'feat'`)
+
+	e := testEconomy()
+	e.WeightN, e.WeightR, e.WeightSR, e.WeightSSR = 0, 0, 0, 1
+	// Pull 1: tier, coin 99 (lose), card → "off". Pull 2: tier, card (no
+	// coin, guaranteed) → "feat". Pull 3: tier, coin 0 (win), card → "feat".
+	rnd := seqRand([]int{0, 99, 0, 0, 0, 0, 0, 0})
+	svc := NewPullService(repo.NewPullRepository(db), repo.NewBannerRepository(db), repo.NewContentRepository(db), e, rnd, true, logger.Default())
+
+	guaranteed := func() bool {
+		var g bool
+		db.Raw(`SELECT guaranteed_featured FROM gacha_pity WHERE user_id = ? AND banner_id = ?`, svcUser, bannerA).Scan(&g)
+		return g
+	}
+	for i, want := range []struct {
+		card       string
+		guaranteed bool
+	}{{"off", true}, {"feat", false}, {"feat", false}} {
+		res, err := svc.Pull(context.Background(), svcUser, bannerA, "x1")
+		if err != nil {
+			t.Fatalf("pull %d: %v", i+1, err)
+		}
+		if got := res.Cards[0].Card.ID; got != want.card {
+			t.Errorf("pull %d = %s; want %s", i+1, got, want.card)
+		}
+		if guaranteed() != want.guaranteed {
+			t.Errorf("after pull %d guaranteed = %v; want %v", i+1, guaranteed(), want.guaranteed)
+		}
+	}
+}
+
+func TestOdds_SoftPityAndFeaturedShare(t *testing.T) {
+	pool, featured := ssrPool()
+	flat := testEconomy()
+	soft := flat
+	soft.SoftPityStart, soft.SoftPityStep = 74, 6
+
+	base := oddsFor(bannerA, flat, pool, rateUp{featured: featured, share: 50})
+	ramp := oddsFor(bannerA, soft, pool, rateUp{featured: featured, share: 50})
+	if ramp.EffectiveRate <= base.EffectiveRate {
+		t.Errorf("soft pity must raise the effective SSR rate: %v <= %v", ramp.EffectiveRate, base.EffectiveRate)
+	}
+	if d := base.FeaturedSSRShare - 2.0/3; d > 1e-12 || d < -1e-12 {
+		t.Errorf("featured SSR share at 50/50 = %v; want 2/3", base.FeaturedSSRShare)
+	}
+	ssr := base.Tiers[len(base.Tiers)-1]
+	if ssr.Featured != 1 || ssr.FeaturedPerCard != ssr.PerCard {
+		t.Errorf("SSR tier = %+v; want 1 featured at half the tier rate each side", ssr)
+	}
+}
+
 // guard against unused import when time helpers trimmed.
 var _ = time.Now
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/config"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/repo"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// tradeMaxLines caps distinct cards per side of one offer.
+	tradeMaxLines = 10
+	// tradeHistoryDefault / tradeHistoryMax bound GET /trades.
+	tradeHistoryDefault = 50
+	tradeHistoryMax     = 100
+)
+
+// TradeCard is one card line of a trade request: Count copies of CardID.
+type TradeCard struct {
+	CardID string `json:"card_id"`
+	Count  int    `json:"count"`
+}
+
+// TradeInput is a new offer or counter-offer as seen by its author: what they
+// give (cards + optional «Энигмы») for what they want from the other side.
+// RecipientID is ignored on a counter — it always goes back to the proposer.
+type TradeInput struct {
+	RecipientID     string
+	OfferedCards    []TradeCard
+	OfferedCurrency int64
+	RequestedCards  []TradeCard
+}
+
+// TradeService is the card-trading use-case layer. Every step is one
+// transaction:
+//
+//   - Propose / Counter escrow the author's offered side — the cards leave
+//     their collection and the currency is debited (trade_escrow) — so a
+//     pending offer can never be double-spent.
+//   - Accept flips pending→accepted with a compare-and-set, takes the
+//     requested cards from the recipient, hands both card sets over and pays
+//     the escrowed currency to the recipient (trade_in).
+//   - Cancel (proposer) / decline (recipient) / counter return the escrow to
+//     the proposer (trade_refund).
+//
+// Anti-abuse: an SSR can't be traded within TradeSSRCooldown of being
+// obtained (pulled or received), and each user gets TradeDailyCap trade
+// actions (offers made + offers accepted) per UTC day.
+type TradeService struct {
+	db      *gorm.DB
+	trades  *repo.TradeRepository
+	econ    config.EconomyConfig
+	enabled bool
+	now     func() time.Time
+	log     *logger.Logger
+}
+
+// NewTradeService wires the trade engine. enabled is the GACHA_ENABLED
+// dark-ship toggle: when false every write is rejected and history is empty
+// (mirrors PullService).
+func NewTradeService(tradeRepo *repo.TradeRepository, econ config.EconomyConfig, enabled bool, log *logger.Logger) *TradeService {
+	return &TradeService{
+		db:      tradeRepo.DB(),
+		trades:  tradeRepo,
+		econ:    econ,
+		enabled: enabled,
+		now:     time.Now,
+		log:     log,
+	}
+}
+
+// Propose creates an offer from userID to in.RecipientID and escrows the
+// offered side.
+func (s *TradeService) Propose(ctx context.Context, userID string, in TradeInput) (*domain.TradeOffer, error) {
+	if !s.enabled {
+		return nil, apperrors.InvalidInput("gacha is disabled")
+	}
+	if in.RecipientID == "" || in.RecipientID == userID {
+		return nil, apperrors.InvalidInput("recipient must be another player")
+	}
+	offer, err := s.newOffer(userID, in.RecipientID, in)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.trades.WalletExists(ctx, in.RecipientID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, apperrors.NotFound("gacha player")
+	}
+	if err := s.checkDailyCap(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return s.escrowTx(tx, offer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.log.Infow("trade offer created", "trade_id", offer.ID, "proposer_id", userID, "recipient_id", in.RecipientID)
+	return s.trades.GetOffer(ctx, offer.ID)
+}
+
+// Counter replaces a pending offer userID received with a new offer back to
+// its proposer. The original is marked countered and its escrow refunded in
+// the same transaction that escrows the counter.
+func (s *TradeService) Counter(ctx context.Context, userID, tradeID string, in TradeInput) (*domain.TradeOffer, error) {
+	if !s.enabled {
+		return nil, apperrors.InvalidInput("gacha is disabled")
+	}
+	orig, err := s.Get(ctx, userID, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	if orig.RecipientID != userID {
+		return nil, apperrors.InvalidInput("only the recipient can counter an offer")
+	}
+	counter, err := s.newOffer(userID, orig.ProposerID, in)
+	if err != nil {
+		return nil, err
+	}
+	counter.CounterOfID = &orig.ID
+	if err := s.checkDailyCap(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.trades.ResolveTx(tx, orig.ID, "recipient_id", userID, domain.TradeCountered, counter.CreatedAt); err != nil {
+			return err
+		}
+		if err := s.trades.RefundEscrowTx(tx, orig); err != nil {
+			return err
+		}
+		return s.escrowTx(tx, counter)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.log.Infow("trade offer countered", "trade_id", orig.ID, "counter_id", counter.ID, "user_id", userID)
+	return s.trades.GetOffer(ctx, counter.ID)
+}
+
+// Accept completes a pending offer userID received.
+func (s *TradeService) Accept(ctx context.Context, userID, tradeID string) (*domain.TradeOffer, error) {
+	if !s.enabled {
+		return nil, apperrors.InvalidInput("gacha is disabled")
+	}
+	offer, err := s.Get(ctx, userID, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	if offer.RecipientID != userID {
+		return nil, apperrors.InvalidInput("only the recipient can accept an offer")
+	}
+	if err := s.checkDailyCap(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	requested := offer.ItemsOn(domain.TradeSideRequested)
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.trades.ResolveTx(tx, offer.ID, "recipient_id", userID, domain.TradeAccepted, now); err != nil {
+			return err
+		}
+		if err := s.checkFreshSSRTx(tx, userID, requested, now); err != nil {
+			return err
+		}
+		for _, it := range requested {
+			if err := s.trades.TakeCardsTx(tx, userID, it.CardID, it.Count); err != nil {
+				return err
+			}
+			if err := s.trades.GiveCardsTx(tx, offer.ProposerID, it.CardID, it.Count, now); err != nil {
+				return err
+			}
+		}
+		for _, it := range offer.ItemsOn(domain.TradeSideOffered) {
+			if err := s.trades.GiveCardsTx(tx, userID, it.CardID, it.Count, now); err != nil {
+				return err
+			}
+		}
+		if offer.OfferedCurrency > 0 {
+			return repo.CreditTx(tx, userID, offer.OfferedCurrency, domain.ReasonTradeIn, offer.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.log.Infow("trade accepted", "trade_id", offer.ID, "proposer_id", offer.ProposerID, "recipient_id", userID)
+	return s.trades.GetOffer(ctx, offer.ID)
+}
+
+// Cancel withdraws (proposer) or declines (recipient) a pending offer and
+// refunds the escrow.
+func (s *TradeService) Cancel(ctx context.Context, userID, tradeID string) (*domain.TradeOffer, error) {
+	if !s.enabled {
+		return nil, apperrors.InvalidInput("gacha is disabled")
+	}
+	offer, err := s.Get(ctx, userID, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	column, status := "proposer_id", domain.TradeCancelled
+	if offer.RecipientID == userID {
+		column, status = "recipient_id", domain.TradeDeclined
+	}
+
+	now := s.now().UTC()
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.trades.ResolveTx(tx, offer.ID, column, userID, status, now); err != nil {
+			return err
+		}
+		return s.trades.RefundEscrowTx(tx, offer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.log.Infow("trade offer closed", "trade_id", offer.ID, "user_id", userID, "status", status)
+	return s.trades.GetOffer(ctx, offer.ID)
+}
+
+// Get returns one offer userID is a party to. Anyone else's offer reads as
+// NotFound so trade IDs cannot be probed.
+func (s *TradeService) Get(ctx context.Context, userID, tradeID string) (*domain.TradeOffer, error) {
+	offer, err := s.trades.GetOffer(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+	if offer.ProposerID != userID && offer.RecipientID != userID {
+		return nil, apperrors.NotFound("trade offer")
+	}
+	return offer, nil
+}
+
+// History returns the offers userID made or received, newest first. status
+// "" means any; limit <= 0 means the default page.
+func (s *TradeService) History(ctx context.Context, userID, status string, limit int) ([]domain.TradeOffer, error) {
+	if !s.enabled {
+		return []domain.TradeOffer{}, nil
+	}
+	if limit <= 0 {
+		limit = tradeHistoryDefault
+	}
+	if limit > tradeHistoryMax {
+		limit = tradeHistoryMax
+	}
+	return s.trades.ListForUser(ctx, userID, status, limit)
+}
+
+// newOffer validates in and builds the pending offer from proposerID to
+// recipientID. Repeated card lines on one side are merged.
+func (s *TradeService) newOffer(proposerID, recipientID string, in TradeInput) (*domain.TradeOffer, error) {
+	if in.OfferedCurrency < 0 {
+		return nil, apperrors.InvalidInput("offered currency must be >= 0")
+	}
+	offered, err := mergeTradeCards(in.OfferedCards)
+	if err != nil {
+		return nil, err
+	}
+	requested, err := mergeTradeCards(in.RequestedCards)
+	if err != nil {
+		return nil, err
+	}
+	if len(offered) == 0 && in.OfferedCurrency == 0 {
+		return nil, apperrors.InvalidInput("offer must include cards or currency")
+	}
+	if len(requested) == 0 {
+		return nil, apperrors.InvalidInput("offer must request at least one card")
+	}
+
+	offer := &domain.TradeOffer{
+		ID:              uuid.NewString(),
+		ProposerID:      proposerID,
+		RecipientID:     recipientID,
+		OfferedCurrency: in.OfferedCurrency,
+		Status:          domain.TradePending,
+		CreatedAt:       s.now().UTC(),
+	}
+	for _, c := range offered {
+		offer.Items = append(offer.Items, domain.TradeItem{TradeID: offer.ID, Side: domain.TradeSideOffered, CardID: c.CardID, Count: c.Count})
+	}
+	for _, c := range requested {
+		offer.Items = append(offer.Items, domain.TradeItem{TradeID: offer.ID, Side: domain.TradeSideRequested, CardID: c.CardID, Count: c.Count})
+	}
+	return offer, nil
+}
+
+// mergeTradeCards validates one side's lines and merges repeats of a card,
+// keeping first-seen order.
+func mergeTradeCards(cards []TradeCard) ([]TradeCard, error) {
+	out := make([]TradeCard, 0, len(cards))
+	idx := make(map[string]int, len(cards))
+	for _, c := range cards {
+		if c.CardID == "" || c.Count <= 0 {
+			return nil, apperrors.InvalidInput("each card needs a card_id and a positive count")
+		}
+		if i, ok := idx[c.CardID]; ok {
+			out[i].Count += c.Count
+			continue
+		}
+		idx[c.CardID] = len(out)
+		out = append(out, c)
+	}
+	if len(out) > tradeMaxLines {
+		return nil, apperrors.InvalidInput(fmt.Sprintf("at most %d different cards per side", tradeMaxLines))
+	}
+	return out, nil
+}
+
+// escrowTx takes the offer's offered side from its proposer and stores the
+// offer. Fresh SSRs, missing copies and an insufficient balance all abort the
+// surrounding transaction.
+func (s *TradeService) escrowTx(tx *gorm.DB, offer *domain.TradeOffer) error {
+	offered := offer.ItemsOn(domain.TradeSideOffered)
+	if err := s.checkFreshSSRTx(tx, offer.ProposerID, offered, offer.CreatedAt); err != nil {
+		return err
+	}
+	for _, it := range offered {
+		if err := s.trades.TakeCardsTx(tx, offer.ProposerID, it.CardID, it.Count); err != nil {
+			return err
+		}
+	}
+	if offer.OfferedCurrency > 0 {
+		if err := repo.DebitTx(tx, offer.ProposerID, offer.OfferedCurrency, domain.ReasonTradeEscrow, offer.ID); err != nil {
+			return err
+		}
+	}
+	return s.trades.CreateOfferTx(tx, offer)
+}
+
+// checkFreshSSRTx rejects the trade when any of userID's items is an SSR
+// they obtained within the cooldown.
+func (s *TradeService) checkFreshSSRTx(tx *gorm.DB, userID string, items []domain.TradeItem, now time.Time) error {
+	if s.econ.TradeSSRCooldown <= 0 || len(items) == 0 {
+		return nil
+	}
+	ids := make([]string, len(items))
+	for i, it := range items {
+		ids[i] = it.CardID
+	}
+	fresh, err := s.trades.FreshSSRTx(tx, userID, ids, now.Add(-s.econ.TradeSSRCooldown))
+	if err != nil {
+		return err
+	}
+	if len(fresh) > 0 {
+		return apperrors.InvalidInput(fmt.Sprintf("SSR card %s was obtained too recently to trade", fresh[0])).
+			WithDetail("card_id", fresh[0])
+	}
+	return nil
+}
+
+// checkDailyCap rejects a trade action once userID has used today's (UTC)
+// allowance.
+func (s *TradeService) checkDailyCap(ctx context.Context, userID string) error {
+	today := s.now().UTC().Truncate(24 * time.Hour)
+	n, err := s.trades.CountActionsSince(ctx, userID, today)
+	if err != nil {
+		return err
+	}
+	if n >= int64(s.econ.TradeDailyCap) {
+		return apperrors.New(apperrors.CodeRateLimited, "daily trade limit reached")
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/config"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/gacha/internal/repo"
+	"gorm.io/gorm"
+)
+
+const (
+	tradeAlice = "aaaaaaaa-0000-0000-0000-000000000001"
+	tradeBob   = "bbbbbbbb-0000-0000-0000-000000000002"
+)
+
+// newTradeFixture builds the pull-service schema plus the trade tables, two
+// players with 500 «Энигмы» each and a clock fixed at noon UTC.
+func newTradeFixture(t *testing.T) (*TradeService, *gorm.DB, time.Time) {
+	t.Helper()
+	db := newPullSvcDB(t)
+	for _, s := range []string{
+		`CREATE TABLE gacha_trades (
+			id TEXT PRIMARY KEY, proposer_id TEXT NOT NULL, recipient_id TEXT NOT NULL,
+			offered_currency INTEGER NOT NULL DEFAULT 0, status TEXT NOT NULL, counter_of_id TEXT,
+			created_at DATETIME, resolved_at DATETIME)`,
+		`CREATE TABLE gacha_trade_items (trade_id TEXT NOT NULL, side TEXT NOT NULL, card_id TEXT NOT NULL, count INTEGER NOT NULL)`,
+		`CREATE UNIQUE INDEX idx_trade_item ON gacha_trade_items(trade_id, side, card_id)`,
+	} {
+		if err := db.Exec(s).Error; err != nil {
+			t.Fatalf("DDL: %v", err)
+		}
+	}
+	for _, u := range []string{tradeAlice, tradeBob} {
+		if err := db.Exec(`INSERT INTO gacha_wallets (user_id, balance) VALUES (?, 500)`, u).Error; err != nil {
+			t.Fatalf("seed wallet: %v", err)
+		}
+	}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	econ := testEconomy()
+	econ.TradeSSRCooldown = 7 * 24 * time.Hour
+	econ.TradeDailyCap = 3
+	svc := NewTradeService(repo.NewTradeRepository(db), econ, true, logger.Default())
+	svc.now = func() time.Time { return now }
+	return svc, db, now
+}
+
+// own gives userID n copies of a card of rarity r, last obtained at `at`.
+func own(t *testing.T, db *gorm.DB, userID, cardID string, r domain.Rarity, n int, at time.Time) {
+	t.Helper()
+	db.Exec(`INSERT OR IGNORE INTO gacha_cards (id, name, image_path, rarity, enabled) VALUES (?, ?, ?, ?, 1)`,
+		cardID, cardID, "cards/"+cardID+".webp", r)
+	if err := db.Exec(`INSERT INTO gacha_collection (user_id, card_id, count, first_obtained_at, last_obtained_at) VALUES (?, ?, ?, ?, ?)`,
+		userID, cardID, n, at, at).Error; err != nil {
+		t.Fatalf("seed collection: %v", err)
+	}
+}
+
+func countOf(t *testing.T, db *gorm.DB, userID, cardID string) int {
+	t.Helper()
+	var c int
+	db.Raw(`SELECT count FROM gacha_collection WHERE user_id = ? AND card_id = ?`, userID, cardID).Scan(&c)
+	return c
+}
+
+func balanceOf(t *testing.T, db *gorm.DB, userID string) int64 {
+	t.Helper()
+	var b int64
+	db.Raw(`SELECT balance FROM gacha_wallets WHERE user_id = ?`, userID).Scan(&b)
+	return b
+}
+
+func TestTrade_ProposeEscrowsAndAcceptMovesEverything(t *testing.T) {
+	svc, db, now := newTradeFixture(t)
+	ctx := context.Background()
+	old := now.Add(-30 * 24 * time.Hour)
+	own(t, db, tradeAlice, "card-sr", domain.RaritySR, 2, old)
+	own(t, db, tradeBob, "card-ssr", domain.RaritySSR, 1, old)
+
+	offer, err := svc.Propose(ctx, tradeAlice, TradeInput{
+		RecipientID:     tradeBob,
+		OfferedCards:    []TradeCard{{CardID: "card-sr", Count: 1}, {CardID: "card-sr", Count: 1}},
+		OfferedCurrency: 200,
+		RequestedCards:  []TradeCard{{CardID: "card-ssr", Count: 1}},
+	})
+	if err != nil {
+		t.Fatalf("Propose: %v", err)
+	}
+	if got := offer.ItemsOn(domain.TradeSideOffered); len(got) != 1 || got[0].Count != 2 {
+		t.Fatalf("offered items = %+v; want one merged line of 2", got)
+	}
+	// Escrow: cards and currency left Alice before Bob said anything.
+	if c := countOf(t, db, tradeAlice, "card-sr"); c != 0 {
+		t.Errorf("alice card-sr in escrow = %d; want 0", c)
+	}
+	if b := balanceOf(t, db, tradeAlice); b != 300 {
+		t.Errorf("alice balance after escrow = %d; want 300", b)
+	}
+
+	if _, err := svc.Accept(ctx, tradeAlice, offer.ID); err == nil {
+		t.Fatal("proposer must not be able to accept their own offer")
+	}
+	done, err := svc.Accept(ctx, tradeBob, offer.ID)
+	if err != nil {
+		t.Fatalf("Accept: %v", err)
+	}
+	if done.Status != domain.TradeAccepted || done.ResolvedAt == nil {
+		t.Fatalf("accepted offer = %+v", done)
+	}
+	if c := countOf(t, db, tradeBob, "card-sr"); c != 2 {
+		t.Errorf("bob card-sr = %d; want 2", c)
+	}
+	if c := countOf(t, db, tradeBob, "card-ssr"); c != 0 {
+		t.Errorf("bob card-ssr = %d; want 0", c)
+	}
+	if c := countOf(t, db, tradeAlice, "card-ssr"); c != 1 {
+		t.Errorf("alice card-ssr = %d; want 1", c)
+	}
+	if b := balanceOf(t, db, tradeBob); b != 700 {
+		t.Errorf("bob balance = %d; want 700", b)
+	}
+	var reasons []string
+	db.Raw(`SELECT reason FROM gacha_ledger WHERE ref = ? ORDER BY reason`, offer.ID).Scan(&reasons)
+	if len(reasons) != 2 || reasons[0] != domain.ReasonTradeEscrow || reasons[1] != domain.ReasonTradeIn {
+		t.Errorf("ledger reasons = %v; want [trade_escrow trade_in]", reasons)
+	}
+
+	// A second accept loses the CAS.
+	if _, err := svc.Accept(ctx, tradeBob, offer.ID); err != repo.ErrTradeNotPending {
+		t.Errorf("double accept err = %v; want ErrTradeNotPending", err)
+	}
+	// Alice's fresh SSR (received just now) is locked for the cooldown.
+	if _, err := svc.Propose(ctx, tradeAlice, TradeInput{
+		RecipientID:    tradeBob,
+		OfferedCards:   []TradeCard{{CardID: "card-ssr", Count: 1}},
+		RequestedCards: []TradeCard{{CardID: "card-sr", Count: 1}},
+	}); err == nil {
+		t.Error("trading a freshly obtained SSR should be rejected")
+	}
+
+	history, err := svc.History(ctx, tradeBob, "", 0)
+	if err != nil || len(history) != 1 || history[0].ID != offer.ID {
+		t.Errorf("bob history = %+v, %v", history, err)
+	}
+	if _, err := svc.Get(ctx, "cccccccc-0000-0000-0000-000000000003", offer.ID); err == nil {
+		t.Error("a non-party must not see the offer")
+	}
+}
+
+func TestTrade_CounterAndCancelRefundEscrow(t *testing.T) {
+	svc, db, now := newTradeFixture(t)
+	ctx := context.Background()
+	old := now.Add(-30 * 24 * time.Hour)
+	own(t, db, tradeAlice, "card-n", domain.RarityN, 3, old)
+	own(t, db, tradeBob, "card-r", domain.RarityR, 1, old)
+
+	offer, err := svc.Propose(ctx, tradeAlice, TradeInput{
+		RecipientID:     tradeBob,
+		OfferedCards:    []TradeCard{{CardID: "card-n", Count: 1}},
+		OfferedCurrency: 50,
+		RequestedCards:  []TradeCard{{CardID: "card-r", Count: 1}},
+	})
+	if err != nil {
+		t.Fatalf("Propose: %v", err)
+	}
+
+	// Bob wants more: he counters, which refunds Alice and escrows Bob's side.
+	counter, err := svc.Counter(ctx, tradeBob, offer.ID, TradeInput{
+		OfferedCards:   []TradeCard{{CardID: "card-r", Count: 1}},
+		RequestedCards: []TradeCard{{CardID: "card-n", Count: 3}},
+	})
+	if err != nil {
+		t.Fatalf("Counter: %v", err)
+	}
+	if counter.ProposerID != tradeBob || counter.RecipientID != tradeAlice || counter.CounterOfID == nil || *counter.CounterOfID != offer.ID {
+		t.Fatalf("counter = %+v", counter)
+	}
+	if orig, _ := svc.Get(ctx, tradeAlice, offer.ID); orig.Status != domain.TradeCountered {
+		t.Errorf("original status = %s; want countered", orig.Status)
+	}
+	if c, b := countOf(t, db, tradeAlice, "card-n"), balanceOf(t, db, tradeAlice); c != 3 || b != 500 {
+		t.Errorf("alice after counter = %d cards / %d balance; want 3 / 500", c, b)
+	}
+	if c := countOf(t, db, tradeBob, "card-r"); c != 0 {
+		t.Errorf("bob card-r in escrow = %d; want 0", c)
+	}
+
+	// Alice declines; Bob's card comes back.
+	declined, err := svc.Cancel(ctx, tradeAlice, counter.ID)
+	if err != nil || declined.Status != domain.TradeDeclined {
+		t.Fatalf("Cancel = %+v, %v", declined, err)
+	}
+	if c := countOf(t, db, tradeBob, "card-r"); c != 1 {
+		t.Errorf("bob card-r after decline = %d; want 1", c)
+	}
+
+	// Bob has used one action (the counter), Alice one (the offer); two more
+	// offers put Alice at the cap of 3.
+	for i := 0; i < 2; i++ {
+		if _, err := svc.Propose(ctx, tradeAlice, TradeInput{
+			RecipientID:     tradeBob,
+			OfferedCurrency: 10,
+			RequestedCards:  []TradeCard{{CardID: "card-r", Count: 1}},
+		}); err != nil {
+			t.Fatalf("Propose %d: %v", i, err)
+		}
+	}
+	_, err = svc.Propose(ctx, tradeAlice, TradeInput{
+		RecipientID:     tradeBob,
+		OfferedCurrency: 10,
+		RequestedCards:  []TradeCard{{CardID: "card-r", Count: 1}},
+	})
+	if appErr, ok := apperrors.IsAppError(err); !ok || appErr.Code != apperrors.CodeRateLimited {
+		t.Errorf("over-cap propose err = %v; want RATE_LIMITED", err)
+	}
+}
+
+// TestTrade_CancelKeepsSSRCooldownClock — getting an escrowed SSR back is not
+// a new acquisition: last_obtained_at is untouched, so the card can be offered
+// again right away instead of sitting out a fresh cooldown.
+func TestTrade_CancelKeepsSSRCooldownClock(t *testing.T) {
+	svc, db, now := newTradeFixture(t)
+	ctx := context.Background()
+	old := now.Add(-30 * 24 * time.Hour)
+	own(t, db, tradeAlice, "card-ssr", domain.RaritySSR, 1, old)
+	own(t, db, tradeBob, "card-r", domain.RarityR, 1, old)
+	input := TradeInput{
+		RecipientID:    tradeBob,
+		OfferedCards:   []TradeCard{{CardID: "card-ssr", Count: 1}},
+		RequestedCards: []TradeCard{{CardID: "card-r", Count: 1}},
+	}
+
+	offer, err := svc.Propose(ctx, tradeAlice, input)
+	if err != nil {
+		t.Fatalf("Propose: %v", err)
+	}
+	if _, err := svc.Cancel(ctx, tradeAlice, offer.ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if c := countOf(t, db, tradeAlice, "card-ssr"); c != 1 {
+		t.Errorf("alice card-ssr after cancel = %d; want 1", c)
+	}
+	var last time.Time
+	db.Raw(`SELECT last_obtained_at FROM gacha_collection WHERE user_id = ? AND card_id = ?`, tradeAlice, "card-ssr").Scan(&last)
+	if !last.Equal(old) {
+		t.Errorf("last_obtained_at after cancel = %v; want %v", last, old)
+	}
+	if _, err := svc.Propose(ctx, tradeAlice, input); err != nil {
+		t.Errorf("re-offering the refunded SSR: %v", err)
+	}
+}
+
+func TestTrade_RejectsBadOffers(t *testing.T) {
+	svc, db, now := newTradeFixture(t)
+	ctx := context.Background()
+	own(t, db, tradeAlice, "card-n", domain.RarityN, 1, now.Add(-30*24*time.Hour))
+
+	cases := map[string]TradeInput{
+		"self trade":       {RecipientID: tradeAlice, OfferedCurrency: 10, RequestedCards: []TradeCard{{CardID: "card-n", Count: 1}}},
+		"nothing offered":  {RecipientID: tradeBob, RequestedCards: []TradeCard{{CardID: "card-n", Count: 1}}},
+		"nothing wanted":   {RecipientID: tradeBob, OfferedCurrency: 10},
+		"zero count":       {RecipientID: tradeBob, OfferedCards: []TradeCard{{CardID: "card-n"}}, RequestedCards: []TradeCard{{CardID: "x", Count: 1}}},
+		"not enough cards": {RecipientID: tradeBob, OfferedCards: []TradeCard{{CardID: "card-n", Count: 2}}, RequestedCards: []TradeCard{{CardID: "x", Count: 1}}},
+		"too poor":         {RecipientID: tradeBob, OfferedCurrency: 10_000, RequestedCards: []TradeCard{{CardID: "x", Count: 1}}},
+		"unknown player":   {RecipientID: "dddddddd-0000-0000-0000-000000000004", OfferedCurrency: 10, RequestedCards: []TradeCard{{CardID: "x", Count: 1}}},
+	}
+	for name, in := range cases {
+		if _, err := svc.Propose(ctx, tradeAlice, in); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	// Failed proposals leave no escrow behind.
+	if c, b := countOf(t, db, tradeAlice, "card-n"), balanceOf(t, db, tradeAlice); c != 1 || b != 500 {
+		t.Errorf("alice after rejected offers = %d cards / %d balance; want 1 / 500", c, b)
+	}
+
+	disabled := NewTradeService(repo.NewTradeRepository(db), config.EconomyConfig{TradeDailyCap: 1}, false, logger.Default())
+	if _, err := disabled.Propose(ctx, tradeAlice, cases["nothing wanted"]); err == nil {
+		t.Error("disabled service must reject offers")
+	}
+}
@@ -17,6 +17,7 @@ type WalletService struct {
 	dailyBase       int64
 	dailyStreakStep int64
 	dailyStreakCap  int64
+	achievements    *AchievementService
 	enabled         bool
 	log             *logger.Logger
 }
@@ -39,6 +40,10 @@ func NewWalletService(
 	}
 }
 
+// SetAchievements makes every successful daily claim record a newly reached
+// streak achievement right away — the streak itself resets on a missed day.
+func (s *WalletService) SetAchievements(a *AchievementService) { s.achievements = a }
+
 // DailyResult is returned by Daily.
 type DailyResult struct {
 	Claimed bool
@@ -113,6 +118,9 @@ func (s *WalletService) Daily(ctx context.Context, userID string, now time.Time)
 		"amount", amount,
 		"streak", newStreak,
 	)
+	if s.achievements != nil {
+		s.achievements.Sync(ctx, userID)
+	}
 	return DailyResult{
 		Claimed: true,
 		Amount:  amount,
@@ -23,6 +23,7 @@ func newSvc(t *testing.T) *WalletService {
 		`CREATE TABLE gacha_wallets (
 			user_id TEXT PRIMARY KEY,
 			balance INTEGER NOT NULL DEFAULT 0,
+			shards INTEGER NOT NULL DEFAULT 0,
 			starter_granted INTEGER NOT NULL DEFAULT 0,
 			daily_streak INTEGER NOT NULL DEFAULT 0,
 			last_daily_at DATETIME,
@@ -35,6 +36,7 @@ func newSvc(t *testing.T) *WalletService {
 			delta INTEGER NOT NULL,
 			reason TEXT NOT NULL,
 			ref TEXT NOT NULL DEFAULT '',
+			currency TEXT NOT NULL DEFAULT 'enigma',
 			created_at DATETIME
 		)`,
 		`CREATE UNIQUE INDEX idx_ledger_dedup ON gacha_ledger(user_id, reason, ref) WHERE ref <> ''`,
@@ -119,6 +121,7 @@ func newSvcNoStarter(t *testing.T) *WalletService {
 		`CREATE TABLE gacha_wallets (
 			user_id TEXT PRIMARY KEY,
 			balance INTEGER NOT NULL DEFAULT 0,
+			shards INTEGER NOT NULL DEFAULT 0,
 			starter_granted INTEGER NOT NULL DEFAULT 0,
 			daily_streak INTEGER NOT NULL DEFAULT 0,
 			last_daily_at DATETIME,
@@ -131,6 +134,7 @@ func newSvcNoStarter(t *testing.T) *WalletService {
 			delta INTEGER NOT NULL,
 			reason TEXT NOT NULL,
 			ref TEXT NOT NULL DEFAULT '',
+			currency TEXT NOT NULL DEFAULT 'enigma',
 			created_at DATETIME
 		)`,
 		`CREATE UNIQUE INDEX idx_ledger_dedup ON gacha_ledger(user_id, reason, ref) WHERE ref <> ''`,
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/database"
 	"github.com/ILITA-hub/animeenigma/libs/httputil"
 	"github.com/ILITA-hub/animeenigma/libs/logger"
 	"github.com/ILITA-hub/animeenigma/libs/metrics"
@@ -30,18 +31,35 @@ func RequireAdmin(next http.Handler) http.Handler {
 //	GET  /metrics                      (public, prom)
 //	POST /internal/gacha/credit        (internal — gateway never proxies)
 //	GET  /internal/health              (internal)
+//	POST /internal/account/export      (internal — auth account fan-out)
+//	POST /internal/account/erase       (internal — auth account fan-out)
 //	GET  /api/gacha/images/*           (public — browser <img> tags, no JWT)
+//	GET  /api/gacha/banners/{id}/odds  (public)
+//	GET  /api/gacha/fairness/seeds     (public)
+//	GET  /api/gacha/fairness/pulls     (JWT)
 //	GET  /api/gacha/wallet             (JWT)
 //	GET  /api/gacha/banners            (JWT)
 //	POST /api/gacha/banners/{id}/pull  (JWT)
 //	GET  /api/gacha/collection         (JWT)
+//	GET  /api/gacha/crafting           (JWT)
+//	POST /api/gacha/dismantle          (JWT)
+//	POST /api/gacha/banners/{id}/craft (JWT)
+//	     /api/gacha/trades/*           (JWT)
+//	GET  /api/gacha/achievements       (JWT)
+//	POST /api/gacha/achievements/{id}/claim (JWT)
+//	GET  /api/gacha/users/{id}/achievements (JWT)
 //	     /api/gacha/admin/*            (JWT + AdminRole)
 func NewRouter(
 	walletHandler *handler.WalletHandler,
 	internalHandler *handler.InternalHandler,
+	accountHandler *database.UserDataHandler,
 	adminHandler *handler.AdminHandler,
 	imagesHandler *handler.ImagesHandler,
 	pullHandler *handler.PullHandler,
+	tradeHandler *handler.TradeHandler,
+	craftHandler *handler.CraftHandler,
+	fairnessHandler *handler.FairnessHandler,
+	achievementHandler *handler.AchievementHandler,
 	jwtConfig authz.JWTConfig,
 	log *logger.Logger,
 	metricsCollector *metrics.Collector,
@@ -72,6 +90,12 @@ func NewRouter(
 	// Internal — no middleware, Docker-network-only.
 	r.Post("/internal/gacha/credit", internalHandler.Credit)
 	r.Get("/internal/health", internalHandler.Health)
+	// Account export + erasure — the auth service's fan-out target when a
+	// user downloads their data or deletes their account.
+	if accountHandler != nil {
+		r.Post("/internal/account/export", accountHandler.Export)
+		r.Post("/internal/account/erase", accountHandler.Erase)
+	}
 
 	// All /api/gacha/* routes in a single Route block to avoid chi subtree
 	// conflicts. The public images endpoint and the auth-gated wallet/admin
@@ -85,6 +109,11 @@ func NewRouter(
 		// match /api/gacha/images/* here (not a bare /images/* route).
 		r.Get("/images/*", imagesHandler.Serve)
 
+		// Public fairness audit: banner odds and the seed commitments are
+		// meant to be checked by anyone, logged in or not.
+		r.Get("/banners/{id}/odds", pullHandler.Odds)
+		r.Get("/fairness/seeds", fairnessHandler.Seeds)
+
 		// JWT-gated user routes.
 		r.Group(func(r chi.Router) {
 			r.Use(AuthMiddleware(jwtConfig))
@@ -96,6 +125,27 @@ func NewRouter(
 			r.Get("/banners", pullHandler.Banners)
 			r.Post("/banners/{id}/pull", pullHandler.Pull)
 			r.Get("/collection", pullHandler.Collection)
+			r.Get("/fairness/pulls", fairnessHandler.Pulls)
+
+			// Shard economy — dismantle duplicates, craft specific cards.
+			r.Get("/crafting", craftHandler.Rates)
+			r.Post("/dismantle", craftHandler.Dismantle)
+			r.Post("/banners/{id}/craft", craftHandler.Craft)
+
+			// Card trading — offers with escrow, counter/accept/cancel and
+			// the caller's trade history.
+			r.Post("/trades", tradeHandler.Propose)
+			r.Get("/trades", tradeHandler.List)
+			r.Get("/trades/{id}", tradeHandler.Get)
+			r.Post("/trades/{id}/counter", tradeHandler.Counter)
+			r.Post("/trades/{id}/accept", tradeHandler.Accept)
+			r.Post("/trades/{id}/cancel", tradeHandler.Cancel)
+
+			// Achievements — progress, idempotent reward claims and the
+			// profile showcase of another player.
+			r.Get("/achievements", achievementHandler.List)
+			r.Post("/achievements/{id}/claim", achievementHandler.Claim)
+			r.Get("/users/{id}/achievements", achievementHandler.Showcase)
 		})
 
 		// Admin content API. The gateway already requires JWT+AdminRole for
@@ -131,8 +181,14 @@ func NewRouter(
 			r.Delete("/banners/{id}", adminHandler.DeleteBanner)
 			r.Put("/banners/{id}/cards", adminHandler.SetBannerCards)
 			r.Post("/banners/{id}/cards", adminHandler.AddBannerCards)
+			r.Put("/banners/{id}/featured", adminHandler.SetBannerFeatured)
 			r.Post("/banners/{id}/groups/{groupId}", adminHandler.AddGroupCardsToBanner)
 
+			// Achievement reward overrides
+			r.Get("/achievements/rewards", achievementHandler.ListRewards)
+			r.Put("/achievements/{id}/reward", achievementHandler.SetReward)
+			r.Delete("/achievements/{id}/reward", achievementHandler.DeleteReward)
+
 			// Upload (file or URL → MinIO)
 			r.Post("/upload", adminHandler.Upload)
 		})
@@ -64,6 +64,7 @@ func getTestRouter(t *testing.T) http.Handler {
 			`CREATE TABLE gacha_wallets (
 				user_id TEXT PRIMARY KEY,
 				balance INTEGER NOT NULL DEFAULT 0,
+				shards INTEGER NOT NULL DEFAULT 0,
 				starter_granted INTEGER NOT NULL DEFAULT 0,
 				daily_streak INTEGER NOT NULL DEFAULT 0,
 				last_daily_at DATETIME,
@@ -76,6 +77,7 @@ func getTestRouter(t *testing.T) http.Handler {
 				delta INTEGER NOT NULL,
 				reason TEXT NOT NULL,
 				ref TEXT NOT NULL DEFAULT '',
+				currency TEXT NOT NULL DEFAULT 'enigma',
 				created_at DATETIME
 			)`,
 			`CREATE UNIQUE INDEX idx_ledger_dedup ON gacha_ledger(user_id, reason, ref) WHERE ref <> ''`,
@@ -106,8 +108,14 @@ func getTestRouter(t *testing.T) http.Handler {
 			WeightN: 69, WeightR: 22, WeightSR: 8, WeightSSR: 1,
 		}, service.NewSecureRand(), true, log)
 		pullH := handler.NewPullHandler(pullSvc, log)
-
-		testRouter = NewRouter(walletH, internalH, adminH, imagesH, pullH, jwtCfg, log, mc)
+		tradeSvc := service.NewTradeService(repo.NewTradeRepository(contentDB), config.EconomyConfig{TradeDailyCap: 10}, true, log)
+		tradeH := handler.NewTradeHandler(tradeSvc, log)
+		craftSvc := service.NewCraftService(repo.NewCraftRepository(contentDB), pullRepo, bannerRepo, contentRepo, config.EconomyConfig{}, true, log)
+		craftH := handler.NewCraftHandler(craftSvc, log)
+		achH := handler.NewAchievementHandler(service.NewAchievementService(repo.NewAchievementRepository(contentDB), pullRepo, contentRepo, config.EconomyConfig{}, true, log), log)
+		fairH := handler.NewFairnessHandler(service.NewFairnessService(repo.NewFairnessRepository(contentDB), 24*time.Hour, log), log)
+
+		testRouter = NewRouter(walletH, internalH, nil, adminH, imagesH, pullH, tradeH, craftH, fairH, achH, jwtCfg, log, mc)
 	})
 	return testRouter
 }
@@ -174,6 +182,29 @@ func TestRouter_Collection_RequiresAuth(t *testing.T) {
 	}
 }
 
+// TestRouter_TradeAndCraft_RequiresAuth asserts the trade and craft routes exist and are
+// auth-gated (401 without token — NOT 404/405).
+func TestRouter_TradeAndCraft_RequiresAuth(t *testing.T) {
+	r := getTestRouter(t)
+	for _, tc := range []struct{ method, path string }{
+		{http.MethodGet, "/api/gacha/crafting"},
+		{http.MethodPost, "/api/gacha/dismantle"},
+		{http.MethodPost, "/api/gacha/banners/aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa/craft"},
+		{http.MethodGet, "/api/gacha/trades"},
+		{http.MethodPost, "/api/gacha/trades"},
+		{http.MethodPost, "/api/gacha/trades/aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa/accept"},
+		{http.MethodPost, "/api/gacha/trades/aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa/counter"},
+		{http.MethodPost, "/api/gacha/trades/aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa/cancel"},
+	} {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s: expected 401 without token, got %d", tc.method, tc.path, rr.Code)
+		}
+	}
+}
+
 // TestRouter_Admin_RequiresAuth asserts that GET /api/gacha/admin/cards without
 // a JWT returns 401.
 func TestRouter_Admin_RequiresAuth(t *testing.T) {
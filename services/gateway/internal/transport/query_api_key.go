@@ -0,0 +1,32 @@
+package transport
+
+import (
+	"net/http"
+	"strings"
+)
+
+// QueryAPIKeyMiddleware lets webhook senders that cannot set request headers
+// (Plex, most Jellyfin webhook setups) authenticate with a personal API key
+// in the URL: ?api_key=ak_… is lifted into "Authorization: Bearer ak_…" for
+// JWTValidationMiddleware to resolve, and removed from the query so it is
+// never forwarded downstream.
+//
+// Only ak_* keys are accepted this way — a JWT in a URL would leak into proxy
+// and browser logs with its full authority, while an API key is revocable.
+// An explicit Authorization header always wins.
+func QueryAPIKeyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		key := q.Get("api_key")
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		q.Del("api_key")
+		r.URL.RawQuery = q.Encode()
+		if strings.HasPrefix(key, "ak_") && r.Header.Get("Authorization") == "" {
+			r.Header.Set("Authorization", "Bearer "+key)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
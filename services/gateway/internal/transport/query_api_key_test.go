@@ -0,0 +1,68 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ILITA-hub/animeenigma/libs/authz"
+)
+
+// TestQueryAPIKeyMiddleware_LiftsAPIKeyAndStripsQuery — a Plex-style webhook
+// URL ?api_key=ak_… authenticates through JWTValidationMiddleware, and the
+// key never reaches the downstream service in the query string.
+func TestQueryAPIKeyMiddleware_LiftsAPIKeyAndStripsQuery(t *testing.T) {
+	rawAPIKey := "ak_test_querylift_0123456789abcdef"
+	fas := newFakeAuthService(t, "user-scrobble-1", "scrobbler", authz.RoleUser)
+	defer fas.Close()
+
+	var gotQuery, gotUser string
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		if c, ok := authz.ClaimsFromContext(r.Context()); ok {
+			gotUser = c.UserID
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	h := QueryAPIKeyMiddleware(JWTValidationMiddleware(gatewayTestJWTConfig(), fas.URL())(inner))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/users/scrobble/plex?api_key="+rawAPIKey+"&account=alice", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; want 200 (body=%q)", rec.Code, rec.Body.String())
+	}
+	if gotUser != "user-scrobble-1" {
+		t.Errorf("claims.UserID = %q; want user-scrobble-1", gotUser)
+	}
+	if gotQuery != "account=alice" {
+		t.Errorf("downstream query = %q; want api_key stripped", gotQuery)
+	}
+	if len(fas.receivedTokens) != 1 || fas.receivedTokens[0] != rawAPIKey {
+		t.Errorf("auth service receivedTokens = %v; want [%q]", fas.receivedTokens, rawAPIKey)
+	}
+}
+
+// TestQueryAPIKeyMiddleware_RejectsJWTInQuery — only ak_* keys are lifted; a
+// JWT passed as ?api_key= is dropped and the request stays unauthenticated.
+func TestQueryAPIKeyMiddleware_RejectsJWTInQuery(t *testing.T) {
+	mgr := authz.NewJWTManager(gatewayTestJWTConfig())
+	pair, err := mgr.GenerateTokenPair("user-1", "someone", authz.RoleUser, "")
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+
+	inner := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := QueryAPIKeyMiddleware(JWTValidationMiddleware(gatewayTestJWTConfig(), "http://auth.invalid")(inner))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/users/scrobble?api_key="+pair.AccessToken, nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d; want 401", rec.Code)
+	}
+}
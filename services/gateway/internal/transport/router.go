@@ -571,10 +571,16 @@ func NewRouterWithCleanup(
 		// Player service routes - public watchlist
 		r.Get("/users/{userId}/watchlist/public", proxyHandler.ProxyToPlayer)
 		r.Get("/users/{userId}/watchlist/public/stats", proxyHandler.ProxyToPlayer)
+		r.Get("/users/{userId}/stats/public", proxyHandler.ProxyToPlayer)
 
 		// Public activity feed
 		r.Get("/activity/feed", proxyHandler.ProxyToPlayer)
 
+		// Shared yearly recap (public link — the token is the capability).
+		// The owner-side /users/recap/* routes ride the protected /users/*
+		// group below.
+		r.Get("/recaps/{token}", proxyHandler.ProxyToPlayer)
+
 		// Player service routes - preferences (public, OptionalAuth on player side)
 		// Per CONTEXT Critical Finding 1: must NOT be inside the JWT-protected /users/* group,
 		// because anonymous users (no Authorization header) need to POST overrides + resolve.
@@ -689,6 +695,18 @@ func NewRouterWithCleanup(
 			r.Get("/users/{userId}/compatibility", proxyHandler.ProxyToPlayer)
 		})
 
+		// Scrobbling (player) — same chain as the protected /users/* group,
+		// plus ?api_key=ak_… for Plex / Jellyfin webhooks that can't send an
+		// Authorization header. More specific than /users/*, so it wins.
+		r.Group(func(r chi.Router) {
+			r.Use(QueryAPIKeyMiddleware)
+			r.Use(JWTValidationMiddleware(cfg.JWT, cfg.Services.AuthService))
+			r.Use(userRateLimit)
+			r.Use(BlockGuestRoleMiddleware)
+			r.Post("/users/scrobble", proxyHandler.ProxyToPlayer)
+			r.Post("/users/scrobble/{source}", proxyHandler.ProxyToPlayer)
+		})
+
 		// Player service routes (protected)
 		r.Group(func(r chi.Router) {
 			r.Use(JWTValidationMiddleware(cfg.JWT, cfg.Services.AuthService))
@@ -719,8 +737,19 @@ func NewRouterWithCleanup(
 			// Video/audio proxy (public)
 			r.Get("/video/{basename}", proxyHandler.ProxyToThemes)
 			r.Get("/audio/{basename}", proxyHandler.ProxyToThemes)
-
-			// Protected routes (rate themes)
+			// Shared playlists and radio (optional auth)
+			r.Get("/playlists/{playlistID}", proxyHandler.ProxyToThemes)
+			r.Get("/playlists/{playlistID}/m3u", proxyHandler.ProxyToThemes)
+			r.Get("/radio", proxyHandler.ProxyToThemes)
+			r.Get("/radio/m3u", proxyHandler.ProxyToThemes)
+			// Artist index and pages (optional auth)
+			r.Get("/artists", proxyHandler.ProxyToThemes)
+			r.Get("/artists/{slug}", proxyHandler.ProxyToThemes)
+			// Tournaments and their archive (optional auth)
+			r.Get("/tournaments", proxyHandler.ProxyToThemes)
+			r.Get("/tournaments/{tournamentID}", proxyHandler.ProxyToThemes)
+
+			// Protected routes (rate themes, manage playlists)
 			r.Group(func(r chi.Router) {
 				r.Use(JWTValidationMiddleware(cfg.JWT, cfg.Services.AuthService))
 				r.Use(userRateLimit)
@@ -728,6 +757,15 @@ func NewRouterWithCleanup(
 				r.Post("/{id}/rate", proxyHandler.ProxyToThemes)
 				r.Delete("/{id}/rate", proxyHandler.ProxyToThemes)
 				r.Get("/my-ratings", proxyHandler.ProxyToThemes)
+				r.Get("/playlists", proxyHandler.ProxyToThemes)
+				r.Post("/playlists", proxyHandler.ProxyToThemes)
+				r.Patch("/playlists/{playlistID}", proxyHandler.ProxyToThemes)
+				r.Delete("/playlists/{playlistID}", proxyHandler.ProxyToThemes)
+				r.Post("/playlists/{playlistID}/items", proxyHandler.ProxyToThemes)
+				r.Delete("/playlists/{playlistID}/items/{themeID}", proxyHandler.ProxyToThemes)
+				r.Put("/playlists/{playlistID}/order", proxyHandler.ProxyToThemes)
+				r.Post("/playlists/{playlistID}/clone", proxyHandler.ProxyToThemes)
+				r.Post("/tournaments/{tournamentID}/matches/{matchID}/vote", proxyHandler.ProxyToThemes)
 			})
 
 			// Admin routes (sync)
@@ -737,6 +775,8 @@ func NewRouterWithCleanup(
 				r.Use(AdminRoleMiddleware)
 				r.Post("/admin/sync", proxyHandler.ProxyToThemes)
 				r.Get("/admin/sync/status", proxyHandler.ProxyToThemes)
+				r.Post("/admin/tournaments", proxyHandler.ProxyToThemes)
+				r.Post("/admin/tournaments/{tournamentID}/cancel", proxyHandler.ProxyToThemes)
 			})
 		})
 
@@ -777,6 +817,7 @@ func NewRouterWithCleanup(
 		//              dedicated WS reverse proxy (see ws_proxy.go for
 		//              why we can't reuse ProxyService.Forward).
 		//   - /rooms → JWT-required REST CRUD (standard Bearer header).
+		//   - /parties → same, plus BlockGuestRole (scheduled parties).
 		//
 		// Internal forward-compat route /internal/watch-together/* is NOT
 		// registered (WT-FOUND-08 — Docker-network-only, same D-05 model
@@ -792,6 +833,29 @@ func NewRouterWithCleanup(
 					r.Post("/", proxyHandler.ProxyToWatchTogether)
 					r.Get("/{id}", proxyHandler.ProxyToWatchTogether)
 					r.Delete("/{id}", proxyHandler.ProxyToWatchTogether)
+					// Voice chat ICE servers (STUN + short-lived TURN creds).
+					r.Get("/{id}/ice-servers", proxyHandler.ProxyToWatchTogether)
+				})
+				// Durable chat history of rooms the caller hosted.
+				r.Route("/history", func(r chi.Router) {
+					r.Get("/", proxyHandler.ProxyToWatchTogether)
+					r.Get("/{id}", proxyHandler.ProxyToWatchTogether)
+					r.Get("/{id}/export", proxyHandler.ProxyToWatchTogether)
+				})
+				// Scheduled watch parties — registered accounts only; a
+				// guest identity can join a live room but never schedule,
+				// invite or RSVP.
+				r.Group(func(r chi.Router) {
+					r.Use(BlockGuestRoleMiddleware)
+					r.Route("/parties", func(r chi.Router) {
+						r.Get("/", proxyHandler.ProxyToWatchTogether)
+						r.Post("/", proxyHandler.ProxyToWatchTogether)
+						r.Get("/{id}", proxyHandler.ProxyToWatchTogether)
+						r.Delete("/{id}", proxyHandler.ProxyToWatchTogether)
+						r.Post("/{id}/invites", proxyHandler.ProxyToWatchTogether)
+						r.Post("/{id}/rsvp", proxyHandler.ProxyToWatchTogether)
+						r.Get("/{id}/archive", proxyHandler.ProxyToWatchTogether)
+					})
 				})
 			})
 		})
@@ -812,6 +876,13 @@ func NewRouterWithCleanup(
 			// gacha service validates the key shape and serves with nosniff.
 			r.Get("/images/*", proxyHandler.ProxyToGacha)
 
+			// Provably fair audit — public so anyone can check the published
+			// banner odds and the seed commitments (seeds are only revealed
+			// by the gacha service once their period has ended). Covered by
+			// the global per-IP limiter only.
+			r.Get("/banners/{id}/odds", proxyHandler.ProxyToGacha)
+			r.Get("/fairness/seeds", proxyHandler.ProxyToGacha)
+
 			// Admin content API (Phase 2) — ALWAYS admin-gated, independent
 			// of the "gacha" feature flag's audience: these are admin tools,
 			// full stop. The gacha service re-validates JWT+admin downstream.
@@ -834,9 +905,32 @@ func NewRouterWithCleanup(
 				r.Get("/banners", proxyHandler.ProxyToGacha)
 				r.Post("/banners/{id}/pull", proxyHandler.ProxyToGacha)
 				r.Get("/collection", proxyHandler.ProxyToGacha)
+				// The caller's verifiable pull history (seed hash, nonce, draws).
+				r.Get("/fairness/pulls", proxyHandler.ProxyToGacha)
 
 				// Daily streak claim (Phase 4).
 				r.Post("/daily", proxyHandler.ProxyToGacha)
+
+				// Shard economy: dismantle duplicates, craft specific cards
+				// from a banner's pool.
+				r.Get("/crafting", proxyHandler.ProxyToGacha)
+				r.Post("/dismantle", proxyHandler.ProxyToGacha)
+				r.Post("/banners/{id}/craft", proxyHandler.ProxyToGacha)
+
+				// Card trading: offers with escrow, counter/accept/cancel
+				// and the caller's trade history.
+				r.Post("/trades", proxyHandler.ProxyToGacha)
+				r.Get("/trades", proxyHandler.ProxyToGacha)
+				r.Get("/trades/{id}", proxyHandler.ProxyToGacha)
+				r.Post("/trades/{id}/counter", proxyHandler.ProxyToGacha)
+				r.Post("/trades/{id}/accept", proxyHandler.ProxyToGacha)
+				r.Post("/trades/{id}/cancel", proxyHandler.ProxyToGacha)
+
+				// Achievements: own progress, idempotent reward claims and
+				// another player's profile showcase.
+				r.Get("/achievements", proxyHandler.ProxyToGacha)
+				r.Post("/achievements/{id}/claim", proxyHandler.ProxyToGacha)
+				r.Get("/users/{id}/achievements", proxyHandler.ProxyToGacha)
 			})
 		})
 
@@ -867,6 +961,36 @@ func NewRouterWithCleanup(
 				r.Post("/{id}/continue", proxyHandler.ProxyToFanficStream)
 				r.Get("/", proxyHandler.ProxyToFanfic)
 				r.Get("/tags", proxyHandler.ProxyToFanfic)
+				r.Get("/models", proxyHandler.ProxyToFanfic)
+				// Human-authored works, reader kudos/bookmarks/subscriptions
+				// and the public works search.
+				r.Get("/bookmarks", proxyHandler.ProxyToFanfic)
+				r.Get("/works", proxyHandler.ProxyToFanfic)
+				r.Post("/works", proxyHandler.ProxyToFanfic)
+				r.Get("/works/{id}", proxyHandler.ProxyToFanfic)
+				r.Put("/works/{id}", proxyHandler.ProxyToFanfic)
+				r.Post("/works/{id}/chapters", proxyHandler.ProxyToFanfic)
+				r.Put("/works/{id}/chapters/{chapterID}", proxyHandler.ProxyToFanfic)
+				r.Delete("/works/{id}/chapters/{chapterID}", proxyHandler.ProxyToFanfic)
+				r.Post("/works/{id}/chapters/{chapterID}/publish", proxyHandler.ProxyToFanfic)
+				r.Delete("/works/{id}/chapters/{chapterID}/publish", proxyHandler.ProxyToFanfic)
+				r.Post("/works/{id}/kudos", proxyHandler.ProxyToFanfic)
+				r.Put("/works/{id}/bookmark", proxyHandler.ProxyToFanfic)
+				r.Delete("/works/{id}/bookmark", proxyHandler.ProxyToFanfic)
+				r.Put("/works/{id}/subscription", proxyHandler.ProxyToFanfic)
+				r.Delete("/works/{id}/subscription", proxyHandler.ProxyToFanfic)
+				// EPUB/PDF exports, rendered in the background.
+				r.Post("/bookmarks/export", proxyHandler.ProxyToFanfic)
+				r.Post("/{id}/export", proxyHandler.ProxyToFanfic)
+				r.Get("/exports/{id}", proxyHandler.ProxyToFanfic)
+				r.Get("/exports/{id}/download", proxyHandler.ProxyToFanfic)
+				// VOICEVOX narrations of Japanese fanfics, synthesized in the
+				// background (the fanfic service sheds them under load itself).
+				r.Get("/narration/speakers", proxyHandler.ProxyToFanfic)
+				r.Post("/{id}/narration", proxyHandler.ProxyToFanfic)
+				r.Get("/narrations/{id}", proxyHandler.ProxyToFanfic)
+				r.Post("/narrations/{id}/resume", proxyHandler.ProxyToFanfic)
+				r.Get("/narrations/{id}/download", proxyHandler.ProxyToFanfic)
 				r.Get("/{id}", proxyHandler.ProxyToFanfic)
 				r.Delete("/{id}", proxyHandler.ProxyToFanfic)
 			})
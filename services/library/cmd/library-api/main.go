@@ -632,6 +632,7 @@ func main() {
 		autocacheConfigHandler,
 		autocacheInternalHandler,
 		filesHandler,
+		handler.NewFilenameInternalHandler(detector),
 		cfg.JWT,
 		log,
 		metricsCollector,
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	liberrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/services/library/internal/parser/filename"
+)
+
+// releaseParser is the filename.Detector surface the parse endpoint uses.
+type releaseParser interface {
+	ParseRelease(name string) (filename.Release, bool)
+}
+
+// FilenameInternalHandler exposes the release-filename parser to other
+// services over the Docker network:
+//
+//	POST /internal/library/parse-filename {"filename"} → {"uploader","title","episode"}
+//
+// The player's scrobble endpoint calls it to identify files played in mpv /
+// Jellyfin / Plex. Same /internal/* non-proxied rule as the autocache signals.
+type FilenameInternalHandler struct {
+	parser releaseParser
+}
+
+func NewFilenameInternalHandler(parser releaseParser) *FilenameInternalHandler {
+	return &FilenameInternalHandler{parser: parser}
+}
+
+type parseFilenameRequest struct {
+	Filename string `json:"filename"`
+}
+
+// Parse handles POST /internal/library/parse-filename. 422 when no episode
+// number or title can be extracted.
+func (h *FilenameInternalHandler) Parse(w http.ResponseWriter, r *http.Request) {
+	var req parseFilenameRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	req.Filename = strings.TrimSpace(req.Filename)
+	if req.Filename == "" {
+		httputil.BadRequest(w, "filename is required")
+		return
+	}
+	rel, ok := h.parser.ParseRelease(req.Filename)
+	if !ok {
+		httputil.Error(w, liberrors.New(liberrors.CodeUnprocessable, "could not detect title and episode in filename"))
+		return
+	}
+	httputil.OK(w, rel)
+}
@@ -0,0 +1,77 @@
+package filename
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Release is what ParseRelease pulls out of a release filename: the
+// uploader tag, a best-effort series title and the episode number.
+type Release struct {
+	Uploader string `json:"uploader,omitempty"`
+	Title    string `json:"title"`
+	Episode  int    `json:"episode"`
+}
+
+var (
+	// leadingGroupRegex captures the "[Uploader] " prefix.
+	leadingGroupRegex = regexp.MustCompile(`^\[([^\]]+)\]\s*`)
+	// titleEpisodeRegex splits "Title - 05 …" at the episode marker.
+	titleEpisodeRegex = regexp.MustCompile(`^(.+?)\s+-\s+\d{1,4}(?:v\d)?(?:\s|$)`)
+	// bareEpisodeRegex matches a trailing " - 05" / " - 05v2" with no
+	// quality tag after it ("Frieren - 05.mkv" once the extension is gone).
+	bareEpisodeRegex = regexp.MustCompile(`\s-\s(\d{1,4})(?:v\d)?\s*$`)
+	// seasonEpisodeRegex matches "Title S01E05" scene-style names.
+	seasonEpisodeRegex = regexp.MustCompile(`(?i)^(.+?)[\s._]+S\d{1,2}E(\d{1,4})\b`)
+	// trailingTagsRegex strips trailing "[1080p]" / "(BD)" tag groups.
+	trailingTagsRegex = regexp.MustCompile(`(\s*[\[\(][^\]\)]*[\]\)])+\s*$`)
+)
+
+// ParseRelease identifies the series and episode of a release filename
+// (a bare name or a full path; the extension is optional). The episode
+// comes from DetectEpisode — uploader pattern first, generic fallback
+// second — and then from the bare " - 05" / "S01E05" shapes media-player
+// scrobblers send for renamed files. ok is false when no episode number
+// or no title could be found.
+func (d *Detector) ParseRelease(name string) (Release, bool) {
+	base := path.Base(strings.ReplaceAll(name, `\`, "/"))
+	if ext := path.Ext(base); len(ext) >= 2 && len(ext) <= 5 && !strings.ContainsAny(ext, " ]") {
+		base = strings.TrimSuffix(base, ext)
+	}
+	base = strings.TrimSpace(base)
+
+	var rel Release
+	rest := base
+	if m := leadingGroupRegex.FindStringSubmatch(base); m != nil {
+		rel.Uploader = m[1]
+		rest = base[len(m[0]):]
+	}
+
+	if ep, ok := d.DetectEpisode(base, rel.Uploader); ok {
+		rel.Episode = ep
+		if m := titleEpisodeRegex.FindStringSubmatch(rest); m != nil {
+			rel.Title = m[1]
+		}
+	}
+	if rel.Episode == 0 {
+		trimmed := trailingTagsRegex.ReplaceAllString(rest, "")
+		if m := bareEpisodeRegex.FindStringSubmatchIndex(trimmed); m != nil {
+			if ep, ok := parseEpisode(trimmed[m[2]:m[3]]); ok {
+				rel.Episode = ep
+				rel.Title = trimmed[:m[0]]
+			}
+		} else if m := seasonEpisodeRegex.FindStringSubmatch(trimmed); m != nil {
+			if ep, ok := parseEpisode(m[2]); ok {
+				rel.Episode = ep
+				rel.Title = strings.NewReplacer(".", " ", "_", " ").Replace(m[1])
+			}
+		}
+	}
+
+	rel.Title = strings.TrimSpace(trailingTagsRegex.ReplaceAllString(rel.Title, ""))
+	if rel.Episode == 0 || rel.Title == "" {
+		return Release{}, false
+	}
+	return rel, true
+}
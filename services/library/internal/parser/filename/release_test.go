@@ -0,0 +1,46 @@
+package filename
+
+import "testing"
+
+func TestParseRelease(t *testing.T) {
+	d, err := NewDetector(seedPatterns(), nil)
+	if err != nil {
+		t.Fatalf("NewDetector: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		in   string
+		want Release
+	}{
+		{"uploader pattern", "[SubsPlease] Sousou no Frieren - 12 (1080p) [ABCD1234].mkv",
+			Release{Uploader: "SubsPlease", Title: "Sousou no Frieren", Episode: 12}},
+		{"generic fallback", "[SomeGroup] Dandadan - 03 [1080p].mkv",
+			Release{Uploader: "SomeGroup", Title: "Dandadan", Episode: 3}},
+		{"full path", "/mnt/anime/Bocchi/[Ohys-Raws] Bocchi the Rock! - 01 (BS11 1280x720 x264 AAC).mp4",
+			Release{Uploader: "Ohys-Raws", Title: "Bocchi the Rock!", Episode: 1}},
+		{"bare renamed file", "Frieren - 05.mkv",
+			Release{Title: "Frieren", Episode: 5}},
+		{"version suffix", "Spy x Family - 07v2 [1080p].mkv",
+			Release{Title: "Spy x Family", Episode: 7}},
+		{"scene style", "Made.in.Abyss.S01E05.1080p.WEB.mkv",
+			Release{Title: "Made in Abyss", Episode: 5}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := d.ParseRelease(tc.in)
+			if !ok {
+				t.Fatalf("ParseRelease(%q) ok=false", tc.in)
+			}
+			if got != tc.want {
+				t.Fatalf("ParseRelease(%q) = %+v, want %+v", tc.in, got, tc.want)
+			}
+		})
+	}
+
+	for _, in := range []string{"", "Some Movie (2019).mkv", "[Group] - 05 (1080p).mkv"} {
+		if got, ok := d.ParseRelease(in); ok {
+			t.Errorf("ParseRelease(%q) = %+v, want ok=false", in, got)
+		}
+	}
+}
@@ -30,6 +30,7 @@ func NewRouter(
 	autocacheConfigHandler *handler.AutocacheConfigHandler,
 	autocacheInternalHandler *handler.AutocacheInternalHandler,
 	filesHandler *handler.FilesHandler,
+	filenameInternalHandler *handler.FilenameInternalHandler,
 	jwtConfig authz.JWTConfig,
 	log *logger.Logger,
 	metricsCollector *metrics.Collector,
@@ -73,6 +74,12 @@ func NewRouter(
 		r.Get("/internal/library/recent-episodes", episodesHandler.RecentEpisodes)
 	}
 
+	// Docker-network-only: release-filename parser for the player's
+	// scrobble endpoint (mpv / Jellyfin / Plex). Same non-proxied rule.
+	if filenameInternalHandler != nil {
+		r.Post("/internal/library/parse-filename", filenameInternalHandler.Parse)
+	}
+
 	// API routes. Phase 2 adds /search; Phase 3 adds the job-control
 	// group. Gateway-side admin gate covers all /api/library/*
 	// non-/health routes (services/gateway/internal/transport/router.go).
@@ -174,13 +174,14 @@ func main() {
 	// Handlers.
 	notifHandler := handler.NewNotificationHandler(notifService, log)
 	internalHandler := handler.NewInternalHandler(notifService, log)
+	accountHandler := database.NewUserDataHandler(repo.NewAccountRepository(db.DB), log)
 	adminHandler := handler.NewAdminHandler(detectorJob, cleanupJob, log)
 
 	// Metrics collector.
 	metricsCollector := metrics.NewCollector("notifications")
 
 	// Router.
-	router := transport.NewRouter(notifHandler, internalHandler, adminHandler, cfg.JWT, log, metricsCollector)
+	router := transport.NewRouter(notifHandler, internalHandler, accountHandler, adminHandler, cfg.JWT, log, metricsCollector)
 
 	// HTTP server.
 	srv := &http.Server{
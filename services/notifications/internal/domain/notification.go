@@ -34,6 +34,25 @@ const (
 	TypeFeedbackInProgress NotificationType = "feedback_in_progress"
 	// TypeFeedbackAIDone — "the robot finished, thanks for the feedback".
 	TypeFeedbackAIDone NotificationType = "feedback_ai_done"
+
+	// Scheduled watch parties: the watch-together service emits an invite
+	// when a user is added to a party and a reminder shortly before it
+	// starts. Payload shape for both: WatchPartyPayload.
+
+	// TypeWatchPartyInvite — "you were invited to a watch party".
+	TypeWatchPartyInvite NotificationType = "watch_party_invite"
+	// TypeWatchPartyReminder — "your watch party starts soon".
+	TypeWatchPartyReminder NotificationType = "watch_party_reminder"
+
+	// TypeThemeTournamentRound — "a round of an OP/ED tournament opened,
+	// go vote". Emitted by the themes service to users who rated one of the
+	// tournament's themes or voted in it. Payload: ThemeTournamentRoundPayload.
+	TypeThemeTournamentRound NotificationType = "theme_tournament_round"
+
+	// TypeFanficChapter — "a work you subscribed to has a new chapter".
+	// Emitted by the fanfic service when an author publishes a chapter (now
+	// or on schedule). Payload: FanficChapterPayload.
+	TypeFanficChapter NotificationType = "fanfic_chapter"
 )
 
 // UserNotification is the per-user notification row.
@@ -87,6 +106,42 @@ type FeedbackStatusPayload struct {
 	Status      string `json:"status"` // created | in_progress | ai_done
 }
 
+// WatchPartyPayload is the JSON shape stored in UserNotification.Payload for
+// the watch_party_* types. Dedupe keys are `watch_party:{party_id}:invite`
+// and `watch_party:{party_id}:reminder`.
+type WatchPartyPayload struct {
+	PartyID   string `json:"party_id"`
+	Title     string `json:"title"`
+	AnimeID   string `json:"anime_id"`
+	EpisodeID string `json:"episode_id"`
+	StartsAt  string `json:"starts_at"` // RFC 3339
+	URL       string `json:"url"`
+}
+
+// ThemeTournamentRoundPayload is the JSON shape stored in
+// UserNotification.Payload for theme_tournament_round. Dedupe key is
+// `theme_tournament:{tournament_id}:round:{round}`.
+type ThemeTournamentRoundPayload struct {
+	TournamentID string `json:"tournament_id"`
+	Title        string `json:"title"`
+	Round        int    `json:"round"`
+	Rounds       int    `json:"rounds"`
+	EndsAt       string `json:"ends_at"` // RFC 3339
+	URL          string `json:"url"`
+}
+
+// FanficChapterPayload is the JSON shape stored in UserNotification.Payload
+// for fanfic_chapter. Dedupe key is `fanfic_chapter:{chapter_id}`.
+type FanficChapterPayload struct {
+	FanficID     string `json:"fanfic_id"`
+	ChapterID    string `json:"chapter_id"`
+	Title        string `json:"title"`
+	ChapterTitle string `json:"chapter_title,omitempty"`
+	Position     int    `json:"position"`
+	Author       string `json:"author,omitempty"`
+	URL          string `json:"url"`
+}
+
 // NewEpisodePayload is the JSON shape stored in UserNotification.Payload
 // when Type == TypeNewEpisode. Mirrors the design-doc payload spec.
 // All fields lowercase_snake_case per the project's JSON convention.
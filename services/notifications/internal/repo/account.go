@@ -0,0 +1,41 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/ILITA-hub/animeenigma/libs/database"
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"gorm.io/gorm"
+)
+
+// accountUserTables lists the notification tables holding a user's rows — user_notifications —
+// in erase order.
+var accountUserTables = []database.UserTable{
+	{Table: "user_notifications", Column: "user_id"},
+}
+
+// AccountRepository backs the auth-driven account export + erasure fan-out
+// (/internal/account/*).
+type AccountRepository struct{ db *gorm.DB }
+
+func NewAccountRepository(db *gorm.DB) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+// Export returns every notification row of the user, keyed by table.
+func (r *AccountRepository) Export(ctx context.Context, userID string) (map[string][]map[string]any, error) {
+	rows, err := database.ExportUserRows(ctx, r.db, userID, accountUserTables)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to export account data")
+	}
+	return rows, nil
+}
+
+// Erase hard-deletes every notification row of the user.
+func (r *AccountRepository) Erase(ctx context.Context, userID string) (map[string]int64, error) {
+	deleted, err := database.EraseUserRows(ctx, r.db, userID, accountUserTables)
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "failed to erase account data")
+	}
+	return deleted, nil
+}
@@ -15,10 +15,14 @@ import (
 // rejected at the Upsert boundary so a buggy producer can't pollute the
 // table with unrecognised types that the frontend won't render.
 var allowedTypes = map[string]bool{
-	string(domain.TypeNewEpisode):         true,
-	string(domain.TypeFeedbackCreated):    true,
-	string(domain.TypeFeedbackInProgress): true,
-	string(domain.TypeFeedbackAIDone):     true,
+	string(domain.TypeNewEpisode):           true,
+	string(domain.TypeFeedbackCreated):      true,
+	string(domain.TypeFeedbackInProgress):   true,
+	string(domain.TypeFeedbackAIDone):       true,
+	string(domain.TypeWatchPartyInvite):     true,
+	string(domain.TypeWatchPartyReminder):   true,
+	string(domain.TypeThemeTournamentRound): true,
+	string(domain.TypeFanficChapter):        true,
 }
 
 // NotificationService is the thin orchestration layer between the HTTP
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/database"
 	"github.com/ILITA-hub/animeenigma/libs/httputil"
 	"github.com/ILITA-hub/animeenigma/libs/logger"
 	"github.com/ILITA-hub/animeenigma/libs/metrics"
@@ -20,6 +21,7 @@ import (
 //	GET    /metrics                        (public, prom format)
 //	POST   /internal/notifications         (internal — gateway never proxies)
 //	GET    /internal/health                (internal — gateway never proxies)
+//	POST   /internal/account/{export,erase} (internal — auth account fan-out)
 //	GET    /api/notifications              (JWT)
 //	GET    /api/notifications/unread-count (JWT)
 //	POST   /api/notifications/mark-all-read(JWT)
@@ -34,6 +36,7 @@ import (
 func NewRouter(
 	notifHandler *handler.NotificationHandler,
 	internalHandler *handler.InternalHandler,
+	accountHandler *database.UserDataHandler,
 	adminHandler *handler.AdminHandler,
 	jwtConfig authz.JWTConfig,
 	log *logger.Logger,
@@ -71,6 +74,12 @@ func NewRouter(
 	r.Post("/internal/notifications", internalHandler.CreateNotification)
 	r.Post("/internal/notifications/invalidate", internalHandler.InvalidateNotifications)
 	r.Get("/internal/health", internalHandler.Health)
+	// Account export + erasure — the auth service's fan-out target when a
+	// user downloads their data or deletes their account.
+	if accountHandler != nil {
+		r.Post("/internal/account/export", accountHandler.Export)
+		r.Post("/internal/account/erase", accountHandler.Erase)
+	}
 
 	// Phase 2 manual-trigger endpoints (D-DET-05 / D-DET-06). Wired only
 	// when adminHandler is non-nil (NOTIFICATIONS_DETECTOR_ENABLED=false
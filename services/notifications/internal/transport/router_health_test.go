@@ -32,7 +32,7 @@ func sharedHealthCollector() *metrics.Collector {
 // here, so their nil-receiver method values are never dereferenced.
 func newHealthRouter(t *testing.T) http.Handler {
 	t.Helper()
-	return NewRouter(nil, nil, nil, authz.JWTConfig{}, logger.Default(), sharedHealthCollector())
+	return NewRouter(nil, nil, nil, nil, authz.JWTConfig{}, logger.Default(), sharedHealthCollector())
 }
 
 // The Docker healthcheck probes /health with `wget --spider`, which issues an
@@ -99,6 +99,19 @@ func main() {
 		&domain.SyncJob{},
 		&domain.ActivityEvent{},
 		&domain.UserFollow{},
+		// Yearly "Wrapped" recaps — precomputed by the scheduler-triggered
+		// /internal/recaps/generate sweep.
+		&domain.UserRecap{},
+		// Personal statistics aggregates — folded incrementally from
+		// watch_history on read.
+		&domain.UserStatsDaily{},
+		&domain.UserStatsHourly{},
+		&domain.UserStatsWatermark{},
+		// Personal Up Next queue + its version / rules row and the episodes
+		// the user dismissed from it.
+		&domain.UpNextItem{},
+		&domain.UpNextState{},
+		&domain.UpNextDismissal{},
 	); err != nil {
 		log.Fatalw("failed to migrate database", "error", err)
 	}
@@ -159,6 +172,16 @@ func main() {
 		log.Fatalw("failed to create watch filtered-sort indexes", "error", err)
 	}
 
+	// Personal stats fold watch_history by (user_id, seq) — see
+	// domain.WatchHistory.Seq. AutoMigrate adds the bigserial column (numbering
+	// the existing rows) but not the index on an existing table.
+	if err := db.DB.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_wh_user_seq
+		ON watch_history (user_id, seq)
+	`).Error; err != nil {
+		log.Fatalw("failed to create idx_wh_user_seq", "error", err)
+	}
+
 	// Phase 1 (workstream: social) — one-shot idempotent migration that
 	// merges every legacy `reviews` row into `anime_list` then drops the
 	// `reviews` table. Guarded by db.Migrator().HasTable("reviews"); after
@@ -461,11 +484,40 @@ func main() {
 	// preference fetches.
 	viewerContextHandler := handler.NewViewerContextHandler(progressService, listService, reviewService, prefService, log)
 
+	// Yearly "Wrapped" recap — built from watch_history + anime_list and the
+	// catalog genre/studio/character tables on the shared DB.
+	recapService := service.NewRecapService(repo.NewRecapRepository(db.DB), log)
+	recapHandler := handler.NewRecapHandler(recapService, log)
+
+	// Personal statistics page — materialized watch_history aggregates.
+	statsService := service.NewStatsService(repo.NewStatsRepository(db.DB), log)
+	statsHandler := handler.NewStatsHandler(statsService, log)
+
+	// Account export/erase target of the auth service's deletion fan-out.
+	accountHandler := database.NewUserDataHandler(repo.NewAccountRepository(db.DB), log)
+
+	// Scrobbling from external players / media servers. Release filenames
+	// are parsed by the library's detector over the Docker network.
+	scrobbleService := service.NewScrobbleService(
+		repo.NewScrobbleRepository(db.DB),
+		service.NewLibraryReleaseParser(cfg.Autocache.LibraryURL),
+		progressService,
+		listService,
+		cfg.Scrobble.CompletionThreshold,
+		log,
+	)
+	scrobbleHandler := handler.NewScrobbleHandler(scrobbleService, log)
+
+	// Personal Up Next queue; MarkEpisodeWatched advances it.
+	upNextService := service.NewUpNextService(repo.NewUpNextRepository(db.DB), log)
+	listService.WithUpNext(upNextService)
+	upNextHandler := handler.NewUpNextHandler(upNextService, log)
+
 	// Initialize metrics collector
 	metricsCollector := metrics.NewCollector("player")
 
 	// Initialize router
-	router := transport.NewRouter(progressHandler, listHandler, historyHandler, reviewHandler, commentHandler, showcaseHandler, compatibilityHandler, malImportHandler, malExportHandler, shikimoriImportHandler, reportHandler, syncHandler, activityHandler, exportHandler, prefHandler, overrideHandler, adminReportsHandler, internalListHandler, viewerContextHandler, cfg.JWT, log, metricsCollector)
+	router := transport.NewRouter(progressHandler, listHandler, historyHandler, reviewHandler, commentHandler, showcaseHandler, compatibilityHandler, malImportHandler, malExportHandler, shikimoriImportHandler, reportHandler, syncHandler, activityHandler, exportHandler, prefHandler, overrideHandler, adminReportsHandler, internalListHandler, viewerContextHandler, recapHandler, statsHandler, accountHandler, scrobbleHandler, upNextHandler, cfg.JWT, log, metricsCollector)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -25,6 +25,16 @@ type Config struct {
 	Notify        NotifyConfig
 	Autocache     AutocacheConfig
 	ContentVerify ContentVerifyConfig
+	Scrobble      ScrobbleConfig
+}
+
+// ScrobbleConfig controls the external-player / media-server scrobble API.
+// The release-filename parser is the library's, reached at
+// Autocache.LibraryURL + /internal/library/parse-filename.
+type ScrobbleConfig struct {
+	// CompletionThreshold is the position/duration ratio at which a stop
+	// event marks the episode watched. Default: 0.85
+	CompletionThreshold float64
 }
 
 // AutocacheConfig controls the fire-and-forget player→library autocache demand
@@ -207,6 +217,9 @@ func Load() (*Config, error) {
 			InternalURL: getEnv("NOTIFICATIONS_INTERNAL_URL", "http://notifications:8090"),
 			Enabled:     getEnvBool("FEEDBACK_NOTIFY_ENABLED", true),
 		},
+		Scrobble: ScrobbleConfig{
+			CompletionThreshold: getEnvFloat("SCROBBLE_COMPLETION_THRESHOLD", 0.85),
+		},
 		Autocache: AutocacheConfig{
 			LibraryURL:    getEnv("LIBRARY_SERVICE_URL", "http://library:8089"),
 			DemandEnabled: getEnvBool("AUTOCACHE_DEMAND_ENABLED", true),
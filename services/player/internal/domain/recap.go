@@ -0,0 +1,132 @@
+package domain
+
+import "time"
+
+// UserRecap is one precomputed yearly "Wrapped" recap. One row per
+// (user, year); regenerated in place by the scheduler-triggered
+// /internal/recaps/generate sweep (and on demand the first time the owner
+// opens a recap that has not been generated yet). Data holds the full
+// RecapData payload as JSON (jsonb on Postgres, TEXT on SQLite in tests).
+//
+// ShareToken is nil until the owner publishes the recap; the public
+// GET /api/recaps/{token} read resolves through its unique index. Revoking
+// the share nulls it again, so an old link stops resolving immediately.
+type UserRecap struct {
+	UserID      string    `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Year        int       `gorm:"primaryKey" json:"year"`
+	Data        RecapData `gorm:"type:jsonb;serializer:json;not null" json:"data"`
+	ShareToken  *string   `gorm:"size:32;uniqueIndex" json:"share_token,omitempty"`
+	GeneratedAt time.Time `gorm:"not null" json:"generated_at"`
+}
+
+func (UserRecap) TableName() string { return "user_recaps" }
+
+// RecapTopN bounds every "top" list in a recap (anime / genres / studios /
+// seiyuu) — enough for a podium + runners-up on the share card.
+const RecapTopN = 5
+
+// RecapBingeGap is the longest pause between two completed episodes of the
+// same anime that still counts as one binge. Two hours covers a meal break
+// without stitching separate evenings together.
+const RecapBingeGap = 2 * time.Hour
+
+// RecapData is the JSON payload served to the frontend. All durations are in
+// minutes (watch_history stores seconds); Months is always 12 long, index 0 =
+// January, bucketed in the user's profile timezone.
+type RecapData struct {
+	Year           int               `json:"year"`
+	TotalMinutes   int               `json:"total_minutes"`
+	TotalEpisodes  int               `json:"total_episodes"`
+	TotalAnime     int               `json:"total_anime"`
+	Months         []RecapMonth      `json:"months"`
+	TopAnime       []RecapAnimeStat  `json:"top_anime"`
+	TopGenres      []RecapNamedCount `json:"top_genres"`
+	TopStudios     []RecapNamedCount `json:"top_studios"`
+	TopSeiyuu      []RecapNamedCount `json:"top_seiyuu"`
+	LongestBinge   *RecapBinge       `json:"longest_binge,omitempty"`
+	CompletedCount int               `json:"completed_count"`
+	DroppedCount   int               `json:"dropped_count"`
+	// CompletionRate = completed / (titles touched this year), 0..1.
+	CompletionRate float64          `json:"completion_rate"`
+	FirstWatch     *RecapWatchPoint `json:"first_watch,omitempty"`
+	LastWatch      *RecapWatchPoint `json:"last_watch,omitempty"`
+	// Percentiles compare this user against every user with any watch
+	// history in the same year (0..100, "you watched more than N% of users").
+	MinutesPercentile  int `json:"minutes_percentile"`
+	EpisodesPercentile int `json:"episodes_percentile"`
+	InstanceUsers      int `json:"instance_users"`
+}
+
+// RecapMonth is one calendar month bucket.
+type RecapMonth struct {
+	Month    int `json:"month"` // 1..12
+	Minutes  int `json:"minutes"`
+	Episodes int `json:"episodes"`
+}
+
+// RecapAnimeStat is one entry of the top-anime podium.
+type RecapAnimeStat struct {
+	AnimeID   string `json:"anime_id"`
+	Name      string `json:"name"`
+	NameRU    string `json:"name_ru,omitempty"`
+	PosterURL string `json:"poster_url,omitempty"`
+	Minutes   int    `json:"minutes"`
+	Episodes  int    `json:"episodes"`
+}
+
+// RecapNamedCount is a genre / studio / seiyuu with the number of episodes
+// the user watched that carried it.
+type RecapNamedCount struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name"`
+	Episodes int    `json:"episodes"`
+}
+
+// RecapBinge is the longest uninterrupted run of one anime (consecutive
+// completions no more than RecapBingeGap apart).
+type RecapBinge struct {
+	AnimeID   string    `json:"anime_id"`
+	Name      string    `json:"name"`
+	Episodes  int       `json:"episodes"`
+	Minutes   int       `json:"minutes"`
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+}
+
+// RecapWatchPoint is the first or last episode watched in the year.
+type RecapWatchPoint struct {
+	AnimeID       string    `json:"anime_id"`
+	Name          string    `json:"name"`
+	PosterURL     string    `json:"poster_url,omitempty"`
+	EpisodeNumber int       `json:"episode_number"`
+	WatchedAt     time.Time `json:"watched_at"`
+}
+
+// RecapWatchRow is the repo projection of one watch_history row joined with
+// the anime title/poster — the raw input to the recap builder.
+type RecapWatchRow struct {
+	ID              string    `gorm:"column:id"`
+	AnimeID         string    `gorm:"column:anime_id"`
+	Name            string    `gorm:"column:name"`
+	NameRU          string    `gorm:"column:name_ru"`
+	PosterURL       string    `gorm:"column:poster_url"`
+	EpisodeNumber   int       `gorm:"column:episode_number"`
+	DurationWatched int       `gorm:"column:duration_watched"`
+	WatchedAt       time.Time `gorm:"column:watched_at"`
+}
+
+// RecapAttribute is one (anime, attribute) pair — a genre, studio or seiyuu
+// credited on an anime — used to roll the top-N attribute lists.
+type RecapAttribute struct {
+	AnimeID string `gorm:"column:anime_id"`
+	ID      string `gorm:"column:id"`
+	Name    string `gorm:"column:name"`
+}
+
+// RecapUserTotals is one user's yearly totals, used for the instance-wide
+// percentile comparison.
+type RecapUserTotals struct {
+	UserID   string `gorm:"column:user_id"`
+	Seconds  int64  `gorm:"column:seconds"`
+	Episodes int64  `gorm:"column:episodes"`
+}
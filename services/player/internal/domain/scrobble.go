@@ -0,0 +1,76 @@
+package domain
+
+// Scrobbling — playback events pushed by external players (mpv/VLC scripts,
+// Kodi) and media servers (Jellyfin, Plex) authenticated with a personal API
+// key. Every event records watch_progress; a stop (or a server-side
+// "played to completion") past the completion threshold marks the episode
+// watched exactly like the in-site player does.
+
+// Scrobble event types.
+const (
+	ScrobbleEventStart    = "start"
+	ScrobbleEventPause    = "pause"
+	ScrobbleEventResume   = "resume"
+	ScrobbleEventProgress = "progress"
+	ScrobbleEventStop     = "stop"
+)
+
+// Scrobble result statuses.
+const (
+	ScrobbleStatusRecorded  = "recorded"  // progress saved
+	ScrobbleStatusCompleted = "completed" // progress saved + episode marked watched
+	ScrobbleStatusIgnored   = "ignored"   // not an episode / nothing to record
+	ScrobbleStatusUnmatched = "unmatched" // no catalog anime matched the identifiers
+)
+
+// How a scrobble was matched to a catalog anime.
+const (
+	ScrobbleMatchAnimeID   = "anime_id"
+	ScrobbleMatchMAL       = "mal_id"
+	ScrobbleMatchAniList   = "anilist_id"
+	ScrobbleMatchShikimori = "shikimori_id"
+	ScrobbleMatchTitle     = "title"
+	ScrobbleMatchFilename  = "filename"
+)
+
+// ScrobblePlayer is the watch_history.player label of scrobbled completions.
+// The client name (mpv, jellyfin, plex, ...) goes to translation_title.
+const ScrobblePlayer = "scrobble"
+
+// ScrobbleRequest is the generic scrobble body. The anime is identified by the
+// first non-empty of AnimeID, MALID, AniListID, ShikimoriID, Title or
+// Filename; Episode may be omitted when Filename carries it. Position and
+// Duration are in seconds.
+type ScrobbleRequest struct {
+	Event       string `json:"event"`
+	AnimeID     string `json:"anime_id,omitempty"`
+	MALID       string `json:"mal_id,omitempty"`
+	AniListID   string `json:"anilist_id,omitempty"`
+	ShikimoriID string `json:"shikimori_id,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Filename    string `json:"filename,omitempty"`
+	Episode     int    `json:"episode,omitempty"`
+	Position    int    `json:"position"`
+	Duration    int    `json:"duration"`
+	Client      string `json:"client,omitempty"`
+	// Completed is set by media-server adapters whose payload states the item
+	// was played to completion (Plex media.scrobble, Jellyfin
+	// PlayedToCompletion); it marks the episode watched regardless of
+	// Position/Duration.
+	Completed bool `json:"-"`
+	// ServerCompletes is set by adapters whose media server sends its own
+	// completion event (Plex media.scrobble). Their stops then only record
+	// progress, so a play stopped past the threshold after the server already
+	// declared it complete is not marked watched a second time.
+	ServerCompletes bool `json:"-"`
+}
+
+// ScrobbleResult reports what a scrobble did.
+type ScrobbleResult struct {
+	Status    string `json:"status"`
+	AnimeID   string `json:"anime_id,omitempty"`
+	Episode   int    `json:"episode,omitempty"`
+	Position  int    `json:"position,omitempty"`
+	Duration  int    `json:"duration,omitempty"`
+	MatchedBy string `json:"matched_by,omitempty"`
+}
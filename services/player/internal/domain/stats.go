@@ -0,0 +1,173 @@
+package domain
+
+import "time"
+
+// Personal statistics — incrementally materialized aggregates of
+// watch_history. A stats read folds only the rows inserted after the user's
+// watermark (watch_history.seq) into the aggregate tables, so a heavy account with years of
+// history costs one small delta per request instead of a full rescan.
+//
+// Buckets are computed in the user's profile timezone at fold time. When the
+// timezone changes the user's aggregates are dropped and rebuilt from scratch.
+
+// UserStatsDaily is one (user, local day, anime) bucket.
+type UserStatsDaily struct {
+	UserID   string `gorm:"type:uuid;primaryKey" json:"-"`
+	Day      string `gorm:"size:10;primaryKey" json:"day"` // YYYY-MM-DD in the user's timezone
+	AnimeID  string `gorm:"type:uuid;primaryKey" json:"anime_id"`
+	Seconds  int64  `gorm:"not null;default:0" json:"seconds"`
+	Episodes int    `gorm:"not null;default:0" json:"episodes"`
+}
+
+func (UserStatsDaily) TableName() string { return "user_stats_daily" }
+
+// UserStatsHourly is one (user, local weekday, local hour, anime) bucket.
+// Weekday follows time.Weekday (0 = Sunday).
+type UserStatsHourly struct {
+	UserID   string `gorm:"type:uuid;primaryKey" json:"-"`
+	Weekday  int    `gorm:"primaryKey" json:"weekday"`
+	Hour     int    `gorm:"primaryKey" json:"hour"`
+	AnimeID  string `gorm:"type:uuid;primaryKey" json:"anime_id"`
+	Seconds  int64  `gorm:"not null;default:0" json:"seconds"`
+	Episodes int    `gorm:"not null;default:0" json:"episodes"`
+}
+
+func (UserStatsHourly) TableName() string { return "user_stats_hourly" }
+
+// UserStatsWatermark tracks how far a user's aggregates have been folded.
+// LastSeq is the watch_history.seq of the last folded row and doubles as the
+// optimistic-concurrency token: a fold commits only if the watermark is still
+// the one it read, so two concurrent reads can never count the same
+// watch_history row twice.
+type UserStatsWatermark struct {
+	UserID    string    `gorm:"type:uuid;primaryKey" json:"user_id"`
+	LastSeq   int64     `gorm:"not null;default:0" json:"last_seq"`
+	Timezone  string    `gorm:"size:64;not null;default:''" json:"timezone"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (UserStatsWatermark) TableName() string { return "user_stats_watermarks" }
+
+// StatsHeatmapDays is the heatmap window (one year of local days, today
+// included).
+const StatsHeatmapDays = 365
+
+// StatsPaceDays is the trailing window the episodes-per-day pace and the
+// projected finish dates are computed over.
+const StatsPaceDays = 30
+
+// StatsWatchRow is one watch_history row as the fold reads it.
+type StatsWatchRow struct {
+	Seq             int64     `json:"seq"`
+	AnimeID         string    `json:"anime_id"`
+	DurationWatched int       `json:"duration_watched"`
+	WatchedAt       time.Time `json:"watched_at"`
+}
+
+// StatsAnime is the catalog slice of an anime the stats breakdowns need.
+type StatsAnime struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	NameRU        string `json:"name_ru"`
+	PosterURL     string `json:"poster_url"`
+	Year          int    `json:"year"`
+	Season        string `json:"season"`
+	EpisodesCount int    `json:"episodes_count"`
+	EpisodesAired int    `json:"episodes_aired"`
+}
+
+// StatsListEntry is the anime_list slice the score + projection sections read.
+type StatsListEntry struct {
+	AnimeID  string `json:"anime_id"`
+	Status   string `json:"status"`
+	Score    int    `json:"score"`
+	Episodes int    `json:"episodes"`
+}
+
+// StatsCommunityScore is the instance-wide mean score of one anime.
+type StatsCommunityScore struct {
+	AnimeID  string  `json:"anime_id"`
+	AvgScore float64 `json:"avg_score"`
+}
+
+// StatsScoreCount is the instance-wide number of list entries with a score.
+type StatsScoreCount struct {
+	Score int   `json:"score"`
+	Count int64 `json:"count"`
+}
+
+// UserStats is the GET /stats response.
+type UserStats struct {
+	TotalMinutes  int `json:"total_minutes"`
+	TotalEpisodes int `json:"total_episodes"`
+	// Heatmap lists only days with activity inside the last StatsHeatmapDays,
+	// oldest first; the client fills the gaps.
+	Heatmap  []StatsDay      `json:"heatmap"`
+	Hours    []StatsTimeSlot `json:"hours"`    // 24 entries, local hour of day
+	Weekdays []StatsTimeSlot `json:"weekdays"` // 7 entries, 0 = Sunday
+	Scores   StatsScores     `json:"scores"`
+	Genres   []StatsShare    `json:"genres"`
+	Studios  []StatsShare    `json:"studios"`
+	Seasons  []StatsShare    `json:"seasons"` // ID is "<year>-<season>", e.g. "2024-fall"
+	// MeanEpisodesPerDay is the trailing StatsPaceDays pace.
+	MeanEpisodesPerDay float64           `json:"mean_episodes_per_day"`
+	Projections        []StatsProjection `json:"projections"`
+	Timezone           string            `json:"timezone"`
+}
+
+// StatsDay is one heatmap cell.
+type StatsDay struct {
+	Day      string `json:"day"`
+	Minutes  int    `json:"minutes"`
+	Episodes int    `json:"episodes"`
+}
+
+// StatsTimeSlot is one bar of the hour-of-day / weekday distributions.
+type StatsTimeSlot struct {
+	Slot     int `json:"slot"`
+	Minutes  int `json:"minutes"`
+	Episodes int `json:"episodes"`
+}
+
+// StatsScores compares the user's scoring with the rest of the instance.
+// Distribution always carries the ten buckets 1..10; CommunityShare is the
+// share of all instance scores in that bucket, Share the user's own.
+type StatsScores struct {
+	Distribution []StatsScoreBucket `json:"distribution"`
+	Mean         float64            `json:"mean"`
+	// CommunityMean is the community average over the same titles the user
+	// scored — "do I rate higher than everyone else on my shows".
+	CommunityMean float64 `json:"community_mean"`
+	Scored        int     `json:"scored"`
+}
+
+// StatsScoreBucket is one score bar.
+type StatsScoreBucket struct {
+	Score          int     `json:"score"`
+	Count          int     `json:"count"`
+	Share          float64 `json:"share"`
+	CommunityShare float64 `json:"community_share"`
+}
+
+// StatsShare is the watch time spent on one genre / studio / season.
+type StatsShare struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Minutes  int    `json:"minutes"`
+	Episodes int    `json:"episodes"`
+}
+
+// StatsProjection estimates when a "watching" title will be finished at the
+// user's current pace. ProjectedFinish is nil when there's no recent pace or
+// the total episode count is unknown.
+type StatsProjection struct {
+	AnimeID           string     `json:"anime_id"`
+	Name              string     `json:"name"`
+	NameRU            string     `json:"name_ru"`
+	PosterURL         string     `json:"poster_url"`
+	EpisodesWatched   int        `json:"episodes_watched"`
+	EpisodesTotal     int        `json:"episodes_total"`
+	EpisodesRemaining int        `json:"episodes_remaining"`
+	EpisodesPerDay    float64    `json:"episodes_per_day"`
+	ProjectedFinish   *time.Time `json:"projected_finish,omitempty"`
+}
@@ -0,0 +1,115 @@
+package domain
+
+import "time"
+
+// Up Next — an explicit, ordered, cross-anime episode queue per user
+// ("ep 4 of A, then ep 1 of B"). Unlike the Continue-Watching rail, which is
+// derived from recent progress, the queue is persisted and reordered by the
+// user. Completing a queued episode dequeues it and (with AutoEnqueue) puts
+// the anime's next aired episode in its place; the KeepAiring rule tops the
+// queue up with the next episode of every airing show on the watching list.
+//
+// Every mutation bumps UpNextState.Version. Devices poll with ?since=<version>
+// and send the version they reordered from, so a TV and a phone converge on
+// the same queue and a stale drag-reorder is rejected instead of clobbering
+// a newer one.
+
+// Up Next item sources.
+const (
+	UpNextSourceManual = "manual" // added by the user
+	UpNextSourceAuto   = "auto"   // next episode enqueued after a completion
+	UpNextSourceRule   = "rule"   // added by the KeepAiring rule
+)
+
+// UpNextMaxItems bounds one user's queue.
+const UpNextMaxItems = 200
+
+// UpNextMaxKeepAiring bounds the KeepAiring rule (episodes per airing show).
+const UpNextMaxKeepAiring = 3
+
+// UpNextItem is one queued episode. Position is dense (0..n-1) per user.
+type UpNextItem struct {
+	UserID        string    `gorm:"type:uuid;primaryKey" json:"-"`
+	AnimeID       string    `gorm:"type:uuid;primaryKey" json:"anime_id"`
+	EpisodeNumber int       `gorm:"primaryKey" json:"episode_number"`
+	Position      int       `gorm:"not null;default:0" json:"position"`
+	Source        string    `gorm:"size:10;not null;default:'manual'" json:"source"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (UpNextItem) TableName() string { return "up_next_items" }
+
+// UpNextState carries the queue version and the user's queue rules.
+type UpNextState struct {
+	UserID      string    `gorm:"type:uuid;primaryKey" json:"-"`
+	Version     int64     `gorm:"not null;default:0" json:"version"`
+	AutoEnqueue bool      `gorm:"not null;default:true" json:"auto_enqueue"`
+	KeepAiring  int       `gorm:"not null;default:0" json:"keep_airing"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (UpNextState) TableName() string { return "up_next_states" }
+
+// UpNextDismissal remembers an episode the user removed (or cleared) from the
+// queue so the KeepAiring rule does not put it back. Re-adding the episode by
+// hand or completing it forgets the dismissal.
+type UpNextDismissal struct {
+	UserID        string    `gorm:"type:uuid;primaryKey" json:"-"`
+	AnimeID       string    `gorm:"type:uuid;primaryKey" json:"-"`
+	EpisodeNumber int       `gorm:"primaryKey" json:"-"`
+	CreatedAt     time.Time `json:"-"`
+}
+
+func (UpNextDismissal) TableName() string { return "up_next_dismissals" }
+
+// UpNextSettings is the rule part of UpNextState.
+type UpNextSettings struct {
+	// AutoEnqueue puts the next aired episode in the queue when a queued (or
+	// any watching-list) episode is completed. Default true.
+	AutoEnqueue bool `json:"auto_enqueue"`
+	// KeepAiring keeps this many upcoming episodes of each airing show the
+	// user is watching in the queue. 0 disables the rule.
+	KeepAiring int `json:"keep_airing"`
+}
+
+// UpNextEntry is one queue row as served, with the anime projection and the
+// saved resume position for that episode.
+type UpNextEntry struct {
+	Anime         AnimeInfo `json:"anime"`
+	EpisodeNumber int       `json:"episode_number"`
+	Position      int       `json:"position"`
+	Source        string    `json:"source"`
+	Progress      int       `json:"progress"`
+	Duration      int       `json:"duration"`
+}
+
+// UpNextQueue is the GET /users/up-next payload. Changed is false (and Items
+// omitted) when the caller's ?since= already matches Version.
+type UpNextQueue struct {
+	Version  int64          `json:"version"`
+	Changed  bool           `json:"changed"`
+	Settings UpNextSettings `json:"settings"`
+	Items    []UpNextEntry  `json:"items,omitempty"`
+}
+
+// UpNextKey identifies one queued episode.
+type UpNextKey struct {
+	AnimeID       string `json:"anime_id"`
+	EpisodeNumber int    `json:"episode_number"`
+}
+
+// AddUpNextRequest enqueues an episode. Position nil appends; otherwise the
+// item is inserted at that index (clamped). Re-adding a queued episode moves
+// it.
+type AddUpNextRequest struct {
+	AnimeID       string `json:"anime_id"`
+	EpisodeNumber int    `json:"episode_number"`
+	Position      *int   `json:"position,omitempty"`
+}
+
+// ReorderUpNextRequest is a drag-reorder: the full new order plus the version
+// it was computed from (0 skips the check).
+type ReorderUpNextRequest struct {
+	Version int64       `json:"version"`
+	Items   []UpNextKey `json:"items"`
+}
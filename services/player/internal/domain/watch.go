@@ -186,7 +186,7 @@ var AllowedReactionEmojis = map[string]bool{
 // WatchHistory records a watched episode with full combo context
 type WatchHistory struct {
 	ID               string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	UserID           string `gorm:"type:uuid;not null;index:idx_wh_user_combo;index:idx_wh_user_watched,priority:1" json:"user_id"`
+	UserID           string `gorm:"type:uuid;not null;index:idx_wh_user_combo;index:idx_wh_user_watched,priority:1;index:idx_wh_user_seq,priority:1" json:"user_id"`
 	AnimeID          string `gorm:"not null;index;index:idx_wh_anime_combo" json:"anime_id"`
 	EpisodeNumber    int    `gorm:"not null" json:"episode_number"`
 	Player           string `gorm:"size:20;not null;index:idx_wh_user_combo;index:idx_wh_anime_combo" json:"player"`
@@ -207,6 +207,11 @@ type WatchHistory struct {
 	// instead of a sort over the user's full history. Authoritative DDL is the
 	// idempotent raw CREATE INDEX in cmd/player-api/main.go (ensureWatchIndexes).
 	WatchedAt time.Time `gorm:"not null;default:now();index:idx_wh_user_watched,priority:2,sort:desc" json:"watched_at"`
+	// Seq is a monotonic insert counter (bigserial). WatchedAt can be
+	// backdated by its writer, so the personal-stats fold watermarks on Seq
+	// instead: a late row with an old timestamp still sorts after everything
+	// already folded.
+	Seq int64 `gorm:"autoIncrement;not null;index:idx_wh_user_seq,priority:2" json:"-"`
 }
 
 func (WatchHistory) TableName() string {
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// RecapHandler serves the yearly "Wrapped" recap:
+//
+//	GET    /api/users/recap/{year}        (owner read, JWT)
+//	POST   /api/users/recap/{year}/share  (owner publish, JWT)
+//	DELETE /api/users/recap/{year}/share  (owner revoke, JWT)
+//	GET    /api/recaps/{token}            (public read of a shared recap)
+//	POST   /internal/recaps/generate      (scheduler sweep trigger)
+type RecapHandler struct {
+	svc *service.RecapService
+	log *logger.Logger
+}
+
+// NewRecapHandler wires a RecapHandler against the service layer.
+func NewRecapHandler(s *service.RecapService, log *logger.Logger) *RecapHandler {
+	return &RecapHandler{svc: s, log: log}
+}
+
+// sharedRecapResponse is the public shape — the owner's user_id stays off
+// the wire so a share link doesn't leak an account identifier.
+type sharedRecapResponse struct {
+	Year        int              `json:"year"`
+	Data        domain.RecapData `json:"data"`
+	GeneratedAt time.Time        `json:"generated_at"`
+}
+
+// GetMyRecap handles GET /api/users/recap/{year}.
+func (h *RecapHandler) GetMyRecap(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+	year, err := strconv.Atoi(chi.URLParam(r, "year"))
+	if err != nil {
+		httputil.BadRequest(w, "invalid year")
+		return
+	}
+	recap, err := h.svc.Get(r.Context(), claims.UserID, year)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, recap)
+}
+
+// ShareRecap handles POST /api/users/recap/{year}/share. Returns the new
+// token; the frontend builds the public URL from it.
+func (h *RecapHandler) ShareRecap(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+	year, err := strconv.Atoi(chi.URLParam(r, "year"))
+	if err != nil {
+		httputil.BadRequest(w, "invalid year")
+		return
+	}
+	token, err := h.svc.Share(r.Context(), claims.UserID, year)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, map[string]string{"share_token": token})
+}
+
+// UnshareRecap handles DELETE /api/users/recap/{year}/share.
+func (h *RecapHandler) UnshareRecap(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+	year, err := strconv.Atoi(chi.URLParam(r, "year"))
+	if err != nil {
+		httputil.BadRequest(w, "invalid year")
+		return
+	}
+	if err := h.svc.Unshare(r.Context(), claims.UserID, year); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.NoContent(w)
+}
+
+// GetSharedRecap handles GET /api/recaps/{token}. Public — no auth.
+func (h *RecapHandler) GetSharedRecap(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	if token == "" {
+		httputil.BadRequest(w, "token is required")
+		return
+	}
+	recap, err := h.svc.GetShared(r.Context(), token)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, sharedRecapResponse{Year: recap.Year, Data: recap.Data, GeneratedAt: recap.GeneratedAt})
+}
+
+// GenerateInternal handles POST /internal/recaps/generate?year=YYYY (year
+// defaults to the current one). The sweep runs in the background, so this
+// answers 202 immediately; "started":false means a sweep is already running.
+// Docker-network only — the gateway does not proxy /internal/*.
+func (h *RecapHandler) GenerateInternal(w http.ResponseWriter, r *http.Request) {
+	year := time.Now().Year()
+	if v := r.URL.Query().Get("year"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			httputil.BadRequest(w, "invalid year")
+			return
+		}
+		year = parsed
+	}
+	started, err := h.svc.StartSweep(year)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.JSON(w, http.StatusAccepted, map[string]any{"year": year, "started": started})
+}
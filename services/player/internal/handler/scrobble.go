@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/service"
+)
+
+// maxScrobbleBody caps webhook bodies. Plex posts multipart with an optional
+// poster thumbnail part, which is skipped but still has to be read.
+const maxScrobbleBody = 4 << 20
+
+// ScrobbleHandler serves the external-player scrobble API. All routes sit in
+// the JWT group; external clients authenticate with a personal API key
+// (Authorization: Bearer ak_…, or ?api_key=ak_… for webhook senders that
+// can't set headers — the gateway swaps either for a JWT).
+//
+//	POST /api/users/scrobble           generic JSON (mpv / VLC / Kodi scripts)
+//	POST /api/users/scrobble/jellyfin  Jellyfin webhook plugin payload
+//	POST /api/users/scrobble/plex      Plex webhook (multipart "payload")
+type ScrobbleHandler struct {
+	svc *service.ScrobbleService
+	log *logger.Logger
+}
+
+// NewScrobbleHandler wires a ScrobbleHandler against the service layer.
+func NewScrobbleHandler(s *service.ScrobbleService, log *logger.Logger) *ScrobbleHandler {
+	return &ScrobbleHandler{svc: s, log: log}
+}
+
+// Scrobble handles POST /api/users/scrobble.
+func (h *ScrobbleHandler) Scrobble(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+	var req domain.ScrobbleRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	h.record(w, r, claims.UserID, &req)
+}
+
+// Jellyfin handles POST /api/users/scrobble/jellyfin. Non-playback
+// notifications and non-episode items answer 200 "ignored" so the plugin
+// doesn't retry them.
+func (h *ScrobbleHandler) Jellyfin(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+	var payload map[string]any
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxScrobbleBody)).Decode(&payload); err != nil {
+		httputil.BadRequest(w, "invalid Jellyfin webhook payload")
+		return
+	}
+	req, ok := jellyfinScrobble(payload)
+	if !ok {
+		httputil.OK(w, &domain.ScrobbleResult{Status: domain.ScrobbleStatusIgnored})
+		return
+	}
+	h.record(w, r, claims.UserID, req)
+}
+
+// Plex handles POST /api/users/scrobble/plex. Plex fires the webhook for
+// every account on the server; ?account=<Plex username> restricts it to one.
+func (h *ScrobbleHandler) Plex(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxScrobbleBody)
+	if err := r.ParseMultipartForm(maxScrobbleBody); err != nil {
+		httputil.BadRequest(w, "invalid Plex webhook payload")
+		return
+	}
+	var payload plexWebhook
+	if err := json.Unmarshal([]byte(r.FormValue("payload")), &payload); err != nil {
+		httputil.BadRequest(w, "invalid Plex webhook payload")
+		return
+	}
+	if account := r.URL.Query().Get("account"); account != "" && !strings.EqualFold(account, payload.Account.Title) {
+		httputil.OK(w, &domain.ScrobbleResult{Status: domain.ScrobbleStatusIgnored})
+		return
+	}
+	req, ok := plexScrobble(&payload)
+	if !ok {
+		httputil.OK(w, &domain.ScrobbleResult{Status: domain.ScrobbleStatusIgnored})
+		return
+	}
+	h.record(w, r, claims.UserID, req)
+}
+
+func (h *ScrobbleHandler) record(w http.ResponseWriter, r *http.Request, userID string, req *domain.ScrobbleRequest) {
+	res, err := h.svc.Scrobble(r.Context(), userID, req)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, res)
+}
+
+// jellyfinTicksPerSecond — Jellyfin positions are in 100ns ticks.
+const jellyfinTicksPerSecond = 10_000_000
+
+// jellyfinScrobble maps a Jellyfin webhook-plugin payload to a scrobble.
+// The plugin's template is user-editable and renders numbers as strings or
+// numbers depending on it, so fields are read loosely. Provider IDs are
+// matched case-insensitively (Provider_AniList / Provider_anilist).
+func jellyfinScrobble(p map[string]any) (*domain.ScrobbleRequest, bool) {
+	if !strings.EqualFold(jsonString(p["ItemType"]), "Episode") {
+		return nil, false
+	}
+	req := &domain.ScrobbleRequest{Client: "jellyfin"}
+	switch jsonString(p["NotificationType"]) {
+	case "PlaybackStart":
+		req.Event = domain.ScrobbleEventStart
+	case "PlaybackProgress":
+		req.Event = domain.ScrobbleEventProgress
+		if b, _ := strconv.ParseBool(jsonString(p["IsPaused"])); b {
+			req.Event = domain.ScrobbleEventPause
+		}
+	case "PlaybackStop":
+		req.Event = domain.ScrobbleEventStop
+		req.Completed, _ = strconv.ParseBool(jsonString(p["PlayedToCompletion"]))
+	default:
+		return nil, false
+	}
+	for k, v := range p {
+		switch strings.ToLower(k) {
+		case "provider_anilist":
+			req.AniListID = jsonString(v)
+		case "provider_myanimelist", "provider_mal":
+			req.MALID = jsonString(v)
+		}
+	}
+	req.Title = jsonString(p["SeriesName"])
+	req.Episode = jsonInt(p["EpisodeNumber"])
+	req.Position = jsonInt(p["PlaybackPositionTicks"]) / jellyfinTicksPerSecond
+	req.Duration = jsonInt(p["RunTimeTicks"]) / jellyfinTicksPerSecond
+	return req, true
+}
+
+// plexWebhook is the subset of the Plex webhook payload the scrobbler reads.
+// viewOffset and duration are milliseconds.
+type plexWebhook struct {
+	Event   string `json:"event"`
+	Account struct {
+		Title string `json:"title"`
+	} `json:"Account"`
+	Metadata struct {
+		Type             string `json:"type"`
+		GrandparentTitle string `json:"grandparentTitle"`
+		Index            int    `json:"index"`
+		ViewOffset       int    `json:"viewOffset"`
+		Duration         int    `json:"duration"`
+	} `json:"Metadata"`
+}
+
+// plexScrobble maps a Plex webhook to a scrobble. media.scrobble is Plex's
+// own "watched" signal (fired at 90%) and is the only event that completes
+// the episode — the media.stop that follows it must not complete it again.
+func plexScrobble(p *plexWebhook) (*domain.ScrobbleRequest, bool) {
+	if p.Metadata.Type != "episode" {
+		return nil, false
+	}
+	req := &domain.ScrobbleRequest{
+		Client:          "plex",
+		Title:           p.Metadata.GrandparentTitle,
+		Episode:         p.Metadata.Index,
+		Position:        p.Metadata.ViewOffset / 1000,
+		Duration:        p.Metadata.Duration / 1000,
+		ServerCompletes: true,
+	}
+	switch p.Event {
+	case "media.play":
+		req.Event = domain.ScrobbleEventStart
+	case "media.pause":
+		req.Event = domain.ScrobbleEventPause
+	case "media.resume":
+		req.Event = domain.ScrobbleEventResume
+	case "media.stop":
+		req.Event = domain.ScrobbleEventStop
+	case "media.scrobble":
+		req.Event = domain.ScrobbleEventStop
+		req.Completed = true
+	default:
+		return nil, false
+	}
+	return req, true
+}
+
+// jsonString renders a decoded JSON scalar as a string ("" for null).
+func jsonString(v any) string {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t)
+	case float64:
+		return strconv.FormatInt(int64(t), 10)
+	case bool:
+		return strconv.FormatBool(t)
+	}
+	return ""
+}
+
+// jsonInt reads a decoded JSON number or numeric string (0 otherwise).
+func jsonInt(v any) int {
+	switch t := v.(type) {
+	case float64:
+		return int(t)
+	case string:
+		n, _ := strconv.ParseInt(strings.TrimSpace(t), 10, 64)
+		return int(n)
+	}
+	return 0
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJellyfinScrobble_MapsWebhookPayload(t *testing.T) {
+	// Numbers as the webhook plugin's default template renders them: some
+	// quoted, some bare.
+	raw := `{
+		"NotificationType": "PlaybackStop",
+		"ItemType": "Episode",
+		"SeriesName": "Sousou no Frieren",
+		"EpisodeNumber": "5",
+		"PlaybackPositionTicks": 13800000000,
+		"RunTimeTicks": "14400000000",
+		"PlayedToCompletion": "True",
+		"Provider_AniList": "154587"
+	}`
+	var p map[string]any
+	require.NoError(t, json.Unmarshal([]byte(raw), &p))
+
+	req, ok := jellyfinScrobble(p)
+	require.True(t, ok)
+	assert.Equal(t, &domain.ScrobbleRequest{
+		Event:     domain.ScrobbleEventStop,
+		AniListID: "154587",
+		Title:     "Sousou no Frieren",
+		Episode:   5,
+		Position:  1380,
+		Duration:  1440,
+		Client:    "jellyfin",
+		Completed: true,
+	}, req)
+
+	p["NotificationType"] = "PlaybackProgress"
+	p["IsPaused"] = true
+	req, ok = jellyfinScrobble(p)
+	require.True(t, ok)
+	assert.Equal(t, domain.ScrobbleEventPause, req.Event)
+	assert.False(t, req.Completed)
+
+	p["ItemType"] = "Movie"
+	_, ok = jellyfinScrobble(p)
+	assert.False(t, ok)
+}
+
+func TestPlexScrobble_MapsWebhookPayload(t *testing.T) {
+	raw := `{
+		"event": "media.scrobble",
+		"Account": {"title": "alice"},
+		"Metadata": {"type": "episode", "grandparentTitle": "Sousou no Frieren", "index": 5, "viewOffset": 1300000, "duration": 1440000}
+	}`
+	var p plexWebhook
+	require.NoError(t, json.Unmarshal([]byte(raw), &p))
+
+	req, ok := plexScrobble(&p)
+	require.True(t, ok)
+	assert.Equal(t, &domain.ScrobbleRequest{
+		Event:           domain.ScrobbleEventStop,
+		Title:           "Sousou no Frieren",
+		Episode:         5,
+		Position:        1300,
+		Duration:        1440,
+		Client:          "plex",
+		Completed:       true,
+		ServerCompletes: true,
+	}, req)
+
+	// The media.stop Plex sends after media.scrobble is progress only.
+	p.Event = "media.stop"
+	req, ok = plexScrobble(&p)
+	require.True(t, ok)
+	assert.Equal(t, domain.ScrobbleEventStop, req.Event)
+	assert.False(t, req.Completed)
+	assert.True(t, req.ServerCompletes)
+
+	p.Event = "library.new"
+	_, ok = plexScrobble(&p)
+	assert.False(t, ok)
+
+	p.Event = "media.play"
+	p.Metadata.Type = "track"
+	_, ok = plexScrobble(&p)
+	assert.False(t, ok)
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// StatsHandler serves the personal statistics page:
+//
+//	GET /api/users/stats                (owner, JWT)
+//	GET /api/users/{userId}/stats/public (public, activity_visibility applied)
+type StatsHandler struct {
+	svc *service.StatsService
+	log *logger.Logger
+}
+
+// NewStatsHandler wires a StatsHandler against the service layer.
+func NewStatsHandler(s *service.StatsService, log *logger.Logger) *StatsHandler {
+	return &StatsHandler{svc: s, log: log}
+}
+
+// GetMyStats handles GET /api/users/stats.
+func (h *StatsHandler) GetMyStats(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+	stats, err := h.svc.GetOwn(r.Context(), claims.UserID)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, stats)
+}
+
+// GetPublicStats handles GET /api/users/{userId}/stats/public. No auth.
+func (h *StatsHandler) GetPublicStats(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userId")
+	if userID == "" {
+		httputil.BadRequest(w, "user ID is required")
+		return
+	}
+	stats, err := h.svc.GetPublic(r.Context(), userID)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, stats)
+}
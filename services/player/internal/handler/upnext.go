@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// UpNextHandler serves the personal Up Next queue (all JWT, owner only):
+//
+//	GET    /api/users/up-next?since=<version>
+//	POST   /api/users/up-next                        {anime_id, episode_number, position?}
+//	PUT    /api/users/up-next/order                  {version, items:[{anime_id, episode_number}]}
+//	PUT    /api/users/up-next/settings               {auto_enqueue, keep_airing}
+//	DELETE /api/users/up-next/{animeId}/{episode}
+//	DELETE /api/users/up-next
+//
+// Every write answers with the full queue and its new version. The scheduler
+// also drives the KeepAiring top-up through POST /internal/up-next/fill-airing.
+type UpNextHandler struct {
+	svc *service.UpNextService
+	log *logger.Logger
+}
+
+// NewUpNextHandler wires an UpNextHandler against the service layer.
+func NewUpNextHandler(s *service.UpNextService, log *logger.Logger) *UpNextHandler {
+	return &UpNextHandler{svc: s, log: log}
+}
+
+// GetQueue handles GET /api/users/up-next.
+func (h *UpNextHandler) GetQueue(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+	var since int64
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || v < 0 {
+			httputil.BadRequest(w, "since must be a non-negative version")
+			return
+		}
+		since = v
+	}
+	q, err := h.svc.Get(r.Context(), claims.UserID, since)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, q)
+}
+
+// Add handles POST /api/users/up-next.
+func (h *UpNextHandler) Add(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+	var req domain.AddUpNextRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	q, err := h.svc.Add(r.Context(), claims.UserID, &req)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, q)
+}
+
+// Reorder handles PUT /api/users/up-next/order.
+func (h *UpNextHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+	var req domain.ReorderUpNextRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	q, err := h.svc.Reorder(r.Context(), claims.UserID, &req)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, q)
+}
+
+// UpdateSettings handles PUT /api/users/up-next/settings.
+func (h *UpNextHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+	var req domain.UpNextSettings
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	q, err := h.svc.UpdateSettings(r.Context(), claims.UserID, &req)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, q)
+}
+
+// Remove handles DELETE /api/users/up-next/{animeId}/{episode}.
+func (h *UpNextHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+	episode, err := strconv.Atoi(chi.URLParam(r, "episode"))
+	if err != nil || episode < 1 {
+		httputil.BadRequest(w, "invalid episode number")
+		return
+	}
+	key := domain.UpNextKey{AnimeID: chi.URLParam(r, "animeId"), EpisodeNumber: episode}
+	q, err := h.svc.Remove(r.Context(), claims.UserID, key)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, q)
+}
+
+// Clear handles DELETE /api/users/up-next.
+func (h *UpNextHandler) Clear(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+	q, err := h.svc.Clear(r.Context(), claims.UserID)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, q)
+}
+
+// FillAiringInternal handles POST /internal/up-next/fill-airing: tops up the
+// queue of every user with the KeepAiring rule on. Docker-network only — the
+// gateway does not proxy /internal/*.
+func (h *UpNextHandler) FillAiringInternal(w http.ResponseWriter, r *http.Request) {
+	changed, err := h.svc.FillAiringAll(r.Context())
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, map[string]any{"changed": changed})
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/repo"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupUpNextHandlerTest mounts UpNextHandler on the production paths over
+// in-memory SQLite; u1 is watching the airing show c (6 of 24 aired).
+func setupUpNextHandlerTest(t *testing.T) *chi.Mux {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err, "open in-memory sqlite")
+
+	stmts := []string{
+		`CREATE TABLE animes (
+			id TEXT PRIMARY KEY, name TEXT, name_ru TEXT, name_jp TEXT, poster_url TEXT,
+			episodes_count INTEGER, episodes_aired INTEGER, status TEXT, deleted_at DATETIME
+		)`,
+		`CREATE TABLE anime_list (
+			user_id TEXT, anime_id TEXT, status TEXT, episodes INTEGER DEFAULT 0, updated_at DATETIME
+		)`,
+		`CREATE TABLE watch_progress (
+			user_id TEXT, anime_id TEXT, episode_number INTEGER, progress INTEGER, duration INTEGER
+		)`,
+		`CREATE TABLE up_next_items (
+			user_id TEXT, anime_id TEXT, episode_number INTEGER,
+			position INTEGER NOT NULL DEFAULT 0, source TEXT NOT NULL DEFAULT 'manual', created_at DATETIME,
+			PRIMARY KEY (user_id, anime_id, episode_number)
+		)`,
+		`CREATE TABLE up_next_states (
+			user_id TEXT PRIMARY KEY, version INTEGER NOT NULL DEFAULT 0,
+			auto_enqueue NUMERIC NOT NULL DEFAULT 1, keep_airing INTEGER NOT NULL DEFAULT 0, updated_at DATETIME
+		)`,
+		`CREATE TABLE up_next_dismissals (
+			user_id TEXT, anime_id TEXT, episode_number INTEGER, created_at DATETIME,
+			PRIMARY KEY (user_id, anime_id, episode_number)
+		)`,
+		`INSERT INTO animes (id, name, episodes_count, episodes_aired, status) VALUES
+			('a', 'Show A', 12, 0, 'released'),
+			('c', 'Airing C', 24, 6, 'ongoing')`,
+		`INSERT INTO anime_list (user_id, anime_id, status, episodes) VALUES ('u1', 'c', 'watching', 0)`,
+	}
+	for _, s := range stmts {
+		require.NoError(t, db.Exec(s).Error)
+	}
+
+	log, err := logger.New(logger.Config{Level: "error", Development: false, Encoding: "json"})
+	require.NoError(t, err)
+	h := NewUpNextHandler(service.NewUpNextService(repo.NewUpNextRepository(db), log), log)
+
+	r := chi.NewRouter()
+	r.Route("/api/users/up-next", func(r chi.Router) {
+		r.Get("/", h.GetQueue)
+		r.Post("/", h.Add)
+		r.Put("/order", h.Reorder)
+		r.Put("/settings", h.UpdateSettings)
+		r.Delete("/", h.Clear)
+		r.Delete("/{animeId}/{episode}", h.Remove)
+	})
+	return r
+}
+
+// doUpNext serves one request as u1 and decodes the {"data": queue} envelope.
+func doUpNext(t *testing.T, r *chi.Mux, method, path string, body interface{}) (int, domain.UpNextQueue) {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buf).Encode(body))
+	}
+	req := withListClaims(httptest.NewRequest(method, path, &buf), "u1", "alice")
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	var resp struct {
+		Data domain.UpNextQueue `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	return rec.Code, resp.Data
+}
+
+func TestUpNextHandler_RemoveRuleEpisode(t *testing.T) {
+	r := setupUpNextHandlerTest(t)
+
+	code, q := doUpNext(t, r, http.MethodPut, "/api/users/up-next/settings", domain.UpNextSettings{AutoEnqueue: true, KeepAiring: 1})
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, q.Items, 1)
+	assert.Equal(t, domain.UpNextSourceRule, q.Items[0].Source)
+
+	code, q = doUpNext(t, r, http.MethodDelete, "/api/users/up-next/c/1", nil)
+	require.Equal(t, http.StatusOK, code)
+	assert.Empty(t, q.Items)
+
+	code, q = doUpNext(t, r, http.MethodGet, "/api/users/up-next", nil)
+	require.Equal(t, http.StatusOK, code)
+	assert.Empty(t, q.Items, "removed rule episode came back on the next poll")
+
+	code, _ = doUpNext(t, r, http.MethodDelete, "/api/users/up-next/c/1", nil)
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = doUpNext(t, r, http.MethodDelete, "/api/users/up-next/c/zero", nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestUpNextHandler_PollAndReorder(t *testing.T) {
+	r := setupUpNextHandlerTest(t)
+
+	doUpNext(t, r, http.MethodPost, "/api/users/up-next", domain.AddUpNextRequest{AnimeID: "a", EpisodeNumber: 1})
+	code, q := doUpNext(t, r, http.MethodPost, "/api/users/up-next", domain.AddUpNextRequest{AnimeID: "a", EpisodeNumber: 2})
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, q.Items, 2)
+
+	code, polled := doUpNext(t, r, http.MethodGet, "/api/users/up-next?since="+strconv.FormatInt(q.Version, 10), nil)
+	require.Equal(t, http.StatusOK, code)
+	assert.False(t, polled.Changed)
+
+	order := []domain.UpNextKey{{AnimeID: "a", EpisodeNumber: 2}, {AnimeID: "a", EpisodeNumber: 1}}
+	code, _ = doUpNext(t, r, http.MethodPut, "/api/users/up-next/order", domain.ReorderUpNextRequest{Version: q.Version - 1, Items: order})
+	assert.Equal(t, http.StatusConflict, code, "stale version")
+
+	code, q = doUpNext(t, r, http.MethodPut, "/api/users/up-next/order", domain.ReorderUpNextRequest{Version: q.Version, Items: order})
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, q.Items, 2)
+	assert.Equal(t, 2, q.Items[0].EpisodeNumber)
+
+	code, q = doUpNext(t, r, http.MethodDelete, "/api/users/up-next", nil)
+	require.Equal(t, http.StatusOK, code)
+	assert.Empty(t, q.Items)
+}
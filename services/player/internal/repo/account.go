@@ -0,0 +1,60 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/ILITA-hub/animeenigma/libs/database"
+	"github.com/ILITA-hub/animeenigma/libs/errors"
+	"gorm.io/gorm"
+)
+
+// playerUserTables lists every player-owned table holding a user's rows, in
+// erase order (children first). review_reactions left by OTHER users on this
+// user's reviews go with anime_list via the ON DELETE CASCADE FK.
+var playerUserTables = []database.UserTable{
+	{Table: "review_reactions", Column: "user_id"},
+	{Table: "comments", Column: "user_id"},
+	{Table: "activity_events", Column: "user_id"},
+	{Table: "user_follows", Column: "follower_id"},
+	{Table: "user_follows", Column: "followed_id"},
+	{Table: "watch_history", Column: "user_id"},
+	{Table: "watch_progress", Column: "user_id"},
+	{Table: "user_anime_preferences", Column: "user_id"},
+	{Table: "user_prefs_version", Column: "user_id"},
+	{Table: "profile_showcases", Column: "user_id"},
+	{Table: "sync_jobs", Column: "user_id"},
+	{Table: "user_recaps", Column: "user_id"},
+	{Table: "user_stats_daily", Column: "user_id"},
+	{Table: "user_stats_hourly", Column: "user_id"},
+	{Table: "user_stats_watermarks", Column: "user_id"},
+	{Table: "up_next_items", Column: "user_id"},
+	{Table: "up_next_states", Column: "user_id"},
+	{Table: "up_next_dismissals", Column: "user_id"},
+	{Table: "anime_list", Column: "user_id"},
+}
+
+// AccountRepository backs the auth-driven account export + erasure fan-out
+// (/internal/account/*).
+type AccountRepository struct{ db *gorm.DB }
+
+func NewAccountRepository(db *gorm.DB) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+// Export returns every player-owned row of the user, keyed by table.
+func (r *AccountRepository) Export(ctx context.Context, userID string) (map[string][]map[string]any, error) {
+	rows, err := database.ExportUserRows(ctx, r.db, userID, playerUserTables)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to export account data")
+	}
+	return rows, nil
+}
+
+// Erase hard-deletes every player-owned row of the user.
+func (r *AccountRepository) Erase(ctx context.Context, userID string) (map[string]int64, error) {
+	deleted, err := database.EraseUserRows(ctx, r.db, userID, playerUserTables)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to erase account data")
+	}
+	return deleted, nil
+}
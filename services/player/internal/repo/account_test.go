@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupAccountTestDB creates a minimal stand-in for every table in
+// playerUserTables — just the ownership column(s) plus a payload column.
+func setupAccountTestDB(t *testing.T) (*AccountRepository, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err, "open in-memory sqlite")
+	created := map[string]bool{}
+	for _, tbl := range playerUserTables {
+		if created[tbl.Table] {
+			continue
+		}
+		created[tbl.Table] = true
+		stmt := `CREATE TABLE ` + tbl.Table + ` (user_id TEXT, note TEXT)`
+		if tbl.Table == "user_follows" {
+			stmt = `CREATE TABLE user_follows (follower_id TEXT, followed_id TEXT)`
+		}
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	return NewAccountRepository(db), db
+}
+
+func TestAccountRepository_ExportAndErase(t *testing.T) {
+	r, db := setupAccountTestDB(t)
+	ctx := context.Background()
+	require.NoError(t, db.Exec(`INSERT INTO anime_list (user_id, note) VALUES ('u1', 'mine'), ('u2', 'theirs')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO comments (user_id, note) VALUES ('u1', 'hi')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO user_follows (follower_id, followed_id) VALUES ('u1', 'u2'), ('u3', 'u1'), ('u2', 'u3')`).Error)
+
+	exported, err := r.Export(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, exported["anime_list"], 1)
+	assert.Equal(t, "mine", exported["anime_list"][0]["note"])
+	assert.Len(t, exported["user_follows"], 2, "both directions of the follow graph")
+	assert.NotNil(t, exported["watch_history"], "empty tables export as [] not null")
+
+	deleted, err := r.Erase(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted["anime_list"])
+	assert.Equal(t, int64(2), deleted["user_follows"])
+
+	var left int64
+	require.NoError(t, db.Raw(`SELECT COUNT(*) FROM user_follows`).Scan(&left).Error)
+	assert.Equal(t, int64(1), left, "unrelated follow survives")
+	require.NoError(t, db.Raw(`SELECT COUNT(*) FROM anime_list WHERE user_id = 'u2'`).Scan(&left).Error)
+	assert.Equal(t, int64(1), left)
+
+	// Idempotent — a retried erase succeeds with nothing left to delete.
+	deleted, err = r.Erase(ctx, "u1")
+	require.NoError(t, err)
+	assert.Zero(t, deleted["anime_list"])
+}
@@ -0,0 +1,238 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// recapHistoryPage is how many watch_history rows one YearHistory query
+// reads; the year is paged through in (watched_at, id) order so a heavy
+// account is loaded in full without one unbounded scan.
+const recapHistoryPage = 5000
+
+// RecapRepository reads the raw inputs of the yearly "Wrapped" recap
+// (watch_history, anime_list, the catalog-owned genre/studio/character
+// tables on the shared DB) and persists the computed user_recaps rows.
+// All queries are plain SQL so they run on Postgres (prod) and SQLite
+// (repo tests).
+type RecapRepository struct{ db *gorm.DB }
+
+func NewRecapRepository(db *gorm.DB) *RecapRepository {
+	return &RecapRepository{db: db}
+}
+
+// YearHistory returns the user's watch_history rows with watched_at in
+// [from, to), oldest first, joined with the anime title and poster. Rows for
+// soft-deleted or missing anime keep an empty name (LEFT JOIN).
+func (r *RecapRepository) YearHistory(ctx context.Context, userID string, from, to time.Time) ([]domain.RecapWatchRow, error) {
+	var out []domain.RecapWatchRow
+	for {
+		q := r.db.WithContext(ctx).
+			Table("watch_history wh").
+			Select("wh.id, wh.anime_id, COALESCE(a.name, '') AS name, COALESCE(a.name_ru, '') AS name_ru, COALESCE(a.poster_url, '') AS poster_url, wh.episode_number, wh.duration_watched, wh.watched_at").
+			Joins("LEFT JOIN animes a ON a.id = wh.anime_id").
+			Where("wh.user_id = ? AND wh.watched_at >= ? AND wh.watched_at < ?", userID, from, to)
+		if n := len(out); n > 0 {
+			last := out[n-1]
+			q = q.Where("(wh.watched_at > ? OR (wh.watched_at = ? AND wh.id > ?))", last.WatchedAt, last.WatchedAt, last.ID)
+		}
+		var page []domain.RecapWatchRow
+		if err := q.Order("wh.watched_at ASC, wh.id ASC").Limit(recapHistoryPage).Scan(&page).Error; err != nil {
+			return nil, errors.Wrap(err, errors.CodeInternal, "failed to load recap history")
+		}
+		out = append(out, page...)
+		if len(page) < recapHistoryPage {
+			return out, nil
+		}
+	}
+}
+
+// ListStatuses returns the user's current list status per anime for the
+// given anime IDs — the completion-rate / dropped-count input.
+func (r *RecapRepository) ListStatuses(ctx context.Context, userID string, animeIDs []string) (map[string]string, error) {
+	out := make(map[string]string, len(animeIDs))
+	if len(animeIDs) == 0 {
+		return out, nil
+	}
+	var rows []domain.AnimeStatusEntry
+	err := r.db.WithContext(ctx).
+		Model(&domain.AnimeListEntry{}).
+		Select("anime_id, status").
+		Where("user_id = ? AND anime_id IN ?", userID, animeIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to load recap list statuses")
+	}
+	for _, row := range rows {
+		out[row.AnimeID] = row.Status
+	}
+	return out, nil
+}
+
+// Genres returns every (anime, genre) pair for the given anime IDs.
+func (r *RecapRepository) Genres(ctx context.Context, animeIDs []string) ([]domain.RecapAttribute, error) {
+	return loadAnimeAttributes(ctx, r.db, "anime_genres", "genre_id", "genres", animeIDs)
+}
+
+// Studios returns every (anime, studio) pair for the given anime IDs.
+func (r *RecapRepository) Studios(ctx context.Context, animeIDs []string) ([]domain.RecapAttribute, error) {
+	return loadAnimeAttributes(ctx, r.db, "anime_studios", "studio_id", "studios", animeIDs)
+}
+
+// loadAnimeAttributes reads the (anime, attribute) pairs of one catalog
+// many2many (anime_genres → genres, anime_studios → studios) for animeIDs.
+// Shared by the recap builder and the personal stats API.
+func loadAnimeAttributes(ctx context.Context, db *gorm.DB, joinTable, fk, table string, animeIDs []string) ([]domain.RecapAttribute, error) {
+	if len(animeIDs) == 0 {
+		return nil, nil
+	}
+	var rows []domain.RecapAttribute
+	err := db.WithContext(ctx).
+		Table(joinTable+" j").
+		Select("j.anime_id, t.id, t.name").
+		Joins("JOIN "+table+" t ON t.id = j."+fk).
+		Where("j.anime_id IN ?", animeIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to load anime "+table)
+	}
+	return rows, nil
+}
+
+// Seiyuu returns the voice actors of each anime's MAIN characters. The cast
+// lives inline as JSON on characters.seyu (catalog owner directive — no seiyu
+// table); Shikimori lists the Japanese seiyu first, so only the first entry
+// per character is credited (later entries are localized dub actors).
+func (r *RecapRepository) Seiyuu(ctx context.Context, animeIDs []string) ([]domain.RecapAttribute, error) {
+	if len(animeIDs) == 0 {
+		return nil, nil
+	}
+	var rows []struct {
+		AnimeID string `gorm:"column:anime_id"`
+		Seyu    string `gorm:"column:seyu"`
+	}
+	err := r.db.WithContext(ctx).
+		Table("anime_characters ac").
+		Select("ac.anime_id, COALESCE(c.seyu, '') AS seyu").
+		Joins("JOIN characters c ON c.id = ac.character_id").
+		Where("ac.anime_id IN ? AND ac.role = ?", animeIDs, "main").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to load recap seiyuu")
+	}
+	out := make([]domain.RecapAttribute, 0, len(rows))
+	for _, row := range rows {
+		if row.Seyu == "" {
+			continue
+		}
+		var cast []struct {
+			ShikimoriID string `json:"shikimori_id"`
+			Name        string `json:"name"`
+		}
+		if err := json.Unmarshal([]byte(row.Seyu), &cast); err != nil || len(cast) == 0 || cast[0].Name == "" {
+			continue
+		}
+		out = append(out, domain.RecapAttribute{AnimeID: row.AnimeID, ID: cast[0].ShikimoriID, Name: cast[0].Name})
+	}
+	return out, nil
+}
+
+// InstanceTotals returns per-user watch seconds + episode counts for every
+// user with watch_history in [from, to) — the percentile population.
+func (r *RecapRepository) InstanceTotals(ctx context.Context, from, to time.Time) ([]domain.RecapUserTotals, error) {
+	var rows []domain.RecapUserTotals
+	err := r.db.WithContext(ctx).
+		Table("watch_history").
+		Select("user_id, COALESCE(SUM(duration_watched), 0) AS seconds, COUNT(*) AS episodes").
+		Where("watched_at >= ? AND watched_at < ?", from, to).
+		Group("user_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to load recap instance totals")
+	}
+	return rows, nil
+}
+
+// UserTimezone returns the user's profile IANA timezone (see
+// fetchUserTimezone).
+func (r *RecapRepository) UserTimezone(ctx context.Context, userID string) string {
+	return fetchUserTimezone(ctx, r.db, userID)
+}
+
+// fetchUserTimezone returns the user's profile IANA timezone (auth-owned
+// users.timezone). Best-effort like fetchActivityVisibility: any error or an
+// unset value degrades to "" and the caller buckets in UTC.
+func fetchUserTimezone(ctx context.Context, db *gorm.DB, userID string) string {
+	var tz string
+	err := db.WithContext(ctx).
+		Table("users").
+		Select("COALESCE(timezone, '')").
+		Where("id = ?", userID).
+		Scan(&tz).Error
+	if err != nil {
+		return ""
+	}
+	return tz
+}
+
+// Save upserts a recap row. A regeneration replaces Data + GeneratedAt but
+// never touches ShareToken, so a published link survives the nightly sweep.
+func (r *RecapRepository) Save(ctx context.Context, recap *domain.UserRecap) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "year"}},
+			DoUpdates: clause.AssignmentColumns([]string{"data", "generated_at"}),
+		}).
+		Omit("share_token").
+		Create(recap).Error
+	if err != nil {
+		return errors.Wrap(err, errors.CodeInternal, "failed to save recap")
+	}
+	return nil
+}
+
+// Get returns the stored recap for (user, year), or nil when none exists.
+func (r *RecapRepository) Get(ctx context.Context, userID string, year int) (*domain.UserRecap, error) {
+	var recap domain.UserRecap
+	err := r.db.WithContext(ctx).Where("user_id = ? AND year = ?", userID, year).First(&recap).Error
+	if stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to load recap")
+	}
+	return &recap, nil
+}
+
+// GetByShareToken resolves a public share link, or nil when the token is
+// unknown or was revoked.
+func (r *RecapRepository) GetByShareToken(ctx context.Context, token string) (*domain.UserRecap, error) {
+	var recap domain.UserRecap
+	err := r.db.WithContext(ctx).Where("share_token = ?", token).First(&recap).Error
+	if stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to load shared recap")
+	}
+	return &recap, nil
+}
+
+// SetShareToken publishes (token != nil) or revokes (token == nil) a recap.
+func (r *RecapRepository) SetShareToken(ctx context.Context, userID string, year int, token *string) error {
+	err := r.db.WithContext(ctx).
+		Model(&domain.UserRecap{}).
+		Where("user_id = ? AND year = ?", userID, year).
+		Update("share_token", token).Error
+	if err != nil {
+		return errors.Wrap(err, errors.CodeInternal, "failed to update recap share token")
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupRecapTestDB hand-rolls the tables RecapRepository touches (same
+// raw-SQL convention as setupCompatTestDB — the production GORM tags carry
+// Postgres-only defaults SQLite refuses to parse).
+func setupRecapTestDB(t *testing.T) (*RecapRepository, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err, "open in-memory sqlite")
+
+	stmts := []string{
+		`CREATE TABLE watch_history (
+			id TEXT PRIMARY KEY,
+			user_id TEXT,
+			anime_id TEXT,
+			episode_number INTEGER,
+			duration_watched INTEGER DEFAULT 0,
+			watched_at DATETIME
+		)`,
+		`CREATE TABLE animes (
+			id TEXT PRIMARY KEY,
+			name TEXT,
+			name_ru TEXT,
+			poster_url TEXT
+		)`,
+		`CREATE TABLE anime_characters (
+			anime_id TEXT,
+			character_id TEXT,
+			role TEXT
+		)`,
+		`CREATE TABLE characters (
+			id TEXT PRIMARY KEY,
+			seyu TEXT
+		)`,
+		`CREATE TABLE user_recaps (
+			user_id TEXT,
+			year INTEGER,
+			data TEXT NOT NULL,
+			share_token TEXT UNIQUE,
+			generated_at DATETIME NOT NULL,
+			PRIMARY KEY (user_id, year)
+		)`,
+	}
+	for _, s := range stmts {
+		require.NoError(t, db.Exec(s).Error)
+	}
+	return NewRecapRepository(db), db
+}
+
+func TestRecapRepository_YearHistoryAndInstanceTotals(t *testing.T) {
+	r, db := setupRecapTestDB(t)
+	ctx := context.Background()
+	require.NoError(t, db.Exec(`INSERT INTO animes (id, name) VALUES ('a1', 'Frieren')`).Error)
+	rows := []struct {
+		id, user string
+		secs     int
+		at       time.Time
+	}{
+		{"h1", "u1", 1400, time.Date(2026, 2, 1, 20, 0, 0, 0, time.UTC)},
+		{"h2", "u1", 1400, time.Date(2026, 2, 1, 20, 30, 0, 0, time.UTC)},
+		{"h3", "u2", 600, time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)},
+		{"h4", "u1", 1400, time.Date(2025, 5, 1, 20, 0, 0, 0, time.UTC)}, // previous year
+	}
+	for _, row := range rows {
+		require.NoError(t, db.Exec(
+			`INSERT INTO watch_history (id, user_id, anime_id, episode_number, duration_watched, watched_at) VALUES (?, ?, 'a1', 1, ?, ?)`,
+			row.id, row.user, row.secs, row.at).Error)
+	}
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+
+	hist, err := r.YearHistory(ctx, "u1", from, to)
+	require.NoError(t, err)
+	require.Len(t, hist, 2)
+	assert.Equal(t, "Frieren", hist[0].Name)
+	assert.True(t, hist[0].WatchedAt.Before(hist[1].WatchedAt), "oldest first")
+
+	totals, err := r.InstanceTotals(ctx, from, to)
+	require.NoError(t, err)
+	byUser := map[string]domain.RecapUserTotals{}
+	for _, tt := range totals {
+		byUser[tt.UserID] = tt
+	}
+	assert.Equal(t, int64(2800), byUser["u1"].Seconds)
+	assert.Equal(t, int64(2), byUser["u1"].Episodes)
+	assert.Equal(t, int64(1), byUser["u2"].Episodes)
+}
+
+// TestRecapRepository_YearHistoryPagesThroughTheYear — a year with more rows
+// than one page, many sharing a timestamp, loads in full and in order; the
+// December rows are not cut off.
+func TestRecapRepository_YearHistoryPagesThroughTheYear(t *testing.T) {
+	r, db := setupRecapTestDB(t)
+	total := recapHistoryPage + 3
+	require.NoError(t, db.Exec(`WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < ?)
+		INSERT INTO watch_history (id, user_id, anime_id, episode_number, duration_watched, watched_at)
+		SELECT printf('p%05d', i), 'u1', 'a1', 1, 60, printf('2026-12-31 %02d:00:00+00:00', 18 + i % 3) FROM n`, total).Error)
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	hist, err := r.YearHistory(context.Background(), "u1", from, from.AddDate(1, 0, 0))
+	require.NoError(t, err)
+	require.Len(t, hist, total)
+	seen := make(map[string]bool, total)
+	for i, row := range hist {
+		require.False(t, seen[row.ID], "row %s returned twice", row.ID)
+		seen[row.ID] = true
+		if i > 0 {
+			require.False(t, row.WatchedAt.Before(hist[i-1].WatchedAt), "oldest first")
+		}
+	}
+}
+
+func TestRecapRepository_SeiyuuTakesFirstMainCast(t *testing.T) {
+	r, db := setupRecapTestDB(t)
+	require.NoError(t, db.Exec(`INSERT INTO characters (id, seyu) VALUES
+		('c1', '[{"shikimori_id":"1","name":"Atsumi Tanezaki"},{"shikimori_id":"9","name":"Dub Actor"}]'),
+		('c2', '[{"shikimori_id":"2","name":"Side Cast"}]'),
+		('c3', '')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO anime_characters (anime_id, character_id, role) VALUES
+		('a1', 'c1', 'main'), ('a1', 'c2', 'supporting'), ('a1', 'c3', 'main')`).Error)
+
+	got, err := r.Seiyuu(context.Background(), []string{"a1"})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, domain.RecapAttribute{AnimeID: "a1", ID: "1", Name: "Atsumi Tanezaki"}, got[0])
+}
+
+// TestRecapRepository_SavePreservesShareToken — the nightly regeneration
+// must not revoke a link the owner already published.
+func TestRecapRepository_SavePreservesShareToken(t *testing.T) {
+	r, _ := setupRecapTestDB(t)
+	ctx := context.Background()
+
+	first := &domain.UserRecap{UserID: "u1", Year: 2026, Data: domain.RecapData{Year: 2026, TotalEpisodes: 1}, GeneratedAt: time.Now()}
+	require.NoError(t, r.Save(ctx, first))
+	token := "abc123"
+	require.NoError(t, r.SetShareToken(ctx, "u1", 2026, &token))
+
+	second := &domain.UserRecap{UserID: "u1", Year: 2026, Data: domain.RecapData{Year: 2026, TotalEpisodes: 7}, GeneratedAt: time.Now()}
+	require.NoError(t, r.Save(ctx, second))
+
+	got, err := r.GetByShareToken(ctx, token)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, 7, got.Data.TotalEpisodes)
+
+	require.NoError(t, r.SetShareToken(ctx, "u1", 2026, nil))
+	got, err = r.GetByShareToken(ctx, token)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
@@ -0,0 +1,71 @@
+package repo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ScrobbleRepository resolves external identifiers (MAL / AniList /
+// Shikimori IDs, series titles) to catalog anime IDs on the shared DB's
+// catalog-owned animes table.
+type ScrobbleRepository struct{ db *gorm.DB }
+
+func NewScrobbleRepository(db *gorm.DB) *ScrobbleRepository {
+	return &ScrobbleRepository{db: db}
+}
+
+// scrobbleIDColumns whitelists the columns FindAnimeByExternalID may match on.
+var scrobbleIDColumns = map[string]bool{
+	"id":           true,
+	"mal_id":       true,
+	"anilist_id":   true,
+	"shikimori_id": true,
+}
+
+// FindAnimeByExternalID returns the id of the live anime whose column equals
+// value, or "" when none matches. id is a uuid column, so a client-supplied
+// value that is not a UUID matches nothing instead of failing the query.
+func (r *ScrobbleRepository) FindAnimeByExternalID(ctx context.Context, column, value string) (string, error) {
+	if !scrobbleIDColumns[column] || value == "" {
+		return "", nil
+	}
+	if column == "id" && uuid.Validate(value) != nil {
+		return "", nil
+	}
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Table("animes").
+		Select("id").
+		Where(column+" = ? AND deleted_at IS NULL", value).
+		Limit(1).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return "", err
+	}
+	return ids[0], nil
+}
+
+// FindAnimeByTitle returns the id of the best-scored live anime whose name,
+// English, Japanese or Russian title equals title case-insensitively, or ""
+// when none matches.
+func (r *ScrobbleRepository) FindAnimeByTitle(ctx context.Context, title string) (string, error) {
+	t := strings.ToLower(strings.TrimSpace(title))
+	if t == "" {
+		return "", nil
+	}
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Table("animes").
+		Select("id").
+		Where("deleted_at IS NULL AND (LOWER(name) = ? OR LOWER(name_en) = ? OR LOWER(name_jp) = ? OR LOWER(name_ru) = ?)", t, t, t, t).
+		Order("score DESC").
+		Limit(1).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return "", err
+	}
+	return ids[0], nil
+}
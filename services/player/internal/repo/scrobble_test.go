@@ -0,0 +1,41 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestScrobbleRepository_FindAnimeByExternalID(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err, "open in-memory sqlite")
+	for _, s := range []string{
+		`CREATE TABLE animes (id TEXT PRIMARY KEY, mal_id TEXT, anilist_id TEXT, shikimori_id TEXT, deleted_at DATETIME)`,
+		`INSERT INTO animes (id, mal_id) VALUES ('5f0c7a52-9d3e-4b7a-8a61-2f4c1e9b7d10', '52991'), ('not-a-uuid', '1')`,
+	} {
+		require.NoError(t, db.Exec(s).Error)
+	}
+	r := NewScrobbleRepository(db)
+	ctx := context.Background()
+
+	id, err := r.FindAnimeByExternalID(ctx, "id", "5f0c7a52-9d3e-4b7a-8a61-2f4c1e9b7d10")
+	require.NoError(t, err)
+	assert.Equal(t, "5f0c7a52-9d3e-4b7a-8a61-2f4c1e9b7d10", id)
+
+	// A client-supplied id that is not a UUID never reaches the uuid column.
+	id, err = r.FindAnimeByExternalID(ctx, "id", "not-a-uuid")
+	require.NoError(t, err)
+	assert.Empty(t, id)
+
+	id, err = r.FindAnimeByExternalID(ctx, "mal_id", "52991")
+	require.NoError(t, err)
+	assert.Equal(t, "5f0c7a52-9d3e-4b7a-8a61-2f4c1e9b7d10", id)
+
+	id, err = r.FindAnimeByExternalID(ctx, "name; DROP TABLE animes", "x")
+	require.NoError(t, err)
+	assert.Empty(t, id, "columns outside the whitelist match nothing")
+}
@@ -0,0 +1,330 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// statsFoldBatch bounds how many watch_history rows one fold step reads. A
+// first-ever fold of a long-lived account runs a few batches; the steady
+// state is a handful of rows per request.
+const statsFoldBatch = 5000
+
+// StatsRepository maintains the personal-stats aggregate tables
+// (user_stats_daily, user_stats_hourly, user_stats_watermarks) and reads the
+// catalog + anime_list inputs of the stats breakdowns. Plain SQL — runs on
+// Postgres (prod) and SQLite (repo tests).
+type StatsRepository struct{ db *gorm.DB }
+
+func NewStatsRepository(db *gorm.DB) *StatsRepository {
+	return &StatsRepository{db: db}
+}
+
+// ActivityVisibility returns the user's activity_visibility setting (see
+// fetchActivityVisibility).
+func (r *StatsRepository) ActivityVisibility(ctx context.Context, userID string) string {
+	return fetchActivityVisibility(ctx, r.db, userID)
+}
+
+// UserTimezone returns the user's profile timezone, "" when unset.
+func (r *StatsRepository) UserTimezone(ctx context.Context, userID string) string {
+	return fetchUserTimezone(ctx, r.db, userID)
+}
+
+// Refresh folds the user's watch_history rows inserted after the watermark into
+// the aggregates, bucketing in loc. tz is loc's stored name: when it differs
+// from the one the aggregates were built with, they are dropped and rebuilt.
+//
+// Every step commits only if the watermark still holds the value it read
+// (compare-and-set in the same transaction as the increments), so concurrent
+// refreshes of the same user never double count — the loser simply stops.
+func (r *StatsRepository) Refresh(ctx context.Context, userID string, loc *time.Location, tz string) error {
+	db := r.db.WithContext(ctx)
+	seed := domain.UserStatsWatermark{UserID: userID, Timezone: tz, UpdatedAt: time.Now()}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&seed).Error; err != nil {
+		return errors.Wrap(err, errors.CodeInternal, "failed to seed stats watermark")
+	}
+
+	for {
+		var wm domain.UserStatsWatermark
+		if err := db.Where("user_id = ?", userID).First(&wm).Error; err != nil {
+			return errors.Wrap(err, errors.CodeInternal, "failed to load stats watermark")
+		}
+
+		if wm.Timezone != tz {
+			reset, err := r.resetAggregates(ctx, wm, tz)
+			if err != nil {
+				return err
+			}
+			if !reset {
+				return nil
+			}
+			continue
+		}
+
+		var rows []domain.StatsWatchRow
+		err := db.Table("watch_history").
+			Select("seq, anime_id, duration_watched, watched_at").
+			Where("user_id = ? AND seq > ?", userID, wm.LastSeq).
+			Order("seq ASC").
+			Limit(statsFoldBatch).
+			Scan(&rows).Error
+		if err != nil {
+			return errors.Wrap(err, errors.CodeInternal, "failed to load stats delta")
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		folded, err := r.fold(ctx, wm, userID, rows, loc)
+		if err != nil {
+			return err
+		}
+		if !folded || len(rows) < statsFoldBatch {
+			return nil
+		}
+	}
+}
+
+// resetAggregates drops the user's aggregates and rewinds the watermark so
+// the next step rebuilds them in the new timezone. False when another
+// refresh moved the watermark first.
+func (r *StatsRepository) resetAggregates(ctx context.Context, wm domain.UserStatsWatermark, tz string) (bool, error) {
+	reset := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.UserStatsWatermark{}).
+			Where("user_id = ? AND last_seq = ? AND timezone = ?", wm.UserID, wm.LastSeq, wm.Timezone).
+			Updates(map[string]interface{}{"last_seq": 0, "timezone": tz, "updated_at": time.Now()})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Where("user_id = ?", wm.UserID).Delete(&domain.UserStatsDaily{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", wm.UserID).Delete(&domain.UserStatsHourly{}).Error; err != nil {
+			return err
+		}
+		reset = true
+		return nil
+	})
+	if err != nil {
+		return false, errors.Wrap(err, errors.CodeInternal, "failed to reset stats aggregates")
+	}
+	return reset, nil
+}
+
+// fold adds one batch of rows to the aggregates and advances the watermark.
+func (r *StatsRepository) fold(ctx context.Context, wm domain.UserStatsWatermark, userID string, rows []domain.StatsWatchRow, loc *time.Location) (bool, error) {
+	type dayKey struct{ day, anime string }
+	type hourKey struct {
+		weekday, hour int
+		anime         string
+	}
+	daily := map[dayKey]*domain.UserStatsDaily{}
+	hourly := map[hourKey]*domain.UserStatsHourly{}
+	for _, row := range rows {
+		local := row.WatchedAt.In(loc)
+		dk := dayKey{local.Format("2006-01-02"), row.AnimeID}
+		d, ok := daily[dk]
+		if !ok {
+			d = &domain.UserStatsDaily{UserID: userID, Day: dk.day, AnimeID: row.AnimeID}
+			daily[dk] = d
+		}
+		d.Seconds += int64(row.DurationWatched)
+		d.Episodes++
+
+		hk := hourKey{int(local.Weekday()), local.Hour(), row.AnimeID}
+		h, ok := hourly[hk]
+		if !ok {
+			h = &domain.UserStatsHourly{UserID: userID, Weekday: hk.weekday, Hour: hk.hour, AnimeID: row.AnimeID}
+			hourly[hk] = h
+		}
+		h.Seconds += int64(row.DurationWatched)
+		h.Episodes++
+	}
+	dailyRows := make([]*domain.UserStatsDaily, 0, len(daily))
+	for _, d := range daily {
+		dailyRows = append(dailyRows, d)
+	}
+	hourlyRows := make([]*domain.UserStatsHourly, 0, len(hourly))
+	for _, h := range hourly {
+		hourlyRows = append(hourlyRows, h)
+	}
+
+	folded := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.UserStatsWatermark{}).
+			Where("user_id = ? AND last_seq = ? AND timezone = ?", userID, wm.LastSeq, wm.Timezone).
+			Updates(map[string]interface{}{"last_seq": rows[len(rows)-1].Seq, "updated_at": time.Now()})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "day"}, {Name: "anime_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"seconds":  gorm.Expr("user_stats_daily.seconds + excluded.seconds"),
+				"episodes": gorm.Expr("user_stats_daily.episodes + excluded.episodes"),
+			}),
+		}).CreateInBatches(dailyRows, 500).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "weekday"}, {Name: "hour"}, {Name: "anime_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"seconds":  gorm.Expr("user_stats_hourly.seconds + excluded.seconds"),
+				"episodes": gorm.Expr("user_stats_hourly.episodes + excluded.episodes"),
+			}),
+		}).CreateInBatches(hourlyRows, 500).Error
+		if err != nil {
+			return err
+		}
+		folded = true
+		return nil
+	})
+	if err != nil {
+		return false, errors.Wrap(err, errors.CodeInternal, "failed to fold stats delta")
+	}
+	return folded, nil
+}
+
+// aggregate starts a query over one aggregate table for the user, with the
+// 18+ titles filtered out when excludeHentai is set.
+func (r *StatsRepository) aggregate(ctx context.Context, table, userID string, excludeHentai bool) *gorm.DB {
+	q := r.db.WithContext(ctx).Table(table).Where(table+".user_id = ?", userID)
+	if excludeHentai {
+		q = q.Where("NOT " + fmt.Sprintf(hentaiAnimeExistsFmt, table+".anime_id"))
+	}
+	return q
+}
+
+// Daily returns the user's daily buckets for local days >= sinceDay
+// (YYYY-MM-DD); an empty sinceDay returns every day.
+func (r *StatsRepository) Daily(ctx context.Context, userID, sinceDay string, excludeHentai bool) ([]domain.UserStatsDaily, error) {
+	q := r.aggregate(ctx, "user_stats_daily", userID, excludeHentai).
+		Select("user_stats_daily.day, user_stats_daily.anime_id, user_stats_daily.seconds, user_stats_daily.episodes")
+	if sinceDay != "" {
+		q = q.Where("user_stats_daily.day >= ?", sinceDay)
+	}
+	var rows []domain.UserStatsDaily
+	if err := q.Order("user_stats_daily.day ASC").Scan(&rows).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to load daily stats")
+	}
+	return rows, nil
+}
+
+// AnimeTotals returns the user's all-time watch time per anime, reusing the
+// daily row shape with an empty Day.
+func (r *StatsRepository) AnimeTotals(ctx context.Context, userID string, excludeHentai bool) ([]domain.UserStatsDaily, error) {
+	var rows []domain.UserStatsDaily
+	err := r.aggregate(ctx, "user_stats_daily", userID, excludeHentai).
+		Select("user_stats_daily.anime_id, SUM(user_stats_daily.seconds) AS seconds, SUM(user_stats_daily.episodes) AS episodes").
+		Group("user_stats_daily.anime_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to load per-anime stats")
+	}
+	return rows, nil
+}
+
+// Hourly returns the user's (weekday, hour) buckets summed over anime.
+func (r *StatsRepository) Hourly(ctx context.Context, userID string, excludeHentai bool) ([]domain.UserStatsHourly, error) {
+	var rows []domain.UserStatsHourly
+	err := r.aggregate(ctx, "user_stats_hourly", userID, excludeHentai).
+		Select("user_stats_hourly.weekday, user_stats_hourly.hour, SUM(user_stats_hourly.seconds) AS seconds, SUM(user_stats_hourly.episodes) AS episodes").
+		Group("user_stats_hourly.weekday, user_stats_hourly.hour").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to load hourly stats")
+	}
+	return rows, nil
+}
+
+// ListEntries returns the user's scored or currently-watching list entries.
+func (r *StatsRepository) ListEntries(ctx context.Context, userID string, excludeHentai bool) ([]domain.StatsListEntry, error) {
+	q := r.db.WithContext(ctx).
+		Model(&domain.AnimeListEntry{}).
+		Select("anime_list.anime_id, anime_list.status, anime_list.score, anime_list.episodes").
+		Where("anime_list.user_id = ? AND (anime_list.score > 0 OR anime_list.status = ?)", userID, "watching")
+	if excludeHentai {
+		q = q.Where("NOT " + fmt.Sprintf(hentaiAnimeExistsFmt, "anime_list.anime_id"))
+	}
+	var rows []domain.StatsListEntry
+	if err := q.Scan(&rows).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to load stats list entries")
+	}
+	return rows, nil
+}
+
+// Animes returns the catalog slice of the given anime IDs.
+func (r *StatsRepository) Animes(ctx context.Context, animeIDs []string) ([]domain.StatsAnime, error) {
+	if len(animeIDs) == 0 {
+		return nil, nil
+	}
+	var rows []domain.StatsAnime
+	err := r.db.WithContext(ctx).
+		Table("animes").
+		Select("id, COALESCE(name, '') AS name, COALESCE(name_ru, '') AS name_ru, COALESCE(poster_url, '') AS poster_url, COALESCE(year, 0) AS year, COALESCE(season, '') AS season, COALESCE(episodes_count, 0) AS episodes_count, COALESCE(episodes_aired, 0) AS episodes_aired").
+		Where("id IN ?", animeIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to load stats anime")
+	}
+	return rows, nil
+}
+
+// Genres returns every (anime, genre) pair for the given anime IDs.
+func (r *StatsRepository) Genres(ctx context.Context, animeIDs []string) ([]domain.RecapAttribute, error) {
+	return loadAnimeAttributes(ctx, r.db, "anime_genres", "genre_id", "genres", animeIDs)
+}
+
+// Studios returns every (anime, studio) pair for the given anime IDs.
+func (r *StatsRepository) Studios(ctx context.Context, animeIDs []string) ([]domain.RecapAttribute, error) {
+	return loadAnimeAttributes(ctx, r.db, "anime_studios", "studio_id", "studios", animeIDs)
+}
+
+// CommunityScores returns the instance-wide mean score of each given anime.
+func (r *StatsRepository) CommunityScores(ctx context.Context, animeIDs []string) ([]domain.StatsCommunityScore, error) {
+	if len(animeIDs) == 0 {
+		return nil, nil
+	}
+	var rows []domain.StatsCommunityScore
+	err := r.db.WithContext(ctx).
+		Model(&domain.AnimeListEntry{}).
+		Select("anime_id, AVG(score) AS avg_score").
+		Where("anime_id IN ? AND score > 0", animeIDs).
+		Group("anime_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to load community scores")
+	}
+	return rows, nil
+}
+
+// CommunityScoreCounts returns how many list entries instance-wide carry each
+// score 1..10.
+func (r *StatsRepository) CommunityScoreCounts(ctx context.Context) ([]domain.StatsScoreCount, error) {
+	var rows []domain.StatsScoreCount
+	err := r.db.WithContext(ctx).
+		Model(&domain.AnimeListEntry{}).
+		Select("score, COUNT(*) AS count").
+		Where("score > 0").
+		Group("score").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to load community score distribution")
+	}
+	return rows, nil
+}
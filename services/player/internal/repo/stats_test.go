@@ -0,0 +1,149 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupStatsTestDB hand-rolls the tables StatsRepository touches (raw SQL —
+// see setupRecapTestDB).
+func setupStatsTestDB(t *testing.T) (*StatsRepository, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err, "open in-memory sqlite")
+
+	stmts := []string{
+		`CREATE TABLE watch_history (
+			seq INTEGER PRIMARY KEY AUTOINCREMENT,
+			id TEXT UNIQUE,
+			user_id TEXT,
+			anime_id TEXT,
+			episode_number INTEGER,
+			duration_watched INTEGER DEFAULT 0,
+			watched_at DATETIME
+		)`,
+		`CREATE TABLE animes (id TEXT PRIMARY KEY, name TEXT, rating TEXT)`,
+		`CREATE TABLE genres (id TEXT PRIMARY KEY, name TEXT)`,
+		`CREATE TABLE anime_genres (anime_id TEXT, genre_id TEXT)`,
+		`CREATE TABLE user_stats_daily (
+			user_id TEXT, day TEXT, anime_id TEXT,
+			seconds INTEGER NOT NULL DEFAULT 0, episodes INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (user_id, day, anime_id)
+		)`,
+		`CREATE TABLE user_stats_hourly (
+			user_id TEXT, weekday INTEGER, hour INTEGER, anime_id TEXT,
+			seconds INTEGER NOT NULL DEFAULT 0, episodes INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (user_id, weekday, hour, anime_id)
+		)`,
+		`CREATE TABLE user_stats_watermarks (
+			user_id TEXT PRIMARY KEY,
+			last_seq INTEGER NOT NULL DEFAULT 0,
+			timezone TEXT NOT NULL DEFAULT '',
+			updated_at DATETIME
+		)`,
+	}
+	for _, s := range stmts {
+		require.NoError(t, db.Exec(s).Error)
+	}
+	return NewStatsRepository(db), db
+}
+
+func insertStatsHistory(t *testing.T, db *gorm.DB, id, anime string, secs int, at time.Time) {
+	t.Helper()
+	require.NoError(t, db.Exec(
+		`INSERT INTO watch_history (id, user_id, anime_id, episode_number, duration_watched, watched_at) VALUES (?, 'u1', ?, 1, ?, ?)`,
+		id, anime, secs, at).Error)
+}
+
+// TestStatsRepository_RefreshIsIncremental — a second refresh folds only the
+// new rows; re-running with nothing new changes nothing.
+func TestStatsRepository_RefreshIsIncremental(t *testing.T) {
+	r, db := setupStatsTestDB(t)
+	ctx := context.Background()
+	insertStatsHistory(t, db, "h1", "a1", 1200, time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC))
+	insertStatsHistory(t, db, "h2", "a1", 1200, time.Date(2026, 3, 1, 20, 30, 0, 0, time.UTC))
+	require.NoError(t, r.Refresh(ctx, "u1", time.UTC, ""))
+
+	insertStatsHistory(t, db, "h3", "a1", 600, time.Date(2026, 3, 1, 21, 0, 0, 0, time.UTC))
+	require.NoError(t, r.Refresh(ctx, "u1", time.UTC, ""))
+	require.NoError(t, r.Refresh(ctx, "u1", time.UTC, ""))
+
+	daily, err := r.Daily(ctx, "u1", "", false)
+	require.NoError(t, err)
+	require.Len(t, daily, 1)
+	assert.Equal(t, "2026-03-01", daily[0].Day)
+	assert.Equal(t, int64(3000), daily[0].Seconds)
+	assert.Equal(t, 3, daily[0].Episodes)
+
+	hourly, err := r.Hourly(ctx, "u1", false)
+	require.NoError(t, err)
+	byHour := map[int]int{}
+	for _, h := range hourly {
+		assert.Equal(t, int(time.Sunday), h.Weekday)
+		byHour[h.Hour] = h.Episodes
+	}
+	assert.Equal(t, map[int]int{20: 2, 21: 1}, byHour)
+}
+
+// TestStatsRepository_RefreshFoldsBackdatedRows — a row inserted after a fold
+// but stamped earlier than everything already folded is still counted: the
+// watermark follows insert order, not watched_at.
+func TestStatsRepository_RefreshFoldsBackdatedRows(t *testing.T) {
+	r, db := setupStatsTestDB(t)
+	ctx := context.Background()
+	insertStatsHistory(t, db, "h1", "a1", 1200, time.Date(2026, 3, 2, 20, 0, 0, 0, time.UTC))
+	require.NoError(t, r.Refresh(ctx, "u1", time.UTC, ""))
+
+	insertStatsHistory(t, db, "h2", "a1", 600, time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, r.Refresh(ctx, "u1", time.UTC, ""))
+
+	daily, err := r.Daily(ctx, "u1", "", false)
+	require.NoError(t, err)
+	require.Len(t, daily, 2)
+	assert.Equal(t, "2026-03-01", daily[0].Day)
+	assert.Equal(t, int64(600), daily[0].Seconds)
+	assert.Equal(t, "2026-03-02", daily[1].Day)
+	assert.Equal(t, int64(1200), daily[1].Seconds)
+}
+
+// TestStatsRepository_TimezoneChangeRebuilds — the aggregates are rebuilt in
+// the new zone rather than mixing two bucketings.
+func TestStatsRepository_TimezoneChangeRebuilds(t *testing.T) {
+	r, db := setupStatsTestDB(t)
+	ctx := context.Background()
+	insertStatsHistory(t, db, "h1", "a1", 600, time.Date(2026, 3, 1, 22, 30, 0, 0, time.UTC))
+	require.NoError(t, r.Refresh(ctx, "u1", time.UTC, ""))
+
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	require.NoError(t, r.Refresh(ctx, "u1", moscow, "Europe/Moscow"))
+
+	daily, err := r.Daily(ctx, "u1", "", false)
+	require.NoError(t, err)
+	require.Len(t, daily, 1)
+	assert.Equal(t, "2026-03-02", daily[0].Day, "22:30 UTC is past midnight in Moscow")
+	assert.Equal(t, 1, daily[0].Episodes)
+}
+
+func TestStatsRepository_ExcludesHentai(t *testing.T) {
+	r, db := setupStatsTestDB(t)
+	ctx := context.Background()
+	require.NoError(t, db.Exec(`INSERT INTO animes (id, name, rating) VALUES ('a1', 'Safe', 'pg_13'), ('a2', 'Adult', 'rx')`).Error)
+	insertStatsHistory(t, db, "h1", "a1", 600, time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC))
+	insertStatsHistory(t, db, "h2", "a2", 600, time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC))
+	require.NoError(t, r.Refresh(ctx, "u1", time.UTC, ""))
+
+	all, err := r.AnimeTotals(ctx, "u1", false)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+	safe, err := r.AnimeTotals(ctx, "u1", true)
+	require.NoError(t, err)
+	require.Len(t, safe, 1)
+	assert.Equal(t, "a1", safe[0].AnimeID)
+}
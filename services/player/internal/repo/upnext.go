@@ -0,0 +1,447 @@
+package repo
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UpNextRepository persists the per-user Up Next queue (up_next_items), its
+// version/rules row (up_next_states) and the episodes the user dismissed from
+// it (up_next_dismissals). Every mutation runs in one
+// transaction that loads the queue, edits it in memory, writes back only the
+// rows that changed and bumps the version — so positions stay dense and a
+// device polling ?since=<version> sees each change exactly once. Plain SQL
+// throughout so it runs on Postgres (prod) and SQLite (repo tests).
+type UpNextRepository struct{ db *gorm.DB }
+
+func NewUpNextRepository(db *gorm.DB) *UpNextRepository {
+	return &UpNextRepository{db: db}
+}
+
+// ErrUpNextStale is returned by Reorder when the queue changed since the
+// version the client reordered from.
+var ErrUpNextStale = errors.New(errors.CodeConflict, "up next queue changed on another device; reload and retry")
+
+// GetState returns the user's queue version and rules; a user who never
+// touched the queue gets version 0 with the defaults.
+func (r *UpNextRepository) GetState(ctx context.Context, userID string) (*domain.UpNextState, error) {
+	return loadUpNextState(r.db.WithContext(ctx), userID)
+}
+
+// SaveSettings stores the queue rules and bumps the version.
+func (r *UpNextRepository) SaveSettings(ctx context.Context, userID string, s domain.UpNextSettings) (*domain.UpNextState, error) {
+	var state *domain.UpNextState
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Exec(`
+			INSERT INTO up_next_states (user_id, version, auto_enqueue, keep_airing, updated_at)
+			VALUES (?, 1, ?, ?, ?)
+			ON CONFLICT (user_id) DO UPDATE SET
+				version = up_next_states.version + 1,
+				auto_enqueue = excluded.auto_enqueue,
+				keep_airing = excluded.keep_airing,
+				updated_at = excluded.updated_at`,
+			userID, s.AutoEnqueue, s.KeepAiring, now).Error; err != nil {
+			return err
+		}
+		var err error
+		state, err = loadUpNextState(tx, userID)
+		return err
+	})
+	return state, err
+}
+
+// List returns the queue in order with the anime projection and the saved
+// resume position of each episode. Items whose anime was deleted from the
+// catalog are skipped.
+func (r *UpNextRepository) List(ctx context.Context, userID string) ([]domain.UpNextEntry, error) {
+	type scanRow struct {
+		AnimeID       string
+		EpisodeNumber int
+		Position      int
+		Source        string
+		Name          string
+		NameRU        string
+		NameJP        string
+		PosterURL     string
+		EpisodesCount int
+		EpisodesAired int
+		Progress      int
+		Duration      int
+	}
+	var rows []scanRow
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT q.anime_id, q.episode_number, q.position, q.source,
+			COALESCE(a.name, '') AS name, COALESCE(a.name_ru, '') AS name_ru,
+			COALESCE(a.name_jp, '') AS name_jp, COALESCE(a.poster_url, '') AS poster_url,
+			COALESCE(a.episodes_count, 0) AS episodes_count, COALESCE(a.episodes_aired, 0) AS episodes_aired,
+			COALESCE(wp.progress, 0) AS progress, COALESCE(wp.duration, 0) AS duration
+		FROM up_next_items q
+		JOIN animes a ON a.id = q.anime_id AND a.deleted_at IS NULL
+		LEFT JOIN watch_progress wp
+			ON wp.user_id = q.user_id AND wp.anime_id = q.anime_id AND wp.episode_number = q.episode_number
+		WHERE q.user_id = ?
+		ORDER BY q.position`, userID).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.UpNextEntry, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, domain.UpNextEntry{
+			Anime: domain.AnimeInfo{
+				ID:            row.AnimeID,
+				Name:          row.Name,
+				NameRU:        row.NameRU,
+				NameJP:        row.NameJP,
+				PosterURL:     row.PosterURL,
+				EpisodesCount: row.EpisodesCount,
+				EpisodesAired: row.EpisodesAired,
+			},
+			EpisodeNumber: row.EpisodeNumber,
+			Position:      row.Position,
+			Source:        row.Source,
+			Progress:      row.Progress,
+			Duration:      row.Duration,
+		})
+	}
+	return out, nil
+}
+
+// Add inserts an episode at pos (nil or out of range appends). A queued
+// episode is moved instead of duplicated. Adding an episode the user had
+// dismissed forgets the dismissal.
+func (r *UpNextRepository) Add(ctx context.Context, userID string, key domain.UpNextKey, pos *int, source string) (int64, error) {
+	return r.mutate(ctx, userID, func(tx *gorm.DB, q []domain.UpNextItem) ([]domain.UpNextItem, error) {
+		if err := tx.Where("user_id = ? AND anime_id = ? AND episode_number = ?", userID, key.AnimeID, key.EpisodeNumber).
+			Delete(&domain.UpNextDismissal{}).Error; err != nil {
+			return nil, err
+		}
+		item := domain.UpNextItem{UserID: userID, AnimeID: key.AnimeID, EpisodeNumber: key.EpisodeNumber, Source: source}
+		if i := upNextIndex(q, key); i >= 0 {
+			item = q[i]
+			q = append(q[:i:i], q[i+1:]...)
+		}
+		if len(q) >= domain.UpNextMaxItems {
+			return nil, errors.InvalidInput("up next queue is full")
+		}
+		at := len(q)
+		if pos != nil && *pos >= 0 && *pos < len(q) {
+			at = *pos
+		}
+		q = append(q[:at:at], append([]domain.UpNextItem{item}, q[at:]...)...)
+		return q, nil
+	})
+}
+
+// Remove dequeues one episode and records it as dismissed.
+func (r *UpNextRepository) Remove(ctx context.Context, userID string, key domain.UpNextKey) (int64, error) {
+	return r.mutate(ctx, userID, func(tx *gorm.DB, q []domain.UpNextItem) ([]domain.UpNextItem, error) {
+		i := upNextIndex(q, key)
+		if i < 0 {
+			return nil, errors.NotFound("up next item")
+		}
+		if err := dismissUpNext(tx, q[i:i+1]); err != nil {
+			return nil, err
+		}
+		return append(q[:i:i], q[i+1:]...), nil
+	})
+}
+
+// Clear empties the queue, recording every dequeued episode as dismissed.
+func (r *UpNextRepository) Clear(ctx context.Context, userID string) (int64, error) {
+	return r.mutate(ctx, userID, func(tx *gorm.DB, q []domain.UpNextItem) ([]domain.UpNextItem, error) {
+		return nil, dismissUpNext(tx, q)
+	})
+}
+
+// Reorder applies a drag-reorder. keys must list exactly the queued
+// episodes; baseVersion (when non-zero) must still be current.
+func (r *UpNextRepository) Reorder(ctx context.Context, userID string, baseVersion int64, keys []domain.UpNextKey) (int64, error) {
+	return r.mutate(ctx, userID, func(tx *gorm.DB, q []domain.UpNextItem) ([]domain.UpNextItem, error) {
+		if baseVersion > 0 {
+			state, err := loadUpNextState(tx, userID)
+			if err != nil {
+				return nil, err
+			}
+			if state.Version != baseVersion {
+				return nil, ErrUpNextStale
+			}
+		}
+		if len(keys) != len(q) {
+			return nil, ErrUpNextStale
+		}
+		out := make([]domain.UpNextItem, 0, len(q))
+		seen := make(map[domain.UpNextKey]bool, len(keys))
+		for _, k := range keys {
+			i := upNextIndex(q, k)
+			if i < 0 || seen[k] {
+				return nil, ErrUpNextStale
+			}
+			seen[k] = true
+			out = append(out, q[i])
+		}
+		return out, nil
+	})
+}
+
+// Advance reacts to an episode completion: every queued episode of the anime
+// up to and including episode is dequeued and, when autoEnqueue is set, the
+// next aired episode takes the place of the first dequeued item. A
+// completion outside the queue only enqueues (appending) for anime on the
+// user's watching list. Dismissals of the watched episodes are dropped — the
+// KeepAiring rule never looks behind the watched count. Returns the new
+// version, or 0 when nothing changed.
+func (r *UpNextRepository) Advance(ctx context.Context, userID, animeID string, episode int, autoEnqueue bool) (int64, error) {
+	return r.mutate(ctx, userID, func(tx *gorm.DB, q []domain.UpNextItem) ([]domain.UpNextItem, error) {
+		if err := tx.Where("user_id = ? AND anime_id = ? AND episode_number <= ?", userID, animeID, episode).
+			Delete(&domain.UpNextDismissal{}).Error; err != nil {
+			return nil, err
+		}
+		at := -1
+		kept := q[:0:0]
+		hasLater := false
+		for _, it := range q {
+			if it.AnimeID == animeID && it.EpisodeNumber <= episode {
+				if at < 0 {
+					at = len(kept)
+				}
+				continue
+			}
+			if it.AnimeID == animeID {
+				hasLater = true
+			}
+			kept = append(kept, it)
+		}
+		if !autoEnqueue || hasLater || len(kept) >= domain.UpNextMaxItems {
+			return kept, nil
+		}
+		available, watching, err := upNextAnimeAvailability(tx, userID, animeID)
+		if err != nil {
+			return nil, err
+		}
+		if episode+1 > available || (at < 0 && !watching) {
+			return kept, nil
+		}
+		if at < 0 {
+			at = len(kept)
+		}
+		next := domain.UpNextItem{UserID: userID, AnimeID: animeID, EpisodeNumber: episode + 1, Source: domain.UpNextSourceAuto}
+		return append(kept[:at:at], append([]domain.UpNextItem{next}, kept[at:]...)...), nil
+	})
+}
+
+// FillAiring applies the KeepAiring rule: for every ongoing anime on the
+// user's watching list, the next perEpisode aired-but-unwatched episodes
+// (counted from anime_list.episodes) are appended when missing. Episodes the
+// user dismissed are skipped, so a removed rule episode stays removed while
+// newly aired ones still arrive. Returns the new version, or 0 when nothing
+// changed.
+func (r *UpNextRepository) FillAiring(ctx context.Context, userID string, perAnime int) (int64, error) {
+	if perAnime <= 0 {
+		return 0, nil
+	}
+	return r.mutate(ctx, userID, func(tx *gorm.DB, q []domain.UpNextItem) ([]domain.UpNextItem, error) {
+		var dismissals []domain.UpNextDismissal
+		if err := tx.Where("user_id = ?", userID).Find(&dismissals).Error; err != nil {
+			return nil, err
+		}
+		dismissed := make(map[domain.UpNextKey]bool, len(dismissals))
+		for _, d := range dismissals {
+			dismissed[domain.UpNextKey{AnimeID: d.AnimeID, EpisodeNumber: d.EpisodeNumber}] = true
+		}
+		type airingRow struct {
+			AnimeID   string
+			Watched   int
+			Available int
+		}
+		var rows []airingRow
+		err := tx.Raw(`
+			SELECT al.anime_id, COALESCE(al.episodes, 0) AS watched,
+				CASE WHEN COALESCE(a.episodes_aired, 0) > 0 THEN a.episodes_aired
+					ELSE COALESCE(a.episodes_count, 0) END AS available
+			FROM anime_list al
+			JOIN animes a ON a.id = al.anime_id AND a.deleted_at IS NULL
+			WHERE al.user_id = ? AND al.status = 'watching' AND a.status = 'ongoing'
+			ORDER BY al.updated_at DESC`, userID).Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			for ep := row.Watched + 1; ep <= row.Watched+perAnime && ep <= row.Available; ep++ {
+				if len(q) >= domain.UpNextMaxItems {
+					return q, nil
+				}
+				key := domain.UpNextKey{AnimeID: row.AnimeID, EpisodeNumber: ep}
+				if dismissed[key] || upNextIndex(q, key) >= 0 {
+					continue
+				}
+				q = append(q, domain.UpNextItem{UserID: userID, AnimeID: row.AnimeID, EpisodeNumber: ep, Source: domain.UpNextSourceRule})
+			}
+		}
+		return q, nil
+	})
+}
+
+// KeepAiringUsers returns the state rows of every user with the KeepAiring
+// rule on, for the scheduled top-up sweep.
+func (r *UpNextRepository) KeepAiringUsers(ctx context.Context) ([]domain.UpNextState, error) {
+	var states []domain.UpNextState
+	err := r.db.WithContext(ctx).Where("keep_airing > 0").Order("user_id").Find(&states).Error
+	return states, err
+}
+
+// mutate loads the queue, lets edit produce the new order and persists the
+// difference. The version is bumped only when something changed; the
+// returned version is 0 in that case.
+//
+// The user's up_next_states row is taken `FOR UPDATE` first, so two devices
+// editing at once serialize instead of overwriting each other's
+// read-modify-write. (On sqlite FOR UPDATE is a no-op — accepted, see
+// gacha's GetPityForUpdate.)
+func (r *UpNextRepository) mutate(ctx context.Context, userID string, edit func(tx *gorm.DB, q []domain.UpNextItem) ([]domain.UpNextItem, error)) (int64, error) {
+	var version int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockUpNextState(tx, userID); err != nil {
+			return err
+		}
+		var before []domain.UpNextItem
+		if err := tx.Where("user_id = ?", userID).Order("position").Find(&before).Error; err != nil {
+			return err
+		}
+		after, err := edit(tx, append([]domain.UpNextItem(nil), before...))
+		if err != nil {
+			return err
+		}
+
+		changed := false
+		old := make(map[domain.UpNextKey]domain.UpNextItem, len(before))
+		for _, it := range before {
+			old[domain.UpNextKey{AnimeID: it.AnimeID, EpisodeNumber: it.EpisodeNumber}] = it
+		}
+		now := time.Now()
+		for i, it := range after {
+			k := domain.UpNextKey{AnimeID: it.AnimeID, EpisodeNumber: it.EpisodeNumber}
+			prev, existed := old[k]
+			delete(old, k)
+			switch {
+			case !existed:
+				it.Position = i
+				it.CreatedAt = now
+				if err := tx.Create(&it).Error; err != nil {
+					return err
+				}
+				changed = true
+			case prev.Position != i:
+				if err := tx.Model(&domain.UpNextItem{}).
+					Where("user_id = ? AND anime_id = ? AND episode_number = ?", userID, it.AnimeID, it.EpisodeNumber).
+					Update("position", i).Error; err != nil {
+					return err
+				}
+				changed = true
+			}
+		}
+		for k := range old {
+			if err := tx.Where("user_id = ? AND anime_id = ? AND episode_number = ?", userID, k.AnimeID, k.EpisodeNumber).
+				Delete(&domain.UpNextItem{}).Error; err != nil {
+				return err
+			}
+			changed = true
+		}
+		if !changed {
+			return nil
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO up_next_states (user_id, version, auto_enqueue, keep_airing, updated_at)
+			VALUES (?, 1, ?, 0, ?)
+			ON CONFLICT (user_id) DO UPDATE SET
+				version = up_next_states.version + 1,
+				updated_at = excluded.updated_at`,
+			userID, true, now).Error; err != nil {
+			return err
+		}
+		state, err := loadUpNextState(tx, userID)
+		if err != nil {
+			return err
+		}
+		version = state.Version
+		return nil
+	})
+	return version, err
+}
+
+// lockUpNextState creates the user's state row at version 0 with the
+// defaults when missing (idempotent), then locks it for the transaction.
+func lockUpNextState(tx *gorm.DB, userID string) error {
+	if err := tx.Exec(`
+		INSERT INTO up_next_states (user_id, version, auto_enqueue, keep_airing, updated_at)
+		VALUES (?, 0, ?, 0, ?)
+		ON CONFLICT (user_id) DO NOTHING`,
+		userID, true, time.Now()).Error; err != nil {
+		return err
+	}
+	var s domain.UpNextState
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		Take(&s).Error
+}
+
+// dismissUpNext records items as dismissed (idempotent).
+func dismissUpNext(tx *gorm.DB, items []domain.UpNextItem) error {
+	now := time.Now()
+	for _, it := range items {
+		if err := tx.Exec(`
+			INSERT INTO up_next_dismissals (user_id, anime_id, episode_number, created_at)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT (user_id, anime_id, episode_number) DO NOTHING`,
+			it.UserID, it.AnimeID, it.EpisodeNumber, now).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadUpNextState(db *gorm.DB, userID string) (*domain.UpNextState, error) {
+	var s domain.UpNextState
+	err := db.Where("user_id = ?", userID).Take(&s).Error
+	if stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return &domain.UpNextState{UserID: userID, AutoEnqueue: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// upNextAnimeAvailability returns the anime's available episode count
+// (episodes_aired for ongoing shows, else episodes_count) and whether it is
+// on the user's watching list.
+func upNextAnimeAvailability(tx *gorm.DB, userID, animeID string) (int, bool, error) {
+	var row struct {
+		Available int
+		Watching  int
+	}
+	err := tx.Raw(`
+		SELECT CASE WHEN COALESCE(a.episodes_aired, 0) > 0 THEN a.episodes_aired
+				ELSE COALESCE(a.episodes_count, 0) END AS available,
+			(SELECT COUNT(*) FROM anime_list al
+				WHERE al.user_id = ? AND al.anime_id = a.id AND al.status = 'watching') AS watching
+		FROM animes a
+		WHERE a.id = ? AND a.deleted_at IS NULL`, userID, animeID).Scan(&row).Error
+	return row.Available, row.Watching > 0, err
+}
+
+func upNextIndex(q []domain.UpNextItem, k domain.UpNextKey) int {
+	for i, it := range q {
+		if it.AnimeID == k.AnimeID && it.EpisodeNumber == k.EpisodeNumber {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,196 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupUpNextTestDB hand-rolls the tables UpNextRepository touches (raw SQL
+// — see setupRecapTestDB).
+func setupUpNextTestDB(t *testing.T) (*UpNextRepository, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err, "open in-memory sqlite")
+
+	stmts := []string{
+		`CREATE TABLE animes (
+			id TEXT PRIMARY KEY, name TEXT, name_ru TEXT, name_jp TEXT, poster_url TEXT,
+			episodes_count INTEGER, episodes_aired INTEGER, status TEXT, deleted_at DATETIME
+		)`,
+		`CREATE TABLE anime_list (
+			user_id TEXT, anime_id TEXT, status TEXT, episodes INTEGER DEFAULT 0, updated_at DATETIME
+		)`,
+		`CREATE TABLE watch_progress (
+			user_id TEXT, anime_id TEXT, episode_number INTEGER, progress INTEGER, duration INTEGER
+		)`,
+		`CREATE TABLE up_next_items (
+			user_id TEXT, anime_id TEXT, episode_number INTEGER,
+			position INTEGER NOT NULL DEFAULT 0, source TEXT NOT NULL DEFAULT 'manual', created_at DATETIME,
+			PRIMARY KEY (user_id, anime_id, episode_number)
+		)`,
+		`CREATE TABLE up_next_states (
+			user_id TEXT PRIMARY KEY, version INTEGER NOT NULL DEFAULT 0,
+			auto_enqueue NUMERIC NOT NULL DEFAULT 1, keep_airing INTEGER NOT NULL DEFAULT 0, updated_at DATETIME
+		)`,
+		`CREATE TABLE up_next_dismissals (
+			user_id TEXT, anime_id TEXT, episode_number INTEGER, created_at DATETIME,
+			PRIMARY KEY (user_id, anime_id, episode_number)
+		)`,
+		`INSERT INTO animes (id, name, episodes_count, episodes_aired, status) VALUES
+			('a', 'Show A', 12, 0, 'released'),
+			('b', 'Show B', 12, 0, 'released'),
+			('c', 'Airing C', 24, 6, 'ongoing')`,
+	}
+	for _, s := range stmts {
+		require.NoError(t, db.Exec(s).Error)
+	}
+	return NewUpNextRepository(db), db
+}
+
+func upNextOrder(t *testing.T, r *UpNextRepository) []domain.UpNextKey {
+	t.Helper()
+	items, err := r.List(context.Background(), "u1")
+	require.NoError(t, err)
+	out := make([]domain.UpNextKey, 0, len(items))
+	for i, it := range items {
+		require.Equal(t, i, it.Position, "positions must stay dense")
+		out = append(out, domain.UpNextKey{AnimeID: it.Anime.ID, EpisodeNumber: it.EpisodeNumber})
+	}
+	return out
+}
+
+func TestUpNextRepository_AddReorderAndStaleVersion(t *testing.T) {
+	r, _ := setupUpNextTestDB(t)
+	ctx := context.Background()
+	a4 := domain.UpNextKey{AnimeID: "a", EpisodeNumber: 4}
+	b1 := domain.UpNextKey{AnimeID: "b", EpisodeNumber: 1}
+	b2 := domain.UpNextKey{AnimeID: "b", EpisodeNumber: 2}
+
+	v1, err := r.Add(ctx, "u1", a4, nil, domain.UpNextSourceManual)
+	require.NoError(t, err)
+	_, err = r.Add(ctx, "u1", b1, nil, domain.UpNextSourceManual)
+	require.NoError(t, err)
+	zero := 0
+	v3, err := r.Add(ctx, "u1", b2, &zero, domain.UpNextSourceManual)
+	require.NoError(t, err)
+	assert.Equal(t, v1+2, v3)
+	assert.Equal(t, []domain.UpNextKey{b2, a4, b1}, upNextOrder(t, r))
+
+	// Re-adding moves instead of duplicating.
+	_, err = r.Add(ctx, "u1", b2, nil, domain.UpNextSourceManual)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.UpNextKey{a4, b1, b2}, upNextOrder(t, r))
+
+	state, err := r.GetState(ctx, "u1")
+	require.NoError(t, err)
+
+	// A reorder computed from an old version is rejected.
+	_, err = r.Reorder(ctx, "u1", v3, []domain.UpNextKey{b1, a4, b2})
+	assert.ErrorIs(t, err, ErrUpNextStale)
+
+	v, err := r.Reorder(ctx, "u1", state.Version, []domain.UpNextKey{b1, a4, b2})
+	require.NoError(t, err)
+	assert.Equal(t, state.Version+1, v)
+	assert.Equal(t, []domain.UpNextKey{b1, a4, b2}, upNextOrder(t, r))
+
+	// Missing items are a stale view too.
+	_, err = r.Reorder(ctx, "u1", 0, []domain.UpNextKey{b1, a4})
+	assert.ErrorIs(t, err, ErrUpNextStale)
+}
+
+func TestUpNextRepository_AdvanceReplacesCompletedEpisode(t *testing.T) {
+	r, db := setupUpNextTestDB(t)
+	ctx := context.Background()
+	a4 := domain.UpNextKey{AnimeID: "a", EpisodeNumber: 4}
+	b1 := domain.UpNextKey{AnimeID: "b", EpisodeNumber: 1}
+	for _, k := range []domain.UpNextKey{a4, b1} {
+		_, err := r.Add(ctx, "u1", k, nil, domain.UpNextSourceManual)
+		require.NoError(t, err)
+	}
+
+	// Finishing A ep 4 puts A ep 5 in its slot.
+	v, err := r.Advance(ctx, "u1", "a", 4, true)
+	require.NoError(t, err)
+	assert.NotZero(t, v)
+	assert.Equal(t, []domain.UpNextKey{{AnimeID: "a", EpisodeNumber: 5}, b1}, upNextOrder(t, r))
+
+	// Last episode: nothing left to enqueue.
+	_, err = r.Advance(ctx, "u1", "b", 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.UpNextKey{{AnimeID: "a", EpisodeNumber: 5}}, upNextOrder(t, r))
+	_, err = r.Advance(ctx, "u1", "a", 12, true)
+	require.NoError(t, err)
+	assert.Empty(t, upNextOrder(t, r))
+
+	// Outside the queue only watching-list anime are enqueued.
+	v, err = r.Advance(ctx, "u1", "b", 3, true)
+	require.NoError(t, err)
+	assert.Zero(t, v, "no change, no version bump")
+	require.NoError(t, db.Exec(`INSERT INTO anime_list (user_id, anime_id, status, episodes) VALUES ('u1', 'b', 'watching', 3)`).Error)
+	_, err = r.Advance(ctx, "u1", "b", 3, true)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.UpNextKey{{AnimeID: "b", EpisodeNumber: 4}}, upNextOrder(t, r))
+}
+
+func TestUpNextRepository_FillAiringKeepsAiredEpisodesQueued(t *testing.T) {
+	r, db := setupUpNextTestDB(t)
+	ctx := context.Background()
+	require.NoError(t, db.Exec(`INSERT INTO anime_list (user_id, anime_id, status, episodes) VALUES
+		('u1', 'c', 'watching', 5), ('u1', 'a', 'watching', 2)`).Error)
+
+	v, err := r.FillAiring(ctx, "u1", 2)
+	require.NoError(t, err)
+	assert.NotZero(t, v)
+	// Only ep 6 has aired; the released show is not an airing show.
+	assert.Equal(t, []domain.UpNextKey{{AnimeID: "c", EpisodeNumber: 6}}, upNextOrder(t, r))
+
+	v, err = r.FillAiring(ctx, "u1", 2)
+	require.NoError(t, err)
+	assert.Zero(t, v, "idempotent")
+}
+
+func TestUpNextRepository_FillAiringSkipsDismissedEpisodes(t *testing.T) {
+	r, db := setupUpNextTestDB(t)
+	ctx := context.Background()
+	require.NoError(t, db.Exec(`INSERT INTO anime_list (user_id, anime_id, status, episodes) VALUES ('u1', 'c', 'watching', 3)`).Error)
+	c4 := domain.UpNextKey{AnimeID: "c", EpisodeNumber: 4}
+	c5 := domain.UpNextKey{AnimeID: "c", EpisodeNumber: 5}
+
+	_, err := r.FillAiring(ctx, "u1", 2)
+	require.NoError(t, err)
+	require.Equal(t, []domain.UpNextKey{c4, c5}, upNextOrder(t, r))
+
+	_, err = r.Remove(ctx, "u1", c4)
+	require.NoError(t, err)
+	v, err := r.FillAiring(ctx, "u1", 2)
+	require.NoError(t, err)
+	assert.Zero(t, v, "a removed episode is not put back")
+	assert.Equal(t, []domain.UpNextKey{c5}, upNextOrder(t, r))
+
+	// Re-adding by hand forgets the dismissal; clearing dismisses everything.
+	_, err = r.Add(ctx, "u1", c4, nil, domain.UpNextSourceManual)
+	require.NoError(t, err)
+	var dismissed int64
+	require.NoError(t, db.Table("up_next_dismissals").Where("user_id = ?", "u1").Count(&dismissed).Error)
+	assert.Zero(t, dismissed)
+	_, err = r.Clear(ctx, "u1")
+	require.NoError(t, err)
+
+	// Completing ep 4 drops its dismissal; ep 5 stays dismissed while the
+	// newly reachable ep 6 is filled.
+	require.NoError(t, db.Exec(`UPDATE anime_list SET episodes = 4 WHERE user_id = 'u1' AND anime_id = 'c'`).Error)
+	_, err = r.Advance(ctx, "u1", "c", 4, false)
+	require.NoError(t, err)
+	_, err = r.FillAiring(ctx, "u1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.UpNextKey{{AnimeID: "c", EpisodeNumber: 6}}, upNextOrder(t, r))
+	var left int64
+	require.NoError(t, db.Table("up_next_dismissals").Where("user_id = ?", "u1").Count(&left).Error)
+	assert.Equal(t, int64(1), left, "only ep 5 remains dismissed")
+}
@@ -19,9 +19,15 @@ type ListService struct {
 	recsHint     *RecsHintProducer    // Recs extraction Phase 1 — fire-and-forget recompute hint to recs:8094; nil-safe, may be nil in tests
 	gachaCredit  *GachaCreditProducer // Phase 4 — fire-and-forget Энигмы credits; nil-safe, may be nil in tests
 	verifyHint   *VerifyHintProducer  // content-verify watching hint to content-verify:8101; nil-safe, may be nil in tests
+	upNext       upNextAdvancer       // Up Next queue advance on completion; nil in tests that don't wire it
 	log          *logger.Logger
 }
 
+// upNextAdvancer is the UpNextService hook MarkEpisodeWatched fires.
+type upNextAdvancer interface {
+	EpisodeCompleted(ctx context.Context, userID, animeID string, episode int)
+}
+
 // NewListService wires the list service. The recsHint, gachaCredit, and
 // verifyHint arguments may be nil in test environments that don't exercise
 // the respective paths; MarkEpisodeWatched and UpdateListEntry nil-guard each
@@ -48,6 +54,12 @@ func NewListService(
 	}
 }
 
+// WithUpNext attaches the Up Next queue so completed episodes advance it.
+func (s *ListService) WithUpNext(u upNextAdvancer) *ListService {
+	s.upNext = u
+	return s
+}
+
 // GetUserList returns user's anime list with optional status filter
 func (s *ListService) GetUserList(ctx context.Context, userID, status string) ([]*domain.AnimeListEntry, error) {
 	if status != "" {
@@ -373,6 +385,9 @@ func (s *ListService) MarkEpisodeWatched(ctx context.Context, userID, animeID st
 			// Phase 4 (gacha): fire non-blocking episode-watched credit.
 			// Nil-safe; gacha outage never fails this branch.
 			s.gachaCredit.EpisodeWatched(userID, animeID, req.Episode)
+			if s.upNext != nil {
+				s.upNext.EpisodeCompleted(ctx, userID, animeID, req.Episode)
+			}
 			return entry, nil
 		}
 
@@ -443,6 +458,12 @@ func (s *ListService) MarkEpisodeWatched(ctx context.Context, userID, animeID st
 	s.recsHint.Hint(userID, animeID)
 	s.verifyHint.Hint(userID, animeID)
 
+	// Up Next: dequeue the completed episode and enqueue the next one.
+	// Best-effort; the queue never fails the mark.
+	if s.upNext != nil {
+		s.upNext.EpisodeCompleted(ctx, userID, animeID, req.Episode)
+	}
+
 	// Return updated entry
 	return s.listRepo.GetByUserAndAnime(ctx, userID, animeID)
 }
@@ -112,7 +112,8 @@ func setupListServiceTestDB(t *testing.T) (*ListService, *gorm.DB) {
 			UNIQUE (user_id, anime_id, episode_number)
 		)`,
 		`CREATE TABLE watch_history (
-			id TEXT PRIMARY KEY,
+			seq INTEGER PRIMARY KEY AUTOINCREMENT,
+			id TEXT UNIQUE,
 			user_id TEXT NOT NULL,
 			anime_id TEXT NOT NULL,
 			episode_number INTEGER NOT NULL,
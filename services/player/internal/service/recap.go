@@ -0,0 +1,446 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+)
+
+// recapRepo is the data dependency (real repo or a test fake).
+type recapRepo interface {
+	YearHistory(ctx context.Context, userID string, from, to time.Time) ([]domain.RecapWatchRow, error)
+	ListStatuses(ctx context.Context, userID string, animeIDs []string) (map[string]string, error)
+	Genres(ctx context.Context, animeIDs []string) ([]domain.RecapAttribute, error)
+	Studios(ctx context.Context, animeIDs []string) ([]domain.RecapAttribute, error)
+	Seiyuu(ctx context.Context, animeIDs []string) ([]domain.RecapAttribute, error)
+	InstanceTotals(ctx context.Context, from, to time.Time) ([]domain.RecapUserTotals, error)
+	UserTimezone(ctx context.Context, userID string) string
+	Save(ctx context.Context, recap *domain.UserRecap) error
+	Get(ctx context.Context, userID string, year int) (*domain.UserRecap, error)
+	GetByShareToken(ctx context.Context, token string) (*domain.UserRecap, error)
+	SetShareToken(ctx context.Context, userID string, year int, token *string) error
+}
+
+// minRecapYear rejects nonsense years before the platform existed.
+const minRecapYear = 2020
+
+// RecapService builds and serves the yearly "Wrapped" recap. Recaps are
+// precomputed by the scheduler-triggered GenerateAll sweep; Get falls back to
+// an on-demand build the first time an owner opens a missing recap.
+type RecapService struct {
+	repo recapRepo
+	log  *logger.Logger
+	now  func() time.Time
+
+	// sweeping guards StartSweep so a retried scheduler trigger can't launch
+	// a second concurrent GenerateAll over the same users.
+	sweeping atomic.Bool
+}
+
+func NewRecapService(r recapRepo, log *logger.Logger) *RecapService {
+	return &RecapService{repo: r, log: log, now: time.Now}
+}
+
+// ValidateRecapYear rejects years outside [minRecapYear, current year].
+func (s *RecapService) ValidateRecapYear(year int) error {
+	if year < minRecapYear || year > s.now().Year() {
+		return errors.InvalidInput("year out of range")
+	}
+	return nil
+}
+
+// yearBounds returns the UTC [from, to) window for year — the instance-wide
+// percentile population is bucketed in UTC.
+func yearBounds(year int) (time.Time, time.Time) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return from, from.AddDate(1, 0, 0)
+}
+
+// Get returns the owner's recap for year, building and storing it on the
+// first request when the nightly sweep has not produced it yet.
+func (s *RecapService) Get(ctx context.Context, userID string, year int) (*domain.UserRecap, error) {
+	if err := s.ValidateRecapYear(year); err != nil {
+		return nil, err
+	}
+	recap, err := s.repo.Get(ctx, userID, year)
+	if err != nil {
+		return nil, err
+	}
+	if recap != nil {
+		return recap, nil
+	}
+	from, to := yearBounds(year)
+	totals, err := s.repo.InstanceTotals(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return s.generate(ctx, userID, year, totals)
+}
+
+// GenerateAll rebuilds the recap of every user with watch history in year.
+// The instance-wide totals are loaded once and shared by every build. A
+// single user's failure is logged and skipped so one bad account never stalls
+// the sweep; the count of successfully written recaps is returned.
+func (s *RecapService) GenerateAll(ctx context.Context, year int) (int, error) {
+	if err := s.ValidateRecapYear(year); err != nil {
+		return 0, err
+	}
+	from, to := yearBounds(year)
+	totals, err := s.repo.InstanceTotals(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	written := 0
+	for _, t := range totals {
+		if ctx.Err() != nil {
+			return written, ctx.Err()
+		}
+		if _, err := s.generate(ctx, t.UserID, year, totals); err != nil {
+			s.log.Errorw("failed to generate recap", "user_id", t.UserID, "year", year, "error", err)
+			continue
+		}
+		written++
+	}
+	s.log.Infow("recap sweep complete", "year", year, "users", len(totals), "written", written)
+	return written, nil
+}
+
+// StartSweep launches GenerateAll for year in the background and reports
+// whether it started (false when a sweep is already running). The sweep
+// outlives the triggering request, so it runs on a detached context.
+func (s *RecapService) StartSweep(year int) (bool, error) {
+	if err := s.ValidateRecapYear(year); err != nil {
+		return false, err
+	}
+	if !s.sweeping.CompareAndSwap(false, true) {
+		return false, nil
+	}
+	go func() {
+		defer s.sweeping.Store(false)
+		if _, err := s.GenerateAll(context.Background(), year); err != nil {
+			s.log.Errorw("recap sweep failed", "year", year, "error", err)
+		}
+	}()
+	return true, nil
+}
+
+func (s *RecapService) generate(ctx context.Context, userID string, year int, totals []domain.RecapUserTotals) (*domain.UserRecap, error) {
+	// Fetch with one day of slack on each side of the UTC year; the rows are
+	// then re-bucketed in the user's own timezone and the slack dropped.
+	from, to := yearBounds(year)
+	rows, err := s.repo.YearHistory(ctx, userID, from.AddDate(0, 0, -1), to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	loc := time.UTC
+	if tz := s.repo.UserTimezone(ctx, userID); tz != "" {
+		if l, err := time.LoadLocation(tz); err == nil {
+			loc = l
+		}
+	}
+	rows = filterRecapYear(rows, year, loc)
+
+	animeIDs := recapAnimeIDs(rows)
+	statuses, err := s.repo.ListStatuses(ctx, userID, animeIDs)
+	if err != nil {
+		return nil, err
+	}
+	genres, err := s.repo.Genres(ctx, animeIDs)
+	if err != nil {
+		return nil, err
+	}
+	studios, err := s.repo.Studios(ctx, animeIDs)
+	if err != nil {
+		return nil, err
+	}
+	seiyuu, err := s.repo.Seiyuu(ctx, animeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	data := buildRecap(year, loc, rows, statuses)
+	perAnime := recapEpisodesPerAnime(rows)
+	data.TopGenres = topAttributes(genres, perAnime)
+	data.TopStudios = topAttributes(studios, perAnime)
+	data.TopSeiyuu = topAttributes(seiyuu, perAnime)
+	data.MinutesPercentile, data.EpisodesPercentile = recapPercentiles(userID, totals)
+	data.InstanceUsers = len(totals)
+
+	recap := &domain.UserRecap{UserID: userID, Year: year, Data: data, GeneratedAt: s.now()}
+	if err := s.repo.Save(ctx, recap); err != nil {
+		return nil, err
+	}
+	return recap, nil
+}
+
+// Share publishes the owner's recap under a fresh random token and returns
+// it. Re-sharing rotates the token, invalidating any earlier link.
+func (s *RecapService) Share(ctx context.Context, userID string, year int) (string, error) {
+	if _, err := s.Get(ctx, userID, year); err != nil {
+		return "", err
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Wrap(err, errors.CodeInternal, "failed to generate share token")
+	}
+	token := hex.EncodeToString(buf)
+	if err := s.repo.SetShareToken(ctx, userID, year, &token); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Unshare revokes the public link of the owner's recap.
+func (s *RecapService) Unshare(ctx context.Context, userID string, year int) error {
+	if err := s.ValidateRecapYear(year); err != nil {
+		return err
+	}
+	return s.repo.SetShareToken(ctx, userID, year, nil)
+}
+
+// GetShared resolves a public share token.
+func (s *RecapService) GetShared(ctx context.Context, token string) (*domain.UserRecap, error) {
+	recap, err := s.repo.GetByShareToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if recap == nil {
+		return nil, errors.NotFound("recap")
+	}
+	return recap, nil
+}
+
+// filterRecapYear keeps only the rows whose watched_at falls inside year in
+// loc (the fetch window carries a day of slack on each side).
+func filterRecapYear(rows []domain.RecapWatchRow, year int, loc *time.Location) []domain.RecapWatchRow {
+	out := rows[:0]
+	for _, row := range rows {
+		if row.WatchedAt.In(loc).Year() == year {
+			out = append(out, row)
+		}
+	}
+	return out
+}
+
+func recapAnimeIDs(rows []domain.RecapWatchRow) []string {
+	seen := map[string]bool{}
+	ids := []string{}
+	for _, row := range rows {
+		if !seen[row.AnimeID] {
+			seen[row.AnimeID] = true
+			ids = append(ids, row.AnimeID)
+		}
+	}
+	return ids
+}
+
+func recapEpisodesPerAnime(rows []domain.RecapWatchRow) map[string]int {
+	out := map[string]int{}
+	for _, row := range rows {
+		out[row.AnimeID]++
+	}
+	return out
+}
+
+// buildRecap computes every history-derived recap field. rows must be sorted
+// by watched_at ascending (YearHistory's order) and already filtered to year.
+// statuses maps anime_id → the user's current list status.
+func buildRecap(year int, loc *time.Location, rows []domain.RecapWatchRow, statuses map[string]string) domain.RecapData {
+	data := domain.RecapData{
+		Year:       year,
+		Months:     make([]domain.RecapMonth, 12),
+		TopAnime:   []domain.RecapAnimeStat{},
+		TopGenres:  []domain.RecapNamedCount{},
+		TopStudios: []domain.RecapNamedCount{},
+		TopSeiyuu:  []domain.RecapNamedCount{},
+	}
+	for i := range data.Months {
+		data.Months[i].Month = i + 1
+	}
+	if len(rows) == 0 {
+		return data
+	}
+
+	var totalSeconds int
+	monthSeconds := make([]int, 12)
+	perAnime := map[string]*domain.RecapAnimeStat{}
+	animeSeconds := map[string]int{}
+	var order []string
+	for _, row := range rows {
+		m := row.WatchedAt.In(loc).Month() - 1
+		monthSeconds[m] += row.DurationWatched
+		data.Months[m].Episodes++
+		totalSeconds += row.DurationWatched
+
+		st, ok := perAnime[row.AnimeID]
+		if !ok {
+			st = &domain.RecapAnimeStat{AnimeID: row.AnimeID, Name: row.Name, NameRU: row.NameRU, PosterURL: row.PosterURL}
+			perAnime[row.AnimeID] = st
+			order = append(order, row.AnimeID)
+		}
+		st.Episodes++
+		animeSeconds[row.AnimeID] += row.DurationWatched
+	}
+	for i := range data.Months {
+		data.Months[i].Minutes = monthSeconds[i] / 60
+	}
+	data.TotalMinutes = totalSeconds / 60
+	data.TotalEpisodes = len(rows)
+	data.TotalAnime = len(perAnime)
+
+	top := make([]domain.RecapAnimeStat, 0, len(order))
+	for _, id := range order {
+		st := *perAnime[id]
+		st.Minutes = animeSeconds[id] / 60
+		top = append(top, st)
+	}
+	// Episodes first (duration_watched is 0 on legacy rows), minutes as the
+	// tiebreak, then first-watched order for a stable podium.
+	sort.SliceStable(top, func(i, j int) bool {
+		if top[i].Episodes != top[j].Episodes {
+			return top[i].Episodes > top[j].Episodes
+		}
+		return top[i].Minutes > top[j].Minutes
+	})
+	if len(top) > domain.RecapTopN {
+		top = top[:domain.RecapTopN]
+	}
+	data.TopAnime = top
+
+	data.LongestBinge = longestBinge(rows)
+
+	for id := range perAnime {
+		switch statuses[id] {
+		case "completed":
+			data.CompletedCount++
+		case "dropped":
+			data.DroppedCount++
+		}
+	}
+	data.CompletionRate = float64(data.CompletedCount) / float64(len(perAnime))
+
+	first, last := rows[0], rows[len(rows)-1]
+	data.FirstWatch = &domain.RecapWatchPoint{AnimeID: first.AnimeID, Name: first.Name, PosterURL: first.PosterURL, EpisodeNumber: first.EpisodeNumber, WatchedAt: first.WatchedAt}
+	data.LastWatch = &domain.RecapWatchPoint{AnimeID: last.AnimeID, Name: last.Name, PosterURL: last.PosterURL, EpisodeNumber: last.EpisodeNumber, WatchedAt: last.WatchedAt}
+	return data
+}
+
+// longestBinge finds the longest chain of completions of one anime where each
+// consecutive pair is at most domain.RecapBingeGap apart. Chains are tracked
+// per anime so switching shows mid-evening doesn't break either chain.
+func longestBinge(rows []domain.RecapWatchRow) *domain.RecapBinge {
+	type chain struct {
+		start, last time.Time
+		episodes    int
+		seconds     int
+	}
+	open := map[string]*chain{}
+	var best *domain.RecapBinge
+	consider := func(id, name string, c *chain) {
+		if c.episodes < 2 {
+			return
+		}
+		if best == nil || c.episodes > best.Episodes {
+			best = &domain.RecapBinge{AnimeID: id, Name: name, Episodes: c.episodes, Minutes: c.seconds / 60, StartedAt: c.start, EndedAt: c.last}
+		}
+	}
+	names := map[string]string{}
+	for _, row := range rows {
+		names[row.AnimeID] = row.Name
+		c, ok := open[row.AnimeID]
+		if ok && row.WatchedAt.Sub(c.last) <= domain.RecapBingeGap {
+			c.last = row.WatchedAt
+			c.episodes++
+			c.seconds += row.DurationWatched
+			continue
+		}
+		if ok {
+			consider(row.AnimeID, names[row.AnimeID], c)
+		}
+		open[row.AnimeID] = &chain{start: row.WatchedAt, last: row.WatchedAt, episodes: 1, seconds: row.DurationWatched}
+	}
+	ids := make([]string, 0, len(open))
+	for id := range open {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids) // deterministic winner among equal-length open chains
+	for _, id := range ids {
+		consider(id, names[id], open[id])
+	}
+	return best
+}
+
+// topAttributes rolls (anime, attribute) pairs into the top-N attributes by
+// the number of episodes the user watched of anime carrying them.
+func topAttributes(attrs []domain.RecapAttribute, perAnime map[string]int) []domain.RecapNamedCount {
+	byKey := map[string]*domain.RecapNamedCount{}
+	seen := map[string]bool{} // (anime, key) — a duplicate join row must not double-count
+	for _, a := range attrs {
+		key := a.ID
+		if key == "" {
+			key = a.Name
+		}
+		pair := a.AnimeID + "\x00" + key
+		if seen[pair] {
+			continue
+		}
+		seen[pair] = true
+		nc, ok := byKey[key]
+		if !ok {
+			nc = &domain.RecapNamedCount{ID: a.ID, Name: a.Name}
+			byKey[key] = nc
+		}
+		nc.Episodes += perAnime[a.AnimeID]
+	}
+	out := make([]domain.RecapNamedCount, 0, len(byKey))
+	for _, nc := range byKey {
+		out = append(out, *nc)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Episodes != out[j].Episodes {
+			return out[i].Episodes > out[j].Episodes
+		}
+		return out[i].Name < out[j].Name
+	})
+	if len(out) > domain.RecapTopN {
+		out = out[:domain.RecapTopN]
+	}
+	return out
+}
+
+// recapPercentiles returns the share (0..100) of OTHER instance users the
+// user strictly out-watched, by minutes and by episodes. A user missing from
+// totals (no history) or alone on the instance scores 0.
+func recapPercentiles(userID string, totals []domain.RecapUserTotals) (int, int) {
+	var me *domain.RecapUserTotals
+	for i := range totals {
+		if totals[i].UserID == userID {
+			me = &totals[i]
+			break
+		}
+	}
+	if me == nil || len(totals) < 2 {
+		return 0, 0
+	}
+	var bySeconds, byEpisodes int
+	for _, t := range totals {
+		if t.UserID == userID {
+			continue
+		}
+		if me.Seconds > t.Seconds {
+			bySeconds++
+		}
+		if me.Episodes > t.Episodes {
+			byEpisodes++
+		}
+	}
+	others := len(totals) - 1
+	return bySeconds * 100 / others, byEpisodes * 100 / others
+}
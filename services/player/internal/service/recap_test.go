@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRecapRepo struct {
+	history  []domain.RecapWatchRow
+	statuses map[string]string
+	genres   []domain.RecapAttribute
+	totals   []domain.RecapUserTotals
+	tz       string
+	saved    map[int]*domain.UserRecap
+	token    *string
+}
+
+func (f *fakeRecapRepo) YearHistory(_ context.Context, _ string, from, to time.Time) ([]domain.RecapWatchRow, error) {
+	var out []domain.RecapWatchRow
+	for _, r := range f.history {
+		if !r.WatchedAt.Before(from) && r.WatchedAt.Before(to) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+func (f *fakeRecapRepo) ListStatuses(context.Context, string, []string) (map[string]string, error) {
+	return f.statuses, nil
+}
+func (f *fakeRecapRepo) Genres(context.Context, []string) ([]domain.RecapAttribute, error) {
+	return f.genres, nil
+}
+func (f *fakeRecapRepo) Studios(context.Context, []string) ([]domain.RecapAttribute, error) {
+	return nil, nil
+}
+func (f *fakeRecapRepo) Seiyuu(context.Context, []string) ([]domain.RecapAttribute, error) {
+	return nil, nil
+}
+func (f *fakeRecapRepo) InstanceTotals(context.Context, time.Time, time.Time) ([]domain.RecapUserTotals, error) {
+	return f.totals, nil
+}
+func (f *fakeRecapRepo) UserTimezone(context.Context, string) string { return f.tz }
+func (f *fakeRecapRepo) Save(_ context.Context, r *domain.UserRecap) error {
+	if f.saved == nil {
+		f.saved = map[int]*domain.UserRecap{}
+	}
+	f.saved[r.Year] = r
+	return nil
+}
+func (f *fakeRecapRepo) Get(_ context.Context, _ string, year int) (*domain.UserRecap, error) {
+	return f.saved[year], nil
+}
+func (f *fakeRecapRepo) GetByShareToken(_ context.Context, token string) (*domain.UserRecap, error) {
+	if f.token == nil || *f.token != token {
+		return nil, nil
+	}
+	for _, r := range f.saved {
+		return r, nil
+	}
+	return nil, nil
+}
+func (f *fakeRecapRepo) SetShareToken(_ context.Context, _ string, _ int, token *string) error {
+	f.token = token
+	return nil
+}
+
+func at(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func newTestRecapService(f *fakeRecapRepo) *RecapService {
+	s := NewRecapService(f, logger.Default())
+	s.now = func() time.Time { return at("2026-12-31T12:00:00Z") }
+	return s
+}
+
+func TestRecapService_BuildsMonthlyTotalsTopAndBinge(t *testing.T) {
+	f := &fakeRecapRepo{
+		history: []domain.RecapWatchRow{
+			{AnimeID: "a", Name: "A", EpisodeNumber: 1, DurationWatched: 1440, WatchedAt: at("2026-01-10T20:00:00Z")},
+			{AnimeID: "a", Name: "A", EpisodeNumber: 2, DurationWatched: 1440, WatchedAt: at("2026-01-10T20:30:00Z")},
+			{AnimeID: "b", Name: "B", EpisodeNumber: 1, DurationWatched: 1440, WatchedAt: at("2026-01-10T21:00:00Z")},
+			{AnimeID: "a", Name: "A", EpisodeNumber: 3, DurationWatched: 1440, WatchedAt: at("2026-01-10T21:30:00Z")},
+			{AnimeID: "b", Name: "B", EpisodeNumber: 2, DurationWatched: 1440, WatchedAt: at("2026-03-02T20:00:00Z")},
+		},
+		statuses: map[string]string{"a": "completed", "b": "dropped"},
+		genres: []domain.RecapAttribute{
+			{AnimeID: "a", ID: "g1", Name: "Action"},
+			{AnimeID: "b", ID: "g1", Name: "Action"},
+			{AnimeID: "b", ID: "g2", Name: "Drama"},
+		},
+		totals: []domain.RecapUserTotals{
+			{UserID: "me", Seconds: 7200, Episodes: 5},
+			{UserID: "u2", Seconds: 60, Episodes: 1},
+			{UserID: "u3", Seconds: 9000, Episodes: 9},
+		},
+	}
+	recap, err := newTestRecapService(f).Get(context.Background(), "me", 2026)
+	require.NoError(t, err)
+	d := recap.Data
+
+	assert.Equal(t, 120, d.TotalMinutes)
+	assert.Equal(t, 5, d.TotalEpisodes)
+	assert.Equal(t, 2, d.TotalAnime)
+	require.Len(t, d.Months, 12)
+	assert.Equal(t, 4, d.Months[0].Episodes)
+	assert.Equal(t, 96, d.Months[0].Minutes)
+	assert.Equal(t, 1, d.Months[2].Episodes)
+
+	require.NotEmpty(t, d.TopAnime)
+	assert.Equal(t, "a", d.TopAnime[0].AnimeID)
+	assert.Equal(t, 3, d.TopAnime[0].Episodes)
+	require.Len(t, d.TopGenres, 2)
+	assert.Equal(t, domain.RecapNamedCount{ID: "g1", Name: "Action", Episodes: 5}, d.TopGenres[0])
+
+	// The B episode in between doesn't break A's chain (chains are per anime).
+	require.NotNil(t, d.LongestBinge)
+	assert.Equal(t, "a", d.LongestBinge.AnimeID)
+	assert.Equal(t, 3, d.LongestBinge.Episodes)
+
+	assert.Equal(t, 1, d.CompletedCount)
+	assert.Equal(t, 1, d.DroppedCount)
+	assert.InDelta(t, 0.5, d.CompletionRate, 1e-9)
+	assert.Equal(t, "a", d.FirstWatch.AnimeID)
+	assert.Equal(t, "b", d.LastWatch.AnimeID)
+
+	// Out-watched u2 but not u3 → 50th percentile of the other two users.
+	assert.Equal(t, 50, d.MinutesPercentile)
+	assert.Equal(t, 50, d.EpisodesPercentile)
+	assert.Equal(t, 3, d.InstanceUsers)
+}
+
+func TestRecapService_BucketsInUserTimezone(t *testing.T) {
+	f := &fakeRecapRepo{
+		// 23:30 UTC on Dec 31 2025 is already Jan 1 2026 in Moscow (UTC+3).
+		history: []domain.RecapWatchRow{
+			{AnimeID: "a", EpisodeNumber: 1, DurationWatched: 600, WatchedAt: at("2025-12-31T23:30:00Z")},
+			{AnimeID: "a", EpisodeNumber: 2, DurationWatched: 600, WatchedAt: at("2026-12-31T22:30:00Z")},
+		},
+		tz: "Europe/Moscow",
+	}
+	recap, err := newTestRecapService(f).Get(context.Background(), "me", 2026)
+	require.NoError(t, err)
+	assert.Equal(t, 1, recap.Data.TotalEpisodes, "only the first row falls inside 2026 in Moscow time")
+	assert.Equal(t, 1, recap.Data.Months[0].Episodes)
+}
+
+func TestRecapService_ShareRoundTripAndRevoke(t *testing.T) {
+	f := &fakeRecapRepo{}
+	s := newTestRecapService(f)
+	ctx := context.Background()
+
+	token, err := s.Share(ctx, "me", 2026)
+	require.NoError(t, err)
+	assert.Len(t, token, 32)
+
+	shared, err := s.GetShared(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, 2026, shared.Year)
+
+	require.NoError(t, s.Unshare(ctx, "me", 2026))
+	_, err = s.GetShared(ctx, token)
+	assert.Error(t, err, "a revoked token must stop resolving")
+}
+
+func TestRecapService_RejectsFutureYear(t *testing.T) {
+	_, err := newTestRecapService(&fakeRecapRepo{}).Get(context.Background(), "me", 2027)
+	assert.Error(t, err)
+}
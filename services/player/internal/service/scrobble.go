@@ -0,0 +1,295 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+)
+
+// ParsedRelease is the library's reading of a release filename.
+type ParsedRelease struct {
+	Uploader string `json:"uploader"`
+	Title    string `json:"title"`
+	Episode  int    `json:"episode"`
+}
+
+// releaseParser identifies a release filename. A nil release with a nil error
+// means the filename could not be parsed.
+type releaseParser interface {
+	ParseRelease(ctx context.Context, filename string) (*ParsedRelease, error)
+}
+
+// scrobbleAnimeLookup is the ScrobbleRepository surface the service uses.
+type scrobbleAnimeLookup interface {
+	FindAnimeByExternalID(ctx context.Context, column, value string) (string, error)
+	FindAnimeByTitle(ctx context.Context, title string) (string, error)
+}
+
+// scrobbleProgressWriter is the ProgressService surface the service uses.
+type scrobbleProgressWriter interface {
+	UpdateProgress(ctx context.Context, userID string, req *domain.UpdateProgressRequest) (*domain.WatchProgress, error)
+}
+
+// scrobbleEpisodeMarker is the ListService surface the service uses.
+type scrobbleEpisodeMarker interface {
+	MarkEpisodeWatched(ctx context.Context, userID, animeID string, req *domain.MarkEpisodeWatchedRequest) (*domain.AnimeListEntry, error)
+}
+
+// ScrobbleService turns playback events from external players and media
+// servers into watch_progress updates and, past the completion threshold,
+// episode completions. Completions go through ListService.MarkEpisodeWatched
+// so the list, gacha credit, watch_history and recs hints behave exactly as
+// for the in-site player.
+type ScrobbleService struct {
+	lookup    scrobbleAnimeLookup
+	parser    releaseParser
+	progress  scrobbleProgressWriter
+	list      scrobbleEpisodeMarker
+	threshold float64
+	log       *logger.Logger
+}
+
+// NewScrobbleService wires a ScrobbleService. threshold is the
+// position/duration ratio a stop event must reach to mark the episode
+// watched; values outside (0, 1] fall back to 0.85.
+func NewScrobbleService(lookup scrobbleAnimeLookup, parser releaseParser, progress scrobbleProgressWriter, list scrobbleEpisodeMarker, threshold float64, log *logger.Logger) *ScrobbleService {
+	if threshold <= 0 || threshold > 1 {
+		threshold = 0.85
+	}
+	return &ScrobbleService{
+		lookup:    lookup,
+		parser:    parser,
+		progress:  progress,
+		list:      list,
+		threshold: threshold,
+		log:       log,
+	}
+}
+
+// Scrobble records one playback event for userID.
+func (s *ScrobbleService) Scrobble(ctx context.Context, userID string, req *domain.ScrobbleRequest) (*domain.ScrobbleResult, error) {
+	switch req.Event {
+	case domain.ScrobbleEventStart, domain.ScrobbleEventPause, domain.ScrobbleEventResume,
+		domain.ScrobbleEventProgress, domain.ScrobbleEventStop:
+	default:
+		return nil, errors.InvalidInput("event must be one of start, pause, resume, progress, stop")
+	}
+	if req.Position < 0 || req.Duration < 0 {
+		return nil, errors.InvalidInput("position and duration must not be negative")
+	}
+
+	animeID, episode, matchedBy, err := s.resolve(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if animeID == "" {
+		return &domain.ScrobbleResult{Status: domain.ScrobbleStatusUnmatched}, nil
+	}
+	if episode <= 0 {
+		return &domain.ScrobbleResult{Status: domain.ScrobbleStatusIgnored, AnimeID: animeID, MatchedBy: matchedBy}, nil
+	}
+
+	position := req.Position
+	if req.Duration > 0 && position > req.Duration {
+		position = req.Duration
+	}
+	res := &domain.ScrobbleResult{
+		Status:    domain.ScrobbleStatusRecorded,
+		AnimeID:   animeID,
+		Episode:   episode,
+		Position:  position,
+		Duration:  req.Duration,
+		MatchedBy: matchedBy,
+	}
+
+	// Player stays empty: an external client has no (player, language,
+	// watch_type) combo, so the preference resolver must not learn from it.
+	if req.Duration > 0 || position > 0 {
+		if _, err := s.progress.UpdateProgress(ctx, userID, &domain.UpdateProgressRequest{
+			AnimeID:       animeID,
+			EpisodeNumber: episode,
+			Progress:      position,
+			Duration:      req.Duration,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if !s.completes(req, position) {
+		return res, nil
+	}
+	if _, err := s.list.MarkEpisodeWatched(ctx, userID, animeID, &domain.MarkEpisodeWatchedRequest{
+		Episode:          episode,
+		Player:           domain.ScrobblePlayer,
+		TranslationTitle: scrobbleClient(req.Client),
+	}); err != nil {
+		return nil, err
+	}
+	res.Status = domain.ScrobbleStatusCompleted
+	s.log.Infow("scrobble completed episode",
+		"user_id", userID, "anime_id", animeID, "episode", episode,
+		"client", req.Client, "matched_by", matchedBy)
+	return res, nil
+}
+
+// completes reports whether the event marks the episode watched: a
+// server-declared completion, or a stop at or past the threshold from a
+// client that has no completion event of its own.
+func (s *ScrobbleService) completes(req *domain.ScrobbleRequest, position int) bool {
+	if req.Completed {
+		return true
+	}
+	if req.ServerCompletes || req.Event != domain.ScrobbleEventStop || req.Duration <= 0 {
+		return false
+	}
+	return float64(position)/float64(req.Duration) >= s.threshold
+}
+
+// resolve maps the request's identifiers to (anime id, episode, matched by).
+// Identifiers are tried most-specific first; a filename also supplies the
+// episode when the request has none.
+func (s *ScrobbleService) resolve(ctx context.Context, req *domain.ScrobbleRequest) (string, int, string, error) {
+	episode := req.Episode
+	ids := []struct{ column, value, matchedBy string }{
+		{"id", req.AnimeID, domain.ScrobbleMatchAnimeID},
+		{"mal_id", req.MALID, domain.ScrobbleMatchMAL},
+		{"anilist_id", req.AniListID, domain.ScrobbleMatchAniList},
+		{"shikimori_id", req.ShikimoriID, domain.ScrobbleMatchShikimori},
+	}
+	for _, id := range ids {
+		v := strings.TrimSpace(id.value)
+		if v == "" {
+			continue
+		}
+		animeID, err := s.lookup.FindAnimeByExternalID(ctx, id.column, v)
+		if err != nil {
+			return "", 0, "", err
+		}
+		if animeID != "" {
+			if episode <= 0 {
+				episode = s.filenameEpisode(ctx, req.Filename)
+			}
+			return animeID, episode, id.matchedBy, nil
+		}
+	}
+
+	if req.Title != "" {
+		animeID, err := s.lookup.FindAnimeByTitle(ctx, req.Title)
+		if err != nil {
+			return "", 0, "", err
+		}
+		if animeID != "" {
+			if episode <= 0 {
+				episode = s.filenameEpisode(ctx, req.Filename)
+			}
+			return animeID, episode, domain.ScrobbleMatchTitle, nil
+		}
+	}
+
+	if req.Filename == "" || s.parser == nil {
+		return "", 0, "", nil
+	}
+	rel, err := s.parser.ParseRelease(ctx, req.Filename)
+	if err != nil {
+		return "", 0, "", err
+	}
+	if rel == nil {
+		return "", 0, "", nil
+	}
+	animeID, err := s.lookup.FindAnimeByTitle(ctx, rel.Title)
+	if err != nil || animeID == "" {
+		return "", 0, "", err
+	}
+	if episode <= 0 {
+		episode = rel.Episode
+	}
+	return animeID, episode, domain.ScrobbleMatchFilename, nil
+}
+
+// filenameEpisode is the episode number parsed from filename, 0 when it
+// can't be parsed. Best-effort: a library outage only loses the episode.
+func (s *ScrobbleService) filenameEpisode(ctx context.Context, filename string) int {
+	if filename == "" || s.parser == nil {
+		return 0
+	}
+	rel, err := s.parser.ParseRelease(ctx, filename)
+	if err != nil {
+		s.log.Warnw("scrobble: filename parse failed", "filename", filename, "error", err)
+		return 0
+	}
+	if rel == nil {
+		return 0
+	}
+	return rel.Episode
+}
+
+// scrobbleClient normalizes the client label stored on watch_history.
+func scrobbleClient(client string) string {
+	client = strings.ToLower(strings.TrimSpace(client))
+	if client == "" {
+		return "external"
+	}
+	if len(client) > 64 {
+		client = client[:64]
+	}
+	return client
+}
+
+// LibraryReleaseParser calls the library's
+// /internal/library/parse-filename endpoint, which owns the release-filename
+// detector.
+type LibraryReleaseParser struct {
+	url    string
+	client *http.Client
+}
+
+// NewLibraryReleaseParser builds a parser against the library base URL
+// inside the Docker network.
+func NewLibraryReleaseParser(libraryURL string) *LibraryReleaseParser {
+	return &LibraryReleaseParser{
+		url:    strings.TrimRight(libraryURL, "/"),
+		client: &http.Client{Timeout: 3 * time.Second},
+	}
+}
+
+// ParseRelease returns the parsed release, or nil when the library could not
+// extract a title and episode (422).
+func (p *LibraryReleaseParser) ParseRelease(ctx context.Context, filename string) (*ParsedRelease, error) {
+	body, err := json.Marshal(map[string]string{"filename": filename})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url+"/internal/library/parse-filename", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeExternalAPI, "library parse-filename")
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return nil, nil
+	default:
+		return nil, errors.New(errors.CodeExternalAPI, fmt.Sprintf("library parse-filename: status %d", resp.StatusCode))
+	}
+	var envelope struct {
+		Data ParsedRelease `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, errors.Wrap(err, errors.CodeExternalAPI, "decode library parse-filename")
+	}
+	return &envelope.Data, nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+)
+
+type fakeScrobbleLookup struct {
+	ids    map[string]string // "column:value" → anime id
+	titles map[string]string // lowercased title → anime id
+}
+
+func (f *fakeScrobbleLookup) FindAnimeByExternalID(_ context.Context, column, value string) (string, error) {
+	return f.ids[column+":"+value], nil
+}
+
+func (f *fakeScrobbleLookup) FindAnimeByTitle(_ context.Context, title string) (string, error) {
+	return f.titles[strings.ToLower(title)], nil
+}
+
+type fakeScrobbleProgress struct {
+	calls []domain.UpdateProgressRequest
+}
+
+func (f *fakeScrobbleProgress) UpdateProgress(_ context.Context, _ string, req *domain.UpdateProgressRequest) (*domain.WatchProgress, error) {
+	f.calls = append(f.calls, *req)
+	return &domain.WatchProgress{}, nil
+}
+
+type fakeScrobbleMarker struct {
+	calls []domain.MarkEpisodeWatchedRequest
+}
+
+func (f *fakeScrobbleMarker) MarkEpisodeWatched(_ context.Context, _, _ string, req *domain.MarkEpisodeWatchedRequest) (*domain.AnimeListEntry, error) {
+	f.calls = append(f.calls, *req)
+	return &domain.AnimeListEntry{}, nil
+}
+
+// newLibraryParserStub answers /internal/library/parse-filename like the
+// library: one known filename parses, anything else is a 422.
+func newLibraryParserStub(t *testing.T) *LibraryReleaseParser {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = httputil.Bind(r, &body)
+		if body["filename"] == "[SubsPlease] Sousou no Frieren - 05 (1080p) [ABCD1234].mkv" {
+			httputil.OK(w, ParsedRelease{Uploader: "SubsPlease", Title: "Sousou no Frieren", Episode: 5})
+			return
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}))
+	t.Cleanup(srv.Close)
+	return NewLibraryReleaseParser(srv.URL)
+}
+
+func newTestScrobbleService(t *testing.T) (*ScrobbleService, *fakeScrobbleProgress, *fakeScrobbleMarker) {
+	lookup := &fakeScrobbleLookup{
+		ids:    map[string]string{"mal_id:52991": "anime-1", "anilist_id:154587": "anime-1"},
+		titles: map[string]string{"sousou no frieren": "anime-1"},
+	}
+	progress := &fakeScrobbleProgress{}
+	marker := &fakeScrobbleMarker{}
+	return NewScrobbleService(lookup, newLibraryParserStub(t), progress, marker, 0.85, testLog(t)), progress, marker
+}
+
+func TestScrobble_StopPastThresholdMarksWatched(t *testing.T) {
+	svc, progress, marker := newTestScrobbleService(t)
+	ctx := context.Background()
+
+	res, err := svc.Scrobble(ctx, "u1", &domain.ScrobbleRequest{
+		Event: domain.ScrobbleEventPause, MALID: "52991", Episode: 5, Position: 600, Duration: 1440, Client: "mpv",
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.ScrobbleStatusRecorded, res.Status)
+	require.Equal(t, domain.ScrobbleMatchMAL, res.MatchedBy)
+	require.Empty(t, marker.calls)
+
+	// Stop short of the threshold only records progress.
+	res, err = svc.Scrobble(ctx, "u1", &domain.ScrobbleRequest{
+		Event: domain.ScrobbleEventStop, MALID: "52991", Episode: 5, Position: 1200, Duration: 1440, Client: "mpv",
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.ScrobbleStatusRecorded, res.Status)
+	require.Empty(t, marker.calls)
+
+	res, err = svc.Scrobble(ctx, "u1", &domain.ScrobbleRequest{
+		Event: domain.ScrobbleEventStop, MALID: "52991", Episode: 5, Position: 1300, Duration: 1440, Client: "mpv",
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.ScrobbleStatusCompleted, res.Status)
+	require.Equal(t, []domain.MarkEpisodeWatchedRequest{{
+		Episode: 5, Player: domain.ScrobblePlayer, TranslationTitle: "mpv",
+	}}, marker.calls)
+
+	require.Len(t, progress.calls, 3)
+	for _, c := range progress.calls {
+		require.Empty(t, c.Player, "scrobbles must not feed the combo resolver")
+		require.Equal(t, "anime-1", c.AnimeID)
+	}
+}
+
+func TestScrobble_FilenameIdentifiesAnimeAndEpisode(t *testing.T) {
+	svc, progress, _ := newTestScrobbleService(t)
+	ctx := context.Background()
+
+	res, err := svc.Scrobble(ctx, "u1", &domain.ScrobbleRequest{
+		Event:    domain.ScrobbleEventStart,
+		Filename: "[SubsPlease] Sousou no Frieren - 05 (1080p) [ABCD1234].mkv",
+		Duration: 1440,
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.ScrobbleStatusRecorded, res.Status)
+	require.Equal(t, domain.ScrobbleMatchFilename, res.MatchedBy)
+	require.Equal(t, 5, res.Episode)
+	require.Equal(t, 5, progress.calls[0].EpisodeNumber)
+
+	res, err = svc.Scrobble(ctx, "u1", &domain.ScrobbleRequest{
+		Event: domain.ScrobbleEventStart, Filename: "holiday.mp4",
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.ScrobbleStatusUnmatched, res.Status)
+}
+
+func TestScrobble_ServerCompletionAndValidation(t *testing.T) {
+	svc, _, marker := newTestScrobbleService(t)
+	ctx := context.Background()
+
+	// Plex media.scrobble / Jellyfin PlayedToCompletion complete regardless
+	// of the reported position.
+	res, err := svc.Scrobble(ctx, "u1", &domain.ScrobbleRequest{
+		Event: domain.ScrobbleEventStop, Title: "Sousou no Frieren", Episode: 6, Completed: true, Client: "plex",
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.ScrobbleStatusCompleted, res.Status)
+	require.Equal(t, domain.ScrobbleMatchTitle, res.MatchedBy)
+	require.Len(t, marker.calls, 1)
+
+	// The media.stop Plex sends right after media.scrobble is past the
+	// threshold but must not complete the episode a second time.
+	res, err = svc.Scrobble(ctx, "u1", &domain.ScrobbleRequest{
+		Event: domain.ScrobbleEventStop, Title: "Sousou no Frieren", Episode: 6,
+		Position: 1400, Duration: 1440, Client: "plex", ServerCompletes: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.ScrobbleStatusRecorded, res.Status)
+	require.Len(t, marker.calls, 1)
+
+	// Matched, but no episode number anywhere.
+	res, err = svc.Scrobble(ctx, "u1", &domain.ScrobbleRequest{Event: domain.ScrobbleEventStart, AniListID: "154587"})
+	require.NoError(t, err)
+	require.Equal(t, domain.ScrobbleStatusIgnored, res.Status)
+
+	_, err = svc.Scrobble(ctx, "u1", &domain.ScrobbleRequest{Event: "rewind", MALID: "52991", Episode: 1})
+	require.Error(t, err)
+}
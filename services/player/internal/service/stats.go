@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/repo"
+)
+
+// statsRepo is the slice of repo.StatsRepository the stats service needs
+// (narrowed for test fakes).
+type statsRepo interface {
+	ActivityVisibility(ctx context.Context, userID string) string
+	UserTimezone(ctx context.Context, userID string) string
+	Refresh(ctx context.Context, userID string, loc *time.Location, tz string) error
+	Daily(ctx context.Context, userID, sinceDay string, excludeHentai bool) ([]domain.UserStatsDaily, error)
+	AnimeTotals(ctx context.Context, userID string, excludeHentai bool) ([]domain.UserStatsDaily, error)
+	Hourly(ctx context.Context, userID string, excludeHentai bool) ([]domain.UserStatsHourly, error)
+	ListEntries(ctx context.Context, userID string, excludeHentai bool) ([]domain.StatsListEntry, error)
+	Animes(ctx context.Context, animeIDs []string) ([]domain.StatsAnime, error)
+	Genres(ctx context.Context, animeIDs []string) ([]domain.RecapAttribute, error)
+	Studios(ctx context.Context, animeIDs []string) ([]domain.RecapAttribute, error)
+	CommunityScores(ctx context.Context, animeIDs []string) ([]domain.StatsCommunityScore, error)
+	CommunityScoreCounts(ctx context.Context) ([]domain.StatsScoreCount, error)
+}
+
+// StatsService builds the personal statistics page: activity heatmap,
+// hour/weekday distributions, score comparison, time per genre/studio/season
+// and finish-date projections. Every read first folds the user's new
+// watch_history rows into the materialized aggregates (repo.Refresh), then
+// answers from the aggregates only.
+type StatsService struct {
+	repo statsRepo
+	log  *logger.Logger
+	now  func() time.Time
+}
+
+func NewStatsService(r statsRepo, log *logger.Logger) *StatsService {
+	return &StatsService{repo: r, log: log, now: time.Now}
+}
+
+// GetOwn returns the caller's own statistics, 18+ titles included.
+func (s *StatsService) GetOwn(ctx context.Context, userID string) (*domain.UserStats, error) {
+	return s.build(ctx, userID, false)
+}
+
+// GetPublic returns another user's statistics under their activity_visibility
+// setting — the same enforcement as the public watchlist: "none" yields an
+// empty page, "non_hentai" drops 18+ titles from every section.
+func (s *StatsService) GetPublic(ctx context.Context, userID string) (*domain.UserStats, error) {
+	visibility := s.repo.ActivityVisibility(ctx, userID)
+	if visibility == repo.ActivityVisibilityNone {
+		return emptyStats(""), nil
+	}
+	return s.build(ctx, userID, visibility == repo.ActivityVisibilityNonHentai)
+}
+
+func emptyStats(tz string) *domain.UserStats {
+	out := &domain.UserStats{
+		Heatmap:     []domain.StatsDay{},
+		Hours:       make([]domain.StatsTimeSlot, 24),
+		Weekdays:    make([]domain.StatsTimeSlot, 7),
+		Scores:      domain.StatsScores{Distribution: make([]domain.StatsScoreBucket, 10)},
+		Genres:      []domain.StatsShare{},
+		Studios:     []domain.StatsShare{},
+		Seasons:     []domain.StatsShare{},
+		Projections: []domain.StatsProjection{},
+		Timezone:    tz,
+	}
+	for i := range out.Hours {
+		out.Hours[i].Slot = i
+	}
+	for i := range out.Weekdays {
+		out.Weekdays[i].Slot = i
+	}
+	for i := range out.Scores.Distribution {
+		out.Scores.Distribution[i].Score = i + 1
+	}
+	return out
+}
+
+func (s *StatsService) build(ctx context.Context, userID string, excludeHentai bool) (*domain.UserStats, error) {
+	tz := s.repo.UserTimezone(ctx, userID)
+	loc := time.UTC
+	if tz != "" {
+		if l, err := time.LoadLocation(tz); err == nil {
+			loc = l
+		} else {
+			tz = ""
+		}
+	}
+	if err := s.repo.Refresh(ctx, userID, loc, tz); err != nil {
+		return nil, err
+	}
+
+	out := emptyStats(tz)
+	today := s.now().In(loc)
+	heatmapFrom := today.AddDate(0, 0, -(domain.StatsHeatmapDays - 1)).Format("2006-01-02")
+	paceFrom := today.AddDate(0, 0, -(domain.StatsPaceDays - 1)).Format("2006-01-02")
+
+	daily, err := s.repo.Daily(ctx, userID, heatmapFrom, excludeHentai)
+	if err != nil {
+		return nil, err
+	}
+	recentEpisodes := map[string]int{} // anime → episodes inside the pace window
+	paceTotal := 0
+	for _, d := range daily {
+		if n := len(out.Heatmap); n > 0 && out.Heatmap[n-1].Day == d.Day {
+			out.Heatmap[n-1].Minutes += int(d.Seconds / 60)
+			out.Heatmap[n-1].Episodes += d.Episodes
+		} else {
+			out.Heatmap = append(out.Heatmap, domain.StatsDay{Day: d.Day, Minutes: int(d.Seconds / 60), Episodes: d.Episodes})
+		}
+		if d.Day >= paceFrom {
+			recentEpisodes[d.AnimeID] += d.Episodes
+			paceTotal += d.Episodes
+		}
+	}
+	out.MeanEpisodesPerDay = round2(float64(paceTotal) / domain.StatsPaceDays)
+
+	hourly, err := s.repo.Hourly(ctx, userID, excludeHentai)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hourly {
+		if h.Hour >= 0 && h.Hour < 24 {
+			out.Hours[h.Hour].Minutes += int(h.Seconds / 60)
+			out.Hours[h.Hour].Episodes += h.Episodes
+		}
+		if h.Weekday >= 0 && h.Weekday < 7 {
+			out.Weekdays[h.Weekday].Minutes += int(h.Seconds / 60)
+			out.Weekdays[h.Weekday].Episodes += h.Episodes
+		}
+	}
+
+	totals, err := s.repo.AnimeTotals(ctx, userID, excludeHentai)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.ListEntries(ctx, userID, excludeHentai)
+	if err != nil {
+		return nil, err
+	}
+
+	idSet := map[string]struct{}{}
+	watchedIDs := make([]string, 0, len(totals))
+	var totalSeconds int64
+	for _, t := range totals {
+		totalSeconds += t.Seconds
+		out.TotalEpisodes += t.Episodes
+		watchedIDs = append(watchedIDs, t.AnimeID)
+		idSet[t.AnimeID] = struct{}{}
+	}
+	out.TotalMinutes = int(totalSeconds / 60)
+	for _, e := range entries {
+		idSet[e.AnimeID] = struct{}{}
+	}
+	allIDs := make([]string, 0, len(idSet))
+	for id := range idSet {
+		allIDs = append(allIDs, id)
+	}
+	sort.Strings(allIDs)
+
+	animes, err := s.repo.Animes(ctx, allIDs)
+	if err != nil {
+		return nil, err
+	}
+	animeByID := make(map[string]domain.StatsAnime, len(animes))
+	for _, a := range animes {
+		animeByID[a.ID] = a
+	}
+
+	genres, err := s.repo.Genres(ctx, watchedIDs)
+	if err != nil {
+		return nil, err
+	}
+	studios, err := s.repo.Studios(ctx, watchedIDs)
+	if err != nil {
+		return nil, err
+	}
+	out.Genres = statsShares(totals, genres)
+	out.Studios = statsShares(totals, studios)
+	out.Seasons = statsShares(totals, seasonAttributes(animeByID))
+
+	if err := s.fillScores(ctx, out, entries); err != nil {
+		return nil, err
+	}
+	out.Projections = projections(entries, animeByID, recentEpisodes, paceTotal, today)
+	return out, nil
+}
+
+// fillScores builds the score distribution and the user-vs-community means.
+func (s *StatsService) fillScores(ctx context.Context, out *domain.UserStats, entries []domain.StatsListEntry) error {
+	var scoredIDs []string
+	sum := 0
+	for _, e := range entries {
+		if e.Score < 1 || e.Score > 10 {
+			continue
+		}
+		out.Scores.Distribution[e.Score-1].Count++
+		sum += e.Score
+		scoredIDs = append(scoredIDs, e.AnimeID)
+	}
+	out.Scores.Scored = len(scoredIDs)
+	if out.Scores.Scored > 0 {
+		out.Scores.Mean = round2(float64(sum) / float64(out.Scores.Scored))
+		for i := range out.Scores.Distribution {
+			out.Scores.Distribution[i].Share = round4(float64(out.Scores.Distribution[i].Count) / float64(out.Scores.Scored))
+		}
+	}
+
+	counts, err := s.repo.CommunityScoreCounts(ctx)
+	if err != nil {
+		return err
+	}
+	var community int64
+	for _, c := range counts {
+		community += c.Count
+	}
+	for _, c := range counts {
+		if c.Score >= 1 && c.Score <= 10 && community > 0 {
+			out.Scores.Distribution[c.Score-1].CommunityShare = round4(float64(c.Count) / float64(community))
+		}
+	}
+
+	if len(scoredIDs) == 0 {
+		return nil
+	}
+	avgs, err := s.repo.CommunityScores(ctx, scoredIDs)
+	if err != nil {
+		return err
+	}
+	var avgSum float64
+	for _, a := range avgs {
+		avgSum += a.AvgScore
+	}
+	if len(avgs) > 0 {
+		out.Scores.CommunityMean = round2(avgSum / float64(len(avgs)))
+	}
+	return nil
+}
+
+// statsShares sums per-anime watch time into each attribute the anime
+// carries, most-watched first.
+func statsShares(totals []domain.UserStatsDaily, attrs []domain.RecapAttribute) []domain.StatsShare {
+	byAnime := make(map[string]domain.UserStatsDaily, len(totals))
+	for _, t := range totals {
+		byAnime[t.AnimeID] = t
+	}
+	acc := map[string]*domain.StatsShare{}
+	seconds := map[string]int64{}
+	for _, a := range attrs {
+		t, ok := byAnime[a.AnimeID]
+		if !ok {
+			continue
+		}
+		share, ok := acc[a.ID]
+		if !ok {
+			share = &domain.StatsShare{ID: a.ID, Name: a.Name}
+			acc[a.ID] = share
+		}
+		seconds[a.ID] += t.Seconds
+		share.Episodes += t.Episodes
+	}
+	out := make([]domain.StatsShare, 0, len(acc))
+	for id, share := range acc {
+		share.Minutes = int(seconds[id] / 60)
+		out = append(out, *share)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if seconds[out[i].ID] != seconds[out[j].ID] {
+			return seconds[out[i].ID] > seconds[out[j].ID]
+		}
+		if out[i].Episodes != out[j].Episodes {
+			return out[i].Episodes > out[j].Episodes
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// seasonAttributes maps each anime to its "<year>-<season>" airing season.
+func seasonAttributes(animes map[string]domain.StatsAnime) []domain.RecapAttribute {
+	out := make([]domain.RecapAttribute, 0, len(animes))
+	for id, a := range animes {
+		if a.Year == 0 || a.Season == "" {
+			continue
+		}
+		key := strconv.Itoa(a.Year) + "-" + a.Season
+		out = append(out, domain.RecapAttribute{AnimeID: id, ID: key, Name: key})
+	}
+	return out
+}
+
+// projections estimates a finish date for every "watching" title. The pace is
+// the title's own episodes/day over the trailing window, falling back to the
+// user's overall pace when the title wasn't touched recently. Titles still
+// airing are projected against the episodes aired so far.
+func projections(entries []domain.StatsListEntry, animes map[string]domain.StatsAnime, recent map[string]int, paceTotal int, today time.Time) []domain.StatsProjection {
+	out := []domain.StatsProjection{}
+	overall := float64(paceTotal) / domain.StatsPaceDays
+	for _, e := range entries {
+		if e.Status != "watching" {
+			continue
+		}
+		a := animes[e.AnimeID]
+		total := a.EpisodesCount
+		if a.EpisodesAired > 0 && (total == 0 || a.EpisodesAired < total) {
+			total = a.EpisodesAired
+		}
+		p := domain.StatsProjection{
+			AnimeID:         e.AnimeID,
+			Name:            a.Name,
+			NameRU:          a.NameRU,
+			PosterURL:       a.PosterURL,
+			EpisodesWatched: e.Episodes,
+			EpisodesTotal:   total,
+		}
+		if total > e.Episodes {
+			p.EpisodesRemaining = total - e.Episodes
+		}
+		pace := float64(recent[e.AnimeID]) / domain.StatsPaceDays
+		if pace == 0 {
+			pace = overall
+		}
+		p.EpisodesPerDay = round2(pace)
+		if total > 0 && pace > 0 {
+			days := int(math.Ceil(float64(p.EpisodesRemaining) / pace))
+			finish := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location()).AddDate(0, 0, days)
+			p.ProjectedFinish = &finish
+		}
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		fi, fj := out[i].ProjectedFinish, out[j].ProjectedFinish
+		if (fi == nil) != (fj == nil) {
+			return fi != nil
+		}
+		if fi != nil && !fi.Equal(*fj) {
+			return fi.Before(*fj)
+		}
+		return out[i].AnimeID < out[j].AnimeID
+	})
+	return out
+}
+
+func round2(v float64) float64 { return math.Round(v*100) / 100 }
+
+func round4(v float64) float64 { return math.Round(v*10000) / 10000 }
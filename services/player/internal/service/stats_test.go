@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStatsRepo struct {
+	visibility   string
+	daily        []domain.UserStatsDaily
+	hourly       []domain.UserStatsHourly
+	entries      []domain.StatsListEntry
+	animes       []domain.StatsAnime
+	genres       []domain.RecapAttribute
+	community    []domain.StatsCommunityScore
+	scoreCounts  []domain.StatsScoreCount
+	refreshed    int
+	lastExcluded bool
+}
+
+func (f *fakeStatsRepo) ActivityVisibility(context.Context, string) string { return f.visibility }
+func (f *fakeStatsRepo) UserTimezone(context.Context, string) string       { return "" }
+func (f *fakeStatsRepo) Refresh(context.Context, string, *time.Location, string) error {
+	f.refreshed++
+	return nil
+}
+func (f *fakeStatsRepo) Daily(_ context.Context, _ string, since string, excl bool) ([]domain.UserStatsDaily, error) {
+	f.lastExcluded = excl
+	var out []domain.UserStatsDaily
+	for _, d := range f.daily {
+		if d.Day >= since {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+func (f *fakeStatsRepo) AnimeTotals(context.Context, string, bool) ([]domain.UserStatsDaily, error) {
+	acc := map[string]*domain.UserStatsDaily{}
+	var out []domain.UserStatsDaily
+	for _, d := range f.daily {
+		if acc[d.AnimeID] == nil {
+			acc[d.AnimeID] = &domain.UserStatsDaily{AnimeID: d.AnimeID}
+		}
+		acc[d.AnimeID].Seconds += d.Seconds
+		acc[d.AnimeID].Episodes += d.Episodes
+	}
+	for _, v := range acc {
+		out = append(out, *v)
+	}
+	return out, nil
+}
+func (f *fakeStatsRepo) Hourly(context.Context, string, bool) ([]domain.UserStatsHourly, error) {
+	return f.hourly, nil
+}
+func (f *fakeStatsRepo) ListEntries(context.Context, string, bool) ([]domain.StatsListEntry, error) {
+	return f.entries, nil
+}
+func (f *fakeStatsRepo) Animes(context.Context, []string) ([]domain.StatsAnime, error) {
+	return f.animes, nil
+}
+func (f *fakeStatsRepo) Genres(context.Context, []string) ([]domain.RecapAttribute, error) {
+	return f.genres, nil
+}
+func (f *fakeStatsRepo) Studios(context.Context, []string) ([]domain.RecapAttribute, error) {
+	return nil, nil
+}
+func (f *fakeStatsRepo) CommunityScores(context.Context, []string) ([]domain.StatsCommunityScore, error) {
+	return f.community, nil
+}
+func (f *fakeStatsRepo) CommunityScoreCounts(context.Context) ([]domain.StatsScoreCount, error) {
+	return f.scoreCounts, nil
+}
+
+func newTestStatsService(f *fakeStatsRepo) *StatsService {
+	s := NewStatsService(f, logger.Default())
+	s.now = func() time.Time { return at("2026-06-30T12:00:00Z") }
+	return s
+}
+
+func TestStatsService_BuildsSectionsAndProjection(t *testing.T) {
+	f := &fakeStatsRepo{
+		daily: []domain.UserStatsDaily{
+			{Day: "2024-01-01", AnimeID: "old", Seconds: 6000, Episodes: 4}, // outside the heatmap window
+			{Day: "2026-06-20", AnimeID: "a", Seconds: 2880, Episodes: 2},
+			{Day: "2026-06-20", AnimeID: "b", Seconds: 1440, Episodes: 1},
+			{Day: "2026-06-29", AnimeID: "a", Seconds: 5760, Episodes: 4},
+		},
+		hourly: []domain.UserStatsHourly{
+			{Weekday: 6, Hour: 21, Seconds: 3600, Episodes: 3},
+			{Weekday: 1, Hour: 21, Seconds: 1200, Episodes: 1},
+		},
+		entries: []domain.StatsListEntry{
+			{AnimeID: "a", Status: "watching", Score: 8, Episodes: 6},
+			{AnimeID: "b", Status: "completed", Score: 6, Episodes: 12},
+		},
+		animes: []domain.StatsAnime{
+			{ID: "a", Name: "A", Year: 2026, Season: "spring", EpisodesCount: 24},
+			{ID: "b", Name: "B", Year: 2025, Season: "fall", EpisodesCount: 12},
+		},
+		genres: []domain.RecapAttribute{
+			{AnimeID: "a", ID: "g1", Name: "Action"},
+			{AnimeID: "b", ID: "g2", Name: "Drama"},
+		},
+		community:   []domain.StatsCommunityScore{{AnimeID: "a", AvgScore: 7}, {AnimeID: "b", AvgScore: 8}},
+		scoreCounts: []domain.StatsScoreCount{{Score: 8, Count: 3}, {Score: 6, Count: 1}},
+	}
+	stats, err := newTestStatsService(f).GetOwn(context.Background(), "me")
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.refreshed, "every read folds the delta first")
+
+	assert.Equal(t, 11, stats.TotalEpisodes)
+	require.Len(t, stats.Heatmap, 2)
+	assert.Equal(t, domain.StatsDay{Day: "2026-06-20", Minutes: 72, Episodes: 3}, stats.Heatmap[0])
+	assert.Equal(t, 21, stats.Hours[21].Slot)
+	assert.Equal(t, 4, stats.Hours[21].Episodes)
+	assert.Equal(t, 3, stats.Weekdays[6].Episodes)
+
+	require.Len(t, stats.Genres, 2)
+	assert.Equal(t, "g1", stats.Genres[0].ID)
+	assert.Equal(t, 144, stats.Genres[0].Minutes)
+	require.Len(t, stats.Seasons, 2)
+
+	assert.Equal(t, 2, stats.Scores.Scored)
+	assert.InDelta(t, 7.0, stats.Scores.Mean, 1e-9)
+	assert.InDelta(t, 7.5, stats.Scores.CommunityMean, 1e-9)
+	assert.InDelta(t, 0.75, stats.Scores.Distribution[7].CommunityShare, 1e-9)
+	assert.InDelta(t, 0.5, stats.Scores.Distribution[5].Share, 1e-9)
+
+	// 7 recent episodes over a 30-day window; "a" alone did 6 → 0.2/day,
+	// 18 remaining → 90 days out.
+	assert.InDelta(t, 0.23, stats.MeanEpisodesPerDay, 1e-9)
+	require.Len(t, stats.Projections, 1)
+	p := stats.Projections[0]
+	assert.Equal(t, 18, p.EpisodesRemaining)
+	require.NotNil(t, p.ProjectedFinish)
+	assert.Equal(t, "2026-09-28", p.ProjectedFinish.Format("2006-01-02"))
+}
+
+func TestStatsService_ProjectsAgainstAiredEpisodes(t *testing.T) {
+	f := &fakeStatsRepo{
+		entries: []domain.StatsListEntry{{AnimeID: "a", Status: "watching", Episodes: 3}},
+		animes:  []domain.StatsAnime{{ID: "a", EpisodesCount: 12, EpisodesAired: 5}},
+		daily:   []domain.UserStatsDaily{{Day: "2026-06-29", AnimeID: "a", Seconds: 1440, Episodes: 3}},
+	}
+	stats, err := newTestStatsService(f).GetOwn(context.Background(), "me")
+	require.NoError(t, err)
+	require.Len(t, stats.Projections, 1)
+	assert.Equal(t, 5, stats.Projections[0].EpisodesTotal)
+	assert.Equal(t, 2, stats.Projections[0].EpisodesRemaining)
+}
+
+func TestStatsService_PublicRespectsActivityVisibility(t *testing.T) {
+	f := &fakeStatsRepo{
+		visibility: repo.ActivityVisibilityNone,
+		daily:      []domain.UserStatsDaily{{Day: "2026-06-29", AnimeID: "a", Seconds: 1440, Episodes: 1}},
+	}
+	s := newTestStatsService(f)
+	stats, err := s.GetPublic(context.Background(), "u")
+	require.NoError(t, err)
+	assert.Zero(t, stats.TotalEpisodes)
+	assert.Empty(t, stats.Heatmap)
+	assert.Len(t, stats.Hours, 24, "empty page keeps the fixed-size shape")
+
+	f.visibility = repo.ActivityVisibilityNonHentai
+	_, err = s.GetPublic(context.Background(), "u")
+	require.NoError(t, err)
+	assert.True(t, f.lastExcluded, "non_hentai filters 18+ titles")
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/repo"
+)
+
+// UpNextService owns the per-user Up Next queue: manual edits, drag-reorder
+// with version checks for cross-device sync, and the automatic rules
+// (AutoEnqueue on completion, KeepAiring top-up).
+type UpNextService struct {
+	repo *repo.UpNextRepository
+	log  *logger.Logger
+}
+
+func NewUpNextService(r *repo.UpNextRepository, log *logger.Logger) *UpNextService {
+	return &UpNextService{repo: r, log: log}
+}
+
+// Get returns the queue. since is the version the caller already holds; when
+// it is current the response carries Changed=false and no items, so devices
+// can poll cheaply. With KeepAiring on, the rule is applied first so an
+// episode that aired since the last top-up shows up on the next poll;
+// episodes the user removed stay removed (see UpNextRepository.FillAiring).
+func (s *UpNextService) Get(ctx context.Context, userID string, since int64) (*domain.UpNextQueue, error) {
+	state, err := s.repo.GetState(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if state.KeepAiring > 0 {
+		v, err := s.repo.FillAiring(ctx, userID, state.KeepAiring)
+		if err != nil {
+			return nil, err
+		}
+		if v > 0 {
+			state.Version = v
+		}
+	}
+	q := &domain.UpNextQueue{
+		Version:  state.Version,
+		Settings: domain.UpNextSettings{AutoEnqueue: state.AutoEnqueue, KeepAiring: state.KeepAiring},
+	}
+	if since > 0 && since == state.Version {
+		return q, nil
+	}
+	items, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	q.Changed = true
+	q.Items = items
+	return q, nil
+}
+
+// Add enqueues an episode (or moves it when already queued).
+func (s *UpNextService) Add(ctx context.Context, userID string, req *domain.AddUpNextRequest) (*domain.UpNextQueue, error) {
+	req.AnimeID = strings.TrimSpace(req.AnimeID)
+	if req.AnimeID == "" || req.EpisodeNumber < 1 {
+		return nil, errors.InvalidInput("anime_id and a positive episode_number are required")
+	}
+	key := domain.UpNextKey{AnimeID: req.AnimeID, EpisodeNumber: req.EpisodeNumber}
+	if _, err := s.repo.Add(ctx, userID, key, req.Position, domain.UpNextSourceManual); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, userID, 0)
+}
+
+// Remove dequeues one episode.
+func (s *UpNextService) Remove(ctx context.Context, userID string, key domain.UpNextKey) (*domain.UpNextQueue, error) {
+	if _, err := s.repo.Remove(ctx, userID, key); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, userID, 0)
+}
+
+// Clear empties the queue.
+func (s *UpNextService) Clear(ctx context.Context, userID string) (*domain.UpNextQueue, error) {
+	if _, err := s.repo.Clear(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, userID, 0)
+}
+
+// Reorder applies a drag-reorder computed from req.Version. A 409 means
+// another device changed the queue first; the client reloads and retries.
+func (s *UpNextService) Reorder(ctx context.Context, userID string, req *domain.ReorderUpNextRequest) (*domain.UpNextQueue, error) {
+	if _, err := s.repo.Reorder(ctx, userID, req.Version, req.Items); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, userID, 0)
+}
+
+// UpdateSettings stores the queue rules and, when KeepAiring is on, tops the
+// queue up right away so the new rule is visible in the response.
+func (s *UpNextService) UpdateSettings(ctx context.Context, userID string, settings *domain.UpNextSettings) (*domain.UpNextQueue, error) {
+	if settings.KeepAiring < 0 || settings.KeepAiring > domain.UpNextMaxKeepAiring {
+		return nil, errors.InvalidInput("keep_airing must be between 0 and 3")
+	}
+	if _, err := s.repo.SaveSettings(ctx, userID, *settings); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.FillAiring(ctx, userID, settings.KeepAiring); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, userID, 0)
+}
+
+// EpisodeCompleted advances the queue after an episode was marked watched and
+// applies the KeepAiring rule (the watched count just moved). Called from
+// ListService.MarkEpisodeWatched; best-effort — errors are logged, never
+// surfaced to the mark-watched request.
+func (s *UpNextService) EpisodeCompleted(ctx context.Context, userID, animeID string, episode int) {
+	state, err := s.repo.GetState(ctx, userID)
+	if err == nil {
+		_, err = s.repo.Advance(ctx, userID, animeID, episode, state.AutoEnqueue)
+	}
+	if err == nil && state.KeepAiring > 0 {
+		_, err = s.repo.FillAiring(ctx, userID, state.KeepAiring)
+	}
+	if err != nil {
+		s.log.Warnw("up next: failed to advance queue",
+			"user_id", userID, "anime_id", animeID, "episode", episode, "error", err)
+	}
+}
+
+// FillAiringAll applies the KeepAiring rule for every user who has it on, so
+// a newly aired episode reaches the queue even when the user is caught up and
+// not polling. Triggered by the scheduler via /internal/up-next/fill-airing.
+// A single user's failure is logged and skipped; the number of queues that
+// changed is returned.
+func (s *UpNextService) FillAiringAll(ctx context.Context) (int, error) {
+	states, err := s.repo.KeepAiringUsers(ctx)
+	if err != nil {
+		return 0, err
+	}
+	changed := 0
+	for _, st := range states {
+		if ctx.Err() != nil {
+			return changed, ctx.Err()
+		}
+		v, err := s.repo.FillAiring(ctx, st.UserID, st.KeepAiring)
+		if err != nil {
+			s.log.Errorw("up next: failed to fill airing episodes", "user_id", st.UserID, "error", err)
+			continue
+		}
+		if v > 0 {
+			changed++
+		}
+	}
+	s.log.Infow("up next airing sweep complete", "users", len(states), "changed", changed)
+	return changed, nil
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/player/internal/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// upNextTablesSQL are the Up Next tables (raw SQL — mirrors the repo
+// package's setupUpNextTestDB).
+var upNextTablesSQL = []string{
+	`CREATE TABLE up_next_items (
+		user_id TEXT, anime_id TEXT, episode_number INTEGER,
+		position INTEGER NOT NULL DEFAULT 0, source TEXT NOT NULL DEFAULT 'manual', created_at DATETIME,
+		PRIMARY KEY (user_id, anime_id, episode_number)
+	)`,
+	`CREATE TABLE up_next_states (
+		user_id TEXT PRIMARY KEY, version INTEGER NOT NULL DEFAULT 0,
+		auto_enqueue NUMERIC NOT NULL DEFAULT 1, keep_airing INTEGER NOT NULL DEFAULT 0, updated_at DATETIME
+	)`,
+	`CREATE TABLE up_next_dismissals (
+		user_id TEXT, anime_id TEXT, episode_number INTEGER, created_at DATETIME,
+		PRIMARY KEY (user_id, anime_id, episode_number)
+	)`,
+}
+
+// setupUpNextService builds an UpNextService over in-memory SQLite with two
+// released shows (a, b) and one airing show (c, 6 of 24 aired) that u1 is
+// watching with nothing watched yet.
+func setupUpNextService(t *testing.T) (*UpNextService, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	stmts := append([]string{
+		`CREATE TABLE animes (
+			id TEXT PRIMARY KEY, name TEXT, name_ru TEXT, name_jp TEXT, poster_url TEXT,
+			episodes_count INTEGER, episodes_aired INTEGER, status TEXT, deleted_at DATETIME
+		)`,
+		`CREATE TABLE anime_list (
+			user_id TEXT, anime_id TEXT, status TEXT, episodes INTEGER DEFAULT 0, updated_at DATETIME
+		)`,
+		`CREATE TABLE watch_progress (
+			user_id TEXT, anime_id TEXT, episode_number INTEGER, progress INTEGER, duration INTEGER
+		)`,
+		`INSERT INTO animes (id, name, episodes_count, episodes_aired, status) VALUES
+			('a', 'Show A', 12, 0, 'released'),
+			('b', 'Show B', 12, 0, 'released'),
+			('c', 'Airing C', 24, 6, 'ongoing')`,
+		`INSERT INTO anime_list (user_id, anime_id, status, episodes) VALUES ('u1', 'c', 'watching', 0)`,
+	}, upNextTablesSQL...)
+	for _, s := range stmts {
+		require.NoError(t, db.Exec(s).Error)
+	}
+	log, err := logger.New(logger.Config{Level: "error", Development: false})
+	require.NoError(t, err)
+	return NewUpNextService(repo.NewUpNextRepository(db), log), db
+}
+
+func upNextKeys(q *domain.UpNextQueue) []domain.UpNextKey {
+	out := make([]domain.UpNextKey, 0, len(q.Items))
+	for _, it := range q.Items {
+		out = append(out, domain.UpNextKey{AnimeID: it.Anime.ID, EpisodeNumber: it.EpisodeNumber})
+	}
+	return out
+}
+
+func upNextCode(t *testing.T, err error) apperrors.ErrorCode {
+	t.Helper()
+	appErr, ok := apperrors.IsAppError(err)
+	require.True(t, ok, "want an AppError, got %v", err)
+	return appErr.Code
+}
+
+func TestUpNextService_KeepAiring_RemovedEpisodesStayRemoved(t *testing.T) {
+	svc, _ := setupUpNextService(t)
+	ctx := context.Background()
+
+	q, err := svc.UpdateSettings(ctx, "u1", &domain.UpNextSettings{AutoEnqueue: true, KeepAiring: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.UpNextKey{{AnimeID: "c", EpisodeNumber: 1}, {AnimeID: "c", EpisodeNumber: 2}}, upNextKeys(q),
+		"enabling the rule tops the queue up at once")
+
+	q, err = svc.Remove(ctx, "u1", domain.UpNextKey{AnimeID: "c", EpisodeNumber: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.UpNextKey{{AnimeID: "c", EpisodeNumber: 2}}, upNextKeys(q))
+
+	q, err = svc.Get(ctx, "u1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.UpNextKey{{AnimeID: "c", EpisodeNumber: 2}}, upNextKeys(q),
+		"a read must not put a removed rule episode back")
+
+	q, err = svc.Clear(ctx, "u1")
+	require.NoError(t, err)
+	assert.Empty(t, q.Items)
+	q, err = svc.Get(ctx, "u1", 0)
+	require.NoError(t, err)
+	assert.Empty(t, q.Items, "a read must not refill a cleared queue")
+}
+
+func TestUpNextService_GetSinceCurrentVersion(t *testing.T) {
+	svc, _ := setupUpNextService(t)
+	ctx := context.Background()
+
+	q, err := svc.Add(ctx, "u1", &domain.AddUpNextRequest{AnimeID: "a", EpisodeNumber: 1})
+	require.NoError(t, err)
+	require.True(t, q.Changed)
+
+	same, err := svc.Get(ctx, "u1", q.Version)
+	require.NoError(t, err)
+	assert.False(t, same.Changed)
+	assert.Empty(t, same.Items)
+	assert.Equal(t, q.Version, same.Version)
+
+	_, err = svc.Add(ctx, "u1", &domain.AddUpNextRequest{AnimeID: " ", EpisodeNumber: 1})
+	assert.Equal(t, apperrors.CodeInvalidInput, upNextCode(t, err))
+}
+
+func TestUpNextService_Reorder_StaleVersionConflicts(t *testing.T) {
+	svc, _ := setupUpNextService(t)
+	ctx := context.Background()
+
+	_, err := svc.Add(ctx, "u1", &domain.AddUpNextRequest{AnimeID: "a", EpisodeNumber: 1})
+	require.NoError(t, err)
+	q, err := svc.Add(ctx, "u1", &domain.AddUpNextRequest{AnimeID: "b", EpisodeNumber: 1})
+	require.NoError(t, err)
+	base := q.Version
+	swapped := []domain.UpNextKey{{AnimeID: "b", EpisodeNumber: 1}, {AnimeID: "a", EpisodeNumber: 1}}
+
+	// Another device edits the queue first.
+	_, err = svc.Add(ctx, "u1", &domain.AddUpNextRequest{AnimeID: "a", EpisodeNumber: 2})
+	require.NoError(t, err)
+	_, err = svc.Reorder(ctx, "u1", &domain.ReorderUpNextRequest{Version: base, Items: swapped})
+	assert.Equal(t, apperrors.CodeConflict, upNextCode(t, err))
+
+	q, err = svc.Get(ctx, "u1", 0)
+	require.NoError(t, err)
+	q, err = svc.Reorder(ctx, "u1", &domain.ReorderUpNextRequest{
+		Version: q.Version,
+		Items:   append(swapped, domain.UpNextKey{AnimeID: "a", EpisodeNumber: 2}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.UpNextKey{{AnimeID: "b", EpisodeNumber: 1}, {AnimeID: "a", EpisodeNumber: 1}, {AnimeID: "a", EpisodeNumber: 2}}, upNextKeys(q))
+}
+
+// TestListService_MarkEpisodeWatched_AdvancesUpNext drives the queue through
+// the real completion hook: the watched episode leaves the queue and the next
+// one takes its place.
+func TestListService_MarkEpisodeWatched_AdvancesUpNext(t *testing.T) {
+	svc, db := setupListServiceTestDB(t)
+	ctx := context.Background()
+	for _, ddl := range append([]string{
+		`ALTER TABLE animes ADD COLUMN name_ru TEXT`,
+		`ALTER TABLE animes ADD COLUMN name_jp TEXT`,
+		`ALTER TABLE animes ADD COLUMN poster_url TEXT`,
+		`ALTER TABLE animes ADD COLUMN episodes_aired INTEGER DEFAULT 0`,
+		`ALTER TABLE animes ADD COLUMN status TEXT`,
+	}, upNextTablesSQL...) {
+		require.NoError(t, db.Exec(ddl).Error)
+	}
+	log, err := logger.New(logger.Config{Level: "error", Development: false})
+	require.NoError(t, err)
+	upNext := NewUpNextService(repo.NewUpNextRepository(db), log)
+	svc.WithUpNext(upNext)
+
+	now := time.Now()
+	require.NoError(t, db.Exec(`INSERT INTO animes (id, name, episodes_count, status) VALUES ('anime-1', 'Test Anime', 24, 'released'), ('anime-2', 'Other', 12, 'released')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO anime_list (id, user_id, anime_id, status, episodes, created_at, updated_at)
+		VALUES ('al-1', 'user-1', 'anime-1', 'watching', 4, ?, ?)`, now, now).Error)
+	_, err = upNext.Add(ctx, "user-1", &domain.AddUpNextRequest{AnimeID: "anime-1", EpisodeNumber: 5})
+	require.NoError(t, err)
+	_, err = upNext.Add(ctx, "user-1", &domain.AddUpNextRequest{AnimeID: "anime-2", EpisodeNumber: 1})
+	require.NoError(t, err)
+
+	_, err = svc.MarkEpisodeWatched(ctx, "user-1", "anime-1", &domain.MarkEpisodeWatchedRequest{Episode: 5})
+	require.NoError(t, err)
+
+	q, err := upNext.Get(ctx, "user-1", 0)
+	require.NoError(t, err)
+	require.Equal(t, []domain.UpNextKey{{AnimeID: "anime-1", EpisodeNumber: 6}, {AnimeID: "anime-2", EpisodeNumber: 1}}, upNextKeys(q))
+	assert.Equal(t, domain.UpNextSourceAuto, q.Items[0].Source)
+}
+
+// TestUpNextService_KeepAiring_NewlyAiredEpisodeArrives covers a caught-up
+// user: an episode that airs later reaches the queue through the scheduled
+// sweep and through a plain read, without any completion or settings change.
+func TestUpNextService_KeepAiring_NewlyAiredEpisodeArrives(t *testing.T) {
+	svc, db := setupUpNextService(t)
+	ctx := context.Background()
+	require.NoError(t, db.Exec(`UPDATE anime_list SET episodes = 6 WHERE user_id = 'u1' AND anime_id = 'c'`).Error)
+
+	q, err := svc.UpdateSettings(ctx, "u1", &domain.UpNextSettings{AutoEnqueue: true, KeepAiring: 1})
+	require.NoError(t, err)
+	assert.Empty(t, q.Items, "caught up: nothing aired to queue")
+
+	require.NoError(t, db.Exec(`UPDATE animes SET episodes_aired = 7 WHERE id = 'c'`).Error)
+	changed, err := svc.FillAiringAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+
+	polled, err := svc.Get(ctx, "u1", q.Version)
+	require.NoError(t, err)
+	assert.True(t, polled.Changed)
+	assert.Equal(t, []domain.UpNextKey{{AnimeID: "c", EpisodeNumber: 7}}, upNextKeys(polled))
+
+	// Without the sweep, the next read tops the queue up itself.
+	require.NoError(t, db.Exec(`UPDATE anime_list SET episodes = 7 WHERE user_id = 'u1' AND anime_id = 'c'`).Error)
+	svc.EpisodeCompleted(ctx, "u1", "c", 7)
+	require.NoError(t, db.Exec(`UPDATE animes SET episodes_aired = 8 WHERE id = 'c'`).Error)
+	q, err = svc.Get(ctx, "u1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.UpNextKey{{AnimeID: "c", EpisodeNumber: 8}}, upNextKeys(q))
+}
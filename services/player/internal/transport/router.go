@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/database"
 	"github.com/ILITA-hub/animeenigma/libs/httputil"
 	"github.com/ILITA-hub/animeenigma/libs/logger"
 	"github.com/ILITA-hub/animeenigma/libs/metrics"
@@ -32,6 +33,11 @@ func NewRouter(
 	adminReportsHandler *handler.AdminReportsHandler, // admin feedback browser
 	internalListHandler *handler.InternalListHandler, // hero-spotlight v1.0 Phase 3
 	viewerContextHandler *handler.ViewerContextHandler, // anime-page aggregate (page-fetch optimization 2026-06-11)
+	recapHandler *handler.RecapHandler, // yearly "Wrapped" recap
+	statsHandler *handler.StatsHandler, // personal statistics page
+	accountHandler *database.UserDataHandler, // account export/erase fan-out target
+	scrobbleHandler *handler.ScrobbleHandler, // external player / media server scrobbling
+	upNextHandler *handler.UpNextHandler, // personal Up Next queue
 	jwtConfig authz.JWTConfig,
 	log *logger.Logger,
 	metricsCollector *metrics.Collector,
@@ -79,6 +85,27 @@ func NewRouter(
 		r.Patch("/internal/feedback/{id}/status", adminReportsHandler.SetStatusInternal)
 	}
 
+	// Internal recap sweep trigger — POSTed by the scheduler's wrapped_recap
+	// job. Same /internal/* exposure rules as above (not gateway-proxied).
+	if recapHandler != nil {
+		r.Post("/internal/recaps/generate", recapHandler.GenerateInternal)
+	}
+
+	// Internal Up Next KeepAiring top-up — POSTed by the scheduler's
+	// up_next_airing job. Same /internal/* exposure rules as above (not
+	// gateway-proxied).
+	if upNextHandler != nil {
+		r.Post("/internal/up-next/fill-airing", upNextHandler.FillAiringInternal)
+	}
+
+	// Account export + erasure — called by the auth service when a user
+	// downloads their data or deletes their account. Same /internal/*
+	// exposure rules as above.
+	if accountHandler != nil {
+		r.Post("/internal/account/export", accountHandler.Export)
+		r.Post("/internal/account/erase", accountHandler.Erase)
+	}
+
 	// API routes
 	r.Route("/api", func(r chi.Router) {
 		// Protected routes - user data
@@ -167,6 +194,41 @@ func NewRouter(
 				r.Get("/reports", adminReportsHandler.ListMine)
 			}
 
+			// Yearly "Wrapped" recap — owner read + share-link management.
+			// Served from the precomputed user_recaps row (built on demand the
+			// first time when the nightly sweep hasn't reached this user yet).
+			if recapHandler != nil {
+				r.Get("/recap/{year}", recapHandler.GetMyRecap)
+				r.Post("/recap/{year}/share", recapHandler.ShareRecap)
+				r.Delete("/recap/{year}/share", recapHandler.UnshareRecap)
+			}
+
+			// Personal statistics (heatmap, distributions, projections) —
+			// answered from the incrementally folded user_stats_* aggregates.
+			if statsHandler != nil {
+				r.Get("/stats", statsHandler.GetMyStats)
+			}
+
+			// Personal Up Next queue — ordered, cross-anime, versioned so
+			// every device converges on the same next item.
+			if upNextHandler != nil {
+				r.Get("/up-next", upNextHandler.GetQueue)
+				r.Post("/up-next", upNextHandler.Add)
+				r.Delete("/up-next", upNextHandler.Clear)
+				r.Put("/up-next/order", upNextHandler.Reorder)
+				r.Put("/up-next/settings", upNextHandler.UpdateSettings)
+				r.Delete("/up-next/{animeId}/{episode}", upNextHandler.Remove)
+			}
+
+			// Scrobbling from external players and media servers. Clients
+			// authenticate with a personal API key, swapped for a JWT by the
+			// gateway (header, or ?api_key= for Plex/Jellyfin webhooks).
+			if scrobbleHandler != nil {
+				r.Post("/scrobble", scrobbleHandler.Scrobble)
+				r.Post("/scrobble/jellyfin", scrobbleHandler.Jellyfin)
+				r.Post("/scrobble/plex", scrobbleHandler.Plex)
+			}
+
 			// Preference routes
 			r.Get("/preferences/global", preferenceHandler.GetGlobalPreferences)
 			r.Get("/preferences/tier2", preferenceHandler.GetTier2DebugView)
@@ -209,6 +271,9 @@ func NewRouter(
 		r.Get("/users/{userId}/watchlist/public", listHandler.GetPublicWatchlist)
 		r.Get("/users/{userId}/watchlist/public/stats", listHandler.GetPublicWatchlistStats)
 		r.Get("/users/{userId}/watchlist/facets", listHandler.GetPublicWatchlistFacets)
+		if statsHandler != nil {
+			r.Get("/users/{userId}/stats/public", statsHandler.GetPublicStats)
+		}
 
 		// Profile showcase public read (mirrors watchlist/public — lives
 		// OUTSIDE the JWT-protected /users group so anonymous viewers can
@@ -218,6 +283,12 @@ func NewRouter(
 		// Public activity feed
 		r.Get("/activity/feed", activityHandler.GetFeed)
 
+		// Shared recap (public link) — resolves only while the owner keeps
+		// the recap published; the token is the sole capability.
+		if recapHandler != nil {
+			r.Get("/recaps/{token}", recapHandler.GetSharedRecap)
+		}
+
 		// Batch anime ratings (public)
 		r.Post("/anime/ratings/batch", reviewHandler.GetBatchAnimeRatings)
 
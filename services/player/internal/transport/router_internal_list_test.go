@@ -72,6 +72,11 @@ func newPlayerRouter(t *testing.T, internalListHandler *handler.InternalListHand
 		nil, // adminReportsHandler
 		internalListHandler,
 		nil, // viewerContextHandler
+		nil, // recapHandler
+		nil, // statsHandler
+		nil, // accountHandler
+		nil, // scrobbleHandler
+		nil, // upNextHandler
 		zeroJWTConfig(),
 		log,
 		zeroMetricsCollector(t),
@@ -163,9 +163,13 @@ func main() {
 	autocachePredictionJob := jobs.NewAutocachePredictionJob(db.DB, cfg.Jobs.AutocacheActiveWatcherDays, cfg.Jobs.AutocacheAvgRawEpBytes, log)
 	// Daily Fanfic Spotlight — ensure-daily generation trigger.
 	fanficDailyJob := jobs.NewFanficDailyJob(&cfg.Jobs, log)
+	// Yearly "Wrapped" recap sweep trigger (player /internal/recaps/generate).
+	wrappedRecapJob := jobs.NewWrappedRecapJob(&cfg.Jobs, log)
+	// Up Next KeepAiring top-up trigger (player /internal/up-next/fill-airing).
+	upNextAiringJob := jobs.NewUpNextAiringJob(&cfg.Jobs, log)
 
 	// Initialize services
-	jobService := service.NewJobService(shikimoriJob, cleanupJob, topAnimeJob, calendarJob, announcementsJob, probeTriggerJob, readThresholdJob, providerRankingJob, subtitleProbeJob, autocacheLogicAJob, autocachePredictionJob, fanficDailyJob, log)
+	jobService := service.NewJobService(shikimoriJob, cleanupJob, topAnimeJob, calendarJob, announcementsJob, probeTriggerJob, readThresholdJob, providerRankingJob, subtitleProbeJob, autocacheLogicAJob, autocachePredictionJob, fanficDailyJob, wrappedRecapJob, upNextAiringJob, log)
 
 	// Graceful-degradation Phase 3: heavy crons skip their tick while the
 	// governor-published level is Elevated+ (Redis ae:degradation:level;
@@ -207,6 +211,8 @@ func main() {
 		cfg.Jobs.AutocacheLogicACron,
 		cfg.Jobs.AutocachePredictionCron,
 		cfg.Jobs.FanficDailyCron,
+		cfg.Jobs.WrappedRecapCron,
+		cfg.Jobs.UpNextAiringCron,
 	); err != nil {
 		log.Fatalw("failed to start job scheduler", "error", err)
 	}
@@ -119,6 +119,20 @@ type JobsConfig struct {
 	// owns the Groq generation + idempotency check for "already generated today".
 	FanficDailyCron  string
 	FanficServiceURL string
+
+	// Yearly "Wrapped" recap sweep trigger. WrappedRecapCron: cron for the
+	// nightly trigger (default `15 6 * * *`, 06:15 — after the 04:00-05:30
+	// analytics/autocache jobs). PlayerServiceURL: base URL of the in-cluster
+	// player service whose /internal/recaps/generate endpoint this job POSTs;
+	// the player owns watch_history and rebuilds every active user's recap.
+	WrappedRecapCron string
+	PlayerServiceURL string
+
+	// Up Next KeepAiring top-up trigger. UpNextAiringCron: cron for the
+	// trigger (default `40 * * * *`, hourly) that POSTs the player's
+	// /internal/up-next/fill-airing so newly aired episodes reach the queues
+	// of caught-up users. Reuses PlayerServiceURL.
+	UpNextAiringCron string
 }
 
 func Load() (*Config, error) {
@@ -173,6 +187,11 @@ func Load() (*Config, error) {
 			// Daily Fanfic Spotlight — ensure-daily generation trigger.
 			FanficDailyCron:  getEnv("FANFIC_DAILY_CRON", "30 4 * * *"), // Daily 04:30
 			FanficServiceURL: getEnv("FANFIC_SERVICE_URL", "http://fanfic:8097"),
+			// Yearly "Wrapped" recap sweep trigger.
+			WrappedRecapCron: getEnv("WRAPPED_RECAP_CRON", "15 6 * * *"), // Daily 06:15
+			PlayerServiceURL: getEnv("PLAYER_SERVICE_URL", "http://player:8083"),
+			// Up Next KeepAiring top-up trigger.
+			UpNextAiringCron: getEnv("UP_NEXT_AIRING_CRON", "40 * * * *"), // Hourly at :40
 		},
 	}, nil
 }
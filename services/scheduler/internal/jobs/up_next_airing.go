@@ -0,0 +1,65 @@
+// Package jobs — up_next_airing.go fires the Up Next KeepAiring top-up. The
+// player service (port 8083) owns the queue and the watching lists, so this
+// job just POSTs its /internal/up-next/fill-airing endpoint, which appends
+// newly aired episodes to the queue of every user with the rule on. Same
+// HTTP-trigger pattern as wrapped_recap.go.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/scheduler/internal/config"
+)
+
+// upNextAiringReqTimeout caps each POST. The player tops the queues up
+// inline, one short transaction per user with the rule on.
+const upNextAiringReqTimeout = 2 * time.Minute
+
+// UpNextAiringJob triggers the player service's KeepAiring sweep.
+type UpNextAiringJob struct {
+	client *http.Client
+	config *config.JobsConfig
+	log    *logger.Logger
+}
+
+func NewUpNextAiringJob(cfg *config.JobsConfig, log *logger.Logger) *UpNextAiringJob {
+	return &UpNextAiringJob{
+		client: &http.Client{Timeout: upNextAiringReqTimeout},
+		config: cfg,
+		log:    log,
+	}
+}
+
+// Run POSTs /internal/up-next/fill-airing. A non-2xx or transport error is
+// returned so the JobService metrics wrapper records a failure; the top-up
+// skips episodes already queued or dismissed, so a retried run is safe.
+func (j *UpNextAiringJob) Run(ctx context.Context) error {
+	if j.log != nil {
+		j.log.Info("starting up next airing top-up trigger")
+	}
+	url := j.config.PlayerServiceURL + "/internal/up-next/fill-airing"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return fmt.Errorf("build up next fill-airing request: %w", err)
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post up next fill-airing: %w", err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("up next fill-airing returned status %d", resp.StatusCode)
+	}
+	if j.log != nil {
+		j.log.Info("up next airing top-up trigger completed")
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ILITA-hub/animeenigma/services/scheduler/internal/config"
+)
+
+func TestUpNextAiringJob_PostsFillAiring(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	j := NewUpNextAiringJob(&config.JobsConfig{PlayerServiceURL: srv.URL}, nil)
+	if err := j.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(got) != 1 || got[0] != "POST /internal/up-next/fill-airing" {
+		t.Errorf("requests = %v; want one POST /internal/up-next/fill-airing", got)
+	}
+}
+
+func TestUpNextAiringJob_Non2xxIsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	j := NewUpNextAiringJob(&config.JobsConfig{PlayerServiceURL: srv.URL}, nil)
+	if err := j.Run(context.Background()); err == nil {
+		t.Fatal("want error on 500, got nil")
+	}
+}
@@ -0,0 +1,84 @@
+// Package jobs — wrapped_recap.go fires the yearly "Wrapped" recap sweep.
+// The player service (port 8083) owns watch_history + the recap builder, so
+// this job just POSTs its /internal/recaps/generate endpoint, which answers
+// 202 and runs the sweep in the background. Same HTTP-trigger pattern as
+// fanfic_daily.go.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/scheduler/internal/config"
+)
+
+// wrappedRecapReqTimeout caps each POST. The player only validates the year
+// and detaches the sweep, so a short timeout is plenty.
+const wrappedRecapReqTimeout = 30 * time.Second
+
+// WrappedRecapJob triggers the player service's recap sweep.
+type WrappedRecapJob struct {
+	client *http.Client
+	config *config.JobsConfig
+	log    *logger.Logger
+	now    func() time.Time
+}
+
+func NewWrappedRecapJob(cfg *config.JobsConfig, log *logger.Logger) *WrappedRecapJob {
+	return &WrappedRecapJob{
+		client: &http.Client{Timeout: wrappedRecapReqTimeout},
+		config: cfg,
+		log:    log,
+		now:    time.Now,
+	}
+}
+
+// Run POSTs /internal/recaps/generate for the current year. During January it
+// first re-runs the previous year too, so late-December watches land in the
+// final recap before the new year's sweep takes over. A non-2xx or transport
+// error is returned so the JobService metrics wrapper records a failure; the
+// player rebuilds every recap in place, so a retried run is safe.
+func (j *WrappedRecapJob) Run(ctx context.Context) error {
+	if j.log != nil {
+		j.log.Info("starting wrapped recap sweep trigger")
+	}
+	now := j.now()
+	years := []int{now.Year()}
+	if now.Month() == time.January {
+		years = []int{now.Year() - 1, now.Year()}
+	}
+	for _, year := range years {
+		if err := j.trigger(ctx, year); err != nil {
+			return err
+		}
+	}
+	if j.log != nil {
+		j.log.Info("wrapped recap sweep trigger completed")
+	}
+	return nil
+}
+
+func (j *WrappedRecapJob) trigger(ctx context.Context, year int) error {
+	url := j.config.PlayerServiceURL + "/internal/recaps/generate?year=" + strconv.Itoa(year)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return fmt.Errorf("build recap generate request: %w", err)
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post recap generate: %w", err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("recap generate (year %d) returned status %d", year, resp.StatusCode)
+	}
+	return nil
+}
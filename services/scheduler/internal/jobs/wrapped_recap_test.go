@@ -0,0 +1,58 @@
+package jobs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/services/scheduler/internal/config"
+)
+
+func TestWrappedRecapJob_PostsCurrentYear(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.URL.Path+"?"+r.URL.RawQuery)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	j := NewWrappedRecapJob(&config.JobsConfig{PlayerServiceURL: srv.URL}, nil)
+	j.now = func() time.Time { return time.Date(2026, time.June, 1, 6, 0, 0, 0, time.UTC) }
+	if err := j.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(got) != 1 || got[0] != "/internal/recaps/generate?year=2026" {
+		t.Errorf("requests = %v; want one POST for 2026", got)
+	}
+}
+
+func TestWrappedRecapJob_JanuaryFinalizesPreviousYear(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.URL.Query().Get("year"))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	j := NewWrappedRecapJob(&config.JobsConfig{PlayerServiceURL: srv.URL}, nil)
+	j.now = func() time.Time { return time.Date(2027, time.January, 3, 6, 0, 0, 0, time.UTC) }
+	if err := j.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(got) != 2 || got[0] != "2026" || got[1] != "2027" {
+		t.Errorf("years = %v; want [2026 2027]", got)
+	}
+}
+
+func TestWrappedRecapJob_Non2xxIsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	j := NewWrappedRecapJob(&config.JobsConfig{PlayerServiceURL: srv.URL}, nil)
+	if err := j.Run(context.Background()); err == nil {
+		t.Fatal("want error on 500, got nil")
+	}
+}
@@ -25,6 +25,8 @@ type JobService struct {
 	autocacheLogicAJob         *jobs.AutocacheLogicAJob
 	autocachePredictionJob     *jobs.AutocachePredictionJob
 	fanficDailyJob             *jobs.FanficDailyJob
+	wrappedRecapJob            *jobs.WrappedRecapJob
+	upNextAiringJob            *jobs.UpNextAiringJob
 	shed                       shedChecker
 	maint                      *maintenancegate.Client
 	successStore               successStore
@@ -41,6 +43,8 @@ type JobService struct {
 	lastAutocacheLogicARun     time.Time
 	lastAutocachePredictionRun time.Time
 	lastFanficDailyRun         time.Time
+	lastWrappedRecapRun        time.Time
+	lastUpNextAiringRun        time.Time
 }
 
 func NewJobService(
@@ -56,6 +60,8 @@ func NewJobService(
 	autocacheLogicAJob *jobs.AutocacheLogicAJob,
 	autocachePredictionJob *jobs.AutocachePredictionJob,
 	fanficDailyJob *jobs.FanficDailyJob,
+	wrappedRecapJob *jobs.WrappedRecapJob,
+	upNextAiringJob *jobs.UpNextAiringJob,
 	log *logger.Logger,
 ) *JobService {
 	return &JobService{
@@ -76,6 +82,8 @@ func NewJobService(
 		autocacheLogicAJob:     autocacheLogicAJob,
 		autocachePredictionJob: autocachePredictionJob,
 		fanficDailyJob:         fanficDailyJob,
+		wrappedRecapJob:        wrappedRecapJob,
+		upNextAiringJob:        upNextAiringJob,
 		log:                    log,
 	}
 }
@@ -133,7 +141,7 @@ func (s *JobService) postStatus(ctx context.Context, id string, ok bool, summary
 }
 
 // Start starts the job scheduler
-func (s *JobService) Start(shikimoriCron, cleanupCron, topAnimeCron, calendarCron, announcementsCron, playbackProbeCron, readThresholdCron, providerRankingCron, subtitleProbeCron, autocacheLogicACron, autocachePredictionCron, fanficDailyCron string) error {
+func (s *JobService) Start(shikimoriCron, cleanupCron, topAnimeCron, calendarCron, announcementsCron, playbackProbeCron, readThresholdCron, providerRankingCron, subtitleProbeCron, autocacheLogicACron, autocachePredictionCron, fanficDailyCron, wrappedRecapCron, upNextAiringCron string) error {
 	// Schedule Shikimori sync job
 	_, err := s.cron.AddFunc(shikimoriCron, func() {
 		ctx := context.Background()
@@ -479,6 +487,64 @@ func (s *JobService) Start(shikimoriCron, cleanupCron, topAnimeCron, calendarCro
 		s.log.Info("registered job: fanfic_daily")
 	}
 
+	// Schedule the yearly "Wrapped" recap sweep trigger. The player service
+	// owns watch_history + the recap builder and runs the sweep detached, so
+	// this job just POSTs /internal/recaps/generate. The sweep reads every
+	// active user's full year of history, so it skips its tick while the
+	// platform is degraded — the previous night's recaps stay served.
+	if s.wrappedRecapJob != nil {
+		_, err = s.cron.AddFunc(wrappedRecapCron, func() {
+			if s.skipIfDegraded("wrapped_recap") {
+				return
+			}
+			ctx := context.Background()
+			s.log.Info("starting scheduled wrapped recap sweep trigger")
+			start := time.Now()
+			if err := s.wrappedRecapJob.Run(ctx); err != nil {
+				metrics.SchedulerJobExecutionsTotal.WithLabelValues("wrapped_recap", "error").Inc()
+				metrics.SchedulerJobDuration.WithLabelValues("wrapped_recap").Observe(time.Since(start).Seconds())
+				s.log.Errorw("wrapped recap sweep trigger failed", "error", err)
+			} else {
+				metrics.SchedulerJobExecutionsTotal.WithLabelValues("wrapped_recap", "success").Inc()
+				metrics.SchedulerJobDuration.WithLabelValues("wrapped_recap").Observe(time.Since(start).Seconds())
+				s.recordSuccess(ctx, "wrapped_recap")
+				s.lastWrappedRecapRun = time.Now()
+				s.log.Info("wrapped recap sweep trigger completed successfully")
+			}
+		})
+		if err != nil {
+			return err
+		}
+		s.log.Info("registered job: wrapped_recap")
+	}
+
+	// Schedule the Up Next KeepAiring top-up trigger. The player service owns
+	// the queues and watching lists, so this job just POSTs
+	// /internal/up-next/fill-airing; without it a caught-up user's queue only
+	// picks up a newly aired episode on their next read.
+	if s.upNextAiringJob != nil {
+		_, err = s.cron.AddFunc(upNextAiringCron, func() {
+			ctx := context.Background()
+			s.log.Info("starting scheduled up next airing top-up trigger")
+			start := time.Now()
+			if err := s.upNextAiringJob.Run(ctx); err != nil {
+				metrics.SchedulerJobExecutionsTotal.WithLabelValues("up_next_airing", "error").Inc()
+				metrics.SchedulerJobDuration.WithLabelValues("up_next_airing").Observe(time.Since(start).Seconds())
+				s.log.Errorw("up next airing top-up trigger failed", "error", err)
+			} else {
+				metrics.SchedulerJobExecutionsTotal.WithLabelValues("up_next_airing", "success").Inc()
+				metrics.SchedulerJobDuration.WithLabelValues("up_next_airing").Observe(time.Since(start).Seconds())
+				s.recordSuccess(ctx, "up_next_airing")
+				s.lastUpNextAiringRun = time.Now()
+				s.log.Info("up next airing top-up trigger completed successfully")
+			}
+		})
+		if err != nil {
+			return err
+		}
+		s.log.Info("registered job: up_next_airing")
+	}
+
 	s.cron.Start()
 	s.log.Info("job scheduler started")
 	return nil
@@ -595,5 +661,11 @@ func (s *JobService) GetStatus() map[string]interface{} {
 		"fanfic_daily": map[string]interface{}{
 			"last_run": s.lastFanficDailyRun,
 		},
+		"wrapped_recap": map[string]interface{}{
+			"last_run": s.lastWrappedRecapRun,
+		},
+		"up_next_airing": map[string]interface{}{
+			"last_run": s.lastUpNextAiringRun,
+		},
 	}
 }
@@ -26,6 +26,8 @@ var KnownJobs = []string{
 	"autocache_logic_a",
 	"autocache_prediction",
 	"fanfic_daily",
+	"wrapped_recap",
+	"up_next_airing",
 }
 
 // successStore is the narrow persistence surface recordSuccess writes to.
@@ -28,7 +28,7 @@ func TestJobService_RegistersAutocacheLogicA(t *testing.T) {
 	logicA := jobs.NewAutocacheLogicAJob(db, "http://library:8089", 30, logger.Default())
 	prediction := jobs.NewAutocachePredictionJob(db, 30, 1288490188, logger.Default())
 
-	svc := NewJobService(nil, nil, nil, nil, nil, nil, nil, nil, nil, logicA, prediction, nil, logger.Default())
+	svc := NewJobService(nil, nil, nil, nil, nil, nil, nil, nil, nil, logicA, prediction, nil, nil, nil, logger.Default())
 
 	err = svc.Start(
 		farFutureCron, // shikimori
@@ -43,6 +43,8 @@ func TestJobService_RegistersAutocacheLogicA(t *testing.T) {
 		farFutureCron, // autocacheLogicA
 		farFutureCron, // autocachePrediction
 		farFutureCron, // fanficDaily (nil job → skipped)
+		farFutureCron, // wrappedRecap (nil job → skipped)
+		farFutureCron, // upNextAiring (nil job → skipped)
 	)
 	require.NoError(t, err)
 	defer svc.Stop()
@@ -61,7 +63,7 @@ func TestJobService_RegistersAutocachePrediction(t *testing.T) {
 
 	prediction := jobs.NewAutocachePredictionJob(db, 30, 1288490188, logger.Default())
 
-	svc := NewJobService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, prediction, nil, logger.Default())
+	svc := NewJobService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, prediction, nil, nil, nil, logger.Default())
 
 	err = svc.Start(
 		farFutureCron, farFutureCron, farFutureCron, farFutureCron,
@@ -69,6 +71,8 @@ func TestJobService_RegistersAutocachePrediction(t *testing.T) {
 		farFutureCron, farFutureCron, farFutureCron, farFutureCron,
 		farFutureCron, farFutureCron, // autocachePrediction
 		farFutureCron, // fanficDaily (nil job → skipped)
+		farFutureCron, // wrappedRecap (nil job → skipped)
+		farFutureCron, // upNextAiring (nil job → skipped)
 	)
 	require.NoError(t, err)
 	defer svc.Stop()
@@ -82,7 +86,7 @@ func TestJobService_RegistersAutocachePrediction(t *testing.T) {
 // URL configured) is skipped cleanly — Start succeeds and GetStatus still exposes
 // the key (zero last_run) without panicking.
 func TestJobService_NilAutocacheLogicASkipped(t *testing.T) {
-	svc := NewJobService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, logger.Default())
+	svc := NewJobService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, logger.Default())
 
 	err := svc.Start(
 		farFutureCron, farFutureCron, farFutureCron, farFutureCron,
@@ -90,6 +94,8 @@ func TestJobService_NilAutocacheLogicASkipped(t *testing.T) {
 		farFutureCron, farFutureCron, farFutureCron, farFutureCron,
 		farFutureCron, farFutureCron, // autocachePrediction (nil job → skipped)
 		farFutureCron, // fanficDaily (nil job → skipped)
+		farFutureCron, // wrappedRecap (nil job → skipped)
+		farFutureCron, // upNextAiring (nil job → skipped)
 	)
 	require.NoError(t, err)
 	defer svc.Stop()
@@ -105,7 +111,7 @@ func TestJobService_NilAutocacheLogicASkipped(t *testing.T) {
 func TestJobService_RegistersFanficDaily(t *testing.T) {
 	fanficDaily := jobs.NewFanficDailyJob(&config.JobsConfig{FanficServiceURL: "http://fanfic:8097"}, logger.Default())
 
-	svc := NewJobService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, fanficDaily, logger.Default())
+	svc := NewJobService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, fanficDaily, nil, nil, logger.Default())
 
 	err := svc.Start(
 		farFutureCron, farFutureCron, farFutureCron, farFutureCron,
@@ -113,6 +119,8 @@ func TestJobService_RegistersFanficDaily(t *testing.T) {
 		farFutureCron, farFutureCron, farFutureCron, farFutureCron,
 		farFutureCron, farFutureCron,
 		farFutureCron, // fanficDaily
+		farFutureCron, // wrappedRecap (nil job → skipped)
+		farFutureCron, // upNextAiring (nil job → skipped)
 	)
 	require.NoError(t, err)
 	defer svc.Stop()
@@ -121,3 +129,52 @@ func TestJobService_RegistersFanficDaily(t *testing.T) {
 	_, ok := status["fanfic_daily"]
 	assert.True(t, ok, "GetStatus must expose fanfic_daily")
 }
+
+// TestJobService_RegistersWrappedRecap verifies the yearly recap sweep trigger
+// is wired into the cron harness via the new NewJobService/Start arity and
+// surfaces in GetStatus.
+func TestJobService_RegistersWrappedRecap(t *testing.T) {
+	wrappedRecap := jobs.NewWrappedRecapJob(&config.JobsConfig{PlayerServiceURL: "http://player:8083"}, logger.Default())
+
+	svc := NewJobService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, wrappedRecap, nil, logger.Default())
+
+	err := svc.Start(
+		farFutureCron, farFutureCron, farFutureCron, farFutureCron,
+		farFutureCron, // announcementsSync (nil job → skipped)
+		farFutureCron, farFutureCron, farFutureCron, farFutureCron,
+		farFutureCron, farFutureCron,
+		farFutureCron, // fanficDaily (nil job → skipped)
+		farFutureCron, // wrappedRecap
+		farFutureCron, // upNextAiring (nil job → skipped)
+	)
+	require.NoError(t, err)
+	defer svc.Stop()
+
+	status := svc.GetStatus()
+	_, ok := status["wrapped_recap"]
+	assert.True(t, ok, "GetStatus must expose wrapped_recap")
+}
+
+// TestJobService_RegistersUpNextAiring verifies the Up Next KeepAiring top-up
+// trigger is wired into the cron harness and surfaces in GetStatus.
+func TestJobService_RegistersUpNextAiring(t *testing.T) {
+	upNextAiring := jobs.NewUpNextAiringJob(&config.JobsConfig{PlayerServiceURL: "http://player:8083"}, logger.Default())
+
+	svc := NewJobService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, upNextAiring, logger.Default())
+
+	err := svc.Start(
+		farFutureCron, farFutureCron, farFutureCron, farFutureCron,
+		farFutureCron, // announcementsSync (nil job → skipped)
+		farFutureCron, farFutureCron, farFutureCron, farFutureCron,
+		farFutureCron, farFutureCron,
+		farFutureCron, // fanficDaily (nil job → skipped)
+		farFutureCron, // wrappedRecap (nil job → skipped)
+		farFutureCron, // upNextAiring
+	)
+	require.NoError(t, err)
+	defer svc.Stop()
+
+	status := svc.GetStatus()
+	_, ok := status["up_next_airing"]
+	assert.True(t, ok, "GetStatus must expose up_next_airing")
+}
@@ -74,6 +74,7 @@ func Load() (*Config, error) {
 			domain.ClassLibraryAuto:   getEnv("STORAGE_CLASS_LIBRARY_AUTO", domain.BackendS3),
 			domain.ClassLibraryManual: getEnv("STORAGE_CLASS_LIBRARY_MANUAL", domain.BackendMinio),
 			domain.ClassUpscaled:      getEnv("STORAGE_CLASS_UPSCALED", domain.BackendS3),
+			domain.ClassFanficExport:  getEnv("STORAGE_CLASS_FANFIC_EXPORT", domain.BackendMinio),
 		},
 	}, nil
 }
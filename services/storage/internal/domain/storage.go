@@ -15,6 +15,7 @@ const (
 	ClassLibraryAuto   = "library-auto"
 	ClassLibraryManual = "library-manual"
 	ClassUpscaled      = "upscaled"
+	ClassFanficExport  = "fanfic-export"
 )
 
 // IngestURLsRequest is the body of POST /internal/storage/ingest-urls.
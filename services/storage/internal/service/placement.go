@@ -38,7 +38,7 @@ func NewPlacement(defaults map[string]string, s3Absent bool, log *logger.Logger)
 //     any other class rejects a non-empty override.
 func (p *Placement) Resolve(class, override string) (string, error) {
 	switch class {
-	case domain.ClassLibraryAuto, domain.ClassUpscaled, domain.ClassLibraryManual:
+	case domain.ClassLibraryAuto, domain.ClassUpscaled, domain.ClassLibraryManual, domain.ClassFanficExport:
 	default:
 		return "", errors.InvalidInput("unknown content class: " + class)
 	}
@@ -13,6 +13,7 @@ func defaultDefaults() map[string]string {
 		domain.ClassLibraryAuto:   domain.BackendS3,
 		domain.ClassLibraryManual: domain.BackendMinio,
 		domain.ClassUpscaled:      domain.BackendS3,
+		domain.ClassFanficExport:  domain.BackendMinio,
 	}
 }
 
@@ -41,6 +42,17 @@ func TestPlacement_Resolve(t *testing.T) {
 			override:    domain.BackendS3,
 			wantStorage: domain.BackendS3,
 		},
+		{
+			name:        "fanfic-export default resolves to minio",
+			class:       domain.ClassFanficExport,
+			wantStorage: domain.BackendMinio,
+		},
+		{
+			name:     "override on fanfic-export is rejected",
+			class:    domain.ClassFanficExport,
+			override: domain.BackendS3,
+			wantErr:  true,
+		},
 		{
 			name:    "unknown content class is rejected",
 			class:   "bogus-class",
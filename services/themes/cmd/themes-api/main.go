@@ -69,6 +69,17 @@ func main() {
 	if err := db.AutoMigrate(
 		&domain.AnimeTheme{},
 		&domain.ThemeRating{},
+		&domain.ThemePlaylist{},
+		&domain.ThemePlaylistItem{},
+		&domain.ThemeArtist{},
+		&domain.ThemeSong{},
+		&domain.ThemeSongArtist{},
+		&domain.ThemeVideo{},
+		&domain.SyncCursor{},
+		&domain.ThemeTournament{},
+		&domain.ThemeTournamentEntry{},
+		&domain.ThemeTournamentMatch{},
+		&domain.ThemeTournamentVote{},
 	); err != nil {
 		log.Fatalw("failed to migrate database", "error", err)
 	}
@@ -108,17 +119,40 @@ func main() {
 	// Initialize repositories
 	themeRepo := repo.NewThemeRepository(db.DB)
 	ratingRepo := repo.NewRatingRepository(db.DB)
+	playlistRepo := repo.NewPlaylistRepository(db.DB)
+	artistRepo := repo.NewArtistRepository(db.DB)
+	tournamentRepo := repo.NewTournamentRepository(db.DB)
 
 	// Initialize services
 	syncService := service.NewSyncService(themeRepo, atClient, log)
-	themeService := service.NewThemeService(themeRepo, ratingRepo, log)
+	themeService := service.NewThemeService(themeRepo, ratingRepo, artistRepo, log)
 	ratingService := service.NewRatingService(ratingRepo, themeRepo, log)
+	playlistService := service.NewPlaylistService(playlistRepo, themeRepo, log)
+	radioService := service.NewRadioService(themeRepo, redisCache, log)
+	artistService := service.NewArtistService(artistRepo, ratingRepo, log)
+	tournamentNotifier := service.NewTournamentNotifier(cfg.NotificationsURL, cfg.TournamentNotifyEnabled, log)
+	tournamentService := service.NewTournamentService(tournamentRepo, tournamentNotifier, log)
+
+	// Poll the animethemes update feed; the first run without a cursor
+	// backfills the whole catalog.
+	syncCtx, syncCancel := context.WithCancel(context.Background())
+	defer syncCancel()
+	if cfg.IncrementalSyncInterval > 0 {
+		go syncService.RunIncremental(syncCtx, cfg.IncrementalSyncInterval)
+	}
+	// Open and close tournament rounds on their schedule.
+	go tournamentService.Run(syncCtx)
 
 	// Initialize handlers
 	themeHandler := handler.NewThemeHandler(themeService, log)
 	ratingHandler := handler.NewRatingHandler(ratingService, log)
 	adminHandler := handler.NewAdminHandler(syncService, log)
 	videoProxyHandler := handler.NewVideoProxyHandler(log)
+	playlistHandler := handler.NewPlaylistHandler(playlistService, cfg.PublicBaseURL, log)
+	radioHandler := handler.NewRadioHandler(radioService, cfg.PublicBaseURL, log)
+	artistHandler := handler.NewArtistHandler(artistService, log)
+	tournamentHandler := handler.NewTournamentHandler(tournamentService, log)
+	accountHandler := database.NewUserDataHandler(repo.NewAccountRepository(db.DB), log)
 
 	// Initialize metrics collector
 	metricsCollector := metrics.NewCollector("themes")
@@ -129,6 +163,11 @@ func main() {
 		ratingHandler,
 		adminHandler,
 		videoProxyHandler,
+		playlistHandler,
+		radioHandler,
+		artistHandler,
+		tournamentHandler,
+		accountHandler,
 		cfg.JWT,
 		log,
 		metricsCollector,
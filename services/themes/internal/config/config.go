@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ILITA-hub/animeenigma/libs/authz"
@@ -20,6 +21,17 @@ type Config struct {
 	// docker-compose, so no compose change is needed.
 	Redis cache.Config
 	JWT   authz.JWTConfig
+	// PublicBaseURL prefixes the proxied audio URLs of M3U exports
+	// (THEMES_PUBLIC_BASE_URL, no trailing slash).
+	PublicBaseURL string
+	// IncrementalSyncInterval is how often the animethemes update feed is
+	// polled (THEMES_SYNC_INTERVAL; 0 disables the schedule).
+	IncrementalSyncInterval time.Duration
+	// NotificationsURL is the notifications service inside the Docker
+	// network; tournament round starts are posted to its internal upsert.
+	NotificationsURL string
+	// TournamentNotifyEnabled toggles round-start notifications.
+	TournamentNotifyEnabled bool
 }
 
 type ServerConfig struct {
@@ -61,6 +73,10 @@ func Load() (*Config, error) {
 			AccessTokenTTL:  getEnvDuration("JWT_ACCESS_TTL", 15*time.Minute),
 			RefreshTokenTTL: getEnvDuration("JWT_REFRESH_TTL", 7*24*time.Hour),
 		},
+		PublicBaseURL:           strings.TrimRight(getEnv("THEMES_PUBLIC_BASE_URL", "https://animeenigma.org"), "/"),
+		IncrementalSyncInterval: getEnvDuration("THEMES_SYNC_INTERVAL", 6*time.Hour),
+		NotificationsURL:        strings.TrimRight(getEnv("NOTIFICATIONS_INTERNAL_URL", "http://notifications:8090"), "/"),
+		TournamentNotifyEnabled: getEnvBool("THEMES_TOURNAMENT_NOTIFY_ENABLED", true),
 	}, nil
 }
 
@@ -88,3 +104,12 @@ func getEnvDuration(key string, defaultVal time.Duration) time.Duration {
 	}
 	return defaultVal
 }
+
+func getEnvBool(key string, defaultVal bool) bool {
+	if val := os.Getenv(key); val != "" {
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	}
+	return defaultVal
+}
@@ -0,0 +1,114 @@
+package domain
+
+import "time"
+
+// ThemeArtist is a performer as listed on animethemes.
+type ThemeArtist struct {
+	ID         string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ExternalID int       `gorm:"uniqueIndex;not null" json:"external_id"`
+	Name       string    `gorm:"type:text;not null;index" json:"name"`
+	Slug       string    `gorm:"type:text;not null;index" json:"slug"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	// Computed (not stored)
+	ThemeCount int    `gorm:"->;-:migration" json:"theme_count,omitempty"`
+	As         string `gorm:"column:credited_as;->;-:migration" json:"as,omitempty"` // credited character, per song
+}
+
+func (ThemeArtist) TableName() string {
+	return "theme_artists"
+}
+
+// ThemeSong is a song used by one or more themes (a song can be the OP of
+// one anime and the ED of another).
+type ThemeSong struct {
+	ID         string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ExternalID int       `gorm:"uniqueIndex;not null" json:"external_id"`
+	Title      string    `gorm:"type:text" json:"title"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (ThemeSong) TableName() string {
+	return "theme_songs"
+}
+
+// ThemeSongArtist credits an artist on a song, in credit order.
+type ThemeSongArtist struct {
+	SongID   string `gorm:"type:uuid;primaryKey" json:"song_id"`
+	ArtistID string `gorm:"type:uuid;primaryKey;index" json:"artist_id"`
+	Position int    `gorm:"not null" json:"position"`
+	As       string `gorm:"column:credited_as;type:text" json:"as,omitempty"`
+}
+
+func (ThemeSongArtist) TableName() string {
+	return "theme_song_artists"
+}
+
+// ThemeVideo is one encode of a theme. A theme usually has several: creditless
+// (NC) or credited, BD/WEB/DVD source, with or without lyrics, and one per
+// entry version (v1, v2) when the sequence changed mid-season.
+type ThemeVideo struct {
+	ID            string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ExternalID    int       `gorm:"uniqueIndex;not null" json:"external_id"`
+	ThemeID       string    `gorm:"type:uuid;not null;index" json:"theme_id"`
+	Version       int       `json:"version,omitempty"`
+	Episodes      string    `gorm:"type:text" json:"episodes,omitempty"`
+	Basename      string    `gorm:"type:text;not null" json:"basename"`
+	AudioBasename string    `gorm:"type:text" json:"audio_basename,omitempty"`
+	Resolution    int       `json:"resolution"`
+	NC            bool      `gorm:"not null;default:false" json:"nc"`
+	Lyrics        bool      `gorm:"not null;default:false" json:"lyrics"`
+	Subbed        bool      `gorm:"not null;default:false" json:"subbed"`
+	Uncensored    bool      `gorm:"not null;default:false" json:"uncensored"`
+	Source        string    `gorm:"type:text" json:"source,omitempty"`  // "BD", "WEB", "DVD", ...
+	Overlap       string    `gorm:"type:text" json:"overlap,omitempty"` // "None", "Transition", "Over"
+	Tags          string    `gorm:"type:text" json:"tags,omitempty"`    // e.g. "NCBD1080"
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (ThemeVideo) TableName() string {
+	return "theme_videos"
+}
+
+// SyncedTheme is one theme as parsed from animethemes, with its song,
+// credited artists and every video, ready to be stored together.
+type SyncedTheme struct {
+	Theme   AnimeTheme
+	Song    *ThemeSong
+	Artists []ThemeSongArtistCredit
+	Videos  []ThemeVideo
+}
+
+// ThemeSongArtistCredit is a parsed artist with its credit on the song.
+type ThemeSongArtistCredit struct {
+	Artist ThemeArtist
+	As     string
+}
+
+// ArtistPage is an artist with all their themes, rated like the theme list.
+type ArtistPage struct {
+	ThemeArtist
+	Themes []AnimeTheme `json:"themes"`
+}
+
+// ArtistListParams filters the artist index.
+type ArtistListParams struct {
+	Query  string `json:"q"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+// SyncCursor records how far incremental sync has read animethemes' update
+// feed.
+type SyncCursor struct {
+	Name          string    `gorm:"type:text;primaryKey" json:"name"`
+	SyncedThrough time.Time `gorm:"not null" json:"synced_through"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (SyncCursor) TableName() string {
+	return "theme_sync_cursors"
+}
@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// ThemePlaylist is a user's ordered list of OP/ED themes. Public playlists
+// can be opened and cloned by anyone with the link.
+type ThemePlaylist struct {
+	ID          string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID      string    `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name        string    `gorm:"type:text;not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description,omitempty"`
+	IsPublic    bool      `gorm:"not null;default:false" json:"is_public"`
+	ClonedFrom  *string   `gorm:"type:uuid" json:"cloned_from,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Computed (not stored): number of items, scanned by list queries.
+	ItemCount int `gorm:"->;-:migration" json:"item_count"`
+}
+
+func (ThemePlaylist) TableName() string {
+	return "theme_playlists"
+}
+
+// ThemePlaylistItem places a theme at a position of a playlist.
+type ThemePlaylistItem struct {
+	PlaylistID string    `gorm:"type:uuid;primaryKey" json:"playlist_id"`
+	ThemeID    string    `gorm:"type:uuid;primaryKey;index" json:"theme_id"`
+	Position   int       `gorm:"not null" json:"position"`
+	AddedAt    time.Time `json:"added_at"`
+}
+
+func (ThemePlaylistItem) TableName() string {
+	return "theme_playlist_items"
+}
+
+// PlaylistDetail is a playlist with its themes in order.
+type PlaylistDetail struct {
+	ThemePlaylist
+	Themes []AnimeTheme `json:"themes"`
+}
+
+// PlaylistRequest is the create/update body. Nil fields are left unchanged
+// on update.
+type PlaylistRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	IsPublic    *bool   `json:"is_public"`
+}
+
+// Radio seeds.
+const (
+	RadioSeedRatings   = "ratings"   // the user's highly rated themes and their artists
+	RadioSeedCompleted = "completed" // themes of anime the user completed
+	RadioSeedSeason    = "season"    // themes of one season
+)
+
+// RadioParams selects and sizes a radio queue.
+type RadioParams struct {
+	Seed    string `json:"seed"`
+	Year    int    `json:"year,omitempty"`
+	Season  string `json:"season,omitempty"`
+	Limit   int    `json:"limit"`
+	Shuffle int64  `json:"shuffle"` // shuffle seed; the same seed and pool give the same queue
+	UserID  string `json:"-"`
+}
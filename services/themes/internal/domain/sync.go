@@ -6,6 +6,7 @@ type SyncStatus struct {
 	mu        sync.RWMutex
 	Running   bool   `json:"running"`
 	Status    string `json:"status"` // "idle", "syncing", "done", "error"
+	Mode      string `json:"mode,omitempty"`
 	Total     int    `json:"total"`
 	Processed int    `json:"processed"`
 	Error     string `json:"error,omitempty"`
@@ -17,11 +18,21 @@ func NewSyncStatus() *SyncStatus {
 	return &SyncStatus{Status: "idle"}
 }
 
-func (s *SyncStatus) Start(year int, season string) {
+// Sync modes.
+const (
+	SyncModeSeason      = "season"      // one (year, season)
+	SyncModeFull        = "full"        // the whole animethemes catalog
+	SyncModeIncremental = "incremental" // themes updated since the last sync
+)
+
+// Start marks a sync as running. year and season are only set for
+// SyncModeSeason.
+func (s *SyncStatus) Start(mode string, year int, season string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.Running = true
 	s.Status = "syncing"
+	s.Mode = mode
 	s.Total = 0
 	s.Processed = 0
 	s.Error = ""
@@ -35,6 +46,13 @@ func (s *SyncStatus) SetTotal(total int) {
 	s.Total = total
 }
 
+// AddTotal grows the total of a sync whose size is only known page by page.
+func (s *SyncStatus) AddTotal(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Total += n
+}
+
 func (s *SyncStatus) Increment() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -60,6 +78,7 @@ func (s *SyncStatus) SetError(err string) {
 type SyncStatusResponse struct {
 	Running   bool   `json:"running"`
 	Status    string `json:"status"`
+	Mode      string `json:"mode,omitempty"`
 	Total     int    `json:"total"`
 	Processed int    `json:"processed"`
 	Error     string `json:"error,omitempty"`
@@ -73,6 +92,7 @@ func (s *SyncStatus) Get() SyncStatusResponse {
 	return SyncStatusResponse{
 		Running:   s.Running,
 		Status:    s.Status,
+		Mode:      s.Mode,
 		Total:     s.Total,
 		Processed: s.Processed,
 		Error:     s.Error,
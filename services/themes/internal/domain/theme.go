@@ -16,7 +16,8 @@ type AnimeTheme struct {
 	Sequence        int            `json:"sequence"`
 	Slug            string         `gorm:"type:text" json:"slug"` // "OP1", "ED2"
 	SongTitle       string         `gorm:"type:text" json:"song_title,omitempty"`
-	ArtistName      string         `gorm:"type:text" json:"artist_name,omitempty"`
+	ArtistName      string         `gorm:"type:text" json:"artist_name,omitempty"` // display credit; normalized via SongID
+	SongID          *string        `gorm:"type:uuid;index" json:"song_id,omitempty"`
 	VideoBasename   string         `gorm:"type:text" json:"video_basename,omitempty"`
 	VideoResolution int            `json:"video_resolution,omitempty"`
 	AudioBasename   string         `gorm:"type:text" json:"audio_basename,omitempty"`
@@ -32,6 +33,10 @@ type AnimeTheme struct {
 	VoteCount int     `gorm:"->;-:migration" json:"vote_count"`
 	UserScore *int    `gorm:"->;-:migration" json:"user_score,omitempty"`
 	AnimeID   string  `gorm:"->;-:migration" json:"anime_id,omitempty"` // Local catalog anime UUID (joined from animes table)
+
+	// Attached on the detail endpoint only
+	Artists []ThemeArtist `gorm:"-" json:"artists,omitempty"`
+	Videos  []ThemeVideo  `gorm:"-" json:"videos,omitempty"`
 }
 
 func (AnimeTheme) TableName() string {
@@ -64,3 +69,22 @@ type ThemeListParams struct {
 	Limit  int    `json:"limit"`  // page size; clamped to [1, 500], default 100
 	Offset int    `json:"offset"` // page offset; clamped to >= 0
 }
+
+// GuessTheme is one OP/ED with audio, linked to a local catalog anime — an
+// entry of the anidle theme-mode pool served on /internal/guessgame/themes.
+// Field shape MUST match the anidle DTO (services/anidle/internal/domain/theme.go
+// PoolTheme).
+type GuessTheme struct {
+	ID            string `json:"id"`
+	Slug          string `json:"slug"`
+	ThemeType     string `json:"theme_type"`
+	SongTitle     string `json:"song_title"`
+	ArtistName    string `json:"artist_name"`
+	AudioBasename string `json:"audio_basename"`
+	AnimeID       string `json:"anime_id"`
+	AnimeName     string `json:"anime_name"`
+	AnimeNameRU   string `json:"anime_name_ru"`
+	PosterURL     string `json:"poster_url"`
+	Franchise     string `json:"franchise"`
+	Year          int    `json:"year"`
+}
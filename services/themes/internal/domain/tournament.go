@@ -0,0 +1,129 @@
+package domain
+
+import "time"
+
+// Tournament statuses.
+const (
+	TournamentScheduled = "scheduled"
+	TournamentRunning   = "running"
+	TournamentFinished  = "finished"
+	TournamentCancelled = "cancelled"
+)
+
+// How a match was decided.
+const (
+	MatchDecidedVotes = "votes" // more votes
+	MatchDecidedSeed  = "seed"  // tied (or no votes): the better seed advances
+	MatchDecidedBye   = "bye"   // no opponent
+)
+
+// ThemeTournament is a single-elimination bracket of one season's themes,
+// seeded by average score. Every round lasts RoundMinutes; the rounds run
+// back to back from StartsAt.
+type ThemeTournament struct {
+	ID            string     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Title         string     `gorm:"type:text;not null" json:"title"`
+	Year          int        `gorm:"not null;index" json:"year"`
+	Season        string     `gorm:"type:text;not null" json:"season"`
+	ThemeType     string     `gorm:"type:text" json:"theme_type,omitempty"` // "OP", "ED" or "" for both
+	Size          int        `gorm:"not null" json:"size"`                  // bracket size, a power of two
+	Rounds        int        `gorm:"not null" json:"rounds"`
+	RoundMinutes  int        `gorm:"not null" json:"round_minutes"`
+	Status        string     `gorm:"type:text;not null;index" json:"status"`
+	CurrentRound  int        `gorm:"not null;default:0" json:"current_round"`
+	StartsAt      time.Time  `gorm:"not null" json:"starts_at"`
+	WinnerThemeID *string    `gorm:"type:uuid" json:"winner_theme_id,omitempty"`
+	CreatedBy     string     `gorm:"type:uuid;not null" json:"created_by"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (ThemeTournament) TableName() string {
+	return "theme_tournaments"
+}
+
+// ThemeTournamentEntry is a seeded theme of a tournament. SeedScore and
+// SeedVotes freeze the rating the seed was computed from.
+type ThemeTournamentEntry struct {
+	TournamentID string  `gorm:"type:uuid;primaryKey" json:"tournament_id"`
+	ThemeID      string  `gorm:"type:uuid;primaryKey" json:"theme_id"`
+	Seed         int     `gorm:"not null" json:"seed"`
+	SeedScore    float64 `gorm:"not null" json:"seed_score"`
+	SeedVotes    int     `gorm:"not null" json:"seed_votes"`
+
+	// Computed (not stored)
+	Theme *AnimeTheme `gorm:"-" json:"theme,omitempty"`
+}
+
+func (ThemeTournamentEntry) TableName() string {
+	return "theme_tournament_entries"
+}
+
+// ThemeTournamentMatch is one head-to-head of the bracket. Slot numbers the
+// match within its round; the winners of slots 2k and 2k+1 meet in slot k
+// of the next round. A nil theme is a bye (round 1) or a winner not decided
+// yet (later rounds). Vote totals are stored when the match closes; votes
+// are blind while it is open.
+type ThemeTournamentMatch struct {
+	ID            string     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TournamentID  string     `gorm:"type:uuid;not null;uniqueIndex:idx_tournament_round_slot" json:"tournament_id"`
+	Round         int        `gorm:"not null;uniqueIndex:idx_tournament_round_slot" json:"round"`
+	Slot          int        `gorm:"not null;uniqueIndex:idx_tournament_round_slot" json:"slot"`
+	ThemeAID      *string    `gorm:"type:uuid" json:"theme_a_id,omitempty"`
+	ThemeBID      *string    `gorm:"type:uuid" json:"theme_b_id,omitempty"`
+	VotesA        int        `gorm:"not null;default:0" json:"votes_a"`
+	VotesB        int        `gorm:"not null;default:0" json:"votes_b"`
+	WinnerThemeID *string    `gorm:"type:uuid" json:"winner_theme_id,omitempty"`
+	DecidedBy     string     `gorm:"type:text" json:"decided_by,omitempty"`
+	StartsAt      *time.Time `json:"starts_at,omitempty"`
+	EndsAt        *time.Time `json:"ends_at,omitempty"`
+
+	// Computed (not stored): the caller's vote.
+	MyVote *string `gorm:"-" json:"my_vote,omitempty"`
+}
+
+func (ThemeTournamentMatch) TableName() string {
+	return "theme_tournament_matches"
+}
+
+// Open reports whether the match takes votes at t.
+func (m *ThemeTournamentMatch) Open(t time.Time) bool {
+	return m.WinnerThemeID == nil && m.ThemeAID != nil && m.ThemeBID != nil &&
+		m.StartsAt != nil && m.EndsAt != nil && !t.Before(*m.StartsAt) && t.Before(*m.EndsAt)
+}
+
+// ThemeTournamentVote is one user's vote in one match.
+type ThemeTournamentVote struct {
+	MatchID   string    `gorm:"type:uuid;primaryKey" json:"match_id"`
+	UserID    string    `gorm:"type:uuid;primaryKey;index" json:"user_id"`
+	ThemeID   string    `gorm:"type:uuid;not null" json:"theme_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (ThemeTournamentVote) TableName() string {
+	return "theme_tournament_votes"
+}
+
+// TournamentRequest is the admin create body.
+type TournamentRequest struct {
+	Title        string    `json:"title"`
+	Year         int       `json:"year"`
+	Season       string    `json:"season"`
+	ThemeType    string    `json:"theme_type"` // "op", "ed" or "" for both
+	Size         int       `json:"size"`       // max entries: 4, 8, 16, 32 or 64
+	RoundMinutes int       `json:"round_minutes"`
+	StartsAt     time.Time `json:"starts_at"`
+}
+
+// TournamentDetail is a tournament with its seeded entries and bracket.
+type TournamentDetail struct {
+	ThemeTournament
+	Entries []ThemeTournamentEntry `json:"entries"`
+	Matches []ThemeTournamentMatch `json:"matches"`
+}
+
+// VoteRequest is the match vote body.
+type VoteRequest struct {
+	ThemeID string `json:"theme_id"`
+}
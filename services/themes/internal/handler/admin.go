@@ -6,6 +6,7 @@ import (
 
 	"github.com/ILITA-hub/animeenigma/libs/httputil"
 	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
 	"github.com/ILITA-hub/animeenigma/services/themes/internal/service"
 )
 
@@ -21,8 +22,32 @@ func NewAdminHandler(syncService *service.SyncService, log *logger.Logger) *Admi
 	}
 }
 
-// TriggerSync handles POST /api/themes/admin/sync?year=2026&season=winter
+// TriggerSync handles POST /api/themes/admin/sync?year=2026&season=winter.
+// mode=full backfills the whole catalog; mode=incremental reads the update
+// feed since the last sync.
 func (h *AdminHandler) TriggerSync(w http.ResponseWriter, r *http.Request) {
+	var err error
+	switch r.URL.Query().Get("mode") {
+	case "", domain.SyncModeSeason:
+		err = h.startSeasonSync(r)
+	case domain.SyncModeFull:
+		err = h.syncService.StartBackfill()
+	case domain.SyncModeIncremental:
+		err = h.syncService.StartIncremental()
+	default:
+		httputil.BadRequest(w, "mode must be season, full or incremental")
+		return
+	}
+	if err != nil {
+		httputil.BadRequest(w, err.Error())
+		return
+	}
+
+	status := h.syncService.GetStatus()
+	httputil.OK(w, status)
+}
+
+func (h *AdminHandler) startSeasonSync(r *http.Request) error {
 	year := 0
 	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
 		if y, err := strconv.Atoi(yearStr); err == nil {
@@ -31,13 +56,7 @@ func (h *AdminHandler) TriggerSync(w http.ResponseWriter, r *http.Request) {
 	}
 	season := r.URL.Query().Get("season")
 
-	if err := h.syncService.StartSync(year, season); err != nil {
-		httputil.BadRequest(w, err.Error())
-		return
-	}
-
-	status := h.syncService.GetStatus()
-	httputil.OK(w, status)
+	return h.syncService.StartSync(year, season)
 }
 
 // GetSyncStatus handles GET /api/themes/admin/sync/status
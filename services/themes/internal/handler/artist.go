@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type ArtistHandler struct {
+	artistService *service.ArtistService
+	log           *logger.Logger
+}
+
+func NewArtistHandler(artistService *service.ArtistService, log *logger.Logger) *ArtistHandler {
+	return &ArtistHandler{
+		artistService: artistService,
+		log:           log,
+	}
+}
+
+// ListArtists handles GET /api/themes/artists?q=&limit=&offset=
+func (h *ArtistHandler) ListArtists(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	params := domain.ArtistListParams{
+		Query: q.Get("q"),
+		Limit: defaultListLimit,
+	}
+	if n, err := strconv.Atoi(q.Get("limit")); err == nil && n > 0 {
+		if n > maxListLimit {
+			n = maxListLimit
+		}
+		params.Limit = n
+	}
+	if n, err := strconv.Atoi(q.Get("offset")); err == nil && n > 0 {
+		params.Offset = n
+	}
+
+	artists, err := h.artistService.List(r.Context(), params)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	if artists == nil {
+		artists = []domain.ThemeArtist{}
+	}
+	httputil.OK(w, artists)
+}
+
+// GetArtist handles GET /api/themes/artists/{slug}
+func (h *ArtistHandler) GetArtist(w http.ResponseWriter, r *http.Request) {
+	page, err := h.artistService.Page(r.Context(), chi.URLParam(r, "slug"), viewerID(r))
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	httputil.OK(w, page)
+}
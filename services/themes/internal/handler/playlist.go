@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// m3uContentType is served for .m3u playlist downloads.
+const m3uContentType = "audio/x-mpegurl"
+
+type PlaylistHandler struct {
+	playlistService *service.PlaylistService
+	publicBaseURL   string
+	log             *logger.Logger
+}
+
+func NewPlaylistHandler(playlistService *service.PlaylistService, publicBaseURL string, log *logger.Logger) *PlaylistHandler {
+	return &PlaylistHandler{
+		playlistService: playlistService,
+		publicBaseURL:   publicBaseURL,
+		log:             log,
+	}
+}
+
+type addPlaylistItemRequest struct {
+	ThemeID string `json:"theme_id"`
+}
+
+type reorderPlaylistRequest struct {
+	ThemeIDs []string `json:"theme_ids"`
+}
+
+type clonePlaylistRequest struct {
+	Name string `json:"name"`
+}
+
+// CreatePlaylist handles POST /api/themes/playlists
+func (h *PlaylistHandler) CreatePlaylist(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+
+	var req domain.PlaylistRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	playlist, err := h.playlistService.Create(r.Context(), claims.UserID, req)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	httputil.Created(w, playlist)
+}
+
+// ListMyPlaylists handles GET /api/themes/playlists
+func (h *PlaylistHandler) ListMyPlaylists(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+
+	playlists, err := h.playlistService.ListMine(r.Context(), claims.UserID)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	if playlists == nil {
+		playlists = []domain.ThemePlaylist{}
+	}
+	httputil.OK(w, playlists)
+}
+
+// GetPlaylist handles GET /api/themes/playlists/{playlistID}
+func (h *PlaylistHandler) GetPlaylist(w http.ResponseWriter, r *http.Request) {
+	playlist, err := h.playlistService.Get(r.Context(), chi.URLParam(r, "playlistID"), viewerID(r))
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	httputil.OK(w, playlist)
+}
+
+// ExportPlaylistM3U handles GET /api/themes/playlists/{playlistID}/m3u
+func (h *PlaylistHandler) ExportPlaylistM3U(w http.ResponseWriter, r *http.Request) {
+	playlist, err := h.playlistService.Get(r.Context(), chi.URLParam(r, "playlistID"), viewerID(r))
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	writeM3U(w, h.log, h.publicBaseURL, playlist.Name, "playlist-"+playlist.ID+".m3u", playlist.Themes)
+}
+
+// UpdatePlaylist handles PATCH /api/themes/playlists/{playlistID}
+func (h *PlaylistHandler) UpdatePlaylist(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+
+	var req domain.PlaylistRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	playlist, err := h.playlistService.Update(r.Context(), chi.URLParam(r, "playlistID"), claims.UserID, req)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	httputil.OK(w, playlist)
+}
+
+// DeletePlaylist handles DELETE /api/themes/playlists/{playlistID}
+func (h *PlaylistHandler) DeletePlaylist(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+
+	if err := h.playlistService.Delete(r.Context(), chi.URLParam(r, "playlistID"), claims.UserID); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	httputil.NoContent(w)
+}
+
+// AddPlaylistItem handles POST /api/themes/playlists/{playlistID}/items
+func (h *PlaylistHandler) AddPlaylistItem(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+
+	var req addPlaylistItemRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	if req.ThemeID == "" {
+		httputil.BadRequest(w, "missing theme id")
+		return
+	}
+
+	if err := h.playlistService.AddItem(r.Context(), chi.URLParam(r, "playlistID"), claims.UserID, req.ThemeID); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	httputil.NoContent(w)
+}
+
+// RemovePlaylistItem handles DELETE /api/themes/playlists/{playlistID}/items/{themeID}
+func (h *PlaylistHandler) RemovePlaylistItem(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+
+	err := h.playlistService.RemoveItem(r.Context(), chi.URLParam(r, "playlistID"), claims.UserID, chi.URLParam(r, "themeID"))
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	httputil.NoContent(w)
+}
+
+// ReorderPlaylist handles PUT /api/themes/playlists/{playlistID}/order
+func (h *PlaylistHandler) ReorderPlaylist(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+
+	var req reorderPlaylistRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	if err := h.playlistService.Reorder(r.Context(), chi.URLParam(r, "playlistID"), claims.UserID, req.ThemeIDs); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	httputil.NoContent(w)
+}
+
+// ClonePlaylist handles POST /api/themes/playlists/{playlistID}/clone
+func (h *PlaylistHandler) ClonePlaylist(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+
+	// The body is optional: an empty one keeps the source name.
+	var req clonePlaylistRequest
+	if r.ContentLength > 0 {
+		if err := httputil.Bind(r, &req); err != nil {
+			httputil.Error(w, err)
+			return
+		}
+	}
+
+	playlist, err := h.playlistService.Clone(r.Context(), chi.URLParam(r, "playlistID"), claims.UserID, req.Name)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	httputil.Created(w, playlist)
+}
+
+// viewerID returns the caller's user ID on optional-auth routes ("" for guests).
+func viewerID(r *http.Request) string {
+	claims, _ := authz.ClaimsFromContext(r.Context())
+	if claims == nil {
+		return ""
+	}
+	return claims.UserID
+}
+
+// writeM3U serves themes as a downloadable M3U playlist.
+func writeM3U(w http.ResponseWriter, log *logger.Logger, baseURL, title, filename string, themes []domain.AnimeTheme) {
+	w.Header().Set("Content-Type", m3uContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	if err := service.WriteM3U(w, baseURL, title, themes); err != nil {
+		log.Warnw("failed to write m3u", "error", err)
+	}
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/service"
+)
+
+type RadioHandler struct {
+	radioService  *service.RadioService
+	publicBaseURL string
+	log           *logger.Logger
+}
+
+func NewRadioHandler(radioService *service.RadioService, publicBaseURL string, log *logger.Logger) *RadioHandler {
+	return &RadioHandler{
+		radioService:  radioService,
+		publicBaseURL: publicBaseURL,
+		log:           log,
+	}
+}
+
+// parseRadioParams extracts the GET /api/themes/radio query parameters.
+// Invalid numbers are ignored; the service applies the limit defaults.
+func parseRadioParams(r *http.Request) domain.RadioParams {
+	q := r.URL.Query()
+	params := domain.RadioParams{
+		Seed:   q.Get("seed"),
+		Season: q.Get("season"),
+		UserID: viewerID(r),
+	}
+	if y, err := strconv.Atoi(q.Get("year")); err == nil {
+		params.Year = y
+	}
+	if n, err := strconv.Atoi(q.Get("limit")); err == nil {
+		params.Limit = n
+	}
+	if s, err := strconv.ParseInt(q.Get("shuffle"), 10, 64); err == nil {
+		params.Shuffle = s
+	}
+	return params
+}
+
+// Radio handles GET /api/themes/radio
+func (h *RadioHandler) Radio(w http.ResponseWriter, r *http.Request) {
+	queue, err := h.radioService.Queue(r.Context(), parseRadioParams(r))
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	httputil.OK(w, queue)
+}
+
+// RadioM3U handles GET /api/themes/radio/m3u
+func (h *RadioHandler) RadioM3U(w http.ResponseWriter, r *http.Request) {
+	params := parseRadioParams(r)
+	queue, err := h.radioService.Queue(r.Context(), params)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	writeM3U(w, h.log, h.publicBaseURL, "AnimeEnigma radio: "+params.Seed, "radio-"+params.Seed+".m3u", queue)
+}
@@ -112,3 +112,18 @@ func (h *ThemeHandler) GetTheme(w http.ResponseWriter, r *http.Request) {
 
 	httputil.OK(w, theme)
 }
+
+// GuessPool handles GET /internal/guessgame/themes (Docker-network only; NOT
+// proxied by the gateway) — the anidle theme-mode pool.
+func (h *ThemeHandler) GuessPool(w http.ResponseWriter, r *http.Request) {
+	themes, err := h.themeService.GuessPool(r.Context())
+	if err != nil {
+		h.log.Errorw("failed to build guess pool", "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	if themes == nil {
+		themes = []domain.GuessTheme{}
+	}
+	httputil.OK(w, themes)
+}
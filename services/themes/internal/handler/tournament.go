@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type TournamentHandler struct {
+	tournamentService *service.TournamentService
+	log               *logger.Logger
+}
+
+func NewTournamentHandler(tournamentService *service.TournamentService, log *logger.Logger) *TournamentHandler {
+	return &TournamentHandler{
+		tournamentService: tournamentService,
+		log:               log,
+	}
+}
+
+// ListTournaments handles GET /api/themes/tournaments?status=finished
+func (h *TournamentHandler) ListTournaments(w http.ResponseWriter, r *http.Request) {
+	tournaments, err := h.tournamentService.List(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	if tournaments == nil {
+		tournaments = []domain.ThemeTournament{}
+	}
+	httputil.OK(w, tournaments)
+}
+
+// GetTournament handles GET /api/themes/tournaments/{tournamentID}
+func (h *TournamentHandler) GetTournament(w http.ResponseWriter, r *http.Request) {
+	tournament, err := h.tournamentService.Get(r.Context(), chi.URLParam(r, "tournamentID"), viewerID(r))
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	httputil.OK(w, tournament)
+}
+
+// VoteMatch handles POST /api/themes/tournaments/{tournamentID}/matches/{matchID}/vote
+func (h *TournamentHandler) VoteMatch(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+
+	var req domain.VoteRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	if req.ThemeID == "" {
+		httputil.BadRequest(w, "missing theme id")
+		return
+	}
+
+	err := h.tournamentService.Vote(r.Context(), chi.URLParam(r, "tournamentID"), chi.URLParam(r, "matchID"), claims.UserID, req.ThemeID)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	httputil.NoContent(w)
+}
+
+// CreateTournament handles POST /api/themes/admin/tournaments
+func (h *TournamentHandler) CreateTournament(w http.ResponseWriter, r *http.Request) {
+	claims, ok := authz.ClaimsFromContext(r.Context())
+	if !ok || claims == nil {
+		httputil.Unauthorized(w)
+		return
+	}
+
+	var req domain.TournamentRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	tournament, err := h.tournamentService.Create(r.Context(), claims.UserID, req)
+	if err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	httputil.Created(w, tournament)
+}
+
+// CancelTournament handles POST /api/themes/admin/tournaments/{tournamentID}/cancel
+func (h *TournamentHandler) CancelTournament(w http.ResponseWriter, r *http.Request) {
+	if err := h.tournamentService.Cancel(r.Context(), chi.URLParam(r, "tournamentID")); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+
+	httputil.NoContent(w)
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,15 +16,28 @@ import (
 const (
 	baseURL      = "https://api.animethemes.moe"
 	requestDelay = 700 * time.Millisecond // Rate limit: ~90 req/min
+
+	// animeInclude is the include list for /anime queries: everything a
+	// SyncedTheme needs in one request.
+	animeInclude = "animethemes.song.artists,animethemes.animethemeentries.videos.audio,images,resources"
+	// themeInclude is the same graph seen from /animetheme.
+	themeInclude = "anime.images,anime.resources,song.artists,animethemeentries.videos.audio"
+	// themeBatchSize is how many theme ids one /animetheme?filter[id] asks
+	// for (the API's page size).
+	themeBatchSize = 25
 )
 
 type Client struct {
+	baseURL    string
+	delay      time.Duration // between requests
 	httpClient *http.Client
 	log        *logger.Logger
 }
 
 func NewClient(log *logger.Logger) *Client {
 	return &Client{
+		baseURL:    baseURL,
+		delay:      requestDelay,
 		httpClient: &http.Client{Timeout: 30 * time.Second},
 		log:        log,
 	}
@@ -30,8 +45,12 @@ func NewClient(log *logger.Logger) *Client {
 
 // apiResponse represents the top-level AnimeThemes API response.
 type apiResponse struct {
-	Anime []animeData     `json:"anime"`
-	Links paginationLinks `json:"links"`
+	Anime       []animeData     `json:"anime"`
+	AnimeThemes []themeData     `json:"animethemes"`
+	Songs       []songData      `json:"songs"`
+	Artists     []artistData    `json:"artists"`
+	Videos      []videoData     `json:"videos"`
+	Links       paginationLinks `json:"links"`
 }
 
 type paginationLinks struct {
@@ -42,6 +61,8 @@ type animeData struct {
 	ID          int            `json:"id"`
 	Name        string         `json:"name"`
 	Slug        string         `json:"slug"`
+	Year        int            `json:"year"`
+	Season      *string        `json:"season"` // "Winter", "Spring", ...
 	Images      []imageData    `json:"images"`
 	AnimeThemes []themeData    `json:"animethemes"`
 	Resources   []resourceData `json:"resources"`
@@ -58,35 +79,61 @@ type imageData struct {
 }
 
 type themeData struct {
-	ID                int          `json:"id"`
-	Type              string       `json:"type"`   // "OP" or "ED"
-	Sequence          *int         `json:"sequence"`
-	Slug              string       `json:"slug"`   // "OP1", "ED2"
-	Song              *songData    `json:"song"`
-	AnimeThemeEntries []entryData  `json:"animethemeentries"`
+	ID                int         `json:"id"`
+	Type              string      `json:"type"` // "OP" or "ED"
+	Sequence          *int        `json:"sequence"`
+	Slug              string      `json:"slug"` // "OP1", "ED2"
+	UpdatedAt         string      `json:"updated_at"`
+	Song              *songData   `json:"song"`
+	AnimeThemeEntries []entryData `json:"animethemeentries"`
+	Anime             *animeData  `json:"anime"` // set on /animetheme queries
 }
 
 type songData struct {
-	Title   string       `json:"title"`
-	Artists []artistData `json:"artists"`
+	ID          int          `json:"id"`
+	Title       string       `json:"title"`
+	UpdatedAt   string       `json:"updated_at"`
+	Artists     []artistData `json:"artists"`
+	AnimeThemes []themeData  `json:"animethemes"` // set on /song queries
 }
 
 type artistData struct {
-	Name string `json:"name"`
-	Slug string `json:"slug"`
+	ID         int             `json:"id"`
+	Name       string          `json:"name"`
+	Slug       string          `json:"slug"`
+	UpdatedAt  string          `json:"updated_at"`
+	ArtistSong *artistSongData `json:"artistsong"`
+	Songs      []songData      `json:"songs"` // set on /artist queries
+}
+
+// artistSongData is the artist↔song pivot: the character the artist sang as.
+type artistSongData struct {
+	As *string `json:"as"`
 }
 
 type entryData struct {
-	Videos []videoData `json:"videos"`
+	Version    *int        `json:"version"`
+	Episodes   *string     `json:"episodes"`
+	Videos     []videoData `json:"videos"`
+	AnimeTheme *themeData  `json:"animetheme"` // set on /video queries
 }
 
 type videoData struct {
-	ID         int    `json:"id"`
-	Basename   string `json:"basename"`
-	Resolution int    `json:"resolution"`
-	NC         bool   `json:"nc"` // creditless
-	Link       string `json:"link"`
+	ID         int        `json:"id"`
+	Basename   string     `json:"basename"`
+	Resolution int        `json:"resolution"`
+	NC         bool       `json:"nc"` // creditless
+	Lyrics     bool       `json:"lyrics"`
+	Subbed     bool       `json:"subbed"`
+	Uncen      bool       `json:"uncen"`
+	Source     *string    `json:"source"`
+	Overlap    string     `json:"overlap"`
+	Tags       string     `json:"tags"`
+	Link       string     `json:"link"`
+	UpdatedAt  string     `json:"updated_at"`
 	Audio      *audioData `json:"audio"`
+
+	AnimeThemeEntries []entryData `json:"animethemeentries"` // set on /video queries
 }
 
 type audioData struct {
@@ -95,56 +142,261 @@ type audioData struct {
 
 // FetchSeason fetches all anime themes for a given year and season.
 // It paginates through all results automatically.
-func (c *Client) FetchSeason(year int, season string) ([]domain.AnimeTheme, error) {
-	var allThemes []domain.AnimeTheme
+func (c *Client) FetchSeason(year int, season string) ([]domain.SyncedTheme, error) {
+	var allThemes []domain.SyncedTheme
+
+	q := url.Values{}
+	q.Set("filter[year]", fmt.Sprint(year))
+	q.Set("filter[season]", season)
+	q.Set("include", animeInclude)
+	q.Set("page[size]", "25")
+	q.Set("page[number]", "1")
+
+	err := c.paginate(c.baseURL+"/anime?"+q.Encode(), func(page apiResponse) (bool, error) {
+		for _, anime := range page.Anime {
+			allThemes = append(allThemes, c.extractThemes(anime, anime.AnimeThemes)...)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	url := fmt.Sprintf(
-		"%s/anime?filter[year]=%d&filter[season]=%s&include=animethemes.song.artists,animethemes.animethemeentries.videos,images,resources&page[size]=25&page[number]=1",
-		baseURL, year, season,
-	)
+	c.log.Infow("finished fetching season", "year", year, "season", season, "themes", len(allThemes))
+	return allThemes, nil
+}
 
-	for url != "" {
-		c.log.Infow("fetching anime themes page", "url", url)
+// FetchAll walks the whole animethemes catalog in id order and hands each
+// page of themes to fn. Used for the historical backfill; a failing fn stops
+// the walk.
+func (c *Client) FetchAll(fn func([]domain.SyncedTheme) error) error {
+	q := url.Values{}
+	q.Set("sort", "id")
+	q.Set("include", animeInclude)
+	q.Set("page[size]", "25")
+	q.Set("page[number]", "1")
+
+	return c.paginate(c.baseURL+"/anime?"+q.Encode(), func(page apiResponse) (bool, error) {
+		var themes []domain.SyncedTheme
+		for _, anime := range page.Anime {
+			themes = append(themes, c.extractThemes(anime, anime.AnimeThemes)...)
+		}
+		return true, fn(themes)
+	})
+}
 
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("create request: %w", err)
+// relatedFeed is an update feed of records themes are built from. An edit
+// there (a renamed artist, a re-encoded video) does not bump the theme's own
+// updated_at, so each feed is read for the themes it touches.
+type relatedFeed struct {
+	path    string
+	include string
+	changes func(apiResponse) []relatedChange
+}
+
+// relatedChange is one updated record and the themes it belongs to.
+type relatedChange struct {
+	updatedAt string
+	themeIDs  []int
+}
+
+var relatedFeeds = []relatedFeed{
+	{path: "/song", include: "animethemes", changes: func(page apiResponse) []relatedChange {
+		out := make([]relatedChange, 0, len(page.Songs))
+		for _, s := range page.Songs {
+			out = append(out, relatedChange{updatedAt: s.UpdatedAt, themeIDs: themeIDsOf(s.AnimeThemes)})
+		}
+		return out
+	}},
+	{path: "/artist", include: "songs.animethemes", changes: func(page apiResponse) []relatedChange {
+		out := make([]relatedChange, 0, len(page.Artists))
+		for _, a := range page.Artists {
+			var ids []int
+			for _, s := range a.Songs {
+				ids = append(ids, themeIDsOf(s.AnimeThemes)...)
+			}
+			out = append(out, relatedChange{updatedAt: a.UpdatedAt, themeIDs: ids})
+		}
+		return out
+	}},
+	{path: "/video", include: "animethemeentries.animetheme", changes: func(page apiResponse) []relatedChange {
+		out := make([]relatedChange, 0, len(page.Videos))
+		for _, v := range page.Videos {
+			var ids []int
+			for _, e := range v.AnimeThemeEntries {
+				if e.AnimeTheme != nil {
+					ids = append(ids, e.AnimeTheme.ID)
+				}
+			}
+			out = append(out, relatedChange{updatedAt: v.UpdatedAt, themeIDs: ids})
 		}
-		req.Header.Set("Accept", "application/json")
+		return out
+	}},
+}
+
+func themeIDsOf(themes []themeData) []int {
+	ids := make([]int, 0, len(themes))
+	for _, t := range themes {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+// updatedBefore reports whether a record's updated_at is older than since.
+// An unparsable timestamp counts as recent, so the record is not missed.
+func updatedBefore(updatedAt string, since time.Time) bool {
+	updated, err := time.Parse(time.RFC3339Nano, updatedAt)
+	return err == nil && updated.Before(since)
+}
+
+// feedURL is the first page of an update feed, newest update first.
+func (c *Client) feedURL(path, include string) string {
+	q := url.Values{}
+	q.Set("sort", "-updated_at")
+	q.Set("include", include)
+	q.Set("page[size]", "25")
+	q.Set("page[number]", "1")
+	return c.baseURL + path + "?" + q.Encode()
+}
+
+// FetchUpdatedSince hands fn, one page at a time, every theme changed at or
+// after since: first the animetheme feed, then the themes of songs, artists
+// and videos updated since, each read from its own feed. Every feed is read
+// newest update first and stops at the first older record, so a run costs
+// pages proportional to the changes. A theme is handed over once per run.
+func (c *Client) FetchUpdatedSince(since time.Time, fn func([]domain.SyncedTheme) error) error {
+	seen := make(map[int]bool)
+
+	err := c.paginate(c.feedURL("/animetheme", themeInclude), func(page apiResponse) (bool, error) {
+		var themes []domain.SyncedTheme
+		more := true
+		for _, t := range page.AnimeThemes {
+			if updatedBefore(t.UpdatedAt, since) {
+				more = false
+				break
+			}
+			seen[t.ID] = true
+			if t.Anime == nil {
+				continue
+			}
+			themes = append(themes, c.extractThemes(*t.Anime, []themeData{t})...)
+		}
+		return more, fn(themes)
+	})
+	if err != nil {
+		return err
+	}
 
-		resp, err := c.httpClient.Do(req)
+	var stale []int
+	for _, feed := range relatedFeeds {
+		time.Sleep(c.delay)
+		err := c.paginate(c.feedURL(feed.path, feed.include), func(page apiResponse) (bool, error) {
+			for _, change := range feed.changes(page) {
+				if updatedBefore(change.updatedAt, since) {
+					return false, nil
+				}
+				for _, id := range change.themeIDs {
+					if !seen[id] {
+						seen[id] = true
+						stale = append(stale, id)
+					}
+				}
+			}
+			return true, nil
+		})
 		if err != nil {
-			return nil, fmt.Errorf("fetch page: %w", err)
+			return fmt.Errorf("%s feed: %w", feed.path, err)
 		}
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			resp.Body.Close()
-			return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	c.log.Infow("themes changed through songs, artists and videos", "count", len(stale))
+	return c.fetchThemes(stale, fn)
+}
+
+// fetchThemes re-reads the given animetheme ids with their whole graph and
+// hands fn each batch. It follows other requests, so every batch waits out
+// the rate limit first.
+func (c *Client) fetchThemes(ids []int, fn func([]domain.SyncedTheme) error) error {
+	for start := 0; start < len(ids); start += themeBatchSize {
+		end := min(start+themeBatchSize, len(ids))
+		batch := make([]string, 0, end-start)
+		for _, id := range ids[start:end] {
+			batch = append(batch, strconv.Itoa(id))
+		}
+		time.Sleep(c.delay)
+
+		q := url.Values{}
+		q.Set("filter[id]", strings.Join(batch, ","))
+		q.Set("include", themeInclude)
+		q.Set("page[size]", strconv.Itoa(themeBatchSize))
+		err := c.paginate(c.baseURL+"/animetheme?"+q.Encode(), func(page apiResponse) (bool, error) {
+			var themes []domain.SyncedTheme
+			for _, t := range page.AnimeThemes {
+				if t.Anime != nil {
+					themes = append(themes, c.extractThemes(*t.Anime, []themeData{t})...)
+				}
+			}
+			return true, fn(themes)
+		})
+		if err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		var apiResp apiResponse
-		if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-			resp.Body.Close()
-			return nil, fmt.Errorf("decode response: %w", err)
+// paginate GETs pageURL and follows links.next while visit asks for more.
+func (c *Client) paginate(pageURL string, visit func(apiResponse) (bool, error)) error {
+	for pageURL != "" {
+		c.log.Infow("fetching anime themes page", "url", pageURL)
+
+		apiResp, err := c.fetchPage(pageURL)
+		if err != nil {
+			return err
 		}
-		resp.Body.Close()
 
-		for _, anime := range apiResp.Anime {
-			themes := c.extractThemes(anime, year, season)
-			allThemes = append(allThemes, themes...)
+		more, err := visit(apiResp)
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
 		}
 
-		url = apiResp.Links.Next
-		if url != "" {
-			time.Sleep(requestDelay)
+		pageURL = apiResp.Links.Next
+		if pageURL != "" {
+			time.Sleep(c.delay)
 		}
 	}
+	return nil
+}
 
-	c.log.Infow("finished fetching season", "year", year, "season", season, "themes", len(allThemes))
-	return allThemes, nil
+func (c *Client) fetchPage(pageURL string) (apiResponse, error) {
+	var apiResp apiResponse
+
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return apiResp, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return apiResp, fmt.Errorf("fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apiResp, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return apiResp, fmt.Errorf("decode response: %w", err)
+	}
+	return apiResp, nil
 }
 
-func (c *Client) extractThemes(anime animeData, year int, season string) []domain.AnimeTheme {
+func (c *Client) extractThemes(anime animeData, animeThemes []themeData) []domain.SyncedTheme {
 	posterURL := ""
 	for _, img := range anime.Images {
 		if img.Facet == "Large Cover" || img.Facet == "Small Cover" {
@@ -166,21 +418,38 @@ func (c *Client) extractThemes(anime animeData, year int, season string) []domai
 		}
 	}
 
-	var themes []domain.AnimeTheme
-	for _, t := range anime.AnimeThemes {
+	season := ""
+	if anime.Season != nil {
+		season = strings.ToLower(*anime.Season)
+	}
+
+	var themes []domain.SyncedTheme
+	for _, t := range animeThemes {
 		seq := 0
 		if t.Sequence != nil {
 			seq = *t.Sequence
 		}
 
-		songTitle := ""
-		artistName := ""
+		var (
+			song       *domain.ThemeSong
+			credits    []domain.ThemeSongArtistCredit
+			songTitle  string
+			artistName string
+		)
 		if t.Song != nil {
 			songTitle = t.Song.Title
+			song = &domain.ThemeSong{ExternalID: t.Song.ID, Title: t.Song.Title}
 			if len(t.Song.Artists) > 0 {
 				var names []string
 				for _, a := range t.Song.Artists {
 					names = append(names, a.Name)
+					credit := domain.ThemeSongArtistCredit{
+						Artist: domain.ThemeArtist{ExternalID: a.ID, Name: a.Name, Slug: a.Slug},
+					}
+					if a.ArtistSong != nil && a.ArtistSong.As != nil {
+						credit.As = *a.ArtistSong.As
+					}
+					credits = append(credits, credit)
 				}
 				artistName = strings.Join(names, ", ")
 			}
@@ -199,29 +468,71 @@ func (c *Client) extractThemes(anime animeData, year int, season string) []domai
 			}
 		}
 
-		themes = append(themes, domain.AnimeTheme{
-			ExternalID:      t.ID,
-			AnimeName:       anime.Name,
-			AnimeSlug:       anime.Slug,
-			PosterURL:       posterURL,
-			ThemeType:       t.Type,
-			Sequence:        seq,
-			Slug:            t.Slug,
-			SongTitle:       songTitle,
-			ArtistName:      artistName,
-			VideoBasename:   videoBasename,
-			VideoResolution: videoResolution,
-			AudioBasename:   audioBasename,
-			MALID:           malID,
-			Year:            year,
-			Season:          season,
+		themes = append(themes, domain.SyncedTheme{
+			Theme: domain.AnimeTheme{
+				ExternalID:      t.ID,
+				AnimeName:       anime.Name,
+				AnimeSlug:       anime.Slug,
+				PosterURL:       posterURL,
+				ThemeType:       t.Type,
+				Sequence:        seq,
+				Slug:            t.Slug,
+				SongTitle:       songTitle,
+				ArtistName:      artistName,
+				VideoBasename:   videoBasename,
+				VideoResolution: videoResolution,
+				AudioBasename:   audioBasename,
+				MALID:           malID,
+				Year:            anime.Year,
+				Season:          season,
+			},
+			Song:    song,
+			Artists: credits,
+			Videos:  extractVideos(t.AnimeThemeEntries),
 		})
 	}
 	return themes
 }
 
+// extractVideos lists every video of every entry of a theme.
+func extractVideos(entries []entryData) []domain.ThemeVideo {
+	var videos []domain.ThemeVideo
+	for _, entry := range entries {
+		version, episodes := 0, ""
+		if entry.Version != nil {
+			version = *entry.Version
+		}
+		if entry.Episodes != nil {
+			episodes = *entry.Episodes
+		}
+		for _, v := range entry.Videos {
+			video := domain.ThemeVideo{
+				ExternalID: v.ID,
+				Version:    version,
+				Episodes:   episodes,
+				Basename:   v.Basename,
+				Resolution: v.Resolution,
+				NC:         v.NC,
+				Lyrics:     v.Lyrics,
+				Subbed:     v.Subbed,
+				Uncensored: v.Uncen,
+				Overlap:    v.Overlap,
+				Tags:       v.Tags,
+			}
+			if v.Source != nil {
+				video.Source = *v.Source
+			}
+			if v.Audio != nil {
+				video.AudioBasename = v.Audio.Basename
+			}
+			videos = append(videos, video)
+		}
+	}
+	return videos
+}
+
 // selectBestVideo picks the best video from all entries.
-// Prefers NC (creditless), then highest resolution.
+// Prefers NC (creditless) without lyrics, then highest resolution.
 func (c *Client) selectBestVideo(entries []entryData) *videoData {
 	var best *videoData
 	for _, entry := range entries {
@@ -239,6 +550,14 @@ func (c *Client) selectBestVideo(entries []entryData) *videoData {
 			if !v.NC && best.NC {
 				continue
 			}
+			// Then versions without burned-in lyrics
+			if !v.Lyrics && best.Lyrics {
+				best = v
+				continue
+			}
+			if v.Lyrics && !best.Lyrics {
+				continue
+			}
 			// Then prefer higher resolution
 			if v.Resolution > best.Resolution {
 				best = v
@@ -0,0 +1,250 @@
+package animethemes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+)
+
+const themeFeedPage = `{
+  "animethemes": [{
+    "id": 101, "type": "OP", "sequence": 1, "slug": "OP1", "updated_at": "2026-10-01T12:00:00.000000Z",
+    "song": {"id": 55, "title": "Yuusha", "artists": [
+      {"id": 7, "name": "YOASOBI", "slug": "yoasobi", "artistsong": {"as": null}},
+      {"id": 8, "name": "Ikura", "slug": "ikura", "artistsong": {"as": "Frieren"}}
+    ]},
+    "animethemeentries": [
+      {"version": 1, "episodes": "1-10", "videos": [
+        {"id": 1, "basename": "Frieren-OP1.webm", "resolution": 1080, "nc": false, "source": "BD", "tags": "BD1080"},
+        {"id": 2, "basename": "Frieren-OP1-NCBD1080-Lyrics.webm", "resolution": 1080, "nc": true, "lyrics": true, "source": "BD"},
+        {"id": 3, "basename": "Frieren-OP1-NCBD1080.webm", "resolution": 1080, "nc": true, "source": "BD", "tags": "NCBD1080",
+         "audio": {"basename": "Frieren-OP1-NCBD1080.ogg"}}
+      ]},
+      {"version": 2, "episodes": "11-", "videos": [
+        {"id": 4, "basename": "Frieren-OP1v2.webm", "resolution": 720, "nc": true, "source": null}
+      ]}
+    ],
+    "anime": {"id": 9, "name": "Sousou no Frieren", "slug": "sousou_no_frieren", "year": 2023, "season": "Fall",
+      "images": [{"facet": "Large Cover", "link": "https://img/frieren.jpg"}],
+      "resources": [{"site": "MyAnimeList", "external_id": 52991}]}
+  }],
+  "links": {"next": null}
+}`
+
+func TestExtractThemes_NormalizesSongArtistsAndVideos(t *testing.T) {
+	var page apiResponse
+	if err := json.Unmarshal([]byte(themeFeedPage), &page); err != nil {
+		t.Fatal(err)
+	}
+	th := page.AnimeThemes[0]
+
+	c := &Client{}
+	got := c.extractThemes(*th.Anime, []themeData{th})
+	if len(got) != 1 {
+		t.Fatalf("got %d themes, want 1", len(got))
+	}
+	st := got[0]
+
+	if st.Theme.Year != 2023 || st.Theme.Season != "fall" || st.Theme.MALID != 52991 {
+		t.Errorf("anime fields: year=%d season=%q mal=%d", st.Theme.Year, st.Theme.Season, st.Theme.MALID)
+	}
+	if st.Theme.ArtistName != "YOASOBI, Ikura" {
+		t.Errorf("artist_name = %q", st.Theme.ArtistName)
+	}
+	// best video: creditless without lyrics
+	if st.Theme.VideoBasename != "Frieren-OP1-NCBD1080.webm" || st.Theme.AudioBasename != "Frieren-OP1-NCBD1080.ogg" {
+		t.Errorf("best video = %q / %q", st.Theme.VideoBasename, st.Theme.AudioBasename)
+	}
+
+	if st.Song == nil || st.Song.ExternalID != 55 || st.Song.Title != "Yuusha" {
+		t.Fatalf("song = %+v", st.Song)
+	}
+	if len(st.Artists) != 2 || st.Artists[0].Artist.Slug != "yoasobi" || st.Artists[1].As != "Frieren" {
+		t.Errorf("artists = %+v", st.Artists)
+	}
+
+	if len(st.Videos) != 4 {
+		t.Fatalf("got %d videos, want 4", len(st.Videos))
+	}
+	v2 := st.Videos[3]
+	if v2.Version != 2 || v2.Episodes != "11-" || !v2.NC || v2.Source != "" {
+		t.Errorf("v2 video = %+v", v2)
+	}
+	if !st.Videos[1].Lyrics || st.Videos[0].Source != "BD" {
+		t.Errorf("video flags = %+v", st.Videos[:2])
+	}
+}
+
+// feedServer fakes the animethemes API for the update feeds. Each path
+// serves its pages in order by page[number]; requests are recorded.
+type feedServer struct {
+	t        *testing.T
+	pages    map[string][]string // path → page bodies; "%s" is the server URL
+	requests []*url.URL
+}
+
+func (f *feedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests = append(f.requests, r.URL)
+	path := r.URL.Path
+	if r.URL.Query().Get("filter[id]") != "" {
+		path += "?filter"
+	}
+	n, _ := strconv.Atoi(r.URL.Query().Get("page[number]"))
+	if n == 0 {
+		n = 1
+	}
+	pages := f.pages[path]
+	if n > len(pages) {
+		f.t.Errorf("unexpected request %s", r.URL)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_, _ = io.WriteString(w, strings.ReplaceAll(pages[n-1], "%s", "http://"+r.Host))
+}
+
+func newFeedClient(t *testing.T, pages map[string][]string) (*Client, *feedServer) {
+	t.Helper()
+	f := &feedServer{t: t, pages: pages}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return &Client{baseURL: srv.URL, httpClient: srv.Client(), log: logger.Default()}, f
+}
+
+// feedTheme is an /animetheme record with just enough graph to extract.
+func feedTheme(id int, updatedAt string) string {
+	return fmt.Sprintf(`{"id": %d, "type": "OP", "slug": "OP1", "updated_at": %q,
+		"anime": {"id": %d, "name": "Anime %d", "slug": "anime_%d", "year": 2024, "season": "Winter"}}`, id, updatedAt, id, id, id)
+}
+
+func syncedIDs(themes []domain.SyncedTheme) []int {
+	ids := make([]int, 0, len(themes))
+	for _, st := range themes {
+		ids = append(ids, st.Theme.ExternalID)
+	}
+	return ids
+}
+
+func TestFetchUpdatedSince_FollowsSongArtistAndVideoFeeds(t *testing.T) {
+	const recent, old = "2026-10-02T00:00:00.000000Z", "2026-09-01T00:00:00.000000Z"
+	c, srv := newFeedClient(t, map[string][]string{
+		"/animetheme": {
+			`{"animethemes": [` + feedTheme(101, recent) + `], "links": {"next": "%s/animetheme?page[number]=2"}}`,
+			`{"animethemes": [` + feedTheme(102, recent) + `,` + feedTheme(103, old) + `], "links": {"next": "%s/animetheme?page[number]=3"}}`,
+		},
+		"/song": {`{"songs": [
+			{"id": 1, "updated_at": "` + recent + `", "animethemes": [{"id": 101}, {"id": 201}]},
+			{"id": 2, "updated_at": "` + old + `", "animethemes": [{"id": 202}]}
+		], "links": {"next": "%s/song?page[number]=2"}}`},
+		"/artist": {`{"artists": [
+			{"id": 7, "updated_at": "` + recent + `", "songs": [{"id": 3, "animethemes": [{"id": 301}]}]},
+			{"id": 8, "updated_at": "` + old + `", "songs": [{"id": 4, "animethemes": [{"id": 302}]}]}
+		], "links": {"next": null}}`},
+		"/video": {`{"videos": [
+			{"id": 9, "updated_at": "` + recent + `", "animethemeentries": [{"animetheme": {"id": 201}}, {"animetheme": {"id": 401}}]}
+		], "links": {"next": null}}`},
+		"/animetheme?filter": {`{"animethemes": [` +
+			feedTheme(201, old) + `,` + feedTheme(301, old) + `,` + feedTheme(401, old) + `], "links": {"next": null}}`},
+	})
+
+	var got [][]int
+	since, _ := time.Parse(time.RFC3339, "2026-10-01T00:00:00Z")
+	err := c.FetchUpdatedSince(since, func(themes []domain.SyncedTheme) error {
+		got = append(got, syncedIDs(themes))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("FetchUpdatedSince: %v", err)
+	}
+	if want := [][]int{{101}, {102}, {201, 301, 401}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("batches = %v; want %v", got, want)
+	}
+
+	var filter string
+	for _, u := range srv.requests {
+		if f := u.Query().Get("filter[id]"); f != "" {
+			filter = f
+			continue
+		}
+		if u.Query().Get("page[number]") == "1" && u.Query().Get("sort") != "-updated_at" {
+			t.Errorf("feed %s not read newest update first", u)
+		}
+	}
+	if filter != "201,301,401" {
+		t.Errorf("refetched ids = %q; want the related themes not in the theme feed, once each", filter)
+	}
+}
+
+func TestFetchUpdatedSince_NoRelatedChanges(t *testing.T) {
+	const old = "2026-09-01T00:00:00.000000Z"
+	empty := `{"links": {"next": null}}`
+	c, srv := newFeedClient(t, map[string][]string{
+		"/animetheme": {`{"animethemes": [` + feedTheme(103, old) + `], "links": {"next": null}}`},
+		"/song":       {`{"songs": [{"id": 2, "updated_at": "` + old + `", "animethemes": [{"id": 202}]}], "links": {"next": null}}`},
+		"/artist":     {empty},
+		"/video":      {empty},
+	})
+
+	calls := 0
+	err := c.FetchUpdatedSince(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), func(themes []domain.SyncedTheme) error {
+		calls++
+		if len(themes) != 0 {
+			t.Errorf("handed %v; want nothing", syncedIDs(themes))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("FetchUpdatedSince: %v", err)
+	}
+	if calls != 1 || len(srv.requests) != 4 {
+		t.Errorf("fn called %d times over %d requests; want 1 over the 4 feeds", calls, len(srv.requests))
+	}
+}
+
+func TestFetchAll_WalksEveryPageUntilFnFails(t *testing.T) {
+	animePage := func(id int, next string) string {
+		return fmt.Sprintf(`{"anime": [{"id": %d, "name": "Anime", "slug": "a", "year": 2024, "season": "Winter",
+			"animethemes": [{"id": %d, "type": "OP", "slug": "OP1"}, {"id": %d, "type": "ED", "slug": "ED1"}]}],
+			"links": {"next": %s}}`, id, id*10, id*10+1, next)
+	}
+	pages := map[string][]string{"/anime": {
+		animePage(1, `"%s/anime?page[number]=2"`),
+		animePage(2, `"%s/anime?page[number]=3"`),
+		animePage(3, "null"),
+	}}
+
+	c, srv := newFeedClient(t, pages)
+	var got [][]int
+	if err := c.FetchAll(func(themes []domain.SyncedTheme) error {
+		got = append(got, syncedIDs(themes))
+		return nil
+	}); err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	if want := [][]int{{10, 11}, {20, 21}, {30, 31}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("pages = %v; want %v", got, want)
+	}
+	if sort := srv.requests[0].Query().Get("sort"); sort != "id" {
+		t.Errorf("backfill sort = %q; want id", sort)
+	}
+
+	c, srv = newFeedClient(t, pages)
+	boom := errors.New("db down")
+	if err := c.FetchAll(func([]domain.SyncedTheme) error { return boom }); !errors.Is(err, boom) {
+		t.Errorf("FetchAll err = %v; want fn's error", err)
+	}
+	if len(srv.requests) != 1 {
+		t.Errorf("fetched %d pages after fn failed; want 1", len(srv.requests))
+	}
+}
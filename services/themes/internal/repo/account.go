@@ -0,0 +1,64 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ILITA-hub/animeenigma/libs/database"
+	"gorm.io/gorm"
+)
+
+// accountUserTables lists the themes tables holding a user's rows — ratings,
+// tournament votes and playlists — in erase order. Playlist items hang off
+// playlists rather than users and are handled separately. Tournaments
+// themselves (created_by) are admin-run public events and stay.
+var accountUserTables = []database.UserTable{
+	{Table: "theme_ratings", Column: "user_id"},
+	{Table: "theme_tournament_votes", Column: "user_id"},
+	{Table: "theme_playlists", Column: "user_id"},
+}
+
+// userPlaylistItems selects the items of every playlist of the user.
+const userPlaylistItems = "playlist_id IN (SELECT id FROM theme_playlists WHERE user_id = ?)"
+
+// AccountRepository backs the auth-driven account export + erasure fan-out
+// (/internal/account/*).
+type AccountRepository struct{ db *gorm.DB }
+
+func NewAccountRepository(db *gorm.DB) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+// Export returns every themes row of the user, keyed by table.
+func (r *AccountRepository) Export(ctx context.Context, userID string) (map[string][]map[string]any, error) {
+	rows, err := database.ExportUserRows(ctx, r.db, userID, accountUserTables)
+	if err != nil {
+		return nil, fmt.Errorf("export account: %w", err)
+	}
+	var items []map[string]any
+	if err := r.db.WithContext(ctx).Table("theme_playlist_items").Where(userPlaylistItems, userID).
+		Order("playlist_id, position").Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("export account: %w", err)
+	}
+	if items == nil {
+		items = []map[string]any{}
+	}
+	rows["theme_playlist_items"] = items
+	return rows, nil
+}
+
+// Erase hard-deletes every themes row of the user, playlist items first.
+// Totals of closed tournament matches are stored on the match, so erasing
+// votes leaves decided results intact.
+func (r *AccountRepository) Erase(ctx context.Context, userID string) (map[string]int64, error) {
+	res := r.db.WithContext(ctx).Exec("DELETE FROM theme_playlist_items WHERE "+userPlaylistItems, userID)
+	if res.Error != nil {
+		return nil, fmt.Errorf("erase account: %w", res.Error)
+	}
+	deleted, err := database.EraseUserRows(ctx, r.db, userID, accountUserTables)
+	if err != nil {
+		return nil, fmt.Errorf("erase account: %w", err)
+	}
+	deleted["theme_playlist_items"] = res.RowsAffected
+	return deleted, nil
+}
@@ -0,0 +1,90 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+	"gorm.io/gorm"
+)
+
+type ArtistRepository struct {
+	db *gorm.DB
+}
+
+func NewArtistRepository(db *gorm.DB) *ArtistRepository {
+	return &ArtistRepository{db: db}
+}
+
+// List returns artists with their theme counts, most prolific first. Query
+// filters by name (case-insensitive substring).
+func (r *ArtistRepository) List(ctx context.Context, params domain.ArtistListParams) ([]domain.ThemeArtist, error) {
+	query := r.db.WithContext(ctx).
+		Table("theme_artists").
+		Select("theme_artists.*, COUNT(DISTINCT anime_themes.id) AS theme_count").
+		Joins("JOIN theme_song_artists ON theme_song_artists.artist_id = theme_artists.id").
+		Joins("JOIN anime_themes ON anime_themes.song_id = theme_song_artists.song_id AND anime_themes.deleted_at IS NULL").
+		Group("theme_artists.id").
+		Order("theme_count DESC, theme_artists.name ASC")
+
+	if params.Query != "" {
+		query = query.Where("theme_artists.name ILIKE ?", "%"+params.Query+"%")
+	}
+	if params.Limit > 0 {
+		query = query.Limit(params.Limit)
+	}
+	if params.Offset > 0 {
+		query = query.Offset(params.Offset)
+	}
+
+	var artists []domain.ThemeArtist
+	if err := query.Scan(&artists).Error; err != nil {
+		return nil, err
+	}
+	return artists, nil
+}
+
+// GetBySlug returns an artist, or nil if there is none.
+func (r *ArtistRepository) GetBySlug(ctx context.Context, slug string) (*domain.ThemeArtist, error) {
+	var artists []domain.ThemeArtist
+	if err := r.db.WithContext(ctx).Where("slug = ?", slug).Limit(1).Find(&artists).Error; err != nil {
+		return nil, err
+	}
+	if len(artists) == 0 {
+		return nil, nil
+	}
+	return &artists[0], nil
+}
+
+// Themes returns every theme the artist is credited on, with avg score and
+// vote count, highest rated first.
+func (r *ArtistRepository) Themes(ctx context.Context, artistID string) ([]domain.AnimeTheme, error) {
+	var themes []domain.AnimeTheme
+	err := r.db.WithContext(ctx).
+		Table("anime_themes").
+		Select(`anime_themes.*,
+			COALESCE(AVG(theme_ratings.score), 0) as avg_score,
+			COUNT(theme_ratings.id) as vote_count,
+			animes.id as anime_id,
+			COALESCE(NULLIF(animes.name_ru, ''), NULLIF(animes.name, ''), anime_themes.anime_name) as anime_name`).
+		Joins("JOIN theme_song_artists ON theme_song_artists.song_id = anime_themes.song_id AND theme_song_artists.artist_id = ?", artistID).
+		Joins("LEFT JOIN theme_ratings ON theme_ratings.theme_id = anime_themes.id").
+		Joins("LEFT JOIN animes ON anime_themes.mal_id > 0 AND anime_themes.mal_id::text = animes.shikimori_id AND animes.deleted_at IS NULL").
+		Where("anime_themes.deleted_at IS NULL").
+		Group("anime_themes.id, animes.id, animes.name, animes.name_ru").
+		Order("avg_score DESC, vote_count DESC, anime_themes.year DESC, anime_themes.slug ASC").
+		Scan(&themes).Error
+	return themes, err
+}
+
+// ForSong returns the artists credited on a song, in credit order.
+func (r *ArtistRepository) ForSong(ctx context.Context, songID string) ([]domain.ThemeArtist, error) {
+	var artists []domain.ThemeArtist
+	err := r.db.WithContext(ctx).
+		Table("theme_artists").
+		Select("theme_artists.*, theme_song_artists.credited_as").
+		Joins("JOIN theme_song_artists ON theme_song_artists.artist_id = theme_artists.id").
+		Where("theme_song_artists.song_id = ?", songID).
+		Order("theme_song_artists.position ASC").
+		Scan(&artists).Error
+	return artists, err
+}
@@ -0,0 +1,194 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPlaylistItemsMismatch is returned by Reorder when the given theme ids are
+// not exactly the playlist's items.
+var ErrPlaylistItemsMismatch = errors.New("theme ids do not match the playlist items")
+
+type PlaylistRepository struct {
+	db *gorm.DB
+}
+
+func NewPlaylistRepository(db *gorm.DB) *PlaylistRepository {
+	return &PlaylistRepository{db: db}
+}
+
+// Create inserts a playlist.
+func (r *PlaylistRepository) Create(ctx context.Context, p *domain.ThemePlaylist) error {
+	return r.db.WithContext(ctx).Create(p).Error
+}
+
+// GetByID returns a playlist with its item count, or nil if it does not exist.
+func (r *PlaylistRepository) GetByID(ctx context.Context, id string) (*domain.ThemePlaylist, error) {
+	var p domain.ThemePlaylist
+	err := r.withCount(ctx).Where("theme_playlists.id = ?", id).Take(&p).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ListByUser returns a user's playlists, most recently updated first.
+func (r *PlaylistRepository) ListByUser(ctx context.Context, userID string) ([]domain.ThemePlaylist, error) {
+	var out []domain.ThemePlaylist
+	err := r.withCount(ctx).Where("theme_playlists.user_id = ?", userID).
+		Order("theme_playlists.updated_at DESC").Find(&out).Error
+	return out, err
+}
+
+// CountByUser counts a user's playlists.
+func (r *PlaylistRepository) CountByUser(ctx context.Context, userID string) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&domain.ThemePlaylist{}).Where("user_id = ?", userID).Count(&n).Error
+	return n, err
+}
+
+func (r *PlaylistRepository) withCount(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&domain.ThemePlaylist{}).
+		Select("theme_playlists.*, (SELECT COUNT(*) FROM theme_playlist_items WHERE theme_playlist_items.playlist_id = theme_playlists.id) AS item_count")
+}
+
+// Update saves the name, description and visibility of a playlist.
+func (r *PlaylistRepository) Update(ctx context.Context, p *domain.ThemePlaylist) error {
+	return r.db.WithContext(ctx).Model(&domain.ThemePlaylist{}).Where("id = ?", p.ID).
+		Updates(map[string]interface{}{
+			"name":        p.Name,
+			"description": p.Description,
+			"is_public":   p.IsPublic,
+			"updated_at":  time.Now(),
+		}).Error
+}
+
+// Delete removes a playlist and its items.
+func (r *PlaylistRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("playlist_id = ?", id).Delete(&domain.ThemePlaylistItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&domain.ThemePlaylist{}).Error
+	})
+}
+
+// Themes returns the playlist's themes in order, with the local catalog anime
+// linked like List does.
+func (r *PlaylistRepository) Themes(ctx context.Context, playlistID string) ([]domain.AnimeTheme, error) {
+	var themes []domain.AnimeTheme
+	err := r.db.WithContext(ctx).
+		Table("theme_playlist_items").
+		Select(`anime_themes.*,
+			animes.id as anime_id,
+			COALESCE(NULLIF(animes.name_ru, ''), NULLIF(animes.name, ''), anime_themes.anime_name) as anime_name`).
+		Joins("JOIN anime_themes ON anime_themes.id = theme_playlist_items.theme_id AND anime_themes.deleted_at IS NULL").
+		Joins("LEFT JOIN animes ON anime_themes.mal_id > 0 AND anime_themes.mal_id::text = animes.shikimori_id AND animes.deleted_at IS NULL").
+		Where("theme_playlist_items.playlist_id = ?", playlistID).
+		Order("theme_playlist_items.position ASC").
+		Scan(&themes).Error
+	return themes, err
+}
+
+// AddItem appends a theme to the end of a playlist; adding a theme already
+// in the playlist is a no-op.
+func (r *PlaylistRepository) AddItem(ctx context.Context, playlistID, themeID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var next int
+		if err := tx.Model(&domain.ThemePlaylistItem{}).Where("playlist_id = ?", playlistID).
+			Select("COALESCE(MAX(position), 0) + 1").Scan(&next).Error; err != nil {
+			return err
+		}
+		item := &domain.ThemePlaylistItem{PlaylistID: playlistID, ThemeID: themeID, Position: next, AddedAt: time.Now()}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(item).Error; err != nil {
+			return err
+		}
+		return touch(tx, playlistID)
+	})
+}
+
+// RemoveItem removes a theme from a playlist. Positions keep their gaps —
+// only the order matters.
+func (r *PlaylistRepository) RemoveItem(ctx context.Context, playlistID, themeID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("playlist_id = ? AND theme_id = ?", playlistID, themeID).Delete(&domain.ThemePlaylistItem{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return touch(tx, playlistID)
+	})
+}
+
+// CountItems counts the items of a playlist.
+func (r *PlaylistRepository) CountItems(ctx context.Context, playlistID string) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&domain.ThemePlaylistItem{}).Where("playlist_id = ?", playlistID).Count(&n).Error
+	return n, err
+}
+
+// Reorder rewrites the positions so the playlist plays in the given order.
+// themeIDs must be exactly the playlist's items.
+func (r *PlaylistRepository) Reorder(ctx context.Context, playlistID string, themeIDs []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current []string
+		if err := tx.Model(&domain.ThemePlaylistItem{}).Where("playlist_id = ?", playlistID).
+			Pluck("theme_id", &current).Error; err != nil {
+			return err
+		}
+		if !sameSet(current, themeIDs) {
+			return ErrPlaylistItemsMismatch
+		}
+		for i, id := range themeIDs {
+			if err := tx.Model(&domain.ThemePlaylistItem{}).
+				Where("playlist_id = ? AND theme_id = ?", playlistID, id).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return touch(tx, playlistID)
+	})
+}
+
+// Clone copies a playlist and its items to a new owner.
+func (r *PlaylistRepository) Clone(ctx context.Context, src *domain.ThemePlaylist, clone *domain.ThemePlaylist) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(clone).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`INSERT INTO theme_playlist_items (playlist_id, theme_id, position, added_at)
+			SELECT ?, theme_id, position, ? FROM theme_playlist_items WHERE playlist_id = ?`,
+			clone.ID, time.Now(), src.ID).Error
+	})
+}
+
+func touch(tx *gorm.DB, playlistID string) error {
+	return tx.Model(&domain.ThemePlaylist{}).Where("id = ?", playlistID).Update("updated_at", time.Now()).Error
+}
+
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(a))
+	for _, id := range a {
+		seen[id]++
+	}
+	for _, id := range b {
+		if seen[id] == 0 {
+			return false
+		}
+		seen[id]--
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+)
+
+// radioSelect is the theme projection of the radio queries: themes with an
+// audio track, the local catalog anime linked like List does.
+const radioSelect = `SELECT anime_themes.*, animes.id AS anime_id,
+		COALESCE(NULLIF(animes.name_ru, ''), NULLIF(animes.name, ''), anime_themes.anime_name) AS anime_name
+	FROM anime_themes
+	LEFT JOIN animes ON anime_themes.mal_id > 0 AND anime_themes.mal_id::text = animes.shikimori_id AND animes.deleted_at IS NULL`
+
+// RadioBySeason returns the themes with audio of one season.
+func (r *ThemeRepository) RadioBySeason(ctx context.Context, year int, season string) ([]domain.AnimeTheme, error) {
+	var out []domain.AnimeTheme
+	err := r.db.WithContext(ctx).Raw(radioSelect+`
+		WHERE anime_themes.deleted_at IS NULL AND anime_themes.audio_basename <> ''
+			AND anime_themes.year = ? AND anime_themes.season = ?`, year, season).Scan(&out).Error
+	return out, err
+}
+
+// RadioByRatings returns the themes with audio the user rated at least
+// minScore, plus every other theme by the artists of those themes.
+func (r *ThemeRepository) RadioByRatings(ctx context.Context, userID string, minScore int) ([]domain.AnimeTheme, error) {
+	var out []domain.AnimeTheme
+	err := r.db.WithContext(ctx).Raw(radioSelect+`
+		WHERE anime_themes.deleted_at IS NULL AND anime_themes.audio_basename <> '' AND (
+			anime_themes.id IN (SELECT theme_id FROM theme_ratings WHERE user_id = ? AND score >= ?)
+			OR (anime_themes.artist_name <> '' AND anime_themes.artist_name IN (
+				SELECT liked.artist_name FROM anime_themes liked
+				JOIN theme_ratings ON theme_ratings.theme_id = liked.id
+				WHERE theme_ratings.user_id = ? AND theme_ratings.score >= ? AND liked.artist_name <> '')))`,
+		userID, minScore, userID, minScore).Scan(&out).Error
+	return out, err
+}
+
+// RadioByCompleted returns the themes with audio of the anime the user has
+// completed (player's anime_list, shared database).
+func (r *ThemeRepository) RadioByCompleted(ctx context.Context, userID string) ([]domain.AnimeTheme, error) {
+	var out []domain.AnimeTheme
+	err := r.db.WithContext(ctx).Raw(radioSelect+`
+		JOIN anime_list ON anime_list.anime_id = animes.id AND anime_list.user_id = ? AND anime_list.status = 'completed'
+		WHERE anime_themes.deleted_at IS NULL AND anime_themes.audio_basename <> ''`, userID).Scan(&out).Error
+	return out, err
+}
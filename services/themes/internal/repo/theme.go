@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
@@ -19,23 +20,122 @@ func NewThemeRepository(db *gorm.DB) *ThemeRepository {
 
 // Upsert creates or updates a theme by external_id.
 func (r *ThemeRepository) Upsert(ctx context.Context, theme *domain.AnimeTheme) error {
+	return upsertTheme(r.db.WithContext(ctx), theme)
+}
+
+func upsertTheme(db *gorm.DB, theme *domain.AnimeTheme) error {
 	now := time.Now()
 	theme.UpdatedAt = now
 	if theme.CreatedAt.IsZero() {
 		theme.CreatedAt = now
 	}
 
-	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+	return db.Clauses(clause.OnConflict{
 		Columns: []clause.Column{{Name: "external_id"}},
 		DoUpdates: clause.AssignmentColumns([]string{
 			"anime_name", "anime_slug", "poster_url", "theme_type",
-			"sequence", "slug", "song_title", "artist_name",
+			"sequence", "slug", "song_title", "artist_name", "song_id",
 			"video_basename", "video_resolution", "audio_basename",
 			"mal_id", "year", "season", "updated_at",
 		}),
 	}).Create(theme).Error
 }
 
+// UpsertSynced stores a theme with its song, artist credits and videos in
+// one transaction. Credits and videos that disappeared upstream are removed.
+func (r *ThemeRepository) UpsertSynced(ctx context.Context, st *domain.SyncedTheme) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		st.Theme.SongID = nil
+		if st.Song != nil {
+			st.Song.CreatedAt, st.Song.UpdatedAt = now, now
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "external_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"title", "updated_at"}),
+			}).Create(st.Song).Error; err != nil {
+				return fmt.Errorf("upsert song: %w", err)
+			}
+			st.Theme.SongID = &st.Song.ID
+
+			if err := tx.Where("song_id = ?", st.Song.ID).Delete(&domain.ThemeSongArtist{}).Error; err != nil {
+				return fmt.Errorf("clear song artists: %w", err)
+			}
+			for i := range st.Artists {
+				artist := &st.Artists[i].Artist
+				artist.CreatedAt, artist.UpdatedAt = now, now
+				if err := tx.Clauses(clause.OnConflict{
+					Columns:   []clause.Column{{Name: "external_id"}},
+					DoUpdates: clause.AssignmentColumns([]string{"name", "slug", "updated_at"}),
+				}).Create(artist).Error; err != nil {
+					return fmt.Errorf("upsert artist: %w", err)
+				}
+				credit := &domain.ThemeSongArtist{SongID: st.Song.ID, ArtistID: artist.ID, Position: i, As: st.Artists[i].As}
+				if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(credit).Error; err != nil {
+					return fmt.Errorf("credit artist: %w", err)
+				}
+			}
+		}
+
+		if err := upsertTheme(tx, &st.Theme); err != nil {
+			return fmt.Errorf("upsert theme: %w", err)
+		}
+
+		keep := make([]int, 0, len(st.Videos))
+		for i := range st.Videos {
+			video := &st.Videos[i]
+			video.ThemeID = st.Theme.ID
+			video.CreatedAt, video.UpdatedAt = now, now
+			if err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "external_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"theme_id", "version", "episodes", "basename", "audio_basename",
+					"resolution", "nc", "lyrics", "subbed", "uncensored",
+					"source", "overlap", "tags", "updated_at",
+				}),
+			}).Create(video).Error; err != nil {
+				return fmt.Errorf("upsert video: %w", err)
+			}
+			keep = append(keep, video.ExternalID)
+		}
+		stale := tx.Where("theme_id = ?", st.Theme.ID)
+		if len(keep) > 0 {
+			stale = stale.Where("external_id NOT IN ?", keep)
+		}
+		if err := stale.Delete(&domain.ThemeVideo{}).Error; err != nil {
+			return fmt.Errorf("remove stale videos: %w", err)
+		}
+		return nil
+	})
+}
+
+// Videos returns every video of a theme, best first (creditless, no lyrics,
+// highest resolution).
+func (r *ThemeRepository) Videos(ctx context.Context, themeID string) ([]domain.ThemeVideo, error) {
+	var videos []domain.ThemeVideo
+	err := r.db.WithContext(ctx).
+		Where("theme_id = ?", themeID).
+		Order("nc DESC, lyrics ASC, resolution DESC, version ASC").
+		Find(&videos).Error
+	return videos, err
+}
+
+// GetSyncCursor returns how far the named sync has read (zero if never).
+func (r *ThemeRepository) GetSyncCursor(ctx context.Context, name string) (time.Time, error) {
+	var cursor domain.SyncCursor
+	err := r.db.WithContext(ctx).Where("name = ?", name).Limit(1).Find(&cursor).Error
+	return cursor.SyncedThrough, err
+}
+
+// SetSyncCursor records how far the named sync has read.
+func (r *ThemeRepository) SetSyncCursor(ctx context.Context, name string, through time.Time) error {
+	cursor := &domain.SyncCursor{Name: name, SyncedThrough: through, UpdatedAt: time.Now()}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"synced_through", "updated_at"}),
+	}).Create(cursor).Error
+}
+
 // List returns themes with optional filters, including avg score and vote count.
 // LEFT JOINs the animes table to link themes with local catalog entries by MAL ID.
 func (r *ThemeRepository) List(ctx context.Context, params domain.ThemeListParams) ([]domain.AnimeTheme, error) {
@@ -107,3 +207,21 @@ func (r *ThemeRepository) GetByID(ctx context.Context, id string) (*domain.Anime
 	}
 	return &theme, nil
 }
+
+// ListGuessThemes returns every theme with an audio track whose anime is in
+// the local catalog (non-hidden), ordered by anime then slug.
+func (r *ThemeRepository) ListGuessThemes(ctx context.Context) ([]domain.GuessTheme, error) {
+	var out []domain.GuessTheme
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT anime_themes.id, anime_themes.slug, anime_themes.theme_type, anime_themes.song_title,
+			anime_themes.artist_name, anime_themes.audio_basename,
+			animes.id AS anime_id, animes.name AS anime_name, animes.name_ru AS anime_name_ru,
+			COALESCE(NULLIF(animes.poster_url, ''), anime_themes.poster_url) AS poster_url,
+			animes.franchise, anime_themes.year
+		FROM anime_themes
+		JOIN animes ON anime_themes.mal_id > 0 AND anime_themes.mal_id::text = animes.shikimori_id
+			AND animes.deleted_at IS NULL AND (animes.hidden = false OR animes.hidden IS NULL)
+		WHERE anime_themes.deleted_at IS NULL AND anime_themes.audio_basename <> ''
+		ORDER BY animes.id, anime_themes.slug`).Scan(&out).Error
+	return out, err
+}
@@ -0,0 +1,250 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrAlreadyVoted is returned by Vote when the user already voted in the match.
+var ErrAlreadyVoted = errors.New("already voted in this match")
+
+type TournamentRepository struct {
+	db *gorm.DB
+}
+
+func NewTournamentRepository(db *gorm.DB) *TournamentRepository {
+	return &TournamentRepository{db: db}
+}
+
+// SeedCandidate is a season theme with the rating it is seeded by.
+type SeedCandidate struct {
+	ThemeID   string
+	AvgScore  float64
+	VoteCount int
+}
+
+// SeasonSeeds returns up to limit themes of a season, best rated first
+// (average score, then vote count, then anime name for a stable order).
+// themeType "" takes both OPs and EDs.
+func (r *TournamentRepository) SeasonSeeds(ctx context.Context, year int, season, themeType string, limit int) ([]SeedCandidate, error) {
+	query := r.db.WithContext(ctx).
+		Table("anime_themes").
+		Select(`anime_themes.id AS theme_id,
+			COALESCE(AVG(theme_ratings.score), 0) AS avg_score,
+			COUNT(theme_ratings.id) AS vote_count`).
+		Joins("LEFT JOIN theme_ratings ON theme_ratings.theme_id = anime_themes.id").
+		Where("anime_themes.deleted_at IS NULL AND anime_themes.year = ? AND anime_themes.season = ?", year, season).
+		Group("anime_themes.id").
+		Order("avg_score DESC, vote_count DESC, anime_themes.anime_name ASC, anime_themes.slug ASC").
+		Limit(limit)
+	if themeType != "" {
+		query = query.Where("anime_themes.theme_type = ?", themeType)
+	}
+
+	var out []SeedCandidate
+	err := query.Scan(&out).Error
+	return out, err
+}
+
+// Create stores a tournament with its entries and bracket.
+func (r *TournamentRepository) Create(ctx context.Context, t *domain.ThemeTournament, entries []domain.ThemeTournamentEntry, build func(id string) []domain.ThemeTournamentMatch) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(t).Error; err != nil {
+			return err
+		}
+		for i := range entries {
+			entries[i].TournamentID = t.ID
+		}
+		if err := tx.Create(&entries).Error; err != nil {
+			return err
+		}
+		matches := build(t.ID)
+		return tx.Create(&matches).Error
+	})
+}
+
+// GetByID returns a tournament, or nil if there is none.
+func (r *TournamentRepository) GetByID(ctx context.Context, id string) (*domain.ThemeTournament, error) {
+	var ts []domain.ThemeTournament
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&ts).Error; err != nil {
+		return nil, err
+	}
+	if len(ts) == 0 {
+		return nil, nil
+	}
+	return &ts[0], nil
+}
+
+// List returns tournaments, newest start first, optionally of one status.
+func (r *TournamentRepository) List(ctx context.Context, status string, limit int) ([]domain.ThemeTournament, error) {
+	query := r.db.WithContext(ctx).Order("starts_at DESC").Limit(limit)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var ts []domain.ThemeTournament
+	err := query.Find(&ts).Error
+	return ts, err
+}
+
+// Entries returns a tournament's entries by seed, with their themes.
+func (r *TournamentRepository) Entries(ctx context.Context, tournamentID string) ([]domain.ThemeTournamentEntry, error) {
+	var entries []domain.ThemeTournamentEntry
+	if err := r.db.WithContext(ctx).Where("tournament_id = ?", tournamentID).Order("seed ASC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return entries, nil
+	}
+
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ThemeID
+	}
+	// Unscoped: an archived bracket keeps showing themes deleted upstream.
+	var themes []domain.AnimeTheme
+	if err := r.db.WithContext(ctx).Unscoped().Where("id IN ?", ids).Find(&themes).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*domain.AnimeTheme, len(themes))
+	for i := range themes {
+		byID[themes[i].ID] = &themes[i]
+	}
+	for i := range entries {
+		entries[i].Theme = byID[entries[i].ThemeID]
+	}
+	return entries, nil
+}
+
+// Matches returns a tournament's bracket by round and slot.
+func (r *TournamentRepository) Matches(ctx context.Context, tournamentID string) ([]domain.ThemeTournamentMatch, error) {
+	var matches []domain.ThemeTournamentMatch
+	err := r.db.WithContext(ctx).Where("tournament_id = ?", tournamentID).Order("round ASC, slot ASC").Find(&matches).Error
+	return matches, err
+}
+
+// RoundMatches returns the matches of one round by slot.
+func (r *TournamentRepository) RoundMatches(ctx context.Context, tournamentID string, round int) ([]domain.ThemeTournamentMatch, error) {
+	var matches []domain.ThemeTournamentMatch
+	err := r.db.WithContext(ctx).Where("tournament_id = ? AND round = ?", tournamentID, round).Order("slot ASC").Find(&matches).Error
+	return matches, err
+}
+
+// GetMatch returns a match of a tournament, or nil if there is none.
+func (r *TournamentRepository) GetMatch(ctx context.Context, tournamentID, matchID string) (*domain.ThemeTournamentMatch, error) {
+	var matches []domain.ThemeTournamentMatch
+	if err := r.db.WithContext(ctx).Where("id = ? AND tournament_id = ?", matchID, tournamentID).Limit(1).Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	return &matches[0], nil
+}
+
+// SaveTournament writes a tournament's mutable fields.
+func (r *TournamentRepository) SaveTournament(ctx context.Context, t *domain.ThemeTournament) error {
+	return r.db.WithContext(ctx).Save(t).Error
+}
+
+// SaveMatch writes a match's mutable fields.
+func (r *TournamentRepository) SaveMatch(ctx context.Context, m *domain.ThemeTournamentMatch) error {
+	return r.db.WithContext(ctx).Save(m).Error
+}
+
+// Vote records a vote; ErrAlreadyVoted if the user already voted in the match.
+func (r *TournamentRepository) Vote(ctx context.Context, v *domain.ThemeTournamentVote) error {
+	v.CreatedAt = time.Now()
+	res := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(v)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrAlreadyVoted
+	}
+	return nil
+}
+
+// VoteCounts returns the votes per theme of a match.
+func (r *TournamentRepository) VoteCounts(ctx context.Context, matchID string) (map[string]int, error) {
+	var rows []struct {
+		ThemeID string
+		Votes   int
+	}
+	err := r.db.WithContext(ctx).Model(&domain.ThemeTournamentVote{}).
+		Select("theme_id, COUNT(*) AS votes").
+		Where("match_id = ?", matchID).
+		Group("theme_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.ThemeID] = row.Votes
+	}
+	return counts, nil
+}
+
+// UserVotes returns the user's vote (theme id) per match of a tournament.
+func (r *TournamentRepository) UserVotes(ctx context.Context, tournamentID, userID string) (map[string]string, error) {
+	var votes []domain.ThemeTournamentVote
+	err := r.db.WithContext(ctx).
+		Joins("JOIN theme_tournament_matches ON theme_tournament_matches.id = theme_tournament_votes.match_id").
+		Where("theme_tournament_matches.tournament_id = ? AND theme_tournament_votes.user_id = ?", tournamentID, userID).
+		Find(&votes).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(votes))
+	for _, v := range votes {
+		out[v.MatchID] = v.ThemeID
+	}
+	return out, nil
+}
+
+// DueIDs returns the tournaments the bracket ticker must look at: scheduled
+// ones whose start has come, and every running one.
+func (r *TournamentRepository) DueIDs(ctx context.Context, now time.Time) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).Model(&domain.ThemeTournament{}).
+		Where("(status = ? AND starts_at <= ?) OR status = ?", domain.TournamentScheduled, now, domain.TournamentRunning).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// WithLock runs fn in a transaction holding the tournament's row lock; fn
+// gets a repository bound to the transaction. A tournament locked by another
+// instance is skipped (fn is not called, t is nil).
+func (r *TournamentRepository) WithLock(ctx context.Context, id string, fn func(tx *TournamentRepository, t *domain.ThemeTournament) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ts []domain.ThemeTournament
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("id = ?", id).Limit(1).Find(&ts).Error; err != nil {
+			return err
+		}
+		if len(ts) == 0 {
+			return nil
+		}
+		return fn(&TournamentRepository{db: tx}, &ts[0])
+	})
+}
+
+// Audience returns the users to notify about a tournament's rounds: everyone
+// who rated one of its themes or voted in one of its matches.
+func (r *TournamentRepository) Audience(ctx context.Context, tournamentID string) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT theme_ratings.user_id::text FROM theme_ratings
+		JOIN theme_tournament_entries ON theme_tournament_entries.theme_id = theme_ratings.theme_id
+		WHERE theme_tournament_entries.tournament_id = ?
+		UNION
+		SELECT theme_tournament_votes.user_id::text FROM theme_tournament_votes
+		JOIN theme_tournament_matches ON theme_tournament_matches.id = theme_tournament_votes.match_id
+		WHERE theme_tournament_matches.tournament_id = ?`, tournamentID, tournamentID).Scan(&ids).Error
+	return ids, err
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/repo"
+)
+
+type ArtistService struct {
+	artistRepo *repo.ArtistRepository
+	ratingRepo *repo.RatingRepository
+	log        *logger.Logger
+}
+
+func NewArtistService(artistRepo *repo.ArtistRepository, ratingRepo *repo.RatingRepository, log *logger.Logger) *ArtistService {
+	return &ArtistService{
+		artistRepo: artistRepo,
+		ratingRepo: ratingRepo,
+		log:        log,
+	}
+}
+
+// List returns artists with their theme counts.
+func (s *ArtistService) List(ctx context.Context, params domain.ArtistListParams) ([]domain.ThemeArtist, error) {
+	params.Query = strings.TrimSpace(params.Query)
+	artists, err := s.artistRepo.List(ctx, params)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "list artists")
+	}
+	return artists, nil
+}
+
+// Page returns an artist with all their themes and ratings, including user
+// scores if userID is provided.
+func (s *ArtistService) Page(ctx context.Context, slug, userID string) (*domain.ArtistPage, error) {
+	artist, err := s.artistRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "get artist")
+	}
+	if artist == nil {
+		return nil, errors.NotFound("artist")
+	}
+
+	themes, err := s.artistRepo.Themes(ctx, artist.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "artist themes")
+	}
+	if themes == nil {
+		themes = []domain.AnimeTheme{}
+	}
+	attachUserScores(ctx, s.ratingRepo, s.log, userID, themes)
+
+	artist.ThemeCount = len(themes)
+	return &domain.ArtistPage{ThemeArtist: *artist, Themes: themes}, nil
+}
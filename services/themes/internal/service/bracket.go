@@ -0,0 +1,92 @@
+package service
+
+import (
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+)
+
+// bracketSize is the smallest power of two holding n entries (at least 2).
+func bracketSize(n int) int {
+	size := 2
+	for size < n {
+		size *= 2
+	}
+	return size
+}
+
+// bracketRounds is log2(size).
+func bracketRounds(size int) int {
+	rounds := 0
+	for s := size; s > 1; s /= 2 {
+		rounds++
+	}
+	return rounds
+}
+
+// seedOrder lists the seeds of a bracket in slot order: consecutive pairs
+// meet in round 1, and seeds 1 and 2 can only meet in the final. For 8 it
+// is 1 8 4 5 2 7 3 6.
+func seedOrder(size int) []int {
+	order := []int{1}
+	for n := 1; n < size; n *= 2 {
+		next := make([]int, 0, 2*n)
+		for _, s := range order {
+			next = append(next, s, 2*n+1-s)
+		}
+		order = next
+	}
+	return order
+}
+
+// buildBracket lays out every match of a bracket for entries seeded 1..n.
+// Round 1 pairs the seeds by seedOrder; seeds above n are byes, which the
+// top seeds get. Later rounds start empty and are filled as winners advance.
+func buildBracket(tournamentID string, entries []domain.ThemeTournamentEntry, size int) []domain.ThemeTournamentMatch {
+	bySeed := make(map[int]string, len(entries))
+	for _, e := range entries {
+		bySeed[e.Seed] = e.ThemeID
+	}
+	themeAt := func(seed int) *string {
+		if id, ok := bySeed[seed]; ok {
+			return &id
+		}
+		return nil
+	}
+
+	order := seedOrder(size)
+	matches := make([]domain.ThemeTournamentMatch, 0, size-1)
+	for slot := 0; slot < size/2; slot++ {
+		matches = append(matches, domain.ThemeTournamentMatch{
+			TournamentID: tournamentID,
+			Round:        1,
+			Slot:         slot,
+			ThemeAID:     themeAt(order[2*slot]),
+			ThemeBID:     themeAt(order[2*slot+1]),
+		})
+	}
+	for round, n := 2, size/4; n >= 1; round, n = round+1, n/2 {
+		for slot := 0; slot < n; slot++ {
+			matches = append(matches, domain.ThemeTournamentMatch{TournamentID: tournamentID, Round: round, Slot: slot})
+		}
+	}
+	return matches
+}
+
+// decideMatch picks the winner of a closed match: a missing opponent is a
+// bye, then more votes win, and a tie goes to the better (lower) seed — the
+// theme that was rated higher when the bracket was drawn.
+func decideMatch(m *domain.ThemeTournamentMatch, votesA, votesB int, seeds map[string]int) (string, string) {
+	switch {
+	case m.ThemeBID == nil:
+		return *m.ThemeAID, domain.MatchDecidedBye
+	case m.ThemeAID == nil:
+		return *m.ThemeBID, domain.MatchDecidedBye
+	case votesA > votesB:
+		return *m.ThemeAID, domain.MatchDecidedVotes
+	case votesB > votesA:
+		return *m.ThemeBID, domain.MatchDecidedVotes
+	case seeds[*m.ThemeBID] < seeds[*m.ThemeAID]:
+		return *m.ThemeBID, domain.MatchDecidedSeed
+	default:
+		return *m.ThemeAID, domain.MatchDecidedSeed
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+)
+
+func TestSeedOrder(t *testing.T) {
+	if got, want := seedOrder(8), []int{1, 8, 4, 5, 2, 7, 3, 6}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("seedOrder(8) = %v, want %v", got, want)
+	}
+	if got, want := seedOrder(2), []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("seedOrder(2) = %v, want %v", got, want)
+	}
+}
+
+func TestBracketSize(t *testing.T) {
+	cases := map[int]int{1: 2, 2: 2, 3: 4, 5: 8, 8: 8, 9: 16, 64: 64}
+	for n, want := range cases {
+		if got := bracketSize(n); got != want {
+			t.Errorf("bracketSize(%d) = %d, want %d", n, got, want)
+		}
+	}
+	if got := bracketRounds(16); got != 4 {
+		t.Errorf("bracketRounds(16) = %d, want 4", got)
+	}
+}
+
+func TestBuildBracket_ByesGoToTopSeeds(t *testing.T) {
+	var entries []domain.ThemeTournamentEntry
+	for i, id := range []string{"s1", "s2", "s3", "s4", "s5"} {
+		entries = append(entries, domain.ThemeTournamentEntry{ThemeID: id, Seed: i + 1})
+	}
+
+	matches := buildBracket("t", entries, 8)
+	if len(matches) != 7 {
+		t.Fatalf("got %d matches, want 7", len(matches))
+	}
+
+	byes := map[string]bool{}
+	for _, m := range matches[:4] {
+		if m.Round != 1 {
+			t.Fatalf("match %d is round %d, want 1", m.Slot, m.Round)
+		}
+		if m.ThemeAID == nil {
+			t.Fatalf("slot %d has no first theme", m.Slot)
+		}
+		if m.ThemeBID == nil {
+			byes[*m.ThemeAID] = true
+		}
+	}
+	if want := map[string]bool{"s1": true, "s2": true, "s3": true}; !reflect.DeepEqual(byes, want) {
+		t.Fatalf("byes = %v, want %v", byes, want)
+	}
+
+	for _, m := range matches[4:] {
+		if m.ThemeAID != nil || m.ThemeBID != nil {
+			t.Fatalf("round %d slot %d is pre-filled", m.Round, m.Slot)
+		}
+	}
+	if last := matches[6]; last.Round != 3 || last.Slot != 0 {
+		t.Fatalf("final = round %d slot %d", last.Round, last.Slot)
+	}
+}
+
+func TestDecideMatch(t *testing.T) {
+	a, b := "a", "b"
+	seeds := map[string]int{"a": 5, "b": 2}
+	m := &domain.ThemeTournamentMatch{ThemeAID: &a, ThemeBID: &b}
+
+	if w, by := decideMatch(m, 3, 1, seeds); w != "a" || by != domain.MatchDecidedVotes {
+		t.Errorf("more votes: %s by %s", w, by)
+	}
+	if w, by := decideMatch(m, 2, 2, seeds); w != "b" || by != domain.MatchDecidedSeed {
+		t.Errorf("tie: %s by %s, want the better seed b", w, by)
+	}
+	if w, by := decideMatch(&domain.ThemeTournamentMatch{ThemeAID: &a}, 0, 0, seeds); w != "a" || by != domain.MatchDecidedBye {
+		t.Errorf("bye: %s by %s", w, by)
+	}
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/repo"
+	"gorm.io/gorm"
+)
+
+const (
+	maxPlaylistsPerUser   = 100
+	maxPlaylistItems      = 500
+	maxPlaylistNameLen    = 100
+	maxPlaylistDescLength = 500
+)
+
+// playlistStore is the subset of *repo.PlaylistRepository PlaylistService uses.
+type playlistStore interface {
+	Create(ctx context.Context, p *domain.ThemePlaylist) error
+	GetByID(ctx context.Context, id string) (*domain.ThemePlaylist, error)
+	ListByUser(ctx context.Context, userID string) ([]domain.ThemePlaylist, error)
+	CountByUser(ctx context.Context, userID string) (int64, error)
+	Update(ctx context.Context, p *domain.ThemePlaylist) error
+	Delete(ctx context.Context, id string) error
+	Themes(ctx context.Context, playlistID string) ([]domain.AnimeTheme, error)
+	AddItem(ctx context.Context, playlistID, themeID string) error
+	RemoveItem(ctx context.Context, playlistID, themeID string) error
+	CountItems(ctx context.Context, playlistID string) (int64, error)
+	Reorder(ctx context.Context, playlistID string, themeIDs []string) error
+	Clone(ctx context.Context, src *domain.ThemePlaylist, clone *domain.ThemePlaylist) error
+}
+
+// themeGetter looks up one theme (*repo.ThemeRepository in production).
+type themeGetter interface {
+	GetByID(ctx context.Context, id string) (*domain.AnimeTheme, error)
+}
+
+type PlaylistService struct {
+	playlistRepo playlistStore
+	themeRepo    themeGetter
+	log          *logger.Logger
+}
+
+func NewPlaylistService(playlistRepo playlistStore, themeRepo themeGetter, log *logger.Logger) *PlaylistService {
+	return &PlaylistService{
+		playlistRepo: playlistRepo,
+		themeRepo:    themeRepo,
+		log:          log,
+	}
+}
+
+// Create makes an empty playlist for the user.
+func (s *PlaylistService) Create(ctx context.Context, userID string, req domain.PlaylistRequest) (*domain.ThemePlaylist, error) {
+	if req.Name == nil {
+		return nil, errors.InvalidInput("name is required")
+	}
+	p := &domain.ThemePlaylist{UserID: userID}
+	if err := applyPlaylistRequest(p, req); err != nil {
+		return nil, err
+	}
+
+	n, err := s.playlistRepo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "count playlists")
+	}
+	if n >= maxPlaylistsPerUser {
+		return nil, errors.InvalidInput("playlist limit reached")
+	}
+
+	if err := s.playlistRepo.Create(ctx, p); err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "create playlist")
+	}
+	return p, nil
+}
+
+// ListMine returns the user's playlists.
+func (s *PlaylistService) ListMine(ctx context.Context, userID string) ([]domain.ThemePlaylist, error) {
+	playlists, err := s.playlistRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "list playlists")
+	}
+	return playlists, nil
+}
+
+// Get returns a playlist with its themes. Private playlists are visible to
+// their owner only; to anyone else they do not exist.
+func (s *PlaylistService) Get(ctx context.Context, id, viewerID string) (*domain.PlaylistDetail, error) {
+	p, err := s.visible(ctx, id, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	themes, err := s.playlistRepo.Themes(ctx, p.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "playlist themes")
+	}
+	if themes == nil {
+		themes = []domain.AnimeTheme{}
+	}
+	return &domain.PlaylistDetail{ThemePlaylist: *p, Themes: themes}, nil
+}
+
+// Update changes the name, description or visibility of the user's playlist.
+func (s *PlaylistService) Update(ctx context.Context, id, userID string, req domain.PlaylistRequest) (*domain.ThemePlaylist, error) {
+	p, err := s.owned(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyPlaylistRequest(p, req); err != nil {
+		return nil, err
+	}
+	if err := s.playlistRepo.Update(ctx, p); err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "update playlist")
+	}
+	return p, nil
+}
+
+// Delete removes the user's playlist.
+func (s *PlaylistService) Delete(ctx context.Context, id, userID string) error {
+	if _, err := s.owned(ctx, id, userID); err != nil {
+		return err
+	}
+	if err := s.playlistRepo.Delete(ctx, id); err != nil {
+		return errors.Wrap(err, errors.CodeInternal, "delete playlist")
+	}
+	return nil
+}
+
+// AddItem appends a theme to the user's playlist.
+func (s *PlaylistService) AddItem(ctx context.Context, id, userID, themeID string) error {
+	if _, err := s.owned(ctx, id, userID); err != nil {
+		return err
+	}
+	theme, err := s.themeRepo.GetByID(ctx, themeID)
+	if err != nil || theme == nil || theme.ID == "" {
+		return errors.NotFound("theme")
+	}
+	n, err := s.playlistRepo.CountItems(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, errors.CodeInternal, "count playlist items")
+	}
+	if n >= maxPlaylistItems {
+		return errors.InvalidInput("playlist is full")
+	}
+	if err := s.playlistRepo.AddItem(ctx, id, themeID); err != nil {
+		return errors.Wrap(err, errors.CodeInternal, "add playlist item")
+	}
+	return nil
+}
+
+// RemoveItem removes a theme from the user's playlist.
+func (s *PlaylistService) RemoveItem(ctx context.Context, id, userID, themeID string) error {
+	if _, err := s.owned(ctx, id, userID); err != nil {
+		return err
+	}
+	if err := s.playlistRepo.RemoveItem(ctx, id, themeID); err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.NotFound("playlist item")
+		}
+		return errors.Wrap(err, errors.CodeInternal, "remove playlist item")
+	}
+	return nil
+}
+
+// Reorder sets the play order of the user's playlist; themeIDs must list
+// every item exactly once.
+func (s *PlaylistService) Reorder(ctx context.Context, id, userID string, themeIDs []string) error {
+	if _, err := s.owned(ctx, id, userID); err != nil {
+		return err
+	}
+	if err := s.playlistRepo.Reorder(ctx, id, themeIDs); err != nil {
+		if stderrors.Is(err, repo.ErrPlaylistItemsMismatch) {
+			return errors.InvalidInput(err.Error())
+		}
+		return errors.Wrap(err, errors.CodeInternal, "reorder playlist")
+	}
+	return nil
+}
+
+// Clone copies a playlist visible to the user into a new private playlist of
+// theirs. name defaults to the source name.
+func (s *PlaylistService) Clone(ctx context.Context, id, userID, name string) (*domain.ThemePlaylist, error) {
+	src, err := s.visible(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(name) == "" {
+		name = src.Name
+	}
+	clone := &domain.ThemePlaylist{UserID: userID, Description: src.Description, ClonedFrom: &src.ID}
+	if err := applyPlaylistRequest(clone, domain.PlaylistRequest{Name: &name}); err != nil {
+		return nil, err
+	}
+
+	n, err := s.playlistRepo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "count playlists")
+	}
+	if n >= maxPlaylistsPerUser {
+		return nil, errors.InvalidInput("playlist limit reached")
+	}
+
+	if err := s.playlistRepo.Clone(ctx, src, clone); err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "clone playlist")
+	}
+	clone.ItemCount = src.ItemCount
+	return clone, nil
+}
+
+func (s *PlaylistService) visible(ctx context.Context, id, viewerID string) (*domain.ThemePlaylist, error) {
+	p, err := s.playlistRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "get playlist")
+	}
+	if p == nil || (!p.IsPublic && p.UserID != viewerID) {
+		return nil, errors.NotFound("playlist")
+	}
+	return p, nil
+}
+
+func (s *PlaylistService) owned(ctx context.Context, id, userID string) (*domain.ThemePlaylist, error) {
+	p, err := s.playlistRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "get playlist")
+	}
+	if p == nil || p.UserID != userID {
+		return nil, errors.NotFound("playlist")
+	}
+	return p, nil
+}
+
+// applyPlaylistRequest validates and applies the set fields of req.
+func applyPlaylistRequest(p *domain.ThemePlaylist, req domain.PlaylistRequest) error {
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return errors.InvalidInput("name is required")
+		}
+		if utf8.RuneCountInString(name) > maxPlaylistNameLen {
+			return errors.InvalidInput("name is too long")
+		}
+		p.Name = name
+	}
+	if req.Description != nil {
+		desc := strings.TrimSpace(*req.Description)
+		if utf8.RuneCountInString(desc) > maxPlaylistDescLength {
+			return errors.InvalidInput("description is too long")
+		}
+		p.Description = desc
+	}
+	if req.IsPublic != nil {
+		p.IsPublic = *req.IsPublic
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/repo"
+)
+
+// fakePlaylists is an in-memory playlistStore with the repository's
+// ordering and reorder rules.
+type fakePlaylists struct {
+	playlists map[string]*domain.ThemePlaylist
+	items     map[string][]string // playlist id → theme ids in play order
+	themes    map[string]domain.AnimeTheme
+	nextID    int
+}
+
+func newFakePlaylists(themeIDs ...string) *fakePlaylists {
+	f := &fakePlaylists{
+		playlists: map[string]*domain.ThemePlaylist{},
+		items:     map[string][]string{},
+		themes:    map[string]domain.AnimeTheme{},
+	}
+	for _, id := range themeIDs {
+		f.themes[id] = domain.AnimeTheme{ID: id, Slug: "OP1", AnimeName: "Anime " + id, AudioBasename: id + ".ogg"}
+	}
+	return f
+}
+
+func (f *fakePlaylists) Create(_ context.Context, p *domain.ThemePlaylist) error {
+	f.nextID++
+	p.ID = fmt.Sprintf("pl-%d", f.nextID)
+	cp := *p
+	f.playlists[p.ID] = &cp
+	return nil
+}
+
+func (f *fakePlaylists) GetByID(_ context.Context, id string) (*domain.ThemePlaylist, error) {
+	p, ok := f.playlists[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *p
+	cp.ItemCount = len(f.items[id])
+	return &cp, nil
+}
+
+func (f *fakePlaylists) ListByUser(_ context.Context, userID string) ([]domain.ThemePlaylist, error) {
+	var out []domain.ThemePlaylist
+	for _, p := range f.playlists {
+		if p.UserID == userID {
+			out = append(out, *p)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (f *fakePlaylists) CountByUser(ctx context.Context, userID string) (int64, error) {
+	list, _ := f.ListByUser(ctx, userID)
+	return int64(len(list)), nil
+}
+
+func (f *fakePlaylists) Update(_ context.Context, p *domain.ThemePlaylist) error {
+	cp := *p
+	f.playlists[p.ID] = &cp
+	return nil
+}
+
+func (f *fakePlaylists) Delete(_ context.Context, id string) error {
+	delete(f.playlists, id)
+	delete(f.items, id)
+	return nil
+}
+
+func (f *fakePlaylists) Themes(_ context.Context, playlistID string) ([]domain.AnimeTheme, error) {
+	var out []domain.AnimeTheme
+	for _, id := range f.items[playlistID] {
+		out = append(out, f.themes[id])
+	}
+	return out, nil
+}
+
+func (f *fakePlaylists) AddItem(_ context.Context, playlistID, themeID string) error {
+	for _, id := range f.items[playlistID] {
+		if id == themeID {
+			return nil
+		}
+	}
+	f.items[playlistID] = append(f.items[playlistID], themeID)
+	return nil
+}
+
+func (f *fakePlaylists) RemoveItem(context.Context, string, string) error { return nil }
+
+func (f *fakePlaylists) CountItems(_ context.Context, playlistID string) (int64, error) {
+	return int64(len(f.items[playlistID])), nil
+}
+
+func (f *fakePlaylists) Reorder(_ context.Context, playlistID string, themeIDs []string) error {
+	current := append([]string(nil), f.items[playlistID]...)
+	want := append([]string(nil), themeIDs...)
+	sort.Strings(current)
+	sort.Strings(want)
+	if strings.Join(current, ",") != strings.Join(want, ",") {
+		return repo.ErrPlaylistItemsMismatch
+	}
+	f.items[playlistID] = append([]string(nil), themeIDs...)
+	return nil
+}
+
+func (f *fakePlaylists) Clone(ctx context.Context, src *domain.ThemePlaylist, clone *domain.ThemePlaylist) error {
+	if err := f.Create(ctx, clone); err != nil {
+		return err
+	}
+	f.items[clone.ID] = append([]string(nil), f.items[src.ID]...)
+	return nil
+}
+
+// fakeThemeGetter resolves the store's themes.
+type fakeThemeGetter struct{ f *fakePlaylists }
+
+func (g fakeThemeGetter) GetByID(_ context.Context, id string) (*domain.AnimeTheme, error) {
+	t, ok := g.f.themes[id]
+	if !ok {
+		return &domain.AnimeTheme{}, nil // the repo scans a miss into an empty theme
+	}
+	return &t, nil
+}
+
+func newPlaylistFixture(t *testing.T, themeIDs ...string) (*PlaylistService, *fakePlaylists) {
+	t.Helper()
+	store := newFakePlaylists(themeIDs...)
+	return NewPlaylistService(store, fakeThemeGetter{store}, logger.Default()), store
+}
+
+func errCode(err error) errors.ErrorCode {
+	if appErr, ok := errors.IsAppError(err); ok {
+		return appErr.Code
+	}
+	return ""
+}
+
+func strPtr(s string) *string { return &s }
+
+func boolPtr(b bool) *bool { return &b }
+
+func playlistIDs(themes []domain.AnimeTheme) string {
+	ids := make([]string, 0, len(themes))
+	for _, t := range themes {
+		ids = append(ids, t.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestPlaylist_Reorder(t *testing.T) {
+	svc, _ := newPlaylistFixture(t, "t1", "t2", "t3")
+	ctx := context.Background()
+	p, err := svc.Create(ctx, "alice", domain.PlaylistRequest{Name: strPtr("  Drive  ")})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if p.Name != "Drive" || p.IsPublic {
+		t.Fatalf("created = %+v; want trimmed private playlist", p)
+	}
+	for _, id := range []string{"t1", "t2", "t3"} {
+		if err := svc.AddItem(ctx, p.ID, "alice", id); err != nil {
+			t.Fatalf("AddItem %s: %v", id, err)
+		}
+	}
+	if err := svc.AddItem(ctx, p.ID, "alice", "missing"); errCode(err) != errors.CodeNotFound {
+		t.Errorf("AddItem unknown theme: err = %v; want not found", err)
+	}
+
+	if err := svc.Reorder(ctx, p.ID, "alice", []string{"t3", "t1", "t2"}); err != nil {
+		t.Fatalf("Reorder: %v", err)
+	}
+	got, err := svc.Get(ctx, p.ID, "alice")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if ids := playlistIDs(got.Themes); ids != "t3,t1,t2" {
+		t.Errorf("order = %s; want t3,t1,t2", ids)
+	}
+
+	// The M3U export plays in the same order.
+	var m3u strings.Builder
+	if err := WriteM3U(&m3u, "https://x", got.Name, got.Themes); err != nil {
+		t.Fatalf("WriteM3U: %v", err)
+	}
+	if i3, i1 := strings.Index(m3u.String(), "/t3.ogg"), strings.Index(m3u.String(), "/t1.ogg"); i3 < 0 || i1 < 0 || i3 > i1 {
+		t.Errorf("m3u order wrong:\n%s", m3u.String())
+	}
+
+	for name, ids := range map[string][]string{
+		"missing item":   {"t1", "t2"},
+		"duplicate item": {"t1", "t1", "t2"},
+		"foreign item":   {"t1", "t2", "t9"},
+	} {
+		if err := svc.Reorder(ctx, p.ID, "alice", ids); errCode(err) != errors.CodeInvalidInput {
+			t.Errorf("%s: err = %v; want invalid input", name, err)
+		}
+	}
+	if err := svc.Reorder(ctx, p.ID, "bob", []string{"t1", "t2", "t3"}); errCode(err) != errors.CodeNotFound {
+		t.Errorf("reorder by non-owner: err = %v; want not found", err)
+	}
+}
+
+func TestPlaylist_PrivateVsShared(t *testing.T) {
+	svc, _ := newPlaylistFixture(t, "t1")
+	ctx := context.Background()
+	p, err := svc.Create(ctx, "alice", domain.PlaylistRequest{Name: strPtr("Mine")})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if _, err := svc.Get(ctx, p.ID, "alice"); err != nil {
+		t.Fatalf("owner Get: %v", err)
+	}
+	for _, viewer := range []string{"bob", ""} {
+		if _, err := svc.Get(ctx, p.ID, viewer); errCode(err) != errors.CodeNotFound {
+			t.Errorf("private Get by %q: err = %v; want not found", viewer, err)
+		}
+	}
+	if _, err := svc.Clone(ctx, p.ID, "bob", ""); errCode(err) != errors.CodeNotFound {
+		t.Errorf("clone of private playlist: err = %v; want not found", err)
+	}
+
+	if _, err := svc.Update(ctx, p.ID, "alice", domain.PlaylistRequest{IsPublic: boolPtr(true)}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	for _, viewer := range []string{"bob", ""} {
+		if _, err := svc.Get(ctx, p.ID, viewer); err != nil {
+			t.Errorf("shared Get by %q: %v", viewer, err)
+		}
+	}
+	// Sharing grants reading, not editing.
+	if _, err := svc.Update(ctx, p.ID, "bob", domain.PlaylistRequest{Name: strPtr("Hijacked")}); errCode(err) != errors.CodeNotFound {
+		t.Errorf("Update by non-owner: err = %v; want not found", err)
+	}
+	if err := svc.AddItem(ctx, p.ID, "bob", "t1"); errCode(err) != errors.CodeNotFound {
+		t.Errorf("AddItem by non-owner: err = %v; want not found", err)
+	}
+	if err := svc.Delete(ctx, p.ID, "bob"); errCode(err) != errors.CodeNotFound {
+		t.Errorf("Delete by non-owner: err = %v; want not found", err)
+	}
+}
+
+func TestPlaylist_Clone(t *testing.T) {
+	svc, store := newPlaylistFixture(t, "t1", "t2")
+	ctx := context.Background()
+	src, err := svc.Create(ctx, "alice", domain.PlaylistRequest{
+		Name: strPtr("Summer"), Description: strPtr("beach OPs"), IsPublic: boolPtr(true),
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	_ = svc.AddItem(ctx, src.ID, "alice", "t2")
+	_ = svc.AddItem(ctx, src.ID, "alice", "t1")
+
+	clone, err := svc.Clone(ctx, src.ID, "bob", "")
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	if clone.UserID != "bob" || clone.Name != "Summer" || clone.Description != "beach OPs" || clone.IsPublic ||
+		clone.ClonedFrom == nil || *clone.ClonedFrom != src.ID || clone.ItemCount != 2 {
+		t.Fatalf("clone = %+v; want bob's private copy of %s with 2 items", clone, src.ID)
+	}
+	got, err := svc.Get(ctx, clone.ID, "bob")
+	if err != nil {
+		t.Fatalf("Get clone: %v", err)
+	}
+	if ids := playlistIDs(got.Themes); ids != "t2,t1" {
+		t.Errorf("clone order = %s; want t2,t1", ids)
+	}
+
+	// The copy is independent of the source.
+	if err := svc.Reorder(ctx, clone.ID, "bob", []string{"t1", "t2"}); err != nil {
+		t.Fatalf("Reorder clone: %v", err)
+	}
+	if ids := strings.Join(store.items[src.ID], ","); ids != "t2,t1" {
+		t.Errorf("source order = %s after reordering the clone", ids)
+	}
+
+	named, err := svc.Clone(ctx, src.ID, "bob", "  Mine now ")
+	if err != nil || named.Name != "Mine now" {
+		t.Errorf("named clone = %+v, %v; want name %q", named, err, "Mine now")
+	}
+
+	for i := 0; i < maxPlaylistsPerUser; i++ {
+		store.playlists[fmt.Sprintf("carol-%d", i)] = &domain.ThemePlaylist{ID: fmt.Sprintf("carol-%d", i), UserID: "carol"}
+	}
+	if _, err := svc.Clone(ctx, src.ID, "carol", ""); errCode(err) != errors.CodeInvalidInput {
+		t.Errorf("clone over the limit: err = %v; want invalid input", err)
+	}
+}
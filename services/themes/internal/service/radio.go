@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/repo"
+)
+
+const (
+	defaultRadioLimit = 50
+	maxRadioLimit     = 200
+	// radioMinScore is the lowest rating that seeds the "ratings" radio.
+	radioMinScore = 7
+	// radioHistorySize themes most recently queued for a user are held back
+	// from the next queue while enough other themes are left.
+	radioHistorySize = 200
+	radioHistoryTTL  = 14 * 24 * time.Hour
+)
+
+// radioHistory keeps the recently queued theme ids per user
+// (*cache.RedisCache satisfies it).
+type radioHistory interface {
+	Get(ctx context.Context, key string, dest interface{}) error
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+}
+
+type RadioService struct {
+	themeRepo *repo.ThemeRepository
+	history   radioHistory
+	log       *logger.Logger
+}
+
+func NewRadioService(themeRepo *repo.ThemeRepository, history radioHistory, log *logger.Logger) *RadioService {
+	return &RadioService{
+		themeRepo: themeRepo,
+		history:   history,
+		log:       log,
+	}
+}
+
+func radioHistoryKey(userID string) string { return "themes:radio:recent:" + userID }
+
+// Queue builds a shuffled radio queue from the seed. The ratings and
+// completed seeds need a user; logged-in users' queues skip what they were
+// queued recently.
+func (s *RadioService) Queue(ctx context.Context, params domain.RadioParams) ([]domain.AnimeTheme, error) {
+	if params.Limit <= 0 {
+		params.Limit = defaultRadioLimit
+	}
+	if params.Limit > maxRadioLimit {
+		params.Limit = maxRadioLimit
+	}
+
+	var (
+		pool []domain.AnimeTheme
+		err  error
+	)
+	switch params.Seed {
+	case domain.RadioSeedRatings, domain.RadioSeedCompleted:
+		if params.UserID == "" {
+			return nil, errors.Unauthorized("login required for this radio")
+		}
+		if params.Seed == domain.RadioSeedRatings {
+			pool, err = s.themeRepo.RadioByRatings(ctx, params.UserID, radioMinScore)
+		} else {
+			pool, err = s.themeRepo.RadioByCompleted(ctx, params.UserID)
+		}
+	case domain.RadioSeedSeason:
+		if params.Year <= 0 || !validSeason(params.Season) {
+			return nil, errors.InvalidInput("year and season are required")
+		}
+		pool, err = s.themeRepo.RadioBySeason(ctx, params.Year, params.Season)
+	default:
+		return nil, errors.InvalidInput("seed must be ratings, completed or season")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "radio pool")
+	}
+
+	var recent []string
+	if params.UserID != "" && s.history != nil {
+		// A miss or a Redis outage only means no repeat avoidance.
+		_ = s.history.Get(ctx, radioHistoryKey(params.UserID), &recent)
+	}
+
+	shuffle := params.Shuffle
+	if shuffle == 0 {
+		shuffle = time.Now().UnixNano()
+	}
+	queue := BuildRadioQueue(pool, recent, params.Limit, rand.New(rand.NewSource(shuffle)))
+
+	if params.UserID != "" && s.history != nil && len(queue) > 0 {
+		for _, t := range queue {
+			recent = append(recent, t.ID)
+		}
+		if len(recent) > radioHistorySize {
+			recent = recent[len(recent)-radioHistorySize:]
+		}
+		if err := s.history.Set(ctx, radioHistoryKey(params.UserID), recent, radioHistoryTTL); err != nil {
+			s.log.Warnw("failed to save radio history", "user_id", params.UserID, "error", err)
+		}
+	}
+	return queue, nil
+}
+
+// BuildRadioQueue shuffles pool and takes up to limit themes with at most one
+// theme per artist. Recently queued themes are used only when the rest of
+// the pool runs out. Themes without an artist are never deduplicated.
+func BuildRadioQueue(pool []domain.AnimeTheme, recent []string, limit int, rng *rand.Rand) []domain.AnimeTheme {
+	shuffled := make([]domain.AnimeTheme, len(pool))
+	copy(shuffled, pool)
+	rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	recentSet := make(map[string]struct{}, len(recent))
+	for _, id := range recent {
+		recentSet[id] = struct{}{}
+	}
+
+	queue := make([]domain.AnimeTheme, 0, limit)
+	taken := make(map[string]struct{}, limit)
+	artists := make(map[string]struct{}, limit)
+	pick := func(allowRecent bool) {
+		for _, t := range shuffled {
+			if len(queue) >= limit {
+				return
+			}
+			if _, dup := taken[t.ID]; dup || t.AudioBasename == "" {
+				continue
+			}
+			if _, isRecent := recentSet[t.ID]; isRecent && !allowRecent {
+				continue
+			}
+			artist := strings.ToLower(strings.TrimSpace(t.ArtistName))
+			if artist != "" {
+				if _, dup := artists[artist]; dup {
+					continue
+				}
+				artists[artist] = struct{}{}
+			}
+			taken[t.ID] = struct{}{}
+			queue = append(queue, t)
+		}
+	}
+	pick(false)
+	pick(true)
+	return queue
+}
+
+// WriteM3U writes themes as an extended M3U playlist of the proxied audio
+// URLs (baseURL + /api/themes/audio/{basename}). Themes without audio are
+// skipped.
+func WriteM3U(w io.Writer, baseURL, title string, themes []domain.AnimeTheme) error {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	if title != "" {
+		fmt.Fprintf(&b, "#PLAYLIST:%s\n", m3uText(title))
+	}
+	for _, t := range themes {
+		if t.AudioBasename == "" {
+			continue
+		}
+		label := t.SongTitle
+		if label == "" {
+			label = t.Slug
+		}
+		if t.ArtistName != "" {
+			label = t.ArtistName + " - " + label
+		}
+		fmt.Fprintf(&b, "#EXTINF:-1,%s (%s %s)\n", m3uText(label), m3uText(t.AnimeName), m3uText(t.Slug))
+		fmt.Fprintf(&b, "%s/api/themes/audio/%s\n", baseURL, url.PathEscape(t.AudioBasename))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// m3uText keeps a value on one line.
+func m3uText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func validSeason(season string) bool {
+	switch season {
+	case "winter", "spring", "summer", "fall":
+		return true
+	}
+	return false
+}
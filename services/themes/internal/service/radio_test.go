@@ -0,0 +1,107 @@
+package service
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+)
+
+func radioTheme(id, artist string) domain.AnimeTheme {
+	return domain.AnimeTheme{ID: id, ArtistName: artist, AudioBasename: id + ".ogg"}
+}
+
+func TestBuildRadioQueue_OneThemePerArtist(t *testing.T) {
+	pool := []domain.AnimeTheme{
+		radioTheme("a1", "LiSA"),
+		radioTheme("a2", " lisa "),
+		radioTheme("b1", "YOASOBI"),
+		radioTheme("c1", ""),
+		radioTheme("c2", ""),
+		{ID: "silent", ArtistName: "Aimer"}, // no audio
+	}
+
+	for seed := int64(1); seed <= 20; seed++ {
+		queue := BuildRadioQueue(pool, nil, 10, rand.New(rand.NewSource(seed)))
+		if len(queue) != 4 {
+			t.Fatalf("seed %d: got %d themes, want 4", seed, len(queue))
+		}
+		seen := map[string]bool{}
+		for _, th := range queue {
+			if th.ID == "silent" {
+				t.Fatalf("seed %d: queued a theme without audio", seed)
+			}
+			artist := strings.ToLower(strings.TrimSpace(th.ArtistName))
+			if artist != "" && seen[artist] {
+				t.Fatalf("seed %d: artist %q queued twice", seed, artist)
+			}
+			seen[artist] = true
+		}
+	}
+}
+
+func TestBuildRadioQueue_RecentLast(t *testing.T) {
+	pool := []domain.AnimeTheme{
+		radioTheme("t1", "A"),
+		radioTheme("t2", "B"),
+		radioTheme("t3", "C"),
+		radioTheme("t4", "D"),
+	}
+	recent := []string{"t1", "t2"}
+
+	queue := BuildRadioQueue(pool, recent, 2, rand.New(rand.NewSource(7)))
+	for _, th := range queue {
+		if th.ID == "t1" || th.ID == "t2" {
+			t.Fatalf("recent theme %s queued while others were left", th.ID)
+		}
+	}
+
+	// Asking for the whole pool falls back to the recent themes at the end.
+	queue = BuildRadioQueue(pool, recent, 4, rand.New(rand.NewSource(7)))
+	if len(queue) != 4 {
+		t.Fatalf("got %d themes, want 4", len(queue))
+	}
+	for _, th := range queue[:2] {
+		if th.ID == "t1" || th.ID == "t2" {
+			t.Fatalf("recent theme %s queued before fresh ones", th.ID)
+		}
+	}
+}
+
+func TestBuildRadioQueue_SameSeedSameQueue(t *testing.T) {
+	var pool []domain.AnimeTheme
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		pool = append(pool, radioTheme(id, id))
+	}
+	q1 := BuildRadioQueue(pool, nil, 5, rand.New(rand.NewSource(42)))
+	q2 := BuildRadioQueue(pool, nil, 5, rand.New(rand.NewSource(42)))
+	for i := range q1 {
+		if q1[i].ID != q2[i].ID {
+			t.Fatalf("queues differ at %d: %s vs %s", i, q1[i].ID, q2[i].ID)
+		}
+	}
+}
+
+func TestWriteM3U(t *testing.T) {
+	themes := []domain.AnimeTheme{
+		{AnimeName: "Sousou no Frieren", Slug: "OP1", SongTitle: "Yuusha", ArtistName: "YOASOBI", AudioBasename: "SousouNoFrieren-OP1.ogg"},
+		{AnimeName: "Bocchi\nthe Rock!", Slug: "ED1", AudioBasename: "Bocchi ED1.ogg"},
+		{AnimeName: "No audio", Slug: "OP1"},
+	}
+
+	var b strings.Builder
+	if err := WriteM3U(&b, "https://example.org", "My list", themes); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "#EXTM3U\n" +
+		"#PLAYLIST:My list\n" +
+		"#EXTINF:-1,YOASOBI - Yuusha (Sousou no Frieren OP1)\n" +
+		"https://example.org/api/themes/audio/SousouNoFrieren-OP1.ogg\n" +
+		"#EXTINF:-1,ED1 (Bocchi the Rock! ED1)\n" +
+		"https://example.org/api/themes/audio/Bocchi%20ED1.ogg\n"
+	if b.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
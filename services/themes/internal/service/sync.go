@@ -7,18 +7,30 @@ import (
 
 	"github.com/ILITA-hub/animeenigma/libs/logger"
 	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
-	"github.com/ILITA-hub/animeenigma/services/themes/internal/parser/animethemes"
-	"github.com/ILITA-hub/animeenigma/services/themes/internal/repo"
 )
 
+// syncStore is the slice of repo.ThemeRepository a sync writes to.
+type syncStore interface {
+	UpsertSynced(ctx context.Context, st *domain.SyncedTheme) error
+	GetSyncCursor(ctx context.Context, name string) (time.Time, error)
+	SetSyncCursor(ctx context.Context, name string, through time.Time) error
+}
+
+// themeSource is the animethemes API as the sync reads it.
+type themeSource interface {
+	FetchSeason(year int, season string) ([]domain.SyncedTheme, error)
+	FetchAll(fn func([]domain.SyncedTheme) error) error
+	FetchUpdatedSince(since time.Time, fn func([]domain.SyncedTheme) error) error
+}
+
 type SyncService struct {
-	themeRepo *repo.ThemeRepository
-	client    *animethemes.Client
+	themeRepo syncStore
+	client    themeSource
 	status    *domain.SyncStatus
 	log       *logger.Logger
 }
 
-func NewSyncService(themeRepo *repo.ThemeRepository, client *animethemes.Client, log *logger.Logger) *SyncService {
+func NewSyncService(themeRepo syncStore, client themeSource, log *logger.Logger) *SyncService {
 	return &SyncService{
 		themeRepo: themeRepo,
 		client:    client,
@@ -45,22 +57,60 @@ func CurrentSeason() (int, string) {
 	}
 }
 
+// syncCursorName names the incremental sync's position in the update feed.
+const syncCursorName = "animethemes"
+
 // StartSync triggers a sync in a background goroutine.
 // If year/season are zero/empty, defaults to the current season.
 func (s *SyncService) StartSync(year int, season string) error {
+	if year == 0 || season == "" {
+		year, season = CurrentSeason()
+	}
+	return s.start(domain.SyncModeSeason, year, season, func(ctx context.Context) error {
+		return s.syncSeason(ctx, year, season)
+	})
+}
+
+// StartBackfill triggers a sync of the whole animethemes catalog. It also
+// moves the incremental cursor to the start of the backfill.
+func (s *SyncService) StartBackfill() error {
+	return s.start(domain.SyncModeFull, 0, "", s.syncAll)
+}
+
+// StartIncremental triggers a sync of the themes updated — themselves or
+// through their song, artists or videos — since the last backfill or
+// incremental sync. Without either it runs a backfill.
+func (s *SyncService) StartIncremental() error {
+	return s.start(domain.SyncModeIncremental, 0, "", s.syncUpdated)
+}
+
+// RunIncremental polls the update feed every interval until ctx is done.
+// Ticks that find another sync running are skipped.
+func (s *SyncService) RunIncremental(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.StartIncremental(); err != nil {
+				s.log.Infow("scheduled theme sync skipped", "error", err)
+			}
+		}
+	}
+}
+
+func (s *SyncService) start(mode string, year int, season string, run func(ctx context.Context) error) error {
 	status := s.status.Get()
 	if status.Running {
 		return fmt.Errorf("sync already in progress")
 	}
-
-	if year == 0 || season == "" {
-		year, season = CurrentSeason()
-	}
-	s.status.Start(year, season)
+	s.status.Start(mode, year, season)
 
 	go func() {
-		if err := s.syncSeason(year, season); err != nil {
-			s.log.Errorw("sync failed", "year", year, "season", season, "error", err)
+		if err := run(context.Background()); err != nil {
+			s.log.Errorw("sync failed", "mode", mode, "year", year, "season", season, "error", err)
 			s.status.SetError(err.Error())
 			return
 		}
@@ -70,7 +120,7 @@ func (s *SyncService) StartSync(year int, season string) error {
 	return nil
 }
 
-func (s *SyncService) syncSeason(year int, season string) error {
+func (s *SyncService) syncSeason(ctx context.Context, year int, season string) error {
 	s.log.Infow("starting theme sync", "year", year, "season", season)
 
 	themes, err := s.client.FetchSeason(year, season)
@@ -81,21 +131,75 @@ func (s *SyncService) syncSeason(year int, season string) error {
 	s.status.SetTotal(len(themes))
 	s.log.Infow("fetched themes from API", "count", len(themes))
 
-	ctx := context.Background()
+	s.store(ctx, themes)
+
+	s.log.Infow("theme sync completed", "year", year, "season", season, "total", len(themes))
+	return nil
+}
+
+func (s *SyncService) syncAll(ctx context.Context) error {
+	s.log.Infow("starting full theme backfill")
+	startedAt := time.Now()
+
+	err := s.client.FetchAll(func(themes []domain.SyncedTheme) error {
+		s.status.AddTotal(len(themes))
+		s.store(ctx, themes)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("fetch catalog: %w", err)
+	}
+
+	if err := s.themeRepo.SetSyncCursor(ctx, syncCursorName, startedAt); err != nil {
+		return fmt.Errorf("save sync cursor: %w", err)
+	}
+	s.log.Infow("full theme backfill completed", "total", s.status.Get().Total)
+	return nil
+}
+
+func (s *SyncService) syncUpdated(ctx context.Context) error {
+	since, err := s.themeRepo.GetSyncCursor(ctx, syncCursorName)
+	if err != nil {
+		return fmt.Errorf("load sync cursor: %w", err)
+	}
+	if since.IsZero() {
+		s.log.Infow("no sync cursor yet, running full backfill")
+		return s.syncAll(ctx)
+	}
+
+	s.log.Infow("starting incremental theme sync", "since", since)
+	startedAt := time.Now()
+
+	err = s.client.FetchUpdatedSince(since, func(themes []domain.SyncedTheme) error {
+		s.status.AddTotal(len(themes))
+		s.store(ctx, themes)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("fetch updates: %w", err)
+	}
+
+	if err := s.themeRepo.SetSyncCursor(ctx, syncCursorName, startedAt); err != nil {
+		return fmt.Errorf("save sync cursor: %w", err)
+	}
+	s.log.Infow("incremental theme sync completed", "since", since, "total", s.status.Get().Total)
+	return nil
+}
+
+// store upserts each theme with its song, artists and videos. A failing
+// theme is logged and skipped.
+func (s *SyncService) store(ctx context.Context, themes []domain.SyncedTheme) {
 	for i := range themes {
-		if err := s.themeRepo.Upsert(ctx, &themes[i]); err != nil {
+		if err := s.themeRepo.UpsertSynced(ctx, &themes[i]); err != nil {
 			s.log.Errorw("failed to upsert theme",
-				"external_id", themes[i].ExternalID,
-				"anime", themes[i].AnimeName,
+				"external_id", themes[i].Theme.ExternalID,
+				"anime", themes[i].Theme.AnimeName,
 				"error", err,
 			)
 			continue
 		}
 		s.status.Increment()
 	}
-
-	s.log.Infow("theme sync completed", "year", year, "season", season, "total", len(themes))
-	return nil
 }
 
 // GetStatus returns the current sync status.
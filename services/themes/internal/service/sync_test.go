@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+)
+
+// fakeSyncStore records upserts and the sync cursor. Themes whose external
+// id is in fail are rejected.
+type fakeSyncStore struct {
+	upserted []int
+	fail     map[int]bool
+	cursor   time.Time
+}
+
+func (f *fakeSyncStore) UpsertSynced(_ context.Context, st *domain.SyncedTheme) error {
+	if f.fail[st.Theme.ExternalID] {
+		return fmt.Errorf("theme %d rejected", st.Theme.ExternalID)
+	}
+	f.upserted = append(f.upserted, st.Theme.ExternalID)
+	return nil
+}
+
+func (f *fakeSyncStore) GetSyncCursor(context.Context, string) (time.Time, error) {
+	return f.cursor, nil
+}
+
+func (f *fakeSyncStore) SetSyncCursor(_ context.Context, _ string, through time.Time) error {
+	f.cursor = through
+	return nil
+}
+
+// fakeThemeSource hands out fixed pages of theme external ids, failing with
+// err after them.
+type fakeThemeSource struct {
+	catalog [][]int // FetchAll pages
+	updates [][]int // FetchUpdatedSince pages
+	err     error
+	since   []time.Time
+}
+
+func syncedPage(ids []int) []domain.SyncedTheme {
+	out := make([]domain.SyncedTheme, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, domain.SyncedTheme{Theme: domain.AnimeTheme{ExternalID: id}})
+	}
+	return out
+}
+
+func (f *fakeThemeSource) feed(pages [][]int, fn func([]domain.SyncedTheme) error) error {
+	for _, ids := range pages {
+		if err := fn(syncedPage(ids)); err != nil {
+			return err
+		}
+	}
+	return f.err
+}
+
+func (f *fakeThemeSource) FetchSeason(int, string) ([]domain.SyncedTheme, error) {
+	return nil, nil
+}
+
+func (f *fakeThemeSource) FetchAll(fn func([]domain.SyncedTheme) error) error {
+	return f.feed(f.catalog, fn)
+}
+
+func (f *fakeThemeSource) FetchUpdatedSince(since time.Time, fn func([]domain.SyncedTheme) error) error {
+	f.since = append(f.since, since)
+	return f.feed(f.updates, fn)
+}
+
+func TestSync_FirstIncrementalRunBackfills(t *testing.T) {
+	store := &fakeSyncStore{}
+	source := &fakeThemeSource{catalog: [][]int{{1, 2}, {3}}, updates: [][]int{{9}}}
+	svc := NewSyncService(store, source, logger.Default())
+
+	before := time.Now()
+	if err := svc.syncUpdated(context.Background()); err != nil {
+		t.Fatalf("syncUpdated: %v", err)
+	}
+	if !reflect.DeepEqual(store.upserted, []int{1, 2, 3}) || len(source.since) != 0 {
+		t.Errorf("upserted %v, update feed read %d times; want the whole catalog and no feed", store.upserted, len(source.since))
+	}
+	if store.cursor.Before(before) {
+		t.Errorf("cursor = %v; want the backfill's start", store.cursor)
+	}
+	if st := svc.GetStatus(); st.Total != 3 || st.Processed != 3 {
+		t.Errorf("status = %d/%d; want 3/3", st.Processed, st.Total)
+	}
+}
+
+func TestSync_IncrementalReadsFromCursor(t *testing.T) {
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	store := &fakeSyncStore{cursor: since, fail: map[int]bool{5: true}}
+	source := &fakeThemeSource{catalog: [][]int{{1}}, updates: [][]int{{4, 5}, {6}}}
+	svc := NewSyncService(store, source, logger.Default())
+
+	before := time.Now()
+	if err := svc.syncUpdated(context.Background()); err != nil {
+		t.Fatalf("syncUpdated: %v", err)
+	}
+	if len(source.since) != 1 || !source.since[0].Equal(since) {
+		t.Fatalf("feed read since %v; want %v", source.since, since)
+	}
+	// A theme that fails to store is skipped; the rest still land.
+	if !reflect.DeepEqual(store.upserted, []int{4, 6}) {
+		t.Errorf("upserted %v; want [4 6]", store.upserted)
+	}
+	if st := svc.GetStatus(); st.Total != 3 || st.Processed != 2 {
+		t.Errorf("status = %d/%d; want 2/3", st.Processed, st.Total)
+	}
+	// The next run starts where this one started, so nothing updated while
+	// it ran is missed.
+	if store.cursor.Before(before) || store.cursor.After(time.Now()) {
+		t.Errorf("cursor = %v; want this run's start", store.cursor)
+	}
+}
+
+func TestSync_FailedRunKeepsCursor(t *testing.T) {
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	boom := stderrors.New("animethemes down")
+
+	store := &fakeSyncStore{cursor: since}
+	source := &fakeThemeSource{updates: [][]int{{4}}, err: boom}
+	if err := NewSyncService(store, source, logger.Default()).syncUpdated(context.Background()); !stderrors.Is(err, boom) {
+		t.Fatalf("incremental err = %v; want %v", err, boom)
+	}
+	if !store.cursor.Equal(since) {
+		t.Errorf("cursor moved to %v after a failed run", store.cursor)
+	}
+
+	store = &fakeSyncStore{}
+	source = &fakeThemeSource{catalog: [][]int{{1}}, err: boom}
+	if err := NewSyncService(store, source, logger.Default()).syncAll(context.Background()); !stderrors.Is(err, boom) {
+		t.Fatalf("backfill err = %v; want %v", err, boom)
+	}
+	if !store.cursor.IsZero() {
+		t.Errorf("cursor set to %v by a failed backfill; the next run must backfill again", store.cursor)
+	}
+}
@@ -13,13 +13,15 @@ import (
 type ThemeService struct {
 	themeRepo  *repo.ThemeRepository
 	ratingRepo *repo.RatingRepository
+	artistRepo *repo.ArtistRepository
 	log        *logger.Logger
 }
 
-func NewThemeService(themeRepo *repo.ThemeRepository, ratingRepo *repo.RatingRepository, log *logger.Logger) *ThemeService {
+func NewThemeService(themeRepo *repo.ThemeRepository, ratingRepo *repo.RatingRepository, artistRepo *repo.ArtistRepository, log *logger.Logger) *ThemeService {
 	return &ThemeService{
 		themeRepo:  themeRepo,
 		ratingRepo: ratingRepo,
+		artistRepo: artistRepo,
 		log:        log,
 	}
 }
@@ -37,24 +39,7 @@ func (s *ThemeService) List(ctx context.Context, params domain.ThemeListParams)
 	}
 
 	// Attach user scores if authenticated
-	if params.UserID != "" && len(themes) > 0 {
-		themeIDs := make([]string, len(themes))
-		for i, t := range themes {
-			themeIDs[i] = t.ID
-		}
-
-		scores, err := s.ratingRepo.GetUserScoresMap(ctx, params.UserID, themeIDs)
-		if err != nil {
-			s.log.Errorw("failed to get user scores", "user_id", params.UserID, "error", err)
-		} else {
-			for i := range themes {
-				if score, ok := scores[themes[i].ID]; ok {
-					s := score
-					themes[i].UserScore = &s
-				}
-			}
-		}
-	}
+	attachUserScores(ctx, s.ratingRepo, s.log, params.UserID, themes)
 
 	return themes, nil
 }
@@ -73,5 +58,53 @@ func (s *ThemeService) GetByID(ctx context.Context, id, userID string) (*domain.
 		}
 	}
 
+	// Detail extras: credited artists and every video version. Failures
+	// degrade to the flat song_title/artist_name and the best video.
+	if theme.SongID != nil {
+		if artists, err := s.artistRepo.ForSong(ctx, *theme.SongID); err != nil {
+			s.log.Errorw("failed to get theme artists", "theme_id", id, "error", err)
+		} else {
+			theme.Artists = artists
+		}
+	}
+	if videos, err := s.themeRepo.Videos(ctx, id); err != nil {
+		s.log.Errorw("failed to get theme videos", "theme_id", id, "error", err)
+	} else {
+		theme.Videos = videos
+	}
+
 	return theme, nil
 }
+
+// GuessPool returns the anidle theme-mode pool.
+func (s *ThemeService) GuessPool(ctx context.Context) ([]domain.GuessTheme, error) {
+	themes, err := s.themeRepo.ListGuessThemes(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "list guess themes")
+	}
+	return themes, nil
+}
+
+// attachUserScores sets UserScore on the themes the user rated.
+func attachUserScores(ctx context.Context, ratingRepo *repo.RatingRepository, log *logger.Logger, userID string, themes []domain.AnimeTheme) {
+	if userID == "" || len(themes) == 0 {
+		return
+	}
+
+	themeIDs := make([]string, len(themes))
+	for i, t := range themes {
+		themeIDs[i] = t.ID
+	}
+
+	scores, err := ratingRepo.GetUserScoresMap(ctx, userID, themeIDs)
+	if err != nil {
+		log.Errorw("failed to get user scores", "user_id", userID, "error", err)
+		return
+	}
+	for i := range themes {
+		if score, ok := scores[themes[i].ID]; ok {
+			s := score
+			themes[i].UserScore = &s
+		}
+	}
+}
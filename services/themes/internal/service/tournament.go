@@ -0,0 +1,481 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/repo"
+)
+
+const (
+	defaultTournamentSize  = 16
+	maxTournamentSize      = 64
+	defaultRoundMinutes    = 24 * 60
+	minRoundMinutes        = 10
+	maxRoundMinutes        = 7 * 24 * 60
+	maxTournamentTitleLen  = 100
+	tournamentListLimit    = 50
+	tournamentTickInterval = time.Minute
+	// tournamentCatchUp is how late a round may start on its schedule; a
+	// ticker further behind (downtime) opens it now so it still gets its
+	// full voting window.
+	tournamentCatchUp = 5 * time.Minute
+)
+
+// bracketStore is what advancing a bracket reads and writes under the
+// tournament's row lock.
+type bracketStore interface {
+	Entries(ctx context.Context, tournamentID string) ([]domain.ThemeTournamentEntry, error)
+	RoundMatches(ctx context.Context, tournamentID string, round int) ([]domain.ThemeTournamentMatch, error)
+	SaveTournament(ctx context.Context, t *domain.ThemeTournament) error
+	SaveMatch(ctx context.Context, m *domain.ThemeTournamentMatch) error
+	VoteCounts(ctx context.Context, matchID string) (map[string]int, error)
+}
+
+// tournamentStore is the slice of repo.TournamentRepository the service uses.
+type tournamentStore interface {
+	bracketStore
+	SeasonSeeds(ctx context.Context, year int, season, themeType string, limit int) ([]repo.SeedCandidate, error)
+	Create(ctx context.Context, t *domain.ThemeTournament, entries []domain.ThemeTournamentEntry, build func(id string) []domain.ThemeTournamentMatch) error
+	List(ctx context.Context, status string, limit int) ([]domain.ThemeTournament, error)
+	GetByID(ctx context.Context, id string) (*domain.ThemeTournament, error)
+	Matches(ctx context.Context, tournamentID string) ([]domain.ThemeTournamentMatch, error)
+	GetMatch(ctx context.Context, tournamentID, matchID string) (*domain.ThemeTournamentMatch, error)
+	UserVotes(ctx context.Context, tournamentID, userID string) (map[string]string, error)
+	Vote(ctx context.Context, v *domain.ThemeTournamentVote) error
+	DueIDs(ctx context.Context, now time.Time) ([]string, error)
+	Audience(ctx context.Context, tournamentID string) ([]string, error)
+	WithLock(ctx context.Context, id string, fn func(tx bracketStore, t *domain.ThemeTournament) error) error
+}
+
+// lockingRepo hands WithLock's transaction-bound repository to fn as a
+// bracketStore.
+type lockingRepo struct {
+	*repo.TournamentRepository
+}
+
+func (r lockingRepo) WithLock(ctx context.Context, id string, fn func(tx bracketStore, t *domain.ThemeTournament) error) error {
+	return r.TournamentRepository.WithLock(ctx, id, func(tx *repo.TournamentRepository, t *domain.ThemeTournament) error {
+		return fn(tx, t)
+	})
+}
+
+type TournamentService struct {
+	repo     tournamentStore
+	notifier *TournamentNotifier
+	log      *logger.Logger
+}
+
+func NewTournamentService(tournamentRepo *repo.TournamentRepository, notifier *TournamentNotifier, log *logger.Logger) *TournamentService {
+	return &TournamentService{
+		repo:     lockingRepo{tournamentRepo},
+		notifier: notifier,
+		log:      log,
+	}
+}
+
+// Create seeds a tournament from a season's best rated themes and lays out
+// its bracket. Fewer themes than the requested size shrink the bracket; an
+// odd count gives the top seeds byes.
+func (s *TournamentService) Create(ctx context.Context, adminID string, req domain.TournamentRequest) (*domain.TournamentDetail, error) {
+	title := strings.TrimSpace(req.Title)
+	if title == "" || utf8.RuneCountInString(title) > maxTournamentTitleLen {
+		return nil, errors.InvalidInput("title must be 1-100 characters")
+	}
+	if req.Year <= 0 || !validSeason(req.Season) {
+		return nil, errors.InvalidInput("year and season are required")
+	}
+	themeType := strings.ToUpper(req.ThemeType)
+	if themeType != "" && themeType != "OP" && themeType != "ED" {
+		return nil, errors.InvalidInput("theme_type must be op, ed or empty")
+	}
+	size := req.Size
+	if size == 0 {
+		size = defaultTournamentSize
+	}
+	if size < 2 || size > maxTournamentSize || bracketSize(size) != size {
+		return nil, errors.InvalidInput("size must be a power of two up to 64")
+	}
+	minutes := req.RoundMinutes
+	if minutes == 0 {
+		minutes = defaultRoundMinutes
+	}
+	if minutes < minRoundMinutes || minutes > maxRoundMinutes {
+		return nil, errors.InvalidInput("round_minutes must be between 10 and 10080")
+	}
+	startsAt := req.StartsAt
+	if startsAt.IsZero() {
+		startsAt = time.Now()
+	}
+
+	candidates, err := s.repo.SeasonSeeds(ctx, req.Year, req.Season, themeType, size)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "season seeds")
+	}
+	if len(candidates) < 2 {
+		return nil, errors.InvalidInput("the season has fewer than two themes")
+	}
+
+	entries := make([]domain.ThemeTournamentEntry, len(candidates))
+	for i, c := range candidates {
+		entries[i] = domain.ThemeTournamentEntry{ThemeID: c.ThemeID, Seed: i + 1, SeedScore: c.AvgScore, SeedVotes: c.VoteCount}
+	}
+	bracket := bracketSize(len(entries))
+	t := &domain.ThemeTournament{
+		Title:        title,
+		Year:         req.Year,
+		Season:       req.Season,
+		ThemeType:    themeType,
+		Size:         bracket,
+		Rounds:       bracketRounds(bracket),
+		RoundMinutes: minutes,
+		Status:       domain.TournamentScheduled,
+		StartsAt:     startsAt,
+		CreatedBy:    adminID,
+	}
+	err = s.repo.Create(ctx, t, entries, func(id string) []domain.ThemeTournamentMatch {
+		return buildBracket(id, entries, bracket)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "create tournament")
+	}
+
+	s.log.Infow("tournament created", "tournament_id", t.ID, "year", t.Year, "season", t.Season, "entries", len(entries))
+	return s.Get(ctx, t.ID, "")
+}
+
+// List returns tournaments, optionally of one status ("finished" is the
+// archive).
+func (s *TournamentService) List(ctx context.Context, status string) ([]domain.ThemeTournament, error) {
+	ts, err := s.repo.List(ctx, status, tournamentListLimit)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "list tournaments")
+	}
+	return ts, nil
+}
+
+// Get returns a tournament with entries and bracket; with a userID, each
+// match carries the user's vote.
+func (s *TournamentService) Get(ctx context.Context, id, userID string) (*domain.TournamentDetail, error) {
+	t, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "get tournament")
+	}
+	if t == nil {
+		return nil, errors.NotFound("tournament")
+	}
+	entries, err := s.repo.Entries(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "tournament entries")
+	}
+	matches, err := s.repo.Matches(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "tournament matches")
+	}
+
+	if userID != "" {
+		votes, err := s.repo.UserVotes(ctx, id, userID)
+		if err != nil {
+			s.log.Errorw("failed to get tournament votes", "tournament_id", id, "user_id", userID, "error", err)
+		}
+		for i := range matches {
+			if v, ok := votes[matches[i].ID]; ok {
+				vote := v
+				matches[i].MyVote = &vote
+			}
+		}
+	}
+
+	return &domain.TournamentDetail{ThemeTournament: *t, Entries: entries, Matches: matches}, nil
+}
+
+// Vote casts the user's one vote in an open match.
+func (s *TournamentService) Vote(ctx context.Context, tournamentID, matchID, userID, themeID string) error {
+	t, err := s.repo.GetByID(ctx, tournamentID)
+	if err != nil {
+		return errors.Wrap(err, errors.CodeInternal, "get tournament")
+	}
+	if t == nil {
+		return errors.NotFound("tournament")
+	}
+	m, err := s.repo.GetMatch(ctx, tournamentID, matchID)
+	if err != nil {
+		return errors.Wrap(err, errors.CodeInternal, "get match")
+	}
+	if m == nil {
+		return errors.NotFound("match")
+	}
+	if t.Status != domain.TournamentRunning || m.Round != t.CurrentRound || !m.Open(time.Now()) {
+		return errors.InvalidInput("match is not open for voting")
+	}
+	if themeID != *m.ThemeAID && themeID != *m.ThemeBID {
+		return errors.InvalidInput("theme is not in this match")
+	}
+
+	err = s.repo.Vote(ctx, &domain.ThemeTournamentVote{MatchID: matchID, UserID: userID, ThemeID: themeID})
+	if stderrors.Is(err, repo.ErrAlreadyVoted) {
+		return errors.AlreadyExists("vote")
+	}
+	if err != nil {
+		return errors.Wrap(err, errors.CodeInternal, "vote")
+	}
+	return nil
+}
+
+// Cancel stops a scheduled or running tournament; its bracket stays as is.
+// The status flips under the tournament's row lock, like advance, so a tick
+// in flight cannot save its round over the cancellation. While a tick holds
+// the lock the cancel answers a conflict and the admin retries.
+func (s *TournamentService) Cancel(ctx context.Context, id string) error {
+	locked := false
+	err := s.repo.WithLock(ctx, id, func(tx bracketStore, t *domain.ThemeTournament) error {
+		locked = true
+		if t.Status == domain.TournamentFinished || t.Status == domain.TournamentCancelled {
+			return errors.InvalidInput("tournament is already over")
+		}
+		t.Status = domain.TournamentCancelled
+		return tx.SaveTournament(ctx, t)
+	})
+	if err != nil {
+		if _, ok := errors.IsAppError(err); ok {
+			return err
+		}
+		return errors.Wrap(err, errors.CodeInternal, "cancel tournament")
+	}
+	if locked {
+		return nil
+	}
+	t, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, errors.CodeInternal, "get tournament")
+	}
+	if t == nil {
+		return errors.NotFound("tournament")
+	}
+	return errors.New(errors.CodeConflict, "tournament is being advanced; retry")
+}
+
+// Run advances brackets every minute until ctx is done.
+func (s *TournamentService) Run(ctx context.Context) {
+	ticker := time.NewTicker(tournamentTickInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.Tick(ctx, now)
+		}
+	}
+}
+
+// roundStart is a round opened by Tick, notified after its transaction.
+type roundStart struct {
+	tournament domain.ThemeTournament
+	endsAt     time.Time
+}
+
+// Tick starts due tournaments, closes rounds whose time is up, advances the
+// winners and opens the next round, finishing the tournament after the
+// final. Each tournament is advanced under its row lock.
+func (s *TournamentService) Tick(ctx context.Context, now time.Time) {
+	ids, err := s.repo.DueIDs(ctx, now)
+	if err != nil {
+		s.log.Errorw("failed to list due tournaments", "error", err)
+		return
+	}
+	for _, id := range ids {
+		var started []roundStart
+		err := s.repo.WithLock(ctx, id, func(tx bracketStore, t *domain.ThemeTournament) error {
+			var err error
+			started, err = advance(ctx, tx, t, now)
+			return err
+		})
+		if err != nil {
+			s.log.Errorw("failed to advance tournament", "tournament_id", id, "error", err)
+			continue
+		}
+		for _, rs := range started {
+			s.notifyRound(ctx, rs)
+		}
+	}
+}
+
+func (s *TournamentService) notifyRound(ctx context.Context, rs roundStart) {
+	s.log.Infow("tournament round started", "tournament_id", rs.tournament.ID, "round", rs.tournament.CurrentRound)
+	if s.notifier == nil {
+		return
+	}
+	users, err := s.repo.Audience(ctx, rs.tournament.ID)
+	if err != nil {
+		s.log.Warnw("failed to list tournament audience", "tournament_id", rs.tournament.ID, "error", err)
+		return
+	}
+	s.notifier.NotifyRoundStart(ctx, &rs.tournament, rs.endsAt, users)
+}
+
+// advance moves t forward as far as now allows and returns the rounds it
+// opened.
+func advance(ctx context.Context, tx bracketStore, t *domain.ThemeTournament, now time.Time) ([]roundStart, error) {
+	var started []roundStart
+	duration := time.Duration(t.RoundMinutes) * time.Minute
+
+	for {
+		switch t.Status {
+		case domain.TournamentScheduled:
+			if now.Before(t.StartsAt) {
+				return started, nil
+			}
+			t.Status = domain.TournamentRunning
+			rs, err := openRound(ctx, tx, t, 1, roundStartAt(t.StartsAt, now), duration)
+			if err != nil {
+				return nil, err
+			}
+			started = append(started, rs)
+
+		case domain.TournamentRunning:
+			matches, err := tx.RoundMatches(ctx, t.ID, t.CurrentRound)
+			if err != nil {
+				return nil, err
+			}
+			endsAt := roundEndsAt(matches)
+			if endsAt == nil || now.Before(*endsAt) {
+				return started, nil
+			}
+			if err := closeRound(ctx, tx, t, matches); err != nil {
+				return nil, err
+			}
+			if t.CurrentRound == t.Rounds {
+				finished := now
+				t.Status = domain.TournamentFinished
+				t.FinishedAt = &finished
+				return started, tx.SaveTournament(ctx, t)
+			}
+			rs, err := openRound(ctx, tx, t, t.CurrentRound+1, roundStartAt(*endsAt, now), duration)
+			if err != nil {
+				return nil, err
+			}
+			started = append(started, rs)
+
+		default:
+			return started, nil
+		}
+	}
+}
+
+// roundStartAt keeps a round on its schedule unless the ticker fell behind.
+func roundStartAt(scheduled, now time.Time) time.Time {
+	if now.Sub(scheduled) > tournamentCatchUp {
+		return now
+	}
+	return scheduled
+}
+
+func roundEndsAt(matches []domain.ThemeTournamentMatch) *time.Time {
+	var end *time.Time
+	for i := range matches {
+		if e := matches[i].EndsAt; e != nil && (end == nil || e.After(*end)) {
+			end = e
+		}
+	}
+	return end
+}
+
+// openRound sets the voting window of a round's matches. Round 1 byes are
+// decided on the spot.
+func openRound(ctx context.Context, tx bracketStore, t *domain.ThemeTournament, round int, startsAt time.Time, duration time.Duration) (roundStart, error) {
+	t.CurrentRound = round
+	if err := tx.SaveTournament(ctx, t); err != nil {
+		return roundStart{}, err
+	}
+	matches, err := tx.RoundMatches(ctx, t.ID, round)
+	if err != nil {
+		return roundStart{}, err
+	}
+	endsAt := startsAt.Add(duration)
+	for i := range matches {
+		m := &matches[i]
+		start, end := startsAt, endsAt
+		m.StartsAt, m.EndsAt = &start, &end
+		if err := tx.SaveMatch(ctx, m); err != nil {
+			return roundStart{}, err
+		}
+	}
+	var byes []domain.ThemeTournamentMatch
+	for _, m := range matches {
+		if m.ThemeAID == nil || m.ThemeBID == nil {
+			byes = append(byes, m)
+		}
+	}
+	if len(byes) > 0 {
+		if err := closeRound(ctx, tx, t, byes); err != nil {
+			return roundStart{}, err
+		}
+	}
+	return roundStart{tournament: *t, endsAt: endsAt}, nil
+}
+
+// closeRound decides the undecided matches given and moves each winner into
+// its slot of the next round (or makes it the champion after the final).
+func closeRound(ctx context.Context, tx bracketStore, t *domain.ThemeTournament, matches []domain.ThemeTournamentMatch) error {
+	entries, err := tx.Entries(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	seeds := make(map[string]int, len(entries))
+	for _, e := range entries {
+		seeds[e.ThemeID] = e.Seed
+	}
+
+	var next []domain.ThemeTournamentMatch
+	if t.CurrentRound < t.Rounds {
+		if next, err = tx.RoundMatches(ctx, t.ID, t.CurrentRound+1); err != nil {
+			return err
+		}
+	}
+
+	for i := range matches {
+		m := &matches[i]
+		if m.WinnerThemeID != nil || (m.ThemeAID == nil && m.ThemeBID == nil) {
+			continue
+		}
+		var votesA, votesB int
+		if m.ThemeAID != nil && m.ThemeBID != nil {
+			counts, err := tx.VoteCounts(ctx, m.ID)
+			if err != nil {
+				return err
+			}
+			votesA, votesB = counts[*m.ThemeAID], counts[*m.ThemeBID]
+		}
+		winner, decidedBy := decideMatch(m, votesA, votesB, seeds)
+		m.VotesA, m.VotesB = votesA, votesB
+		m.WinnerThemeID, m.DecidedBy = &winner, decidedBy
+		if err := tx.SaveMatch(ctx, m); err != nil {
+			return err
+		}
+
+		if t.CurrentRound == t.Rounds {
+			t.WinnerThemeID = &winner
+			continue
+		}
+		parent := m.Slot / 2
+		if parent >= len(next) {
+			continue
+		}
+		if m.Slot%2 == 0 {
+			next[parent].ThemeAID = &winner
+		} else {
+			next[parent].ThemeBID = &winner
+		}
+		if err := tx.SaveMatch(ctx, &next[parent]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
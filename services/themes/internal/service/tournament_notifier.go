@@ -0,0 +1,112 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+)
+
+// notifyTypeTournamentRound is emitted when a tournament round opens.
+// Mirrored by the notifications service's TypeThemeTournamentRound.
+const notifyTypeTournamentRound = "theme_tournament_round"
+
+// uuidRe guards user ids: user_notifications.user_id is a Postgres uuid column.
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// TournamentNotifier is the fire-and-forget producer of round-start
+// notifications. It POSTs to the notifications service's internal upsert
+// over the Docker network; a notifications outage never holds up a bracket.
+type TournamentNotifier struct {
+	baseURL string
+	enabled bool
+	client  *http.Client
+	log     *logger.Logger
+}
+
+// NewTournamentNotifier constructs the producer. enabled=false turns every
+// call into a no-op.
+func NewTournamentNotifier(baseURL string, enabled bool, log *logger.Logger) *TournamentNotifier {
+	if log == nil {
+		log = logger.Default()
+	}
+	return &TournamentNotifier{
+		baseURL: baseURL,
+		enabled: enabled,
+		client:  &http.Client{Timeout: 5 * time.Second},
+		log:     log,
+	}
+}
+
+// tournamentRoundNotification is the payload of theme_tournament_round.
+type tournamentRoundNotification struct {
+	TournamentID string `json:"tournament_id"`
+	Title        string `json:"title"`
+	Round        int    `json:"round"`
+	Rounds       int    `json:"rounds"`
+	EndsAt       string `json:"ends_at"` // RFC 3339
+	URL          string `json:"url"`
+}
+
+// NotifyRoundStart tells each user a round of t opened.
+func (n *TournamentNotifier) NotifyRoundStart(ctx context.Context, t *domain.ThemeTournament, endsAt time.Time, userIDs []string) {
+	if n == nil || !n.enabled || n.baseURL == "" || t == nil {
+		return
+	}
+	payload, err := json.Marshal(tournamentRoundNotification{
+		TournamentID: t.ID,
+		Title:        t.Title,
+		Round:        t.CurrentRound,
+		Rounds:       t.Rounds,
+		EndsAt:       endsAt.UTC().Format(time.RFC3339),
+		URL:          "/themes?tournament=" + t.ID,
+	})
+	if err != nil {
+		n.log.Errorw("tournament notify: marshal payload", "tournament_id", t.ID, "err", err)
+		return
+	}
+	for _, uid := range userIDs {
+		if !uuidRe.MatchString(uid) {
+			continue
+		}
+		body := map[string]interface{}{
+			"user_id":    uid,
+			"type":       notifyTypeTournamentRound,
+			"dedupe_key": fmt.Sprintf("theme_tournament:%s:round:%d", t.ID, t.CurrentRound),
+			"payload":    json.RawMessage(payload),
+		}
+		if err := n.post(ctx, body); err != nil {
+			n.log.Warnw("tournament notify failed (non-fatal)",
+				"tournament_id", t.ID, "round", t.CurrentRound, "user_id", uid, "err", err)
+		}
+	}
+}
+
+func (n *TournamentNotifier) post(ctx context.Context, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.baseURL+"/internal/notifications", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, string(b))
+	}
+	return nil
+}
@@ -0,0 +1,437 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/themes/internal/repo"
+)
+
+// fakeTournaments is an in-memory tournamentStore. WithLock hands itself to
+// fn, so a bracket advances exactly as it would inside the transaction.
+type fakeTournaments struct {
+	seeds       []repo.SeedCandidate
+	audience    []string
+	tournaments map[string]*domain.ThemeTournament
+	entries     map[string][]domain.ThemeTournamentEntry
+	matches     map[string]*domain.ThemeTournamentMatch
+	votes       map[string]domain.ThemeTournamentVote // match id + user id → vote
+	busy        map[string]bool                       // row locked by another instance
+	nextID      int
+}
+
+func newFakeTournaments(themeIDs ...string) *fakeTournaments {
+	f := &fakeTournaments{
+		tournaments: map[string]*domain.ThemeTournament{},
+		entries:     map[string][]domain.ThemeTournamentEntry{},
+		matches:     map[string]*domain.ThemeTournamentMatch{},
+		votes:       map[string]domain.ThemeTournamentVote{},
+		busy:        map[string]bool{},
+	}
+	for i, id := range themeIDs {
+		f.seeds = append(f.seeds, repo.SeedCandidate{ThemeID: id, AvgScore: float64(10 - i), VoteCount: 5})
+	}
+	return f
+}
+
+func (f *fakeTournaments) SeasonSeeds(_ context.Context, _ int, _, _ string, limit int) ([]repo.SeedCandidate, error) {
+	if len(f.seeds) > limit {
+		return f.seeds[:limit], nil
+	}
+	return f.seeds, nil
+}
+
+func (f *fakeTournaments) Create(_ context.Context, t *domain.ThemeTournament, entries []domain.ThemeTournamentEntry, build func(id string) []domain.ThemeTournamentMatch) error {
+	f.nextID++
+	t.ID = fmt.Sprintf("tour-%d", f.nextID)
+	cp := *t
+	f.tournaments[t.ID] = &cp
+	for i := range entries {
+		entries[i].TournamentID = t.ID
+	}
+	f.entries[t.ID] = append([]domain.ThemeTournamentEntry(nil), entries...)
+	for _, m := range build(t.ID) {
+		m.ID = fmt.Sprintf("%s-r%d-s%d", t.ID, m.Round, m.Slot)
+		m := m
+		f.matches[m.ID] = &m
+	}
+	return nil
+}
+
+func (f *fakeTournaments) List(context.Context, string, int) ([]domain.ThemeTournament, error) {
+	return nil, nil
+}
+
+func (f *fakeTournaments) GetByID(_ context.Context, id string) (*domain.ThemeTournament, error) {
+	t, ok := f.tournaments[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *t
+	return &cp, nil
+}
+
+func (f *fakeTournaments) Entries(_ context.Context, tournamentID string) ([]domain.ThemeTournamentEntry, error) {
+	return append([]domain.ThemeTournamentEntry(nil), f.entries[tournamentID]...), nil
+}
+
+func (f *fakeTournaments) Matches(_ context.Context, tournamentID string) ([]domain.ThemeTournamentMatch, error) {
+	var out []domain.ThemeTournamentMatch
+	for _, m := range f.matches {
+		if m.TournamentID == tournamentID {
+			out = append(out, *m)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Round != out[j].Round {
+			return out[i].Round < out[j].Round
+		}
+		return out[i].Slot < out[j].Slot
+	})
+	return out, nil
+}
+
+func (f *fakeTournaments) RoundMatches(ctx context.Context, tournamentID string, round int) ([]domain.ThemeTournamentMatch, error) {
+	all, _ := f.Matches(ctx, tournamentID)
+	var out []domain.ThemeTournamentMatch
+	for _, m := range all {
+		if m.Round == round {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeTournaments) GetMatch(_ context.Context, tournamentID, matchID string) (*domain.ThemeTournamentMatch, error) {
+	m, ok := f.matches[matchID]
+	if !ok || m.TournamentID != tournamentID {
+		return nil, nil
+	}
+	cp := *m
+	return &cp, nil
+}
+
+func (f *fakeTournaments) SaveTournament(_ context.Context, t *domain.ThemeTournament) error {
+	cp := *t
+	f.tournaments[t.ID] = &cp
+	return nil
+}
+
+func (f *fakeTournaments) SaveMatch(_ context.Context, m *domain.ThemeTournamentMatch) error {
+	cp := *m
+	f.matches[m.ID] = &cp
+	return nil
+}
+
+func (f *fakeTournaments) Vote(_ context.Context, v *domain.ThemeTournamentVote) error {
+	key := v.MatchID + "/" + v.UserID
+	if _, ok := f.votes[key]; ok {
+		return repo.ErrAlreadyVoted
+	}
+	f.votes[key] = *v
+	return nil
+}
+
+func (f *fakeTournaments) VoteCounts(_ context.Context, matchID string) (map[string]int, error) {
+	counts := map[string]int{}
+	for _, v := range f.votes {
+		if v.MatchID == matchID {
+			counts[v.ThemeID]++
+		}
+	}
+	return counts, nil
+}
+
+func (f *fakeTournaments) UserVotes(_ context.Context, _, userID string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, v := range f.votes {
+		if v.UserID == userID {
+			out[v.MatchID] = v.ThemeID
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeTournaments) DueIDs(_ context.Context, now time.Time) ([]string, error) {
+	var ids []string
+	for id, t := range f.tournaments {
+		if (t.Status == domain.TournamentScheduled && !t.StartsAt.After(now)) || t.Status == domain.TournamentRunning {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (f *fakeTournaments) Audience(context.Context, string) ([]string, error) {
+	return f.audience, nil
+}
+
+func (f *fakeTournaments) WithLock(ctx context.Context, id string, fn func(tx bracketStore, t *domain.ThemeTournament) error) error {
+	t, _ := f.GetByID(ctx, id)
+	if t == nil || f.busy[id] {
+		return nil
+	}
+	return fn(f, t)
+}
+
+// match returns the stored match of a round and slot.
+func (f *fakeTournaments) match(tournamentID string, round, slot int) domain.ThemeTournamentMatch {
+	return *f.matches[fmt.Sprintf("%s-r%d-s%d", tournamentID, round, slot)]
+}
+
+// newTournamentFixture creates a 4-theme, 60-minute-round tournament
+// starting at startsAt. Seeds 1-4 are th1-th4, so round 1 is th1 vs th4
+// (slot 0) and th2 vs th3 (slot 1).
+func newTournamentFixture(t *testing.T, startsAt time.Time, notifier *TournamentNotifier) (*TournamentService, *fakeTournaments, string) {
+	t.Helper()
+	store := newFakeTournaments("th1", "th2", "th3", "th4")
+	svc := &TournamentService{repo: store, notifier: notifier, log: logger.Default()}
+	detail, err := svc.Create(context.Background(), "admin", domain.TournamentRequest{
+		Title: "Winter cup", Year: 2024, Season: "winter", Size: 4, RoundMinutes: 60, StartsAt: startsAt,
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if detail.Rounds != 2 || len(detail.Matches) != 3 {
+		t.Fatalf("bracket = %d rounds, %d matches; want 2 and 3", detail.Rounds, len(detail.Matches))
+	}
+	return svc, store, detail.ID
+}
+
+func derefOr(s *string) string {
+	if s == nil {
+		return "<nil>"
+	}
+	return *s
+}
+
+func TestTournament_TimedRounds(t *testing.T) {
+	ctx := context.Background()
+	// Round 1 is open right now, so Vote (on the wall clock) lands in it.
+	t0 := time.Now().Add(-time.Minute).Truncate(time.Second)
+	svc, store, id := newTournamentFixture(t, t0, nil)
+
+	svc.Tick(ctx, t0.Add(-time.Second))
+	if got := store.tournaments[id]; got.Status != domain.TournamentScheduled || got.CurrentRound != 0 {
+		t.Fatalf("before start: status %s round %d; want scheduled, round 0", got.Status, got.CurrentRound)
+	}
+
+	svc.Tick(ctx, t0)
+	if got := store.tournaments[id]; got.Status != domain.TournamentRunning || got.CurrentRound != 1 {
+		t.Fatalf("at start: status %s round %d; want running, round 1", got.Status, got.CurrentRound)
+	}
+	m := store.match(id, 1, 0)
+	if !m.StartsAt.Equal(t0) || !m.EndsAt.Equal(t0.Add(time.Hour)) {
+		t.Errorf("round 1 window = %v-%v; want %v-%v", m.StartsAt, m.EndsAt, t0, t0.Add(time.Hour))
+	}
+
+	// th4 upsets th1 on votes; th2 vs th3 gets none and goes to the seed.
+	for user, theme := range map[string]string{"u1": "th4", "u2": "th4", "u3": "th1"} {
+		if err := svc.Vote(ctx, id, m.ID, user, theme); err != nil {
+			t.Fatalf("Vote %s: %v", user, err)
+		}
+	}
+
+	svc.Tick(ctx, t0.Add(30*time.Minute))
+	if m := store.match(id, 1, 0); m.WinnerThemeID != nil || store.tournaments[id].CurrentRound != 1 {
+		t.Fatalf("mid-round tick decided the match: %+v", m)
+	}
+
+	svc.Tick(ctx, t0.Add(time.Hour))
+	upset, tie := store.match(id, 1, 0), store.match(id, 1, 1)
+	if derefOr(upset.WinnerThemeID) != "th4" || upset.DecidedBy != domain.MatchDecidedVotes || upset.VotesA != 1 || upset.VotesB != 2 {
+		t.Errorf("slot 0 = winner %s by %s (%d-%d); want th4 by votes (1-2)",
+			derefOr(upset.WinnerThemeID), upset.DecidedBy, upset.VotesA, upset.VotesB)
+	}
+	if derefOr(tie.WinnerThemeID) != "th2" || tie.DecidedBy != domain.MatchDecidedSeed {
+		t.Errorf("slot 1 = winner %s by %s; want th2 by seed", derefOr(tie.WinnerThemeID), tie.DecidedBy)
+	}
+	final := store.match(id, 2, 0)
+	if store.tournaments[id].CurrentRound != 2 || derefOr(final.ThemeAID) != "th4" || derefOr(final.ThemeBID) != "th2" {
+		t.Fatalf("final = %s vs %s in round %d; want th4 vs th2 in round 2",
+			derefOr(final.ThemeAID), derefOr(final.ThemeBID), store.tournaments[id].CurrentRound)
+	}
+	if !final.StartsAt.Equal(t0.Add(time.Hour)) || !final.EndsAt.Equal(t0.Add(2*time.Hour)) {
+		t.Errorf("final window = %v-%v; want back to back with round 1", final.StartsAt, final.EndsAt)
+	}
+
+	svc.Tick(ctx, t0.Add(2*time.Hour))
+	done := store.tournaments[id]
+	if done.Status != domain.TournamentFinished || derefOr(done.WinnerThemeID) != "th2" || done.FinishedAt == nil {
+		t.Errorf("after final: status %s winner %s; want finished, th2", done.Status, derefOr(done.WinnerThemeID))
+	}
+}
+
+func TestTournament_LateTickerGivesFullWindow(t *testing.T) {
+	ctx := context.Background()
+	t0 := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	svc, store, id := newTournamentFixture(t, t0, nil)
+
+	// The ticker was down past round 1's whole window: the round opens late
+	// with its full hour rather than closing unvoted.
+	late := t0.Add(3 * time.Hour)
+	svc.Tick(ctx, late)
+	m := store.match(id, 1, 0)
+	if store.tournaments[id].CurrentRound != 1 || !m.StartsAt.Equal(late) || !m.EndsAt.Equal(late.Add(time.Hour)) {
+		t.Errorf("late start: round %d window %v-%v; want round 1 from %v for an hour",
+			store.tournaments[id].CurrentRound, m.StartsAt, m.EndsAt, late)
+	}
+}
+
+func TestTournament_VoteWindow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	svc, store, id := newTournamentFixture(t, now.Add(time.Hour), nil)
+	r1 := store.match(id, 1, 0)
+	if err := svc.Vote(ctx, id, r1.ID, "u1", "th1"); errCode(err) != errors.CodeInvalidInput {
+		t.Errorf("vote before the tournament starts: err = %v; want invalid input", err)
+	}
+
+	svc, store, id = newTournamentFixture(t, now.Add(-time.Minute), nil)
+	svc.Tick(ctx, now.Add(-time.Minute))
+	r1 = store.match(id, 1, 0)
+	if err := svc.Vote(ctx, id, r1.ID, "u1", "th1"); err != nil {
+		t.Fatalf("vote in the open round: %v", err)
+	}
+	if err := svc.Vote(ctx, id, r1.ID, "u1", "th4"); errCode(err) != errors.CodeAlreadyExists {
+		t.Errorf("second vote in the match: err = %v; want already exists", err)
+	}
+	if err := svc.Vote(ctx, id, r1.ID, "u2", "th2"); errCode(err) != errors.CodeInvalidInput {
+		t.Errorf("vote for a theme outside the match: err = %v; want invalid input", err)
+	}
+	if err := svc.Vote(ctx, id, store.match(id, 2, 0).ID, "u1", "th1"); errCode(err) != errors.CodeInvalidInput {
+		t.Errorf("vote in a later round: err = %v; want invalid input", err)
+	}
+	if err := svc.Vote(ctx, id, "nope", "u1", "th1"); errCode(err) != errors.CodeNotFound {
+		t.Errorf("vote in an unknown match: err = %v; want not found", err)
+	}
+	detail, err := svc.Get(ctx, id, "u1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if derefOr(detail.Matches[0].MyVote) != "th1" {
+		t.Errorf("my vote = %s; want th1", derefOr(detail.Matches[0].MyVote))
+	}
+
+	// The round's time is up but the ticker has not closed it yet.
+	svc, store, id = newTournamentFixture(t, now.Add(-2*time.Hour), nil)
+	svc.Tick(ctx, now.Add(-2*time.Hour))
+	if err := svc.Vote(ctx, id, store.match(id, 1, 0).ID, "u1", "th1"); errCode(err) != errors.CodeInvalidInput {
+		t.Errorf("vote after the round ended: err = %v; want invalid input", err)
+	}
+}
+
+// roundNotices records the notifications service's internal upserts.
+type roundNotices struct {
+	mu   sync.Mutex
+	sent []map[string]json.RawMessage
+}
+
+func (n *roundNotices) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body map[string]json.RawMessage
+	if r.URL.Path != "/internal/notifications" || json.NewDecoder(r.Body).Decode(&body) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	n.mu.Lock()
+	n.sent = append(n.sent, body)
+	n.mu.Unlock()
+}
+
+func (n *roundNotices) take() []map[string]json.RawMessage {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	out := n.sent
+	n.sent = nil
+	return out
+}
+
+func TestTournament_Cancel(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	svc, store, id := newTournamentFixture(t, now.Add(-time.Minute), nil)
+	svc.Tick(ctx, now.Add(-time.Minute))
+
+	// A tick on another instance holds the row: the cancel must not write.
+	store.busy[id] = true
+	if err := svc.Cancel(ctx, id); errCode(err) != errors.CodeConflict {
+		t.Errorf("cancel while locked: err = %v; want conflict", err)
+	}
+	if got := store.tournaments[id].Status; got != domain.TournamentRunning {
+		t.Fatalf("status after a locked cancel = %s; want running", got)
+	}
+	store.busy[id] = false
+
+	if err := svc.Cancel(ctx, id); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if got := store.tournaments[id].Status; got != domain.TournamentCancelled {
+		t.Errorf("status = %s; want cancelled", got)
+	}
+	if err := svc.Cancel(ctx, id); errCode(err) != errors.CodeInvalidInput {
+		t.Errorf("second cancel: err = %v; want invalid input", err)
+	}
+	if err := svc.Cancel(ctx, "nope"); errCode(err) != errors.CodeNotFound {
+		t.Errorf("cancel of an unknown tournament: err = %v; want not found", err)
+	}
+}
+
+func TestTournament_RoundStartNotifications(t *testing.T) {
+	ctx := context.Background()
+	notices := &roundNotices{}
+	srv := httptest.NewServer(notices)
+	defer srv.Close()
+
+	t0 := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	svc, store, id := newTournamentFixture(t, t0, NewTournamentNotifier(srv.URL, true, logger.Default()))
+	const fan = "0b1c2d3e-4f50-4617-8293-a4b5c6d7e8f9"
+	store.audience = []string{fan, "not-a-uuid"}
+
+	checkRound := func(round int, endsAt time.Time) {
+		t.Helper()
+		sent := notices.take()
+		if len(sent) != 1 {
+			t.Fatalf("round %d: %d notifications; want 1 (the non-uuid user is skipped)", round, len(sent))
+		}
+		var userID, typ, dedupe string
+		var payload tournamentRoundNotification
+		_ = json.Unmarshal(sent[0]["user_id"], &userID)
+		_ = json.Unmarshal(sent[0]["type"], &typ)
+		_ = json.Unmarshal(sent[0]["dedupe_key"], &dedupe)
+		_ = json.Unmarshal(sent[0]["payload"], &payload)
+		if userID != fan || typ != notifyTypeTournamentRound || dedupe != fmt.Sprintf("theme_tournament:%s:round:%d", id, round) {
+			t.Errorf("round %d notification = %s %s %s", round, userID, typ, dedupe)
+		}
+		if payload.Round != round || payload.Rounds != 2 || payload.EndsAt != endsAt.Format(time.RFC3339) {
+			t.Errorf("round %d payload = %+v; want ends_at %s", round, payload, endsAt.Format(time.RFC3339))
+		}
+	}
+
+	svc.Tick(ctx, t0)
+	checkRound(1, t0.Add(time.Hour))
+
+	svc.Tick(ctx, t0.Add(30*time.Minute))
+	if sent := notices.take(); len(sent) != 0 {
+		t.Errorf("mid-round tick sent %d notifications", len(sent))
+	}
+
+	svc.Tick(ctx, t0.Add(time.Hour))
+	checkRound(2, t0.Add(2*time.Hour))
+
+	svc.Tick(ctx, t0.Add(2*time.Hour))
+	if sent := notices.take(); len(sent) != 0 {
+		t.Errorf("finishing the tournament sent %d notifications", len(sent))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/database"
 	"github.com/ILITA-hub/animeenigma/libs/httputil"
 	"github.com/ILITA-hub/animeenigma/libs/logger"
 	"github.com/ILITA-hub/animeenigma/libs/metrics"
@@ -17,6 +18,11 @@ func NewRouter(
 	ratingHandler *handler.RatingHandler,
 	adminHandler *handler.AdminHandler,
 	videoProxyHandler *handler.VideoProxyHandler,
+	playlistHandler *handler.PlaylistHandler,
+	radioHandler *handler.RadioHandler,
+	artistHandler *handler.ArtistHandler,
+	tournamentHandler *handler.TournamentHandler,
+	accountHandler *database.UserDataHandler,
 	jwtConfig authz.JWTConfig,
 	log *logger.Logger,
 	metricsCollector *metrics.Collector,
@@ -41,6 +47,15 @@ func NewRouter(
 		metrics.Handler().ServeHTTP(w, r)
 	})
 
+	// Anidle theme-mode pool — Docker-network only.
+	r.Get("/internal/guessgame/themes", themeHandler.GuessPool)
+
+	// Account export / erasure fan-out from auth. Docker-network only.
+	if accountHandler != nil {
+		r.Post("/internal/account/export", accountHandler.Export)
+		r.Post("/internal/account/erase", accountHandler.Erase)
+	}
+
 	// API routes
 	r.Route("/api/themes", func(r chi.Router) {
 		// Public routes with optional auth (for user scores)
@@ -48,6 +63,14 @@ func NewRouter(
 			r.Use(OptionalAuthMiddleware(jwtConfig))
 			r.Get("/", themeHandler.ListThemes)
 			r.Get("/{id}", themeHandler.GetTheme)
+			r.Get("/playlists/{playlistID}", playlistHandler.GetPlaylist)
+			r.Get("/playlists/{playlistID}/m3u", playlistHandler.ExportPlaylistM3U)
+			r.Get("/radio", radioHandler.Radio)
+			r.Get("/radio/m3u", radioHandler.RadioM3U)
+			r.Get("/artists", artistHandler.ListArtists)
+			r.Get("/artists/{slug}", artistHandler.GetArtist)
+			r.Get("/tournaments", tournamentHandler.ListTournaments)
+			r.Get("/tournaments/{tournamentID}", tournamentHandler.GetTournament)
 		})
 
 		// Video/audio proxy (public, no auth needed)
@@ -60,6 +83,17 @@ func NewRouter(
 			r.Post("/{id}/rate", ratingHandler.RateTheme)
 			r.Delete("/{id}/rate", ratingHandler.UnrateTheme)
 			r.Get("/my-ratings", ratingHandler.GetMyRatings)
+
+			r.Get("/playlists", playlistHandler.ListMyPlaylists)
+			r.Post("/playlists", playlistHandler.CreatePlaylist)
+			r.Patch("/playlists/{playlistID}", playlistHandler.UpdatePlaylist)
+			r.Delete("/playlists/{playlistID}", playlistHandler.DeletePlaylist)
+			r.Post("/playlists/{playlistID}/items", playlistHandler.AddPlaylistItem)
+			r.Delete("/playlists/{playlistID}/items/{themeID}", playlistHandler.RemovePlaylistItem)
+			r.Put("/playlists/{playlistID}/order", playlistHandler.ReorderPlaylist)
+			r.Post("/playlists/{playlistID}/clone", playlistHandler.ClonePlaylist)
+
+			r.Post("/tournaments/{tournamentID}/matches/{matchID}/vote", tournamentHandler.VoteMatch)
 		})
 
 		// Admin routes (JWT + admin role)
@@ -68,6 +102,8 @@ func NewRouter(
 			r.Use(AdminRoleMiddleware)
 			r.Post("/admin/sync", adminHandler.TriggerSync)
 			r.Get("/admin/sync/status", adminHandler.GetSyncStatus)
+			r.Post("/admin/tournaments", tournamentHandler.CreateTournament)
+			r.Post("/admin/tournaments/{tournamentID}/cancel", tournamentHandler.CancelTournament)
 		})
 	})
 
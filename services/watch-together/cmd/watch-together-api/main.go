@@ -15,6 +15,9 @@
 //     starts; a Redis outage at boot is fatal because every Redis-only
 //     path needs the client.
 //  5. repo.NewRoomRepo — Redis facade owning every wt:* key.
+//     database.New + AutoMigrate — Postgres for scheduled watch parties
+//     (parties, invites, archived attendance and chat) and the durable
+//     chat history (threads + write-behind chat log).
 //  6. service.NewRoomService — single mutation surface for the room
 //     lifecycle (REST handler + WS snapshot generation).
 //  7. instanceID via uuid.NewString — tags every pubsub publish so the
@@ -31,8 +34,14 @@
 // 12. handler.NewWebSocketHandler — /ws upgrade entry point (01.5).
 //     Upgrade Cancels any pending grace timer; OnClose Starts a new one
 //     when MemberCount drops to 0 (Plan 05.1).
-// 13. transport.NewRouter — mounts /health + /metrics + /rooms + /ws.
-// 14. http.Server with graceful shutdown on SIGINT/SIGTERM.
+// 13. service.NewPartyService — watch-party scheduling; installed as the
+//     room archiver on RoomService + GraceManager, and its scheduler loop
+//     (reminders, room materialization, orphan sweep) runs until shutdown.
+// 14. service.NewChatLog — write-behind chat history; fed by the inbound
+//     router, read by RoomService snapshots and the /history handler.
+//     Drained after hub.Close on shutdown.
+// 15. transport.NewRouter — mounts /health + /metrics + /rooms + /parties + /history + /ws + /internal/account/*.
+// 16. http.Server with graceful shutdown on SIGINT/SIGTERM.
 //
 // On SIGTERM: hub.Close() runs FIRST so live WS connections drain cleanly.
 // graceMgr.Close() runs AFTER hub.Close — the OnClose cascade from
@@ -40,8 +49,9 @@
 // immediately before teardown; graceMgr's `closed` atomic flag
 // short-circuits those Start calls so SIGTERM teardown stays quiet.
 //
-// No database — service is Redis-only by design (WT-FOUND-02; persistence
-// deferred to v1.2 via the persistent-rooms workstream).
+// Live rooms stay Redis-only (WT-FOUND-02). Postgres holds only what must
+// outlive a room: scheduled watch parties, their archives and the chat
+// history.
 package main
 
 import (
@@ -56,10 +66,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 
+	"github.com/ILITA-hub/animeenigma/libs/database"
 	"github.com/ILITA-hub/animeenigma/libs/logger"
 	"github.com/ILITA-hub/animeenigma/libs/metrics"
 	"github.com/ILITA-hub/animeenigma/libs/tracing"
 	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/config"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
 	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/handler"
 	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/hub"
 	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/repo"
@@ -108,6 +120,24 @@ func main() {
 	}
 	pingCancel()
 
+	// Postgres — scheduled watch parties and the durable chat history
+	// (auto-creates the DB).
+	db, err := database.New(cfg.Database)
+	if err != nil {
+		log.Fatalw("failed to connect to database", "error", err)
+	}
+	defer db.Close()
+	if err := db.AutoMigrate(
+		&domain.WatchParty{},
+		&domain.WatchPartyInvite{},
+		&domain.WatchPartyAttendance{},
+		&domain.WatchPartyMessage{},
+		&domain.ChatThread{},
+		&domain.ChatLogEntry{},
+	); err != nil {
+		log.Fatalw("failed to migrate database", "error", err)
+	}
+
 	// Repo → Service. The service is the single mutation surface for room
 	// lifecycle (Plan 01.4); the WS upgrader (01.5) shares the same
 	// *RoomService for snapshot generation.
@@ -144,12 +174,58 @@ func main() {
 	// timer before the explicit DeleteRoom.
 	roomHandler := handler.NewRoomHandler(roomService, wsHub, graceMgr, cfg, log)
 
+	// Voice chat: media is peer-to-peer; the room service only hands members
+	// STUN servers plus short-lived TURN credentials minted from the shared
+	// secret (TURN stays off without one).
+	roomService.SetTURN(service.NewTURNIssuer(service.TURNOptions{
+		STUNURLs: cfg.Voice.STUNURLs,
+		TURNURLs: cfg.Voice.TURNURLs,
+		Secret:   cfg.Voice.TURNSecret,
+		TTL:      cfg.Voice.TURNCredentialTTL,
+	}))
+
+	// Durable chat history — every chat message and reaction is written
+	// behind to Postgres. The live path only enqueues; chatCancel + Wait on
+	// shutdown flush what is still buffered.
+	chatLogRepo := repo.NewChatLogRepo(db.DB)
+	chatLog := service.NewChatLog(chatLogRepo, log)
+	roomService.SetChatLog(chatLog)
+	inboundRouter.SetChatRecorder(chatLog)
+	historyHandler := handler.NewChatHistoryHandler(chatLog, log)
+	chatCtx, chatCancel := context.WithCancel(context.Background())
+	go chatLog.Run(chatCtx)
+
+	// Scheduled watch parties. The service archives party rooms from both
+	// teardown paths (host DELETE + grace fire) and runs the scheduler on
+	// every instance — its status transitions are conditional UPDATEs, so
+	// replicas never double-remind or double-materialize.
+	partyRepo := repo.NewPartyRepo(db.DB)
+	partyNotifier := service.NewPartyNotifier(cfg.Party.NotificationsURL, cfg.Party.NotifyEnabled, log)
+	partyService := service.NewPartyService(partyRepo, roomRepo, roomService, catalogClient, partyNotifier, service.PartyOptions{
+		PublicBaseURL: cfg.PublicBaseURL,
+		ReminderLead:  cfg.Party.ReminderLead,
+		TickInterval:  cfg.Party.TickInterval,
+	}, log)
+	roomService.SetArchiver(partyService)
+	graceMgr.SetArchiver(partyService)
+	partyHandler := handler.NewPartyHandler(partyService, log)
+	schedCtx, schedCancel := context.WithCancel(context.Background())
+	defer schedCancel()
+	go partyService.Run(schedCtx)
+
+
 	// WS upgrade handler (01.5) — JWT validation, capacity gate, snapshot
 	// on connect, member:joined/left lifecycle. Mounted at /api/watch-together/ws
 	// in transport.NewRouter, OUTSIDE the AuthMiddleware-wrapped subgroup.
 	wsHandler := handler.NewWebSocketHandler(wsHub, roomRepo, roomService, inboundRouter, graceMgr, cfg, log)
 
-	router := transport.NewRouter(cfg, roomHandler, wsHandler, log, metricsCollector)
+	// Account fan-out (/internal/account/*) — the auth service calls these on
+	// a data export or account deletion.
+	accountHandler := handler.NewAccountInternalHandler(roomRepo, log).
+		WithParties(partyRepo).
+		WithChatHistory(chatLogRepo)
+
+	router := transport.NewRouter(cfg, roomHandler, wsHandler, accountHandler, partyHandler, historyHandler, log, metricsCollector)
 
 	srv := &http.Server{
 		Addr:         cfg.Server.Address(),
@@ -168,6 +244,7 @@ func main() {
 			"grace_period", cfg.GracePeriod,
 			"public_base_url", cfg.PublicBaseURL,
 			"allow_all_origins", cfg.AllowAllOrigins,
+			"voice_turn_enabled", cfg.Voice.TURNSecret != "",
 		)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalw("failed to start server", "error", err)
@@ -178,6 +255,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Info("shutting down watch-together service...")
+	schedCancel()
 
 	// Tear down the hub FIRST so live WS connections drain cleanly before
 	// the HTTP listener stops accepting. The hub's Close cancels every
@@ -192,6 +270,11 @@ func main() {
 	// client).
 	graceMgr.Close()
 
+	// Chat log last among the writers: the hub is closed, so nothing more
+	// is recorded; flush the buffered tail before the DB handle closes.
+	chatCancel()
+	chatLog.Wait()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
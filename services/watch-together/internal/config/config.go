@@ -8,9 +8,11 @@
 //   - RoomTTL:         WATCH_TOGETHER_ROOM_TTL (default 900s, sliding)
 //   - GracePeriod:     WATCH_TOGETHER_GRACE_PERIOD (default 5m post-last-disconnect)
 //   - PublicBaseURL:   WATCH_TOGETHER_PUBLIC_BASE_URL (default https://animeenigma.org)
+//   - Voice:           WATCH_TOGETHER_STUN_URLS / WATCH_TOGETHER_TURN_* (TURN off without a secret)
 //
-// No Postgres / GORM — this service is Redis-only by design
-// (Phase 01-CONTEXT.md / WT-FOUND-02 deferred persistence to v1.2).
+// Live rooms stay Redis-only (WT-FOUND-02). Postgres (DB_*) holds only what
+// must outlive a room: scheduled watch parties, their invitations and the
+// post-party attendance / chat archive.
 package config
 
 import (
@@ -22,12 +24,14 @@ import (
 
 	"github.com/ILITA-hub/animeenigma/libs/authz"
 	"github.com/ILITA-hub/animeenigma/libs/cache"
+	"github.com/ILITA-hub/animeenigma/libs/database"
 )
 
 type Config struct {
 	Server ServerConfig
-	Redis  cache.Config
-	JWT    authz.JWTConfig
+	Redis    cache.Config
+	Database database.Config
+	JWT      authz.JWTConfig
 
 	// MaxMembers caps room size (WT-NF-02 / 01-CONTEXT.md). Default 10.
 	MaxMembers int
@@ -69,6 +73,44 @@ type Config struct {
 	// header on a WS upgrade is the requesting page's origin, so this list
 	// must contain every origin the frontend is served from.
 	ExtraAllowedOrigins []string
+
+	// Party configures scheduled watch parties.
+	Party PartyConfig
+
+	// Voice configures the ICE servers handed to voice-chat clients.
+	Voice VoiceConfig
+}
+
+// VoiceConfig lists the STUN / TURN servers for voice chat. TURN uses the
+// coturn shared-secret scheme (use-auth-secret): the service mints
+// short-lived credentials from TURNSecret and never stores any.
+type VoiceConfig struct {
+	// STUNURLs are always returned. Default stun:stun.l.google.com:19302.
+	STUNURLs []string
+	// TURNURLs are returned only when TURNSecret is set, e.g.
+	// "turn:turn.animeenigma.org:3478?transport=udp".
+	TURNURLs []string
+	// TURNSecret is coturn's static-auth-secret. Empty disables TURN:
+	// peers behind symmetric NATs then cannot connect.
+	TURNSecret string
+	// TURNCredentialTTL bounds each minted credential. Default 6h.
+	TURNCredentialTTL time.Duration
+}
+
+// PartyConfig controls the watch-party scheduler and its reminder producer.
+type PartyConfig struct {
+	// NotificationsURL is the base URL of the notifications service inside
+	// the Docker network; only /internal/notifications is called. Default
+	// http://notifications:8090.
+	NotificationsURL string
+	// NotifyEnabled toggles invite / reminder notifications; when false they
+	// are dropped (parties still schedule and start). Default true.
+	NotifyEnabled bool
+	// ReminderLead is how long before StartsAt the reminder goes out.
+	// Default 15m.
+	ReminderLead time.Duration
+	// TickInterval is the scheduler poll period. Default 30s.
+	TickInterval time.Duration
 }
 
 type ServerConfig struct {
@@ -99,6 +141,14 @@ func Load() (*Config, error) {
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       getEnvInt("REDIS_DB", 0),
 		},
+		Database: database.Config{
+			Host:     getEnv("DB_HOST", "localhost"),
+			Port:     getEnvInt("DB_PORT", 5432),
+			User:     getEnv("DB_USER", "postgres"),
+			Password: getEnv("DB_PASSWORD", "postgres"),
+			Database: getEnv("DB_NAME", "animeenigma"),
+			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+		},
 		JWT: authz.JWTConfig{
 			Secret:          getEnv("JWT_SECRET", ""),
 			Issuer:          getEnv("JWT_ISSUER", "animeenigma"),
@@ -112,6 +162,18 @@ func Load() (*Config, error) {
 		CatalogURL:          strings.TrimRight(getEnv("CATALOG_URL", "http://catalog:8081"), "/"),
 		AllowAllOrigins:     getEnvBool("WATCH_TOGETHER_ALLOW_ALL_ORIGINS", false),
 		ExtraAllowedOrigins: parseCSV(getEnv("WATCH_TOGETHER_ALLOWED_ORIGINS", "")),
+		Party: PartyConfig{
+			NotificationsURL: strings.TrimRight(getEnv("NOTIFICATIONS_INTERNAL_URL", "http://notifications:8090"), "/"),
+			NotifyEnabled:    getEnvBool("WATCH_PARTY_NOTIFY_ENABLED", true),
+			ReminderLead:     getEnvDuration("WATCH_PARTY_REMINDER_LEAD", 15*time.Minute),
+			TickInterval:     getEnvDuration("WATCH_PARTY_TICK_INTERVAL", 30*time.Second),
+		},
+		Voice: VoiceConfig{
+			STUNURLs:          parseCSV(getEnv("WATCH_TOGETHER_STUN_URLS", "stun:stun.l.google.com:19302")),
+			TURNURLs:          parseCSV(getEnv("WATCH_TOGETHER_TURN_URLS", "")),
+			TURNSecret:        getEnv("WATCH_TOGETHER_TURN_SECRET", ""),
+			TURNCredentialTTL: getEnvDuration("WATCH_TOGETHER_TURN_TTL", 6*time.Hour),
+		},
 	}, nil
 }
 
@@ -0,0 +1,53 @@
+package domain
+
+// AccountExport is the watch-together slice of a user's data export, served
+// to the auth service by POST /internal/account/export. Rooms are ephemeral
+// (sliding Redis TTL), so this is whatever is still live at export time.
+type AccountExport struct {
+	HostedRooms []Room              `json:"hosted_rooms"`
+	Memberships []AccountMembership `json:"memberships"`
+	Messages    []AccountMessage    `json:"messages"`
+	// Parties is the Postgres half: scheduled parties and their archives.
+	Parties *PartyAccountExport `json:"parties,omitempty"`
+	// ChatHistory is the durable chat log (threads owned, entries sent).
+	ChatHistory *ChatAccountExport `json:"chat_history,omitempty"`
+}
+
+// AccountMembership is one live room the user is a member of.
+type AccountMembership struct {
+	RoomID string     `json:"room_id"`
+	Meta   MemberMeta `json:"meta"`
+}
+
+// AccountMessage is one chat message the user sent, with its room.
+type AccountMessage struct {
+	RoomID string `json:"room_id"`
+	ChatMessage
+}
+
+// AccountErasure counts what POST /internal/account/erase removed.
+type AccountErasure struct {
+	Memberships int `json:"memberships"`
+	Messages    int `json:"messages"`
+	HostCleared int `json:"host_cleared"`
+
+	Parties     *PartyAccountErasure `json:"parties,omitempty"`
+	ChatHistory *ChatAccountErasure  `json:"chat_history,omitempty"`
+}
+
+// PartyAccountExport is the user's scheduled-party data: parties they host,
+// their invites/RSVPs, attendance records and archived chat.
+type PartyAccountExport struct {
+	HostedParties []WatchParty           `json:"hosted_parties"`
+	Invites       []WatchPartyInvite     `json:"invites"`
+	Attendance    []WatchPartyAttendance `json:"attendance"`
+	Messages      []WatchPartyMessage    `json:"messages"`
+}
+
+// PartyAccountErasure counts the party rows an erase removed.
+type PartyAccountErasure struct {
+	Parties    int `json:"parties"`
+	Invites    int `json:"invites"`
+	Attendance int `json:"attendance"`
+	Messages   int `json:"messages"`
+}
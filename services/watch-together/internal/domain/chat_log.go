@@ -0,0 +1,70 @@
+package domain
+
+import "time"
+
+// ChatThread is the durable chat history a room writes into. Rooms are
+// ephemeral; a thread outlives them so a re-opened room (POST /rooms with
+// history_id) or a scheduled party continues the same history. ID is the
+// first room's ID for ad-hoc rooms and the party ID for party rooms.
+// OwnerUserID — the host who opened it — is the only one who may browse or
+// export it.
+type ChatThread struct {
+	ID           string    `gorm:"size:64;primaryKey" json:"id"`
+	OwnerUserID  string    `gorm:"size:64;not null;index" json:"owner_user_id"` // guests carry non-UUID ids
+	AnimeID      string    `gorm:"size:64" json:"anime_id"`
+	PartyID      string    `gorm:"size:64" json:"party_id,omitempty"`
+	LastRoomID   string    `gorm:"size:64" json:"last_room_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	LastActiveAt time.Time `gorm:"index" json:"last_active_at"`
+}
+
+// TableName pins the table name.
+func (ChatThread) TableName() string { return "watch_chat_threads" }
+
+// ChatLogEntry is one chat message or reaction written behind to Postgres,
+// with the room context it was sent in. PlaybackTime is the room's canonical
+// position (seconds) at send time, so an export can be lined up with the
+// episode.
+type ChatLogEntry struct {
+	ID           string    `gorm:"size:64;primaryKey" json:"id"` // chat message ID; reactions get their own
+	ThreadID     string    `gorm:"size:64;not null;index:idx_chat_log_thread_sent,priority:1" json:"thread_id"`
+	RoomID       string    `gorm:"size:64;not null;index" json:"room_id"`
+	AnimeID      string    `gorm:"size:64" json:"anime_id"`
+	EpisodeID    string    `gorm:"size:64" json:"episode_id"`
+	Kind         string    `gorm:"size:16;not null" json:"kind"`
+	UserID       string    `gorm:"size:64;not null;index" json:"user_id"`
+	Username     string    `gorm:"size:64" json:"username"`
+	Body         string    `gorm:"type:text" json:"body"` // message text, or the reaction emoji
+	PlaybackTime float64   `json:"playback_time"`
+	SentAt       time.Time `gorm:"not null;index:idx_chat_log_thread_sent,priority:2" json:"sent_at"`
+}
+
+// TableName pins the table name.
+func (ChatLogEntry) TableName() string { return "watch_chat_log" }
+
+// Chat log entry kinds — string union for ChatLogEntry.Kind.
+const (
+	ChatKindMessage  = "message"
+	ChatKindReaction = "reaction"
+)
+
+// ChatHistoryPage is one page of a thread, oldest first. NextBefore, when
+// set, is the `before` cursor for the next (older) page.
+type ChatHistoryPage struct {
+	Thread     ChatThread     `json:"thread"`
+	Entries    []ChatLogEntry `json:"entries"`
+	NextBefore *time.Time     `json:"next_before,omitempty"`
+}
+
+// ChatAccountExport is the user's durable chat data: the threads they own
+// and every entry they sent.
+type ChatAccountExport struct {
+	Threads []ChatThread   `json:"threads"`
+	Entries []ChatLogEntry `json:"entries"`
+}
+
+// ChatAccountErasure counts the chat-log rows an erase removed.
+type ChatAccountErasure struct {
+	Threads int `json:"threads"`
+	Entries int `json:"entries"`
+}
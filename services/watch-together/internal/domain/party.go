@@ -0,0 +1,124 @@
+package domain
+
+import "time"
+
+// WatchParty is a scheduled co-watch, persisted in Postgres because it
+// outlives any Redis room: it exists days before its room is materialized
+// at StartsAt and keeps its archive after the room is gone.
+//
+// Lifecycle: PartyScheduled → PartyLive (room materialized, RoomID set) →
+// PartyEnded (room torn down, archive written). PartyCancelled is terminal
+// and only reachable from PartyScheduled.
+type WatchParty struct {
+	ID            string     `gorm:"type:uuid;primaryKey" json:"id"`
+	HostUserID    string     `gorm:"type:uuid;not null;index" json:"host_user_id"`
+	Title         string     `gorm:"size:120;not null" json:"title"`
+	AnimeID       string     `gorm:"size:64;not null" json:"anime_id"`
+	EpisodeID     string     `gorm:"size:64;not null" json:"episode_id"`
+	Player        string     `gorm:"size:32;not null" json:"player"`
+	TranslationID string     `gorm:"size:128" json:"translation_id"`
+	ControlMode   string     `gorm:"size:32" json:"control_mode"`
+	StartsAt      time.Time  `gorm:"not null;index" json:"starts_at"`
+	InviteCode    string     `gorm:"size:32;not null;uniqueIndex" json:"-"` // secret half of the invite link
+	Status        string     `gorm:"size:16;not null;index" json:"status"`
+	RoomID        string     `gorm:"size:64;index" json:"room_id,omitempty"`
+	ReminderSent  *time.Time `json:"reminder_sent_at,omitempty"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	EndedAt       *time.Time `json:"ended_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName pins the table name.
+func (WatchParty) TableName() string { return "watch_parties" }
+
+// Party statuses — string union for WatchParty.Status.
+const (
+	PartyScheduled = "scheduled"
+	PartyLive      = "live"
+	PartyEnded     = "ended"
+	PartyCancelled = "cancelled"
+)
+
+// WatchPartyInvite is one invitee and their RSVP. Created by the host's
+// invite list or when someone opens the invite link and RSVPs.
+type WatchPartyInvite struct {
+	PartyID     string     `gorm:"type:uuid;primaryKey" json:"party_id"`
+	UserID      string     `gorm:"type:uuid;primaryKey;index" json:"user_id"`
+	RSVP        string     `gorm:"size:16;not null" json:"rsvp"`
+	InvitedAt   time.Time  `json:"invited_at"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+}
+
+// TableName pins the table name.
+func (WatchPartyInvite) TableName() string { return "watch_party_invites" }
+
+// RSVP values — string union for WatchPartyInvite.RSVP.
+const (
+	RSVPPending  = "pending"
+	RSVPGoing    = "going"
+	RSVPMaybe    = "maybe"
+	RSVPDeclined = "declined"
+)
+
+// ValidRSVP reports whether rsvp is an answer a user may give (pending is
+// only ever the initial state).
+func ValidRSVP(rsvp string) bool {
+	switch rsvp {
+	case RSVPGoing, RSVPMaybe, RSVPDeclined:
+		return true
+	}
+	return false
+}
+
+// WatchPartyAttendance is one member who joined the party's room at least
+// once, archived when the room is torn down.
+type WatchPartyAttendance struct {
+	PartyID     string    `gorm:"type:uuid;primaryKey" json:"party_id"`
+	UserID      string    `gorm:"size:64;primaryKey" json:"user_id"` // guests carry non-UUID ids
+	Username    string    `gorm:"size:64" json:"username"`
+	FirstJoined time.Time `json:"first_joined_at"`
+}
+
+// TableName pins the table name.
+func (WatchPartyAttendance) TableName() string { return "watch_party_attendance" }
+
+// WatchPartyMessage is one archived chat message of a party's room.
+type WatchPartyMessage struct {
+	PartyID   string    `gorm:"type:uuid;primaryKey" json:"party_id"`
+	MessageID string    `gorm:"size:64;primaryKey" json:"id"`
+	UserID    string    `gorm:"size:64;index" json:"user_id"`
+	Username  string    `gorm:"size:64" json:"username"`
+	Body      string    `gorm:"type:text" json:"body"`
+	SentAt    time.Time `gorm:"index" json:"sent_at"`
+}
+
+// TableName pins the table name.
+func (WatchPartyMessage) TableName() string { return "watch_party_messages" }
+
+// Attendee is the value half of the `wt:room:{roomId}:attendance` HASH —
+// everyone who ever joined the room, kept after they leave so a party's
+// attendance can be archived at teardown.
+type Attendee struct {
+	Username    string `json:"username"`
+	FirstJoined int64  `json:"first_joined"` // unix sec
+}
+
+// PartyView is the API shape of one party for a given viewer: the party,
+// its invite list, RSVP tallies and (host only) the invite link.
+type PartyView struct {
+	WatchParty
+	Invites   []WatchPartyInvite `json:"invites"`
+	Going     int                `json:"going"`
+	Maybe     int                `json:"maybe"`
+	Declined  int                `json:"declined"`
+	MyRSVP    string             `json:"my_rsvp,omitempty"`
+	InviteURL string             `json:"invite_url,omitempty"`
+}
+
+// PartyArchive is the post-party record: who came and what was said.
+type PartyArchive struct {
+	PartyID    string                 `json:"party_id"`
+	Attendance []WatchPartyAttendance `json:"attendance"`
+	Messages   []WatchPartyMessage    `json:"messages"`
+}
@@ -0,0 +1,35 @@
+package domain
+
+// QueueItem is one entry of the room's episode queue, stored JSON-encoded in
+// the `wt:room:{roomId}:queue` HASH keyed by ID. The queue plays in
+// QueueItem order: most upvotes first, then oldest AddedAt (see repo
+// GetQueue). Items are catalog-validated when added.
+//
+// A queue item may name a different anime than the one playing — marathon
+// nights chain shows — so advancing rewrites Room.AnimeID too.
+type QueueItem struct {
+	ID            string   `json:"id"`
+	AnimeID       string   `json:"anime_id"`
+	EpisodeID     string   `json:"episode_id"`
+	Player        string   `json:"player"`
+	TranslationID string   `json:"translation_id"`
+	AddedBy       string   `json:"added_by"`
+	AddedAt       int64    `json:"added_at"` // unix milliseconds
+	Votes         []string `json:"votes"`    // user IDs that upvoted; len() drives the order
+}
+
+// QueueMaxItems caps one room's queue.
+const QueueMaxItems = 50
+
+// Advance reasons carried by RoomEpisodeAdvancedData.Reason.
+const (
+	AdvanceEnded    = "ended"     // the current episode reached its end (or its ED with skip_op_ed)
+	AdvanceVoteSkip = "vote_skip" // a majority of members voted to skip
+	AdvanceNext     = "next"      // a member with playback control sent queue:next
+)
+
+// PlaybackEventData.Reason values for server-initiated seeks.
+const (
+	SkipReasonOP = "skip_op"
+	SkipReasonED = "skip_ed"
+)
@@ -20,10 +20,28 @@ type Room struct {
 	Player        string `json:"player"` // PlayerKodik | PlayerAnimeLib | PlayerOurEnglish | PlayerHanime | PlayerAePlayer
 	TranslationID string `json:"translation_id"`
 
-	PlaybackState           string  `json:"playback_state"`            // StatePlaying | StatePaused
-	PlaybackTime            float64 `json:"playback_time"`             // seconds into the episode
-	PlaybackTimeUpdatedAtMs int64   `json:"playback_time_updated_at"`  // unix milliseconds — drift-detection anchor
-	HostUserID              string  `json:"host_user_id"`              // cosmetic only — any member can drive playback
+	PlaybackState           string  `json:"playback_state"`           // StatePlaying | StatePaused
+	PlaybackTime            float64 `json:"playback_time"`            // seconds into the episode
+	PlaybackTimeUpdatedAtMs int64   `json:"playback_time_updated_at"` // unix milliseconds — drift-detection anchor
+	HostUserID              string  `json:"host_user_id"`             // transferable via host:transfer; who may drive playback depends on ControlMode
+
+	// ControlMode gates playback:* and state:change_* (ControlEveryone |
+	// ControlHostOnly | ControlHostPlusDelegates). Empty — rooms created
+	// before protocol 1.1 — reads as ControlEveryone.
+	ControlMode string `json:"control_mode"`
+	// SlowModeSeconds is the minimum gap between two chat messages from the
+	// same member; 0 disables slow mode. Host and delegates are exempt.
+	SlowModeSeconds int `json:"slow_mode_seconds"`
+	// SkipOpEd makes the server seek past the catalog's OP window and
+	// treat the ED start as the episode end for queue auto-advance.
+	SkipOpEd bool `json:"skip_op_ed"`
+	// PartyID links a room materialized from a scheduled watch party back
+	// to its Postgres row; empty for ad-hoc rooms.
+	PartyID string `json:"party_id,omitempty"`
+	// ChatThreadID names the durable chat history (ChatThread) the room
+	// writes into; empty for rooms created before it existed, which log
+	// under their own ID.
+	ChatThreadID string `json:"chat_thread_id,omitempty"`
 }
 
 // MemberMeta is the value half of the `wt:room:{roomId}:members` HASH;
@@ -42,14 +60,62 @@ type Member struct {
 	Meta   MemberMeta `json:"meta"`
 }
 
+// Control modes — string union for Room.ControlMode.
+const (
+	ControlEveryone          = "everyone"
+	ControlHostOnly          = "host_only"
+	ControlHostPlusDelegates = "host_plus_delegates"
+)
+
+// MaxSlowModeSeconds caps Room.SlowModeSeconds.
+const MaxSlowModeSeconds = 300
+
+// EffectiveControlMode maps the empty (pre-1.1) value onto ControlEveryone.
+func EffectiveControlMode(mode string) string {
+	if mode == "" {
+		return ControlEveryone
+	}
+	return mode
+}
+
+// ValidControlMode reports whether mode is one of the Control* constants.
+func ValidControlMode(mode string) bool {
+	switch mode {
+	case ControlEveryone, ControlHostOnly, ControlHostPlusDelegates:
+		return true
+	}
+	return false
+}
+
+// RoomModeration is the protocol-1.1 per-room moderation state, stored as
+// three Redis SETs next to the room HASH. Slices are never nil.
+type RoomModeration struct {
+	Delegates []string `json:"delegates"`
+	Muted     []string `json:"muted"`
+	Banned    []string `json:"banned"`
+}
+
 // RoomSnapshot is the payload of the outbound `room:snapshot` envelope sent
 // to a freshly-connected client. Includes the canonical room state, full
 // member roster, the last 50 chat messages (capped by retrieval, not by
 // storage — Redis keeps 100 per the LTRIM cap), and a protocol version so
 // clients can reject incompatible servers.
+//
+// Delegates / Muted / Banned / Queue / SkipVotes were added in protocol 1.1
+// and Voice in 1.2; older clients ignore the unknown keys. History carries
+// the durable chat of earlier rooms in the same ChatThread (a re-opened room
+// or a party), oldest first, when there is any.
 type RoomSnapshot struct {
-	Room            Room          `json:"room"`
-	Members         []Member      `json:"members"`
-	Messages        []ChatMessage `json:"messages"`
-	ProtocolVersion string        `json:"protocol_version"` // always ProtocolVersion const
+	Room               Room               `json:"room"`
+	Members            []Member           `json:"members"`
+	Messages           []ChatMessage      `json:"messages"`
+	Delegates          []string           `json:"delegates"`
+	Muted              []string           `json:"muted"`
+	Banned             []string           `json:"banned"`
+	Queue              []QueueItem        `json:"queue"`
+	SkipVotes          int                `json:"skip_votes"`
+	Voice              []VoiceParticipant `json:"voice"`
+	History            []ChatLogEntry     `json:"history,omitempty"`
+	ProtocolVersion    string             `json:"protocol_version"`     // always ProtocolVersion const
+	MinProtocolVersion string             `json:"min_protocol_version"` // always MinProtocolVersion const
 }
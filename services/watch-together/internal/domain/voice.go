@@ -0,0 +1,53 @@
+package domain
+
+// VoiceParticipant is one member in the room's voice channel, stored
+// JSON-encoded in the `wt:room:{roomId}:voice` HASH keyed by UserID. Media
+// flows peer-to-peer (full mesh); the server only tracks who is in the
+// channel and whether they muted themselves, and relays WebRTC signaling
+// between participants. "Speaking" is transient and never stored.
+type VoiceParticipant struct {
+	UserID   string `json:"user_id"`
+	Muted    bool   `json:"muted"`
+	JoinedAt int64  `json:"joined_at"` // unix milliseconds
+}
+
+// VoiceMaxParticipants caps the voice channel. Every participant holds a
+// peer connection to every other, so upload cost grows with the square of
+// this number.
+const VoiceMaxParticipants = 8
+
+// VoiceSignalMaxBytes caps the opaque payload of one voice:signal — a full
+// SDP offer with many codecs stays well under it.
+const VoiceSignalMaxBytes = 16 * 1024
+
+// WebRTC signal kinds carried by voice:signal.
+const (
+	SignalOffer  = "offer"
+	SignalAnswer = "answer"
+	SignalICE    = "ice"
+)
+
+// ValidSignalKind reports whether kind is a relayable signal.
+func ValidSignalKind(kind string) bool {
+	switch kind {
+	case SignalOffer, SignalAnswer, SignalICE:
+		return true
+	}
+	return false
+}
+
+// ICEServer is one RTCIceServer entry handed to the browser.
+type ICEServer struct {
+	URLs       []string `json:"urls"`
+	Username   string   `json:"username,omitempty"`
+	Credential string   `json:"credential,omitempty"`
+}
+
+// ICEConfig is the response of GET /rooms/{id}/ice-servers: STUN servers
+// plus, when a TURN secret is configured, TURN servers with short-lived
+// credentials. ExpiresAt (unix seconds) is when the TURN credential stops
+// working; clients refetch before joining voice after it.
+type ICEConfig struct {
+	ICEServers []ICEServer `json:"ice_servers"`
+	ExpiresAt  int64       `json:"expires_at,omitempty"`
+}
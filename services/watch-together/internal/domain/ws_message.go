@@ -38,6 +38,47 @@ const (
 	MsgPresenceHeartbeat = "presence:heartbeat"
 )
 
+// ----------------------------------------------------------------------------
+// Message type constants — inbound host actions (protocol 1.1). Only the
+// room's current host may send these; anyone else gets ErrCodeNotHost.
+// ----------------------------------------------------------------------------
+const (
+	MsgHostSetControlMode = "host:set_control_mode"
+	MsgHostSetDelegate    = "host:set_delegate"
+	MsgHostSetSlowMode    = "host:set_slow_mode"
+	MsgHostTransfer       = "host:transfer"
+	MsgHostKick           = "host:kick"
+	MsgHostBan            = "host:ban"
+	MsgHostUnban          = "host:unban"
+	MsgHostMute           = "host:mute"
+	MsgHostSetSkipOpEd    = "host:set_skip_op_ed"
+)
+
+// ----------------------------------------------------------------------------
+// Message type constants — inbound episode queue (protocol 1.1). Any member
+// may add and vote; queue:next is gated by the room's ControlMode like the
+// other playback commands.
+// ----------------------------------------------------------------------------
+const (
+	MsgQueueAdd      = "queue:add"
+	MsgQueueRemove   = "queue:remove" // the item's adder or the host
+	MsgQueueVote     = "queue:vote"
+	MsgQueueVoteSkip = "queue:vote_skip"
+	MsgQueueNext     = "queue:next"
+)
+
+// ----------------------------------------------------------------------------
+// Message type constants — inbound voice chat (protocol 1.2). The server
+// relays WebRTC signaling between voice participants; media is peer-to-peer.
+// ----------------------------------------------------------------------------
+const (
+	MsgVoiceJoin     = "voice:join"
+	MsgVoiceLeave    = "voice:leave"
+	MsgVoiceMute     = "voice:mute"
+	MsgVoiceSpeaking = "voice:speaking"
+	MsgVoiceSignal   = "voice:signal" // offer / answer / ICE candidate for one peer
+)
+
 // ----------------------------------------------------------------------------
 // Message type constants — outbound (server → client).
 // `chat:message` and `chat:reaction` use the same wire string in both
@@ -54,6 +95,27 @@ const (
 	MsgChatReactionOut    = "chat:reaction" // shares the wire string with MsgChatReaction by design
 	MsgRoomClosed         = "room:closed"
 	MsgError              = "error"
+
+	// Protocol 1.1 — control mode and moderation. Broadcast to ALL (sender
+	// included) so every roster/permission view converges on the same state.
+	MsgRoomControlChanged = "room:control_changed"
+	MsgRoomHostChanged    = "room:host_changed"
+	MsgMemberRoleChanged  = "member:role_changed"
+	MsgMemberMuted        = "member:muted"
+	MsgMemberKicked       = "member:kicked" // also the final frame on the kicked member's own socket
+	MsgMemberUnbanned     = "member:unbanned"
+
+	// Protocol 1.1 — episode queue. Broadcast to ALL.
+	MsgQueueUpdated        = "queue:updated"
+	MsgRoomEpisodeAdvanced = "room:episode_advanced"
+
+	// Protocol 1.2 — voice chat. voice:state is broadcast to ALL;
+	// voice:speaking to everyone but the speaker; voice:signal only to its
+	// addressee. voice:speaking and voice:signal share the inbound wire
+	// string by design.
+	MsgVoiceState       = "voice:state"
+	MsgVoiceSpeakingOut = "voice:speaking"
+	MsgVoiceSignalOut   = "voice:signal"
 )
 
 // ----------------------------------------------------------------------------
@@ -70,6 +132,22 @@ const (
 	ErrCodeEpisodeUnavailable     = "EPISODE_UNAVAILABLE"
 	ErrCodePlayerUnavailable      = "PLAYER_UNAVAILABLE"      // Phase 4 WT-STATE-02 — sent sender-only when state:change_player references a player with no episodes for the room's anime.
 	ErrCodeTranslationUnavailable = "TRANSLATION_UNAVAILABLE" // Phase 4 WT-STATE-02 — sent sender-only when state:change_translation references a translation that yields no episodes for the room's anime+player.
+
+	// Protocol 1.1 — control mode and moderation.
+	ErrCodeControlNotAllowed = "CONTROL_NOT_ALLOWED" // playback:* / state:change_* rejected by the room's ControlMode
+	ErrCodeNotHost           = "NOT_HOST"            // host:* sent by someone other than the current host
+	ErrCodeMemberNotFound    = "MEMBER_NOT_FOUND"    // host:* target is not in the room
+	ErrCodeMuted             = "MUTED"               // chat:message from a muted member
+	ErrCodeSlowMode          = "SLOW_MODE"           // chat:message inside the slow-mode window; Hint carries the seconds to wait
+	ErrCodeBanned            = "BANNED"              // WS upgrade refused (HTTP 403) for a banned user
+	ErrCodeKicked            = "KICKED"              // close-frame reason after host:kick
+	ErrCodeQueueFull         = "QUEUE_FULL"          // queue:add past QueueMaxItems
+	ErrCodeQueueItemNotFound = "QUEUE_ITEM_NOT_FOUND"
+	ErrCodeQueueEmpty        = "QUEUE_EMPTY" // queue:next / queue:vote_skip with nothing queued
+
+	// Protocol 1.2 — voice chat.
+	ErrCodeVoiceFull  = "VOICE_FULL"   // voice:join past VoiceMaxParticipants
+	ErrCodeNotInVoice = "NOT_IN_VOICE" // voice:* from, or voice:signal to, someone outside the voice channel
 )
 
 // ----------------------------------------------------------------------------
@@ -97,7 +175,18 @@ const (
 // ProtocolVersion is sent on every `room:snapshot` so frontend clients can
 // reject incompatible servers (forward-compat hook; see design doc
 // §WebSocket protocol versioning).
-const ProtocolVersion = "1.0"
+//
+// 1.1 added control modes and host moderation. Every 1.1 change is additive:
+// new message types, new snapshot keys, new error codes. A room left on the
+// default ControlEveryone mode behaves exactly like 1.0, so 1.0 clients keep
+// working — they just cannot send host:* actions.
+//
+// 1.2 adds voice chat signaling (voice:* messages, snapshot `voice`), again
+// purely additive: older clients simply never join the voice channel.
+const ProtocolVersion = "1.2"
+
+// MinProtocolVersion is the oldest client protocol this server still serves.
+const MinProtocolVersion = "1.0"
 
 // ----------------------------------------------------------------------------
 // Inbound payload shapes (client → server). Handlers in plan 01.5 deserialize
@@ -121,8 +210,12 @@ type PlaybackSeekData struct {
 	Time float64 `json:"time"`
 }
 
+// PlaybackTimeTickData.Duration (protocol 1.1, optional) is the client's
+// media duration; it lets the server notice the episode end for queue
+// auto-advance. 1.0 clients omit it and simply never auto-advance.
 type PlaybackTimeTickData struct {
-	Time float64 `json:"time"`
+	Time     float64 `json:"time"`
+	Duration float64 `json:"duration,omitempty"`
 }
 
 // StateChangeEpisodeData / StateChangePlayerData / StateChangeTranslationData
@@ -155,6 +248,87 @@ type ChatReactionInData struct {
 // the sender's last_seen_at on the server side.
 type PresenceHeartbeatData struct{}
 
+// HostSetControlModeData switches Room.ControlMode.
+type HostSetControlModeData struct {
+	Mode string `json:"mode"` // ControlEveryone | ControlHostOnly | ControlHostPlusDelegates
+}
+
+// HostSetDelegateData grants or revokes delegate rights (effective under
+// ControlHostPlusDelegates; the set is kept across mode switches).
+type HostSetDelegateData struct {
+	UserID   string `json:"user_id"`
+	Delegate bool   `json:"delegate"`
+}
+
+// HostSetSlowModeData sets Room.SlowModeSeconds (0 disables).
+type HostSetSlowModeData struct {
+	Seconds int `json:"seconds"`
+}
+
+// HostMuteData mutes or unmutes a member's chat.
+type HostMuteData struct {
+	UserID string `json:"user_id"`
+	Muted  bool   `json:"muted"`
+}
+
+// HostSetSkipOpEdData toggles Room.SkipOpEd.
+type HostSetSkipOpEdData struct {
+	Enabled bool `json:"enabled"`
+}
+
+// QueueAddData enqueues an episode. Empty AnimeID / Player inherit the room's
+// current values; an empty TranslationID inherits the room's only when the
+// player does too.
+type QueueAddData struct {
+	AnimeID       string `json:"anime_id"`
+	EpisodeID     string `json:"episode_id"`
+	Player        string `json:"player"`
+	TranslationID string `json:"translation_id"`
+}
+
+// QueueItemRefData names one queue item (queue:remove).
+type QueueItemRefData struct {
+	ItemID string `json:"item_id"`
+}
+
+// QueueVoteData adds (Up=true) or withdraws the sender's upvote.
+type QueueVoteData struct {
+	ItemID string `json:"item_id"`
+	Up     bool   `json:"up"`
+}
+
+// VoiceJoinData joins the voice channel, optionally muted from the start.
+type VoiceJoinData struct {
+	Muted bool `json:"muted"`
+}
+
+// VoiceMuteData sets the sender's own mute flag.
+type VoiceMuteData struct {
+	Muted bool `json:"muted"`
+}
+
+// VoiceSpeakingData flips the sender's speaking indicator (client-side voice
+// activity detection).
+type VoiceSpeakingData struct {
+	Speaking bool `json:"speaking"`
+}
+
+// VoiceSignalInData addresses one WebRTC signal to one voice participant.
+// Payload is opaque to the server (an RTCSessionDescriptionInit for
+// offer/answer, an RTCIceCandidateInit for ice), capped at
+// VoiceSignalMaxBytes.
+type VoiceSignalInData struct {
+	ToUserID string          `json:"to_user_id"`
+	Kind     string          `json:"kind"` // SignalOffer | SignalAnswer | SignalICE
+	Payload  json.RawMessage `json:"payload"`
+}
+
+// HostTargetData is the payload of host:transfer / host:kick / host:ban /
+// host:unban — just the target user.
+type HostTargetData struct {
+	UserID string `json:"user_id"`
+}
+
 // ----------------------------------------------------------------------------
 // Outbound payload shapes (server → client).
 // ----------------------------------------------------------------------------
@@ -172,11 +346,15 @@ type RoomStateChangedData struct {
 // Kind ∈ {"play", "pause", "seek"}. ServerTS is the authoritative wall-clock
 // (unix ms) at which the server processed the event — clients use it as the
 // drift-detection anchor.
+//
+// Server-initiated seeks (OP/ED skip, protocol 1.1) carry an empty ByUserID
+// and Reason SkipReasonOP / SkipReasonED.
 type PlaybackEventData struct {
 	Kind     string  `json:"kind"`
 	Time     float64 `json:"time"`
 	ByUserID string  `json:"by_user_id"`
 	ServerTS int64   `json:"server_ts"`
+	Reason   string  `json:"reason,omitempty"`
 }
 
 // PlaybackCorrectionData is the per-recipient drift-correction nudge.
@@ -223,6 +401,95 @@ type RoomClosedData struct {
 	Reason string `json:"reason"`
 }
 
+// RoomControlChangedData is broadcast after host:set_control_mode or
+// host:set_slow_mode and always carries both settings.
+type RoomControlChangedData struct {
+	ControlMode     string `json:"control_mode"`
+	SlowModeSeconds int    `json:"slow_mode_seconds"`
+	ByUserID        string `json:"by_user_id"`
+}
+
+// RoomHostChangedData announces a host transfer.
+type RoomHostChangedData struct {
+	HostUserID         string `json:"host_user_id"`
+	PreviousHostUserID string `json:"previous_host_user_id"`
+	ByUserID           string `json:"by_user_id"`
+}
+
+// MemberRoleChangedData announces a delegate grant / revoke.
+type MemberRoleChangedData struct {
+	UserID   string `json:"user_id"`
+	Delegate bool   `json:"delegate"`
+	ByUserID string `json:"by_user_id"`
+}
+
+// MemberMutedData announces a mute / unmute.
+type MemberMutedData struct {
+	UserID   string `json:"user_id"`
+	Muted    bool   `json:"muted"`
+	ByUserID string `json:"by_user_id"`
+}
+
+// MemberKickedData announces a kick (Banned=false) or ban (Banned=true). The
+// target receives it as the last frame before the server closes the socket;
+// the regular member:left follows for everyone else.
+type MemberKickedData struct {
+	UserID   string `json:"user_id"`
+	Banned   bool   `json:"banned"`
+	ByUserID string `json:"by_user_id"`
+}
+
+// MemberUnbannedData announces that a banned user may rejoin.
+type MemberUnbannedData struct {
+	UserID   string `json:"user_id"`
+	ByUserID string `json:"by_user_id"`
+}
+
+// QueueUpdatedData is the full queue in play order, plus the skip-vote
+// tally for the current episode (SkipVotesNeeded is a strict majority of
+// the members in the room).
+type QueueUpdatedData struct {
+	Items           []QueueItem `json:"items"`
+	SkipVotes       int         `json:"skip_votes"`
+	SkipVotesNeeded int         `json:"skip_votes_needed"`
+}
+
+// RoomEpisodeAdvancedData announces that the room moved to the queue head.
+// Carries every field that changed so clients swap source in one step;
+// playback restarts at 0 in StatePlaying with ServerTS as the anchor.
+type RoomEpisodeAdvancedData struct {
+	ItemID        string `json:"item_id"`
+	AnimeID       string `json:"anime_id"`
+	EpisodeID     string `json:"episode_id"`
+	Player        string `json:"player"`
+	TranslationID string `json:"translation_id"`
+	Reason        string `json:"reason"` // AdvanceEnded | AdvanceVoteSkip | AdvanceNext
+	ByUserID      string `json:"by_user_id,omitempty"`
+	ServerTS      int64  `json:"server_ts"`
+}
+
+// VoiceStateData announces a participant's voice state. InVoice=false means
+// they left the channel (explicitly or by disconnecting); peers tear down
+// the matching RTCPeerConnection.
+type VoiceStateData struct {
+	UserID  string `json:"user_id"`
+	InVoice bool   `json:"in_voice"`
+	Muted   bool   `json:"muted"`
+}
+
+// VoiceSpeakingOutData relays a speaking-indicator change.
+type VoiceSpeakingOutData struct {
+	UserID   string `json:"user_id"`
+	Speaking bool   `json:"speaking"`
+}
+
+// VoiceSignalOutData is a relayed WebRTC signal, attributed to its sender.
+type VoiceSignalOutData struct {
+	FromUserID string          `json:"from_user_id"`
+	Kind       string          `json:"kind"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
 // ErrorData is the universal outbound error envelope payload. Code is one
 // of the ErrCode* constants above; Message is human-readable (optional);
 // Hint suggests recovery action (e.g. "reload" on PERSISTENT_DRIFT).
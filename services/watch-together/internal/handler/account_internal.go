@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+// AccountStore is the narrow surface of *repo.RoomRepo the account fan-out
+// uses. Defined here so tests can pass a fake.
+type AccountStore interface {
+	ExportUser(ctx context.Context, userID string) (*domain.AccountExport, error)
+	EraseUser(ctx context.Context, userID string) (*domain.AccountErasure, error)
+}
+
+// PartyAccountStore is the narrow surface of *repo.PartyRepo the account
+// fan-out uses for the Postgres half (scheduled parties and archives).
+type PartyAccountStore interface {
+	ExportUser(ctx context.Context, userID string) (*domain.PartyAccountExport, error)
+	EraseUser(ctx context.Context, userID string) (*domain.PartyAccountErasure, error)
+}
+
+// ChatAccountStore is the narrow surface of *repo.ChatLogRepo the account
+// fan-out uses for the durable chat history.
+type ChatAccountStore interface {
+	ExportUser(ctx context.Context, userID string) (*domain.ChatAccountExport, error)
+	EraseUser(ctx context.Context, userID string) (*domain.ChatAccountErasure, error)
+}
+
+// AccountInternalHandler serves the auth service's account fan-out:
+//
+//	POST /internal/account/export  {"user_id"} → live rooms, memberships, chat, parties, chat history
+//	POST /internal/account/erase   {"user_id"} → scrub them (idempotent)
+//
+// Docker-network only — the gateway never proxies /internal/*. user_id comes
+// from the body here, not from JWT claims: the caller is the auth service,
+// not the user.
+type AccountInternalHandler struct {
+	store   AccountStore
+	parties PartyAccountStore // nil when parties are not wired
+	chat    ChatAccountStore  // nil when the chat history is not wired
+	log     *logger.Logger
+}
+
+// NewAccountInternalHandler wires the store. Pass nil for log to fall back to
+// logger.Default().
+func NewAccountInternalHandler(store AccountStore, log *logger.Logger) *AccountInternalHandler {
+	if log == nil {
+		log = logger.Default()
+	}
+	return &AccountInternalHandler{store: store, log: log}
+}
+
+// WithParties adds the watch-party store to export and erase.
+func (h *AccountInternalHandler) WithParties(p PartyAccountStore) *AccountInternalHandler {
+	h.parties = p
+	return h
+}
+
+// WithChatHistory adds the durable chat history to export and erase.
+func (h *AccountInternalHandler) WithChatHistory(c ChatAccountStore) *AccountInternalHandler {
+	h.chat = c
+	return h
+}
+
+type accountRequest struct {
+	UserID string `json:"user_id"`
+}
+
+// Export handles POST /internal/account/export.
+func (h *AccountInternalHandler) Export(w http.ResponseWriter, r *http.Request) {
+	var req accountRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	if req.UserID == "" {
+		httputil.BadRequest(w, "user_id is required")
+		return
+	}
+	out, err := h.store.ExportUser(r.Context(), req.UserID)
+	if err != nil {
+		h.log.Errorw("account export failed", "user_id", req.UserID, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	if h.parties != nil {
+		if out.Parties, err = h.parties.ExportUser(r.Context(), req.UserID); err != nil {
+			h.log.Errorw("account party export failed", "user_id", req.UserID, "error", err)
+			httputil.Error(w, err)
+			return
+		}
+	}
+	if h.chat != nil {
+		if out.ChatHistory, err = h.chat.ExportUser(r.Context(), req.UserID); err != nil {
+			h.log.Errorw("account chat history export failed", "user_id", req.UserID, "error", err)
+			httputil.Error(w, err)
+			return
+		}
+	}
+	httputil.OK(w, out)
+}
+
+// Erase handles POST /internal/account/erase.
+func (h *AccountInternalHandler) Erase(w http.ResponseWriter, r *http.Request) {
+	var req accountRequest
+	if err := httputil.Bind(r, &req); err != nil {
+		httputil.Error(w, err)
+		return
+	}
+	if req.UserID == "" {
+		httputil.BadRequest(w, "user_id is required")
+		return
+	}
+	erased, err := h.store.EraseUser(r.Context(), req.UserID)
+	if err != nil {
+		h.log.Errorw("account erase failed", "user_id", req.UserID, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	if h.parties != nil {
+		if erased.Parties, err = h.parties.EraseUser(r.Context(), req.UserID); err != nil {
+			h.log.Errorw("account party erase failed", "user_id", req.UserID, "error", err)
+			httputil.Error(w, err)
+			return
+		}
+	}
+	if h.chat != nil {
+		if erased.ChatHistory, err = h.chat.EraseUser(r.Context(), req.UserID); err != nil {
+			h.log.Errorw("account chat history erase failed", "user_id", req.UserID, "error", err)
+			httputil.Error(w, err)
+			return
+		}
+	}
+	httputil.OK(w, map[string]any{"status": "erased", "deleted": erased})
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/service"
+)
+
+// ChatHistoryHandler serves /api/watch-together/history — the durable chat
+// of rooms the caller opened (hosted). Only a thread's owner sees it; any
+// other ID reads as 404.
+//
+//	GET /history                         — the caller's threads, most recent first
+//	GET /history/{id}?before=&limit=     — one page, oldest first; before is RFC 3339
+//	GET /history/{id}/export?format=     — download as json (default) or txt
+type ChatHistoryHandler struct {
+	chat *service.ChatLog
+	log  *logger.Logger
+}
+
+// NewChatHistoryHandler wires the chat log. Pass nil for log to fall back to
+// logger.Default().
+func NewChatHistoryHandler(chat *service.ChatLog, log *logger.Logger) *ChatHistoryHandler {
+	if log == nil {
+		log = logger.Default()
+	}
+	return &ChatHistoryHandler{chat: chat, log: log}
+}
+
+// List handles GET /api/watch-together/history.
+func (h *ChatHistoryHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := authz.UserIDFromContext(r.Context())
+	if userID == "" {
+		httputil.Unauthorized(w)
+		return
+	}
+	threads, err := h.chat.Threads(r.Context(), userID)
+	if err != nil {
+		h.log.Errorw("watch_together list chat history failed", "user_id", userID, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, threads)
+}
+
+// Get handles GET /api/watch-together/history/{id}.
+func (h *ChatHistoryHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := authz.UserIDFromContext(r.Context())
+	if userID == "" {
+		httputil.Unauthorized(w)
+		return
+	}
+	q := r.URL.Query()
+	var before time.Time
+	if v := q.Get("before"); v != "" {
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			httputil.BadRequest(w, "before must be an RFC 3339 timestamp")
+			return
+		}
+		before = t
+	}
+	limit := 0
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			httputil.BadRequest(w, "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+	page, err := h.chat.Page(r.Context(), userID, chi.URLParam(r, "id"), before, limit)
+	if err != nil {
+		h.log.Infow("watch_together get chat history failed", "user_id", userID, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+	httputil.OK(w, page)
+}
+
+// Export handles GET /api/watch-together/history/{id}/export?format=json|txt.
+func (h *ChatHistoryHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID := authz.UserIDFromContext(r.Context())
+	if userID == "" {
+		httputil.Unauthorized(w)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "txt" {
+		httputil.BadRequest(w, "format must be json or txt")
+		return
+	}
+	threadID := chi.URLParam(r, "id")
+	export, err := h.chat.Export(r.Context(), userID, threadID)
+	if err != nil {
+		h.log.Infow("watch_together export chat history failed", "user_id", userID, "error", err)
+		httputil.Error(w, err)
+		return
+	}
+
+	filename := fmt.Sprintf("watch-together-chat-%s.%s", threadID, format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	if format == "txt" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		if err := export.WriteText(w); err != nil {
+			h.log.Warnw("watch_together write chat export", "user_id", userID, "error", err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(export); err != nil {
+		h.log.Warnw("watch_together write chat export", "user_id", userID, "error", err)
+	}
+}
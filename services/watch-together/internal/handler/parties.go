@@ -0,0 +1,202 @@
+package handler
+
+import (
+	stderrors "errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ILITA-hub/animeenigma/libs/authz"
+	"github.com/ILITA-hub/animeenigma/libs/httputil"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/service"
+)
+
+// PartyHandler serves /api/watch-together/parties — scheduling, invites,
+// RSVPs and the post-party archive. Guests (role=guest) are refused on every
+// route: an invite-link guest may join a live room but owns no schedule.
+//
+// The invite link carries ?code=; GET /{id}, POST /{id}/rsvp and
+// GET /{id}/archive accept it as a query param so a user who was not on the
+// host's list can open and answer the invite.
+type PartyHandler struct {
+	svc *service.PartyService
+	log *logger.Logger
+}
+
+// NewPartyHandler wires the service. Pass nil for log to fall back to
+// logger.Default().
+func NewPartyHandler(svc *service.PartyService, log *logger.Logger) *PartyHandler {
+	if log == nil {
+		log = logger.Default()
+	}
+	return &PartyHandler{svc: svc, log: log}
+}
+
+// SchedulePartyBody is the JSON request shape for POST /parties.
+type SchedulePartyBody struct {
+	Title         string    `json:"title"`
+	AnimeID       string    `json:"anime_id"`
+	EpisodeID     string    `json:"episode_id"`
+	Player        string    `json:"player"`
+	TranslationID string    `json:"translation_id"`
+	ControlMode   string    `json:"control_mode,omitempty"`
+	StartsAt      time.Time `json:"starts_at"`
+	Invitees      []string  `json:"invitees,omitempty"`
+}
+
+type inviteBody struct {
+	UserIDs []string `json:"user_ids"`
+}
+
+type rsvpBody struct {
+	RSVP string `json:"rsvp"`
+}
+
+// caller returns the authenticated non-guest user, or writes 401/403.
+func (h *PartyHandler) caller(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := authz.UserIDFromContext(r.Context())
+	if userID == "" {
+		httputil.Unauthorized(w)
+		return "", false
+	}
+	if authz.RoleFromContext(r.Context()) == authz.RoleGuest {
+		httputil.Forbidden(w)
+		return "", false
+	}
+	return userID, true
+}
+
+// fail maps service errors: ErrInvalidInput → 400, typed AppErrors by code.
+func (h *PartyHandler) fail(w http.ResponseWriter, op, userID string, err error) {
+	if stderrors.Is(err, service.ErrInvalidInput) {
+		httputil.BadRequest(w, err.Error())
+		return
+	}
+	h.log.Infow("watch_together party request failed", "action", op, "user_id", userID, "err", err)
+	httputil.Error(w, err)
+}
+
+// Create handles POST /api/watch-together/parties.
+func (h *PartyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+	var body SchedulePartyBody
+	if err := httputil.Bind(r, &body); err != nil {
+		httputil.BadRequest(w, "invalid JSON body")
+		return
+	}
+	view, err := h.svc.Schedule(r.Context(), userID, service.ScheduleInput{
+		Title:         body.Title,
+		AnimeID:       body.AnimeID,
+		EpisodeID:     body.EpisodeID,
+		Player:        body.Player,
+		TranslationID: body.TranslationID,
+		ControlMode:   body.ControlMode,
+		StartsAt:      body.StartsAt,
+		Invitees:      body.Invitees,
+	})
+	if err != nil {
+		h.fail(w, "schedule_party", userID, err)
+		return
+	}
+	httputil.Created(w, view)
+}
+
+// List handles GET /api/watch-together/parties — the caller's upcoming and
+// live parties, hosted or invited.
+func (h *PartyHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+	views, err := h.svc.ListMine(r.Context(), userID)
+	if err != nil {
+		h.fail(w, "list_parties", userID, err)
+		return
+	}
+	httputil.OK(w, views)
+}
+
+// Get handles GET /api/watch-together/parties/{id}[?code=].
+func (h *PartyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+	view, err := h.svc.Get(r.Context(), userID, chi.URLParam(r, "id"), r.URL.Query().Get("code"))
+	if err != nil {
+		h.fail(w, "get_party", userID, err)
+		return
+	}
+	httputil.OK(w, view)
+}
+
+// Invite handles POST /api/watch-together/parties/{id}/invites. Host only.
+func (h *PartyHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+	var body inviteBody
+	if err := httputil.Bind(r, &body); err != nil {
+		httputil.BadRequest(w, "invalid JSON body")
+		return
+	}
+	view, err := h.svc.Invite(r.Context(), userID, chi.URLParam(r, "id"), body.UserIDs)
+	if err != nil {
+		h.fail(w, "invite_party", userID, err)
+		return
+	}
+	httputil.OK(w, view)
+}
+
+// RSVP handles POST /api/watch-together/parties/{id}/rsvp[?code=].
+func (h *PartyHandler) RSVP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+	var body rsvpBody
+	if err := httputil.Bind(r, &body); err != nil {
+		httputil.BadRequest(w, "invalid JSON body")
+		return
+	}
+	view, err := h.svc.RSVP(r.Context(), userID, chi.URLParam(r, "id"), r.URL.Query().Get("code"), body.RSVP)
+	if err != nil {
+		h.fail(w, "rsvp_party", userID, err)
+		return
+	}
+	httputil.OK(w, view)
+}
+
+// Cancel handles DELETE /api/watch-together/parties/{id}. Host only, and
+// only before the party starts.
+func (h *PartyHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+	if err := h.svc.Cancel(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		h.fail(w, "cancel_party", userID, err)
+		return
+	}
+	httputil.NoContent(w)
+}
+
+// Archive handles GET /api/watch-together/parties/{id}/archive[?code=].
+func (h *PartyHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+	archive, err := h.svc.Archive(r.Context(), userID, chi.URLParam(r, "id"), r.URL.Query().Get("code"))
+	if err != nil {
+		h.fail(w, "party_archive", userID, err)
+		return
+	}
+	httputil.OK(w, archive)
+}
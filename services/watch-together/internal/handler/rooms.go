@@ -66,15 +66,19 @@ func NewRoomHandler(svc *service.RoomService, hub RoomDeleteFanout, graceMgr Gra
 	return &RoomHandler{svc: svc, hub: hub, graceMgr: graceMgr, cfg: cfg, log: log}
 }
 
-// CreateRoomBody is the JSON request shape for POST /rooms. All 4 fields
-// are required; missing/blank field → 400 BadRequest with explicit reason.
-// Player must be one of the 5 domain.Player* constants (service layer
-// rejects unknown values).
+// CreateRoomBody is the JSON request shape for POST /rooms. The first 4
+// fields are required; missing/blank field → 400 BadRequest with explicit
+// reason. Player must be one of the 5 domain.Player* constants (service
+// layer rejects unknown values). ControlMode is optional (default
+// domain.ControlEveryone). HistoryID optionally re-opens one of the
+// caller's chat histories (GET /history) so its messages carry over.
 type CreateRoomBody struct {
 	AnimeID       string `json:"anime_id"`
 	EpisodeID     string `json:"episode_id"`
 	Player        string `json:"player"`
 	TranslationID string `json:"translation_id"`
+	ControlMode   string `json:"control_mode,omitempty"`
+	HistoryID     string `json:"history_id,omitempty"`
 }
 
 // CreateRoomResponse is the JSON shape returned by POST /rooms. Kept narrow
@@ -123,6 +127,8 @@ func (h *RoomHandler) Create(w http.ResponseWriter, r *http.Request) {
 		EpisodeID:     body.EpisodeID,
 		Player:        body.Player,
 		TranslationID: body.TranslationID,
+		ControlMode:   body.ControlMode,
+		HistoryID:     body.HistoryID,
 	})
 	if err != nil {
 		if stderrors.Is(err, service.ErrInvalidInput) {
@@ -202,6 +208,38 @@ func (h *RoomHandler) Get(w http.ResponseWriter, r *http.Request) {
 	httputil.OK(w, snap)
 }
 
+// ICEServers handles GET /api/watch-together/rooms/{id}/ice-servers — the
+// STUN / TURN list a member feeds into RTCPeerConnection before voice:join.
+// 410 when the room is gone, 403 when the caller is not connected to it.
+func (h *RoomHandler) ICEServers(w http.ResponseWriter, r *http.Request) {
+	userID := authz.UserIDFromContext(r.Context())
+	if userID == "" {
+		httputil.Unauthorized(w)
+		return
+	}
+
+	roomID := chi.URLParam(r, "id")
+	cfg, err := h.svc.ICEServers(r.Context(), roomID, userID)
+	if err != nil {
+		switch {
+		case stderrors.Is(err, service.ErrNotFound):
+			writeGone(w, "room expired or does not exist")
+		case stderrors.Is(err, service.ErrInvalidInput):
+			httputil.BadRequest(w, err.Error())
+		default:
+			h.log.Infow("watch_together ice_servers failed",
+				"action", "ice_servers",
+				"room_id", roomID,
+				"user_id", userID,
+				"error", err,
+			)
+			httputil.Error(w, err)
+		}
+		return
+	}
+	httputil.OK(w, cfg)
+}
+
 // Delete handles DELETE /api/watch-together/rooms/{id}. Only the host
 // (room.host_user_id == requester user_id) can force-close (WT-FOUND-03).
 // 204 on success, 403 ErrNotHost, 410 ErrNotFound.
@@ -3,7 +3,8 @@
 // Lifecycle (per 01.5-PLAN.md §<lifecycle_contract>):
 //
 //  1. Pre-upgrade JWT validation from ?token=... → 401 on failure.
-//  2. Pre-upgrade room presence check via repo.Exists → 404 on miss.
+//  2. Pre-upgrade room presence check via repo.Exists → 404 on miss, then
+//     the protocol-1.1 ban check via repo.IsBanned → 403 BANNED.
 //  3. Origin-header allowlist (production) or open (dev via cfg.AllowAllOrigins).
 //  4. websocket.Upgrader.Upgrade.
 //  5. Post-upgrade capacity check via hub.MemberCount → CAPACITY_FULL close frame.
@@ -34,6 +35,7 @@ import (
 	"github.com/gorilla/websocket"
 
 	"github.com/ILITA-hub/animeenigma/libs/authz"
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
 	"github.com/ILITA-hub/animeenigma/libs/httputil"
 	"github.com/ILITA-hub/animeenigma/libs/logger"
 	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/config"
@@ -180,6 +182,26 @@ func (h *WebSocketHandler) Upgrade(w http.ResponseWriter, r *http.Request) {
 		httputil.NotFound(w, "ROOM_NOT_FOUND")
 		return
 	}
+	// A banned user (host:ban) is refused before the upgrade, same
+	// rationale as the 404 above. Code BANNED matches the ErrCode vocabulary.
+	banned, err := h.repo.IsBanned(ctx, roomID, userID)
+	if err != nil {
+		h.log.Errorw("watch_together ws ban check failed",
+			"room_id", roomID,
+			"user_id", userID,
+			"err", err,
+		)
+		httputil.Error(w, err)
+		return
+	}
+	if banned {
+		h.log.Infow("watch_together ws upgrade rejected: banned",
+			"room_id", roomID,
+			"user_id", userID,
+		)
+		httputil.Error(w, apperrors.Forbidden(domain.ErrCodeBanned))
+		return
+	}
 
 	// Step 3: Upgrade. Origin allowlist enforced inside the Upgrader's
 	// CheckOrigin hook — built once in NewWebSocketHandler.
@@ -344,6 +344,25 @@ func TestWS_NonExistentRoom_Returns404(t *testing.T) {
 	}
 }
 
+// ----------------------------------------------------------------------------
+// Test 4b: a user banned via host:ban → HTTP 403 before the upgrade.
+// ----------------------------------------------------------------------------
+
+func TestWS_BannedUser_Returns403(t *testing.T) {
+	fx := newWSFixture(t, 0)
+	roomID := fx.createRoom("host", "Host")
+	if err := fx.repo.Ban(context.Background(), roomID, "troll"); err != nil {
+		t.Fatalf("Ban: %v", err)
+	}
+	_, resp, err := fx.dial(fx.mintToken("troll", "Troll"), roomID)
+	if err == nil {
+		t.Fatal("expected dial to fail with 403, but it succeeded")
+	}
+	if resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403", responseStatus(resp))
+	}
+}
+
 // ----------------------------------------------------------------------------
 // Test 5: valid token + existing room → 101 Switching Protocols + room:snapshot.
 // ----------------------------------------------------------------------------
@@ -75,6 +75,11 @@ type Connection struct {
 	// sendCh is the per-connection outbound buffer. writePump drains it.
 	sendCh chan []byte
 
+	// evictCh carries the single final frame of a server-initiated
+	// disconnect (host:kick / host:ban). writePump writes it, sends a close
+	// control frame and exits; see Evict.
+	evictCh chan evictFrame
+
 	// hub is set by Hub.Register so the readPump can call hub.Unregister(c)
 	// on read failure without the caller needing to manage the cleanup path.
 	hub *Hub
@@ -123,6 +128,7 @@ func newConnection(roomID, userID, username string, conn wsConn, log *logger.Log
 		RoomID:   roomID,
 		conn:     conn,
 		sendCh:   make(chan []byte, sendBufferSize),
+		evictCh:  make(chan evictFrame, 1),
 		log:      log,
 		closed:   make(chan struct{}),
 	}
@@ -157,6 +163,32 @@ func (c *Connection) Send(payload []byte) bool {
 	}
 }
 
+// evictFrame is one queued server-initiated disconnect.
+type evictFrame struct {
+	payload []byte
+	reason  string
+}
+
+// Evict asks writePump to flush payload as the last text frame, follow it
+// with a close control frame carrying reason, and exit. The peer's next read
+// then fails, so the readPump runs the normal Unregister → OnClose path
+// (member:left, RemoveMember). Unlike Send followed by Close, the final frame
+// is never abandoned in sendCh. Returns false when the connection is already
+// closed or another eviction is pending; the caller should then Close.
+func (c *Connection) Evict(payload []byte, reason string) bool {
+	select {
+	case <-c.closed:
+		return false
+	default:
+	}
+	select {
+	case c.evictCh <- evictFrame{payload: payload, reason: reason}:
+		return true
+	default:
+		return false
+	}
+}
+
 // Close marks the connection as closed (idempotent) and closes the
 // underlying websocket. The sendCh channel is intentionally NOT closed —
 // closing it would race with concurrent Connection.Send writes from a
@@ -274,6 +306,15 @@ func (c *Connection) writePump(ctx context.Context) {
 				)
 				return
 			}
+		case ev := <-c.evictCh:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			_ = c.conn.WriteMessage(websocket.TextMessage, ev.payload)
+			_ = c.conn.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.ClosePolicyViolation, ev.reason),
+				time.Now().Add(writeWait),
+			)
+			return
 		case <-ticker.C:
 			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if err := c.conn.WriteControl(
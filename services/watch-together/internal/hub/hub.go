@@ -328,6 +328,44 @@ func (h *Hub) SendTo(ctx context.Context, roomID, userID string, env domain.Enve
 	return delivered, nil
 }
 
+// Disconnect evicts every connection userID holds in roomID: env is written
+// as the final frame, then the socket is closed with reason as the close
+// frame text (see Connection.Evict). The regular Unregister → OnClose path
+// runs once the readPump notices, so member:left and RemoveMember happen
+// exactly as for a voluntary leave. Returns the number of connections
+// evicted.
+func (h *Hub) Disconnect(ctx context.Context, roomID, userID string, env domain.Envelope, reason string) (int, error) {
+	if roomID == "" || userID == "" {
+		return 0, apperrors.InvalidInput("hub.Disconnect: room_id and user_id are required")
+	}
+
+	payload, err := json.Marshal(env)
+	if err != nil {
+		return 0, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: marshal envelope failed")
+	}
+
+	h.mu.RLock()
+	set := h.rooms[roomID]
+	targets := make([]*Connection, 0, len(set))
+	for c := range set {
+		if c.UserID == userID {
+			targets = append(targets, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, c := range targets {
+		if c.Evict(payload, reason) {
+			MessagesSentTotal.WithLabelValues(env.Type).Inc()
+			continue
+		}
+		// Already closing or an eviction is pending — go straight to the
+		// teardown path so the user is out either way.
+		h.Unregister(c)
+	}
+	return len(targets), nil
+}
+
 // MemberUserIDs returns the deduplicated set of user IDs currently connected
 // to roomID. O(N) on the connection count; called from 01.5 to populate
 // RoomSnapshot.Members. Iteration order is undefined (Go map semantics).
@@ -365,6 +365,49 @@ func TestSendTo_MultiTabUser(t *testing.T) {
 	waitForPayload(t, fc2, 1, time.Second)
 }
 
+// ----------------------------------------------------------------------------
+// Behavior 8c — Disconnect writes the final frame to every tab of the target,
+// closes them, and runs the OnClose teardown; other members stay connected.
+// ----------------------------------------------------------------------------
+
+func TestDisconnect_EvictsTargetOnly(t *testing.T) {
+	h, _, _ := newTestHub(t)
+	c1, fc1 := registerFake(t, h, "room-A", "alice", "Alice")
+	c2, fc2 := registerFake(t, h, "room-A", "alice", "Alice")
+	_, fcB := registerFake(t, h, "room-A", "bob", "Bob")
+
+	var closes atomic.Int32
+	c1.OnClose = func(*Connection) { closes.Add(1) }
+	c2.OnClose = func(*Connection) { closes.Add(1) }
+
+	env := sampleEnvelope(t, domain.MsgMemberKicked, map[string]any{"user_id": "alice"})
+	n, err := h.Disconnect(context.Background(), "room-A", "alice", env, domain.ErrCodeKicked)
+	if err != nil {
+		t.Fatalf("Disconnect: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("evicted = %d, want 2", n)
+	}
+	for _, fc := range []*fakeConn{fc1, fc2} {
+		got := waitForPayload(t, fc, 1, time.Second)
+		var out domain.Envelope
+		if err := json.Unmarshal(got[0], &out); err != nil || out.Type != domain.MsgMemberKicked {
+			t.Fatalf("final frame = %s (err %v), want member:kicked", got[0], err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for h.MemberCount("room-A") != 1 || closes.Load() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("MemberCount = %d, OnClose calls = %d; want 1 and 2", h.MemberCount("room-A"), closes.Load())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := fcB.payloads(); len(got) != 0 {
+		t.Fatalf("bob received %d payloads, want 0", len(got))
+	}
+}
+
 // ----------------------------------------------------------------------------
 // Behavior 9 — Connection.Send returns false when sendCh is full;
 // dropped-message metric increments.
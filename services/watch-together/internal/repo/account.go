@@ -0,0 +1,147 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+// roomScanBatch is the SCAN COUNT hint used when walking every live room.
+const roomScanBatch = 200
+
+// roomIDs walks every live wt:room:{id} HASH. Members / messages keys share
+// the prefix, so only keys without a suffix after the ID are kept.
+func (r *RoomRepo) roomIDs(ctx context.Context) ([]string, error) {
+	prefix := KeyRoom("")
+	var ids []string
+	iter := r.client.Scan(ctx, 0, prefix+"*", roomScanBatch).Iterator()
+	for iter.Next(ctx) {
+		id := strings.TrimPrefix(iter.Val(), prefix)
+		if id == "" || strings.Contains(id, ":") {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: scan rooms failed")
+	}
+	return ids, nil
+}
+
+// ExportUser collects every live room the user hosts, every membership and
+// every chat message they sent. Read-only — no TTL refresh.
+func (r *RoomRepo) ExportUser(ctx context.Context, userID string) (*domain.AccountExport, error) {
+	ids, err := r.roomIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := &domain.AccountExport{
+		HostedRooms: []domain.Room{},
+		Memberships: []domain.AccountMembership{},
+		Messages:    []domain.AccountMessage{},
+	}
+	for _, id := range ids {
+		room, err := r.GetRoom(ctx, id)
+		if stderrors.Is(err, ErrNotFound) {
+			continue // expired between SCAN and HGETALL
+		}
+		if err != nil {
+			return nil, err
+		}
+		if room.HostUserID == userID {
+			out.HostedRooms = append(out.HostedRooms, *room)
+		}
+
+		raw, err := r.client.HGet(ctx, KeyRoomMembers(id), userID).Result()
+		if err != nil && err != redis.Nil {
+			return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: export member failed")
+		}
+		if err == nil {
+			var meta domain.MemberMeta
+			if json.Unmarshal([]byte(raw), &meta) == nil {
+				out.Memberships = append(out.Memberships, domain.AccountMembership{RoomID: id, Meta: meta})
+			}
+		}
+
+		msgs, err := r.GetMessages(ctx, id, chatListMaxIndex+1)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range msgs {
+			if m.UserID == userID {
+				out.Messages = append(out.Messages, domain.AccountMessage{RoomID: id, ChatMessage: m})
+			}
+		}
+	}
+	return out, nil
+}
+
+// EraseUser removes the user from every live room: the membership entry,
+// every chat message they sent (LREM by exact stored payload), the
+// delegate / muted / banned / skip-vote SETs, the attendance and voice
+// entries and the host_user_id pointer. Rooms
+// themselves survive — the other members are mid-episode, and a host-less
+// room falls back to ControlEveryone. Idempotent; no TTL refresh, an erasure
+// is not room activity.
+func (r *RoomRepo) EraseUser(ctx context.Context, userID string) (*domain.AccountErasure, error) {
+	ids, err := r.roomIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := &domain.AccountErasure{}
+	for _, id := range ids {
+		removed, err := r.client.HDel(ctx, KeyRoomMembers(id), userID).Result()
+		if err != nil {
+			return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: erase member failed")
+		}
+		out.Memberships += int(removed)
+
+		if _, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SRem(ctx, KeyRoomDelegates(id), userID)
+			pipe.SRem(ctx, KeyRoomMuted(id), userID)
+			pipe.SRem(ctx, KeyRoomBans(id), userID)
+			pipe.SRem(ctx, KeyRoomSkipVotes(id), userID)
+			pipe.HDel(ctx, KeyRoomAttendance(id), userID)
+			pipe.HDel(ctx, KeyRoomVoice(id), userID)
+			return nil
+		}); err != nil {
+			return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: erase moderation failed")
+		}
+
+		raw, err := r.client.LRange(ctx, KeyRoomMessages(id), 0, -1).Result()
+		if err != nil {
+			return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: erase messages failed")
+		}
+		for _, entry := range raw {
+			var m domain.ChatMessage
+			if json.Unmarshal([]byte(entry), &m) != nil || m.UserID != userID {
+				continue
+			}
+			n, err := r.client.LRem(ctx, KeyRoomMessages(id), 0, entry).Result()
+			if err != nil {
+				return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: erase messages failed")
+			}
+			out.Messages += int(n)
+		}
+
+		host, err := r.client.HGet(ctx, KeyRoom(id), "host_user_id").Result()
+		if err != nil && err != redis.Nil {
+			return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: erase host failed")
+		}
+		if host == userID {
+			if err := r.client.HSet(ctx, KeyRoom(id), "host_user_id", "").Err(); err != nil {
+				return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: erase host failed")
+			}
+			out.HostCleared++
+		}
+	}
+	r.log.Infow("watch_together repo op", "op", "erase_user", "user_id", userID,
+		"memberships", out.Memberships, "messages", out.Messages, "host_cleared", out.HostCleared)
+	return out, nil
+}
@@ -0,0 +1,86 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+func TestExportAndEraseUser(t *testing.T) {
+	r, _ := newRepo(t)
+	ctx := context.Background()
+
+	hosted := sampleRoom("room-a")
+	hosted.HostUserID = "user-1"
+	other := sampleRoom("room-b")
+	for _, room := range []*domain.Room{hosted, other} {
+		if err := r.CreateRoom(ctx, room); err != nil {
+			t.Fatalf("CreateRoom: %v", err)
+		}
+	}
+	alice := domain.MemberMeta{Username: "alice", JoinedAt: 1700000100}
+	bob := domain.MemberMeta{Username: "bob", JoinedAt: 1700000110}
+	for _, id := range []string{"room-a", "room-b"} {
+		if err := r.AddMember(ctx, id, "user-1", alice); err != nil {
+			t.Fatalf("AddMember: %v", err)
+		}
+		if err := r.AddMember(ctx, id, "user-2", bob); err != nil {
+			t.Fatalf("AddMember: %v", err)
+		}
+	}
+	msgs := []domain.ChatMessage{
+		{ID: "m1", UserID: "user-1", Username: "alice", Body: "hi", TS: 1},
+		{ID: "m2", UserID: "user-2", Username: "bob", Body: "hey", TS: 2},
+		{ID: "m3", UserID: "user-1", Username: "alice", Body: "op skip?", TS: 3},
+	}
+	for _, m := range msgs {
+		if err := r.AppendMessage(ctx, "room-b", m); err != nil {
+			t.Fatalf("AppendMessage: %v", err)
+		}
+	}
+
+	exp, err := r.ExportUser(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("ExportUser: %v", err)
+	}
+	if len(exp.HostedRooms) != 1 || exp.HostedRooms[0].ID != "room-a" {
+		t.Errorf("HostedRooms = %+v; want [room-a]", exp.HostedRooms)
+	}
+	if len(exp.Memberships) != 2 {
+		t.Errorf("Memberships len = %d; want 2", len(exp.Memberships))
+	}
+	if len(exp.Messages) != 2 || exp.Messages[0].ID != "m1" || exp.Messages[1].ID != "m3" {
+		t.Errorf("Messages = %+v; want m1, m3", exp.Messages)
+	}
+
+	erased, err := r.EraseUser(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("EraseUser: %v", err)
+	}
+	want := domain.AccountErasure{Memberships: 2, Messages: 2, HostCleared: 1}
+	if *erased != want {
+		t.Errorf("EraseUser = %+v; want %+v", *erased, want)
+	}
+
+	members, _ := r.ListMembers(ctx, "room-b")
+	if len(members) != 1 || members[0].UserID != "user-2" {
+		t.Errorf("members after erase = %+v; want only user-2", members)
+	}
+	left, _ := r.GetMessages(ctx, "room-b", 100)
+	if len(left) != 1 || left[0].ID != "m2" {
+		t.Errorf("messages after erase = %+v; want only m2", left)
+	}
+	room, _ := r.GetRoom(ctx, "room-a")
+	if room.HostUserID != "" {
+		t.Errorf("host after erase = %q; want empty", room.HostUserID)
+	}
+
+	again, err := r.EraseUser(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("EraseUser (repeat): %v", err)
+	}
+	if *again != (domain.AccountErasure{}) {
+		t.Errorf("repeat EraseUser = %+v; want zero", *again)
+	}
+}
@@ -0,0 +1,161 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+// ChatLogRepo persists the durable chat history: threads and the messages /
+// reactions written behind from live rooms. Entries are insert-only and keyed
+// by message ID, so a retried batch never duplicates a row.
+type ChatLogRepo struct{ db *gorm.DB }
+
+// NewChatLogRepo wires the repo.
+func NewChatLogRepo(db *gorm.DB) *ChatLogRepo {
+	return &ChatLogRepo{db: db}
+}
+
+// chatEntryBatch bounds one INSERT of write-behind entries.
+const chatEntryBatch = 100
+
+// OpenThread creates the thread, or points an existing one at its newest
+// room and bumps LastActiveAt. Owner, anime and party of an existing thread
+// never change.
+func (r *ChatLogRepo) OpenThread(ctx context.Context, t *domain.ChatThread) error {
+	err := r.db.WithContext(ctx).Exec(`
+		INSERT INTO watch_chat_threads (id, owner_user_id, anime_id, party_id, last_room_id, created_at, last_active_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET last_room_id = excluded.last_room_id, last_active_at = excluded.last_active_at`,
+		t.ID, t.OwnerUserID, t.AnimeID, t.PartyID, t.LastRoomID, t.CreatedAt, t.LastActiveAt).Error
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: open chat thread failed")
+	}
+	return nil
+}
+
+// GetThread returns one thread or a NotFound error.
+func (r *ChatLogRepo) GetThread(ctx context.Context, id string) (*domain.ChatThread, error) {
+	var t domain.ChatThread
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&t).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, apperrors.NotFound("chat history")
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: get chat thread failed")
+	}
+	return &t, nil
+}
+
+// ListThreads returns the threads ownerID owns, most recently active first.
+func (r *ChatLogRepo) ListThreads(ctx context.Context, ownerID string, limit int) ([]domain.ChatThread, error) {
+	out := []domain.ChatThread{}
+	err := r.db.WithContext(ctx).Where("owner_user_id = ?", ownerID).
+		Order("last_active_at DESC").Limit(limit).Find(&out).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: list chat threads failed")
+	}
+	return out, nil
+}
+
+// InsertEntries writes a batch of entries; IDs already stored are skipped.
+func (r *ChatLogRepo) InsertEntries(ctx context.Context, entries []domain.ChatLogEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(&entries, chatEntryBatch).Error
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: insert chat log failed")
+	}
+	return nil
+}
+
+// ListEntries returns up to limit of a thread's entries sent strictly before
+// `before` (zero = newest), oldest first.
+func (r *ChatLogRepo) ListEntries(ctx context.Context, threadID string, before time.Time, limit int) ([]domain.ChatLogEntry, error) {
+	q := r.db.WithContext(ctx).Where("thread_id = ?", threadID)
+	if !before.IsZero() {
+		q = q.Where("sent_at < ?", before)
+	}
+	var out []domain.ChatLogEntry
+	if err := q.Order("sent_at DESC, id DESC").Limit(limit).Find(&out).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: list chat log failed")
+	}
+	reverseEntries(out)
+	return out, nil
+}
+
+// PriorMessages returns the latest limit chat messages (no reactions) of a
+// thread that were sent in rooms other than roomID, oldest first — the
+// history a re-opened room shows above its live chat.
+func (r *ChatLogRepo) PriorMessages(ctx context.Context, threadID, roomID string, limit int) ([]domain.ChatLogEntry, error) {
+	var out []domain.ChatLogEntry
+	err := r.db.WithContext(ctx).
+		Where("thread_id = ? AND room_id <> ? AND kind = ?", threadID, roomID, domain.ChatKindMessage).
+		Order("sent_at DESC, id DESC").Limit(limit).Find(&out).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: list prior chat failed")
+	}
+	reverseEntries(out)
+	return out, nil
+}
+
+// AllEntries returns a whole thread oldest first, for export.
+func (r *ChatLogRepo) AllEntries(ctx context.Context, threadID string) ([]domain.ChatLogEntry, error) {
+	out := []domain.ChatLogEntry{}
+	err := r.db.WithContext(ctx).Where("thread_id = ?", threadID).
+		Order("sent_at ASC, id ASC").Find(&out).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: export chat log failed")
+	}
+	return out, nil
+}
+
+func reverseEntries(e []domain.ChatLogEntry) {
+	for i, j := 0, len(e)-1; i < j; i, j = i+1, j-1 {
+		e[i], e[j] = e[j], e[i]
+	}
+}
+
+// ExportUser collects the threads userID owns and every entry they sent.
+func (r *ChatLogRepo) ExportUser(ctx context.Context, userID string) (*domain.ChatAccountExport, error) {
+	out := &domain.ChatAccountExport{Threads: []domain.ChatThread{}, Entries: []domain.ChatLogEntry{}}
+	db := r.db.WithContext(ctx)
+	if err := db.Where("owner_user_id = ?", userID).Order("created_at ASC").Find(&out.Threads).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: export chat threads failed")
+	}
+	if err := db.Where("user_id = ?", userID).Order("sent_at ASC").Find(&out.Entries).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: export chat log failed")
+	}
+	return out, nil
+}
+
+// EraseUser deletes the threads userID owns (with all their entries) and
+// every entry userID sent elsewhere. Idempotent.
+func (r *ChatLogRepo) EraseUser(ctx context.Context, userID string) (*domain.ChatAccountErasure, error) {
+	out := &domain.ChatAccountErasure{}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		owned := tx.Model(&domain.ChatThread{}).Select("id").Where("owner_user_id = ?", userID)
+		res := tx.Where("thread_id IN (?) OR user_id = ?", owned, userID).Delete(&domain.ChatLogEntry{})
+		if res.Error != nil {
+			return res.Error
+		}
+		out.Entries = int(res.RowsAffected)
+		res = tx.Where("owner_user_id = ?", userID).Delete(&domain.ChatThread{})
+		if res.Error != nil {
+			return res.Error
+		}
+		out.Threads = int(res.RowsAffected)
+		return nil
+	})
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: erase chat log failed")
+	}
+	return out, nil
+}
@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+func newChatLogRepo(t *testing.T) *ChatLogRepo {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.ChatThread{}, &domain.ChatLogEntry{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return NewChatLogRepo(db)
+}
+
+func TestChatLogRepo_PagingPriorAndErase(t *testing.T) {
+	r := newChatLogRepo(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 2, 20, 0, 0, 0, time.UTC)
+	thread := &domain.ChatThread{ID: "room-1", OwnerUserID: hostID, LastRoomID: "room-1", CreatedAt: now, LastActiveAt: now}
+	if err := r.OpenThread(ctx, thread); err != nil {
+		t.Fatalf("OpenThread: %v", err)
+	}
+	// Re-opening keeps the owner and moves LastRoomID.
+	reopen := *thread
+	reopen.OwnerUserID, reopen.LastRoomID, reopen.LastActiveAt = guestID, "room-2", now.Add(time.Hour)
+	if err := r.OpenThread(ctx, &reopen); err != nil {
+		t.Fatalf("re-OpenThread: %v", err)
+	}
+	if got, _ := r.GetThread(ctx, "room-1"); got.OwnerUserID != hostID || got.LastRoomID != "room-2" {
+		t.Fatalf("thread after reopen = %+v", got)
+	}
+
+	var entries []domain.ChatLogEntry
+	for i := 0; i < 5; i++ {
+		roomID, user := "room-1", hostID
+		if i >= 3 {
+			roomID, user = "room-2", guestID
+		}
+		entries = append(entries, domain.ChatLogEntry{
+			ID: fmt.Sprintf("m%d", i), ThreadID: "room-1", RoomID: roomID, Kind: domain.ChatKindMessage,
+			UserID: user, Body: fmt.Sprintf("msg %d", i), SentAt: now.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	if err := r.InsertEntries(ctx, entries); err != nil {
+		t.Fatalf("InsertEntries: %v", err)
+	}
+	if err := r.InsertEntries(ctx, entries[:2]); err != nil {
+		t.Fatalf("replayed InsertEntries must be a no-op: %v", err)
+	}
+
+	page, err := r.ListEntries(ctx, "room-1", time.Time{}, 2)
+	if err != nil || len(page) != 2 || page[0].ID != "m3" || page[1].ID != "m4" {
+		t.Fatalf("newest page = %+v, %v", page, err)
+	}
+	older, _ := r.ListEntries(ctx, "room-1", page[0].SentAt, 10)
+	if len(older) != 3 || older[0].ID != "m0" {
+		t.Fatalf("older page = %+v", older)
+	}
+	prior, _ := r.PriorMessages(ctx, "room-1", "room-2", 10)
+	if len(prior) != 3 {
+		t.Fatalf("prior messages = %+v, want the 3 from room-1", prior)
+	}
+
+	erased, err := r.EraseUser(ctx, guestID)
+	if err != nil || erased.Entries != 2 || erased.Threads != 0 {
+		t.Fatalf("EraseUser(guest) = %+v, %v", erased, err)
+	}
+	erased, err = r.EraseUser(ctx, hostID)
+	if err != nil || erased.Entries != 3 || erased.Threads != 1 {
+		t.Fatalf("EraseUser(host) = %+v, %v", erased, err)
+	}
+}
@@ -25,6 +25,47 @@ func KeyRoomMembers(roomID string) string { return fmt.Sprintf("wt:room:%s:membe
 // capped at 100 entries via LPUSH + LTRIM 0 99 (newest at head).
 func KeyRoomMessages(roomID string) string { return fmt.Sprintf("wt:room:%s:messages", roomID) }
 
+// KeyRoomDelegates returns the Redis key for the per-room delegates SET
+// (user IDs allowed to drive playback under ControlHostPlusDelegates).
+func KeyRoomDelegates(roomID string) string { return fmt.Sprintf("wt:room:%s:delegates", roomID) }
+
+// KeyRoomMuted returns the Redis key for the per-room muted SET (user IDs
+// whose chat:message is rejected).
+func KeyRoomMuted(roomID string) string { return fmt.Sprintf("wt:room:%s:muted", roomID) }
+
+// KeyRoomBans returns the Redis key for the per-room bans SET (user IDs
+// refused at WS upgrade).
+func KeyRoomBans(roomID string) string { return fmt.Sprintf("wt:room:%s:bans", roomID) }
+
+// KeyRoomQueue returns the Redis key for the per-room episode queue HASH
+// (item_id → QueueItem JSON).
+func KeyRoomQueue(roomID string) string { return fmt.Sprintf("wt:room:%s:queue", roomID) }
+
+// KeyRoomSkipVotes returns the Redis key for the SET of user IDs voting to
+// skip the current episode. Cleared on every advance.
+func KeyRoomSkipVotes(roomID string) string { return fmt.Sprintf("wt:room:%s:skip_votes", roomID) }
+
+// KeyRoomAutoLock returns the Redis key of the short SET NX lock that makes a
+// server-initiated transition (auto-advance, OP/ED skip) fire once even when
+// every member's time_tick notices it. Expires on its own.
+func KeyRoomAutoLock(roomID string) string { return fmt.Sprintf("wt:room:%s:auto_lock", roomID) }
+
+// KeyRoomAttendance returns the Redis key for the HASH of everyone who ever
+// joined the room (user_id → Attendee JSON). Unlike the members HASH it is
+// never trimmed on leave; watch parties archive it at teardown.
+func KeyRoomAttendance(roomID string) string { return fmt.Sprintf("wt:room:%s:attendance", roomID) }
+
+// KeyRoomVoice returns the Redis key for the HASH of the room's voice-channel
+// participants (user_id → VoiceParticipant JSON).
+func KeyRoomVoice(roomID string) string { return fmt.Sprintf("wt:room:%s:voice", roomID) }
+
+// KeyRoomChatSlot returns the Redis key of one member's slow-mode window
+// (SET NX with the slow-mode TTL; its presence means "wait"). Expires on its
+// own, so it is not part of the room's sliding-TTL key set.
+func KeyRoomChatSlot(roomID, userID string) string {
+	return fmt.Sprintf("wt:room:%s:slow:%s", roomID, userID)
+}
+
 // KeyRoomEvents returns the Redis PUBSUB channel used for forward-compat
 // multi-instance fanout. Wired in Phase 1 but no-op for single-instance v1.0.
 func KeyRoomEvents(roomID string) string { return fmt.Sprintf("wt:room:%s:events", roomID) }
@@ -41,13 +41,36 @@ func TestKeyRoomEvents(t *testing.T) {
 	}
 }
 
+func TestKeyRoomModerationSets(t *testing.T) {
+	t.Parallel()
+	cases := map[string]string{
+		KeyRoomDelegates("abc"):      "wt:room:abc:delegates",
+		KeyRoomMuted("abc"):          "wt:room:abc:muted",
+		KeyRoomBans("abc"):           "wt:room:abc:bans",
+		KeyRoomChatSlot("abc", "u1"): "wt:room:abc:slow:u1",
+		KeyRoomQueue("abc"):          "wt:room:abc:queue",
+		KeyRoomSkipVotes("abc"):      "wt:room:abc:skip_votes",
+		KeyRoomAutoLock("abc"):       "wt:room:abc:auto_lock",
+		KeyRoomAttendance("abc"):     "wt:room:abc:attendance",
+		KeyRoomVoice("abc"):          "wt:room:abc:voice",
+	}
+	for got, want := range cases {
+		if got != want {
+			t.Errorf("got %q; want %q", got, want)
+		}
+	}
+}
+
 func TestKeyAllStartWithPrefix(t *testing.T) {
 	t.Parallel()
 	cases := map[string]string{
-		"KeyRoom":         KeyRoom("xyz"),
-		"KeyRoomMembers":  KeyRoomMembers("xyz"),
-		"KeyRoomMessages": KeyRoomMessages("xyz"),
-		"KeyRoomEvents":   KeyRoomEvents("xyz"),
+		"KeyRoom":          KeyRoom("xyz"),
+		"KeyRoomMembers":   KeyRoomMembers("xyz"),
+		"KeyRoomMessages":  KeyRoomMessages("xyz"),
+		"KeyRoomEvents":    KeyRoomEvents("xyz"),
+		"KeyRoomDelegates": KeyRoomDelegates("xyz"),
+		"KeyRoomMuted":     KeyRoomMuted("xyz"),
+		"KeyRoomBans":      KeyRoomBans("xyz"),
 	}
 	for name, key := range cases {
 		if !strings.HasPrefix(key, KeyPrefix) {
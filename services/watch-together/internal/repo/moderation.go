@@ -0,0 +1,175 @@
+package repo
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+// Protocol-1.1 moderation state: delegates, muted and banned user IDs live in
+// three SETs next to the room HASH (see keys.go) and share its sliding TTL.
+// Every write goes through setMembership so the TTL refresh cannot be
+// forgotten.
+
+// setMembership SADDs (on=true) or SREMs userID in key and refreshes the
+// sliding TTL on every room key. Returns whether the SET changed.
+func (r *RoomRepo) setMembership(ctx context.Context, op, roomID, key, userID string, on bool) (bool, error) {
+	if roomID == "" || userID == "" {
+		return false, apperrors.InvalidInput("room_id and user_id are required")
+	}
+	var cmd *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if on {
+			cmd = pipe.SAdd(ctx, key, userID)
+		} else {
+			cmd = pipe.SRem(ctx, key, userID)
+		}
+		r.expireAll(pipe, ctx, roomID)
+		return nil
+	})
+	if err != nil {
+		return false, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: "+op+" failed")
+	}
+	r.log.Infow("watch_together repo op", "op", op, "room_id", roomID, "user_id", userID, "on", on)
+	return cmd.Val() > 0, nil
+}
+
+// isMember runs SISMEMBER. Read-only — no TTL refresh.
+func (r *RoomRepo) isMember(ctx context.Context, op, key, userID string) (bool, error) {
+	ok, err := r.client.SIsMember(ctx, key, userID).Result()
+	if err != nil {
+		return false, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: "+op+" failed")
+	}
+	return ok, nil
+}
+
+// SetDelegate grants (on=true) or revokes delegate rights.
+func (r *RoomRepo) SetDelegate(ctx context.Context, roomID, userID string, on bool) (bool, error) {
+	return r.setMembership(ctx, "set_delegate", roomID, KeyRoomDelegates(roomID), userID, on)
+}
+
+// IsDelegate reports whether userID is a delegate of the room.
+func (r *RoomRepo) IsDelegate(ctx context.Context, roomID, userID string) (bool, error) {
+	return r.isMember(ctx, "is_delegate", KeyRoomDelegates(roomID), userID)
+}
+
+// SetMuted mutes (on=true) or unmutes userID's chat.
+func (r *RoomRepo) SetMuted(ctx context.Context, roomID, userID string, on bool) (bool, error) {
+	return r.setMembership(ctx, "set_muted", roomID, KeyRoomMuted(roomID), userID, on)
+}
+
+// IsMuted reports whether userID is muted in the room.
+func (r *RoomRepo) IsMuted(ctx context.Context, roomID, userID string) (bool, error) {
+	return r.isMember(ctx, "is_muted", KeyRoomMuted(roomID), userID)
+}
+
+// Ban adds userID to the bans SET and drops any delegate grant in the same
+// transaction. The caller disconnects the user's live sockets.
+func (r *RoomRepo) Ban(ctx context.Context, roomID, userID string) error {
+	if roomID == "" || userID == "" {
+		return apperrors.InvalidInput("room_id and user_id are required")
+	}
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, KeyRoomBans(roomID), userID)
+		pipe.SRem(ctx, KeyRoomDelegates(roomID), userID)
+		r.expireAll(pipe, ctx, roomID)
+		return nil
+	})
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: ban failed")
+	}
+	r.log.Infow("watch_together repo op", "op", "ban", "room_id", roomID, "user_id", userID)
+	return nil
+}
+
+// Unban removes userID from the bans SET. Returns whether they were banned.
+func (r *RoomRepo) Unban(ctx context.Context, roomID, userID string) (bool, error) {
+	return r.setMembership(ctx, "unban", roomID, KeyRoomBans(roomID), userID, false)
+}
+
+// IsBanned reports whether userID is banned from the room. Checked by the WS
+// upgrade handler before the socket is accepted.
+func (r *RoomRepo) IsBanned(ctx context.Context, roomID, userID string) (bool, error) {
+	return r.isMember(ctx, "is_banned", KeyRoomBans(roomID), userID)
+}
+
+// IsRoomMember reports whether userID has an entry in the members HASH.
+func (r *RoomRepo) IsRoomMember(ctx context.Context, roomID, userID string) (bool, error) {
+	ok, err := r.client.HExists(ctx, KeyRoomMembers(roomID), userID).Result()
+	if err != nil {
+		return false, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: member check failed")
+	}
+	return ok, nil
+}
+
+// TransferHost points host_user_id at userID and drops their delegate grant
+// (the host needs none) in one transaction.
+func (r *RoomRepo) TransferHost(ctx context.Context, roomID, userID string) error {
+	if roomID == "" || userID == "" {
+		return apperrors.InvalidInput("room_id and user_id are required")
+	}
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, KeyRoom(roomID), "host_user_id", userID)
+		pipe.SRem(ctx, KeyRoomDelegates(roomID), userID)
+		r.expireAll(pipe, ctx, roomID)
+		return nil
+	})
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: transfer host failed")
+	}
+	r.log.Infow("watch_together repo op", "op", "transfer_host", "room_id", roomID, "host_user_id", userID)
+	return nil
+}
+
+// GetModeration reads all three moderation SETs, each sorted for stable
+// snapshots. Read-only — no TTL refresh.
+func (r *RoomRepo) GetModeration(ctx context.Context, roomID string) (*domain.RoomModeration, error) {
+	var delegates, muted, banned *redis.StringSliceCmd
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		delegates = pipe.SMembers(ctx, KeyRoomDelegates(roomID))
+		muted = pipe.SMembers(ctx, KeyRoomMuted(roomID))
+		banned = pipe.SMembers(ctx, KeyRoomBans(roomID))
+		return nil
+	})
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: get moderation failed")
+	}
+	sorted := func(cmd *redis.StringSliceCmd) []string {
+		out := append([]string{}, cmd.Val()...)
+		sort.Strings(out)
+		return out
+	}
+	return &domain.RoomModeration{
+		Delegates: sorted(delegates),
+		Muted:     sorted(muted),
+		Banned:    sorted(banned),
+	}, nil
+}
+
+// TakeChatSlot claims userID's slow-mode window of the given length. Returns
+// ok=false plus the remaining wait when a previous message still holds it.
+// Backed by SET NX EX, so the window survives reconnects and holds across
+// instances.
+func (r *RoomRepo) TakeChatSlot(ctx context.Context, roomID, userID string, window time.Duration) (bool, time.Duration, error) {
+	key := KeyRoomChatSlot(roomID, userID)
+	ok, err := r.client.SetNX(ctx, key, 1, window).Result()
+	if err != nil {
+		return false, 0, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: chat slot failed")
+	}
+	if ok {
+		return true, 0, nil
+	}
+	wait, err := r.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return false, 0, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: chat slot ttl failed")
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return false, wait, nil
+}
@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+func TestModeration_SetsRoundTripWithTTL(t *testing.T) {
+	r, mr := newRepo(t)
+	ctx := context.Background()
+	room := sampleRoom("mod-room")
+	room.ControlMode = domain.ControlHostPlusDelegates
+	room.SlowModeSeconds = 10
+	if err := r.CreateRoom(ctx, room); err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+
+	got, err := r.GetRoom(ctx, "mod-room")
+	if err != nil {
+		t.Fatalf("GetRoom: %v", err)
+	}
+	if got.ControlMode != domain.ControlHostPlusDelegates || got.SlowModeSeconds != 10 {
+		t.Fatalf("control fields = %q/%d, want host_plus_delegates/10", got.ControlMode, got.SlowModeSeconds)
+	}
+
+	if _, err := r.SetDelegate(ctx, "mod-room", "d1", true); err != nil {
+		t.Fatalf("SetDelegate: %v", err)
+	}
+	if _, err := r.SetMuted(ctx, "mod-room", "m1", true); err != nil {
+		t.Fatalf("SetMuted: %v", err)
+	}
+	// Banning a delegate drops the grant.
+	if _, err := r.SetDelegate(ctx, "mod-room", "b1", true); err != nil {
+		t.Fatalf("SetDelegate: %v", err)
+	}
+	if err := r.Ban(ctx, "mod-room", "b1"); err != nil {
+		t.Fatalf("Ban: %v", err)
+	}
+
+	mod, err := r.GetModeration(ctx, "mod-room")
+	if err != nil {
+		t.Fatalf("GetModeration: %v", err)
+	}
+	want := &domain.RoomModeration{Delegates: []string{"d1"}, Muted: []string{"m1"}, Banned: []string{"b1"}}
+	if !reflect.DeepEqual(mod, want) {
+		t.Fatalf("moderation = %+v, want %+v", mod, want)
+	}
+	for _, k := range []string{KeyRoomDelegates("mod-room"), KeyRoomMuted("mod-room"), KeyRoomBans("mod-room")} {
+		if ttl := mr.TTL(k); ttl != testTTL {
+			t.Errorf("TTL(%s) = %v, want %v", k, ttl, testTTL)
+		}
+	}
+
+	if changed, err := r.Unban(ctx, "mod-room", "b1"); err != nil || !changed {
+		t.Fatalf("Unban = %v, %v; want true, nil", changed, err)
+	}
+	if banned, _ := r.IsBanned(ctx, "mod-room", "b1"); banned {
+		t.Fatal("b1 should be unbanned")
+	}
+
+	if err := r.DeleteRoom(ctx, "mod-room"); err != nil {
+		t.Fatalf("DeleteRoom: %v", err)
+	}
+	for _, k := range []string{KeyRoomDelegates("mod-room"), KeyRoomMuted("mod-room")} {
+		if mr.Exists(k) {
+			t.Errorf("DeleteRoom: key %s should be gone", k)
+		}
+	}
+}
+
+func TestTakeChatSlot_HoldsWindow(t *testing.T) {
+	r, mr := newRepo(t)
+	ctx := context.Background()
+
+	ok, _, err := r.TakeChatSlot(ctx, "slow-room", "u1", 10*time.Second)
+	if err != nil || !ok {
+		t.Fatalf("first slot = %v, %v; want true, nil", ok, err)
+	}
+	ok, wait, err := r.TakeChatSlot(ctx, "slow-room", "u1", 10*time.Second)
+	if err != nil || ok || wait <= 0 {
+		t.Fatalf("second slot = %v, %v, %v; want false with a positive wait", ok, wait, err)
+	}
+
+	mr.FastForward(11 * time.Second)
+	if ok, _, _ := r.TakeChatSlot(ctx, "slow-room", "u1", 10*time.Second); !ok {
+		t.Fatal("slot should be free after the window")
+	}
+}
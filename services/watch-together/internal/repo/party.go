@@ -0,0 +1,331 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+// PartyRepo persists scheduled watch parties, their invites and the
+// post-party archive in Postgres. Rooms stay in Redis; a party only points at
+// its room by RoomID while live. Status transitions are conditional UPDATEs
+// so the scheduler can run on every instance and each transition still
+// happens once. Plain SQL where it matters so it runs on Postgres (prod) and
+// SQLite (repo tests).
+type PartyRepo struct{ db *gorm.DB }
+
+// NewPartyRepo wires the repo.
+func NewPartyRepo(db *gorm.DB) *PartyRepo {
+	return &PartyRepo{db: db}
+}
+
+// partyNotFound is the typed 404 for a missing party.
+func partyNotFound() error { return apperrors.NotFound("watch party") }
+
+// CreateParty inserts the party and its initial invite list in one
+// transaction.
+func (r *PartyRepo) CreateParty(ctx context.Context, p *domain.WatchParty, invites []domain.WatchPartyInvite) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(p).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: create party failed")
+		}
+		if len(invites) == 0 {
+			return nil
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&invites).Error; err != nil {
+			return apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: create invites failed")
+		}
+		return nil
+	})
+}
+
+// GetParty returns one party or a NotFound error.
+func (r *PartyRepo) GetParty(ctx context.Context, id string) (*domain.WatchParty, error) {
+	var p domain.WatchParty
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&p).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, partyNotFound()
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: get party failed")
+	}
+	return &p, nil
+}
+
+// ListInvites returns a party's invites, oldest first.
+func (r *PartyRepo) ListInvites(ctx context.Context, partyID string) ([]domain.WatchPartyInvite, error) {
+	var out []domain.WatchPartyInvite
+	err := r.db.WithContext(ctx).Where("party_id = ?", partyID).
+		Order("invited_at ASC, user_id ASC").Find(&out).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: list invites failed")
+	}
+	return out, nil
+}
+
+// AddInvites invites userIDs with a pending RSVP. Already-invited users keep
+// their answer. Returns the users that were newly invited.
+func (r *PartyRepo) AddInvites(ctx context.Context, partyID string, userIDs []string, now time.Time) ([]string, error) {
+	var added []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, uid := range userIDs {
+			res := tx.Exec(`
+				INSERT INTO watch_party_invites (party_id, user_id, rsvp, invited_at)
+				VALUES (?, ?, ?, ?)
+				ON CONFLICT (party_id, user_id) DO NOTHING`,
+				partyID, uid, domain.RSVPPending, now)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected > 0 {
+				added = append(added, uid)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: add invites failed")
+	}
+	return added, nil
+}
+
+// SetRSVP records userID's answer, inviting them first if they came through
+// the invite link.
+func (r *PartyRepo) SetRSVP(ctx context.Context, partyID, userID, rsvp string, now time.Time) error {
+	err := r.db.WithContext(ctx).Exec(`
+		INSERT INTO watch_party_invites (party_id, user_id, rsvp, invited_at, responded_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (party_id, user_id) DO UPDATE SET
+			rsvp = excluded.rsvp,
+			responded_at = excluded.responded_at`,
+		partyID, userID, rsvp, now, now).Error
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: set rsvp failed")
+	}
+	return nil
+}
+
+// ListForUser returns the scheduled and live parties userID hosts or is
+// invited to, soonest first.
+func (r *PartyRepo) ListForUser(ctx context.Context, userID string) ([]domain.WatchParty, error) {
+	var out []domain.WatchParty
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT * FROM watch_parties
+		WHERE status IN (?, ?)
+			AND (host_user_id = ? OR id IN (SELECT party_id FROM watch_party_invites WHERE user_id = ?))
+		ORDER BY starts_at ASC, id ASC`,
+		domain.PartyScheduled, domain.PartyLive, userID, userID).Scan(&out).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: list parties failed")
+	}
+	return out, nil
+}
+
+// CancelParty moves a scheduled party to cancelled. Returns false when the
+// party is no longer scheduled.
+func (r *PartyRepo) CancelParty(ctx context.Context, id string, now time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Exec(`
+		UPDATE watch_parties SET status = ?, ended_at = ?, updated_at = ?
+		WHERE id = ? AND status = ?`,
+		domain.PartyCancelled, now, now, id, domain.PartyScheduled)
+	if res.Error != nil {
+		return false, apperrors.Wrap(res.Error, apperrors.CodeInternal, "watch-together: cancel party failed")
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// DueReminders returns scheduled parties starting at or before `before` whose
+// reminder has not gone out yet.
+func (r *PartyRepo) DueReminders(ctx context.Context, before time.Time) ([]domain.WatchParty, error) {
+	var out []domain.WatchParty
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND reminder_sent IS NULL AND starts_at <= ?", domain.PartyScheduled, before).
+		Order("starts_at ASC").Find(&out).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: due reminders failed")
+	}
+	return out, nil
+}
+
+// MarkReminded claims a party's reminder. Returns false when another tick or
+// instance already claimed it.
+func (r *PartyRepo) MarkReminded(ctx context.Context, id string, now time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Exec(`
+		UPDATE watch_parties SET reminder_sent = ?, updated_at = ?
+		WHERE id = ? AND reminder_sent IS NULL`,
+		now, now, id)
+	if res.Error != nil {
+		return false, apperrors.Wrap(res.Error, apperrors.CodeInternal, "watch-together: mark reminded failed")
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// DueStarts returns scheduled parties whose start time has passed.
+func (r *PartyRepo) DueStarts(ctx context.Context, now time.Time) ([]domain.WatchParty, error) {
+	var out []domain.WatchParty
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND starts_at <= ?", domain.PartyScheduled, now).
+		Order("starts_at ASC").Find(&out).Error
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: due starts failed")
+	}
+	return out, nil
+}
+
+// ClaimStart moves a scheduled party to live before its room exists, so only
+// one instance materializes it. Returns false when someone else won.
+func (r *PartyRepo) ClaimStart(ctx context.Context, id string, now time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Exec(`
+		UPDATE watch_parties SET status = ?, started_at = ?, updated_at = ?
+		WHERE id = ? AND status = ?`,
+		domain.PartyLive, now, now, id, domain.PartyScheduled)
+	if res.Error != nil {
+		return false, apperrors.Wrap(res.Error, apperrors.CodeInternal, "watch-together: claim start failed")
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// SetRoom records the room a live party was materialized into.
+func (r *PartyRepo) SetRoom(ctx context.Context, id, roomID string) error {
+	err := r.db.WithContext(ctx).Exec(`
+		UPDATE watch_parties SET room_id = ?, updated_at = ? WHERE id = ?`,
+		roomID, time.Now(), id).Error
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: set party room failed")
+	}
+	return nil
+}
+
+// ListLive returns every live party. The scheduler uses it to end parties
+// whose room expired without a teardown hook firing.
+func (r *PartyRepo) ListLive(ctx context.Context) ([]domain.WatchParty, error) {
+	var out []domain.WatchParty
+	if err := r.db.WithContext(ctx).Where("status = ?", domain.PartyLive).Find(&out).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: list live parties failed")
+	}
+	return out, nil
+}
+
+// EndParty writes the archive and moves the party to ended in one
+// transaction. Archive rows are insert-or-ignore so a retried teardown is
+// harmless. Returns false when the party was not live.
+func (r *PartyRepo) EndParty(ctx context.Context, id string, now time.Time, attendance []domain.WatchPartyAttendance, messages []domain.WatchPartyMessage) (bool, error) {
+	ended := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Exec(`
+			UPDATE watch_parties SET status = ?, ended_at = ?, updated_at = ?
+			WHERE id = ? AND status = ?`,
+			domain.PartyEnded, now, now, id, domain.PartyLive)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		ended = true
+		if len(attendance) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&attendance).Error; err != nil {
+				return err
+			}
+		}
+		if len(messages) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&messages, 200).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: end party failed")
+	}
+	return ended, nil
+}
+
+// GetArchive returns a party's attendance (by first join) and chat (by send
+// time).
+func (r *PartyRepo) GetArchive(ctx context.Context, partyID string) (*domain.PartyArchive, error) {
+	out := &domain.PartyArchive{
+		PartyID:    partyID,
+		Attendance: []domain.WatchPartyAttendance{},
+		Messages:   []domain.WatchPartyMessage{},
+	}
+	db := r.db.WithContext(ctx)
+	if err := db.Where("party_id = ?", partyID).Order("first_joined ASC, user_id ASC").Find(&out.Attendance).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: get attendance failed")
+	}
+	if err := db.Where("party_id = ?", partyID).Order("sent_at ASC, message_id ASC").Find(&out.Messages).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: get party messages failed")
+	}
+	return out, nil
+}
+
+// ExportUser returns the parties userID hosts and their invites, attendance
+// and archived messages.
+func (r *PartyRepo) ExportUser(ctx context.Context, userID string) (*domain.PartyAccountExport, error) {
+	out := &domain.PartyAccountExport{
+		HostedParties: []domain.WatchParty{},
+		Invites:       []domain.WatchPartyInvite{},
+		Attendance:    []domain.WatchPartyAttendance{},
+		Messages:      []domain.WatchPartyMessage{},
+	}
+	db := r.db.WithContext(ctx)
+	if err := db.Where("host_user_id = ?", userID).Order("starts_at ASC").Find(&out.HostedParties).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: export parties failed")
+	}
+	if err := db.Where("user_id = ?", userID).Find(&out.Invites).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: export invites failed")
+	}
+	if err := db.Where("user_id = ?", userID).Find(&out.Attendance).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: export attendance failed")
+	}
+	if err := db.Where("user_id = ?", userID).Order("sent_at ASC").Find(&out.Messages).Error; err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: export party messages failed")
+	}
+	return out, nil
+}
+
+// EraseUser deletes the parties userID hosts (with everything hanging off
+// them) and scrubs userID from every other party's invites, attendance and
+// archive. Idempotent.
+func (r *PartyRepo) EraseUser(ctx context.Context, userID string) (*domain.PartyAccountErasure, error) {
+	out := &domain.PartyAccountErasure{}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		hosted := tx.Model(&domain.WatchParty{}).Select("id").Where("host_user_id = ?", userID)
+		for _, table := range []string{"watch_party_invites", "watch_party_attendance", "watch_party_messages"} {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE party_id IN (?)", hosted).Error; err != nil {
+				return err
+			}
+		}
+		res := tx.Where("host_user_id = ?", userID).Delete(&domain.WatchParty{})
+		if res.Error != nil {
+			return res.Error
+		}
+		out.Parties = int(res.RowsAffected)
+
+		res = tx.Where("user_id = ?", userID).Delete(&domain.WatchPartyInvite{})
+		if res.Error != nil {
+			return res.Error
+		}
+		out.Invites = int(res.RowsAffected)
+		res = tx.Where("user_id = ?", userID).Delete(&domain.WatchPartyAttendance{})
+		if res.Error != nil {
+			return res.Error
+		}
+		out.Attendance = int(res.RowsAffected)
+		res = tx.Where("user_id = ?", userID).Delete(&domain.WatchPartyMessage{})
+		if res.Error != nil {
+			return res.Error
+		}
+		out.Messages = int(res.RowsAffected)
+		return nil
+	})
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: erase party data failed")
+	}
+	return out, nil
+}
@@ -0,0 +1,139 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+const (
+	hostID  = "11111111-1111-1111-1111-111111111111"
+	guestID = "22222222-2222-2222-2222-222222222222"
+)
+
+func newPartyRepo(t *testing.T) *PartyRepo {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.WatchParty{}, &domain.WatchPartyInvite{},
+		&domain.WatchPartyAttendance{}, &domain.WatchPartyMessage{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return NewPartyRepo(db)
+}
+
+func sampleParty(id string, startsAt time.Time) *domain.WatchParty {
+	return &domain.WatchParty{
+		ID: id, HostUserID: hostID, Title: "Friday night", AnimeID: "anime-1", EpisodeID: "1",
+		Player: "kodik", TranslationID: "610", StartsAt: startsAt, InviteCode: "code-" + id,
+		Status: domain.PartyScheduled,
+	}
+}
+
+func TestPartyRepo_LifecycleTransitionsOnce(t *testing.T) {
+	r := newPartyRepo(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC)
+	p := sampleParty("aaaaaaaa-0000-0000-0000-000000000001", now.Add(10*time.Minute))
+	if err := r.CreateParty(ctx, p, []domain.WatchPartyInvite{{PartyID: p.ID, UserID: guestID, RSVP: domain.RSVPPending, InvitedAt: now}}); err != nil {
+		t.Fatalf("CreateParty: %v", err)
+	}
+
+	due, err := r.DueReminders(ctx, now.Add(15*time.Minute))
+	if err != nil || len(due) != 1 {
+		t.Fatalf("DueReminders = %v, %v; want 1 party", due, err)
+	}
+	if ok, _ := r.MarkReminded(ctx, p.ID, now); !ok {
+		t.Fatal("first MarkReminded should claim")
+	}
+	if ok, _ := r.MarkReminded(ctx, p.ID, now); ok {
+		t.Fatal("second MarkReminded must not claim again")
+	}
+	if due, _ := r.DueReminders(ctx, now.Add(15*time.Minute)); len(due) != 0 {
+		t.Fatalf("reminder still due after claim: %v", due)
+	}
+
+	if due, _ := r.DueStarts(ctx, now); len(due) != 0 {
+		t.Fatalf("party due before StartsAt: %v", due)
+	}
+	start := now.Add(10 * time.Minute)
+	if ok, _ := r.ClaimStart(ctx, p.ID, start); !ok {
+		t.Fatal("ClaimStart should win")
+	}
+	if ok, _ := r.ClaimStart(ctx, p.ID, start); ok {
+		t.Fatal("second ClaimStart must lose")
+	}
+	if ok, _ := r.CancelParty(ctx, p.ID, start); ok {
+		t.Fatal("a live party cannot be cancelled")
+	}
+	if err := r.SetRoom(ctx, p.ID, "room-1"); err != nil {
+		t.Fatalf("SetRoom: %v", err)
+	}
+
+	attendance := []domain.WatchPartyAttendance{{PartyID: p.ID, UserID: guestID, Username: "bob", FirstJoined: start}}
+	msgs := []domain.WatchPartyMessage{{PartyID: p.ID, MessageID: "m1", UserID: guestID, Username: "bob", Body: "hi", SentAt: start}}
+	if ended, err := r.EndParty(ctx, p.ID, start.Add(time.Hour), attendance, msgs); err != nil || !ended {
+		t.Fatalf("EndParty = %v, %v", ended, err)
+	}
+	if ended, _ := r.EndParty(ctx, p.ID, start.Add(time.Hour), attendance, msgs); ended {
+		t.Fatal("EndParty must only end a live party once")
+	}
+	got, err := r.GetParty(ctx, p.ID)
+	if err != nil || got.Status != domain.PartyEnded || got.RoomID != "room-1" {
+		t.Fatalf("GetParty = %+v, %v", got, err)
+	}
+	archive, err := r.GetArchive(ctx, p.ID)
+	if err != nil || len(archive.Attendance) != 1 || len(archive.Messages) != 1 {
+		t.Fatalf("GetArchive = %+v, %v", archive, err)
+	}
+}
+
+func TestPartyRepo_InvitesRSVPAndErase(t *testing.T) {
+	r := newPartyRepo(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	p := sampleParty("aaaaaaaa-0000-0000-0000-000000000002", now.Add(time.Hour))
+	if err := r.CreateParty(ctx, p, nil); err != nil {
+		t.Fatalf("CreateParty: %v", err)
+	}
+
+	added, err := r.AddInvites(ctx, p.ID, []string{guestID}, now)
+	if err != nil || len(added) != 1 {
+		t.Fatalf("AddInvites = %v, %v", added, err)
+	}
+	if err := r.SetRSVP(ctx, p.ID, guestID, domain.RSVPGoing, now); err != nil {
+		t.Fatalf("SetRSVP: %v", err)
+	}
+	// Re-inviting keeps the answer.
+	if added, _ := r.AddInvites(ctx, p.ID, []string{guestID}, now); len(added) != 0 {
+		t.Fatalf("re-invite reported new invitees: %v", added)
+	}
+	invites, _ := r.ListInvites(ctx, p.ID)
+	if len(invites) != 1 || invites[0].RSVP != domain.RSVPGoing {
+		t.Fatalf("invites = %+v", invites)
+	}
+	for _, uid := range []string{hostID, guestID} {
+		if list, err := r.ListForUser(ctx, uid); err != nil || len(list) != 1 {
+			t.Fatalf("ListForUser(%s) = %v, %v", uid, list, err)
+		}
+	}
+
+	erased, err := r.EraseUser(ctx, hostID)
+	if err != nil || erased.Parties != 1 {
+		t.Fatalf("EraseUser = %+v, %v", erased, err)
+	}
+	if invites, _ := r.ListInvites(ctx, p.ID); len(invites) != 0 {
+		t.Fatalf("invites of an erased host's party survived: %+v", invites)
+	}
+	if _, err := r.GetParty(ctx, p.ID); err == nil {
+		t.Fatal("party of an erased host should be gone")
+	}
+}
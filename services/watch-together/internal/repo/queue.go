@@ -0,0 +1,266 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+// Episode queue: items live JSON-encoded in the wt:room:{id}:queue HASH keyed
+// by item ID, skip votes in the wt:room:{id}:skip_votes SET. Both share the
+// room's sliding TTL. Read-modify-write paths (add with cap, vote) run under
+// WATCH so concurrent members on different instances cannot lose updates.
+
+// queueTxRetries bounds the optimistic-lock retries of a WATCH transaction.
+const queueTxRetries = 5
+
+// watchTx runs fn under WATCH on key, retrying on redis.TxFailedErr.
+func (r *RoomRepo) watchTx(ctx context.Context, op, key string, fn func(tx *redis.Tx) error) error {
+	var err error
+	for i := 0; i < queueTxRetries; i++ {
+		err = r.client.Watch(ctx, fn, key)
+		if !errors.Is(err, redis.TxFailedErr) {
+			break
+		}
+	}
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: "+op+" failed")
+	}
+	return nil
+}
+
+// AddQueueItem appends item to the room's queue. Returns ok=false without
+// writing when the queue already holds domain.QueueMaxItems entries.
+func (r *RoomRepo) AddQueueItem(ctx context.Context, roomID string, item domain.QueueItem) (bool, error) {
+	if roomID == "" || item.ID == "" {
+		return false, apperrors.InvalidInput("room_id and item id are required")
+	}
+	if item.Votes == nil {
+		item.Votes = []string{}
+	}
+	raw, err := json.Marshal(item)
+	if err != nil {
+		return false, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: marshal queue item")
+	}
+	key := KeyRoomQueue(roomID)
+	added := false
+	err = r.watchTx(ctx, "queue_add", key, func(tx *redis.Tx) error {
+		n, err := tx.HLen(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if n >= domain.QueueMaxItems {
+			added = false
+			return nil
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, item.ID, raw)
+			r.expireAll(pipe, ctx, roomID)
+			return nil
+		})
+		added = err == nil
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	if added {
+		r.log.Infow("watch_together repo op", "op", "queue_add", "room_id", roomID, "item_id", item.ID)
+	}
+	return added, nil
+}
+
+// GetQueueItem returns one queue item, or nil when itemID is not queued.
+// Read-only — no TTL refresh.
+func (r *RoomRepo) GetQueueItem(ctx context.Context, roomID, itemID string) (*domain.QueueItem, error) {
+	raw, err := r.client.HGet(ctx, KeyRoomQueue(roomID), itemID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: get queue item failed")
+	}
+	var item domain.QueueItem
+	if err := json.Unmarshal([]byte(raw), &item); err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: decode queue item")
+	}
+	return &item, nil
+}
+
+// GetQueue returns the room's queue in play order: most votes first, then
+// oldest AddedAt, then ID for a stable tie-break. Corrupt entries are logged
+// and skipped. Read-only — no TTL refresh.
+func (r *RoomRepo) GetQueue(ctx context.Context, roomID string) ([]domain.QueueItem, error) {
+	raw, err := r.client.HGetAll(ctx, KeyRoomQueue(roomID)).Result()
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: get queue failed")
+	}
+	items := make([]domain.QueueItem, 0, len(raw))
+	for id, v := range raw {
+		var item domain.QueueItem
+		if err := json.Unmarshal([]byte(v), &item); err != nil {
+			r.log.Warnw("watch_together: skip corrupt queue item", "room_id", roomID, "item_id", id, "err", err)
+			continue
+		}
+		if item.Votes == nil {
+			item.Votes = []string{}
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if len(a.Votes) != len(b.Votes) {
+			return len(a.Votes) > len(b.Votes)
+		}
+		if a.AddedAt != b.AddedAt {
+			return a.AddedAt < b.AddedAt
+		}
+		return a.ID < b.ID
+	})
+	return items, nil
+}
+
+// RemoveQueueItem HDELs itemID. Returns whether it was queued.
+func (r *RoomRepo) RemoveQueueItem(ctx context.Context, roomID, itemID string) (bool, error) {
+	var cmd *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		cmd = pipe.HDel(ctx, KeyRoomQueue(roomID), itemID)
+		r.expireAll(pipe, ctx, roomID)
+		return nil
+	})
+	if err != nil {
+		return false, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: queue remove failed")
+	}
+	r.log.Infow("watch_together repo op", "op", "queue_remove", "room_id", roomID, "item_id", itemID)
+	return cmd.Val() > 0, nil
+}
+
+// VoteQueueItem adds (up=true) or withdraws userID's upvote on itemID.
+// Voting twice is idempotent. Returns found=false when itemID is not queued.
+func (r *RoomRepo) VoteQueueItem(ctx context.Context, roomID, itemID, userID string, up bool) (bool, error) {
+	if roomID == "" || itemID == "" || userID == "" {
+		return false, apperrors.InvalidInput("room_id, item_id and user_id are required")
+	}
+	key := KeyRoomQueue(roomID)
+	found := false
+	err := r.watchTx(ctx, "queue_vote", key, func(tx *redis.Tx) error {
+		raw, err := tx.HGet(ctx, key, itemID).Result()
+		if errors.Is(err, redis.Nil) {
+			found = false
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		found = true
+		var item domain.QueueItem
+		if err := json.Unmarshal([]byte(raw), &item); err != nil {
+			return err
+		}
+		votes := make([]string, 0, len(item.Votes)+1)
+		for _, v := range item.Votes {
+			if v != userID {
+				votes = append(votes, v)
+			}
+		}
+		if up {
+			votes = append(votes, userID)
+		}
+		item.Votes = votes
+		updated, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, itemID, updated)
+			r.expireAll(pipe, ctx, roomID)
+			return nil
+		})
+		return err
+	})
+	return found, err
+}
+
+// AddSkipVote records userID's vote to skip the current episode and returns
+// the number of distinct voters.
+func (r *RoomRepo) AddSkipVote(ctx context.Context, roomID, userID string) (int, error) {
+	if roomID == "" || userID == "" {
+		return 0, apperrors.InvalidInput("room_id and user_id are required")
+	}
+	var count *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, KeyRoomSkipVotes(roomID), userID)
+		count = pipe.SCard(ctx, KeyRoomSkipVotes(roomID))
+		r.expireAll(pipe, ctx, roomID)
+		return nil
+	})
+	if err != nil {
+		return 0, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: skip vote failed")
+	}
+	return int(count.Val()), nil
+}
+
+// ClearSkipVotes drops every skip vote. Called when the episode changes
+// outside the queue (state:change_episode).
+func (r *RoomRepo) ClearSkipVotes(ctx context.Context, roomID string) error {
+	if err := r.client.Del(ctx, KeyRoomSkipVotes(roomID)).Err(); err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: clear skip votes failed")
+	}
+	return nil
+}
+
+// SkipVoteCount returns the number of skip votes on the current episode.
+// Read-only — no TTL refresh.
+func (r *RoomRepo) SkipVoteCount(ctx context.Context, roomID string) (int, error) {
+	n, err := r.client.SCard(ctx, KeyRoomSkipVotes(roomID)).Result()
+	if err != nil {
+		return 0, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: skip vote count failed")
+	}
+	return int(n), nil
+}
+
+// AdvanceTo makes item the room's current episode in one transaction: the
+// item leaves the queue, skip votes reset, and playback restarts at 0 in the
+// playing state anchored at nowMs.
+func (r *RoomRepo) AdvanceTo(ctx context.Context, roomID string, item domain.QueueItem, nowMs int64) error {
+	if roomID == "" || item.ID == "" {
+		return apperrors.InvalidInput("room_id and item id are required")
+	}
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, KeyRoomQueue(roomID), item.ID)
+		pipe.Del(ctx, KeyRoomSkipVotes(roomID))
+		pipe.HSet(ctx, KeyRoom(roomID), map[string]interface{}{
+			"anime_id":                 item.AnimeID,
+			"episode_id":               item.EpisodeID,
+			"player":                   item.Player,
+			"translation_id":           item.TranslationID,
+			"playback_state":           domain.StatePlaying,
+			"playback_time":            "0",
+			"playback_time_updated_at": nowMs,
+		})
+		r.expireAll(pipe, ctx, roomID)
+		return nil
+	})
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: advance failed")
+	}
+	r.log.Infow("watch_together repo op", "op", "advance", "room_id", roomID, "item_id", item.ID, "episode_id", item.EpisodeID)
+	return nil
+}
+
+// TryAutoLock claims the room's short server-transition lock (SET NX PX).
+// Returns false while another instance or tick already holds it.
+func (r *RoomRepo) TryAutoLock(ctx context.Context, roomID string, ttl time.Duration) (bool, error) {
+	ok, err := r.client.SetNX(ctx, KeyRoomAutoLock(roomID), 1, ttl).Result()
+	if err != nil {
+		return false, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: auto lock failed")
+	}
+	return ok, nil
+}
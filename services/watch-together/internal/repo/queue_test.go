@@ -0,0 +1,119 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+func queueItem(id string, addedAt int64) domain.QueueItem {
+	return domain.QueueItem{
+		ID:            id,
+		AnimeID:       "anime-2",
+		EpisodeID:     "ep-" + id,
+		Player:        "kodik",
+		TranslationID: "610",
+		AddedBy:       "u1",
+		AddedAt:       addedAt,
+	}
+}
+
+func TestQueue_OrderVotesAndRemove(t *testing.T) {
+	r, mr := newRepo(t)
+	ctx := context.Background()
+	if err := r.CreateRoom(ctx, sampleRoom("q-room")); err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+
+	for i, id := range []string{"a", "b", "c"} {
+		if ok, err := r.AddQueueItem(ctx, "q-room", queueItem(id, int64(100+i))); err != nil || !ok {
+			t.Fatalf("AddQueueItem(%s) = %v, %v", id, ok, err)
+		}
+	}
+	// Two votes lift c to the head; a withdrawn vote does not count.
+	for _, u := range []string{"u1", "u2"} {
+		if found, err := r.VoteQueueItem(ctx, "q-room", "c", u, true); err != nil || !found {
+			t.Fatalf("VoteQueueItem = %v, %v", found, err)
+		}
+	}
+	_, _ = r.VoteQueueItem(ctx, "q-room", "b", "u1", true)
+	_, _ = r.VoteQueueItem(ctx, "q-room", "b", "u1", false)
+	if found, _ := r.VoteQueueItem(ctx, "q-room", "missing", "u1", true); found {
+		t.Fatal("vote on a missing item should report found=false")
+	}
+
+	items, err := r.GetQueue(ctx, "q-room")
+	if err != nil {
+		t.Fatalf("GetQueue: %v", err)
+	}
+	var order []string
+	for _, it := range items {
+		order = append(order, it.ID)
+	}
+	if fmt.Sprint(order) != "[c a b]" {
+		t.Fatalf("order = %v, want [c a b]", order)
+	}
+	if ttl := mr.TTL(KeyRoomQueue("q-room")); ttl != testTTL {
+		t.Errorf("queue TTL = %v, want %v", ttl, testTTL)
+	}
+
+	if removed, err := r.RemoveQueueItem(ctx, "q-room", "a"); err != nil || !removed {
+		t.Fatalf("RemoveQueueItem = %v, %v", removed, err)
+	}
+	if it, _ := r.GetQueueItem(ctx, "q-room", "a"); it != nil {
+		t.Fatal("item a should be gone")
+	}
+}
+
+func TestQueue_CapRejectsOverflow(t *testing.T) {
+	r, _ := newRepo(t)
+	ctx := context.Background()
+	for i := 0; i < domain.QueueMaxItems; i++ {
+		if ok, err := r.AddQueueItem(ctx, "cap-room", queueItem(fmt.Sprintf("i%d", i), int64(i))); err != nil || !ok {
+			t.Fatalf("AddQueueItem #%d = %v, %v", i, ok, err)
+		}
+	}
+	if ok, err := r.AddQueueItem(ctx, "cap-room", queueItem("overflow", 999)); err != nil || ok {
+		t.Fatalf("AddQueueItem past cap = %v, %v; want false, nil", ok, err)
+	}
+}
+
+func TestAdvanceTo_RewritesRoomAndResetsVotes(t *testing.T) {
+	r, _ := newRepo(t)
+	ctx := context.Background()
+	if err := r.CreateRoom(ctx, sampleRoom("adv-room")); err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+	item := queueItem("next", 1)
+	if _, err := r.AddQueueItem(ctx, "adv-room", item); err != nil {
+		t.Fatalf("AddQueueItem: %v", err)
+	}
+	if n, err := r.AddSkipVote(ctx, "adv-room", "u1"); err != nil || n != 1 {
+		t.Fatalf("AddSkipVote = %d, %v", n, err)
+	}
+	if n, _ := r.AddSkipVote(ctx, "adv-room", "u1"); n != 1 {
+		t.Fatalf("duplicate skip vote counted: %d", n)
+	}
+
+	if err := r.AdvanceTo(ctx, "adv-room", item, 1700000999000); err != nil {
+		t.Fatalf("AdvanceTo: %v", err)
+	}
+	room, err := r.GetRoom(ctx, "adv-room")
+	if err != nil {
+		t.Fatalf("GetRoom: %v", err)
+	}
+	if room.AnimeID != "anime-2" || room.EpisodeID != "ep-next" || room.Player != "kodik" || room.TranslationID != "610" {
+		t.Fatalf("room after advance = %+v", room)
+	}
+	if room.PlaybackState != domain.StatePlaying || room.PlaybackTime != 0 || room.PlaybackTimeUpdatedAtMs != 1700000999000 {
+		t.Fatalf("playback after advance = %s/%v/%d", room.PlaybackState, room.PlaybackTime, room.PlaybackTimeUpdatedAtMs)
+	}
+	if n, _ := r.SkipVoteCount(ctx, "adv-room"); n != 0 {
+		t.Fatalf("skip votes after advance = %d, want 0", n)
+	}
+	if items, _ := r.GetQueue(ctx, "adv-room"); len(items) != 0 {
+		t.Fatalf("queue after advance = %v, want empty", items)
+	}
+}
@@ -35,6 +35,11 @@ var allowedRoomFields = map[string]struct{}{
 	"playback_time":            {},
 	"playback_time_updated_at": {},
 	"host_user_id":             {},
+	"control_mode":             {},
+	"slow_mode_seconds":        {},
+	"skip_op_ed":               {},
+	"party_id":                 {},
+	"chat_thread_id":           {},
 }
 
 // Chat list cap (LTRIM 0 99 → keep at most 100 entries, newest at head).
@@ -77,6 +82,11 @@ func roomToFields(r *domain.Room) map[string]interface{} {
 		"playback_time":            strconv.FormatFloat(r.PlaybackTime, 'f', -1, 64),
 		"playback_time_updated_at": strconv.FormatInt(r.PlaybackTimeUpdatedAtMs, 10),
 		"host_user_id":             r.HostUserID,
+		"control_mode":             r.ControlMode,
+		"slow_mode_seconds":        strconv.Itoa(r.SlowModeSeconds),
+		"skip_op_ed":               strconv.FormatBool(r.SkipOpEd),
+		"party_id":                 r.PartyID,
+		"chat_thread_id":           r.ChatThreadID,
 	}
 }
 
@@ -88,6 +98,8 @@ func fieldsToRoom(m map[string]string) *domain.Room {
 	createdAt, _ := strconv.ParseInt(m["created_at"], 10, 64)
 	playbackTime, _ := strconv.ParseFloat(m["playback_time"], 64)
 	updatedAt, _ := strconv.ParseInt(m["playback_time_updated_at"], 10, 64)
+	slowMode, _ := strconv.Atoi(m["slow_mode_seconds"])
+	skipOpEd, _ := strconv.ParseBool(m["skip_op_ed"])
 	return &domain.Room{
 		ID:                      m["id"],
 		CreatedAt:               createdAt,
@@ -99,17 +111,40 @@ func fieldsToRoom(m map[string]string) *domain.Room {
 		PlaybackTime:            playbackTime,
 		PlaybackTimeUpdatedAtMs: updatedAt,
 		HostUserID:              m["host_user_id"],
+		ControlMode:             m["control_mode"],
+		SlowModeSeconds:         slowMode,
+		SkipOpEd:                skipOpEd,
+		PartyID:                 m["party_id"],
+		ChatThreadID:            m["chat_thread_id"],
 	}
 }
 
-// expireAll pipelines EXPIRE on all 3 persistent keys for the given room. Used
+// roomKeys lists every persistent key of a room: the 3 core keys plus the
+// protocol-1.1 moderation SETs, episode queue, attendance HASH and voice
+// channel HASH.
+func roomKeys(roomID string) []string {
+	return []string{
+		KeyRoom(roomID),
+		KeyRoomMembers(roomID),
+		KeyRoomMessages(roomID),
+		KeyRoomDelegates(roomID),
+		KeyRoomMuted(roomID),
+		KeyRoomBans(roomID),
+		KeyRoomQueue(roomID),
+		KeyRoomSkipVotes(roomID),
+		KeyRoomAttendance(roomID),
+		KeyRoomVoice(roomID),
+	}
+}
+
+// expireAll pipelines EXPIRE on every persistent key for the given room. Used
 // by every state-mutating method to keep the sliding TTL honest. EXPIRE on a
 // non-existent key is a Redis no-op (returns 0), so calling this for a room
-// that has not yet seen members/messages is safe.
+// that has not yet seen members/messages/moderation is safe.
 func (r *RoomRepo) expireAll(pipe redis.Pipeliner, ctx context.Context, roomID string) {
-	pipe.Expire(ctx, KeyRoom(roomID), r.ttl)
-	pipe.Expire(ctx, KeyRoomMembers(roomID), r.ttl)
-	pipe.Expire(ctx, KeyRoomMessages(roomID), r.ttl)
+	for _, key := range roomKeys(roomID) {
+		pipe.Expire(ctx, key, r.ttl)
+	}
 }
 
 // CreateRoom HSETs every field of `room` into wt:room:{id} and refreshes the
@@ -194,6 +229,8 @@ func (r *RoomRepo) UpdateRoomState(ctx context.Context, roomID string, fields ma
 			encoded[name] = strconv.FormatInt(v, 10)
 		case int:
 			encoded[name] = strconv.Itoa(v)
+		case bool:
+			encoded[name] = strconv.FormatBool(v)
 		default:
 			encoded[name] = v
 		}
@@ -216,15 +253,11 @@ func (r *RoomRepo) UpdateRoomState(ctx context.Context, roomID string, fields ma
 	return nil
 }
 
-// DeleteRoom removes all 3 persistent keys (HASH + members + messages). The
-// pubsub channel is not a key — it self-cleans when the last subscriber drops.
+// DeleteRoom removes every persistent key (HASH + members + messages +
+// moderation SETs). The pubsub channel is not a key — it self-cleans when the
+// last subscriber drops.
 func (r *RoomRepo) DeleteRoom(ctx context.Context, roomID string) error {
-	keys := []string{
-		KeyRoom(roomID),
-		KeyRoomMembers(roomID),
-		KeyRoomMessages(roomID),
-	}
-	if err := r.client.Del(ctx, keys...).Err(); err != nil {
+	if err := r.client.Del(ctx, roomKeys(roomID)...).Err(); err != nil {
 		return apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: delete room failed")
 	}
 	r.log.Infow("watch_together repo op", "op", "delete_room", "room_id", roomID)
@@ -244,9 +277,15 @@ func (r *RoomRepo) AddMember(ctx context.Context, roomID, userID string, meta do
 	if err != nil {
 		return apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: marshal member meta failed")
 	}
+	attendee, err := json.Marshal(domain.Attendee{Username: meta.Username, FirstJoined: meta.JoinedAt})
+	if err != nil {
+		return apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: marshal attendee failed")
+	}
 
 	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
 		pipe.HSet(ctx, KeyRoomMembers(roomID), userID, string(payload))
+		// First join wins — reconnects keep the original timestamp.
+		pipe.HSetNX(ctx, KeyRoomAttendance(roomID), userID, string(attendee))
 		r.expireAll(pipe, ctx, roomID)
 		return nil
 	})
@@ -307,6 +346,30 @@ func (r *RoomRepo) ListMembers(ctx context.Context, roomID string) ([]domain.Mem
 	return members, nil
 }
 
+// GetAttendance returns everyone who ever joined the room (user_id →
+// Attendee), including members who since left. Read-only — no TTL refresh.
+func (r *RoomRepo) GetAttendance(ctx context.Context, roomID string) (map[string]domain.Attendee, error) {
+	m, err := r.client.HGetAll(ctx, KeyRoomAttendance(roomID)).Result()
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: get attendance failed")
+	}
+	out := make(map[string]domain.Attendee, len(m))
+	for userID, raw := range m {
+		var a domain.Attendee
+		if err := json.Unmarshal([]byte(raw), &a); err != nil {
+			r.log.Warnw("watch_together repo decode",
+				"op", "get_attendance",
+				"room_id", roomID,
+				"user_id", userID,
+				"err", err,
+			)
+			continue
+		}
+		out[userID] = a
+	}
+	return out, nil
+}
+
 // CountMembers returns HLEN on the members HASH. Cheap O(1) — used by the WS
 // upgrade handler to enforce the per-room capacity limit (WT-NF-02 → 10).
 func (r *RoomRepo) CountMembers(ctx context.Context, roomID string) (int, error) {
@@ -0,0 +1,111 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/redis/go-redis/v9"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+// Voice channel: participants live JSON-encoded in the wt:room:{id}:voice
+// HASH keyed by user ID and share the room's sliding TTL. Join runs under
+// WATCH so the participant cap holds across instances.
+
+// JoinVoice adds p to the room's voice channel, or updates their mute flag
+// when already in it. Returns ok=false without writing when the channel is
+// full.
+func (r *RoomRepo) JoinVoice(ctx context.Context, roomID string, p domain.VoiceParticipant) (bool, error) {
+	if roomID == "" || p.UserID == "" {
+		return false, apperrors.InvalidInput("room_id and user_id are required")
+	}
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return false, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: marshal voice participant")
+	}
+	key := KeyRoomVoice(roomID)
+	joined := false
+	err = r.watchTx(ctx, "voice_join", key, func(tx *redis.Tx) error {
+		already, err := tx.HExists(ctx, key, p.UserID).Result()
+		if err != nil {
+			return err
+		}
+		if !already {
+			n, err := tx.HLen(ctx, key).Result()
+			if err != nil {
+				return err
+			}
+			if n >= domain.VoiceMaxParticipants {
+				joined = false
+				return nil
+			}
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, p.UserID, raw)
+			r.expireAll(pipe, ctx, roomID)
+			return nil
+		})
+		joined = err == nil
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return joined, nil
+}
+
+// LeaveVoice removes userID from the voice channel. Returns whether they
+// were in it.
+func (r *RoomRepo) LeaveVoice(ctx context.Context, roomID, userID string) (bool, error) {
+	n, err := r.client.HDel(ctx, KeyRoomVoice(roomID), userID).Result()
+	if err != nil {
+		return false, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: voice leave failed")
+	}
+	return n > 0, nil
+}
+
+// GetVoiceParticipant returns one participant, or nil when userID is not in
+// the voice channel. Read-only — no TTL refresh.
+func (r *RoomRepo) GetVoiceParticipant(ctx context.Context, roomID, userID string) (*domain.VoiceParticipant, error) {
+	raw, err := r.client.HGet(ctx, KeyRoomVoice(roomID), userID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: get voice participant failed")
+	}
+	var p domain.VoiceParticipant
+	if err := json.Unmarshal([]byte(raw), &p); err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: decode voice participant")
+	}
+	return &p, nil
+}
+
+// GetVoice returns the voice channel ordered by join time. Corrupt entries
+// are logged and skipped. Read-only — no TTL refresh.
+func (r *RoomRepo) GetVoice(ctx context.Context, roomID string) ([]domain.VoiceParticipant, error) {
+	raw, err := r.client.HGetAll(ctx, KeyRoomVoice(roomID)).Result()
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInternal, "watch-together: get voice failed")
+	}
+	out := make([]domain.VoiceParticipant, 0, len(raw))
+	for id, v := range raw {
+		var p domain.VoiceParticipant
+		if err := json.Unmarshal([]byte(v), &p); err != nil {
+			r.log.Warnw("watch_together: skip corrupt voice participant", "room_id", roomID, "user_id", id, "err", err)
+			continue
+		}
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].JoinedAt != out[j].JoinedAt {
+			return out[i].JoinedAt < out[j].JoinedAt
+		}
+		return out[i].UserID < out[j].UserID
+	})
+	return out, nil
+}
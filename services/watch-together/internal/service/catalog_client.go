@@ -78,9 +78,10 @@ type CatalogClient struct {
 	http    *http.Client
 	log     *logger.Logger
 
-	mu    sync.Mutex
-	cache map[string]cachedValidation // positive (Valid=true) results only
-	now   func() time.Time            // injectable for tests
+	mu        sync.Mutex
+	cache     map[string]cachedValidation // positive (Valid=true) results only
+	skipCache map[string]cachedSkipTimes  // found and not-found answers alike
+	now       func() time.Time            // injectable for tests
 
 	stop     chan struct{} // closed by Stop() to end the janitor goroutine
 	stopOnce sync.Once
@@ -139,12 +140,13 @@ func NewCatalogClient(baseURL string, log *logger.Logger) *CatalogClient {
 		log = logger.Default()
 	}
 	c := &CatalogClient{
-		baseURL: strings.TrimRight(baseURL, "/"),
-		http:    &http.Client{Timeout: catalogClientTimeout},
-		log:     log,
-		cache:   make(map[string]cachedValidation),
-		now:     time.Now,
-		stop:    make(chan struct{}),
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		http:      &http.Client{Timeout: catalogClientTimeout},
+		log:       log,
+		cache:     make(map[string]cachedValidation),
+		skipCache: make(map[string]cachedSkipTimes),
+		now:       time.Now,
+		stop:      make(chan struct{}),
 	}
 	go c.runJanitor()
 	return c
@@ -175,6 +177,11 @@ func (c *CatalogClient) evictExpired() {
 			delete(c.cache, k)
 		}
 	}
+	for k, v := range c.skipCache {
+		if !now.Before(v.expireAt) {
+			delete(c.skipCache, k)
+		}
+	}
 }
 
 // Stop ends the background janitor goroutine. Idempotent; safe to call from a
@@ -302,3 +309,102 @@ func (c *CatalogClient) ValidateEpisode(
 			resp.StatusCode, string(body))
 	}
 }
+
+// SkipTimes is the OP/ED window pair of one episode, in seconds. A zero
+// window (End == 0) means the catalog knows no timestamp for that side.
+type SkipTimes struct {
+	OPStart, OPEnd float64
+	EDStart, EDEnd float64
+}
+
+// HasOP reports whether the OP window is known.
+func (s SkipTimes) HasOP() bool { return s.OPEnd > s.OPStart }
+
+// HasED reports whether the ED window is known.
+func (s SkipTimes) HasED() bool { return s.EDEnd > s.EDStart }
+
+// cachedSkipTimes is the per-(malID, episode) skip-times cache entry.
+type cachedSkipTimes struct {
+	times    SkipTimes
+	expireAt time.Time
+}
+
+// catalogSkipTimesCacheTTL bounds how long an episode's OP/ED windows are
+// reused. Unlike validation, not-found answers are cached too: every
+// member's 1Hz time_tick consults skip times while skip_op_ed is on, so an
+// uncached miss would turn into a catalog request per tick per member.
+const catalogSkipTimesCacheTTL = 5 * time.Minute
+
+// skipTimesEnvelope mirrors the catalog's public skip-times response.
+type skipTimesEnvelope struct {
+	Success bool `json:"success"`
+	Data    struct {
+		Found   bool `json:"found"`
+		Results []struct {
+			Interval struct {
+				StartTime float64 `json:"startTime"`
+				EndTime   float64 `json:"endTime"`
+			} `json:"interval"`
+			SkipType string `json:"skipType"`
+		} `json:"results"`
+	} `json:"data"`
+	Error *catalogErrorObj `json:"error,omitempty"`
+}
+
+// SkipTimes fetches the OP/ED windows of an episode from the catalog's
+// skip-times endpoint (GET {CatalogURL}/api/anime/skip-times/{malId}/{episode}).
+// Room anime IDs are shikimori IDs, which equal MAL IDs. "mixed-op" /
+// "mixed-ed" count as their side; other segment types are ignored.
+func (c *CatalogClient) SkipTimes(ctx context.Context, malID string, episode int) (SkipTimes, error) {
+	key := fmt.Sprintf("%s|%d", malID, episode)
+	c.mu.Lock()
+	entry, ok := c.skipCache[key]
+	if ok && !c.now().Before(entry.expireAt) {
+		delete(c.skipCache, key)
+		ok = false
+	}
+	c.mu.Unlock()
+	if ok {
+		return entry.times, nil
+	}
+
+	endpoint := fmt.Sprintf("%s/api/anime/skip-times/%s/%d", c.baseURL, url.PathEscape(malID), episode)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return SkipTimes{}, fmt.Errorf("build catalog skip-times request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return SkipTimes{}, fmt.Errorf("catalog skip-times transport error: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return SkipTimes{}, fmt.Errorf("read catalog skip-times response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return SkipTimes{}, fmt.Errorf("catalog skip-times status %d: %s", resp.StatusCode, string(body))
+	}
+	var env skipTimesEnvelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return SkipTimes{}, fmt.Errorf("decode catalog skip-times response: %w", err)
+	}
+	if !env.Success {
+		return SkipTimes{}, fmt.Errorf("catalog skip-times envelope success=false")
+	}
+
+	var times SkipTimes
+	for _, r := range env.Data.Results {
+		switch r.SkipType {
+		case "op", "mixed-op":
+			times.OPStart, times.OPEnd = r.Interval.StartTime, r.Interval.EndTime
+		case "ed", "mixed-ed":
+			times.EDStart, times.EDEnd = r.Interval.StartTime, r.Interval.EndTime
+		}
+	}
+	c.mu.Lock()
+	c.skipCache[key] = cachedSkipTimes{times: times, expireAt: c.now().Add(catalogSkipTimesCacheTTL)}
+	c.mu.Unlock()
+	return times, nil
+}
@@ -316,3 +316,37 @@ func TestCatalogClient_EvictExpired(t *testing.T) {
 		t.Errorf("cache size = %d, want 1", n)
 	}
 }
+
+func TestCatalogClient_SkipTimes_ParsesAndCaches(t *testing.T) {
+	var hits int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&hits, 1)
+		if r.URL.Path != "/api/anime/skip-times/5114/3" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success":true,"data":{"found":true,"results":[` +
+			`{"interval":{"startTime":80,"endTime":170},"skipType":"op"},` +
+			`{"interval":{"startTime":1300,"endTime":1390},"skipType":"mixed-ed"},` +
+			`{"interval":{"startTime":0,"endTime":30},"skipType":"recap"}]}}`))
+	}))
+	defer srv.Close()
+
+	c := NewCatalogClient(srv.URL, logger.Default())
+	defer c.Stop()
+
+	got, err := c.SkipTimes(context.Background(), "5114", 3)
+	if err != nil {
+		t.Fatalf("SkipTimes: %v", err)
+	}
+	want := SkipTimes{OPStart: 80, OPEnd: 170, EDStart: 1300, EDEnd: 1390}
+	if got != want {
+		t.Fatalf("SkipTimes = %+v, want %+v", got, want)
+	}
+	if _, err := c.SkipTimes(context.Background(), "5114", 3); err != nil {
+		t.Fatalf("second SkipTimes: %v", err)
+	}
+	if n := atomic.LoadInt64(&hits); n != 1 {
+		t.Fatalf("catalog hits = %d, want 1 (second call cached)", n)
+	}
+}
@@ -0,0 +1,307 @@
+// Package service — chat_log.go is the durable chat history: a write-behind
+// sink that copies every chat message and reaction from live rooms into
+// Postgres (with room / anime / episode / playback-position context), plus
+// the owner-facing browse and export API over it.
+//
+// Write-behind model:
+//   - The live path never waits on Postgres. The InboundRouter hands each
+//     entry to Record, which enqueues without blocking; Run batches the queue
+//     into INSERTs every chatLogFlushInterval or chatLogBatchSize entries.
+//   - A full queue drops the entry (wt_chat_log_dropped_total) rather than
+//     stall the room. The Redis LIST stays the source of truth for the live
+//     snapshot either way.
+//   - Entries are keyed by message ID, so a replayed batch is harmless.
+//
+// Threads: a room writes into its ChatThread (Room.ChatThreadID). Ad-hoc
+// rooms start a thread under their own ID, party rooms use the party ID, and
+// POST /rooms with history_id re-opens an existing thread the caller owns —
+// the new room's snapshot then carries the earlier rooms' messages.
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/repo"
+)
+
+const (
+	// chatLogQueueSize bounds entries waiting for the next flush.
+	chatLogQueueSize = 4096
+	// chatLogBatchSize flushes early once this many entries are queued.
+	chatLogBatchSize = 200
+	// chatLogFlushInterval is the longest an entry waits in memory.
+	chatLogFlushInterval = 2 * time.Second
+	// chatHistoryPriorLimit caps the earlier-room messages in a snapshot.
+	chatHistoryPriorLimit = 50
+	// chatHistoryPageMax caps one GET /history/{id} page.
+	chatHistoryPageMax = 200
+	// chatHistoryThreadsMax caps GET /history.
+	chatHistoryThreadsMax = 100
+)
+
+// ChatRecorder is the write-behind sink the InboundRouter hands chat to.
+// *ChatLog implements it; tests may pass a capturing fake.
+type ChatRecorder interface {
+	Record(e domain.ChatLogEntry)
+}
+
+// ChatLog owns the durable chat history. Construct with NewChatLog, run
+// Run in a goroutine and call Wait after cancelling it so the last batch
+// lands before shutdown.
+type ChatLog struct {
+	repo  *repo.ChatLogRepo
+	log   *logger.Logger
+	queue chan domain.ChatLogEntry
+	done  chan struct{}
+	now   func() time.Time
+}
+
+// NewChatLog wires the repo. Pass nil for log to fall back to
+// logger.Default().
+func NewChatLog(r *repo.ChatLogRepo, log *logger.Logger) *ChatLog {
+	if log == nil {
+		log = logger.Default()
+	}
+	return &ChatLog{
+		repo:  r,
+		log:   log,
+		queue: make(chan domain.ChatLogEntry, chatLogQueueSize),
+		done:  make(chan struct{}),
+		now:   time.Now,
+	}
+}
+
+// Record enqueues one entry for the next flush. Never blocks.
+func (c *ChatLog) Record(e domain.ChatLogEntry) {
+	select {
+	case c.queue <- e:
+	default:
+		ChatLogDroppedTotal.Inc()
+		c.log.Warnw("watch_together chat log queue full, entry dropped",
+			"room_id", e.RoomID,
+			"thread_id", e.ThreadID,
+		)
+	}
+}
+
+// Run flushes queued entries until ctx is cancelled, then drains the queue
+// once more with a fresh deadline.
+func (c *ChatLog) Run(ctx context.Context) {
+	defer close(c.done)
+	ticker := time.NewTicker(chatLogFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]domain.ChatLogEntry, 0, chatLogBatchSize)
+	flush := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		if err := c.repo.InsertEntries(ctx, batch); err != nil {
+			ChatLogDroppedTotal.Add(float64(len(batch)))
+			c.log.Errorw("watch_together chat log flush failed", "entries", len(batch), "err", err)
+		}
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case e := <-c.queue:
+			batch = append(batch, e)
+			if len(batch) >= chatLogBatchSize {
+				flush(ctx)
+			}
+		case <-ticker.C:
+			flush(ctx)
+		case <-ctx.Done():
+			drainCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			for {
+				select {
+				case e := <-c.queue:
+					batch = append(batch, e)
+					if len(batch) >= chatLogBatchSize {
+						flush(drainCtx)
+					}
+				default:
+					flush(drainCtx)
+					return
+				}
+			}
+		}
+	}
+}
+
+// Wait blocks until Run has returned.
+func (c *ChatLog) Wait() { <-c.done }
+
+// openThread records that room writes into room.ChatThreadID. Called by
+// RoomService.Create; a failure is logged, not surfaced — the room still
+// works, its chat just lands in a thread row created on the next open.
+func (c *ChatLog) openThread(ctx context.Context, room *domain.Room) {
+	now := c.now().UTC()
+	err := c.repo.OpenThread(ctx, &domain.ChatThread{
+		ID:           room.ChatThreadID,
+		OwnerUserID:  room.HostUserID,
+		AnimeID:      room.AnimeID,
+		PartyID:      room.PartyID,
+		LastRoomID:   room.ID,
+		CreatedAt:    now,
+		LastActiveAt: now,
+	})
+	if err != nil {
+		c.log.Warnw("watch_together open chat thread", "room_id", room.ID, "thread_id", room.ChatThreadID, "err", err)
+	}
+}
+
+// ownedThread returns threadID when ownerID owns it. Someone else's thread
+// reads as NotFound so IDs cannot be probed.
+func (c *ChatLog) ownedThread(ctx context.Context, ownerID, threadID string) (*domain.ChatThread, error) {
+	t, err := c.repo.GetThread(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+	if t.OwnerUserID != ownerID {
+		return nil, apperrors.NotFound("chat history")
+	}
+	return t, nil
+}
+
+// prior returns the earlier rooms' messages for a snapshot, or nil. Errors
+// are logged — a snapshot without history beats no snapshot.
+func (c *ChatLog) prior(ctx context.Context, room *domain.Room) []domain.ChatLogEntry {
+	if room.ChatThreadID == "" {
+		return nil
+	}
+	entries, err := c.repo.PriorMessages(ctx, room.ChatThreadID, room.ID, chatHistoryPriorLimit)
+	if err != nil {
+		c.log.Warnw("watch_together prior chat", "room_id", room.ID, "thread_id", room.ChatThreadID, "err", err)
+		return nil
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	return entries
+}
+
+// Threads lists the chat histories userID owns, most recent first.
+func (c *ChatLog) Threads(ctx context.Context, userID string) ([]domain.ChatThread, error) {
+	return c.repo.ListThreads(ctx, userID, chatHistoryThreadsMax)
+}
+
+// Page returns up to limit entries of an owned thread sent before `before`
+// (zero = the newest), oldest first.
+func (c *ChatLog) Page(ctx context.Context, userID, threadID string, before time.Time, limit int) (*domain.ChatHistoryPage, error) {
+	if limit <= 0 || limit > chatHistoryPageMax {
+		limit = chatHistoryPageMax
+	}
+	t, err := c.ownedThread(ctx, userID, threadID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := c.repo.ListEntries(ctx, threadID, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	page := &domain.ChatHistoryPage{Thread: *t, Entries: entries}
+	if page.Entries == nil {
+		page.Entries = []domain.ChatLogEntry{}
+	}
+	if len(entries) == limit {
+		next := entries[0].SentAt
+		page.NextBefore = &next
+	}
+	return page, nil
+}
+
+// ChatExport is a whole thread as downloaded by its owner.
+type ChatExport struct {
+	Thread     domain.ChatThread     `json:"thread"`
+	ExportedAt time.Time             `json:"exported_at"`
+	Entries    []domain.ChatLogEntry `json:"entries"`
+}
+
+// Export returns an owned thread in full.
+func (c *ChatLog) Export(ctx context.Context, userID, threadID string) (*ChatExport, error) {
+	t, err := c.ownedThread(ctx, userID, threadID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := c.repo.AllEntries(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+	return &ChatExport{Thread: *t, ExportedAt: c.now().UTC(), Entries: entries}, nil
+}
+
+// WriteText renders the export as a plain-text log, one line per entry:
+//
+//	2026-10-01 19:05:03 [ep 3 12:34] Alice: hello
+//	2026-10-01 19:05:04 [ep 3 12:35] Bob reacted 🔥
+func (e *ChatExport) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Watch-together chat %s (anime %s), exported %s UTC\n\n",
+		e.Thread.ID, e.Thread.AnimeID, e.ExportedAt.Format("2006-01-02 15:04:05"))
+	for _, en := range e.Entries {
+		fmt.Fprintf(&b, "%s [ep %s %s] ", en.SentAt.UTC().Format("2006-01-02 15:04:05"), en.EpisodeID, formatPlayback(en.PlaybackTime))
+		if en.Kind == domain.ChatKindReaction {
+			fmt.Fprintf(&b, "%s reacted %s\n", en.Username, en.Body)
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %s\n", en.Username, en.Body)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatPlayback renders seconds as m:ss, or h:mm:ss past an hour.
+func formatPlayback(sec float64) string {
+	if sec < 0 {
+		sec = 0
+	}
+	s := int(sec)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// recordChat hands one message or reaction to the chat log, stamped with
+// the room's thread, episode and canonical playback position. No-op when
+// no recorder is wired.
+func (r *InboundRouter) recordChat(ctx context.Context, conn ConnectionCtx, kind, id, body string, ts int64) {
+	if r.chatLog == nil {
+		return
+	}
+	room, err := r.roomCache.GetRoom(ctx, conn.RoomID)
+	if err != nil {
+		r.log.Warnw("watch_together chat log room lookup", "room_id", conn.RoomID, "err", err)
+		return
+	}
+	pos := room.PlaybackTime
+	if room.PlaybackState == domain.StatePlaying {
+		pos += float64(ts-room.PlaybackTimeUpdatedAtMs) / 1000
+	}
+	threadID := room.ChatThreadID
+	if threadID == "" {
+		threadID = room.ID
+	}
+	r.chatLog.Record(domain.ChatLogEntry{
+		ID:           id,
+		ThreadID:     threadID,
+		RoomID:       room.ID,
+		AnimeID:      room.AnimeID,
+		EpisodeID:    room.EpisodeID,
+		Kind:         kind,
+		UserID:       conn.UserID,
+		Username:     conn.Username,
+		Body:         body,
+		PlaybackTime: pos,
+		SentAt:       time.UnixMilli(ts).UTC(),
+	})
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/repo"
+)
+
+func newChatLog(t *testing.T) *ChatLog {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.ChatThread{}, &domain.ChatLogEntry{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return NewChatLog(repo.NewChatLogRepo(db), logger.Default())
+}
+
+// drain runs the write-behind loop until everything queued so far is stored.
+// Run closes done, so call it once per ChatLog.
+func drain(c *ChatLog) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go c.Run(ctx)
+	cancel()
+	c.Wait()
+}
+
+func TestChatLog_WritesBehindAndReopensHistory(t *testing.T) {
+	ctx := context.Background()
+	fx := newRouterFixture(t)
+	svc := NewRoomService(fx.repo, logger.Default())
+	svc.newID = func() string { return "room-1" }
+	svc.now = func() time.Time { return fx.now }
+	chat := newChatLog(t)
+	svc.SetChatLog(chat)
+	fx.router.SetChatRecorder(chat)
+	n := 0
+	fx.router.SetIDProviderForTest(func() string { n++; return fmt.Sprintf("entry-%d", n) })
+
+	room, err := svc.Create(ctx, "host", "Host", validInput())
+	if err != nil || room.ChatThreadID != "room-1" {
+		t.Fatalf("Create = %+v, %v", room, err)
+	}
+	// Playing since 90s into the episode, 30s ago.
+	if err := fx.repo.UpdateRoomState(ctx, "room-1", map[string]interface{}{
+		"playback_state":           domain.StatePlaying,
+		"playback_time":            "90",
+		"playback_time_updated_at": fx.now.Add(-30 * time.Second).UnixMilli(),
+	}); err != nil {
+		t.Fatalf("UpdateRoomState: %v", err)
+	}
+
+	conn := ConnectionCtx{RoomID: "room-1", UserID: "host", Username: "Host"}
+	fx.dispatchJSON(t, conn, domain.MsgChatMessage, domain.ChatMessageInData{Body: "hello"})
+	fx.dispatchJSON(t, conn, domain.MsgChatReaction, domain.ChatReactionInData{Emoji: "🔥"})
+	drain(chat)
+
+	page, err := chat.Page(ctx, "host", "room-1", time.Time{}, 0)
+	if err != nil || len(page.Entries) != 2 {
+		t.Fatalf("Page = %+v, %v", page, err)
+	}
+	msg := page.Entries[0]
+	if msg.Kind != domain.ChatKindMessage || msg.Body != "hello" || msg.EpisodeID != "ep-1" || msg.PlaybackTime != 120 {
+		t.Fatalf("message entry = %+v", msg)
+	}
+	if page.Entries[1].Kind != domain.ChatKindReaction || page.Entries[1].Body != "🔥" {
+		t.Fatalf("reaction entry = %+v", page.Entries[1])
+	}
+	if _, err := chat.Page(ctx, "someone-else", "room-1", time.Time{}, 0); !isNotFound(err) {
+		t.Fatalf("non-owner Page err = %v, want NotFound", err)
+	}
+
+	// Re-opening the history in a new room shows the earlier messages.
+	svc.newID = func() string { return "room-2" }
+	in := validInput()
+	in.HistoryID = "room-1"
+	if _, err := svc.Create(ctx, "someone-else", "Else", in); !stderrors.Is(err, ErrInvalidInput) {
+		t.Fatalf("reopen by non-owner err = %v, want ErrInvalidInput", err)
+	}
+	reopened, err := svc.Create(ctx, "host", "Host", in)
+	if err != nil || reopened.ChatThreadID != "room-1" {
+		t.Fatalf("reopen = %+v, %v", reopened, err)
+	}
+	snap, err := svc.Get(ctx, "room-2")
+	if err != nil || len(snap.History) != 1 || snap.History[0].Body != "hello" {
+		t.Fatalf("reopened snapshot history = %+v, %v", snap, err)
+	}
+
+	export, err := chat.Export(ctx, "host", "room-1")
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	var txt strings.Builder
+	if err := export.WriteText(&txt); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+	for _, want := range []string{"[ep ep-1 2:00] Host: hello", "Host reacted 🔥"} {
+		if !strings.Contains(txt.String(), want) {
+			t.Fatalf("text export missing %q:\n%s", want, txt.String())
+		}
+	}
+}
@@ -102,6 +102,10 @@ type GraceManager struct {
 	period time.Duration
 	log    *logger.Logger
 
+	// archiver, when set, archives a party room before DeleteRoom. Set once
+	// in main.go before any timer can fire.
+	archiver RoomArchiver
+
 	// entries: roomID → *graceEntry. sync.Map is the right primitive here
 	// because the dominant access pattern is independent roomIDs — no
 	// cross-room contention, and Cancel needs LoadAndDelete (the atomic
@@ -138,6 +142,10 @@ func NewGraceManager(r *repo.RoomRepo, hub HubFanout, period time.Duration, log
 	}
 }
 
+// SetArchiver installs the hook fire runs before deleting an abandoned
+// room. nil (the default) disables it.
+func (g *GraceManager) SetArchiver(a RoomArchiver) { g.archiver = a }
+
 // Period returns the configured grace duration. Exposed for log context
 // in the WS handler (so we don't have to thread cfg.GracePeriod through
 // to every makeOnClose call site).
@@ -288,6 +296,7 @@ func (g *GraceManager) fire(roomID string) {
 	// Must run BEFORE DeleteRoom (the room snapshot is still readable).
 	if room, err := g.repo.GetRoom(ctx, roomID); err == nil && room != nil {
 		observeRoomTeardown(ctx, g.repo, g.log, room)
+		archiveRoom(ctx, g.archiver, room)
 	}
 
 	// 3. Delete the 3 persistent keys.
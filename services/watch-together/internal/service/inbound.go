@@ -1,16 +1,18 @@
 // Package service — inbound.go is the WebSocket inbound message router for
 // the watch-together service.
 //
-// Role: every `playback:*`, `state:change_*`, `chat:*`, `presence:*` envelope
-// the readPump in hub/connection.go decodes hits Dispatch, which routes to
-// one of 10 typed handlers, applies side effects against Redis via repo, and
-// fans out the corresponding outbound envelope(s) via hub.Broadcast or
-// hub.SendTo.
+// Role: every `playback:*`, `state:change_*`, `chat:*`, `presence:*`,
+// (protocol 1.1) `host:*` / `queue:*` and (protocol 1.2) `voice:*` envelope
+// the readPump in hub/connection.go decodes hits Dispatch, which routes to a
+// typed handler, applies side effects against Redis via repo, and fans out
+// the corresponding outbound envelope(s) via hub.Broadcast or hub.SendTo. The room's ControlMode gate
+// and the host:* handlers live in moderation.go; the episode queue and its
+// server-initiated transitions in queue.go; voice signaling in voice.go.
 //
 // The router is the production binding for Connection.OnMessage installed by
 // the WS upgrade handler (handler/websocket.go): `c.OnMessage = router.Dispatch`
-// and `c.OnClose` chains into router.OnDisconnect for drift + rate-limit
-// cleanup.
+// and `c.OnClose` chains into router.OnDisconnect for drift, rate-limit and
+// voice cleanup.
 //
 // Design references:
 //   - docs/superpowers/specs/2026-05-25-watch-together-design.md §Inbound &
@@ -54,6 +56,14 @@ type HubFanout interface {
 	SendTo(ctx context.Context, roomID, userID string, env domain.Envelope) (int, error)
 }
 
+// HubEvictor is the optional hub capability host:kick / host:ban need: write
+// a final frame to every connection of one user and close them. *hub.Hub
+// implements it; NewInboundRouter picks it up by type assertion so fakes
+// that only fan out (GraceManager tests) need not grow the method.
+type HubEvictor interface {
+	Disconnect(ctx context.Context, roomID, userID string, final domain.Envelope, reason string) (int, error)
+}
+
 // ConnectionCtx is the minimal Connection-shaped view the router needs.
 // Defined here so tests don't have to construct a real hub.Connection
 // (which carries goroutine pumps + private state). The real
@@ -139,10 +149,14 @@ var reactionWhitelist = map[string]struct{}{
 type InboundRouter struct {
 	repo    *repo.RoomRepo
 	hub     HubFanout
+	evictor HubEvictor // nil when the hub cannot disconnect users
 	drift   *DriftEngine
 	rl      *RateLimiter
 	catalog CatalogValidator
-	log     *logger.Logger
+	// skipTimes is the catalog's optional OP/ED source for skip_op_ed; nil
+	// when the catalog implementation does not provide one.
+	skipTimes SkipTimesSource
+	log       *logger.Logger
 
 	// roomCache is a TTL + write-invalidated in-process cache over repo's
 	// canonical Room read (audit L802). The drift engine reads through it on
@@ -153,6 +167,10 @@ type InboundRouter struct {
 	// wt:room:{id}, so invalidating here keeps the cache exactly consistent.
 	roomCache *RoomCache
 
+	// chatLog receives every persisted chat message and reaction for the
+	// durable history (chat_log.go); nil disables it.
+	chatLog ChatRecorder
+
 	now   func() time.Time
 	newID func() string
 }
@@ -175,7 +193,7 @@ func NewInboundRouter(
 	if log == nil {
 		log = logger.Default()
 	}
-	return &InboundRouter{
+	router := &InboundRouter{
 		repo:      r,
 		hub:       h,
 		drift:     drift,
@@ -186,8 +204,19 @@ func NewInboundRouter(
 		now:       time.Now,
 		newID:     uuid.NewString,
 	}
+	if ev, ok := h.(HubEvictor); ok {
+		router.evictor = ev
+	}
+	if st, ok := catalog.(SkipTimesSource); ok {
+		router.skipTimes = st
+	}
+	return router
 }
 
+// SetChatRecorder installs the durable chat-history sink. Wired in main.go
+// to the *ChatLog; nil (the default) keeps chat Redis-only.
+func (r *InboundRouter) SetChatRecorder(c ChatRecorder) { r.chatLog = c }
+
 // SetClockForTest overrides the router's `now` provider. INTERNAL TEST USE
 // ONLY — pin envelope server_ts / chat message TS to a deterministic instant.
 func (r *InboundRouter) SetClockForTest(fn func() time.Time) {
@@ -214,6 +243,12 @@ func (r *InboundRouter) Dispatch(conn ConnectionCtx, env domain.Envelope) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// Protocol 1.1 — the room's ControlMode decides who may drive playback
+	// and change episode/player/translation (moderation.go).
+	if isControlType(env.Type) && !r.allowControl(ctx, conn) {
+		return
+	}
+
 	switch env.Type {
 	case domain.MsgPlaybackPlay:
 		r.handlePlaybackEvent(ctx, conn, env.Data, "play", domain.StatePlaying)
@@ -235,6 +270,44 @@ func (r *InboundRouter) Dispatch(conn ConnectionCtx, env domain.Envelope) {
 		r.handleReaction(ctx, conn, env.Data)
 	case domain.MsgPresenceHeartbeat:
 		r.handleHeartbeat(ctx, conn, env.Data)
+	case domain.MsgHostSetControlMode:
+		r.handleSetControlMode(ctx, conn, env.Data)
+	case domain.MsgHostSetSlowMode:
+		r.handleSetSlowMode(ctx, conn, env.Data)
+	case domain.MsgHostSetDelegate:
+		r.handleSetDelegate(ctx, conn, env.Data)
+	case domain.MsgHostMute:
+		r.handleMute(ctx, conn, env.Data)
+	case domain.MsgHostTransfer:
+		r.handleTransferHost(ctx, conn, env.Data)
+	case domain.MsgHostKick:
+		r.handleKick(ctx, conn, env.Data, false)
+	case domain.MsgHostBan:
+		r.handleKick(ctx, conn, env.Data, true)
+	case domain.MsgHostUnban:
+		r.handleUnban(ctx, conn, env.Data)
+	case domain.MsgHostSetSkipOpEd:
+		r.handleSetSkipOpEd(ctx, conn, env.Data)
+	case domain.MsgQueueAdd:
+		r.handleQueueAdd(ctx, conn, env.Data)
+	case domain.MsgQueueRemove:
+		r.handleQueueRemove(ctx, conn, env.Data)
+	case domain.MsgQueueVote:
+		r.handleQueueVote(ctx, conn, env.Data)
+	case domain.MsgQueueVoteSkip:
+		r.handleQueueVoteSkip(ctx, conn)
+	case domain.MsgQueueNext:
+		r.handleQueueNext(ctx, conn)
+	case domain.MsgVoiceJoin:
+		r.handleVoiceJoin(ctx, conn, env.Data)
+	case domain.MsgVoiceLeave:
+		r.handleVoiceLeave(ctx, conn)
+	case domain.MsgVoiceMute:
+		r.handleVoiceMute(ctx, conn, env.Data)
+	case domain.MsgVoiceSpeaking:
+		r.handleVoiceSpeaking(ctx, conn, env.Data)
+	case domain.MsgVoiceSignal:
+		r.handleVoiceSignal(ctx, conn, env.Data)
 	default:
 		r.log.Warnw("watch_together inbound unknown type",
 			"room_id", conn.RoomID,
@@ -247,10 +320,14 @@ func (r *InboundRouter) Dispatch(conn ConnectionCtx, env domain.Envelope) {
 
 // OnDisconnect is the per-connection lifecycle hook invoked from the WS
 // handler's OnClose callback. Drops the member's drift state + rate-limit
-// buckets so a long-running service doesn't accumulate dead user state.
+// buckets so a long-running service doesn't accumulate dead user state, and
+// takes them out of the voice channel (voice.go).
 //
 // Idempotent — calling on a member who never had state is a no-op.
 func (r *InboundRouter) OnDisconnect(roomID, userID string) {
+	if r.repo != nil {
+		r.leaveVoiceOnDisconnect(roomID, userID)
+	}
 	if r.drift != nil {
 		r.drift.Reset(roomID, userID)
 	}
@@ -377,7 +454,10 @@ func (r *InboundRouter) handleSeek(ctx context.Context, conn ConnectionCtx, data
 // ----------------------------------------------------------------------------
 // Handler — playback:time_tick
 //
-// Drift-detection only; never broadcasts. The drift engine reads the
+// Drift detection, plus (protocol 1.1) the queue's server-initiated
+// transitions: when the room reaches the episode end or, with skip_op_ed,
+// an OP/ED window, queue.go advances or seeks the room and this tick's drift
+// check is skipped. Otherwise it never broadcasts. The drift engine reads the
 // canonical room state from Redis, decides if the reported_time is
 // in/soft/hard/persistent drift band, and the router either sends a
 // playback:correction (soft/hard) or an error:PERSISTENT_DRIFT (persistent)
@@ -390,6 +470,9 @@ func (r *InboundRouter) handleTimeTick(ctx context.Context, conn ConnectionCtx,
 		r.sendBadPayload(ctx, conn, "time_tick", err)
 		return
 	}
+	if r.autoTransition(ctx, conn, payload) {
+		return
+	}
 
 	nowMs := r.now().UnixMilli()
 	// Read the canonical room through the in-process cache (audit L802) so an
@@ -522,6 +605,12 @@ func (r *InboundRouter) applyStateChange(
 	// Episode/player/translation change reset playback_time=0 — invalidate so
 	// the drift engine sees the reset anchor immediately (audit L802).
 	r.roomCache.Invalidate(conn.RoomID)
+	// Skip votes were cast against the episode that just changed.
+	if _, ok := fields["episode_id"]; ok {
+		if err := r.repo.ClearSkipVotes(ctx, conn.RoomID); err != nil {
+			r.log.Warnw("watch_together clear skip votes", "room_id", conn.RoomID, "err", err)
+		}
+	}
 
 	out, err := buildEnvelope(domain.MsgRoomStateChanged, domain.RoomStateChangedData{
 		Field:    broadcastField,
@@ -715,7 +804,9 @@ func (r *InboundRouter) handleChangeTranslation(
 //
 // Order matters: char-cap check FIRST (drop oversized payloads before
 // they touch the rate limiter, so a spammer can't exhaust the limiter
-// with garbage), then rate-limit check, then persist + broadcast.
+// with garbage), then rate-limit check, then the protocol-1.1 mute /
+// slow-mode gate (last, so a rejected message never claims a slow-mode
+// window), then persist + broadcast.
 // Broadcast is to ALL (sender INCLUDED) — the sender's UI listens for
 // their own echo as the persistence confirmation per WT-FOUND-10
 // success criterion #4.
@@ -741,6 +832,10 @@ func (r *InboundRouter) handleChat(ctx context.Context, conn ConnectionCtx, data
 		return
 	}
 
+	if !r.allowChat(ctx, conn) {
+		return
+	}
+
 	msg := domain.ChatMessage{
 		ID:       r.newID(),
 		UserID:   conn.UserID,
@@ -761,6 +856,7 @@ func (r *InboundRouter) handleChat(ctx context.Context, conn ConnectionCtx, data
 		return
 	}
 	ChatMessagesTotal.Inc()
+	r.recordChat(ctx, conn, domain.ChatKindMessage, msg.ID, msg.Body, msg.TS)
 
 	out, err := buildEnvelope(domain.MsgChatMessageOut, domain.ChatMessageOutData{Message: msg})
 	if err != nil {
@@ -778,7 +874,8 @@ func (r *InboundRouter) handleChat(ctx context.Context, conn ConnectionCtx, data
 // ----------------------------------------------------------------------------
 // Handler — chat:reaction
 //
-// Ephemeral — NOT persisted in the Redis LIST. Whitelist-checked silently
+// NOT persisted in the Redis LIST (only in the durable chat log, when wired).
+// Whitelist-checked silently
 // (out-of-whitelist emoji is dropped without error). Broadcast to ALL
 // (sender included — gives a "yes my reaction landed" feedback signal).
 // ----------------------------------------------------------------------------
@@ -816,6 +913,7 @@ func (r *InboundRouter) handleReaction(ctx context.Context, conn ConnectionCtx,
 		return
 	}
 	ReactionsTotal.Inc()
+	r.recordChat(ctx, conn, domain.ChatKindReaction, r.newID(), payload.Emoji, r.now().UnixMilli())
 }
 
 // ----------------------------------------------------------------------------
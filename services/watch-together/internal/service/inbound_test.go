@@ -27,11 +27,12 @@ import (
 // real (we want their actual behavior exercised), constructed fresh per test.
 // ----------------------------------------------------------------------------
 
-// fakeHubCall captures one Broadcast or SendTo invocation.
+// fakeHubCall captures one Broadcast, SendTo or Disconnect invocation.
 type fakeHubCall struct {
-	method        string // "Broadcast" or "SendTo"
+	method        string // "Broadcast", "SendTo" or "Disconnect"
 	roomID        string
-	userID        string // SendTo only
+	userID        string // SendTo / Disconnect only
+	reason        string // Disconnect only
 	excludeUserID string // Broadcast only
 	env           domain.Envelope
 }
@@ -69,6 +70,20 @@ func (h *fakeHub) SendTo(_ context.Context, roomID, userID string, env domain.En
 	return 1, nil
 }
 
+// Disconnect makes fakeHub a HubEvictor too.
+func (h *fakeHub) Disconnect(_ context.Context, roomID, userID string, env domain.Envelope, reason string) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls = append(h.calls, fakeHubCall{
+		method: "Disconnect",
+		roomID: roomID,
+		userID: userID,
+		reason: reason,
+		env:    env,
+	})
+	return 1, nil
+}
+
 func (h *fakeHub) snapshot() []fakeHubCall {
 	h.mu.Lock()
 	defer h.mu.Unlock()
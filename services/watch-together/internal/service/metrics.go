@@ -75,6 +75,14 @@ var ReactionsTotal = prometheus.NewCounter(prometheus.CounterOpts{
 	Help: "Emoji reactions broadcast to watch-together rooms (not persisted)",
 })
 
+// ChatLogDroppedTotal counts chat-log entries that never reached Postgres:
+// enqueued while the write-behind queue was full, or in a batch whose INSERT
+// failed. The live room is unaffected; only the durable history has a gap.
+var ChatLogDroppedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "wt_chat_log_dropped_total",
+	Help: "Chat messages and reactions dropped by the durable chat-log write-behind",
+})
+
 // RoomsActive is the live gauge of currently-active watch-together rooms.
 // Bumped (+1) by RoomService.Create after a successful CreateRoom; bumped
 // (-1) by RoomService.Delete and GraceManager.fire after a successful
@@ -173,6 +181,7 @@ func init() {
 		RateLimitedTotal,
 		ChatMessagesTotal,
 		ReactionsTotal,
+		ChatLogDroppedTotal,
 		RoomsActive,
 		MembersPerRoom,
 		ChatMessagesPerRoom,
@@ -0,0 +1,438 @@
+// Package service — moderation.go holds the protocol-1.1 room controls:
+// the ControlMode gate in front of playback:* / state:change_*, the mute and
+// slow-mode gate in front of chat:message, and the host:* action handlers
+// (control mode, slow mode, delegates, mute, host transfer, kick, ban).
+//
+// Permission model:
+//   - ControlEveryone (default, and what pre-1.1 rooms read as) — any member
+//     drives playback, exactly like protocol 1.0.
+//   - ControlHostOnly — only the host.
+//   - ControlHostPlusDelegates — the host plus members in the delegates SET.
+//   - A room without a host (the host erased their account) falls back to
+//     ControlEveryone so nobody is locked out.
+//   - host:* actions are host-only regardless of mode.
+//
+// playback:time_tick is never gated: it is a drift probe, not a command. Its
+// client-reported duration only ends the episode (queue auto-advance) when
+// the sender could have driven playback anyway — see autoTransition.
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+// isControlType reports whether msgType is a playback / state command gated
+// by the room's ControlMode. queue:next counts: it changes the episode.
+func isControlType(msgType string) bool {
+	switch msgType {
+	case domain.MsgPlaybackPlay,
+		domain.MsgPlaybackPause,
+		domain.MsgPlaybackSeek,
+		domain.MsgStateChangeEpisode,
+		domain.MsgStateChangePlayer,
+		domain.MsgStateChangeTrans,
+		domain.MsgQueueNext:
+		return true
+	}
+	return false
+}
+
+// isPrivileged reports whether userID is the host or a delegate of room.
+// Repo errors count as not privileged.
+func (r *InboundRouter) isPrivileged(ctx context.Context, room *domain.Room, userID string) bool {
+	if room.HostUserID == userID {
+		return true
+	}
+	ok, err := r.repo.IsDelegate(ctx, room.ID, userID)
+	if err != nil {
+		r.log.Warnw("watch_together delegate check failed",
+			"room_id", room.ID,
+			"user_id", userID,
+			"err", err,
+		)
+		return false
+	}
+	return ok
+}
+
+// allowControl applies the ControlMode gate. On rejection the sender gets
+// CONTROL_NOT_ALLOWED and nothing else happens. The room is read through the
+// cache (every write below invalidates it); a missing room passes the gate
+// so the handler's own not-found path runs unchanged.
+func (r *InboundRouter) allowControl(ctx context.Context, conn ConnectionCtx) bool {
+	room, err := r.roomCache.GetRoom(ctx, conn.RoomID)
+	if err != nil {
+		return true
+	}
+	if r.canControl(ctx, room, conn.UserID) {
+		return true
+	}
+	mode := domain.EffectiveControlMode(room.ControlMode)
+	r.sendErrorToSelf(ctx, conn, domain.ErrCodeControlNotAllowed,
+		fmt.Sprintf("playback is controlled by the host (%s)", mode), "")
+	return false
+}
+
+// canControl reports whether userID may drive playback in room under its
+// ControlMode. Silent counterpart of allowControl, also used to decide whose
+// time_tick may end the episode.
+func (r *InboundRouter) canControl(ctx context.Context, room *domain.Room, userID string) bool {
+	mode := domain.EffectiveControlMode(room.ControlMode)
+	if mode == domain.ControlEveryone || room.HostUserID == "" || room.HostUserID == userID {
+		return true
+	}
+	return mode == domain.ControlHostPlusDelegates && r.isPrivileged(ctx, room, userID)
+}
+
+// allowChat applies mute and slow mode to chat:message. Host and delegates
+// are exempt from slow mode but not from mute (only the host can mute, and
+// never themselves). Repo errors fail open — chat is not worth dropping on
+// a Redis blip.
+func (r *InboundRouter) allowChat(ctx context.Context, conn ConnectionCtx) bool {
+	muted, err := r.repo.IsMuted(ctx, conn.RoomID, conn.UserID)
+	if err != nil {
+		r.log.Warnw("watch_together mute check failed",
+			"room_id", conn.RoomID,
+			"user_id", conn.UserID,
+			"err", err,
+		)
+	}
+	if muted {
+		r.sendErrorToSelf(ctx, conn, domain.ErrCodeMuted, "you are muted in this room", "")
+		return false
+	}
+
+	room, err := r.roomCache.GetRoom(ctx, conn.RoomID)
+	if err != nil || room.SlowModeSeconds <= 0 || r.isPrivileged(ctx, room, conn.UserID) {
+		return true
+	}
+	ok, wait, err := r.repo.TakeChatSlot(ctx, conn.RoomID, conn.UserID, time.Duration(room.SlowModeSeconds)*time.Second)
+	if err != nil {
+		r.log.Warnw("watch_together slow mode check failed",
+			"room_id", conn.RoomID,
+			"user_id", conn.UserID,
+			"err", err,
+		)
+		return true
+	}
+	if !ok {
+		RateLimitedTotal.WithLabelValues(domain.MsgChatMessage).Inc()
+		secs := int((wait + time.Second - 1) / time.Second)
+		r.sendErrorToSelf(ctx, conn, domain.ErrCodeSlowMode,
+			fmt.Sprintf("slow mode: one message every %ds", room.SlowModeSeconds),
+			strconv.Itoa(secs))
+		return false
+	}
+	return true
+}
+
+// requireHost loads the room fresh from Redis (host:* actions must not act
+// on a stale host id) and answers NOT_HOST to anyone else. A missing room is
+// a silent drop, like the state:change_* handlers.
+func (r *InboundRouter) requireHost(ctx context.Context, conn ConnectionCtx, msgType string) (*domain.Room, bool) {
+	room, err := r.repo.GetRoom(ctx, conn.RoomID)
+	if err != nil {
+		r.log.Debugw("watch_together host action get_room failed",
+			"room_id", conn.RoomID,
+			"user_id", conn.UserID,
+			"type", msgType,
+			"err", err,
+		)
+		return nil, false
+	}
+	if room.HostUserID != conn.UserID {
+		r.sendErrorToSelf(ctx, conn, domain.ErrCodeNotHost,
+			fmt.Sprintf("%s is a host action", msgType), "")
+		return nil, false
+	}
+	return room, true
+}
+
+// decodeTarget decodes a host:* payload and validates its target user:
+// non-empty and not the host themselves.
+func (r *InboundRouter) decodeTarget(ctx context.Context, conn ConnectionCtx, msgType string, data json.RawMessage, payload interface{}, target func() string) bool {
+	if err := json.Unmarshal(data, payload); err != nil {
+		r.sendBadPayload(ctx, conn, msgType, err)
+		return false
+	}
+	switch target() {
+	case "":
+		r.sendBadPayload(ctx, conn, msgType, fmt.Errorf("user_id must be non-empty"))
+		return false
+	case conn.UserID:
+		r.sendBadPayload(ctx, conn, msgType, fmt.Errorf("cannot target yourself"))
+		return false
+	}
+	return true
+}
+
+// requireMember answers MEMBER_NOT_FOUND when userID is not in the room.
+func (r *InboundRouter) requireMember(ctx context.Context, conn ConnectionCtx, userID string) bool {
+	ok, err := r.repo.IsRoomMember(ctx, conn.RoomID, userID)
+	if err != nil {
+		r.log.Warnw("watch_together member check failed",
+			"room_id", conn.RoomID,
+			"user_id", userID,
+			"err", err,
+		)
+		return false
+	}
+	if !ok {
+		r.sendErrorToSelf(ctx, conn, domain.ErrCodeMemberNotFound,
+			fmt.Sprintf("user %s is not in this room", userID), "")
+	}
+	return ok
+}
+
+// broadcastAll marshals body and fans it out to every member (sender
+// included, except excludeUserID when set).
+func (r *InboundRouter) broadcastAll(ctx context.Context, roomID, msgType string, body interface{}, excludeUserID string) {
+	out, err := buildEnvelope(msgType, body)
+	if err != nil {
+		r.log.Errorw("watch_together marshal moderation event", "type", msgType, "err", err)
+		return
+	}
+	if _, err := r.hub.Broadcast(ctx, roomID, out, excludeUserID); err != nil {
+		r.log.Warnw("watch_together broadcast moderation event",
+			"room_id", roomID,
+			"type", msgType,
+			"err", err,
+		)
+	}
+}
+
+// ----------------------------------------------------------------------------
+// Handlers — host:set_control_mode / host:set_slow_mode
+//
+// Both write one Room field and broadcast room:control_changed carrying the
+// full control settings, so a client never has to merge partial updates.
+// ----------------------------------------------------------------------------
+
+func (r *InboundRouter) handleSetControlMode(ctx context.Context, conn ConnectionCtx, data json.RawMessage) {
+	room, ok := r.requireHost(ctx, conn, domain.MsgHostSetControlMode)
+	if !ok {
+		return
+	}
+	var payload domain.HostSetControlModeData
+	if err := json.Unmarshal(data, &payload); err != nil {
+		r.sendBadPayload(ctx, conn, domain.MsgHostSetControlMode, err)
+		return
+	}
+	if !domain.ValidControlMode(payload.Mode) {
+		r.sendBadPayload(ctx, conn, domain.MsgHostSetControlMode,
+			fmt.Errorf("unknown mode %q", payload.Mode))
+		return
+	}
+	if err := r.repo.UpdateRoomState(ctx, conn.RoomID, map[string]interface{}{"control_mode": payload.Mode}); err != nil {
+		r.log.Errorw("watch_together update room (control mode)",
+			"room_id", conn.RoomID,
+			"err", err,
+		)
+		return
+	}
+	r.roomCache.Invalidate(conn.RoomID)
+
+	r.broadcastAll(ctx, conn.RoomID, domain.MsgRoomControlChanged, domain.RoomControlChangedData{
+		ControlMode:     payload.Mode,
+		SlowModeSeconds: room.SlowModeSeconds,
+		ByUserID:        conn.UserID,
+	}, "")
+}
+
+func (r *InboundRouter) handleSetSlowMode(ctx context.Context, conn ConnectionCtx, data json.RawMessage) {
+	room, ok := r.requireHost(ctx, conn, domain.MsgHostSetSlowMode)
+	if !ok {
+		return
+	}
+	var payload domain.HostSetSlowModeData
+	if err := json.Unmarshal(data, &payload); err != nil {
+		r.sendBadPayload(ctx, conn, domain.MsgHostSetSlowMode, err)
+		return
+	}
+	if payload.Seconds < 0 || payload.Seconds > domain.MaxSlowModeSeconds {
+		r.sendBadPayload(ctx, conn, domain.MsgHostSetSlowMode,
+			fmt.Errorf("seconds must be between 0 and %d", domain.MaxSlowModeSeconds))
+		return
+	}
+	if err := r.repo.UpdateRoomState(ctx, conn.RoomID, map[string]interface{}{"slow_mode_seconds": payload.Seconds}); err != nil {
+		r.log.Errorw("watch_together update room (slow mode)",
+			"room_id", conn.RoomID,
+			"err", err,
+		)
+		return
+	}
+	r.roomCache.Invalidate(conn.RoomID)
+
+	r.broadcastAll(ctx, conn.RoomID, domain.MsgRoomControlChanged, domain.RoomControlChangedData{
+		ControlMode:     domain.EffectiveControlMode(room.ControlMode),
+		SlowModeSeconds: payload.Seconds,
+		ByUserID:        conn.UserID,
+	}, "")
+}
+
+// ----------------------------------------------------------------------------
+// Handlers — host:set_delegate / host:mute
+//
+// Grants (delegate=true, muted=true) require the target to be in the room;
+// revocations do not, so a host can clean up after someone who already left.
+// ----------------------------------------------------------------------------
+
+func (r *InboundRouter) handleSetDelegate(ctx context.Context, conn ConnectionCtx, data json.RawMessage) {
+	if _, ok := r.requireHost(ctx, conn, domain.MsgHostSetDelegate); !ok {
+		return
+	}
+	var payload domain.HostSetDelegateData
+	if !r.decodeTarget(ctx, conn, domain.MsgHostSetDelegate, data, &payload, func() string { return payload.UserID }) {
+		return
+	}
+	if payload.Delegate && !r.requireMember(ctx, conn, payload.UserID) {
+		return
+	}
+	if _, err := r.repo.SetDelegate(ctx, conn.RoomID, payload.UserID, payload.Delegate); err != nil {
+		r.log.Errorw("watch_together set delegate", "room_id", conn.RoomID, "err", err)
+		return
+	}
+	r.broadcastAll(ctx, conn.RoomID, domain.MsgMemberRoleChanged, domain.MemberRoleChangedData{
+		UserID:   payload.UserID,
+		Delegate: payload.Delegate,
+		ByUserID: conn.UserID,
+	}, "")
+}
+
+func (r *InboundRouter) handleMute(ctx context.Context, conn ConnectionCtx, data json.RawMessage) {
+	if _, ok := r.requireHost(ctx, conn, domain.MsgHostMute); !ok {
+		return
+	}
+	var payload domain.HostMuteData
+	if !r.decodeTarget(ctx, conn, domain.MsgHostMute, data, &payload, func() string { return payload.UserID }) {
+		return
+	}
+	if payload.Muted && !r.requireMember(ctx, conn, payload.UserID) {
+		return
+	}
+	if _, err := r.repo.SetMuted(ctx, conn.RoomID, payload.UserID, payload.Muted); err != nil {
+		r.log.Errorw("watch_together set muted", "room_id", conn.RoomID, "err", err)
+		return
+	}
+	r.broadcastAll(ctx, conn.RoomID, domain.MsgMemberMuted, domain.MemberMutedData{
+		UserID:   payload.UserID,
+		Muted:    payload.Muted,
+		ByUserID: conn.UserID,
+	}, "")
+}
+
+// ----------------------------------------------------------------------------
+// Handler — host:transfer
+//
+// The new host must be connected. Invalidates the room cache so the control
+// gate sees the new host on the very next command.
+// ----------------------------------------------------------------------------
+
+func (r *InboundRouter) handleTransferHost(ctx context.Context, conn ConnectionCtx, data json.RawMessage) {
+	if _, ok := r.requireHost(ctx, conn, domain.MsgHostTransfer); !ok {
+		return
+	}
+	var payload domain.HostTargetData
+	if !r.decodeTarget(ctx, conn, domain.MsgHostTransfer, data, &payload, func() string { return payload.UserID }) {
+		return
+	}
+	if !r.requireMember(ctx, conn, payload.UserID) {
+		return
+	}
+	if err := r.repo.TransferHost(ctx, conn.RoomID, payload.UserID); err != nil {
+		r.log.Errorw("watch_together transfer host", "room_id", conn.RoomID, "err", err)
+		return
+	}
+	r.roomCache.Invalidate(conn.RoomID)
+
+	r.broadcastAll(ctx, conn.RoomID, domain.MsgRoomHostChanged, domain.RoomHostChangedData{
+		HostUserID:         payload.UserID,
+		PreviousHostUserID: conn.UserID,
+		ByUserID:           conn.UserID,
+	}, "")
+}
+
+// ----------------------------------------------------------------------------
+// Handlers — host:kick / host:ban / host:unban
+//
+// Kick needs the target in the room; ban does not (a host may pre-empt a
+// known troll). Everyone else gets member:kicked by broadcast; the target
+// gets the same envelope as the final frame on each of their sockets, then
+// the hub closes them and the usual member:left teardown follows. A kicked
+// user may rejoin; a banned one is refused at WS upgrade until host:unban.
+// ----------------------------------------------------------------------------
+
+func (r *InboundRouter) handleKick(ctx context.Context, conn ConnectionCtx, data json.RawMessage, ban bool) {
+	msgType, reason := domain.MsgHostKick, domain.ErrCodeKicked
+	if ban {
+		msgType, reason = domain.MsgHostBan, domain.ErrCodeBanned
+	}
+	if _, ok := r.requireHost(ctx, conn, msgType); !ok {
+		return
+	}
+	var payload domain.HostTargetData
+	if !r.decodeTarget(ctx, conn, msgType, data, &payload, func() string { return payload.UserID }) {
+		return
+	}
+	if ban {
+		if err := r.repo.Ban(ctx, conn.RoomID, payload.UserID); err != nil {
+			r.log.Errorw("watch_together ban", "room_id", conn.RoomID, "err", err)
+			return
+		}
+	} else if !r.requireMember(ctx, conn, payload.UserID) {
+		return
+	}
+
+	body := domain.MemberKickedData{UserID: payload.UserID, Banned: ban, ByUserID: conn.UserID}
+	r.broadcastAll(ctx, conn.RoomID, domain.MsgMemberKicked, body, payload.UserID)
+
+	if r.evictor == nil {
+		r.log.Warnw("watch_together kick without evictor — target stays connected",
+			"room_id", conn.RoomID,
+			"user_id", payload.UserID,
+		)
+		return
+	}
+	final, err := buildEnvelope(domain.MsgMemberKicked, body)
+	if err != nil {
+		r.log.Errorw("watch_together marshal member_kicked", "err", err)
+		return
+	}
+	if _, err := r.evictor.Disconnect(ctx, conn.RoomID, payload.UserID, final, reason); err != nil {
+		r.log.Warnw("watch_together disconnect kicked member",
+			"room_id", conn.RoomID,
+			"user_id", payload.UserID,
+			"err", err,
+		)
+	}
+	r.log.Infow("watch_together member removed",
+		"room_id", conn.RoomID,
+		"user_id", payload.UserID,
+		"by_user_id", conn.UserID,
+		"banned", ban,
+	)
+}
+
+func (r *InboundRouter) handleUnban(ctx context.Context, conn ConnectionCtx, data json.RawMessage) {
+	if _, ok := r.requireHost(ctx, conn, domain.MsgHostUnban); !ok {
+		return
+	}
+	var payload domain.HostTargetData
+	if !r.decodeTarget(ctx, conn, domain.MsgHostUnban, data, &payload, func() string { return payload.UserID }) {
+		return
+	}
+	if _, err := r.repo.Unban(ctx, conn.RoomID, payload.UserID); err != nil {
+		r.log.Errorw("watch_together unban", "room_id", conn.RoomID, "err", err)
+		return
+	}
+	r.broadcastAll(ctx, conn.RoomID, domain.MsgMemberUnbanned, domain.MemberUnbannedData{
+		UserID:   payload.UserID,
+		ByUserID: conn.UserID,
+	}, "")
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+// ----------------------------------------------------------------------------
+// Protocol 1.1 — control modes and host moderation. Same fixture as
+// inbound_test.go: real miniredis-backed repo, fakeHub (which also records
+// Disconnect calls as a HubEvictor).
+// ----------------------------------------------------------------------------
+
+func hostConn(roomID string) ConnectionCtx {
+	return ConnectionCtx{RoomID: roomID, UserID: "host", Username: "Host"}
+}
+
+// errorCodesTo returns every error code SendTo'd to userID, in order.
+func (h *fakeHub) errorCodesTo(userID string) []string {
+	var out []string
+	for _, c := range h.snapshot() {
+		if c.method != "SendTo" || c.userID != userID || c.env.Type != domain.MsgError {
+			continue
+		}
+		var e domain.ErrorData
+		_ = json.Unmarshal(c.env.Data, &e)
+		out = append(out, e.Code)
+	}
+	return out
+}
+
+func hasCode(codes []string, want string) bool {
+	for _, c := range codes {
+		if c == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestModeration_HostOnly_RejectsMemberPlayback(t *testing.T) {
+	fx := newRouterFixture(t)
+	roomID := "room-m1"
+	room := fx.defaultRoom(roomID)
+	room.ControlMode = domain.ControlHostOnly
+	fx.seedRoom(t, room)
+
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgPlaybackPlay, map[string]interface{}{"time": 10.0})
+
+	if !hasCode(fx.hub.errorCodesTo("alice"), domain.ErrCodeControlNotAllowed) {
+		t.Fatalf("want CONTROL_NOT_ALLOWED to alice; calls=%v", fx.hub.snapshot())
+	}
+	got, _ := fx.repo.GetRoom(context.Background(), roomID)
+	if got.PlaybackState != domain.StatePaused {
+		t.Fatalf("PlaybackState = %q, want paused (rejected command must not mutate)", got.PlaybackState)
+	}
+
+	// The host still drives playback.
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgPlaybackPlay, map[string]interface{}{"time": 10.0})
+	if _, ok := fx.hub.findFirst("Broadcast", domain.MsgPlaybackEvent); !ok {
+		t.Fatal("host play was not broadcast")
+	}
+}
+
+func TestModeration_LegacyRoom_DefaultsToEveryone(t *testing.T) {
+	fx := newRouterFixture(t)
+	roomID := "room-m2"
+	fx.seedRoom(t, fx.defaultRoom(roomID)) // ControlMode "" — a pre-1.1 room
+
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgPlaybackSeek, map[string]interface{}{"time": 5.0})
+
+	if _, ok := fx.hub.findFirst("Broadcast", domain.MsgPlaybackEvent); !ok {
+		t.Fatalf("member seek should pass in a legacy room; calls=%v", fx.hub.snapshot())
+	}
+}
+
+func TestModeration_Delegate_CanControlUnderHostPlusDelegates(t *testing.T) {
+	fx := newRouterFixture(t)
+	roomID := "room-m3"
+	fx.seedRoom(t, fx.defaultRoom(roomID))
+	fx.seedMember(t, roomID, "alice", domain.MemberMeta{Username: "Alice"})
+
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgHostSetControlMode,
+		domain.HostSetControlModeData{Mode: domain.ControlHostPlusDelegates})
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgPlaybackPause, map[string]interface{}{"time": 1.0})
+	if !hasCode(fx.hub.errorCodesTo("alice"), domain.ErrCodeControlNotAllowed) {
+		t.Fatal("non-delegate pause should be rejected")
+	}
+
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgHostSetDelegate,
+		domain.HostSetDelegateData{UserID: "alice", Delegate: true})
+	if _, ok := fx.hub.findFirst("Broadcast", domain.MsgMemberRoleChanged); !ok {
+		t.Fatal("no member:role_changed broadcast")
+	}
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgPlaybackPause, map[string]interface{}{"time": 2.0})
+	if _, ok := fx.hub.findFirst("Broadcast", domain.MsgPlaybackEvent); !ok {
+		t.Fatal("delegate pause should be broadcast")
+	}
+
+	call, _ := fx.hub.findFirst("Broadcast", domain.MsgRoomControlChanged)
+	var changed domain.RoomControlChangedData
+	_ = json.Unmarshal(call.env.Data, &changed)
+	if changed.ControlMode != domain.ControlHostPlusDelegates || changed.ByUserID != "host" {
+		t.Fatalf("room:control_changed = %+v", changed)
+	}
+}
+
+func TestModeration_HostActions_RequireHost(t *testing.T) {
+	fx := newRouterFixture(t)
+	roomID := "room-m4"
+	fx.seedRoom(t, fx.defaultRoom(roomID))
+	fx.seedMember(t, roomID, "bob", domain.MemberMeta{Username: "Bob"})
+
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgHostKick, domain.HostTargetData{UserID: "bob"})
+
+	if !hasCode(fx.hub.errorCodesTo("alice"), domain.ErrCodeNotHost) {
+		t.Fatalf("want NOT_HOST; calls=%v", fx.hub.snapshot())
+	}
+	if _, ok := fx.hub.findFirst("Disconnect", domain.MsgMemberKicked); ok {
+		t.Fatal("non-host kick must not disconnect anyone")
+	}
+}
+
+func TestModeration_Kick_BroadcastsAndDisconnects(t *testing.T) {
+	fx := newRouterFixture(t)
+	roomID := "room-m5"
+	fx.seedRoom(t, fx.defaultRoom(roomID))
+	fx.seedMember(t, roomID, "bob", domain.MemberMeta{Username: "Bob"})
+
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgHostKick, domain.HostTargetData{UserID: "bob"})
+
+	bc, ok := fx.hub.findFirst("Broadcast", domain.MsgMemberKicked)
+	if !ok || bc.excludeUserID != "bob" {
+		t.Fatalf("member:kicked broadcast = %+v (found %v), want excluding bob", bc, ok)
+	}
+	dc, ok := fx.hub.findFirst("Disconnect", domain.MsgMemberKicked)
+	if !ok || dc.userID != "bob" || dc.reason != domain.ErrCodeKicked {
+		t.Fatalf("disconnect = %+v (found %v), want bob/KICKED", dc, ok)
+	}
+	if banned, _ := fx.repo.IsBanned(context.Background(), roomID, "bob"); banned {
+		t.Fatal("kick must not ban")
+	}
+
+	// Kicking someone who is not in the room is reported back.
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgHostKick, domain.HostTargetData{UserID: "ghost"})
+	if !hasCode(fx.hub.errorCodesTo("host"), domain.ErrCodeMemberNotFound) {
+		t.Fatal("want MEMBER_NOT_FOUND for an absent target")
+	}
+}
+
+func TestModeration_Ban_PersistsAndUnbans(t *testing.T) {
+	fx := newRouterFixture(t)
+	roomID := "room-m6"
+	fx.seedRoom(t, fx.defaultRoom(roomID))
+	ctx := context.Background()
+
+	// Banning works for users not currently in the room.
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgHostBan, domain.HostTargetData{UserID: "troll"})
+	if banned, _ := fx.repo.IsBanned(ctx, roomID, "troll"); !banned {
+		t.Fatal("troll should be banned")
+	}
+	dc, ok := fx.hub.findFirst("Disconnect", domain.MsgMemberKicked)
+	if !ok || dc.reason != domain.ErrCodeBanned {
+		t.Fatalf("disconnect = %+v (found %v), want BANNED", dc, ok)
+	}
+
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgHostUnban, domain.HostTargetData{UserID: "troll"})
+	if banned, _ := fx.repo.IsBanned(ctx, roomID, "troll"); banned {
+		t.Fatal("troll should be unbanned")
+	}
+
+	// The host cannot target themselves.
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgHostBan, domain.HostTargetData{UserID: "host"})
+	if !hasCode(fx.hub.errorCodesTo("host"), errCodeBadPayload) {
+		t.Fatal("self-ban should be rejected as BAD_PAYLOAD")
+	}
+}
+
+func TestModeration_Mute_RejectsChat(t *testing.T) {
+	fx := newRouterFixture(t)
+	roomID := "room-m7"
+	fx.seedRoom(t, fx.defaultRoom(roomID))
+	fx.seedMember(t, roomID, "alice", domain.MemberMeta{Username: "Alice"})
+
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgHostMute, domain.HostMuteData{UserID: "alice", Muted: true})
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgChatMessage, map[string]interface{}{"body": "spam"})
+
+	if !hasCode(fx.hub.errorCodesTo("alice"), domain.ErrCodeMuted) {
+		t.Fatalf("want MUTED; calls=%v", fx.hub.snapshot())
+	}
+	if msgs, _ := fx.repo.GetMessages(context.Background(), roomID, 10); len(msgs) != 0 {
+		t.Fatalf("muted chat persisted: %+v", msgs)
+	}
+}
+
+func TestModeration_SlowMode_LimitsMembersNotHost(t *testing.T) {
+	fx := newRouterFixture(t)
+	roomID := "room-m8"
+	fx.seedRoom(t, fx.defaultRoom(roomID))
+
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgHostSetSlowMode, domain.HostSetSlowModeData{Seconds: 30})
+
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgChatMessage, map[string]interface{}{"body": "one"})
+	fx.rl.Forget("alice") // isolate slow mode from the 5/sec limiter
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgChatMessage, map[string]interface{}{"body": "two"})
+	if !hasCode(fx.hub.errorCodesTo("alice"), domain.ErrCodeSlowMode) {
+		t.Fatalf("want SLOW_MODE on the second message; calls=%v", fx.hub.snapshot())
+	}
+
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgChatMessage, map[string]interface{}{"body": "a"})
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgChatMessage, map[string]interface{}{"body": "b"})
+	if codes := fx.hub.errorCodesTo("host"); len(codes) != 0 {
+		t.Fatalf("host is exempt from slow mode; got %v", codes)
+	}
+
+	msgs, _ := fx.repo.GetMessages(context.Background(), roomID, 10)
+	if len(msgs) != 3 {
+		t.Fatalf("len(msgs) = %d, want 3", len(msgs))
+	}
+
+	// Out-of-range values are rejected.
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgHostSetSlowMode, domain.HostSetSlowModeData{Seconds: domain.MaxSlowModeSeconds + 1})
+	if !hasCode(fx.hub.errorCodesTo("host"), errCodeBadPayload) {
+		t.Fatal("want BAD_PAYLOAD for slow mode above the cap")
+	}
+}
+
+func TestModeration_TransferHost(t *testing.T) {
+	fx := newRouterFixture(t)
+	roomID := "room-m9"
+	room := fx.defaultRoom(roomID)
+	room.ControlMode = domain.ControlHostOnly
+	fx.seedRoom(t, room)
+	fx.seedMember(t, roomID, "alice", domain.MemberMeta{Username: "Alice"})
+
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgHostTransfer, domain.HostTargetData{UserID: "alice"})
+
+	got, _ := fx.repo.GetRoom(context.Background(), roomID)
+	if got.HostUserID != "alice" {
+		t.Fatalf("HostUserID = %q, want alice", got.HostUserID)
+	}
+	call, ok := fx.hub.findFirst("Broadcast", domain.MsgRoomHostChanged)
+	if !ok {
+		t.Fatal("no room:host_changed broadcast")
+	}
+	var changed domain.RoomHostChangedData
+	_ = json.Unmarshal(call.env.Data, &changed)
+	if changed.HostUserID != "alice" || changed.PreviousHostUserID != "host" {
+		t.Fatalf("room:host_changed = %+v", changed)
+	}
+
+	// New host drives playback immediately; the old host is now a member.
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgPlaybackPlay, map[string]interface{}{"time": 3.0})
+	if codes := fx.hub.errorCodesTo("alice"); len(codes) != 0 {
+		t.Fatalf("new host got errors %v", codes)
+	}
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgPlaybackPause, map[string]interface{}{"time": 4.0})
+	if !hasCode(fx.hub.errorCodesTo("host"), domain.ErrCodeControlNotAllowed) {
+		t.Fatal("former host should be gated under host_only")
+	}
+}
@@ -0,0 +1,571 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/repo"
+)
+
+// Watch-party limits.
+const (
+	partyTitleMaxRunes = 120
+	partyMaxInvites    = 50
+	partyMaxLead       = 90 * 24 * time.Hour // furthest a party may be scheduled ahead
+	partyArchiveLimit  = 100                 // = the Redis chat list cap
+	partyTickTimeout   = 20 * time.Second
+	// partyOrphanGrace is how long a live party may sit without a Redis room
+	// before the sweep ends it — covers the gap between ClaimStart and
+	// SetRoom on another instance.
+	partyOrphanGrace = time.Minute
+)
+
+// PartyOptions carries the config the PartyService reads.
+type PartyOptions struct {
+	PublicBaseURL string
+	ReminderLead  time.Duration
+	TickInterval  time.Duration
+}
+
+// ScheduleInput is the transport-agnostic payload for PartyService.Schedule.
+type ScheduleInput struct {
+	Title         string
+	AnimeID       string
+	EpisodeID     string
+	Player        string
+	TranslationID string
+	ControlMode   string
+	StartsAt      time.Time
+	Invitees      []string
+}
+
+// PartyService owns scheduled watch parties: scheduling, invites and RSVPs
+// over REST, and a scheduler loop (Run) that sends reminders, materializes
+// each party's room at StartsAt and ends parties whose room is gone. Rooms
+// are created through RoomService so they are ordinary rooms with PartyID
+// set; ArchiveRoom (the RoomArchiver hook) copies attendance and chat to
+// Postgres when such a room is torn down.
+//
+// Every status transition is a conditional UPDATE in PartyRepo, so Run can
+// execute on every replica without double reminders or double rooms.
+type PartyService struct {
+	parties  *repo.PartyRepo
+	rooms    *repo.RoomRepo
+	roomSvc  *RoomService
+	catalog  CatalogValidator // nil skips episode validation
+	notifier *PartyNotifier
+	opts     PartyOptions
+	log      *logger.Logger
+
+	newID   func() string
+	newCode func() string
+	now     func() time.Time
+}
+
+// NewPartyService wires the service. catalog and notifier may be nil.
+func NewPartyService(parties *repo.PartyRepo, rooms *repo.RoomRepo, roomSvc *RoomService, catalog CatalogValidator, notifier *PartyNotifier, opts PartyOptions, log *logger.Logger) *PartyService {
+	if log == nil {
+		log = logger.Default()
+	}
+	return &PartyService{
+		parties:  parties,
+		rooms:    rooms,
+		roomSvc:  roomSvc,
+		catalog:  catalog,
+		notifier: notifier,
+		opts:     opts,
+		log:      log,
+		newID:    uuid.NewString,
+		newCode:  newInviteCode,
+		now:      time.Now,
+	}
+}
+
+// newInviteCode returns 24 hex chars of crypto randomness — the secret half
+// of an invite link.
+func newInviteCode() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return strings.ReplaceAll(uuid.NewString(), "-", "")[:24]
+	}
+	return hex.EncodeToString(b)
+}
+
+// partyURL is the page for a party; with a code it is the shareable invite
+// link.
+func (s *PartyService) partyURL(id, code string) string {
+	u := s.opts.PublicBaseURL + "/watch/party/" + id
+	if code != "" {
+		u += "?code=" + code
+	}
+	return u
+}
+
+// normalizeInvitees dedupes ids, drops the host and rejects non-UUIDs.
+func normalizeInvitees(hostID string, ids []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || id == hostID {
+			continue
+		}
+		if !uuidRe.MatchString(id) {
+			return nil, fmt.Errorf("%w: invalid invitee id %q", ErrInvalidInput, id)
+		}
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+// Schedule creates a party hosted by hostID and invites in.Invitees.
+func (s *PartyService) Schedule(ctx context.Context, hostID string, in ScheduleInput) (*domain.PartyView, error) {
+	if hostID == "" {
+		return nil, fmt.Errorf("%w: host user_id is required", ErrInvalidInput)
+	}
+	title := strings.TrimSpace(in.Title)
+	if title == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(title) > partyTitleMaxRunes {
+		return nil, fmt.Errorf("%w: title is longer than %d characters", ErrInvalidInput, partyTitleMaxRunes)
+	}
+	roomIn := CreateRoomInput{
+		AnimeID:       in.AnimeID,
+		EpisodeID:     in.EpisodeID,
+		Player:        in.Player,
+		TranslationID: in.TranslationID,
+		ControlMode:   in.ControlMode,
+	}
+	if err := roomIn.validate(); err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if !in.StartsAt.After(now) {
+		return nil, fmt.Errorf("%w: starts_at must be in the future", ErrInvalidInput)
+	}
+	if in.StartsAt.Sub(now) > partyMaxLead {
+		return nil, fmt.Errorf("%w: starts_at must be within 90 days", ErrInvalidInput)
+	}
+	invitees, err := normalizeInvitees(hostID, in.Invitees)
+	if err != nil {
+		return nil, err
+	}
+	if len(invitees) > partyMaxInvites {
+		return nil, fmt.Errorf("%w: at most %d invitees", ErrInvalidInput, partyMaxInvites)
+	}
+	if s.catalog != nil {
+		result, err := s.catalog.ValidateEpisode(ctx, in.AnimeID, in.Player, in.EpisodeID, in.TranslationID, "")
+		if err != nil {
+			return nil, apperrors.Wrap(err, apperrors.CodeUnavailable, "episode validation failed; retry")
+		}
+		if !result.Valid {
+			return nil, fmt.Errorf("%w: episode %s unavailable on %s", ErrInvalidInput, in.EpisodeID, in.Player)
+		}
+	}
+
+	p := &domain.WatchParty{
+		ID:            s.newID(),
+		HostUserID:    hostID,
+		Title:         title,
+		AnimeID:       in.AnimeID,
+		EpisodeID:     in.EpisodeID,
+		Player:        in.Player,
+		TranslationID: in.TranslationID,
+		ControlMode:   domain.EffectiveControlMode(in.ControlMode),
+		StartsAt:      in.StartsAt.UTC(),
+		InviteCode:    s.newCode(),
+		Status:        domain.PartyScheduled,
+	}
+	invites := make([]domain.WatchPartyInvite, 0, len(invitees))
+	for _, uid := range invitees {
+		invites = append(invites, domain.WatchPartyInvite{
+			PartyID: p.ID, UserID: uid, RSVP: domain.RSVPPending, InvitedAt: now,
+		})
+	}
+	if err := s.parties.CreateParty(ctx, p, invites); err != nil {
+		return nil, err
+	}
+	s.log.Infow("watch_together party scheduled",
+		"party_id", p.ID, "host_user_id", hostID, "starts_at", p.StartsAt, "invitees", len(invitees))
+	s.notifyAsync(*p, invitees, false)
+	return s.view(ctx, p, hostID)
+}
+
+// notifyAsync sends invite or reminder notifications off the request path.
+func (s *PartyService) notifyAsync(p domain.WatchParty, userIDs []string, reminder bool) {
+	if s.notifier == nil || len(userIDs) == 0 {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), partyTickTimeout)
+		defer cancel()
+		if reminder {
+			s.notifier.NotifyReminder(ctx, &p, s.partyURL(p.ID, ""), userIDs)
+			return
+		}
+		s.notifier.NotifyInvite(ctx, &p, s.partyURL(p.ID, ""), userIDs)
+	}()
+}
+
+// view builds the viewer-specific API shape.
+func (s *PartyService) view(ctx context.Context, p *domain.WatchParty, viewerID string) (*domain.PartyView, error) {
+	invites, err := s.parties.ListInvites(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	v := &domain.PartyView{WatchParty: *p, Invites: invites}
+	if v.Invites == nil {
+		v.Invites = []domain.WatchPartyInvite{}
+	}
+	for _, inv := range invites {
+		switch inv.RSVP {
+		case domain.RSVPGoing:
+			v.Going++
+		case domain.RSVPMaybe:
+			v.Maybe++
+		case domain.RSVPDeclined:
+			v.Declined++
+		}
+		if inv.UserID == viewerID {
+			v.MyRSVP = inv.RSVP
+		}
+	}
+	if viewerID == p.HostUserID {
+		v.InviteURL = s.partyURL(p.ID, p.InviteCode)
+	}
+	return v, nil
+}
+
+// load fetches a party the viewer may see: the host, an invitee, or anyone
+// holding the invite code. Everyone else gets NotFound so ids cannot be
+// probed.
+func (s *PartyService) load(ctx context.Context, viewerID, id, code string) (*domain.WatchParty, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, apperrors.NotFound("watch party")
+	}
+	p, err := s.parties.GetParty(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.HostUserID == viewerID {
+		return p, nil
+	}
+	if code != "" && subtle.ConstantTimeCompare([]byte(code), []byte(p.InviteCode)) == 1 {
+		return p, nil
+	}
+	invites, err := s.parties.ListInvites(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, inv := range invites {
+		if inv.UserID == viewerID {
+			return p, nil
+		}
+	}
+	return nil, apperrors.NotFound("watch party")
+}
+
+// Get returns one party as seen by viewerID.
+func (s *PartyService) Get(ctx context.Context, viewerID, id, code string) (*domain.PartyView, error) {
+	p, err := s.load(ctx, viewerID, id, code)
+	if err != nil {
+		return nil, err
+	}
+	return s.view(ctx, p, viewerID)
+}
+
+// ListMine returns the upcoming and live parties userID hosts or is invited
+// to.
+func (s *PartyService) ListMine(ctx context.Context, userID string) ([]domain.PartyView, error) {
+	parties, err := s.parties.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.PartyView, 0, len(parties))
+	for i := range parties {
+		v, err := s.view(ctx, &parties[i], userID)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *v)
+	}
+	return out, nil
+}
+
+// Invite adds userIDs to the host's party and notifies the new invitees.
+func (s *PartyService) Invite(ctx context.Context, hostID, id string, userIDs []string) (*domain.PartyView, error) {
+	p, err := s.load(ctx, hostID, id, "")
+	if err != nil {
+		return nil, err
+	}
+	if p.HostUserID != hostID {
+		return nil, apperrors.Forbidden("only the host can invite")
+	}
+	if p.Status != domain.PartyScheduled && p.Status != domain.PartyLive {
+		return nil, apperrors.New(apperrors.CodeConflict, "watch party is over")
+	}
+	ids, err := normalizeInvitees(hostID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.parties.ListInvites(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing)+len(ids) > partyMaxInvites {
+		return nil, fmt.Errorf("%w: at most %d invitees", ErrInvalidInput, partyMaxInvites)
+	}
+	added, err := s.parties.AddInvites(ctx, id, ids, s.now())
+	if err != nil {
+		return nil, err
+	}
+	s.notifyAsync(*p, added, false)
+	return s.view(ctx, p, hostID)
+}
+
+// RSVP records userID's answer. Users reach a party either through an
+// invite or through the invite link, in which case code must match.
+func (s *PartyService) RSVP(ctx context.Context, userID, id, code, rsvp string) (*domain.PartyView, error) {
+	if !domain.ValidRSVP(rsvp) {
+		return nil, fmt.Errorf("%w: rsvp must be going|maybe|declined", ErrInvalidInput)
+	}
+	p, err := s.load(ctx, userID, id, code)
+	if err != nil {
+		return nil, err
+	}
+	if p.HostUserID == userID {
+		return nil, fmt.Errorf("%w: the host does not rsvp", ErrInvalidInput)
+	}
+	if p.Status != domain.PartyScheduled && p.Status != domain.PartyLive {
+		return nil, apperrors.New(apperrors.CodeConflict, "watch party is over")
+	}
+	if err := s.parties.SetRSVP(ctx, id, userID, rsvp, s.now()); err != nil {
+		return nil, err
+	}
+	return s.view(ctx, p, userID)
+}
+
+// Cancel calls off a party that has not started yet. Host only.
+func (s *PartyService) Cancel(ctx context.Context, hostID, id string) error {
+	p, err := s.load(ctx, hostID, id, "")
+	if err != nil {
+		return err
+	}
+	if p.HostUserID != hostID {
+		return apperrors.Forbidden("only the host can cancel")
+	}
+	ok, err := s.parties.CancelParty(ctx, id, s.now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apperrors.New(apperrors.CodeConflict, "watch party already started or ended")
+	}
+	s.log.Infow("watch_together party cancelled", "party_id", id, "host_user_id", hostID)
+	return nil
+}
+
+// Archive returns an ended party's attendance and chat to anyone who could
+// see the party or who attended it.
+func (s *PartyService) Archive(ctx context.Context, viewerID, id, code string) (*domain.PartyArchive, error) {
+	p, err := s.load(ctx, viewerID, id, code)
+	if err != nil {
+		// NotFound may still be an attendee who joined through a shared
+		// room link; anything else is a real failure.
+		if appErr, ok := apperrors.IsAppError(err); !ok || appErr.Code != apperrors.CodeNotFound {
+			return nil, err
+		}
+	}
+	archive, aerr := s.parties.GetArchive(ctx, id)
+	if aerr != nil {
+		return nil, aerr
+	}
+	if p == nil {
+		attended := false
+		for _, a := range archive.Attendance {
+			if a.UserID == viewerID {
+				attended = true
+				break
+			}
+		}
+		if !attended {
+			return nil, apperrors.NotFound("watch party")
+		}
+	}
+	return archive, nil
+}
+
+// ArchiveRoom implements RoomArchiver: copies the room's attendance and chat
+// to Postgres and ends its party. Called just before the room's Redis keys
+// are deleted; failures are logged, never returned.
+func (s *PartyService) ArchiveRoom(ctx context.Context, room *domain.Room) {
+	if room == nil || room.PartyID == "" {
+		return
+	}
+	attendance, err := s.rooms.GetAttendance(ctx, room.ID)
+	if err != nil {
+		s.log.Warnw("watch_together party archive attendance", "party_id", room.PartyID, "room_id", room.ID, "err", err)
+	}
+	msgs, err := s.rooms.GetMessages(ctx, room.ID, partyArchiveLimit)
+	if err != nil {
+		s.log.Warnw("watch_together party archive messages", "party_id", room.PartyID, "room_id", room.ID, "err", err)
+	}
+	s.endParty(ctx, room.PartyID, attendance, msgs)
+}
+
+func (s *PartyService) endParty(ctx context.Context, partyID string, attendance map[string]domain.Attendee, msgs []domain.ChatMessage) {
+	rows := make([]domain.WatchPartyAttendance, 0, len(attendance))
+	for uid, a := range attendance {
+		rows = append(rows, domain.WatchPartyAttendance{
+			PartyID: partyID, UserID: uid, Username: a.Username, FirstJoined: time.Unix(a.FirstJoined, 0).UTC(),
+		})
+	}
+	archived := make([]domain.WatchPartyMessage, 0, len(msgs))
+	for _, m := range msgs {
+		archived = append(archived, domain.WatchPartyMessage{
+			PartyID: partyID, MessageID: m.ID, UserID: m.UserID, Username: m.Username, Body: m.Body,
+			SentAt: time.UnixMilli(m.TS).UTC(),
+		})
+	}
+	ended, err := s.parties.EndParty(ctx, partyID, s.now(), rows, archived)
+	if err != nil {
+		s.log.Errorw("watch_together party end failed", "party_id", partyID, "err", err)
+		return
+	}
+	if ended {
+		s.log.Infow("watch_together party ended",
+			"party_id", partyID, "attendance", len(rows), "messages", len(archived))
+	}
+}
+
+// Run drives Tick every TickInterval until ctx is cancelled.
+func (s *PartyService) Run(ctx context.Context) {
+	interval := s.opts.TickInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			tickCtx, cancel := context.WithTimeout(ctx, partyTickTimeout)
+			s.Tick(tickCtx)
+			cancel()
+		}
+	}
+}
+
+// Tick runs one scheduler pass: reminders, then room materialization, then
+// the sweep of live parties whose room expired unobserved.
+func (s *PartyService) Tick(ctx context.Context) {
+	now := s.now()
+	s.sendReminders(ctx, now)
+	s.startDue(ctx, now)
+	s.sweepOrphans(ctx, now)
+}
+
+func (s *PartyService) sendReminders(ctx context.Context, now time.Time) {
+	due, err := s.parties.DueReminders(ctx, now.Add(s.opts.ReminderLead))
+	if err != nil {
+		s.log.Warnw("watch_together party reminders", "err", err)
+		return
+	}
+	for i := range due {
+		p := due[i]
+		claimed, err := s.parties.MarkReminded(ctx, p.ID, now)
+		if err != nil || !claimed {
+			continue
+		}
+		invites, err := s.parties.ListInvites(ctx, p.ID)
+		if err != nil {
+			s.log.Warnw("watch_together party reminder invites", "party_id", p.ID, "err", err)
+			continue
+		}
+		recipients := []string{p.HostUserID}
+		for _, inv := range invites {
+			if inv.RSVP != domain.RSVPDeclined {
+				recipients = append(recipients, inv.UserID)
+			}
+		}
+		s.notifyAsync(p, recipients, true)
+	}
+}
+
+func (s *PartyService) startDue(ctx context.Context, now time.Time) {
+	due, err := s.parties.DueStarts(ctx, now)
+	if err != nil {
+		s.log.Warnw("watch_together party starts", "err", err)
+		return
+	}
+	for i := range due {
+		p := due[i]
+		claimed, err := s.parties.ClaimStart(ctx, p.ID, now)
+		if err != nil || !claimed {
+			continue
+		}
+		room, err := s.roomSvc.Create(ctx, p.HostUserID, "", CreateRoomInput{
+			AnimeID:       p.AnimeID,
+			EpisodeID:     p.EpisodeID,
+			Player:        p.Player,
+			TranslationID: p.TranslationID,
+			ControlMode:   p.ControlMode,
+			PartyID:       p.ID,
+		})
+		if err != nil {
+			// The party stays live without a room; sweepOrphans ends it.
+			s.log.Errorw("watch_together party materialize failed", "party_id", p.ID, "err", err)
+			continue
+		}
+		if err := s.parties.SetRoom(ctx, p.ID, room.ID); err != nil {
+			s.log.Errorw("watch_together party set room failed", "party_id", p.ID, "room_id", room.ID, "err", err)
+			continue
+		}
+		s.log.Infow("watch_together party started", "party_id", p.ID, "room_id", room.ID)
+	}
+}
+
+func (s *PartyService) sweepOrphans(ctx context.Context, now time.Time) {
+	live, err := s.parties.ListLive(ctx)
+	if err != nil {
+		s.log.Warnw("watch_together party sweep", "err", err)
+		return
+	}
+	for _, p := range live {
+		if p.StartedAt != nil && now.Sub(*p.StartedAt) < partyOrphanGrace {
+			continue
+		}
+		if p.RoomID != "" {
+			_, err := s.rooms.GetRoom(ctx, p.RoomID)
+			if err == nil {
+				continue
+			}
+			if !stderrors.Is(err, repo.ErrNotFound) {
+				s.log.Warnw("watch_together party sweep get room", "party_id", p.ID, "room_id", p.RoomID, "err", err)
+				continue
+			}
+		}
+		// The room expired by TTL (nobody stayed to trigger a grace
+		// teardown) — its keys are gone, so the archive is empty.
+		s.endParty(ctx, p.ID, nil, nil)
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+// Notification types emitted for watch parties. Mirrored by the
+// notifications service's TypeWatchParty* constants.
+const (
+	notifyTypePartyInvite   = "watch_party_invite"
+	notifyTypePartyReminder = "watch_party_reminder"
+)
+
+// uuidRe guards user ids: user_notifications.user_id is a Postgres uuid
+// column, so guest ids (which never receive invites anyway) are skipped.
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// PartyNotifier is the fire-and-forget producer for watch-party invites and
+// reminders. It POSTs to the notifications service's internal upsert over
+// the Docker network; every call is best-effort — a notifications outage
+// must never block scheduling or starting a party.
+type PartyNotifier struct {
+	baseURL string
+	enabled bool
+	client  *http.Client
+	log     *logger.Logger
+}
+
+// NewPartyNotifier constructs the producer. enabled=false turns every call
+// into a no-op.
+func NewPartyNotifier(baseURL string, enabled bool, log *logger.Logger) *PartyNotifier {
+	if log == nil {
+		log = logger.Default()
+	}
+	return &PartyNotifier{
+		baseURL: baseURL,
+		enabled: enabled,
+		client:  &http.Client{Timeout: 5 * time.Second},
+		log:     log,
+	}
+}
+
+// partyNotification is the payload shape of both party types.
+type partyNotification struct {
+	PartyID   string `json:"party_id"`
+	Title     string `json:"title"`
+	AnimeID   string `json:"anime_id"`
+	EpisodeID string `json:"episode_id"`
+	StartsAt  string `json:"starts_at"` // RFC 3339
+	URL       string `json:"url"`
+}
+
+// NotifyInvite tells each user they were invited to p.
+func (n *PartyNotifier) NotifyInvite(ctx context.Context, p *domain.WatchParty, url string, userIDs []string) {
+	n.dispatch(ctx, p, url, notifyTypePartyInvite, "invite", userIDs)
+}
+
+// NotifyReminder tells each user p starts soon.
+func (n *PartyNotifier) NotifyReminder(ctx context.Context, p *domain.WatchParty, url string, userIDs []string) {
+	n.dispatch(ctx, p, url, notifyTypePartyReminder, "reminder", userIDs)
+}
+
+func (n *PartyNotifier) dispatch(ctx context.Context, p *domain.WatchParty, url, ntype, stage string, userIDs []string) {
+	if n == nil || !n.enabled || n.baseURL == "" || p == nil {
+		return
+	}
+	payload, err := json.Marshal(partyNotification{
+		PartyID:   p.ID,
+		Title:     p.Title,
+		AnimeID:   p.AnimeID,
+		EpisodeID: p.EpisodeID,
+		StartsAt:  p.StartsAt.UTC().Format(time.RFC3339),
+		URL:       url,
+	})
+	if err != nil {
+		n.log.Errorw("watch party notify: marshal payload", "party_id", p.ID, "err", err)
+		return
+	}
+	for _, uid := range userIDs {
+		if !uuidRe.MatchString(uid) {
+			continue
+		}
+		body := map[string]interface{}{
+			"user_id":    uid,
+			"type":       ntype,
+			"dedupe_key": fmt.Sprintf("watch_party:%s:%s", p.ID, stage),
+			"payload":    json.RawMessage(payload),
+		}
+		if err := n.post(ctx, body); err != nil {
+			n.log.Warnw("watch party notify failed (non-fatal)",
+				"party_id", p.ID, "type", ntype, "user_id", uid, "err", err)
+		}
+	}
+}
+
+func (n *PartyNotifier) post(ctx context.Context, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.baseURL+"/internal/notifications", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, string(b))
+	}
+	return nil
+}
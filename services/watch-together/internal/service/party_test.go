@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/libs/logger"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/repo"
+)
+
+const (
+	partyHost     = "11111111-1111-1111-1111-111111111111"
+	partyGuest    = "22222222-2222-2222-2222-222222222222"
+	partyStranger = "33333333-3333-3333-3333-333333333333"
+)
+
+// partyFixture wires a PartyService over miniredis + in-memory SQLite with a
+// movable clock shared by the party and room services.
+type partyFixture struct {
+	svc     *PartyService
+	roomSvc *RoomService
+	parties *repo.PartyRepo
+	now     time.Time
+}
+
+func newPartyFixture(t *testing.T) *partyFixture {
+	t.Helper()
+	fx := &partyFixture{now: time.Date(2026, 10, 1, 19, 0, 0, 0, time.UTC)}
+	roomSvc, _ := newService(t, "party-room-1", fx.now)
+	roomSvc.now = func() time.Time { return fx.now }
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.WatchParty{}, &domain.WatchPartyInvite{},
+		&domain.WatchPartyAttendance{}, &domain.WatchPartyMessage{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	fx.parties = repo.NewPartyRepo(db)
+	fx.roomSvc = roomSvc
+	fx.svc = NewPartyService(fx.parties, roomSvc.repo, roomSvc, nil, nil, PartyOptions{
+		PublicBaseURL: "https://example.test",
+		ReminderLead:  15 * time.Minute,
+	}, logger.Default())
+	fx.svc.newID = func() string { return "aaaaaaaa-0000-0000-0000-000000000001" }
+	fx.svc.newCode = func() string { return "secret-code" }
+	fx.svc.now = func() time.Time { return fx.now }
+	roomSvc.SetArchiver(fx.svc)
+	return fx
+}
+
+func (fx *partyFixture) schedule(t *testing.T, invitees ...string) *domain.PartyView {
+	t.Helper()
+	v, err := fx.svc.Schedule(context.Background(), partyHost, ScheduleInput{
+		Title:         "Friday night",
+		AnimeID:       "anime-1",
+		EpisodeID:     "ep-1",
+		Player:        domain.PlayerKodik,
+		TranslationID: "610",
+		StartsAt:      fx.now.Add(time.Hour),
+		Invitees:      invitees,
+	})
+	if err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+	return v
+}
+
+func isNotFound(err error) bool {
+	appErr, ok := apperrors.IsAppError(err)
+	return ok && appErr.Code == apperrors.CodeNotFound
+}
+
+func TestSchedule_Validation(t *testing.T) {
+	fx := newPartyFixture(t)
+	base := ScheduleInput{
+		Title: "x", AnimeID: "a", EpisodeID: "1", Player: domain.PlayerKodik, TranslationID: "610",
+		StartsAt: fx.now.Add(time.Hour),
+	}
+	cases := map[string]func(in *ScheduleInput){
+		"blank title":     func(in *ScheduleInput) { in.Title = "  " },
+		"past start":      func(in *ScheduleInput) { in.StartsAt = fx.now.Add(-time.Minute) },
+		"too far ahead":   func(in *ScheduleInput) { in.StartsAt = fx.now.Add(91 * 24 * time.Hour) },
+		"unknown player":  func(in *ScheduleInput) { in.Player = "vlc" },
+		"bad invitee id":  func(in *ScheduleInput) { in.Invitees = []string{"guest-xyz"} },
+		"missing episode": func(in *ScheduleInput) { in.EpisodeID = "" },
+	}
+	for name, mutate := range cases {
+		in := base
+		mutate(&in)
+		if _, err := fx.svc.Schedule(context.Background(), partyHost, in); !stderrors.Is(err, ErrInvalidInput) {
+			t.Errorf("%s: err = %v, want ErrInvalidInput", name, err)
+		}
+	}
+}
+
+func TestParty_AccessInviteLinkAndRSVP(t *testing.T) {
+	fx := newPartyFixture(t)
+	ctx := context.Background()
+	v := fx.schedule(t, partyGuest)
+	if v.InviteURL != "https://example.test/watch/party/"+v.ID+"?code=secret-code" {
+		t.Fatalf("host InviteURL = %q", v.InviteURL)
+	}
+
+	guestView, err := fx.svc.Get(ctx, partyGuest, v.ID, "")
+	if err != nil || guestView.MyRSVP != domain.RSVPPending || guestView.InviteURL != "" {
+		t.Fatalf("invitee Get = %+v, %v", guestView, err)
+	}
+	if _, err := fx.svc.Get(ctx, partyStranger, v.ID, ""); !isNotFound(err) {
+		t.Fatalf("stranger Get err = %v, want NotFound", err)
+	}
+	if _, err := fx.svc.RSVP(ctx, partyStranger, v.ID, "wrong", domain.RSVPGoing); !isNotFound(err) {
+		t.Fatalf("RSVP with wrong code err = %v, want NotFound", err)
+	}
+	after, err := fx.svc.RSVP(ctx, partyStranger, v.ID, "secret-code", domain.RSVPGoing)
+	if err != nil || after.Going != 1 || after.MyRSVP != domain.RSVPGoing {
+		t.Fatalf("RSVP via link = %+v, %v", after, err)
+	}
+	// The link-joiner is now on the list and needs no code.
+	if _, err := fx.svc.Get(ctx, partyStranger, v.ID, ""); err != nil {
+		t.Fatalf("Get after RSVP: %v", err)
+	}
+	if _, err := fx.svc.Invite(ctx, partyGuest, v.ID, []string{partyStranger}); err == nil {
+		t.Fatal("non-host Invite should fail")
+	}
+	if err := fx.svc.Cancel(ctx, partyGuest, v.ID); err == nil {
+		t.Fatal("non-host Cancel should fail")
+	}
+}
+
+func TestParty_TickMaterializesOnceAndArchivesOnDelete(t *testing.T) {
+	fx := newPartyFixture(t)
+	ctx := context.Background()
+	v := fx.schedule(t, partyGuest)
+
+	fx.svc.Tick(ctx) // 60 min out: nothing due
+	if p, _ := fx.parties.GetParty(ctx, v.ID); p.ReminderSent != nil || p.Status != domain.PartyScheduled {
+		t.Fatalf("party touched too early: %+v", p)
+	}
+	fx.now = fx.now.Add(50 * time.Minute)
+	fx.svc.Tick(ctx)
+	if p, _ := fx.parties.GetParty(ctx, v.ID); p.ReminderSent == nil || p.Status != domain.PartyScheduled {
+		t.Fatalf("reminder not claimed 10 min before start: %+v", p)
+	}
+
+	fx.now = fx.now.Add(10 * time.Minute)
+	fx.svc.Tick(ctx)
+	fx.svc.Tick(ctx)
+	p, _ := fx.parties.GetParty(ctx, v.ID)
+	if p.Status != domain.PartyLive || p.RoomID != "party-room-1" {
+		t.Fatalf("party after start tick = %+v", p)
+	}
+	snap, err := fx.roomSvc.Get(ctx, p.RoomID)
+	if err != nil || snap.Room.PartyID != v.ID || snap.Room.HostUserID != partyHost {
+		t.Fatalf("materialized room = %+v, %v", snap, err)
+	}
+
+	rooms := fx.roomSvc.repo
+	if err := rooms.AddMember(ctx, p.RoomID, partyGuest, domain.MemberMeta{Username: "bob", JoinedAt: fx.now.Unix()}); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+	if err := rooms.RemoveMember(ctx, p.RoomID, partyGuest); err != nil {
+		t.Fatalf("RemoveMember: %v", err)
+	}
+	if err := rooms.AppendMessage(ctx, p.RoomID, domain.ChatMessage{ID: "m1", UserID: partyGuest, Username: "bob", Body: "hi", TS: fx.now.UnixMilli()}); err != nil {
+		t.Fatalf("AppendMessage: %v", err)
+	}
+
+	if err := fx.roomSvc.Delete(ctx, partyHost, p.RoomID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	p, _ = fx.parties.GetParty(ctx, v.ID)
+	if p.Status != domain.PartyEnded {
+		t.Fatalf("status after teardown = %s, want ended", p.Status)
+	}
+	archive, err := fx.svc.Archive(ctx, partyGuest, v.ID, "")
+	if err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+	// bob left before teardown but still counts as attended.
+	if len(archive.Attendance) != 1 || archive.Attendance[0].Username != "bob" ||
+		len(archive.Messages) != 1 || archive.Messages[0].Body != "hi" {
+		t.Fatalf("archive = %+v", archive)
+	}
+}
+
+func TestParty_SweepEndsPartyWhoseRoomExpired(t *testing.T) {
+	fx := newPartyFixture(t)
+	ctx := context.Background()
+	v := fx.schedule(t)
+	fx.now = fx.now.Add(time.Hour)
+	fx.svc.Tick(ctx)
+	p, _ := fx.parties.GetParty(ctx, v.ID)
+	if err := fx.roomSvc.repo.DeleteRoom(ctx, p.RoomID); err != nil {
+		t.Fatalf("DeleteRoom: %v", err)
+	}
+
+	fx.svc.Tick(ctx) // inside the orphan grace: left alone
+	if p, _ := fx.parties.GetParty(ctx, v.ID); p.Status != domain.PartyLive {
+		t.Fatalf("status inside grace = %s, want live", p.Status)
+	}
+	fx.now = fx.now.Add(2 * time.Minute)
+	fx.svc.Tick(ctx)
+	if p, _ := fx.parties.GetParty(ctx, v.ID); p.Status != domain.PartyEnded {
+		t.Fatalf("status after sweep = %s, want ended", p.Status)
+	}
+}
@@ -0,0 +1,404 @@
+// Package service — queue.go holds the protocol-1.1 episode queue: the
+// queue:* handlers, host:set_skip_op_ed, and the server-initiated
+// transitions driven by playback:time_tick (auto-advance at the episode end,
+// OP/ED skip).
+//
+// Queue model:
+//   - Items are catalog-validated on queue:add and play most-upvoted first,
+//     then oldest (repo.GetQueue).
+//   - Any member adds, votes and votes to skip; an item is removed by whoever
+//     added it or by the host. queue:next obeys the room's ControlMode like
+//     any other playback command.
+//   - A strict majority of the room's members skips the current episode.
+//   - Every queue change broadcasts the full queue (queue:updated); every
+//     advance broadcasts room:episode_advanced to all members.
+//
+// Server-initiated transitions judge the room's canonical position (anchor +
+// elapsed), not the reporting member's, so a lagging member cannot trigger a
+// second skip. A short Redis lock makes each transition fire once even
+// though every member's tick notices it.
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+// SkipTimesSource is the optional catalog capability behind skip_op_ed.
+// *CatalogClient implements it; NewInboundRouter picks it up by type
+// assertion so validation-only fakes need not grow the method. Without it
+// OP/ED skip is a no-op and only the duration-based auto-advance runs.
+type SkipTimesSource interface {
+	SkipTimes(ctx context.Context, malID string, episode int) (SkipTimes, error)
+}
+
+// autoTransitionLockTTL holds the per-room transition lock. Long enough to
+// swallow the burst of ticks from every member that notice the same end /
+// OP window, short enough not to block the next legitimate transition.
+const autoTransitionLockTTL = 3 * time.Second
+
+// autoAdvanceEndSlack treats the last seconds of an episode as its end:
+// players often stop ticking (or report a rounded-down time) just before
+// the media's nominal duration.
+const autoAdvanceEndSlack = 2.0
+
+// skipWindowSlack keeps a seek target from landing back inside the window it
+// skips (the next tick reports a time a hair before the end).
+const skipWindowSlack = 0.5
+
+// skipVotesNeeded is a strict majority of members (at least one vote).
+func skipVotesNeeded(members int) int {
+	return members/2 + 1
+}
+
+// broadcastQueue sends the full queue plus the skip tally to every member.
+func (r *InboundRouter) broadcastQueue(ctx context.Context, roomID string) {
+	items, err := r.repo.GetQueue(ctx, roomID)
+	if err != nil {
+		r.log.Warnw("watch_together get queue", "room_id", roomID, "err", err)
+		return
+	}
+	votes, _ := r.repo.SkipVoteCount(ctx, roomID)
+	members, _ := r.repo.CountMembers(ctx, roomID)
+	r.broadcastAll(ctx, roomID, domain.MsgQueueUpdated, domain.QueueUpdatedData{
+		Items:           items,
+		SkipVotes:       votes,
+		SkipVotesNeeded: skipVotesNeeded(members),
+	}, "")
+}
+
+// advanceQueue moves the room to the queue head. Returns false when the
+// queue is empty or the transition lock is held (another advance just ran).
+func (r *InboundRouter) advanceQueue(ctx context.Context, roomID, reason, byUserID string) bool {
+	locked, err := r.repo.TryAutoLock(ctx, roomID, autoTransitionLockTTL)
+	if err != nil || !locked {
+		return false
+	}
+	items, err := r.repo.GetQueue(ctx, roomID)
+	if err != nil || len(items) == 0 {
+		return false
+	}
+	head := items[0]
+	nowMs := r.now().UnixMilli()
+	if err := r.repo.AdvanceTo(ctx, roomID, head, nowMs); err != nil {
+		r.log.Errorw("watch_together advance queue",
+			"room_id", roomID,
+			"item_id", head.ID,
+			"err", err,
+		)
+		return false
+	}
+	// New episode and playback anchor — the drift engine must see them on
+	// the very next tick.
+	r.roomCache.Invalidate(roomID)
+
+	r.broadcastAll(ctx, roomID, domain.MsgRoomEpisodeAdvanced, domain.RoomEpisodeAdvancedData{
+		ItemID:        head.ID,
+		AnimeID:       head.AnimeID,
+		EpisodeID:     head.EpisodeID,
+		Player:        head.Player,
+		TranslationID: head.TranslationID,
+		Reason:        reason,
+		ByUserID:      byUserID,
+		ServerTS:      nowMs,
+	}, "")
+	r.broadcastQueue(ctx, roomID)
+	return true
+}
+
+// ----------------------------------------------------------------------------
+// Handlers — queue:add / queue:remove / queue:vote
+// ----------------------------------------------------------------------------
+
+func (r *InboundRouter) handleQueueAdd(ctx context.Context, conn ConnectionCtx, data json.RawMessage) {
+	var payload domain.QueueAddData
+	if err := json.Unmarshal(data, &payload); err != nil {
+		r.sendBadPayload(ctx, conn, domain.MsgQueueAdd, err)
+		return
+	}
+	if payload.EpisodeID == "" {
+		r.sendBadPayload(ctx, conn, domain.MsgQueueAdd, fmt.Errorf("episode_id must be non-empty"))
+		return
+	}
+	room, err := r.roomCache.GetRoom(ctx, conn.RoomID)
+	if err != nil {
+		r.log.Debugw("watch_together queue_add get_room failed",
+			"room_id", conn.RoomID,
+			"user_id", conn.UserID,
+			"err", err,
+		)
+		return
+	}
+	item := domain.QueueItem{
+		ID:            r.newID(),
+		AnimeID:       payload.AnimeID,
+		EpisodeID:     payload.EpisodeID,
+		Player:        payload.Player,
+		TranslationID: payload.TranslationID,
+		AddedBy:       conn.UserID,
+		AddedAt:       r.now().UnixMilli(),
+	}
+	if item.AnimeID == "" {
+		item.AnimeID = room.AnimeID
+	}
+	if item.Player == "" {
+		item.Player = room.Player
+	}
+	if item.TranslationID == "" && item.Player == room.Player {
+		item.TranslationID = room.TranslationID
+	}
+
+	// Same contract as state:change_episode: never queue what the catalog
+	// cannot play, and refuse on transport failure rather than guess.
+	result, err := r.catalog.ValidateEpisode(ctx,
+		item.AnimeID, item.Player, item.EpisodeID, item.TranslationID, "")
+	if err != nil {
+		r.log.Warnw("watch_together queue_add catalog transport error",
+			"room_id", conn.RoomID,
+			"user_id", conn.UserID,
+			"err", err,
+		)
+		r.sendErrorToSelf(ctx, conn, domain.ErrCodeEpisodeUnavailable, "upstream validation failed; retry", "")
+		return
+	}
+	if !result.Valid {
+		code := mapValidationReason(result.Reason, domain.ErrCodeEpisodeUnavailable)
+		r.sendErrorToSelf(ctx, conn, code,
+			fmt.Sprintf("episode %s unavailable on %s/%s", item.EpisodeID, item.Player, item.TranslationID), "")
+		return
+	}
+
+	added, err := r.repo.AddQueueItem(ctx, conn.RoomID, item)
+	if err != nil {
+		r.log.Errorw("watch_together queue_add", "room_id", conn.RoomID, "err", err)
+		return
+	}
+	if !added {
+		r.sendErrorToSelf(ctx, conn, domain.ErrCodeQueueFull,
+			fmt.Sprintf("queue holds at most %d items", domain.QueueMaxItems), "")
+		return
+	}
+	r.broadcastQueue(ctx, conn.RoomID)
+}
+
+func (r *InboundRouter) handleQueueRemove(ctx context.Context, conn ConnectionCtx, data json.RawMessage) {
+	var payload domain.QueueItemRefData
+	if err := json.Unmarshal(data, &payload); err != nil || payload.ItemID == "" {
+		r.sendBadPayload(ctx, conn, domain.MsgQueueRemove, fmt.Errorf("item_id must be non-empty"))
+		return
+	}
+	item, err := r.repo.GetQueueItem(ctx, conn.RoomID, payload.ItemID)
+	if err != nil {
+		r.log.Warnw("watch_together queue_remove lookup", "room_id", conn.RoomID, "err", err)
+		return
+	}
+	if item == nil {
+		r.sendErrorToSelf(ctx, conn, domain.ErrCodeQueueItemNotFound, "queue item not found", "")
+		return
+	}
+	if item.AddedBy != conn.UserID {
+		room, err := r.repo.GetRoom(ctx, conn.RoomID)
+		if err != nil {
+			return
+		}
+		if room.HostUserID != conn.UserID {
+			r.sendErrorToSelf(ctx, conn, domain.ErrCodeNotHost,
+				"only the host or the member who queued it may remove an item", "")
+			return
+		}
+	}
+	if _, err := r.repo.RemoveQueueItem(ctx, conn.RoomID, payload.ItemID); err != nil {
+		r.log.Errorw("watch_together queue_remove", "room_id", conn.RoomID, "err", err)
+		return
+	}
+	r.broadcastQueue(ctx, conn.RoomID)
+}
+
+func (r *InboundRouter) handleQueueVote(ctx context.Context, conn ConnectionCtx, data json.RawMessage) {
+	var payload domain.QueueVoteData
+	if err := json.Unmarshal(data, &payload); err != nil || payload.ItemID == "" {
+		r.sendBadPayload(ctx, conn, domain.MsgQueueVote, fmt.Errorf("item_id must be non-empty"))
+		return
+	}
+	found, err := r.repo.VoteQueueItem(ctx, conn.RoomID, payload.ItemID, conn.UserID, payload.Up)
+	if err != nil {
+		r.log.Errorw("watch_together queue_vote", "room_id", conn.RoomID, "err", err)
+		return
+	}
+	if !found {
+		r.sendErrorToSelf(ctx, conn, domain.ErrCodeQueueItemNotFound, "queue item not found", "")
+		return
+	}
+	r.broadcastQueue(ctx, conn.RoomID)
+}
+
+// ----------------------------------------------------------------------------
+// Handlers — queue:vote_skip / queue:next / host:set_skip_op_ed
+// ----------------------------------------------------------------------------
+
+func (r *InboundRouter) handleQueueVoteSkip(ctx context.Context, conn ConnectionCtx) {
+	items, err := r.repo.GetQueue(ctx, conn.RoomID)
+	if err != nil {
+		r.log.Warnw("watch_together vote_skip get queue", "room_id", conn.RoomID, "err", err)
+		return
+	}
+	if len(items) == 0 {
+		r.sendErrorToSelf(ctx, conn, domain.ErrCodeQueueEmpty, "nothing queued to skip to", "")
+		return
+	}
+	votes, err := r.repo.AddSkipVote(ctx, conn.RoomID, conn.UserID)
+	if err != nil {
+		r.log.Errorw("watch_together vote_skip", "room_id", conn.RoomID, "err", err)
+		return
+	}
+	members, err := r.repo.CountMembers(ctx, conn.RoomID)
+	if err == nil && votes >= skipVotesNeeded(members) && r.advanceQueue(ctx, conn.RoomID, domain.AdvanceVoteSkip, "") {
+		return
+	}
+	r.broadcastQueue(ctx, conn.RoomID)
+}
+
+func (r *InboundRouter) handleQueueNext(ctx context.Context, conn ConnectionCtx) {
+	items, err := r.repo.GetQueue(ctx, conn.RoomID)
+	if err != nil {
+		r.log.Warnw("watch_together queue_next get queue", "room_id", conn.RoomID, "err", err)
+		return
+	}
+	if len(items) == 0 {
+		r.sendErrorToSelf(ctx, conn, domain.ErrCodeQueueEmpty, "nothing queued", "")
+		return
+	}
+	r.advanceQueue(ctx, conn.RoomID, domain.AdvanceNext, conn.UserID)
+}
+
+func (r *InboundRouter) handleSetSkipOpEd(ctx context.Context, conn ConnectionCtx, data json.RawMessage) {
+	if _, ok := r.requireHost(ctx, conn, domain.MsgHostSetSkipOpEd); !ok {
+		return
+	}
+	var payload domain.HostSetSkipOpEdData
+	if err := json.Unmarshal(data, &payload); err != nil {
+		r.sendBadPayload(ctx, conn, domain.MsgHostSetSkipOpEd, err)
+		return
+	}
+	if err := r.repo.UpdateRoomState(ctx, conn.RoomID, map[string]interface{}{"skip_op_ed": payload.Enabled}); err != nil {
+		r.log.Errorw("watch_together update room (skip_op_ed)",
+			"room_id", conn.RoomID,
+			"err", err,
+		)
+		return
+	}
+	r.roomCache.Invalidate(conn.RoomID)
+
+	r.broadcastAll(ctx, conn.RoomID, domain.MsgRoomStateChanged, domain.RoomStateChangedData{
+		Field:    "skip_op_ed",
+		Value:    payload.Enabled,
+		ByUserID: conn.UserID,
+	}, "")
+}
+
+// ----------------------------------------------------------------------------
+// Server-initiated transitions (called from handleTimeTick)
+// ----------------------------------------------------------------------------
+
+// autoTransition checks the room's canonical position against the episode
+// end and, with skip_op_ed, the catalog's OP/ED windows. Returns true when
+// it advanced or seeked the room, in which case the tick is stale and the
+// caller skips drift detection.
+//
+// OP/ED windows come from the catalog, so any member's tick may trigger
+// those. The episode end, however, is the duration the client reports; it is
+// trusted only from a sender who passes the ControlMode gate, otherwise a
+// viewer in a host_only room could skip the room ahead with duration=1.
+func (r *InboundRouter) autoTransition(ctx context.Context, conn ConnectionCtx, tick domain.PlaybackTimeTickData) bool {
+	roomID := conn.RoomID
+	room, err := r.roomCache.GetRoom(ctx, roomID)
+	if err != nil || room.PlaybackState != domain.StatePlaying {
+		return false
+	}
+	nowMs := r.now().UnixMilli()
+	pos := room.PlaybackTime + float64(nowMs-room.PlaybackTimeUpdatedAtMs)/1000
+
+	if room.SkipOpEd && r.skipTimes != nil {
+		if times, ok := r.lookupSkipTimes(ctx, room); ok {
+			switch {
+			case times.HasOP() && pos >= times.OPStart && pos < times.OPEnd-skipWindowSlack:
+				return r.autoSeek(ctx, room, times.OPEnd, domain.SkipReasonOP)
+			case times.HasED() && pos >= times.EDStart && pos < times.EDEnd-skipWindowSlack:
+				if r.queueLength(ctx, roomID) > 0 {
+					return r.advanceQueue(ctx, roomID, domain.AdvanceEnded, "")
+				}
+				return r.autoSeek(ctx, room, times.EDEnd, domain.SkipReasonED)
+			}
+		}
+	}
+
+	if tick.Duration > 0 && pos >= tick.Duration-autoAdvanceEndSlack &&
+		r.canControl(ctx, room, conn.UserID) && r.queueLength(ctx, roomID) > 0 {
+		return r.advanceQueue(ctx, roomID, domain.AdvanceEnded, "")
+	}
+	return false
+}
+
+// lookupSkipTimes resolves the current episode's OP/ED windows. Episode IDs
+// that are not plain episode numbers have no skip times.
+func (r *InboundRouter) lookupSkipTimes(ctx context.Context, room *domain.Room) (SkipTimes, bool) {
+	episode, err := strconv.Atoi(room.EpisodeID)
+	if err != nil || episode < 1 {
+		return SkipTimes{}, false
+	}
+	times, err := r.skipTimes.SkipTimes(ctx, room.AnimeID, episode)
+	if err != nil {
+		r.log.Debugw("watch_together skip times unavailable",
+			"room_id", room.ID,
+			"anime_id", room.AnimeID,
+			"episode", episode,
+			"err", err,
+		)
+		return SkipTimes{}, false
+	}
+	return times, true
+}
+
+// queueLength counts queued items; errors count as an empty queue.
+func (r *InboundRouter) queueLength(ctx context.Context, roomID string) int {
+	items, err := r.repo.GetQueue(ctx, roomID)
+	if err != nil {
+		return 0
+	}
+	return len(items)
+}
+
+// autoSeek moves the whole room to target and broadcasts an unattributed
+// playback:event seek carrying the skip reason.
+func (r *InboundRouter) autoSeek(ctx context.Context, room *domain.Room, target float64, reason string) bool {
+	locked, err := r.repo.TryAutoLock(ctx, room.ID, autoTransitionLockTTL)
+	if err != nil || !locked {
+		return false
+	}
+	nowMs := r.now().UnixMilli()
+	if err := r.repo.UpdateRoomState(ctx, room.ID, map[string]interface{}{
+		"playback_time":            target,
+		"playback_time_updated_at": nowMs,
+	}); err != nil {
+		r.log.Errorw("watch_together update room (auto seek)",
+			"room_id", room.ID,
+			"reason", reason,
+			"err", err,
+		)
+		return false
+	}
+	r.roomCache.Invalidate(room.ID)
+
+	r.broadcastAll(ctx, room.ID, domain.MsgPlaybackEvent, domain.PlaybackEventData{
+		Kind:     "seek",
+		Time:     target,
+		ServerTS: nowMs,
+		Reason:   reason,
+	}, "")
+	return true
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+// ----------------------------------------------------------------------------
+// Protocol 1.1 — episode queue, vote-skip and server-initiated transitions.
+// Same fixture as inbound_test.go.
+// ----------------------------------------------------------------------------
+
+// skipCatalog adds a fixed SkipTimesSource to the validation fake.
+type skipCatalog struct {
+	*fakeCatalog
+	times SkipTimes
+}
+
+func (c skipCatalog) SkipTimes(context.Context, string, int) (SkipTimes, error) {
+	return c.times, nil
+}
+
+// sequentialIDs makes router.newID return item-1, item-2, ...
+func (fx *routerFixture) sequentialIDs() {
+	n := 0
+	fx.router.SetIDProviderForTest(func() string {
+		n++
+		return fmt.Sprintf("item-%d", n)
+	})
+}
+
+// withSkipTimes rebuilds the fixture's router over a catalog that serves times.
+func (fx *routerFixture) withSkipTimes(times SkipTimes) {
+	fx.router = NewInboundRouter(fx.repo, fx.hub, fx.drift, fx.rl, skipCatalog{fakeCatalog: fx.catalog, times: times}, nil)
+	fx.router.SetClockForTest(func() time.Time { return fx.now })
+	fx.sequentialIDs()
+}
+
+func bobConn(roomID string) ConnectionCtx {
+	return ConnectionCtx{RoomID: roomID, UserID: "bob", Username: "Bob"}
+}
+
+func (fx *routerFixture) advancedEvent(t *testing.T) domain.RoomEpisodeAdvancedData {
+	t.Helper()
+	call, ok := fx.hub.findFirst("Broadcast", domain.MsgRoomEpisodeAdvanced)
+	if !ok {
+		t.Fatalf("no room:episode_advanced broadcast; calls=%v", fx.hub.snapshot())
+	}
+	var got domain.RoomEpisodeAdvancedData
+	if err := json.Unmarshal(call.env.Data, &got); err != nil {
+		t.Fatalf("decode episode_advanced: %v", err)
+	}
+	return got
+}
+
+func TestQueue_Add_InheritsRoomAndBroadcasts(t *testing.T) {
+	fx := newRouterFixture(t)
+	fx.sequentialIDs()
+	roomID := "room-q1"
+	fx.seedRoom(t, fx.defaultRoom(roomID))
+
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgQueueAdd, domain.QueueAddData{EpisodeID: "2"})
+
+	call, ok := fx.hub.findFirst("Broadcast", domain.MsgQueueUpdated)
+	if !ok {
+		t.Fatalf("no queue:updated broadcast; calls=%v", fx.hub.snapshot())
+	}
+	var got domain.QueueUpdatedData
+	_ = json.Unmarshal(call.env.Data, &got)
+	if len(got.Items) != 1 {
+		t.Fatalf("items = %+v, want one", got.Items)
+	}
+	item := got.Items[0]
+	if item.AnimeID != "anime-1" || item.Player != domain.PlayerAnimeLib || item.TranslationID != "trans-1" || item.AddedBy != "alice" {
+		t.Fatalf("item = %+v, want room defaults added by alice", item)
+	}
+	calls := fx.catalog.snapshot()
+	if len(calls) != 1 || calls[0].EpisodeID != "2" {
+		t.Fatalf("catalog calls = %+v, want one validation of episode 2", calls)
+	}
+}
+
+func TestQueue_Add_InvalidEpisodeRejected(t *testing.T) {
+	fx := newRouterFixture(t)
+	roomID := "room-q2"
+	fx.seedRoom(t, fx.defaultRoom(roomID))
+	fx.catalog.alwaysInvalid(domain.ErrCodeEpisodeUnavailable)
+
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgQueueAdd, domain.QueueAddData{EpisodeID: "99"})
+
+	if !hasCode(fx.hub.errorCodesTo("alice"), domain.ErrCodeEpisodeUnavailable) {
+		t.Fatalf("want EPISODE_UNAVAILABLE; calls=%v", fx.hub.snapshot())
+	}
+	if items, _ := fx.repo.GetQueue(context.Background(), roomID); len(items) != 0 {
+		t.Fatalf("invalid item was queued: %+v", items)
+	}
+}
+
+func TestQueue_Remove_OnlyAdderOrHost(t *testing.T) {
+	fx := newRouterFixture(t)
+	fx.sequentialIDs()
+	roomID := "room-q3"
+	fx.seedRoom(t, fx.defaultRoom(roomID))
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgQueueAdd, domain.QueueAddData{EpisodeID: "2"})
+
+	fx.dispatchJSON(t, bobConn(roomID), domain.MsgQueueRemove, domain.QueueItemRefData{ItemID: "item-1"})
+	if !hasCode(fx.hub.errorCodesTo("bob"), domain.ErrCodeNotHost) {
+		t.Fatalf("bob should be refused; calls=%v", fx.hub.snapshot())
+	}
+
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgQueueRemove, domain.QueueItemRefData{ItemID: "item-1"})
+	if items, _ := fx.repo.GetQueue(context.Background(), roomID); len(items) != 0 {
+		t.Fatalf("host remove left %+v", items)
+	}
+}
+
+func TestQueue_VoteSkip_MajorityAdvances(t *testing.T) {
+	fx := newRouterFixture(t)
+	fx.sequentialIDs()
+	roomID := "room-q4"
+	fx.seedRoom(t, fx.defaultRoom(roomID))
+	for _, u := range []string{"host", "alice", "bob"} {
+		fx.seedMember(t, roomID, u, domain.MemberMeta{Username: u})
+	}
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgQueueAdd, domain.QueueAddData{EpisodeID: "2"})
+
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgQueueVoteSkip, nil)
+	if _, ok := fx.hub.findFirst("Broadcast", domain.MsgRoomEpisodeAdvanced); ok {
+		t.Fatal("1 of 3 votes must not advance")
+	}
+	fx.dispatchJSON(t, bobConn(roomID), domain.MsgQueueVoteSkip, nil)
+
+	got := fx.advancedEvent(t)
+	if got.EpisodeID != "2" || got.Reason != domain.AdvanceVoteSkip {
+		t.Fatalf("advanced = %+v, want episode 2 by vote_skip", got)
+	}
+	room, _ := fx.repo.GetRoom(context.Background(), roomID)
+	if room.EpisodeID != "2" || room.PlaybackState != domain.StatePlaying || room.PlaybackTime != 0 {
+		t.Fatalf("room after advance = %+v", room)
+	}
+	if n, _ := fx.repo.SkipVoteCount(context.Background(), roomID); n != 0 {
+		t.Fatalf("skip votes after advance = %d, want 0", n)
+	}
+}
+
+func TestQueue_VoteSkip_EmptyQueue(t *testing.T) {
+	fx := newRouterFixture(t)
+	roomID := "room-q5"
+	fx.seedRoom(t, fx.defaultRoom(roomID))
+
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgQueueVoteSkip, nil)
+
+	if !hasCode(fx.hub.errorCodesTo("alice"), domain.ErrCodeQueueEmpty) {
+		t.Fatalf("want QUEUE_EMPTY; calls=%v", fx.hub.snapshot())
+	}
+}
+
+func TestQueue_Next_GatedByControlMode(t *testing.T) {
+	fx := newRouterFixture(t)
+	fx.sequentialIDs()
+	roomID := "room-q6"
+	room := fx.defaultRoom(roomID)
+	room.ControlMode = domain.ControlHostOnly
+	fx.seedRoom(t, room)
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgQueueAdd, domain.QueueAddData{EpisodeID: "2"})
+
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgQueueNext, nil)
+	if !hasCode(fx.hub.errorCodesTo("alice"), domain.ErrCodeControlNotAllowed) {
+		t.Fatalf("want CONTROL_NOT_ALLOWED; calls=%v", fx.hub.snapshot())
+	}
+
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgQueueNext, nil)
+	if got := fx.advancedEvent(t); got.Reason != domain.AdvanceNext || got.ByUserID != "host" {
+		t.Fatalf("advanced = %+v, want next by host", got)
+	}
+}
+
+func TestQueue_TimeTick_AutoAdvancesAtEnd(t *testing.T) {
+	fx := newRouterFixture(t)
+	fx.sequentialIDs()
+	roomID := "room-q7"
+	room := fx.defaultRoom(roomID)
+	room.PlaybackState = domain.StatePlaying
+	room.PlaybackTime = 1419
+	fx.seedRoom(t, room)
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgQueueAdd, domain.QueueAddData{EpisodeID: "2"})
+
+	// Mid-episode ticks never advance.
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgPlaybackTimeTick, domain.PlaybackTimeTickData{Time: 1419, Duration: 1500})
+	if _, ok := fx.hub.findFirst("Broadcast", domain.MsgRoomEpisodeAdvanced); ok {
+		t.Fatal("advanced before the end")
+	}
+
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgPlaybackTimeTick, domain.PlaybackTimeTickData{Time: 1419, Duration: 1420})
+	if got := fx.advancedEvent(t); got.Reason != domain.AdvanceEnded || got.ByUserID != "" {
+		t.Fatalf("advanced = %+v, want unattributed ended", got)
+	}
+}
+
+func TestQueue_TimeTick_HostOnly_IgnoresViewerDuration(t *testing.T) {
+	fx := newRouterFixture(t)
+	fx.sequentialIDs()
+	roomID := "room-q7b"
+	room := fx.defaultRoom(roomID)
+	room.ControlMode = domain.ControlHostOnly
+	room.PlaybackState = domain.StatePlaying
+	room.PlaybackTime = 100
+	fx.seedRoom(t, room)
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgQueueAdd, domain.QueueAddData{EpisodeID: "2"})
+
+	// A viewer claiming the episode is 1s long must not skip the room ahead.
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgPlaybackTimeTick, domain.PlaybackTimeTickData{Time: 100, Duration: 1})
+	if _, ok := fx.hub.findFirst("Broadcast", domain.MsgRoomEpisodeAdvanced); ok {
+		t.Fatal("viewer tick advanced a host_only room")
+	}
+	if got, _ := fx.repo.GetRoom(context.Background(), roomID); got.EpisodeID != room.EpisodeID {
+		t.Fatalf("EpisodeID = %q, want %q", got.EpisodeID, room.EpisodeID)
+	}
+
+	// The host's own tick at the end still advances.
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgPlaybackTimeTick, domain.PlaybackTimeTickData{Time: 100, Duration: 101})
+	if got := fx.advancedEvent(t); got.Reason != domain.AdvanceEnded {
+		t.Fatalf("advanced = %+v, want ended", got)
+	}
+}
+
+func TestQueue_TimeTick_SkipsOP(t *testing.T) {
+	fx := newRouterFixture(t)
+	fx.withSkipTimes(SkipTimes{OPStart: 80, OPEnd: 170, EDStart: 1300, EDEnd: 1390})
+	roomID := "room-q8"
+	room := fx.defaultRoom(roomID)
+	room.EpisodeID = "3"
+	room.PlaybackState = domain.StatePlaying
+	room.PlaybackTime = 90
+	room.SkipOpEd = true
+	fx.seedRoom(t, room)
+
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgPlaybackTimeTick, domain.PlaybackTimeTickData{Time: 90})
+
+	call, ok := fx.hub.findFirst("Broadcast", domain.MsgPlaybackEvent)
+	if !ok {
+		t.Fatalf("no skip seek broadcast; calls=%v", fx.hub.snapshot())
+	}
+	var ev domain.PlaybackEventData
+	_ = json.Unmarshal(call.env.Data, &ev)
+	if ev.Kind != "seek" || ev.Time != 170 || ev.Reason != domain.SkipReasonOP || ev.ByUserID != "" {
+		t.Fatalf("event = %+v, want unattributed seek to 170 (skip_op)", ev)
+	}
+	got, _ := fx.repo.GetRoom(context.Background(), roomID)
+	if got.PlaybackTime != 170 {
+		t.Fatalf("PlaybackTime = %v, want 170", got.PlaybackTime)
+	}
+
+	// A second tick inside the lock window does not seek again.
+	fx.dispatchJSON(t, bobConn(roomID), domain.MsgPlaybackTimeTick, domain.PlaybackTimeTickData{Time: 95})
+	seeks := 0
+	for _, c := range fx.hub.snapshot() {
+		if c.env.Type == domain.MsgPlaybackEvent {
+			seeks++
+		}
+	}
+	if seeks != 1 {
+		t.Fatalf("seek broadcasts = %d, want 1", seeks)
+	}
+}
+
+func TestQueue_SetSkipOpEd_HostOnly(t *testing.T) {
+	fx := newRouterFixture(t)
+	roomID := "room-q9"
+	fx.seedRoom(t, fx.defaultRoom(roomID))
+
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgHostSetSkipOpEd, domain.HostSetSkipOpEdData{Enabled: true})
+	if !hasCode(fx.hub.errorCodesTo("alice"), domain.ErrCodeNotHost) {
+		t.Fatalf("want NOT_HOST; calls=%v", fx.hub.snapshot())
+	}
+
+	fx.dispatchJSON(t, hostConn(roomID), domain.MsgHostSetSkipOpEd, domain.HostSetSkipOpEdData{Enabled: true})
+	got, _ := fx.repo.GetRoom(context.Background(), roomID)
+	if !got.SkipOpEd {
+		t.Fatal("skip_op_ed not persisted")
+	}
+}
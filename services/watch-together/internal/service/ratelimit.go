@@ -5,6 +5,7 @@
 //
 //	playback:seek  → 1/sec/user, burst 1   (rate.Every(time.Second), 1)
 //	chat:message   → 5/sec/user, burst 5   (rate.Limit(5), 5)
+//	voice:signal / voice:speaking → 20/sec/user, burst 40
 //
 // In-process only (single-instance v1.0). Multi-instance scale-out is deferred
 // to v2; when that lands, the obvious upgrade is to lift this onto Redis
@@ -52,27 +53,37 @@ const chatRate = rate.Limit(5)
 // sustained traffic.
 const chatBurst = 5
 
+// signalRate / signalBurst bound voice signaling. A mesh join sends one offer
+// per peer followed by a trickle of ICE candidates, so the burst is sized for
+// a full channel (VoiceMaxParticipants peers × a handful of candidates).
+const (
+	signalRate  = rate.Limit(20)
+	signalBurst = 40
+)
+
 // RateLimiter holds a per-user token bucket for each rate-limited inbound
-// message type. The two maps are separate so a chatty user can't exhaust
+// message type. The maps are separate so a chatty user can't exhaust
 // their seek budget by sending chat messages, and vice versa.
 //
-// Concurrency: mu protects both maps. AllowSeek / AllowChat / Forget are
-// all O(1) with one bucket lookup or insert. The rate.Limiter values
+// Concurrency: mu protects the maps. AllowSeek / AllowChat / AllowSignal /
+// Forget are all O(1) with one bucket lookup or insert. The rate.Limiter values
 // themselves are individually safe for concurrent use, so once we've
 // fetched the pointer under the lock we can release it before calling
 // Allow().
 type RateLimiter struct {
-	mu   sync.Mutex
-	seek map[string]*rate.Limiter
-	chat map[string]*rate.Limiter
+	mu     sync.Mutex
+	seek   map[string]*rate.Limiter
+	chat   map[string]*rate.Limiter
+	signal map[string]*rate.Limiter
 }
 
 // NewRateLimiter constructs an empty limiter. The maps are lazily populated
 // on first AllowSeek / AllowChat per user.
 func NewRateLimiter() *RateLimiter {
 	return &RateLimiter{
-		seek: make(map[string]*rate.Limiter),
-		chat: make(map[string]*rate.Limiter),
+		seek:   make(map[string]*rate.Limiter),
+		chat:   make(map[string]*rate.Limiter),
+		signal: make(map[string]*rate.Limiter),
 	}
 }
 
@@ -118,7 +129,23 @@ func (r *RateLimiter) AllowChat(userID string) bool {
 	return limiter.Allow()
 }
 
-// Forget drops both buckets for userID. Called from the WS OnClose hook
+// AllowSignal returns true if userID has tokens available for a voice
+// signaling or speaking-indicator message.
+func (r *RateLimiter) AllowSignal(userID string) bool {
+	if userID == "" {
+		return true
+	}
+	r.mu.Lock()
+	limiter, ok := r.signal[userID]
+	if !ok {
+		limiter = rate.NewLimiter(signalRate, signalBurst)
+		r.signal[userID] = limiter
+	}
+	r.mu.Unlock()
+	return limiter.Allow()
+}
+
+// Forget drops every bucket for userID. Called from the WS OnClose hook
 // in 01.6.3 wiring so a disconnected user's state doesn't linger forever.
 // No-op if userID has no buckets yet.
 //
@@ -133,5 +160,6 @@ func (r *RateLimiter) Forget(userID string) {
 	r.mu.Lock()
 	delete(r.seek, userID)
 	delete(r.chat, userID)
+	delete(r.signal, userID)
 	r.mu.Unlock()
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
 	"github.com/ILITA-hub/animeenigma/libs/logger"
 	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
 	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/repo"
@@ -62,6 +63,11 @@ type CreateRoomInput struct {
 	EpisodeID     string
 	Player        string
 	TranslationID string
+	ControlMode   string // optional; empty → domain.ControlEveryone
+	PartyID       string // set only when the scheduler materializes a watch party
+	// HistoryID re-opens a chat history (ChatThread) the host owns; empty
+	// starts a new one (or continues the party's).
+	HistoryID string
 }
 
 // validate returns ErrInvalidInput-wrapped detail on missing/unknown fields.
@@ -88,6 +94,9 @@ func (in CreateRoomInput) validate() error {
 	if in.TranslationID == "" && in.Player != domain.PlayerAePlayer {
 		return fmt.Errorf("%w: translation_id is required", ErrInvalidInput)
 	}
+	if in.ControlMode != "" && !domain.ValidControlMode(in.ControlMode) {
+		return fmt.Errorf("%w: unknown control_mode %q (allowed: everyone|host_only|host_plus_delegates)", ErrInvalidInput, in.ControlMode)
+	}
 	return nil
 }
 
@@ -103,6 +112,14 @@ type RoomService struct {
 	repo *repo.RoomRepo
 	log  *logger.Logger
 
+	// archiver, when set, archives a party room before DeleteRoom.
+	archiver RoomArchiver
+	// turn mints voice-chat ICE servers (turn.go); nil hands out none.
+	turn *TURNIssuer
+	// chatLog holds the durable chat history (chat_log.go); nil keeps chat
+	// Redis-only and rejects HistoryID.
+	chatLog *ChatLog
+
 	// newID returns the UUID assigned to a newly-created room. Defaults to
 	// uuid.NewString. Tests override to assert exact room_id values.
 	newID func() string
@@ -126,8 +143,21 @@ func NewRoomService(r *repo.RoomRepo, log *logger.Logger) *RoomService {
 	}
 }
 
+// SetArchiver installs the hook Delete runs before tearing a room down.
+// Wired in main.go to the PartyService; nil (the default) disables it.
+func (s *RoomService) SetArchiver(a RoomArchiver) { s.archiver = a }
+
+// SetTURN installs the ICE server issuer behind ICEServers. Wired in main.go
+// from config.VoiceConfig.
+func (s *RoomService) SetTURN(t *TURNIssuer) { s.turn = t }
+
+// SetChatLog installs the durable chat history: Create opens the room's
+// thread and Get adds earlier rooms' messages to the snapshot.
+func (s *RoomService) SetChatLog(c *ChatLog) { s.chatLog = c }
+
 // Create allocates a fresh room HASH in Redis with `hostUserID` recorded as
-// the cosmetic host (WT-FOUND-03 — only the host can call Delete). Returns
+// the host (WT-FOUND-03 — only the host can call Delete; protocol 1.1 adds
+// the host:* moderation actions and ControlMode). Returns
 // the persisted Room on success; the handler builds the API response from
 // these fields (room_id = Room.ID, invite_url / ws_url constructed against
 // cfg.PublicBaseURL).
@@ -159,11 +189,32 @@ func (s *RoomService) Create(ctx context.Context, hostUserID, hostUsername strin
 		PlaybackTime:            0,
 		PlaybackTimeUpdatedAtMs: now.UnixMilli(),
 		HostUserID:              hostUserID,
+		ControlMode:             domain.EffectiveControlMode(in.ControlMode),
+		PartyID:                 in.PartyID,
+	}
+	room.ChatThreadID = room.ID
+	switch {
+	case in.HistoryID != "":
+		if s.chatLog == nil {
+			return nil, fmt.Errorf("%w: chat history is not available", ErrInvalidInput)
+		}
+		if _, err := s.chatLog.ownedThread(ctx, hostUserID, in.HistoryID); err != nil {
+			if appErr, ok := apperrors.IsAppError(err); ok && appErr.Code == apperrors.CodeNotFound {
+				return nil, fmt.Errorf("%w: unknown history_id", ErrInvalidInput)
+			}
+			return nil, err
+		}
+		room.ChatThreadID = in.HistoryID
+	case in.PartyID != "":
+		room.ChatThreadID = in.PartyID
 	}
 
 	if err := s.repo.CreateRoom(ctx, room); err != nil {
 		return nil, err
 	}
+	if s.chatLog != nil {
+		s.chatLog.openThread(ctx, room)
+	}
 
 	// Metric bump only after successful persistence — validation failures
 	// (caught above) do not contribute to the counter.
@@ -217,11 +268,41 @@ func (s *RoomService) Get(ctx context.Context, roomID string) (*domain.RoomSnaps
 		messages = []domain.ChatMessage{}
 	}
 
+	mod, err := s.repo.GetModeration(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	queue, err := s.repo.GetQueue(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	skipVotes, err := s.repo.SkipVoteCount(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	voice, err := s.repo.GetVoice(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	room.ControlMode = domain.EffectiveControlMode(room.ControlMode)
+	var history []domain.ChatLogEntry
+	if s.chatLog != nil {
+		history = s.chatLog.prior(ctx, room)
+	}
+
 	return &domain.RoomSnapshot{
-		Room:            *room,
-		Members:         members,
-		Messages:        messages,
-		ProtocolVersion: domain.ProtocolVersion,
+		Room:               *room,
+		Members:            members,
+		Messages:           messages,
+		Delegates:          mod.Delegates,
+		Muted:              mod.Muted,
+		Banned:             mod.Banned,
+		Queue:              queue,
+		SkipVotes:          skipVotes,
+		Voice:              voice,
+		History:            history,
+		ProtocolVersion:    domain.ProtocolVersion,
+		MinProtocolVersion: domain.MinProtocolVersion,
 	}, nil
 }
 
@@ -261,6 +342,7 @@ func (s *RoomService) Delete(ctx context.Context, requesterUserID, roomID string
 	// active gauge BEFORE the destructive DeleteRoom call. Best-effort:
 	// observation failures must not block the delete.
 	observeRoomTeardown(ctx, s.repo, s.log, room)
+	archiveRoom(ctx, s.archiver, room)
 
 	if err := s.repo.DeleteRoom(ctx, roomID); err != nil {
 		return err
@@ -273,6 +355,23 @@ func (s *RoomService) Delete(ctx context.Context, requesterUserID, roomID string
 	return nil
 }
 
+// RoomArchiver persists whatever must outlive a room before its Redis keys
+// are deleted — today the attendance and chat of watch-party rooms.
+// Implemented by *PartyService.
+type RoomArchiver interface {
+	ArchiveRoom(ctx context.Context, room *domain.Room)
+}
+
+// archiveRoom runs the archiver for party rooms. Best-effort like the
+// telemetry beside it: the archiver logs its own failures and never blocks
+// the teardown.
+func archiveRoom(ctx context.Context, a RoomArchiver, room *domain.Room) {
+	if a == nil || room == nil || room.PartyID == "" {
+		return
+	}
+	a.ArchiveRoom(ctx, room)
+}
+
 // observeRoomTeardown is the Plan 05.2 telemetry helper shared between
 // RoomService.Delete and GraceManager.fire (05.1's territory — 05.1 may
 // wire this call into grace.go after this plan lands). It:
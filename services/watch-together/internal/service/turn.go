@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"time"
+
+	apperrors "github.com/ILITA-hub/animeenigma/libs/errors"
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+// TURNOptions configures a TURNIssuer; see config.VoiceConfig.
+type TURNOptions struct {
+	STUNURLs []string
+	TURNURLs []string
+	Secret   string
+	TTL      time.Duration
+}
+
+// TURNIssuer mints ICE server lists for voice chat. TURN credentials follow
+// the coturn REST scheme (use-auth-secret): username is
+// "{expiry unix}:{user id}" and the password is
+// base64(HMAC-SHA1(secret, username)), so the TURN server verifies them
+// with the shared secret alone and they lapse on their own.
+type TURNIssuer struct {
+	opts TURNOptions
+	now  func() time.Time
+}
+
+// NewTURNIssuer returns an issuer. Without a secret (or TURN URLs) only the
+// STUN servers are handed out.
+func NewTURNIssuer(opts TURNOptions) *TURNIssuer {
+	if opts.TTL <= 0 {
+		opts.TTL = 6 * time.Hour
+	}
+	return &TURNIssuer{opts: opts, now: time.Now}
+}
+
+// Issue returns the ICE configuration for userID.
+func (t *TURNIssuer) Issue(userID string) domain.ICEConfig {
+	cfg := domain.ICEConfig{ICEServers: []domain.ICEServer{}}
+	if len(t.opts.STUNURLs) > 0 {
+		cfg.ICEServers = append(cfg.ICEServers, domain.ICEServer{URLs: t.opts.STUNURLs})
+	}
+	if t.opts.Secret == "" || len(t.opts.TURNURLs) == 0 {
+		return cfg
+	}
+	expires := t.now().Add(t.opts.TTL).Unix()
+	username := strconv.FormatInt(expires, 10) + ":" + userID
+	mac := hmac.New(sha1.New, []byte(t.opts.Secret))
+	mac.Write([]byte(username))
+	cfg.ICEServers = append(cfg.ICEServers, domain.ICEServer{
+		URLs:       t.opts.TURNURLs,
+		Username:   username,
+		Credential: base64.StdEncoding.EncodeToString(mac.Sum(nil)),
+	})
+	cfg.ExpiresAt = expires
+	return cfg
+}
+
+// ICEServers returns the ICE configuration for a member of a live room.
+// ErrNotFound when the room is gone, Forbidden when userID is not connected
+// to it — TURN relays bandwidth, so credentials go to members only.
+func (s *RoomService) ICEServers(ctx context.Context, roomID, userID string) (*domain.ICEConfig, error) {
+	if roomID == "" || userID == "" {
+		return nil, fmt.Errorf("%w: room_id and user_id are required", ErrInvalidInput)
+	}
+	if _, err := s.repo.GetRoom(ctx, roomID); err != nil {
+		return nil, err
+	}
+	member, err := s.repo.IsRoomMember(ctx, roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, apperrors.Forbidden("join the room before requesting ICE servers")
+	}
+	issuer := s.turn
+	if issuer == nil {
+		issuer = NewTURNIssuer(TURNOptions{})
+	}
+	cfg := issuer.Issue(userID)
+	return &cfg, nil
+}
@@ -0,0 +1,230 @@
+// Package service — voice.go holds the protocol-1.2 voice channel: the
+// voice:* handlers that track who is in the room's voice channel and relay
+// WebRTC signaling between them.
+//
+// Voice model:
+//   - Media never touches the server. Participants form a full mesh of peer
+//     connections; the hub only relays offer / answer / ICE candidates, and
+//     each signal goes to exactly one addressee (hub.SendTo).
+//   - Membership and mute state live in Redis (repo/voice.go) so a second
+//     instance and a late joiner's room snapshot see them. Every change
+//     broadcasts voice:state to the whole room, so non-participants can show
+//     who is talking too.
+//   - voice:speaking is a transient indicator the client derives from its own
+//     audio level. It is fanned out but never stored, and dropped for a muted
+//     or non-participating sender.
+//   - A participant whose last tab closes leaves the channel (OnDisconnect).
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+// voiceParticipant looks up userID in the voice channel. Returns nil (and
+// logs) on a repo failure so callers treat it as "not in voice".
+func (r *InboundRouter) voiceParticipant(ctx context.Context, roomID, userID string) *domain.VoiceParticipant {
+	p, err := r.repo.GetVoiceParticipant(ctx, roomID, userID)
+	if err != nil {
+		r.log.Warnw("watch_together voice lookup failed",
+			"room_id", roomID,
+			"user_id", userID,
+			"err", err,
+		)
+		return nil
+	}
+	return p
+}
+
+// broadcastVoiceState tells every member (sender included) where userID now
+// stands in the voice channel.
+func (r *InboundRouter) broadcastVoiceState(ctx context.Context, roomID, userID string, inVoice, muted bool) {
+	r.broadcastAll(ctx, roomID, domain.MsgVoiceState, domain.VoiceStateData{
+		UserID:  userID,
+		InVoice: inVoice,
+		Muted:   muted,
+	}, "")
+}
+
+// ----------------------------------------------------------------------------
+// Handlers — voice:join / voice:leave / voice:mute
+// ----------------------------------------------------------------------------
+
+func (r *InboundRouter) handleVoiceJoin(ctx context.Context, conn ConnectionCtx, data json.RawMessage) {
+	var payload domain.VoiceJoinData
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &payload); err != nil {
+			r.sendBadPayload(ctx, conn, domain.MsgVoiceJoin, err)
+			return
+		}
+	}
+	joinedAt := r.now().UnixMilli()
+	// Re-joining from a second tab keeps the original position in the
+	// channel's join order.
+	if existing := r.voiceParticipant(ctx, conn.RoomID, conn.UserID); existing != nil {
+		joinedAt = existing.JoinedAt
+	}
+	ok, err := r.repo.JoinVoice(ctx, conn.RoomID, domain.VoiceParticipant{
+		UserID:   conn.UserID,
+		Muted:    payload.Muted,
+		JoinedAt: joinedAt,
+	})
+	if err != nil {
+		r.log.Errorw("watch_together voice join",
+			"room_id", conn.RoomID,
+			"user_id", conn.UserID,
+			"err", err,
+		)
+		return
+	}
+	if !ok {
+		r.sendErrorToSelf(ctx, conn, domain.ErrCodeVoiceFull,
+			fmt.Sprintf("voice channel is full (%d participants)", domain.VoiceMaxParticipants), "")
+		return
+	}
+	r.broadcastVoiceState(ctx, conn.RoomID, conn.UserID, true, payload.Muted)
+}
+
+func (r *InboundRouter) handleVoiceLeave(ctx context.Context, conn ConnectionCtx) {
+	left, err := r.repo.LeaveVoice(ctx, conn.RoomID, conn.UserID)
+	if err != nil {
+		r.log.Errorw("watch_together voice leave",
+			"room_id", conn.RoomID,
+			"user_id", conn.UserID,
+			"err", err,
+		)
+		return
+	}
+	if left {
+		r.broadcastVoiceState(ctx, conn.RoomID, conn.UserID, false, false)
+	}
+}
+
+func (r *InboundRouter) handleVoiceMute(ctx context.Context, conn ConnectionCtx, data json.RawMessage) {
+	var payload domain.VoiceMuteData
+	if err := json.Unmarshal(data, &payload); err != nil {
+		r.sendBadPayload(ctx, conn, domain.MsgVoiceMute, err)
+		return
+	}
+	p := r.voiceParticipant(ctx, conn.RoomID, conn.UserID)
+	if p == nil {
+		r.sendErrorToSelf(ctx, conn, domain.ErrCodeNotInVoice, "join voice first", "")
+		return
+	}
+	p.Muted = payload.Muted
+	if _, err := r.repo.JoinVoice(ctx, conn.RoomID, *p); err != nil {
+		r.log.Errorw("watch_together voice mute",
+			"room_id", conn.RoomID,
+			"user_id", conn.UserID,
+			"err", err,
+		)
+		return
+	}
+	r.broadcastVoiceState(ctx, conn.RoomID, conn.UserID, true, p.Muted)
+}
+
+// ----------------------------------------------------------------------------
+// Handlers — voice:speaking / voice:signal
+//
+// Both are high-frequency (a speaking toggle per utterance, a burst of ICE
+// candidates per peer on join) and share the per-user signal bucket.
+// ----------------------------------------------------------------------------
+
+func (r *InboundRouter) handleVoiceSpeaking(ctx context.Context, conn ConnectionCtx, data json.RawMessage) {
+	if !r.rl.AllowSignal(conn.UserID) {
+		// Indicator frames are cosmetic — drop silently rather than
+		// flooding the client with RATE_LIMITED errors.
+		RateLimitedTotal.WithLabelValues(domain.MsgVoiceSpeaking).Inc()
+		return
+	}
+	var payload domain.VoiceSpeakingData
+	if err := json.Unmarshal(data, &payload); err != nil {
+		r.sendBadPayload(ctx, conn, domain.MsgVoiceSpeaking, err)
+		return
+	}
+	p := r.voiceParticipant(ctx, conn.RoomID, conn.UserID)
+	if p == nil || (p.Muted && payload.Speaking) {
+		return
+	}
+	r.broadcastAll(ctx, conn.RoomID, domain.MsgVoiceSpeakingOut, domain.VoiceSpeakingOutData{
+		UserID:   conn.UserID,
+		Speaking: payload.Speaking,
+	}, conn.UserID)
+}
+
+func (r *InboundRouter) handleVoiceSignal(ctx context.Context, conn ConnectionCtx, data json.RawMessage) {
+	if !r.rl.AllowSignal(conn.UserID) {
+		RateLimitedTotal.WithLabelValues(domain.MsgVoiceSignal).Inc()
+		r.sendErrorToSelf(ctx, conn, domain.ErrCodeRateLimited,
+			"voice signaling rate limit exceeded (20/sec)", "")
+		return
+	}
+	var payload domain.VoiceSignalInData
+	if err := json.Unmarshal(data, &payload); err != nil {
+		r.sendBadPayload(ctx, conn, domain.MsgVoiceSignal, err)
+		return
+	}
+	switch {
+	case !domain.ValidSignalKind(payload.Kind):
+		r.sendBadPayload(ctx, conn, domain.MsgVoiceSignal, fmt.Errorf("unknown signal kind %q", payload.Kind))
+		return
+	case payload.ToUserID == "" || payload.ToUserID == conn.UserID:
+		r.sendBadPayload(ctx, conn, domain.MsgVoiceSignal, fmt.Errorf("to_user_id must name another participant"))
+		return
+	case len(payload.Payload) == 0 || len(payload.Payload) > domain.VoiceSignalMaxBytes:
+		r.sendBadPayload(ctx, conn, domain.MsgVoiceSignal,
+			fmt.Errorf("payload must be 1..%d bytes", domain.VoiceSignalMaxBytes))
+		return
+	}
+	if r.voiceParticipant(ctx, conn.RoomID, conn.UserID) == nil {
+		r.sendErrorToSelf(ctx, conn, domain.ErrCodeNotInVoice, "join voice first", "")
+		return
+	}
+	if r.voiceParticipant(ctx, conn.RoomID, payload.ToUserID) == nil {
+		r.sendErrorToSelf(ctx, conn, domain.ErrCodeNotInVoice,
+			fmt.Sprintf("user %s is not in voice", payload.ToUserID), "")
+		return
+	}
+
+	out, err := buildEnvelope(domain.MsgVoiceSignalOut, domain.VoiceSignalOutData{
+		FromUserID: conn.UserID,
+		Kind:       payload.Kind,
+		Payload:    payload.Payload,
+	})
+	if err != nil {
+		r.log.Errorw("watch_together marshal voice signal", "err", err)
+		return
+	}
+	if _, err := r.hub.SendTo(ctx, conn.RoomID, payload.ToUserID, out); err != nil {
+		r.log.Warnw("watch_together relay voice signal",
+			"room_id", conn.RoomID,
+			"from_user_id", conn.UserID,
+			"to_user_id", payload.ToUserID,
+			"err", err,
+		)
+	}
+}
+
+// leaveVoiceOnDisconnect drops a member whose last tab closed from the voice
+// channel and tells the rest of the room, so peers tear down their
+// connection to them instead of waiting for ICE to time out.
+func (r *InboundRouter) leaveVoiceOnDisconnect(roomID, userID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	left, err := r.repo.LeaveVoice(ctx, roomID, userID)
+	if err != nil {
+		r.log.Warnw("watch_together voice leave on disconnect",
+			"room_id", roomID,
+			"user_id", userID,
+			"err", err,
+		)
+		return
+	}
+	if left {
+		r.broadcastVoiceState(ctx, roomID, userID, false, false)
+	}
+}
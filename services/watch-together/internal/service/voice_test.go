@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ILITA-hub/animeenigma/services/watch-together/internal/domain"
+)
+
+// ----------------------------------------------------------------------------
+// Protocol 1.2 — voice channel state and WebRTC signal relay. Same fixture
+// as inbound_test.go.
+// ----------------------------------------------------------------------------
+
+func TestVoice_JoinBroadcastsStateAndCapsChannel(t *testing.T) {
+	fx := newRouterFixture(t)
+	roomID := "room-v1"
+	fx.seedRoom(t, fx.defaultRoom(roomID))
+
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgVoiceJoin, domain.VoiceJoinData{Muted: true})
+	call, ok := fx.hub.findFirst("Broadcast", domain.MsgVoiceState)
+	if !ok {
+		t.Fatalf("voice:join not broadcast; calls=%v", fx.hub.snapshot())
+	}
+	var state domain.VoiceStateData
+	_ = json.Unmarshal(call.env.Data, &state)
+	if state != (domain.VoiceStateData{UserID: "alice", InVoice: true, Muted: true}) || call.excludeUserID != "" {
+		t.Fatalf("voice:state = %+v (exclude %q)", state, call.excludeUserID)
+	}
+
+	for i := 1; i < domain.VoiceMaxParticipants; i++ {
+		conn := ConnectionCtx{RoomID: roomID, UserID: fmt.Sprintf("user-%d", i)}
+		fx.dispatchJSON(t, conn, domain.MsgVoiceJoin, domain.VoiceJoinData{})
+	}
+	fx.dispatchJSON(t, bobConn(roomID), domain.MsgVoiceJoin, domain.VoiceJoinData{})
+	if !hasCode(fx.hub.errorCodesTo("bob"), domain.ErrCodeVoiceFull) {
+		t.Fatalf("want VOICE_FULL to bob; calls=%v", fx.hub.snapshot())
+	}
+	voice, _ := fx.repo.GetVoice(context.Background(), roomID)
+	if len(voice) != domain.VoiceMaxParticipants || voice[0].UserID != "alice" {
+		t.Fatalf("voice channel = %+v", voice)
+	}
+}
+
+func TestVoice_SignalRelaysToAddresseeOnly(t *testing.T) {
+	fx := newRouterFixture(t)
+	roomID := "room-v2"
+	fx.seedRoom(t, fx.defaultRoom(roomID))
+	offer := json.RawMessage(`{"type":"offer","sdp":"v=0"}`)
+
+	// Neither side in voice yet.
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgVoiceSignal, domain.VoiceSignalInData{ToUserID: "bob", Kind: domain.SignalOffer, Payload: offer})
+	if !hasCode(fx.hub.errorCodesTo("alice"), domain.ErrCodeNotInVoice) {
+		t.Fatalf("want NOT_IN_VOICE to alice; calls=%v", fx.hub.snapshot())
+	}
+
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgVoiceJoin, domain.VoiceJoinData{})
+	fx.dispatchJSON(t, bobConn(roomID), domain.MsgVoiceJoin, domain.VoiceJoinData{})
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgVoiceSignal, domain.VoiceSignalInData{Kind: "renegotiate", ToUserID: "bob", Payload: offer})
+	if !hasCode(fx.hub.errorCodesTo("alice"), errCodeBadPayload) {
+		t.Fatalf("unknown signal kind should be BAD_PAYLOAD; calls=%v", fx.hub.snapshot())
+	}
+
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgVoiceSignal, domain.VoiceSignalInData{ToUserID: "bob", Kind: domain.SignalOffer, Payload: offer})
+	var relayed []fakeHubCall
+	for _, c := range fx.hub.snapshot() {
+		if c.env.Type == domain.MsgVoiceSignalOut {
+			relayed = append(relayed, c)
+		}
+	}
+	if len(relayed) != 1 || relayed[0].method != "SendTo" || relayed[0].userID != "bob" {
+		t.Fatalf("signal relay calls = %+v, want one SendTo bob", relayed)
+	}
+	var out domain.VoiceSignalOutData
+	_ = json.Unmarshal(relayed[0].env.Data, &out)
+	if out.FromUserID != "alice" || out.Kind != domain.SignalOffer || string(out.Payload) != string(offer) {
+		t.Fatalf("relayed signal = %+v", out)
+	}
+}
+
+func TestVoice_MutedSpeakerIsDroppedAndDisconnectLeaves(t *testing.T) {
+	fx := newRouterFixture(t)
+	roomID := "room-v3"
+	fx.seedRoom(t, fx.defaultRoom(roomID))
+
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgVoiceJoin, domain.VoiceJoinData{})
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgVoiceMute, domain.VoiceMuteData{Muted: true})
+	fx.dispatchJSON(t, aliceConn(roomID), domain.MsgVoiceSpeaking, domain.VoiceSpeakingData{Speaking: true})
+	if _, ok := fx.hub.findFirst("Broadcast", domain.MsgVoiceSpeakingOut); ok {
+		t.Fatal("a muted participant's speaking indicator must not be relayed")
+	}
+	p, _ := fx.repo.GetVoiceParticipant(context.Background(), roomID, "alice")
+	if p == nil || !p.Muted {
+		t.Fatalf("participant after mute = %+v", p)
+	}
+
+	fx.router.OnDisconnect(roomID, "alice")
+	if p, _ := fx.repo.GetVoiceParticipant(context.Background(), roomID, "alice"); p != nil {
+		t.Fatalf("participant survived disconnect: %+v", p)
+	}
+	var left bool
+	for _, c := range fx.hub.snapshot() {
+		var s domain.VoiceStateData
+		if c.env.Type == domain.MsgVoiceState && json.Unmarshal(c.env.Data, &s) == nil && s.UserID == "alice" && !s.InVoice {
+			left = true
+		}
+	}
+	if !left {
+		t.Fatalf("disconnect did not broadcast voice:state in_voice=false; calls=%v", fx.hub.snapshot())
+	}
+}
+
+func TestTURNIssuer_MintsCoturnCredentials(t *testing.T) {
+	issuer := NewTURNIssuer(TURNOptions{
+		STUNURLs: []string{"stun:stun.example.test:3478"},
+		TURNURLs: []string{"turn:turn.example.test:3478"},
+		Secret:   "s3cret",
+		TTL:      time.Hour,
+	})
+	issuer.now = func() time.Time { return time.Unix(1_700_000_000, 0) }
+
+	cfg := issuer.Issue("alice")
+	if len(cfg.ICEServers) != 2 || cfg.ExpiresAt != 1_700_003_600 {
+		t.Fatalf("ICE config = %+v", cfg)
+	}
+	turn := cfg.ICEServers[1]
+	if turn.Username != "1700003600:alice" {
+		t.Fatalf("username = %q", turn.Username)
+	}
+	mac := hmac.New(sha1.New, []byte("s3cret"))
+	mac.Write([]byte(turn.Username))
+	if want := base64.StdEncoding.EncodeToString(mac.Sum(nil)); turn.Credential != want {
+		t.Fatalf("credential = %q, want %q", turn.Credential, want)
+	}
+
+	stunOnly := NewTURNIssuer(TURNOptions{STUNURLs: []string{"stun:stun.example.test:3478"}}).Issue("alice")
+	if len(stunOnly.ICEServers) != 1 || stunOnly.ICEServers[0].Username != "" || stunOnly.ExpiresAt != 0 {
+		t.Fatalf("without a secret only STUN is handed out; got %+v", stunOnly)
+	}
+}
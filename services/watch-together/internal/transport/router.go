@@ -41,18 +41,34 @@ import (
 //	POST   /api/watch-together/rooms               — JWT-protected (01.4)
 //	GET    /api/watch-together/rooms/{id}          — JWT-protected (01.4)
 //	DELETE /api/watch-together/rooms/{id}          — JWT-protected (01.4)
+//	GET    /api/watch-together/rooms/{id}/ice-servers — JWT-protected, members only (voice)
+//	GET    /api/watch-together/parties             — JWT-protected, scheduled parties
+//	POST   /api/watch-together/parties             — JWT-protected
+//	GET    /api/watch-together/parties/{id}        — JWT-protected (?code= from invite link)
+//	DELETE /api/watch-together/parties/{id}        — JWT-protected, host cancels
+//	POST   /api/watch-together/parties/{id}/invites — JWT-protected, host only
+//	POST   /api/watch-together/parties/{id}/rsvp   — JWT-protected (?code=)
+//	GET    /api/watch-together/parties/{id}/archive — JWT-protected (?code=)
+//	GET    /api/watch-together/history             — JWT-protected, chat histories the caller owns
+//	GET    /api/watch-together/history/{id}        — JWT-protected, owner only (?before=&limit=)
+//	GET    /api/watch-together/history/{id}/export — JWT-protected, owner only (?format=json|txt)
+//	POST   /internal/account/export                — auth account fan-out (Docker-network only)
+//	POST   /internal/account/erase                 — auth account fan-out (Docker-network only)
 //
 // The /ws endpoint sits OUTSIDE the AuthMiddleware-wrapped subgroup because
 // browsers can't set Authorization: Bearer on a WS upgrade (see package doc).
 // The WS handler validates the JWT itself from the ?token= query param.
 //
-// roomHandler / wsHandler may each be nil; if so the corresponding routes
+// roomHandler / wsHandler / accountHandler / partyHandler / historyHandler may each be nil; if so the corresponding routes
 // are NOT mounted. Used by unit tests that exercise the middleware stack
 // without the full DI graph.
 func NewRouter(
 	cfg *config.Config,
 	roomHandler *handler.RoomHandler,
 	wsHandler *handler.WebSocketHandler,
+	accountHandler *handler.AccountInternalHandler,
+	partyHandler *handler.PartyHandler,
+	historyHandler *handler.ChatHistoryHandler,
 	log *logger.Logger,
 	metricsCollector *metrics.Collector,
 ) http.Handler {
@@ -82,6 +98,13 @@ func NewRouter(
 		metrics.Handler().ServeHTTP(w, req)
 	})
 
+	// Account export / erasure fan-out from the auth service. Never proxied
+	// by the gateway, so no JWT — the caller is another service.
+	if accountHandler != nil {
+		r.Post("/internal/account/export", accountHandler.Export)
+		r.Post("/internal/account/erase", accountHandler.Erase)
+	}
+
 	// /api/watch-together subtree with SPLIT auth handling:
 	//   - /ws    → no chi-level AuthMiddleware (handler does its own JWT check
 	//              from ?token= because browsers can't set Authorization on
@@ -102,6 +125,33 @@ func NewRouter(
 					r.Post("/", roomHandler.Create)
 					r.Get("/{id}", roomHandler.Get)
 					r.Delete("/{id}", roomHandler.Delete)
+					r.Get("/{id}/ice-servers", roomHandler.ICEServers)
+				})
+			})
+		}
+
+		if partyHandler != nil {
+			r.Group(func(r chi.Router) {
+				r.Use(AuthMiddleware(cfg.JWT))
+				r.Route("/parties", func(r chi.Router) {
+					r.Get("/", partyHandler.List)
+					r.Post("/", partyHandler.Create)
+					r.Get("/{id}", partyHandler.Get)
+					r.Delete("/{id}", partyHandler.Cancel)
+					r.Post("/{id}/invites", partyHandler.Invite)
+					r.Post("/{id}/rsvp", partyHandler.RSVP)
+					r.Get("/{id}/archive", partyHandler.Archive)
+				})
+			})
+		}
+
+		if historyHandler != nil {
+			r.Group(func(r chi.Router) {
+				r.Use(AuthMiddleware(cfg.JWT))
+				r.Route("/history", func(r chi.Router) {
+					r.Get("/", historyHandler.List)
+					r.Get("/{id}", historyHandler.Get)
+					r.Get("/{id}/export", historyHandler.Export)
 				})
 			})
 		}